@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+
+	"pdf-ocr-ai/pkg/config"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/ocr"
+)
+
+// GetAIProviders 获取可选的AI服务类型
+func (a *App) GetAIProviders() []ocr.ProviderInfo {
+	return ocr.Providers()
+}
+
+// applyAIProvider 按AI配置创建或更新服务提供方，服务类型变化时重新创建
+func (a *App) applyAIProvider(cfg config.AIConfig) {
+	providerID := cfg.Provider
+	if providerID == "" {
+		providerID = ocr.DefaultProviderID
+	}
+	if a.ocrClient != nil && a.ocrClient.ID() == providerID {
+		a.ocrClient.UpdateConfig(cfg)
+		return
+	}
+
+	provider, err := ocr.NewProvider(cfg)
+	if err != nil {
+		if !errors.Is(err, ocr.ErrAPIKeyRequired) {
+			logger.Errorf("创建AI服务失败: %v", err)
+		}
+		// 无法创建新的提供方时保留原来的
+		return
+	}
+	provider.SetRateLimitHandler(a.onRateLimited)
+
+	if a.ocrClient != nil {
+		a.ocrClient.Close()
+		logger.Infof("AI服务类型已切换为 %s", providerID)
+	}
+	a.ocrClient = provider
+}
+
+// maxContextNeighborPages 上下文模式下每侧最多包含的相邻页面数
+const maxContextNeighborPages = 3
+
+// contextReserveTokens 上下文模式下为说明文字和消息格式预留的长度
+const contextReserveTokens = 500
+
+// contextNeighborBudget 计算上下文模式下相邻页面可使用的长度（tokens）
+// 当前页内容既出现在上下文中又作为正文发送，因此计算两次
+func (a *App) contextNeighborBudget(currentPageText, prompt string) int {
+	if a.ocrClient == nil {
+		return 0
+	}
+	aiConfig := a.configManager.GetAIConfig()
+	model := aiConfig.TextModel
+	if model == "" {
+		model = aiConfig.Model
+	}
+
+	limits := a.ocrClient.ModelLimits(model)
+	budget := limits.ContextLength - limits.MaxOutput - ocr.EstimateTokens(prompt) -
+		2*ocr.EstimateTokens(currentPageText) - contextReserveTokens
+	if budget < 0 {
+		return 0
+	}
+	return budget
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"pdf-ocr-ai/pkg/apiserver"
+	"pdf-ocr-ai/pkg/config"
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/jobs"
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// apiBackend 将API请求转发给App（单独的类型，避免这些方法被绑定到前端）
+type apiBackend struct {
+	app *App
+}
+
+// OpenDocument 在工作区中打开文档（不切换界面上的活动文档）
+func (b *apiBackend) OpenDocument(filePath string) (string, error) {
+	session, err := b.app.loadDocumentSession(filePath)
+	if err != nil {
+		return "", err
+	}
+	session = b.app.registerSession(session, false)
+	b.app.emitWorkspaceChanged()
+	return session.ID, nil
+}
+
+// ListDocuments 获取工作区文档列表
+func (b *apiBackend) ListDocuments() interface{} {
+	return b.app.GetOpenDocuments()
+}
+
+// StartOCR 开始OCR批量处理，返回任务ID；文档正在处理时加入队列，返回队列任务ID
+func (b *apiBackend) StartOCR(documentID string, pages []int, force bool) (string, error) {
+	session, pages, err := b.prepareBatch(documentID, pages)
+	if err != nil {
+		return "", err
+	}
+	return b.startBatch(session, QueueRequest{Pages: pages, TaskType: string(jobs.TaskOCR), ForceReprocess: force})
+}
+
+// StartAI 开始AI批量处理，返回任务ID；文档正在处理时加入队列，返回队列任务ID
+func (b *apiBackend) StartAI(documentID string, pages []int, prompt string, contextMode bool, force bool) (string, error) {
+	session, pages, err := b.prepareBatch(documentID, pages)
+	if err != nil {
+		return "", err
+	}
+	return b.startBatch(session, aiBatchRequest(pages, prompt, force, contextMode))
+}
+
+// prepareBatch 校验文档，页面为空时返回全部页面
+func (b *apiBackend) prepareBatch(documentID string, pages []int) (*DocumentSession, []int, error) {
+	if b.app.ocrClient == nil {
+		return nil, nil, i18n.Errorf("ai.not_configured")
+	}
+
+	session, err := b.app.getSession(documentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(pages) == 0 {
+		pages = allPageNumbers(session.Doc.PageCount)
+	}
+	return session, pages, nil
+}
+
+// startBatch 开始批量处理或在文档正在处理时加入队列
+func (b *apiBackend) startBatch(session *DocumentSession, req QueueRequest) (string, error) {
+	id := b.app.startOrQueueBatch(session, req)
+	if id == "" {
+		return "", i18n.Errorf("doc.busy")
+	}
+	return id, nil
+}
+
+// GetStatus 获取文档处理状态
+func (b *apiBackend) GetStatus(documentID string) (map[string]interface{}, error) {
+	session, err := b.app.getSession(documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := session.processingSnapshot()
+	summary := session.summary(false)
+	status["page_count"] = summary.PageCount
+	status["processed_pages"] = summary.ProcessedPages
+	status["file_path"] = summary.FilePath
+	return status, nil
+}
+
+// GetDocument 获取文档及各页处理结果
+func (b *apiBackend) GetDocument(documentID string) (interface{}, error) {
+	session, err := b.app.getSession(documentID)
+	if err != nil {
+		return nil, err
+	}
+	return session.Doc, nil
+}
+
+// ExportDocument 按格式导出文档全部页面的文本
+func (b *apiBackend) ExportDocument(documentID string, format string) (string, error) {
+	session, err := b.app.getSession(documentID)
+	if err != nil {
+		return "", err
+	}
+	return formatDocumentText(session.Doc, allPageNumbers(session.Doc.PageCount), format, false, b.app.exportApprovedOnly()), nil
+}
+
+// CancelProcessing 取消文档的批量处理
+func (b *apiBackend) CancelProcessing(documentID string) error {
+	session, err := b.app.getSession(documentID)
+	if err != nil {
+		return err
+	}
+	b.app.cancelSession(session)
+	return nil
+}
+
+// allPageNumbers 生成 1..pageCount 的页码列表
+func allPageNumbers(pageCount int) []int {
+	pages := make([]int, 0, pageCount)
+	for i := 1; i <= pageCount; i++ {
+		pages = append(pages, i)
+	}
+	return pages
+}
+
+// applyAPIServerConfig 根据配置启动或停止本地API服务，配置没有变化时保持现有服务，不断开事件订阅
+func (a *App) applyAPIServerConfig(cfg config.APIServerConfig) error {
+	a.apiServerMu.Lock()
+	defer a.apiServerMu.Unlock()
+
+	running := a.apiServer.Load() != nil
+	if running == cfg.Enabled && (!cfg.Enabled || cfg == a.apiServerConfig) {
+		return nil
+	}
+
+	if previous := a.apiServer.Swap(nil); previous != nil {
+		if err := previous.Stop(); err != nil {
+			logger.Errorf("停止API服务失败: %v", err)
+		}
+	}
+
+	if !cfg.Enabled {
+		return nil
+	}
+
+	// 首次启用时自动生成访问令牌并保存
+	if cfg.Token == "" {
+		token, err := apiserver.GenerateToken()
+		if err != nil {
+			return err
+		}
+		cfg.Token = token
+
+		full := a.configManager.GetConfig()
+		full.APIServer = cfg
+		if err := a.configManager.UpdateConfig(full); err != nil {
+			return err
+		}
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("获取用户目录失败: %w", err)
+	}
+
+	server, err := apiserver.NewServer(&apiBackend{app: a}, cfg.Port, cfg.Token, filepath.Join(homeDir, ".pdfSeer", "uploads"))
+	if err != nil {
+		return err
+	}
+	if err := server.Start(); err != nil {
+		return err
+	}
+
+	a.apiServer.Store(server)
+	a.apiServerConfig = cfg
+	return nil
+}
+
+// GetAPIServerStatus 获取本地API服务状态
+func (a *App) GetAPIServerStatus() map[string]interface{} {
+	cfg := a.configManager.GetConfig().APIServer
+
+	status := map[string]interface{}{
+		"enabled": cfg.Enabled,
+		"running": false,
+		"token":   cfg.Token,
+	}
+	if server := a.apiServer.Load(); server != nil {
+		status["running"] = true
+		status["address"] = "http://" + server.Addr()
+	}
+	return status
+}
+
+// RegenerateAPIToken 重新生成API访问令牌并重启服务
+func (a *App) RegenerateAPIToken() (string, error) {
+	token, err := apiserver.GenerateToken()
+	if err != nil {
+		return "", err
+	}
+
+	full := a.configManager.GetConfig()
+	full.APIServer.Token = token
+	if err := a.configManager.UpdateConfig(full); err != nil {
+		return "", err
+	}
+
+	if err := a.applyAPIServerConfig(full.APIServer); err != nil {
+		return "", err
+	}
+	return token, nil
+}
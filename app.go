@@ -3,31 +3,68 @@ package main
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"html"
 	"io/ioutil"
-	"log"
+	"os"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"pdf-ocr-ai/pkg/annotations"
+	"pdf-ocr-ai/pkg/apiserver"
+	"pdf-ocr-ai/pkg/bookmarks"
 	"pdf-ocr-ai/pkg/cache"
 	"pdf-ocr-ai/pkg/config"
 	"pdf-ocr-ai/pkg/document"
+	"pdf-ocr-ai/pkg/encryption"
+	"pdf-ocr-ai/pkg/exclusions"
+	"pdf-ocr-ai/pkg/extraction"
+	"pdf-ocr-ai/pkg/glossary"
 	"pdf-ocr-ai/pkg/history"
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/jobs"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/memguard"
 	"pdf-ocr-ai/pkg/ocr"
+	"pdf-ocr-ai/pkg/outline"
 	"pdf-ocr-ai/pkg/pdf"
+	"pdf-ocr-ai/pkg/postprocess"
+	"pdf-ocr-ai/pkg/profiles"
+	"pdf-ocr-ai/pkg/proofread"
+	"pdf-ocr-ai/pkg/reviews"
+	"pdf-ocr-ai/pkg/revisions"
+	"pdf-ocr-ai/pkg/scheduler"
+	"pdf-ocr-ai/pkg/storage"
+	"pdf-ocr-ai/pkg/summaries"
 	"pdf-ocr-ai/pkg/system"
+	"pdf-ocr-ai/pkg/tags"
+	"pdf-ocr-ai/pkg/watcher"
+	"pdf-ocr-ai/pkg/zhconv"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 // ProgressUpdate 进度更新
 type ProgressUpdate struct {
+	DocumentID  string `json:"document_id,omitempty"`
+	TaskID      string `json:"task_id,omitempty"`
 	Total       int    `json:"total"`
 	Processed   int    `json:"processed"`
 	CurrentPage int    `json:"current_page"`
 	Status      string `json:"status"`
 	Error       string `json:"error,omitempty"`
+
+	// 耗时和用量统计（页面完成时填写）
+	ElapsedSeconds float64 `json:"elapsed_seconds,omitempty"`  // 开始以来的耗时
+	PageSeconds    float64 `json:"page_seconds,omitempty"`     // 本页处理耗时
+	AvgPageSeconds float64 `json:"avg_page_seconds,omitempty"` // 最近页面的平均处理耗时
+	PagesPerMinute float64 `json:"pages_per_minute,omitempty"` // 吞吐量
+	EtaSeconds     float64 `json:"eta_seconds,omitempty"`      // 预计剩余时间
+	TotalTokens    int     `json:"total_tokens,omitempty"`     // 本批次累计token用量（与其他并发批次共享客户端时为近似值）
 }
 
 // ProcessingState 处理状态
@@ -44,51 +81,125 @@ const (
 type App struct {
 	ctx               context.Context
 	configManager     *config.ConfigManager
+	store             *storage.Store
+	encryption        *encryption.Manager
 	cacheManager      *cache.CacheManager
 	historyManager    *history.HistoryManager
+	profileManager    *profiles.Manager
+	extractionManager *extraction.Manager
+	tagManager        *tags.Manager
+	proofreadManager  *proofread.Manager
+	revisionManager   *revisions.Manager
+	annotationManager *annotations.Manager
+	bookmarkManager   *bookmarks.Manager
+	exclusionManager  *exclusions.Manager
+	reviewManager     *reviews.Manager
+	summaryManager    *summaries.Manager
+	summaryMu         sync.Mutex         // 避免并发页面重复生成同一章节的摘要
+	audioCancel       context.CancelFunc // 取消正在进行的语音导出
+	jobManager        *jobs.JobManager
 	pdfProcessor      *pdf.PDFProcessor
 	documentProcessor *document.DocumentProcessor
-	ocrClient         *ocr.OpenAIClient
-	currentDoc        *pdf.PDFDocument
+	ocrClient         ocr.Provider
 	mu                sync.RWMutex
-	// 批量处理控制
-	processingCancel context.CancelFunc
-	processingMu     sync.Mutex
-	processingState  ProcessingState
-	pauseSignal      chan bool
-	resumeSignal     chan bool
-	currentBatch     []int // 当前批次的页面
-	processedInBatch int   // 当前批次已处理的页面数
+	// 多文档工作区
+	sessions        map[string]*DocumentSession      // 已打开的文档，按文档ID索引
+	sessionOrder    []string                         // 文档打开顺序
+	activeSessionID string                           // 当前活动文档ID
+	queue           *ProcessingQueue                 // 跨文档批量处理队列
+	folderWatcher   *watcher.FolderWatcher           // 监视文件夹（未启用时为nil）
+	apiServer       atomic.Pointer[apiserver.Server] // 本地HTTP API服务（未启用时为nil）
+	apiServerMu     sync.Mutex                       // 保护API服务的启动和停止
+	apiServerConfig config.APIServerConfig           // API服务当前使用的配置
+	probingModels   sync.Map                         // 正在后台检测能力的模型名称
+	scheduler       *scheduler.Scheduler             // 定时任务调度器
+	errorEvents     *errorEventLog                   // 最近的错误事件，用于诊断包
+	events          *eventCoalescer                  // 合并高频的进度事件
+	memoryMonitor   *memguard.Monitor                // 内存占用监视
+	diskMonitorStop chan struct{}                    // 停止监视磁盘空间
+	maintenanceStop chan struct{}                    // 停止定期存储维护
+	configWatchStop chan struct{}                    // 停止监视配置文件
+	tasks           *taskRegistry                    // 可按ID取消的单页和AI任务
+	importDirs      map[string]string                // 导入ZIP和邮件附件时的解压目录 -> 来源邮件（ZIP为空），来源和其中的文档都关闭后删除
 }
 
 // NewApp creates a new App application struct
 func NewApp() *App {
-	return &App{}
+	a := &App{
+		sessions:    make(map[string]*DocumentSession),
+		queue:       newProcessingQueue(),
+		scheduler:   scheduler.NewScheduler(),
+		errorEvents: &errorEventLog{},
+		tasks:       newTaskRegistry(),
+		importDirs:  make(map[string]string),
+	}
+	a.events = newEventCoalescer(a.dispatch)
+	return a
+}
+
+// emit 发送事件到前端，高频的进度和单页完成事件合并后批量发送
+func (a *App) emit(eventName string, data interface{}) {
+	if !a.events.add(eventName, data) {
+		a.events.sendAfterPending(eventName, data)
+	}
+}
+
+// dispatch 立即发送事件到前端，并同步推送给API服务的SSE订阅者
+func (a *App) dispatch(eventName string, data interface{}) {
+	runtime.EventsEmit(a.ctx, eventName, data)
+	a.errorEvents.record(eventName, data)
+	if server := a.apiServer.Load(); server != nil {
+		server.Publish(eventName, data)
+	}
 }
 
 // startup is called when the app starts. The context is saved
 // so we can call the runtime methods
 func (a *App) startup(ctx context.Context) {
-	fmt.Printf("[DEBUG] startup 方法被调用\n")
+	logger.Debugf("startup 方法被调用")
 	a.ctx = ctx
 
 	// 检查系统依赖
-	fmt.Printf("[DEBUG] 检查系统依赖\n")
+	logger.Debugf("检查系统依赖")
 	sysInfo := system.CheckDependencies()
 	dependencyReport := system.FormatDependencyReport(sysInfo)
-	fmt.Printf("[INFO] 系统依赖检查结果:\n%s", dependencyReport)
+	logger.Infof("系统依赖检查结果:\n%s", dependencyReport)
 
 	// 发送依赖检查结果到前端
 	runtime.EventsEmit(ctx, "dependency-check", sysInfo)
 
+	// 接收系统文件拖放
+	runtime.OnFileDrop(ctx, a.handleFileDrop)
+
 	// 初始化各个组件
 	if err := a.initializeComponents(); err != nil {
-		fmt.Printf("[ERROR] 初始化组件失败: %v\n", err)
-		log.Printf("初始化组件失败: %v", err)
-		runtime.EventsEmit(ctx, "error", fmt.Sprintf("初始化失败: %v", err))
+		logger.Errorf("初始化组件失败: %v", err)
+		a.emitError("error", newEventError(ErrCodeInitFailed, i18n.T("init.failed", err)))
 	} else {
-		fmt.Printf("[DEBUG] 所有组件初始化成功\n")
+		logger.Debugf("所有组件初始化成功")
+		a.detectInterruptedJobs()
+		a.notifyEncryptionLocked()
+
+		if err := a.applyWatchFolderConfig(a.configManager.GetConfig().WatchFolder); err != nil {
+			logger.Infof("监视文件夹未启动: %v", err)
+		}
+
+		if err := a.applyAPIServerConfig(a.configManager.GetConfig().APIServer); err != nil {
+			logger.Infof("API服务未启动: %v", err)
+		}
+
+		if err := a.applyScheduleConfig(a.configManager.GetConfig().Schedules); err != nil {
+			logger.Infof("定时任务未启动: %v", err)
+		}
+
+		a.startMaintenanceLoop()
+		a.startConfigWatch()
+		a.startMemoryGuard()
+		a.startDiskMonitor()
 	}
+
+	// 组件初始化失败时同样执行自检，帮助定位原因
+	go a.runStartupSelfTest()
 }
 
 // initializeComponents 初始化组件
@@ -100,51 +211,162 @@ func (a *App) initializeComponents() error {
 	if err != nil {
 		return fmt.Errorf("初始化配置管理器失败: %w", err)
 	}
+	applyLogLevel(a.configManager.GetConfig().Logging.Level)
+	i18n.SetLanguage(a.configManager.GetConfig().UI.Language)
+
+	// 打开统一数据库（缓存和历史记录共用）
+	a.store, err = storage.Open()
+	if err != nil {
+		return fmt.Errorf("打开数据库失败: %w", err)
+	}
+
+	// 初始化静态数据加密（钥匙串读取失败时保持锁定，可稍后手动解锁）
+	a.encryption, err = encryption.NewManager(a.store.DataDir())
+	if a.encryption == nil {
+		return fmt.Errorf("初始化数据加密失败: %w", err)
+	}
+	if err != nil {
+		logger.Warnf("数据加密未能自动解锁: %v", err)
+	}
+	a.store.SetCipher(a.encryption)
 
 	// 初始化缓存管理器
-	a.cacheManager, err = cache.NewCacheManager()
+	a.cacheManager, err = cache.NewCacheManager(a.store)
 	if err != nil {
 		return fmt.Errorf("初始化缓存管理器失败: %w", err)
 	}
 
 	// 初始化历史记录管理器
-	a.historyManager, err = history.NewHistoryManager()
+	a.historyManager, err = history.NewHistoryManager(a.store)
 	if err != nil {
 		return fmt.Errorf("初始化历史记录管理器失败: %w", err)
 	}
 
-	// 初始化PDF处理器
+	// 初始化文档处理配置
+	a.profileManager, err = profiles.NewManager(a.store)
+	if err != nil {
+		return fmt.Errorf("初始化处理配置失败: %w", err)
+	}
+
+	// 初始化结构化提取结果存储
+	a.extractionManager, err = extraction.NewManager(a.store)
+	if err != nil {
+		return fmt.Errorf("初始化提取结果存储失败: %w", err)
+	}
+
+	// 初始化页面标签存储
+	a.tagManager, err = tags.NewManager(a.store)
+	if err != nil {
+		return fmt.Errorf("初始化页面标签存储失败: %w", err)
+	}
+
+	// 初始化校对修改存储
+	a.proofreadManager, err = proofread.NewManager(a.store)
+	if err != nil {
+		return fmt.Errorf("初始化校对修改存储失败: %w", err)
+	}
+
+	// 初始化页面文本历史版本存储
+	a.revisionManager, err = revisions.NewManager(a.store, a.configManager.GetConfig().Storage.MaxPageRevisions)
+	if err != nil {
+		return fmt.Errorf("初始化页面历史版本存储失败: %w", err)
+	}
+
+	// 初始化页面批注存储
+	a.annotationManager, err = annotations.NewManager(a.store)
+	if err != nil {
+		return fmt.Errorf("初始化页面批注存储失败: %w", err)
+	}
+
+	// 初始化页面书签存储
+	a.bookmarkManager, err = bookmarks.NewManager(a.store)
+	if err != nil {
+		return fmt.Errorf("初始化页面书签存储失败: %w", err)
+	}
+
+	// 初始化排除页面存储
+	a.exclusionManager, err = exclusions.NewManager(a.store)
+	if err != nil {
+		return fmt.Errorf("初始化排除页面存储失败: %w", err)
+	}
+
+	// 初始化页面审核状态存储
+	a.reviewManager, err = reviews.NewManager(a.store)
+	if err != nil {
+		return fmt.Errorf("初始化页面审核状态存储失败: %w", err)
+	}
+
+	// 初始化章节摘要缓存
+	a.summaryManager, err = summaries.NewManager(a.store)
+	if err != nil {
+		return fmt.Errorf("初始化章节摘要缓存失败: %w", err)
+	}
+
+	// 跨缓存和历史记录的视图
+	if err := a.store.Migrate("views", storage.ViewMigrations()); err != nil {
+		return fmt.Errorf("初始化数据库视图失败: %w", err)
+	}
+
+	// 初始化任务管理器
+	a.jobManager, err = jobs.NewJobManager()
+	if err != nil {
+		return fmt.Errorf("初始化任务管理器失败: %w", err)
+	}
+
+	// 初始化PDF处理器（先在主流程中初始化 libvips，再由各工作协程并发渲染）
+	if err := pdf.InitVips(); err != nil {
+		logger.Errorf("%v", err)
+	}
+	a.applyRenderConfig(a.configManager.GetConfig().Render)
 	a.pdfProcessor, err = pdf.NewPDFProcessor()
 	if err != nil {
 		return fmt.Errorf("初始化PDF处理器失败: %w", err)
 	}
+	a.pdfProcessor.SetImageCache(a.cacheManager.Images())
+	a.applyImageCacheConfig(a.configManager.GetConfig().Storage)
 
 	// 初始化文档处理器
-	fmt.Printf("[DEBUG] 开始初始化文档处理器\n")
+	logger.Debugf("开始初始化文档处理器")
 	a.documentProcessor, err = document.NewDocumentProcessor()
 	if err != nil {
-		fmt.Printf("[ERROR] 初始化文档处理器失败: %v\n", err)
+		logger.Errorf("初始化文档处理器失败: %v", err)
 		return fmt.Errorf("初始化文档处理器失败: %w", err)
 	}
-	fmt.Printf("[DEBUG] 文档处理器初始化成功\n")
+	logger.Debugf("文档处理器初始化成功")
 
-	// 初始化OCR客户端
-	aiConfig := a.configManager.GetAIConfig()
-	if aiConfig.APIKey != "" {
-		a.ocrClient = ocr.NewOpenAIClient(aiConfig)
-	}
+	// 初始化AI服务（未配置API密钥时为nil）
+	a.applyAIProvider(a.configManager.GetAIConfig())
 
 	return nil
 }
 
+// shutdownTimeout 退出时等待运行中的任务结束的最长时间
+const shutdownTimeout = 10 * time.Second
+
 // shutdown 应用关闭时清理资源
 func (a *App) shutdown(ctx context.Context) {
-	if a.cacheManager != nil {
-		a.cacheManager.Close()
+	a.applyWatchFolderConfig(config.WatchFolderConfig{})
+	a.applyAPIServerConfig(config.APIServerConfig{})
+	a.scheduler.Stop()
+
+	// 先取消并等待运行中的批量处理，再关闭数据库和 libvips
+	a.tasks.cancelAll()
+	tasksDone := a.tasks.wait(shutdownTimeout)
+	if !tasksDone {
+		logger.Warnf("等待处理任务结束超时，跳过释放 libvips")
 	}
-	if a.historyManager != nil {
-		a.historyManager.Close()
+
+	a.stopMaintenanceLoop()
+	a.stopConfigWatch()
+	a.stopMemoryGuard()
+	a.stopDiskMonitor()
+	if a.store != nil {
+		a.store.Close()
+	}
+	if a.jobManager != nil {
+		a.jobManager.Close()
 	}
+	a.removeImportDirs(true)
 	if a.pdfProcessor != nil {
 		a.pdfProcessor.Cleanup()
 	}
@@ -154,6 +376,10 @@ func (a *App) shutdown(ctx context.Context) {
 	if a.ocrClient != nil {
 		a.ocrClient.Close()
 	}
+	// 仍有任务在渲染时释放 libvips 会导致崩溃，进程退出时由系统回收
+	if tasksDone {
+		pdf.ShutdownVips()
+	}
 }
 
 // Greet returns a greeting for the given name
@@ -170,63 +396,79 @@ func (a *App) SelectFile() (string, error) {
 				DisplayName: "PDF文件",
 				Pattern:     "*.pdf",
 			},
+			{
+				DisplayName: "DjVu文件",
+				Pattern:     "*.djvu;*.djv",
+			},
+			{
+				DisplayName: "邮件",
+				Pattern:     "*.eml;*.msg",
+			},
 		},
 	}
 
 	return runtime.OpenFileDialog(a.ctx, options)
 }
 
-// LoadDocument 加载文档文件（支持多种格式）
+// LoadDocument 加载文档文件（支持多种格式），并设为当前活动文档
 func (a *App) LoadDocument(filePath string) error {
-	fmt.Printf("[DEBUG] 开始加载文档: %s\n", filePath)
-	a.mu.Lock()
-	defer a.mu.Unlock()
+	_, err := a.OpenDocument(filePath)
+	return err
+}
+
+// loadDocumentSession 加载文档并创建会话（不注册到工作区）
+func (a *App) loadDocumentSession(filePath string) (*DocumentSession, error) {
+	logger.Debugf("开始加载文档: %s", filePath)
 
 	// 首先检查 documentProcessor 是否已初始化
-	fmt.Printf("[DEBUG] documentProcessor 是否为 nil: %v\n", a.documentProcessor == nil)
+	logger.Debugf("documentProcessor 是否为 nil: %v", a.documentProcessor == nil)
 	if a.documentProcessor == nil {
-		fmt.Printf("[ERROR] documentProcessor 未初始化\n")
-		return fmt.Errorf("documentProcessor 未初始化")
+		logger.Errorf("documentProcessor 未初始化")
+		return nil, fmt.Errorf("documentProcessor 未初始化")
+	}
+
+	// 从压缩包或邮件中解压的文件在关闭后被删除，恢复或重试以前的任务时无法再打开
+	if err := importedFileRemoved(filePath); err != nil {
+		return nil, err
 	}
 
 	// 检查文件格式是否支持
-	fmt.Printf("[DEBUG] 检查文件格式支持性\n")
+	logger.Debugf("检查文件格式支持性")
 	if !a.documentProcessor.IsSupported(filePath) {
-		fmt.Printf("[ERROR] 不支持的文件格式: %s\n", filePath)
-		return fmt.Errorf("不支持的文件格式")
+		logger.Errorf("不支持的文件格式: %s", filePath)
+		return nil, fmt.Errorf("不支持的文件格式")
 	}
 
 	// 加载文档
-	fmt.Printf("[DEBUG] 开始加载文档内容\n")
+	logger.Debugf("开始加载文档内容")
 
 	doc, err := a.documentProcessor.LoadDocument(filePath)
 	if err != nil {
-		fmt.Printf("[ERROR] 加载文档失败: %v\n", err)
-		return fmt.Errorf("加载文档失败: %w", err)
+		logger.Errorf("加载文档失败: %v", err)
+		return nil, i18n.Errorf("doc.load_failed", err)
 	}
 
-	fmt.Printf("[DEBUG] 文档加载成功，页数: %d\n", doc.PageCount)
-
-	a.currentDoc = doc
+	logger.Debugf("文档加载成功，页数: %d", doc.PageCount)
 
 	// 生成文档ID并检查缓存
 	documentID, err := a.cacheManager.GenerateDocumentID(filePath)
 	if err != nil {
-		log.Printf("生成文档ID失败: %v", err)
+		logger.Errorf("生成文档ID失败: %v", err)
+		// 无法生成缓存ID时使用临时ID，文档仍可在工作区中使用
+		documentID = fmt.Sprintf("temp-%d", time.Now().UnixNano())
 	} else {
+		if err := a.cacheManager.RekeyLegacyDocument(filePath, documentID); err != nil {
+			logger.Warnf("迁移旧版文档缓存失败: %v", err)
+		}
 		// 尝试从缓存加载
-		if err := a.loadFromCache(documentID); err != nil {
-			log.Printf("从缓存加载失败: %v", err)
+		if err := a.loadFromCache(doc, documentID); err != nil {
+			logger.Errorf("从缓存加载失败: %v", err)
 		}
+		a.loadExcludedPages(doc, documentID)
+		a.loadPageReviews(doc, documentID)
 	}
 
-	// 通知前端文档已加载
-	runtime.EventsEmit(a.ctx, "document-loaded", map[string]interface{}{
-		"document":    doc,
-		"document_id": documentID,
-	})
-
-	return nil
+	return newDocumentSession(documentID, doc), nil
 }
 
 // LoadPDF 加载PDF文件（保持向后兼容）
@@ -236,19 +478,15 @@ func (a *App) LoadPDF(filePath string) error {
 
 // GetCurrentDocument 获取当前文档
 func (a *App) GetCurrentDocument() *pdf.PDFDocument {
-	a.mu.RLock()
-	defer a.mu.RUnlock()
-	return a.currentDoc
+	return a.activeDocument()
 }
 
 // GetPDFPath 获取当前 PDF 文件路径（用于浏览器预览）
 func (a *App) GetPDFPath() (string, error) {
-	a.mu.RLock()
-	doc := a.currentDoc
-	a.mu.RUnlock()
+	doc := a.activeDocument()
 
 	if doc == nil {
-		return "", fmt.Errorf("没有加载的文档")
+		return "", i18n.Errorf("doc.not_loaded")
 	}
 
 	return a.pdfProcessor.GetPDFPath(doc), nil
@@ -256,176 +494,148 @@ func (a *App) GetPDFPath() (string, error) {
 
 // GetPageImage 获取页面图片
 func (a *App) GetPageImage(pageNumber int) ([]byte, error) {
-	a.mu.RLock()
-	doc := a.currentDoc
-	a.mu.RUnlock()
+	doc := a.activeDocument()
 
 	if doc == nil {
-		return nil, fmt.Errorf("未加载PDF文档")
+		return nil, i18n.Errorf("doc.not_loaded")
 	}
 
 	return a.pdfProcessor.GetPageImage(doc, pageNumber)
 }
 
-// ProcessPages 处理选中的页面
-func (a *App) ProcessPages(pageNumbers []int) {
-	go a.processPagesBatch(pageNumbers, false)
+// ProcessPages 处理选中的页面，返回任务ID（文档正在处理时加入队列，返回队列任务ID）
+func (a *App) ProcessPages(pageNumbers []int) string {
+	return a.startOrQueueBatch(a.activeSession(), QueueRequest{Pages: pageNumbers, TaskType: string(jobs.TaskOCR)})
+}
+
+// ProcessSinglePage 处理单个页面（非批量），返回可用于 CancelTask 的任务ID
+func (a *App) ProcessSinglePage(pageNumber int) string {
+	return a.startSinglePage(pageNumber, false)
 }
 
-// ProcessSinglePage 处理单个页面（非批量）
-func (a *App) ProcessSinglePage(pageNumber int) {
-	go a.processSinglePageWithHistory(pageNumber, false)
+// ProcessSinglePageForce 强制处理单个页面（非批量），返回可用于 CancelTask 的任务ID
+func (a *App) ProcessSinglePageForce(pageNumber int) string {
+	return a.startSinglePage(pageNumber, true)
 }
 
-// ProcessSinglePageForce 强制处理单个页面（非批量）
-func (a *App) ProcessSinglePageForce(pageNumber int) {
-	go a.processSinglePageWithHistory(pageNumber, true)
+// startSinglePage 登记单页OCR任务并在后台处理
+func (a *App) startSinglePage(pageNumber int, forceReprocess bool) string {
+	session := a.activeSession()
+	return a.runTask(TaskKindSinglePageOCR, session, []int{pageNumber}, func(ctx context.Context) {
+		a.processSinglePageWithHistory(ctx, session, pageNumber, forceReprocess)
+	})
 }
 
 // processSinglePageWithHistory 处理单个页面并创建历史记录
-func (a *App) processSinglePageWithHistory(pageNumber int, forceReprocess bool) {
-	a.mu.RLock()
-	doc := a.currentDoc
-	a.mu.RUnlock()
+func (a *App) processSinglePageWithHistory(ctx context.Context, session *DocumentSession, pageNumber int, forceReprocess bool) {
+	defer logger.RecoverPanic("processSinglePageWithHistory")
 
-	if doc == nil {
-		runtime.EventsEmit(a.ctx, "processing-error", "未加载PDF文档")
+	if session == nil {
+		a.emitTask(ctx, "processing-error", newEventError(ErrCodeDocumentNotLoaded, i18n.T("doc.not_loaded")))
 		return
 	}
+	doc := session.Doc
 
 	if a.ocrClient == nil {
-		runtime.EventsEmit(a.ctx, "processing-error", "未配置AI服务")
+		a.emitTask(ctx, "processing-error", newEventError(ErrCodeAINotConfigured, i18n.T("ai.not_configured")))
 		return
 	}
 
-	// 获取实际使用的OCR模型名称
-	aiConfig := a.configManager.GetAIConfig()
-	actualOCRModel := aiConfig.OCRModel
-	if actualOCRModel == "" {
-		actualOCRModel = aiConfig.Model
+	// 获取实际使用的OCR模型名称（处理配置可指定模型）
+	actualOCRModel := a.ocrModel(a.resolveProfile(doc.FilePath))
+	if err := a.checkOCRModel(actualOCRModel); err != nil {
+		a.emitModelUnsupported(ctx, actualOCRModel, err)
+		return
 	}
 
-	// 创建历史记录，使用实际的OCR模型名称（OCR任务不添加前缀）
-	historyRecord, err := a.historyManager.CreateRecord(doc.FilePath, 1, actualOCRModel)
+	// 创建历史记录，使用实际的OCR模型名称
+	historyRecord, err := a.createHistoryRecord(doc, 1, history.TaskTypeOCR, actualOCRModel)
 	if err != nil {
-		log.Printf("创建单页OCR历史记录失败: %v", err)
+		logger.Errorf("创建单页OCR历史记录失败: %v", err)
 	}
 
-	// 创建上下文
-	ctx := context.Background()
-
 	// 处理页面
-	err = a.processSinglePage(ctx, pageNumber, historyRecord)
+	err = a.processSinglePage(ctx, doc, pageNumber, historyRecord)
+	if err != nil && ctx.Err() != nil {
+		logger.Infof("单页OCR处理被取消: 页面%d", pageNumber)
+		if historyRecord != nil {
+			a.historyManager.UpdateRecordStatus(historyRecord.ID, history.StatusCancelled, i18n.T("status.cancelled"))
+		}
+		a.emitTask(ctx, "page-processed", map[string]interface{}{
+			"document_id": session.ID,
+			"pageNumber":  pageNumber,
+			"status":      i18n.T("status.cancelled"),
+		})
+		return
+	}
 	if err != nil {
-		log.Printf("单页OCR处理失败: %v", err)
+		logger.Errorf("单页OCR处理失败: %v", err)
 		if historyRecord != nil {
 			a.historyManager.UpdateRecordStatus(historyRecord.ID, history.StatusFailed, err.Error())
 		}
-		runtime.EventsEmit(a.ctx, "processing-error", fmt.Sprintf("处理第%d页失败: %v", pageNumber, err))
+		a.emitPageError(ctx, "processing-error", pageNumber, i18n.T("page.failed", pageNumber, err), err)
 		return
 	}
 
 	// 更新历史记录状态
 	if historyRecord != nil {
-		a.historyManager.UpdateRecordStatus(historyRecord.ID, history.StatusCompleted, "")
+		a.historyManager.FinishRecord(historyRecord.ID, 1, 0, false)
 	}
 
 	// 发送单页完成事件
-	runtime.EventsEmit(a.ctx, "page-processed", map[string]interface{}{
-		"pageNumber": pageNumber,
-		"status":     "处理完成",
+	a.emitTask(ctx, "page-processed", map[string]interface{}{
+		"document_id": session.ID,
+		"pageNumber":  pageNumber,
+		"status":      i18n.T("status.done"),
 	})
 
-	log.Printf("单页OCR处理完成: 页面%d", pageNumber)
+	logger.Infof("单页OCR处理完成: 页面%d", pageNumber)
 }
 
-// ProcessPagesForce 强制重新处理指定页面（跳过缓存）
-func (a *App) ProcessPagesForce(pageNumbers []int) {
-	go a.processPagesBatch(pageNumbers, true)
+// ProcessPagesForce 强制重新处理指定页面（跳过缓存），返回任务ID（文档正在处理时加入队列，返回队列任务ID）
+func (a *App) ProcessPagesForce(pageNumbers []int) string {
+	return a.startOrQueueBatch(a.activeSession(), QueueRequest{Pages: pageNumbers, TaskType: string(jobs.TaskOCR), ForceReprocess: true})
 }
 
-// PauseProcessing 暂停当前的批量处理
+// PauseProcessing 暂停当前文档的批量处理
 func (a *App) PauseProcessing() {
-	a.processingMu.Lock()
-	defer a.processingMu.Unlock()
-
-	if a.processingState == ProcessingStateRunning {
-		log.Printf("用户请求暂停批量处理")
-		a.processingState = ProcessingStatePaused
-
-		// 发送暂停信号
-		select {
-		case a.pauseSignal <- true:
-		default:
-		}
-
-		// 发送暂停通知
-		runtime.EventsEmit(a.ctx, "processing-paused", map[string]interface{}{
-			"message": "批量处理已暂停",
-		})
+	if session := a.activeSession(); session != nil {
+		a.pauseSession(session)
 	}
 }
 
-// ResumeProcessing 继续当前的批量处理
+// ResumeProcessing 继续当前文档的批量处理
 func (a *App) ResumeProcessing() {
-	a.processingMu.Lock()
-	defer a.processingMu.Unlock()
-
-	if a.processingState == ProcessingStatePaused {
-		log.Printf("用户请求继续批量处理")
-		a.processingState = ProcessingStateRunning
-
-		// 发送继续信号
-		select {
-		case a.resumeSignal <- true:
-		default:
-		}
-
-		// 发送继续通知
-		runtime.EventsEmit(a.ctx, "processing-resumed", map[string]interface{}{
-			"message": "批量处理已继续",
-		})
+	if session := a.activeSession(); session != nil {
+		a.resumeSession(session)
 	}
 }
 
-// CancelProcessing 取消当前的批量处理
+// CancelProcessing 取消当前文档的批量处理
 func (a *App) CancelProcessing() {
-	a.processingMu.Lock()
-	defer a.processingMu.Unlock()
-
-	if a.processingState == ProcessingStateRunning || a.processingState == ProcessingStatePaused {
-		log.Printf("用户请求取消批量处理")
-		a.processingState = ProcessingStateCancelling
-
-		if a.processingCancel != nil {
-			a.processingCancel()
-		}
-
-		// 发送取消通知，但不立即清理状态，让批量处理函数自己清理
-		runtime.EventsEmit(a.ctx, "processing-cancelled", map[string]interface{}{
-			"message": "批量处理已取消",
-		})
+	if session := a.activeSession(); session != nil {
+		a.cancelSession(session)
 	}
 }
 
-// GetProcessingState 获取当前处理状态
+// GetProcessingState 获取当前文档的处理状态
 func (a *App) GetProcessingState() map[string]interface{} {
-	a.processingMu.Lock()
-	defer a.processingMu.Unlock()
-
-	return map[string]interface{}{
-		"state":           int(a.processingState),
-		"current_batch":   a.currentBatch,
-		"processed_count": a.processedInBatch,
-		"total_count":     len(a.currentBatch),
+	session := a.activeSession()
+	if session == nil {
+		return map[string]interface{}{
+			"state":           int(ProcessingStateIdle),
+			"current_batch":   []int{},
+			"processed_count": 0,
+			"total_count":     0,
+		}
 	}
+
+	return session.processingSnapshot()
 }
 
 // CheckProcessedPages 检查哪些页面已经处理过
 func (a *App) CheckProcessedPages(pageNumbers []int) map[string]interface{} {
-	a.mu.RLock()
-	doc := a.currentDoc
-	a.mu.RUnlock()
+	doc := a.activeDocument()
 
 	result := map[string]interface{}{
 		"total_pages":       len(pageNumbers),
@@ -442,7 +652,7 @@ func (a *App) CheckProcessedPages(pageNumbers []int) map[string]interface{} {
 	var unprocessedPages []int
 
 	for _, pageNum := range pageNumbers {
-		if cached := a.checkPageCache(pageNum); cached != nil {
+		if cached := a.checkPageCache(doc, pageNum); cached != nil {
 			processedPages = append(processedPages, pageNum)
 		} else {
 			unprocessedPages = append(unprocessedPages, pageNum)
@@ -463,15 +673,66 @@ func (a *App) GetConfig() config.AppConfig {
 
 // UpdateConfig 更新配置
 func (a *App) UpdateConfig(cfg config.AppConfig) error {
+	if err := validateConfig(cfg); err != nil {
+		return err
+	}
 	if err := a.configManager.UpdateConfig(cfg); err != nil {
 		return err
 	}
+	a.removeMissingProfiles(cfg.Profiles)
 
-	// 更新OCR客户端配置
-	if a.ocrClient != nil {
-		a.ocrClient.UpdateConfig(cfg.AI)
-	} else if cfg.AI.APIKey != "" {
-		a.ocrClient = ocr.NewOpenAIClient(cfg.AI)
+	return a.applyConfig(cfg)
+}
+
+// validateConfig 检查配置中相互引用的部分是否有效
+func validateConfig(cfg config.AppConfig) error {
+	if err := validateProfiles(cfg); err != nil {
+		return err
+	}
+	if err := validateExtractionSchemas(cfg); err != nil {
+		return err
+	}
+	for _, mode := range []string{cfg.ChineseConversion.Results, cfg.ChineseConversion.Export} {
+		if mode != "" && !zhconv.ValidMode(mode) {
+			return fmt.Errorf("不支持的简繁转换方式: %s", mode)
+		}
+	}
+	if err := validateZipImport(cfg.UI); err != nil {
+		return err
+	}
+	return glossary.Validate(cfg.Glossary.Terms)
+}
+
+// applyConfig 使新配置在运行时生效
+func (a *App) applyConfig(cfg config.AppConfig) error {
+	applyConfigMu.Lock()
+	defer applyConfigMu.Unlock()
+
+	// 更新AI服务配置（包括频率限制）
+	a.applyAIProvider(cfg.AI)
+
+	applyLogLevel(cfg.Logging.Level)
+	i18n.SetLanguage(cfg.UI.Language)
+	a.applyImageCacheConfig(cfg.Storage)
+	a.applyMemoryConfig(cfg.Storage)
+	a.applyRenderConfig(cfg.Render)
+	if a.revisionManager != nil {
+		a.revisionManager.SetLimit(cfg.Storage.MaxPageRevisions)
+	}
+
+	// 根据新配置重启监视文件夹
+	if err := a.applyWatchFolderConfig(cfg.WatchFolder); err != nil {
+		return err
+	}
+
+	// 根据新配置重启API服务
+	if err := a.applyAPIServerConfig(cfg.APIServer); err != nil {
+		return err
+	}
+
+	// 根据新配置重建定时任务
+	if err := a.applyScheduleConfig(cfg.Schedules); err != nil {
+		return err
 	}
 
 	return nil
@@ -482,201 +743,429 @@ func (a *App) GetHistoryRecords(limit int) ([]*history.HistoryRecord, error) {
 	return a.historyManager.GetRecentRecords(limit)
 }
 
+// ListHistory 按条件分页查询历史记录
+func (a *App) ListHistory(filter history.HistoryFilter) (*history.HistoryListResult, error) {
+	return a.historyManager.ListRecords(filter)
+}
+
+// GetHistoryStats 获取历史统计数据（按天/周的页数统计最近30天）
+func (a *App) GetHistoryStats() (*history.HistoryStats, error) {
+	return a.historyManager.GetStats(30)
+}
+
+// CompareHistoryRecords 逐页比较两条历史记录的文本差异
+func (a *App) CompareHistoryRecords(idA, idB int) (*history.RecordComparison, error) {
+	return a.historyManager.CompareRecords(idA, idB)
+}
+
+// ExportHistory 将符合条件的历史记录导出为归档文件，path为空时弹出保存对话框，返回保存路径
+func (a *App) ExportHistory(path string, filter history.HistoryFilter) (string, error) {
+	if path == "" {
+		var err error
+		path, err = runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+			DefaultFilename: fmt.Sprintf("pdfseer-history-%s.zip", time.Now().Format("20060102")),
+			Filters:         []runtime.FileFilter{{DisplayName: "历史记录归档", Pattern: "*.zip"}},
+			Title:           "导出历史记录",
+		})
+		if err != nil || path == "" {
+			return "", err
+		}
+	}
+
+	count, err := a.historyManager.ExportArchive(path, filter)
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+
+	logger.Infof("已导出 %d 条历史记录到 %s", count, path)
+	return path, nil
+}
+
+// ImportHistory 导入历史记录归档，path为空时弹出选择对话框
+func (a *App) ImportHistory(path string) (*history.ImportResult, error) {
+	if path == "" {
+		var err error
+		path, err = runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+			Filters: []runtime.FileFilter{{DisplayName: "历史记录归档", Pattern: "*.zip"}},
+			Title:   "导入历史记录",
+		})
+		if err != nil || path == "" {
+			return nil, err
+		}
+	}
+
+	result, err := a.historyManager.ImportArchive(path)
+	if err != nil {
+		return result, err
+	}
+
+	logger.Infof("导入历史记录: 新增 %d 条, 跳过 %d 条", result.Imported, result.Skipped)
+	return result, nil
+}
+
+// ArchiveHistory 将days天前的历史记录移入压缩冷存储
+func (a *App) ArchiveHistory(days int) (*history.ColdArchiveResult, error) {
+	result, err := a.historyManager.ArchiveOldRecords(days)
+	if err != nil {
+		return result, err
+	}
+
+	logger.Infof("归档历史记录: %d 条记录, %d 页", result.Records, result.Pages)
+	return result, nil
+}
+
+// RestoreArchivedHistory 从冷存储恢复历史记录（查看页面时也会自动恢复）
+func (a *App) RestoreArchivedHistory(id int) error {
+	return a.historyManager.RestoreArchivedRecord(id)
+}
+
 // GetHistoryPages 获取历史记录页面
 func (a *App) GetHistoryPages(historyID int) ([]*history.HistoryPage, error) {
 	return a.historyManager.GetRecordPages(historyID)
 }
 
+// RelocateDocument 文件移动或重命名后，将缓存和历史记录重新关联到新路径（文件内容必须一致）
+func (a *App) RelocateDocument(oldPath, newPath string) error {
+	if _, err := os.Stat(newPath); err != nil {
+		return fmt.Errorf("新文件不存在: %w", err)
+	}
+
+	documentID, err := a.cacheManager.RelocateDocument(oldPath, newPath)
+	if err != nil {
+		return err
+	}
+	if documentID == "" {
+		// 没有缓存时按新文件内容补全历史记录的文档标识
+		documentID, _ = a.cacheManager.GenerateDocumentID(newPath)
+	}
+
+	updated, err := a.historyManager.RelocateDocument(oldPath, newPath, documentID)
+	if err != nil {
+		return err
+	}
+	if err := a.profileManager.Relocate(oldPath, newPath); err != nil {
+		logger.Warnf("更新文档处理配置失败: %v", err)
+	}
+
+	logger.Infof("文档已重新关联: %s -> %s（%d 条历史记录）", oldPath, newPath, updated)
+	return nil
+}
+
+// GetLatestResults 获取文档每页的最新结果（缓存）
+func (a *App) GetLatestResults(filePath string) ([]storage.LatestPage, error) {
+	documentID, err := a.cacheManager.GenerateDocumentID(filePath)
+	if err != nil {
+		return nil, i18n.Errorf("doc.id_failed", err)
+	}
+	return a.store.LatestPages(documentID)
+}
+
+// GetRunHistory 获取文档所有运行的逐页结果（历史记录）
+func (a *App) GetRunHistory(filePath string) ([]storage.RunPage, error) {
+	documentID, err := a.cacheManager.GenerateDocumentID(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return a.store.RunHistory(documentID, filePath)
+}
+
+// GetRecordProgress 获取历史记录的逐页处理状态（可查看中断或失败的页面）
+func (a *App) GetRecordProgress(historyID int) (*history.RecordProgress, error) {
+	return a.historyManager.GetRecordProgress(historyID)
+}
+
 // GetDocumentHistoryPages 获取文档所有历史记录的页面数据
 func (a *App) GetDocumentHistoryPages(documentPath string) ([]*history.HistoryPage, error) {
 	return a.historyManager.GetDocumentPages(documentPath)
 }
 
-// DeleteHistoryRecord 删除历史记录及相关数据
-func (a *App) DeleteHistoryRecord(historyID int) error {
+// DeleteHistoryRecord 删除历史记录，文档没有其他历史记录时同时清理缓存和处理结果
+// 批注、书签、审核状态和排除页面是用户编辑的数据，只在 deleteDocumentData 为 true 时删除
+func (a *App) DeleteHistoryRecord(historyID int, deleteDocumentData bool) error {
 	// 获取历史记录信息
 	record, err := a.historyManager.GetRecord(historyID)
 	if err != nil {
-		return fmt.Errorf("获取历史记录失败: %w", err)
+		return i18n.Errorf("history.get_failed", err)
 	}
 	if record == nil {
-		return fmt.Errorf("历史记录不存在")
+		return i18n.Errorf("history.not_found")
 	}
 
-	log.Printf("开始删除历史记录 ID=%d, 文档=%s", historyID, record.DocumentPath)
+	logger.Infof("开始删除历史记录 ID=%d, 文档=%s", historyID, record.DocumentPath)
 
 	// 1. 删除历史记录数据库记录
 	if err := a.historyManager.DeleteRecord(historyID); err != nil {
 		return fmt.Errorf("删除历史记录失败: %w", err)
 	}
-	log.Printf("已删除历史记录数据库记录")
+	logger.Infof("已删除历史记录数据库记录")
 
-	// 2. 检查是否还有其他历史记录使用同一文档
-	otherRecords, err := a.historyManager.GetRecordsByDocumentPath(record.DocumentPath)
+	// 2. 检查是否还有其他历史记录使用同一文档（按记录保存的文档标识，文件已修改或移动时也能找到对应缓存）
+	documentID := ""
+	if record.DocumentID != nil {
+		documentID = *record.DocumentID
+	}
+	otherRecords, err := a.historyManager.CountDocumentRecords(documentID, record.DocumentPath)
 	if err != nil {
-		log.Printf("检查其他历史记录失败: %v", err)
+		logger.Errorf("检查其他历史记录失败: %v", err)
 	}
 
 	// 如果没有其他历史记录使用该文档，清理缓存数据
-	if len(otherRecords) == 0 {
-		log.Printf("没有其他历史记录使用文档 %s，开始清理缓存", record.DocumentPath)
+	if err == nil && otherRecords == 0 {
+		logger.Infof("没有其他历史记录使用文档 %s，开始清理缓存", record.DocumentPath)
 
-		// 生成文档ID用于缓存查找
-		documentID, err := a.cacheManager.GenerateDocumentID(record.DocumentPath)
+		// 旧记录没有文档标识时按当前文件生成
+		if documentID == "" {
+			documentID, err = a.cacheManager.GenerateDocumentID(record.DocumentPath)
+		}
 		if err != nil {
-			log.Printf("生成文档ID失败: %v", err)
+			logger.Errorf("生成文档ID失败: %v", err)
 		} else {
 			// 删除缓存数据
 			if err := a.cacheManager.DeleteDocument(documentID); err != nil {
-				log.Printf("删除缓存数据失败: %v", err)
+				logger.Errorf("删除缓存数据失败: %v", err)
 			} else {
-				log.Printf("已删除缓存数据")
+				logger.Infof("已删除缓存数据")
+			}
+			if err := a.extractionManager.DeleteDocument(documentID); err != nil {
+				logger.Errorf("删除提取结果失败: %v", err)
+			}
+			if err := a.tagManager.DeleteDocument(documentID); err != nil {
+				logger.Errorf("删除页面标签失败: %v", err)
+			}
+			if err := a.proofreadManager.DeleteDocument(documentID); err != nil {
+				logger.Errorf("删除校对结果失败: %v", err)
+			}
+			if err := a.revisionManager.DeleteDocument(documentID); err != nil {
+				logger.Errorf("删除页面历史版本失败: %v", err)
+			}
+			if err := a.summaryManager.DeleteDocument(documentID); err != nil {
+				logger.Errorf("删除章节摘要失败: %v", err)
 			}
 		}
 
-		// 如果当前加载的文档是被删除的文档，保持文档加载但清理处理状态
+		// 如果工作区中打开了被删除的文档，保持文档加载但清理处理状态
 		a.mu.Lock()
-		if a.currentDoc != nil && a.currentDoc.FilePath == record.DocumentPath {
-			log.Printf("保持文档加载状态，但清理页面处理数据")
+		for _, session := range a.sessions {
+			if session.Doc.FilePath != record.DocumentPath {
+				continue
+			}
+			logger.Infof("保持文档加载状态，但清理页面处理数据")
 			// 清理页面的处理状态，但保持文档结构
-			for i := range a.currentDoc.Pages {
-				a.currentDoc.Pages[i].OCRText = ""
-				a.currentDoc.Pages[i].AIText = ""
-				a.currentDoc.Pages[i].Processed = false
+			for i := range session.Doc.Pages {
+				session.Doc.Pages[i].OCRText = ""
+				session.Doc.Pages[i].AIText = ""
+				session.Doc.Pages[i].Processed = false
 			}
 		}
 		a.mu.Unlock()
 	} else {
-		log.Printf("文档 %s 还有 %d 个其他历史记录，保留缓存数据", record.DocumentPath, len(otherRecords))
+		logger.Infof("文档 %s 还有 %d 个其他历史记录，保留缓存数据", record.DocumentPath, otherRecords)
+	}
+
+	if deleteDocumentData {
+		a.deleteDocumentData(documentID, record.DocumentPath)
 	}
 
-	log.Printf("历史记录删除完成")
+	logger.Infof("历史记录删除完成")
 	return nil
 }
 
+// deleteDocumentData 删除文档的批注、书签、审核状态和排除页面，并清除已打开文档中的对应状态
+func (a *App) deleteDocumentData(documentID, filePath string) {
+	if documentID == "" {
+		var err error
+		if documentID, err = a.cacheManager.GenerateDocumentID(filePath); err != nil {
+			logger.Errorf("生成文档ID失败: %v", err)
+			return
+		}
+	}
+	if err := a.annotationManager.DeleteDocument(documentID); err != nil {
+		logger.Errorf("删除页面批注失败: %v", err)
+	}
+	if err := a.bookmarkManager.DeleteDocument(documentID); err != nil {
+		logger.Errorf("删除页面书签失败: %v", err)
+	}
+	if err := a.exclusionManager.DeleteDocument(documentID); err != nil {
+		logger.Errorf("删除排除页面失败: %v", err)
+	}
+	if err := a.reviewManager.DeleteDocument(documentID); err != nil {
+		logger.Errorf("删除审核状态失败: %v", err)
+	}
+
+	a.mu.Lock()
+	for _, session := range a.sessions {
+		if session.Doc.FilePath != filePath {
+			continue
+		}
+		for _, page := range session.Doc.Pages {
+			page.Excluded = false
+			page.ReviewStatus = ""
+		}
+	}
+	a.mu.Unlock()
+	a.emit("bookmarks-updated", map[string]interface{}{"document_id": documentID})
+	logger.Infof("已删除文档 %s 的批注、书签、审核状态和排除页面", filePath)
+}
+
 // SearchHistory 搜索历史记录
 func (a *App) SearchHistory(keyword string, limit int) ([]*history.SearchResult, error) {
 	return a.historyManager.SearchContent(keyword, limit)
 }
 
-// processPagesBatch 批量处理页面
-func (a *App) processPagesBatch(pageNumbers []int, forceReprocess bool) {
-	a.mu.RLock()
-	doc := a.currentDoc
-	a.mu.RUnlock()
+// SearchHistoryAdvanced 按查询语法、搜索范围和过滤条件分页搜索历史内容
+func (a *App) SearchHistoryAdvanced(options history.SearchOptions) (*history.SearchResultPage, error) {
+	return a.historyManager.Search(options)
+}
 
-	if doc == nil {
-		log.Printf("未加载PDF文档，建议用户重新选择文件")
-		runtime.EventsEmit(a.ctx, "processing-error", map[string]interface{}{
-			"error":   "未加载PDF文档",
-			"message": "请重新选择PDF文件。如果刚刚删除了历史记录，文档可能需要重新加载。",
-			"code":    "DOCUMENT_NOT_LOADED",
-			"action":  "RELOAD_DOCUMENT",
-		})
-		return
+// startPagesBatch 登记OCR批量任务并在后台处理，返回任务ID
+// job不为空时表示恢复中断的任务，record 不为空时结果追加到该历史记录（重试失败页面）
+func (a *App) startPagesBatch(session *DocumentSession, pageNumbers []int, forceReprocess bool, job *jobs.Job, record *history.HistoryRecord) string {
+	return a.runTask(TaskKindBatchOCR, session, pageNumbers, func(ctx context.Context) {
+		a.reportBatchBusy(ctx, a.processPagesBatch(ctx, session, pageNumbers, forceReprocess, job, record))
+	})
+}
+
+// errBatchBusy 文档已有批次在运行，批次没有开始
+var errBatchBusy = errors.New("batch busy")
+
+// reportBatchBusy 批次因文档已有批次在运行而没有开始时通知前端
+// 队列任务遇到这种情况会重新排队，不调用此方法
+func (a *App) reportBatchBusy(ctx context.Context, err error) {
+	if errors.Is(err, errBatchBusy) {
+		a.emitTask(ctx, "processing-error", newEventError(ErrCodeDocumentBusy, i18n.T("batch.busy")))
+	}
+}
+
+// processPagesBatch 批量处理页面（阻塞直到处理结束），ctx 为任务的上下文
+// 批次没有开始、被取消或全部页面失败时返回错误，部分页面失败时返回nil
+// 文档已有批次在运行时返回 errBatchBusy 且不通知前端，由调用方决定如何提示
+func (a *App) processPagesBatch(ctx context.Context, session *DocumentSession, pageNumbers []int, forceReprocess bool, job *jobs.Job, record *history.HistoryRecord) error {
+	defer logger.RecoverPanic("processPagesBatch")
+
+	if session == nil {
+		logger.Infof("未加载PDF文档，建议用户重新选择文件")
+		a.emitTask(ctx, "processing-error", newEventError(ErrCodeDocumentNotLoaded, i18n.T("doc.not_loaded")))
+		return i18n.Errorf("doc.not_loaded")
 	}
 
+	doc := session.Doc
+
 	if a.ocrClient == nil {
-		runtime.EventsEmit(a.ctx, "processing-error", "未配置AI服务")
-		return
+		a.emitTask(ctx, "processing-error", newEventError(ErrCodeAINotConfigured, i18n.T("ai.not_configured")))
+		return i18n.Errorf("ai.not_configured")
 	}
 
-	// 初始化处理状态
-	a.processingMu.Lock()
-	processingCtx, cancel := context.WithCancel(a.ctx)
-	a.processingCancel = cancel
-	a.processingState = ProcessingStateRunning
-	a.currentBatch = pageNumbers
-	a.processedInBatch = 0
+	// 获取实际使用的OCR模型名称（处理配置可指定模型），开始前确认模型支持图片识别
+	actualOCRModel := a.ocrModel(a.resolveProfile(doc.FilePath))
+	if err := a.checkOCRModel(actualOCRModel); err != nil {
+		a.emitModelUnsupported(ctx, actualOCRModel, err)
+		return err
+	}
 
-	// 初始化信号通道
-	if a.pauseSignal == nil {
-		a.pauseSignal = make(chan bool, 1)
+	// 渲染图片会写入临时目录和图片缓存，预计空间不足时不开始处理
+	if err := a.checkBatchDiskSpace(len(pageNumbers)); err != nil {
+		logger.Warnf("拒绝开始批量处理: %v", err)
+		a.emitTask(ctx, "processing-error", newEventError(ErrCodeDiskSpaceLow, err.Error()))
+		return err
 	}
-	if a.resumeSignal == nil {
-		a.resumeSignal = make(chan bool, 1)
+
+	// 初始化处理状态，取消批次即取消任务；文档已有批次在运行时不开始，避免两个批次共用处理状态
+	if !session.beginBatch(taskIDFromContext(ctx), pageNumbers, a.taskCanceller(ctx)) {
+		// 恢复的任务保持中断状态，之后还可以继续
+		a.finishJob(job, jobs.StatusInterrupted, i18n.T("batch.busy"))
+		return errBatchBusy
 	}
-	a.processingMu.Unlock()
 
-	// 确保在函数结束时清理
-	defer func() {
-		a.processingMu.Lock()
-		if a.processingCancel != nil {
-			a.processingCancel = nil
-		}
-		a.processingState = ProcessingStateIdle
-		a.currentBatch = nil
-		a.processedInBatch = 0
-		a.processingMu.Unlock()
-		cancel()
-	}()
+	// 确保在函数结束时清理，并启动排在其后的队列任务
+	defer a.endSessionBatch(session)
 
-	// 获取实际使用的OCR模型名称
-	aiConfig := a.configManager.GetAIConfig()
-	actualOCRModel := aiConfig.OCRModel
-	if actualOCRModel == "" {
-		actualOCRModel = aiConfig.Model
+	// 创建历史记录，使用实际的OCR模型名称
+	historyRecord := record
+	if historyRecord == nil {
+		var err error
+		historyRecord, err = a.createHistoryRecord(doc, len(pageNumbers), history.TaskTypeOCR, actualOCRModel)
+		if err != nil {
+			logger.Errorf("创建历史记录失败: %v", err)
+		}
+	} else {
+		a.reopenHistoryRecord(historyRecord)
 	}
+	session.setHistoryRecord(historyRecord)
+	a.initHistoryPages(historyRecord, pageNumbers)
 
-	// 创建历史记录，使用实际的OCR模型名称（OCR任务不添加前缀）
-	historyRecord, err := a.historyManager.CreateRecord(doc.FilePath, len(pageNumbers), actualOCRModel)
-	if err != nil {
-		log.Printf("创建历史记录失败: %v", err)
+	// 持久化任务，以便应用异常退出后可以继续
+	if job == nil {
+		job = a.createJob(doc.FilePath, jobs.TaskOCR, pageNumbers, "", false, false, forceReprocess, historyRecord)
 	}
 
+	tracker := a.startBatchTracking(session, string(jobs.TaskOCR), actualOCRModel, pageNumbers, historyRecord)
+
 	// 发送初始进度
-	runtime.EventsEmit(a.ctx, "processing-progress", ProgressUpdate{
-		Total:     len(pageNumbers),
-		Processed: 0,
-		Status:    "开始处理",
+	a.emitTask(ctx, "processing-progress", ProgressUpdate{
+		DocumentID: session.ID,
+		Total:      len(pageNumbers),
+		Processed:  0,
+		Status:     i18n.T("status.started"),
 	})
 
 	// 使用并发处理（传入可取消的上下文）
-	processed := a.processPagesConcurrently(processingCtx, pageNumbers, historyRecord, doc, forceReprocess)
+	processed, succeeded := a.processPagesConcurrently(ctx, session, pageNumbers, historyRecord, forceReprocess, job, tracker)
 
 	// 检查上下文是否被取消
 	select {
-	case <-processingCtx.Done():
-		log.Printf("批量处理被取消")
-		if historyRecord != nil {
-			a.historyManager.UpdateRecordStatus(historyRecord.ID, history.StatusCancelled, "处理被用户取消")
-		}
-		return
+	case <-ctx.Done():
+		logger.Infof("批量处理被取消")
+		a.finishHistoryRecord(historyRecord, record != nil, succeeded, processed-succeeded, true)
+		a.finishJob(job, jobs.StatusCancelled, "处理被用户取消")
+		a.finishBatchTracking(tracker, succeeded, true, "处理被用户取消")
+		return ctx.Err()
 	default:
 		// 正常完成
 	}
 
-	// 更新历史记录状态
-	if historyRecord != nil {
-		a.historyManager.UpdateRecordStatus(historyRecord.ID, history.StatusCompleted, "")
-	}
+	a.finishBatchJob(job, succeeded, processed-succeeded)
+	a.finishBatchTracking(tracker, succeeded, false, "")
+	a.reportWriteFailures(session.ID)
+
+	// 更新历史记录状态（部分页面失败时标记为 partial）
+	a.finishHistoryRecord(historyRecord, record != nil, succeeded, processed-succeeded, false)
 
 	// 发送完成通知
-	runtime.EventsEmit(a.ctx, "processing-complete", map[string]interface{}{
+	a.emitTask(ctx, "processing-complete", map[string]interface{}{
+		"document_id":     session.ID,
 		"total_processed": processed,
 		"document":        doc,
 		"processedPages":  pageNumbers, // 添加处理过的页面信息
 	})
+
+	if succeeded == 0 && processed > 0 {
+		return i18n.Errorf("batch.all_failed")
+	}
+	return nil
 }
 
 // processSinglePage 处理单个页面
-func (a *App) processSinglePage(ctx context.Context, pageNum int, historyRecord *history.HistoryRecord) error {
-	a.mu.RLock()
-	doc := a.currentDoc
-	a.mu.RUnlock()
-
+func (a *App) processSinglePage(ctx context.Context, doc *pdf.PDFDocument, pageNum int, historyRecord *history.HistoryRecord) error {
 	if doc == nil {
-		return fmt.Errorf("未加载PDF文档")
+		return i18n.Errorf("doc.not_loaded")
 	}
 
 	startTime := time.Now()
+	profile := a.resolveProfile(doc.FilePath)
 
-	// 渲染页面为图片
-	imagePath, err := a.pdfProcessor.RenderPageToImage(doc, pageNum)
+	// 按处理配置的分辨率和预处理步骤渲染页面
+	imagePath, cleanup, err := a.renderPageForOCR(doc, pageNum, profile)
 	if err != nil {
-		return fmt.Errorf("渲染页面失败: %w", err)
+		return err
 	}
+	defer cleanup()
 
+	return a.recognizePage(ctx, doc, pageNum, imagePath, profile, historyRecord, startTime)
+}
+
+// recognizePage 识别已渲染的页面图片，保存结果到文档、缓存和历史记录
+func (a *App) recognizePage(ctx context.Context, doc *pdf.PDFDocument, pageNum int, imagePath string, profile *config.ProcessingProfile, historyRecord *history.HistoryRecord, startTime time.Time) error {
 	// 检查是否被取消
 	select {
 	case <-ctx.Done():
@@ -685,26 +1174,33 @@ func (a *App) processSinglePage(ctx context.Context, pageNum int, historyRecord
 	}
 
 	// 使用AI识别文字（带重试机制）
-	log.Printf("开始OCR识别页面 %d", pageNum)
-	result, err := a.ocrClient.RecognizeImage(ctx, imagePath)
+	logger.Infof("开始OCR识别页面 %d", pageNum)
+	result, err := a.recognizePageImage(ctx, imagePath, profileOCRModel(profile))
 	if err != nil {
-		log.Printf("页面 %d OCR识别失败: %v", pageNum, err)
+		logger.Errorf("页面 %d OCR识别失败: %v", pageNum, err)
 		return fmt.Errorf("OCR识别失败: %w", err)
 	}
-	log.Printf("页面 %d OCR识别成功", pageNum)
+	logger.Infof("页面 %d OCR识别成功", pageNum)
 
 	if result.Error != "" {
 		return fmt.Errorf("OCR识别错误: %s", result.Error)
 	}
 
-	// 更新页面OCR结果
+	// 按处理配置的规则后处理并更新页面OCR结果
+	result.Text = a.postProcessResult(doc, postprocess.TargetOCR, result.Text)
 	a.pdfProcessor.UpdatePageOCR(doc, pageNum, result.Text)
 
 	// 保存到缓存
-	if err := a.savePageToCache(pageNum, result.Text, ""); err != nil {
-		log.Printf("保存缓存失败: %v", err)
+	if err := a.savePageToCache(doc, pageNum, result.Text, "", revisions.SourceOCR); err != nil {
+		logger.Errorf("保存缓存失败: %v", err)
 	}
 
+	// 页面有原生文本时交叉检查识别结果
+	a.checkNativeTextAfterOCR(ctx, doc, pageNum, result.Text)
+
+	// 按配置提取页面关键词和主题
+	a.autoTagPage(ctx, doc, pageNum)
+
 	// 保存到历史记录
 	if historyRecord != nil {
 		page := &history.HistoryPage{
@@ -713,9 +1209,10 @@ func (a *App) processSinglePage(ctx context.Context, pageNum int, historyRecord
 			OriginalText:   doc.Pages[pageNum-1].Text,
 			OCRText:        result.Text,
 			ProcessingTime: time.Since(startTime).Seconds(),
+			RenderFallback: doc.Pages[pageNum-1].RenderFallback,
 		}
 		if err := a.historyManager.AddPage(page); err != nil {
-			log.Printf("保存历史记录失败: %v", err)
+			logger.Errorf("保存历史记录失败: %v", err)
 		}
 	}
 
@@ -723,9 +1220,9 @@ func (a *App) processSinglePage(ctx context.Context, pageNum int, historyRecord
 }
 
 // loadFromCache 从缓存加载文档
-func (a *App) loadFromCache(documentID string) error {
-	if a.currentDoc == nil {
-		return fmt.Errorf("当前文档为空")
+func (a *App) loadFromCache(doc *pdf.PDFDocument, documentID string) error {
+	if doc == nil {
+		return i18n.Errorf("doc.not_loaded")
 	}
 
 	// 获取缓存的页面
@@ -736,8 +1233,8 @@ func (a *App) loadFromCache(documentID string) error {
 
 	// 更新文档页面信息
 	for _, cachedPage := range pages {
-		if cachedPage.PageNumber > 0 && cachedPage.PageNumber <= len(a.currentDoc.Pages) {
-			page := a.currentDoc.Pages[cachedPage.PageNumber-1]
+		if cachedPage.PageNumber > 0 && cachedPage.PageNumber <= len(doc.Pages) {
+			page := doc.Pages[cachedPage.PageNumber-1]
 			if cachedPage.OCRText != "" {
 				page.OCRText = cachedPage.OCRText
 				page.Processed = true
@@ -755,13 +1252,38 @@ func (a *App) loadFromCache(documentID string) error {
 	return nil
 }
 
+// checkDocumentChanged 检查文档是否在缓存后被原地修改，是则通知前端可重新处理过期页面
+func (a *App) checkDocumentChanged(session *DocumentSession) {
+	if strings.HasPrefix(session.ID, "temp-") {
+		return
+	}
+
+	change, err := a.cacheManager.CheckDocument(session.Doc.FilePath, session.ID)
+	if err != nil {
+		logger.Warnf("检查文档变化失败: %v", err)
+		return
+	}
+	if change == nil {
+		return
+	}
+
+	logger.Infof("文档内容已变化，%d 页缓存已过期: %s", len(change.StalePages), change.FilePath)
+	a.emit("document-changed", map[string]interface{}{
+		"document_id":          change.DocumentID,
+		"previous_document_id": change.PreviousDocumentID,
+		"file_path":            change.FilePath,
+		"stale_pages":          change.StalePages,
+		"message":              i18n.T("doc.changed"),
+	})
+}
+
 // checkPageCache 检查页面缓存
-func (a *App) checkPageCache(pageNum int) *cache.CacheEntry {
-	if a.currentDoc == nil {
+func (a *App) checkPageCache(doc *pdf.PDFDocument, pageNum int) *cache.CacheEntry {
+	if doc == nil {
 		return nil
 	}
 
-	documentID, err := a.cacheManager.GenerateDocumentID(a.currentDoc.FilePath)
+	documentID, err := a.cacheManager.GenerateDocumentID(doc.FilePath)
 	if err != nil {
 		return nil
 	}
@@ -774,33 +1296,44 @@ func (a *App) checkPageCache(pageNum int) *cache.CacheEntry {
 	return cached
 }
 
+// createHistoryRecord 创建历史记录，并关联缓存使用的文档标识
+func (a *App) createHistoryRecord(doc *pdf.PDFDocument, pageCount int, taskType history.TaskType, model string) (*history.HistoryRecord, error) {
+	documentID, err := a.cacheManager.GenerateDocumentID(doc.FilePath)
+	if err != nil {
+		logger.Warnf("生成文档ID失败，历史记录将只按路径关联: %v", err)
+	}
+	return a.historyManager.CreateRecord(documentID, doc.FilePath, pageCount, taskType, model)
+}
+
 // savePageToCache 保存页面到缓存
-func (a *App) savePageToCache(pageNum int, ocrText, aiText string) error {
-	if a.currentDoc == nil {
-		return fmt.Errorf("当前文档为空")
+// source 为文本来源（revisions.Source*），不为空时为内容有变化的文本记录历史版本
+func (a *App) savePageToCache(doc *pdf.PDFDocument, pageNum int, ocrText, aiText, source string) error {
+	if doc == nil {
+		return i18n.Errorf("doc.not_loaded")
 	}
 
-	documentID, err := a.cacheManager.GenerateDocumentID(a.currentDoc.FilePath)
+	documentID, err := a.cacheManager.GenerateDocumentID(doc.FilePath)
 	if err != nil {
-		return fmt.Errorf("生成文档ID失败: %w", err)
+		return i18n.Errorf("doc.id_failed", err)
 	}
 
 	// 保存文档信息
 	docCache := &cache.DocumentCache{
 		ID:        documentID,
-		FilePath:  a.currentDoc.FilePath,
-		PageCount: a.currentDoc.PageCount,
-		Title:     a.currentDoc.Title,
-		Author:    a.currentDoc.Author,
+		FilePath:  doc.FilePath,
+		FileHash:  documentID,
+		PageCount: doc.PageCount,
+		Title:     doc.Title,
+		Author:    doc.Author,
 	}
 	if err := a.cacheManager.SaveDocument(docCache); err != nil {
-		log.Printf("保存文档缓存失败: %v", err)
+		logger.Errorf("保存文档缓存失败: %v", err)
 	}
 
 	// 保存页面信息
 	var originalText string
-	if pageNum > 0 && pageNum <= len(a.currentDoc.Pages) {
-		originalText = a.currentDoc.Pages[pageNum-1].Text
+	if pageNum > 0 && pageNum <= len(doc.Pages) {
+		originalText = doc.Pages[pageNum-1].Text
 	}
 
 	pageCache := &cache.CacheEntry{
@@ -811,35 +1344,59 @@ func (a *App) savePageToCache(pageNum int, ocrText, aiText string) error {
 		AIText:       aiText,
 	}
 
-	return a.cacheManager.SavePage(pageCache)
+	var previous *cache.CacheEntry
+	if source != "" {
+		if previous, err = a.cacheManager.GetPage(documentID, pageNum); err != nil {
+			logger.Warnf("读取页面缓存失败: %v", err)
+		}
+	}
+	if err := a.cacheManager.SavePage(pageCache); err != nil {
+		return err
+	}
+	if source != "" {
+		a.recordPageRevisions(documentID, pageNum, previous, ocrText, aiText, source)
+	}
+	return nil
+}
+
+// ProcessWithAI 使用AI处理文本（不支持上下文模式，保持向后兼容），返回可用于 CancelTask 的任务ID
+func (a *App) ProcessWithAI(pageNumbers []int, prompt string) string {
+	return a.startAI(pageNumbers, prompt, false)
 }
 
-// ProcessWithAI 使用AI处理文本（不支持上下文模式，保持向后兼容）
-func (a *App) ProcessWithAI(pageNumbers []int, prompt string) {
-	go a.processWithAI(pageNumbers, prompt, false)
+// ProcessWithAIContext 使用AI处理文本（支持上下文模式），返回可用于 CancelTask 的任务ID
+func (a *App) ProcessWithAIContext(pageNumbers []int, prompt string, contextMode bool) string {
+	return a.startAI(pageNumbers, prompt, contextMode)
 }
 
-// ProcessWithAIContext 使用AI处理文本（支持上下文模式）
-func (a *App) ProcessWithAIContext(pageNumbers []int, prompt string, contextMode bool) {
-	go a.processWithAI(pageNumbers, prompt, contextMode)
+// startAI 登记AI处理任务并在后台处理
+func (a *App) startAI(pageNumbers []int, prompt string, contextMode bool) string {
+	session := a.activeSession()
+	return a.runTask(TaskKindAI, session, pageNumbers, func(ctx context.Context) {
+		a.processWithAI(ctx, session, pageNumbers, prompt, contextMode)
+	})
 }
 
 // processWithAI AI处理文本
-func (a *App) processWithAI(pageNumbers []int, prompt string, contextMode bool) {
-	a.mu.RLock()
-	doc := a.currentDoc
-	a.mu.RUnlock()
+func (a *App) processWithAI(ctx context.Context, session *DocumentSession, pageNumbers []int, prompt string, contextMode bool) {
+	defer logger.RecoverPanic("processWithAI")
 
-	if doc == nil {
-		runtime.EventsEmit(a.ctx, "ai-processing-error", "未加载PDF文档")
+	if session == nil {
+		a.emitTask(ctx, "ai-processing-error", newEventError(ErrCodeDocumentNotLoaded, i18n.T("doc.not_loaded")))
 		return
 	}
+	doc := session.Doc
 
 	if a.ocrClient == nil {
-		runtime.EventsEmit(a.ctx, "ai-processing-error", "未配置AI服务")
+		a.emitTask(ctx, "ai-processing-error", newEventError(ErrCodeAINotConfigured, i18n.T("ai.not_configured")))
 		return
 	}
 
+	// 未指定提示词时使用处理配置的提示词模板
+	if prompt == "" {
+		prompt = profilePrompt(a.resolveProfile(doc.FilePath))
+	}
+
 	// 获取实际使用的AI文本处理模型名称
 	aiConfig := a.configManager.GetAIConfig()
 	actualAIModel := aiConfig.TextModel
@@ -851,37 +1408,42 @@ func (a *App) processWithAI(pageNumbers []int, prompt string, contextMode bool)
 		}
 	}
 
-	// 创建历史记录，使用实际的AI模型名称，添加AI前缀标识任务类型
-	historyRecord, err := a.historyManager.CreateRecord(doc.FilePath, len(pageNumbers), "AI-"+actualAIModel)
+	// 创建历史记录，使用实际的AI模型名称
+	historyRecord, err := a.createHistoryRecord(doc, len(pageNumbers), history.TaskTypeAI, actualAIModel)
 	if err != nil {
-		log.Printf("创建AI处理历史记录失败: %v", err)
+		logger.Errorf("创建AI处理历史记录失败: %v", err)
 	}
 
 	// 根据上下文模式选择处理方式
 	if contextMode && len(pageNumbers) == 1 {
 		// 上下文模式且单页处理：使用新的单页AI处理逻辑
 		pageNum := pageNumbers[0]
-		result := a.processPageAI(context.Background(), pageNum, prompt, doc, false, contextMode, historyRecord)
+		result := a.processPageAI(ctx, pageNum, prompt, "", doc, false, contextMode, historyRecord)
 
+		if result.Error != nil && ctx.Err() != nil {
+			a.cancelAITask(ctx, session, pageNumbers, historyRecord)
+			return
+		}
 		if result.Error != nil {
 			// 更新历史记录状态为失败
 			if historyRecord != nil {
 				a.historyManager.UpdateRecordStatus(historyRecord.ID, history.StatusFailed, fmt.Sprintf("AI处理失败: %v", result.Error))
 			}
-			runtime.EventsEmit(a.ctx, "ai-processing-error", fmt.Sprintf("AI处理失败: %v", result.Error))
+			a.emitPageError(ctx, "ai-processing-error", pageNum, i18n.T("ai.failed", result.Error), result.Error)
 			return
 		}
 
 		// 更新历史记录状态为完成
 		if historyRecord != nil {
-			a.historyManager.UpdateRecordStatus(historyRecord.ID, history.StatusCompleted, "")
+			a.historyManager.FinishRecord(historyRecord.ID, 1, 0, false)
 		}
 
 		// 发送结果
-		runtime.EventsEmit(a.ctx, "ai-processing-complete", map[string]interface{}{
-			"pages":  pageNumbers,
-			"prompt": prompt,
-			"result": result.Result,
+		a.emitTask(ctx, "ai-processing-complete", map[string]interface{}{
+			"document_id": session.ID,
+			"pages":       pageNumbers,
+			"prompt":      prompt,
+			"result":      result.Result,
 		})
 		return
 	}
@@ -898,21 +1460,27 @@ func (a *App) processWithAI(pageNumbers []int, prompt string, contextMode bool)
 			text = page.Text
 		}
 		if text != "" {
-			textBuilder.WriteString(fmt.Sprintf("=== 第 %d 页 ===\n%s\n\n", pageNum, text))
+			textBuilder.WriteString(i18n.T("prompt.page_header", pageNum) + "\n" + text + "\n\n")
 		}
 	}
 
 	if textBuilder.Len() == 0 {
-		runtime.EventsEmit(a.ctx, "ai-processing-error", "没有可处理的文本")
+		a.emitTask(ctx, "ai-processing-error", newEventError(ErrCodeNoText, i18n.T("text.none")))
 		return
 	}
 
-	// 使用AI处理
-	result, err := a.ocrClient.ProcessWithAI(context.Background(), textBuilder.String(), prompt)
+	// 使用AI处理，{{page}} 为合并处理的页码范围
+	prompt = expandPagesPromptVariables(prompt, doc, pageNumbers)
+	result, err := a.processTextWithGlossary(ctx, textBuilder.String(), prompt)
+	if err != nil && ctx.Err() != nil {
+		a.cancelAITask(ctx, session, pageNumbers, historyRecord)
+		return
+	}
 	if err != nil {
-		runtime.EventsEmit(a.ctx, "ai-processing-error", fmt.Sprintf("AI处理失败: %v", err))
+		a.emitTask(ctx, "ai-processing-error", newEventError(classifyError(err), i18n.T("ai.failed", err)))
 		return
 	}
+	result = a.postProcessResult(doc, postprocess.TargetAI, result)
 
 	// 更新页面AI处理结果并保存到缓存
 	for _, pageNum := range pageNumbers {
@@ -925,8 +1493,8 @@ func (a *App) processWithAI(pageNumbers []int, prompt string, contextMode bool)
 
 		// 保存到缓存（保持现有的OCR文本，只更新AI文本）
 		page := doc.Pages[pageNum-1]
-		if err := a.savePageToCache(pageNum, page.OCRText, result); err != nil {
-			log.Printf("保存AI处理结果到缓存失败: %v", err)
+		if err := a.savePageToCache(doc, pageNum, page.OCRText, result, revisions.SourceAI); err != nil {
+			logger.Errorf("保存AI处理结果到缓存失败: %v", err)
 		}
 
 		// 保存到历史记录
@@ -940,31 +1508,40 @@ func (a *App) processWithAI(pageNumbers []int, prompt string, contextMode bool)
 				ProcessingTime:  0, // 非批量处理暂时设为0
 			}
 			if err := a.historyManager.AddPage(historyPage); err != nil {
-				log.Printf("保存AI处理历史记录失败: %v", err)
-			} else {
-				log.Printf("AI处理历史记录保存成功: 页面%d", pageNum)
+				logger.Errorf("保存AI处理历史记录失败: %v", err)
 			}
 		}
 	}
 
 	// 更新历史记录状态
 	if historyRecord != nil {
-		a.historyManager.UpdateRecordStatus(historyRecord.ID, history.StatusCompleted, "")
+		a.historyManager.FinishRecord(historyRecord.ID, len(pageNumbers), 0, false)
 	}
 
 	// 发送结果
-	runtime.EventsEmit(a.ctx, "ai-processing-complete", map[string]interface{}{
-		"pages":  pageNumbers,
-		"prompt": prompt,
-		"result": result,
+	a.emitTask(ctx, "ai-processing-complete", map[string]interface{}{
+		"document_id": session.ID,
+		"pages":       pageNumbers,
+		"prompt":      prompt,
+		"result":      result,
+	})
+}
+
+// cancelAITask 记录AI处理任务被取消并通知前端
+func (a *App) cancelAITask(ctx context.Context, session *DocumentSession, pageNumbers []int, historyRecord *history.HistoryRecord) {
+	logger.Infof("AI处理被取消: 页面%v", pageNumbers)
+	if historyRecord != nil {
+		a.historyManager.UpdateRecordStatus(historyRecord.ID, history.StatusCancelled, i18n.T("status.cancelled"))
+	}
+	a.emitTask(ctx, "ai-processing-cancelled", map[string]interface{}{
+		"document_id": session.ID,
+		"pages":       pageNumbers,
 	})
 }
 
 // CheckAIProcessedPages 检查页面AI处理状态
 func (a *App) CheckAIProcessedPages(pageNumbers []int) map[string]interface{} {
-	a.mu.RLock()
-	doc := a.currentDoc
-	a.mu.RUnlock()
+	doc := a.activeDocument()
 
 	result := map[string]interface{}{
 		"total_pages":       len(pageNumbers),
@@ -1000,40 +1577,73 @@ func (a *App) CheckAIProcessedPages(pageNumbers []int) map[string]interface{} {
 	return result
 }
 
-// ProcessWithAIBatch 批量AI处理（每页单独处理）
-func (a *App) ProcessWithAIBatch(pageNumbers []int, prompt string) {
-	go a.processWithAIBatch(pageNumbers, prompt, false, false)
+// ProcessWithAIBatch 批量AI处理（每页单独处理），返回任务ID（文档正在处理时加入队列，返回队列任务ID）
+func (a *App) ProcessWithAIBatch(pageNumbers []int, prompt string) string {
+	return a.startOrQueueBatch(a.activeSession(), aiBatchRequest(pageNumbers, prompt, false, false))
 }
 
-// ProcessWithAIBatchForce 强制批量AI处理（忽略缓存）
-func (a *App) ProcessWithAIBatchForce(pageNumbers []int, prompt string) {
-	go a.processWithAIBatch(pageNumbers, prompt, true, false)
+// ProcessWithAIBatchForce 强制批量AI处理（忽略缓存），返回任务ID（文档正在处理时加入队列，返回队列任务ID）
+func (a *App) ProcessWithAIBatchForce(pageNumbers []int, prompt string) string {
+	return a.startOrQueueBatch(a.activeSession(), aiBatchRequest(pageNumbers, prompt, true, false))
 }
 
-// ProcessWithAIBatchContext 批量AI处理（支持上下文模式）
-func (a *App) ProcessWithAIBatchContext(pageNumbers []int, prompt string, contextMode bool) {
-	go a.processWithAIBatch(pageNumbers, prompt, false, contextMode)
+// ProcessWithAIBatchContext 批量AI处理（支持上下文模式），返回任务ID（文档正在处理时加入队列，返回队列任务ID）
+func (a *App) ProcessWithAIBatchContext(pageNumbers []int, prompt string, contextMode bool) string {
+	return a.startOrQueueBatch(a.activeSession(), aiBatchRequest(pageNumbers, prompt, false, contextMode))
 }
 
-// ProcessWithAIBatchForceContext 强制批量AI处理（支持上下文模式）
-func (a *App) ProcessWithAIBatchForceContext(pageNumbers []int, prompt string, contextMode bool) {
-	go a.processWithAIBatch(pageNumbers, prompt, true, contextMode)
+// ProcessWithAIBatchForceContext 强制批量AI处理（支持上下文模式），返回任务ID（文档正在处理时加入队列，返回队列任务ID）
+func (a *App) ProcessWithAIBatchForceContext(pageNumbers []int, prompt string, contextMode bool) string {
+	return a.startOrQueueBatch(a.activeSession(), aiBatchRequest(pageNumbers, prompt, true, contextMode))
 }
 
-// processWithAIBatch 批量AI处理实现
-func (a *App) processWithAIBatch(pageNumbers []int, prompt string, forceReprocess bool, contextMode bool) {
-	a.mu.RLock()
-	doc := a.currentDoc
-	a.mu.RUnlock()
+// ProcessWithAIBatchRolling 按页顺序批量AI处理，每页附带前面已处理页面结果的摘录，使术语和指代在全文保持一致（适合翻译、改写）
+// 返回任务ID（文档正在处理时加入队列，返回队列任务ID）
+func (a *App) ProcessWithAIBatchRolling(pageNumbers []int, prompt string, forceReprocess bool, contextMode bool) string {
+	req := aiBatchRequest(pageNumbers, prompt, forceReprocess, contextMode)
+	req.RollingContext = true
+	return a.startOrQueueBatch(a.activeSession(), req)
+}
 
-	if doc == nil {
-		runtime.EventsEmit(a.ctx, "processing-error", "未加载PDF文档")
-		return
+// startAIBatch 登记AI批量任务并在后台处理，返回任务ID
+// job不为空时表示恢复中断的任务，record 不为空时结果追加到该历史记录（重试失败页面）
+func (a *App) startAIBatch(session *DocumentSession, pageNumbers []int, prompt string, forceReprocess bool, contextMode bool, rollingContext bool, job *jobs.Job, record *history.HistoryRecord) string {
+	return a.runTask(TaskKindBatchAI, session, pageNumbers, func(ctx context.Context) {
+		a.reportBatchBusy(ctx, a.processWithAIBatch(ctx, session, pageNumbers, prompt, forceReprocess, contextMode, rollingContext, job, record))
+	})
+}
+
+// aiBatchRequest 创建AI批量处理请求
+func aiBatchRequest(pageNumbers []int, prompt string, forceReprocess bool, contextMode bool) QueueRequest {
+	return QueueRequest{
+		Pages:          pageNumbers,
+		TaskType:       string(jobs.TaskAI),
+		Prompt:         prompt,
+		ContextMode:    contextMode,
+		ForceReprocess: forceReprocess,
+	}
+}
+
+// processWithAIBatch 批量AI处理实现（阻塞直到处理结束），ctx 为任务的上下文
+// rollingContext 为 true 时逐页顺序处理，每页的提示词包含前面已处理页面结果的摘录
+// 与 processPagesBatch 相同，批次没有开始、被取消或全部页面失败时返回错误
+func (a *App) processWithAIBatch(ctx context.Context, session *DocumentSession, pageNumbers []int, prompt string, forceReprocess bool, contextMode bool, rollingContext bool, job *jobs.Job, record *history.HistoryRecord) error {
+	defer logger.RecoverPanic("processWithAIBatch")
+
+	if session == nil {
+		a.emitTask(ctx, "processing-error", newEventError(ErrCodeDocumentNotLoaded, i18n.T("doc.not_loaded")))
+		return i18n.Errorf("doc.not_loaded")
 	}
+	doc := session.Doc
 
 	if a.ocrClient == nil {
-		runtime.EventsEmit(a.ctx, "processing-error", "未配置AI服务")
-		return
+		a.emitTask(ctx, "processing-error", newEventError(ErrCodeAINotConfigured, i18n.T("ai.not_configured")))
+		return i18n.Errorf("ai.not_configured")
+	}
+
+	// 未指定提示词时使用处理配置的提示词模板
+	if prompt == "" {
+		prompt = profilePrompt(a.resolveProfile(doc.FilePath))
 	}
 
 	// 过滤有效页面
@@ -1048,9 +1658,18 @@ func (a *App) processWithAIBatch(pageNumbers []int, prompt string, forceReproces
 	}
 
 	if len(validPages) == 0 {
-		runtime.EventsEmit(a.ctx, "processing-error", "没有可处理的页面")
-		return
+		a.finishJob(job, jobs.StatusFailed, i18n.T("pages.none"))
+		a.emitTask(ctx, "processing-error", newEventError(ErrCodeNoPages, i18n.T("pages.none")))
+		return i18n.Errorf("pages.none")
+	}
+
+	// 设置处理状态，取消批次即取消任务；文档已有批次在运行时不开始，避免两个批次共用处理状态
+	if !session.beginBatch(taskIDFromContext(ctx), validPages, a.taskCanceller(ctx)) {
+		// 恢复的任务保持中断状态，之后还可以继续
+		a.finishJob(job, jobs.StatusInterrupted, i18n.T("batch.busy"))
+		return errBatchBusy
 	}
+	defer a.endSessionBatch(session)
 
 	// 获取实际使用的AI文本处理模型名称
 	aiConfig := a.configManager.GetAIConfig()
@@ -1063,49 +1682,64 @@ func (a *App) processWithAIBatch(pageNumbers []int, prompt string, forceReproces
 		}
 	}
 
-	// 创建历史记录，使用实际的AI模型名称，添加AI前缀标识任务类型
-	historyRecord, err := a.historyManager.CreateRecord(doc.FilePath, len(validPages), "AI-"+actualAIModel)
-	if err != nil {
-		log.Printf("创建AI处理历史记录失败: %v", err)
+	// 创建历史记录，使用实际的AI模型名称
+	historyRecord := record
+	if historyRecord == nil {
+		var err error
+		historyRecord, err = a.createHistoryRecord(doc, len(validPages), history.TaskTypeAI, actualAIModel)
+		if err != nil {
+			logger.Errorf("创建AI处理历史记录失败: %v", err)
+		}
+	} else {
+		a.reopenHistoryRecord(historyRecord)
 	}
 
-	// 创建上下文用于取消
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	// 持久化任务，以便应用异常退出后可以继续
+	if job == nil {
+		job = a.createJob(doc.FilePath, jobs.TaskAI, validPages, prompt, contextMode, rollingContext, forceReprocess, historyRecord)
+	}
+
+	tracker := a.startBatchTracking(session, string(jobs.TaskAI), actualAIModel, validPages, historyRecord)
 
-	// 设置处理状态
-	a.processingMu.Lock()
-	a.processingState = 1 // processing
-	a.processedInBatch = 0
-	a.processingMu.Unlock()
+	session.setHistoryRecord(historyRecord)
+	a.initHistoryPages(historyRecord, validPages)
 
 	// 并发处理AI任务
-	const maxConcurrency = 2 // AI处理并发数较低，避免API限制
-	pagesChan := make(chan int, len(validPages))
+	// AI处理并发数较低，避免API限制（处理配置可调整）
+	maxConcurrency := profileConcurrency(a.resolveProfile(doc.FilePath), 2)
+	var rolling *rollingContextWindow
+	if rollingContext {
+		// 滚动上下文依赖前一页的结果，只能顺序处理
+		maxConcurrency = 1
+		rolling = &rollingContextWindow{}
+		sort.Ints(validPages)
+	}
+	queue := session.startPageQueue(validPages)
 	resultsChan := make(chan AIProcessResult, len(validPages))
 
-	// 发送页面到通道
-	for _, pageNum := range validPages {
-		pagesChan <- pageNum
-	}
-	close(pagesChan)
-
 	// 启动工作协程
 	var wg sync.WaitGroup
 	for i := 0; i < maxConcurrency; i++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			for pageNum := range pagesChan {
-				// 检查取消状态
-				select {
-				case <-ctx.Done():
-					log.Printf("AI处理协程检测到取消信号，停止处理")
+			defer logger.RecoverPanic("page worker")
+			for pageNum, ok := queue.next(); ok; pageNum, ok = queue.next() {
+				// 暂停时等待继续，取消时停止处理
+				if !session.waitIfPaused(ctx) {
+					logger.Infof("AI处理协程检测到取消信号，停止处理")
 					return
-				default:
 				}
 
-				result := a.processPageAI(ctx, pageNum, prompt, doc, forceReprocess, contextMode, historyRecord)
+				a.setHistoryPageStatus(historyRecord, pageNum, history.PageProcessing, nil)
+				startedAt := time.Now()
+				result := a.processPageAI(ctx, pageNum, prompt, rolling.prompt(pageNum), doc, forceReprocess, contextMode, historyRecord)
+				result.Duration = time.Since(startedAt)
+				if result.Error != nil {
+					a.setHistoryPageStatus(historyRecord, pageNum, history.PageFailed, result.Error)
+				} else {
+					rolling.add(pageNum, result.Result)
+				}
 
 				select {
 				case <-ctx.Done():
@@ -1131,77 +1765,110 @@ func (a *App) processWithAIBatch(pageNumbers []int, prompt string, forceReproces
 		processed++
 
 		if result.Error != nil {
-			log.Printf("AI处理第%d页失败: %v", result.PageNumber, result.Error)
+			logger.Errorf("AI处理第%d页失败: %v", result.PageNumber, result.Error)
 			// 检查是否是取消导致的错误
 			if result.Error == context.Canceled || strings.Contains(result.Error.Error(), "context canceled") {
-				log.Printf("页面 %d AI处理被取消", result.PageNumber)
+				logger.Infof("页面 %d AI处理被取消", result.PageNumber)
 			} else {
-				runtime.EventsEmit(a.ctx, "processing-error", fmt.Sprintf("AI处理第%d页失败: %v", result.PageNumber, result.Error))
+				a.emitPageError(ctx, "processing-error", result.PageNumber, i18n.T("ai.page_failed", result.PageNumber, result.Error), result.Error)
 			}
 		} else {
 			successCount++
+			a.markJobPageDone(job, result.PageNumber)
 			// AI页面处理成功，立即发送单页完成事件以触发实时刷新
-			runtime.EventsEmit(a.ctx, "ai-page-processed", map[string]interface{}{
-				"pageNumber": result.PageNumber,
-				"status":     result.Status,
-				"result":     result.Result,
+			a.emitTask(ctx, "ai-page-processed", map[string]interface{}{
+				"document_id": session.ID,
+				"pageNumber":  result.PageNumber,
+				"status":      result.Status,
+				"result":      result.Result,
 			})
 		}
 
-		runtime.EventsEmit(a.ctx, "processing-progress", ProgressUpdate{
+		session.incrementProcessed()
+		a.emitTask(ctx, "processing-progress", a.pageProgress(tracker, ProgressUpdate{
+			DocumentID:  session.ID,
 			Total:       total,
 			Processed:   processed,
 			CurrentPage: result.PageNumber,
 			Status:      result.Status,
-		})
+		}, result.Duration))
 	}
 
-	// 重置处理状态
-	a.processingMu.Lock()
-	a.processingState = 0 // idle
-	a.processingMu.Unlock()
+	// 更新历史记录状态（部分页面失败时标记为 partial）
+	a.finishHistoryRecord(historyRecord, record != nil, successCount, processed-successCount, ctx.Err() != nil)
+	a.reportWriteFailures(session.ID)
 
-	// 更新历史记录状态
-	if historyRecord != nil {
+	// 更新任务状态
+	select {
+	case <-ctx.Done():
+		a.finishJob(job, jobs.StatusCancelled, "处理被用户取消")
+		a.finishBatchTracking(tracker, successCount, true, "处理被用户取消")
+	default:
+		a.finishBatchJob(job, successCount, processed-successCount)
 		if successCount > 0 {
-			a.historyManager.UpdateRecordStatus(historyRecord.ID, history.StatusCompleted, "")
+			a.finishBatchTracking(tracker, successCount, false, "")
 		} else {
-			a.historyManager.UpdateRecordStatus(historyRecord.ID, history.StatusFailed, "所有页面处理失败")
+			a.finishBatchTracking(tracker, successCount, false, "所有页面处理失败")
 		}
 	}
 
 	// 发送完成事件
 	if successCount > 0 {
-		runtime.EventsEmit(a.ctx, "ai-processing-complete", map[string]interface{}{
+		a.emitTask(ctx, "ai-processing-complete", map[string]interface{}{
+			"document_id":  session.ID,
 			"pages":        validPages,
 			"prompt":       prompt,
 			"successCount": successCount,
 			"totalCount":   total,
 		})
 	}
+
+	switch {
+	case ctx.Err() != nil:
+		return ctx.Err()
+	case successCount == 0 && processed > 0:
+		return i18n.Errorf("batch.all_failed")
+	}
+	return nil
 }
 
-// processPageAI 处理单个页面的AI任务
-func (a *App) processPageAI(ctx context.Context, pageNum int, prompt string, doc *pdf.PDFDocument, forceReprocess bool, contextMode bool, historyRecord *history.HistoryRecord) AIProcessResult {
+// processPageAI 处理单个页面的AI任务，rollingPrompt 为滚动上下文的前文摘录，放在页面上下文之前
+func (a *App) processPageAI(ctx context.Context, pageNum int, prompt string, rollingPrompt string, doc *pdf.PDFDocument, forceReprocess bool, contextMode bool, historyRecord *history.HistoryRecord) AIProcessResult {
 	startTime := time.Now()
 	result := AIProcessResult{
 		PageNumber: pageNum,
-		Status:     fmt.Sprintf("正在AI处理第%d页", pageNum),
+		Status:     i18n.T("status.ai_processing", pageNum),
 	}
 
 	// 检查页面范围
 	if pageNum < 1 || pageNum > len(doc.Pages) {
-		result.Error = fmt.Errorf("页码超出范围")
+		result.Error = i18n.Errorf("page.out_of_range")
 		return result
 	}
 
 	page := doc.Pages[pageNum-1]
+	prompt = expandPromptVariables(prompt, doc, pageNum)
 
 	// 获取上下文内容（包含当前页面和前后页面的内容）
-	currentPageText, _, _, contextPrompt := a.collectContextContent(doc, pageNum, contextMode)
+	var neighborBudget int
+	if contextMode {
+		pageText := page.OCRText
+		if pageText == "" {
+			pageText = page.Text
+		}
+		neighborBudget = a.contextNeighborBudget(pageText, rollingPrompt+prompt)
+	}
+
+	// 启用章节上下文时，章节摘要最多使用相邻页面长度的一半
+	var chapterPrompt string
+	if contextMode {
+		chapterPrompt = a.chapterContextPrompt(ctx, doc, pageNum, neighborBudget/2)
+		neighborBudget -= ocr.EstimateTokens(chapterPrompt)
+	}
+	currentPageText, _, _, contextPrompt := a.collectContextContent(doc, pageNum, contextMode, neighborBudget)
 
 	if currentPageText == "" {
-		result.Error = fmt.Errorf("页面没有可处理的文本")
+		result.Error = i18n.Errorf("page.no_text")
 		return result
 	}
 
@@ -1211,35 +1878,36 @@ func (a *App) processPageAI(ctx context.Context, pageNum int, prompt string, doc
 	if contextMode {
 		// 上下文模式：只发送当前页内容给AI，但在提示词中包含上下文信息
 		processText = currentPageText
-		finalPrompt = contextPrompt + "【重要提示】请严格按照以下指令处理上述第" + fmt.Sprintf("%d", pageNum) + "页的内容，不要包含其他页面的内容：\n\n" + prompt
+		finalPrompt = chapterPrompt + rollingPrompt + contextPrompt + i18n.T("prompt.instruction", pageNum) + "\n\n" + prompt
 	} else {
 		// 普通模式：只使用当前页面内容
 		processText = currentPageText
-		finalPrompt = prompt
+		finalPrompt = rollingPrompt + prompt
 	}
 
 	// 检查缓存（只有在强制重新处理时才跳过缓存）
 	// 注意：单页AI处理通常是用户主动触发的，可能使用不同的提示词或上下文模式
 	// 因此我们不使用缓存，总是进行新的AI处理
 	if !forceReprocess && page.AIText != "" {
-		log.Printf("第%d页已有AI处理结果，但单页AI处理总是使用新的提示词，跳过缓存")
+		logger.Warnf("第%d页已有AI处理结果，但单页AI处理总是使用新的提示词，跳过缓存", pageNum)
 	}
 
-	log.Printf("开始AI处理第%d页", pageNum)
+	logger.Infof("开始AI处理第%d页", pageNum)
 
 	// 使用AI处理（使用上下文内容）
-	aiResult, err := a.ocrClient.ProcessWithAI(ctx, processText, finalPrompt)
+	aiResult, err := a.processTextWithGlossary(ctx, processText, finalPrompt)
 	if err != nil {
-		result.Error = fmt.Errorf("AI处理失败: %w", err)
+		result.Error = i18n.Errorf("ai.failed_err", err)
 		return result
 	}
+	aiResult = a.postProcessResult(doc, postprocess.TargetAI, aiResult)
 
 	// 更新页面AI处理结果
 	a.pdfProcessor.UpdatePageAI(doc, pageNum, aiResult)
 
 	// 保存到缓存
-	if err := a.savePageToCache(pageNum, page.OCRText, aiResult); err != nil {
-		log.Printf("保存AI处理结果到缓存失败: %v", err)
+	if err := a.savePageToCache(doc, pageNum, page.OCRText, aiResult, revisions.SourceAI); err != nil {
+		logger.Errorf("保存AI处理结果到缓存失败: %v", err)
 	}
 
 	// 保存到历史记录
@@ -1251,129 +1919,115 @@ func (a *App) processPageAI(ctx context.Context, pageNum int, prompt string, doc
 			OCRText:         page.OCRText,
 			AIProcessedText: aiResult,
 			ProcessingTime:  time.Since(startTime).Seconds(),
+			RenderFallback:  page.RenderFallback,
 		}
 		if err := a.historyManager.AddPage(historyPage); err != nil {
-			log.Printf("保存AI处理历史记录失败: %v", err)
-		} else {
-			log.Printf("AI处理历史记录保存成功: 页面%d", pageNum)
+			logger.Errorf("保存AI处理历史记录失败: %v", err)
 		}
 	}
 
 	result.Result = aiResult
-	result.Status = fmt.Sprintf("第%d页AI处理完成", pageNum)
+	result.Status = i18n.T("status.ai_done", pageNum)
 
-	log.Printf("第%d页AI处理完成", pageNum)
+	logger.Infof("第%d页AI处理完成", pageNum)
 	return result
 }
 
 // collectContextContent 收集上下文内容（当前页面的前后页面内容）
-func (a *App) collectContextContent(doc *pdf.PDFDocument, currentPageNum int, contextMode bool) (string, string, string, string) {
+// neighborBudget 为前后页面可使用的总长度（tokens），按模型上下文长度计算：
+// 长上下文模型可包含多个相邻页面，短上下文模型只截取相邻页面靠近当前页的部分
+func (a *App) collectContextContent(doc *pdf.PDFDocument, currentPageNum int, contextMode bool, neighborBudget int) (string, string, string, string) {
+	pageText := func(pageNum int) string {
+		page := doc.Pages[pageNum-1]
+		text := page.OCRText
+		if text == "" {
+			text = page.Text
+		}
+		return text
+	}
+
+	if currentPageNum < 1 || currentPageNum > len(doc.Pages) {
+		return "", "", "", ""
+	}
+	currentPageText := pageText(currentPageNum)
+
 	// 检查是否启用上下文模式
 	if !contextMode {
 		// 未启用上下文模式，只返回当前页面内容
-		if currentPageNum >= 1 && currentPageNum <= len(doc.Pages) {
-			page := doc.Pages[currentPageNum-1]
-			text := page.OCRText
-			if text == "" {
-				text = page.Text
-			}
-			return text, "", "", ""
-		}
-		return "", "", "", ""
+		return currentPageText, "", "", ""
 	}
 
-	// 启用上下文模式，分别收集上一页、当前页、下一页内容
-	log.Printf("启用上下文模式，为第%d页收集上下文内容", currentPageNum)
+	// 启用上下文模式，前后两侧各使用一半长度，由近及远收集相邻页面
+	logger.Infof("启用上下文模式，为第%d页收集上下文内容（相邻页面可用约 %d tokens）", currentPageNum, neighborBudget)
 
-	var prevPageText, currentPageText, nextPageText string
+	var prevSections, nextSections []string
+	var prevPageText, nextPageText strings.Builder
 
-	// 获取上一页内容
-	if currentPageNum > 1 && currentPageNum-1 <= len(doc.Pages) {
-		page := doc.Pages[currentPageNum-2]
-		text := page.OCRText
+	remaining := neighborBudget / 2
+	for pageNum := currentPageNum - 1; pageNum >= 1 && pageNum >= currentPageNum-maxContextNeighborPages && remaining > 0; pageNum-- {
+		text := strings.TrimSpace(pageText(pageNum))
 		if text == "" {
-			text = page.Text
-		}
-		if strings.TrimSpace(text) != "" {
-			prevPageText = text
+			continue
 		}
-	}
-
-	// 获取当前页内容
-	if currentPageNum >= 1 && currentPageNum <= len(doc.Pages) {
-		page := doc.Pages[currentPageNum-1]
-		text := page.OCRText
-		if text == "" {
-			text = page.Text
+		label := i18n.T("page.label", pageNum)
+		if tokens := ocr.EstimateTokens(text); tokens > remaining {
+			// 上文取靠近当前页的末尾部分
+			text = ocr.TailTokens(text, remaining)
+			label += i18n.T("prompt.tail_excerpt")
 		}
-		currentPageText = text
+		remaining -= ocr.EstimateTokens(text)
+		prevSections = append([]string{i18n.T("prompt.previous_page", label) + "\n" + text + "\n\n"}, prevSections...)
+		prevPageText.WriteString(text)
 	}
 
-	// 获取下一页内容
-	if currentPageNum < len(doc.Pages) {
-		page := doc.Pages[currentPageNum]
-		text := page.OCRText
+	remaining = neighborBudget / 2
+	for pageNum := currentPageNum + 1; pageNum <= len(doc.Pages) && pageNum <= currentPageNum+maxContextNeighborPages && remaining > 0; pageNum++ {
+		text := strings.TrimSpace(pageText(pageNum))
 		if text == "" {
-			text = page.Text
+			continue
 		}
-		if strings.TrimSpace(text) != "" {
-			nextPageText = text
+		label := i18n.T("page.label", pageNum)
+		if tokens := ocr.EstimateTokens(text); tokens > remaining {
+			// 下文取靠近当前页的开头部分
+			text = ocr.HeadTokens(text, remaining)
+			label += i18n.T("prompt.head_excerpt")
 		}
+		remaining -= ocr.EstimateTokens(text)
+		nextSections = append(nextSections, i18n.T("prompt.next_page", label)+"\n"+text+"\n\n")
+		nextPageText.WriteString(text)
 	}
 
 	// 构建上下文提示
 	var contextPrompt strings.Builder
-	contextPrompt.WriteString("【上下文信息】\n")
+	contextPrompt.WriteString(i18n.T("prompt.context_header") + "\n")
 
-	if prevPageText != "" {
-		contextPrompt.WriteString(fmt.Sprintf("上一页（第%d页）内容：\n%s\n\n", currentPageNum-1, prevPageText))
-	} else {
-		if currentPageNum == 1 {
-			contextPrompt.WriteString("上一页：无（当前是第一页）\n\n")
-		} else {
-			contextPrompt.WriteString("上一页：无内容或为空页\n\n")
-		}
+	switch {
+	case len(prevSections) > 0:
+		contextPrompt.WriteString(strings.Join(prevSections, ""))
+	case currentPageNum == 1:
+		contextPrompt.WriteString(i18n.T("prompt.no_previous_first") + "\n\n")
+	default:
+		contextPrompt.WriteString(i18n.T("prompt.no_previous") + "\n\n")
 	}
 
-	contextPrompt.WriteString(fmt.Sprintf("当前页（第%d页）内容：\n%s\n\n", currentPageNum, currentPageText))
+	contextPrompt.WriteString(i18n.T("prompt.current_page", currentPageNum) + "\n" + currentPageText + "\n\n")
 
-	if nextPageText != "" {
-		contextPrompt.WriteString(fmt.Sprintf("下一页（第%d页）内容：\n%s\n\n", currentPageNum+1, nextPageText))
-	} else {
-		if currentPageNum == len(doc.Pages) {
-			contextPrompt.WriteString("下一页：无（当前是最后一页）\n\n")
-		} else {
-			contextPrompt.WriteString("下一页：无内容或为空页\n\n")
-		}
+	switch {
+	case len(nextSections) > 0:
+		contextPrompt.WriteString(strings.Join(nextSections, ""))
+	case currentPageNum == len(doc.Pages):
+		contextPrompt.WriteString(i18n.T("prompt.no_next_last") + "\n\n")
+	default:
+		contextPrompt.WriteString(i18n.T("prompt.no_next") + "\n\n")
 	}
 
-	contextPrompt.WriteString("【处理要求】\n")
-	contextPrompt.WriteString(fmt.Sprintf("请根据上述上下文信息处理第%d页的内容。\n", currentPageNum))
-	contextPrompt.WriteString("⚠️ 重要限制：\n")
-	contextPrompt.WriteString("1. 只处理和输出第" + fmt.Sprintf("%d", currentPageNum) + "页的内容\n")
-	contextPrompt.WriteString("2. 上一页和下一页的内容仅作为理解上下文的参考，不要在输出中包含它们的内容\n")
-	contextPrompt.WriteString("3. 如果当前页内容与前后页有连续性，可以适当提及相关背景，但主体内容必须是当前页\n")
-	contextPrompt.WriteString("4. 严格按照页面边界进行处理，避免跨页面混合内容\n\n")
+	contextPrompt.WriteString(i18n.T("prompt.context_rules", currentPageNum, currentPageNum) + "\n\n")
 
-	log.Printf("为第%d页收集的上下文内容长度: %d", currentPageNum, len(contextPrompt.String()))
-
-	// 调试日志：输出收集到的内容
-	log.Printf("=== 调试信息：第%d页上下文收集 ===", currentPageNum)
-	log.Printf("上一页内容长度: %d", len(prevPageText))
-	if prevPageText != "" {
-		log.Printf("上一页内容前100字符: %s", truncateString(prevPageText, 100))
-	}
-	log.Printf("当前页内容长度: %d", len(currentPageText))
-	if currentPageText != "" {
-		log.Printf("当前页内容前100字符: %s", truncateString(currentPageText, 100))
-	}
-	log.Printf("下一页内容长度: %d", len(nextPageText))
-	if nextPageText != "" {
-		log.Printf("下一页内容前100字符: %s", truncateString(nextPageText, 100))
-	}
-	log.Printf("=== 调试信息结束 ===")
+	logger.Infof("为第%d页收集的上下文内容长度: %d", currentPageNum, len(contextPrompt.String()))
+	logger.Debugf("第%d页上下文: 上文 %d 页 (%d 字符), 下文 %d 页 (%d 字符)",
+		currentPageNum, len(prevSections), prevPageText.Len(), len(nextSections), nextPageText.Len())
 
-	return currentPageText, prevPageText, nextPageText, contextPrompt.String()
+	return currentPageText, prevPageText.String(), nextPageText.String(), contextPrompt.String()
 }
 
 // truncateString 截断字符串到指定长度
@@ -1386,14 +2040,18 @@ func truncateString(s string, maxLen int) string {
 
 // ExportText 导出文本
 func (a *App) ExportText(pageNumbers []int, format string) (string, error) {
-	a.mu.RLock()
-	doc := a.currentDoc
-	a.mu.RUnlock()
+	doc := a.activeDocument()
 
 	if doc == nil {
-		return "", fmt.Errorf("未加载PDF文档")
+		return "", i18n.Errorf("doc.not_loaded")
 	}
 
+	text := formatDocumentText(doc, pageNumbers, format, false, a.exportApprovedOnly())
+	return convertChinese(text, a.configManager.GetConfig().ChineseConversion.Export), nil
+}
+
+// formatDocumentText 按格式拼接页面文本，preferAI为true时优先使用AI处理结果，approvedOnly为true时只包含审核通过的页面
+func formatDocumentText(doc *pdf.PDFDocument, pageNumbers []int, format string, preferAI, approvedOnly bool) string {
 	var builder strings.Builder
 
 	for _, pageNum := range pageNumbers {
@@ -1402,7 +2060,13 @@ func (a *App) ExportText(pageNumbers []int, format string) (string, error) {
 		}
 
 		page := doc.Pages[pageNum-1]
+		if !exportsPage(page, approvedOnly) {
+			continue
+		}
 		text := page.OCRText
+		if preferAI && page.AIText != "" {
+			text = page.AIText
+		}
 		if text == "" {
 			text = page.Text
 		}
@@ -1413,26 +2077,24 @@ func (a *App) ExportText(pageNumbers []int, format string) (string, error) {
 		if text != "" {
 			switch format {
 			case "markdown":
-				builder.WriteString(fmt.Sprintf("## 第 %d 页\n\n%s\n\n", pageNum, text))
+				builder.WriteString(fmt.Sprintf("## %s\n\n%s\n\n", i18n.T("export.page", pageNum), text))
 			case "html":
-				builder.WriteString(fmt.Sprintf("<h2>第 %d 页</h2>\n<p>%s</p>\n\n", pageNum, text))
+				builder.WriteString(fmt.Sprintf("<h2>%s</h2>\n<p>%s</p>\n\n", i18n.T("export.page", pageNum), text))
 			default: // txt
-				builder.WriteString(fmt.Sprintf("=== 第 %d 页 ===\n%s\n\n", pageNum, text))
+				builder.WriteString(fmt.Sprintf("=== %s ===\n%s\n\n", i18n.T("export.page", pageNum), text))
 			}
 		}
 	}
 
-	return builder.String(), nil
+	return builder.String()
 }
 
 // ExportProcessingResults 导出批量处理结果
 func (a *App) ExportProcessingResults(format string) (string, error) {
-	a.mu.RLock()
-	doc := a.currentDoc
-	a.mu.RUnlock()
+	doc := a.activeDocument()
 
 	if doc == nil {
-		return "", fmt.Errorf("未加载PDF文档")
+		return "", i18n.Errorf("doc.not_loaded")
 	}
 
 	var builder strings.Builder
@@ -1441,70 +2103,134 @@ func (a *App) ExportProcessingResults(format string) (string, error) {
 	// 添加文档信息头部
 	switch format {
 	case "markdown":
-		builder.WriteString(fmt.Sprintf("# %s - 处理结果\n\n", doc.Title))
-		builder.WriteString(fmt.Sprintf("**文件路径:** %s\n\n", doc.FilePath))
-		builder.WriteString(fmt.Sprintf("**总页数:** %d\n\n", doc.PageCount))
+		builder.WriteString(fmt.Sprintf("# %s\n\n", i18n.T("export.results_title", doc.Title)))
+		builder.WriteString(fmt.Sprintf("**%s:** %s\n\n", i18n.T("export.file_path"), doc.FilePath))
+		builder.WriteString(fmt.Sprintf("**%s:** %d\n\n", i18n.T("export.total_pages"), doc.PageCount))
 		builder.WriteString("---\n\n")
 	case "html":
-		builder.WriteString(fmt.Sprintf("<h1>%s - 处理结果</h1>\n", doc.Title))
-		builder.WriteString(fmt.Sprintf("<p><strong>文件路径:</strong> %s</p>\n", doc.FilePath))
-		builder.WriteString(fmt.Sprintf("<p><strong>总页数:</strong> %d</p>\n", doc.PageCount))
+		builder.WriteString(fmt.Sprintf("<h1>%s</h1>\n", i18n.T("export.results_title", doc.Title)))
+		builder.WriteString(fmt.Sprintf("<p><strong>%s:</strong> %s</p>\n", i18n.T("export.file_path"), doc.FilePath))
+		builder.WriteString(fmt.Sprintf("<p><strong>%s:</strong> %d</p>\n", i18n.T("export.total_pages"), doc.PageCount))
 		builder.WriteString("<hr>\n")
 	case "rtf":
 		builder.WriteString("{\\rtf1\\ansi\\ansicpg936\\deff0\\deflang2052\n")
 		builder.WriteString("{\\fonttbl{\\f0\\fswiss\\fcharset134 Microsoft YaHei;}{\\f1\\fmodern\\fcharset0 Courier New;}}\n")
 		builder.WriteString("{\\colortbl;\\red0\\green0\\blue0;\\red0\\green0\\blue255;}\n")
-		builder.WriteString(fmt.Sprintf("\\viewkind4\\uc1\\pard\\cf1\\lang2052\\f0\\fs28\\b %s - 处理结果\\par\n", doc.Title))
+		builder.WriteString(fmt.Sprintf("\\viewkind4\\uc1\\pard\\cf1\\lang2052\\f0\\fs28\\b %s\\par\n", i18n.T("export.results_title", doc.Title)))
 		builder.WriteString("\\par\n")
-		builder.WriteString(fmt.Sprintf("\\cf0\\fs22\\b0\\f1 文件路径: %s\\par\n", doc.FilePath))
-		builder.WriteString(fmt.Sprintf("总页数: %d\\par\n", doc.PageCount))
+		builder.WriteString(fmt.Sprintf("\\cf0\\fs22\\b0\\f1 %s: %s\\par\n", i18n.T("export.file_path"), doc.FilePath))
+		builder.WriteString(fmt.Sprintf("%s: %d\\par\n", i18n.T("export.total_pages"), doc.PageCount))
 		builder.WriteString("\\par\n")
 	default: // txt
-		builder.WriteString(fmt.Sprintf("%s - 处理结果\n", doc.Title))
-		builder.WriteString(fmt.Sprintf("文件路径: %s\n", doc.FilePath))
-		builder.WriteString(fmt.Sprintf("总页数: %d\n", doc.PageCount))
+		builder.WriteString(i18n.T("export.results_title", doc.Title) + "\n")
+		builder.WriteString(fmt.Sprintf("%s: %s\n", i18n.T("export.file_path"), doc.FilePath))
+		builder.WriteString(fmt.Sprintf("%s: %d\n", i18n.T("export.total_pages"), doc.PageCount))
 		builder.WriteString("=" + strings.Repeat("=", 50) + "\n\n")
 	}
 
-	// 导出所有已处理的页面
+	// 收集所有已处理页面的文本
+	approvedOnly := a.exportApprovedOnly()
+	var pageNums []int
+	var texts, textTypes []string
 	for i, page := range doc.Pages {
-		if !page.Processed {
+		if !page.Processed || !exportsPage(page, approvedOnly) {
 			continue
 		}
-
-		pageNum := i + 1
 		processedCount++
 
 		// 优先使用 OCR 结果，其次是 AI 结果，最后是原生文本
-		text := page.OCRText
+		text, textType := page.OCRText, "ocr"
 		if text == "" && page.AIText != "" {
-			text = page.AIText
+			text, textType = page.AIText, "ai"
 		}
 		if text == "" && page.Text != "" {
-			text = page.Text
+			text, textType = page.Text, "original"
 		}
-
 		if text != "" {
-			switch format {
-			case "markdown":
-				builder.WriteString(fmt.Sprintf("## 第 %d 页\n\n", pageNum))
-				builder.WriteString(fmt.Sprintf("%s\n\n", text))
-			case "html":
-				builder.WriteString(fmt.Sprintf("<h2>第 %d 页</h2>\n", pageNum))
-				builder.WriteString(fmt.Sprintf("<div class=\"page-content\">%s</div>\n\n",
-					strings.ReplaceAll(text, "\n", "<br>\n")))
-			case "rtf":
-				builder.WriteString(fmt.Sprintf("\\par\\b 第 %d 页\\b0\\par\\par", pageNum))
-				// 转义RTF特殊字符
-				rtfText := strings.ReplaceAll(text, "\\", "\\\\")
-				rtfText = strings.ReplaceAll(rtfText, "{", "\\{")
-				rtfText = strings.ReplaceAll(rtfText, "}", "\\}")
-				rtfText = strings.ReplaceAll(rtfText, "\n", "\\par\n")
-				builder.WriteString(fmt.Sprintf("%s\\par\\par", rtfText))
-			default: // txt
-				builder.WriteString(fmt.Sprintf("=== 第 %d 页 ===\n", pageNum))
-				builder.WriteString(fmt.Sprintf("%s\n\n", text))
+			pageNums = append(pageNums, i+1)
+			texts = append(texts, text)
+			textTypes = append(textTypes, textType)
+		}
+	}
+
+	texts = a.convertExportTexts(a.resolveFootnotes(a.annotateTexts(pageNums, texts, textTypes)))
+
+	// Markdown和HTML按检测到的章节生成目录，没有检测到标题时按页输出
+	var sections outline.Result
+	if format == "markdown" || format == "html" {
+		sections = outline.Analyze(texts, format, 2)
+		texts = sections.Pages
+	}
+	hasOutline := len(sections.Headings) > 0
+	if hasOutline {
+		switch format {
+		case "markdown":
+			builder.WriteString(fmt.Sprintf("## %s\n\n", i18n.T("export.toc")))
+			builder.WriteString(sections.MarkdownTOC + "\n")
+		case "html":
+			builder.WriteString(fmt.Sprintf("<h2>%s</h2>\n", i18n.T("export.toc")))
+			builder.WriteString(sections.HTMLTOC)
+		}
+	}
+
+	// 导出所有已处理的页面
+	for j, text := range texts {
+		pageNum := pageNums[j]
+		switch format {
+		case "markdown":
+			if hasOutline {
+				builder.WriteString(fmt.Sprintf("<!-- %s -->\n\n", i18n.T("export.page", pageNum)))
+			} else {
+				builder.WriteString(fmt.Sprintf("## %s\n\n", i18n.T("export.page", pageNum)))
+			}
+			builder.WriteString(fmt.Sprintf("%s\n\n", text))
+		case "html":
+			if !hasOutline {
+				builder.WriteString(fmt.Sprintf("<h2>%s</h2>\n", i18n.T("export.page", pageNum)))
+			}
+			builder.WriteString(fmt.Sprintf("<div class=\"page-content\" data-page=\"%d\">%s</div>\n\n",
+				pageNum, htmlPageContent(text)))
+		case "rtf":
+			builder.WriteString(fmt.Sprintf("\\par\\b %s\\b0\\par\\par", i18n.T("export.page", pageNum)))
+			// 转义RTF特殊字符
+			rtfText := strings.ReplaceAll(text, "\\", "\\\\")
+			rtfText = strings.ReplaceAll(rtfText, "{", "\\{")
+			rtfText = strings.ReplaceAll(rtfText, "}", "\\}")
+			rtfText = strings.ReplaceAll(rtfText, "\n", "\\par\n")
+			builder.WriteString(fmt.Sprintf("%s\\par\\par", rtfText))
+		default: // txt
+			builder.WriteString(fmt.Sprintf("=== %s ===\n", i18n.T("export.page", pageNum)))
+			builder.WriteString(fmt.Sprintf("%s\n\n", text))
+		}
+	}
+
+	// 添加主题索引（页面已提取主题时）
+	if index := a.topicIndex(doc); len(index) > 0 {
+		switch format {
+		case "markdown":
+			builder.WriteString(fmt.Sprintf("## %s\n\n", i18n.T("export.topic_index")))
+			for _, entry := range index {
+				builder.WriteString(fmt.Sprintf("- **%s**%s\n", entry.Tag, i18n.T("export.topic_pages", formatPages(entry.Pages))))
 			}
+			builder.WriteString("\n")
+		case "html":
+			builder.WriteString(fmt.Sprintf("<h2>%s</h2>\n<ul>\n", i18n.T("export.topic_index")))
+			for _, entry := range index {
+				builder.WriteString(fmt.Sprintf("<li><strong>%s</strong>%s</li>\n", html.EscapeString(entry.Tag), i18n.T("export.topic_pages", formatPages(entry.Pages))))
+			}
+			builder.WriteString("</ul>\n")
+		case "rtf":
+			builder.WriteString(fmt.Sprintf("\\par\\b %s\\b0\\par\\par", i18n.T("export.topic_index")))
+			for _, entry := range index {
+				tag := strings.NewReplacer("\\", "\\\\", "{", "\\{", "}", "\\}").Replace(entry.Tag)
+				builder.WriteString(fmt.Sprintf("%s%s\\par\n", tag, i18n.T("export.topic_pages", formatPages(entry.Pages))))
+			}
+		default: // txt
+			builder.WriteString(fmt.Sprintf("=== %s ===\n", i18n.T("export.topic_index")))
+			for _, entry := range index {
+				builder.WriteString(fmt.Sprintf("%s%s\n", entry.Tag, i18n.T("export.topic_pages", formatPages(entry.Pages))))
+			}
+			builder.WriteString("\n")
 		}
 	}
 
@@ -1512,21 +2238,21 @@ func (a *App) ExportProcessingResults(format string) (string, error) {
 	switch format {
 	case "markdown":
 		builder.WriteString("---\n\n")
-		builder.WriteString(fmt.Sprintf("**处理统计:** 共处理 %d 页，总计 %d 页\n", processedCount, doc.PageCount))
+		builder.WriteString(fmt.Sprintf("**%s:** %s\n", i18n.T("export.stats_label"), i18n.T("export.stats", processedCount, doc.PageCount)))
 	case "html":
 		builder.WriteString("<hr>\n")
-		builder.WriteString(fmt.Sprintf("<p><strong>处理统计:</strong> 共处理 %d 页，总计 %d 页</p>\n", processedCount, doc.PageCount))
+		builder.WriteString(fmt.Sprintf("<p><strong>%s:</strong> %s</p>\n", i18n.T("export.stats_label"), i18n.T("export.stats", processedCount, doc.PageCount)))
 	case "rtf":
 		builder.WriteString("\\par\\par")
-		builder.WriteString(fmt.Sprintf("\\b 处理统计:\\b0 共处理 %d 页，总计 %d 页\\par", processedCount, doc.PageCount))
+		builder.WriteString(fmt.Sprintf("\\b %s:\\b0 %s\\par", i18n.T("export.stats_label"), i18n.T("export.stats", processedCount, doc.PageCount)))
 		builder.WriteString("}")
 	default: // txt
 		builder.WriteString(strings.Repeat("=", 50) + "\n")
-		builder.WriteString(fmt.Sprintf("处理统计: 共处理 %d 页，总计 %d 页\n", processedCount, doc.PageCount))
+		builder.WriteString(fmt.Sprintf("%s: %s\n", i18n.T("export.stats_label"), i18n.T("export.stats", processedCount, doc.PageCount)))
 	}
 
 	if processedCount == 0 {
-		return "", fmt.Errorf("没有已处理的页面可以导出")
+		return "", i18n.Errorf("export.no_pages")
 	}
 
 	return builder.String(), nil
@@ -1552,7 +2278,7 @@ func (a *App) SaveFileWithDialog(content string, defaultFilename string, filters
 	// 保存文件
 	err = ioutil.WriteFile(filePath, []byte(content), 0644)
 	if err != nil {
-		return "", fmt.Errorf("保存文件失败: %w", err)
+		return "", i18n.Errorf("file.save_failed", err)
 	}
 
 	return filePath, nil
@@ -1584,7 +2310,7 @@ func (a *App) SaveBinaryFileWithDialog(base64Content string, defaultFilename str
 	// 保存二进制文件
 	err = ioutil.WriteFile(filePath, data, 0644)
 	if err != nil {
-		return "", fmt.Errorf("保存文件失败: %w", err)
+		return "", i18n.Errorf("file.save_failed", err)
 	}
 
 	return filePath, nil
@@ -1592,18 +2318,25 @@ func (a *App) SaveBinaryFileWithDialog(base64Content string, defaultFilename str
 
 // UpdatePageText 更新页面文本（用于编辑功能）
 func (a *App) UpdatePageText(pageNumber int, textType string, text string) error {
+	return a.updatePageText(pageNumber, textType, text, revisions.SourceEdit)
+}
+
+// updatePageText 更新页面文本，source 为历史版本中记录的来源
+func (a *App) updatePageText(pageNumber int, textType, text, source string) error {
+	doc := a.activeDocument()
+
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	if a.currentDoc == nil {
-		return fmt.Errorf("未加载PDF文档")
+	if doc == nil {
+		return i18n.Errorf("doc.not_loaded")
 	}
 
-	if pageNumber < 1 || pageNumber > len(a.currentDoc.Pages) {
-		return fmt.Errorf("页码超出范围")
+	if pageNumber < 1 || pageNumber > len(doc.Pages) {
+		return i18n.Errorf("page.out_of_range")
 	}
 
-	page := a.currentDoc.Pages[pageNumber-1]
+	page := doc.Pages[pageNumber-1]
 
 	switch textType {
 	case "ocr":
@@ -1611,7 +2344,7 @@ func (a *App) UpdatePageText(pageNumber int, textType string, text string) error
 	case "ai":
 		page.AIText = text
 	default:
-		return fmt.Errorf("不支持的文本类型: %s", textType)
+		return i18n.Errorf("text.unsupported_type", textType)
 	}
 
 	// 更新缓存
@@ -1624,8 +2357,8 @@ func (a *App) UpdatePageText(pageNumber int, textType string, text string) error
 		aiText = text
 	}
 
-	if err := a.savePageToCache(pageNumber, ocrText, aiText); err != nil {
-		log.Printf("更新缓存失败: %v", err)
+	if err := a.savePageToCache(doc, pageNumber, ocrText, aiText, source); err != nil {
+		logger.Errorf("更新缓存失败: %v", err)
 	}
 
 	return nil
@@ -1633,16 +2366,14 @@ func (a *App) UpdatePageText(pageNumber int, textType string, text string) error
 
 // ExtractNativeText 按需提取页面原生文本
 func (a *App) ExtractNativeText(pageNumber int) (string, error) {
-	a.mu.RLock()
-	doc := a.currentDoc
-	a.mu.RUnlock()
+	doc := a.activeDocument()
 
 	if doc == nil {
-		return "", fmt.Errorf("未加载PDF文档")
+		return "", i18n.Errorf("doc.not_loaded")
 	}
 
 	if pageNumber < 1 || pageNumber > len(doc.Pages) {
-		return "", fmt.Errorf("页码超出范围")
+		return "", i18n.Errorf("page.out_of_range")
 	}
 
 	page := doc.Pages[pageNumber-1]
@@ -1665,83 +2396,78 @@ func (a *App) ExtractNativeText(pageNumber int) (string, error) {
 	a.mu.Unlock()
 
 	// 更新缓存
-	if err := a.savePageToCache(pageNumber, page.OCRText, page.AIText); err != nil {
-		log.Printf("更新缓存失败: %v", err)
+	if err := a.savePageToCache(doc, pageNumber, page.OCRText, page.AIText, ""); err != nil {
+		logger.Errorf("更新缓存失败: %v", err)
 	}
 
 	return text, nil
 }
 
 // processPagesConcurrently 并发处理页面
-func (a *App) processPagesConcurrently(ctx context.Context, pageNumbers []int, historyRecord *history.HistoryRecord, doc *pdf.PDFDocument, forceReprocess bool) int {
-	const maxConcurrency = 3 // 限制并发数以避免API限制
-
-	// 创建工作通道
-	pagesChan := make(chan int, len(pageNumbers))
+// 渲染和识别分别由两组工作协程完成，渲染好的页面经有界队列交给识别协程，
+// 渲染慢时不占用API并发，识别慢时渲染最多提前队列长度的页面
+func (a *App) processPagesConcurrently(ctx context.Context, session *DocumentSession, pageNumbers []int, historyRecord *history.HistoryRecord, forceReprocess bool, job *jobs.Job, tracker *batchTracker) (int, int) {
+	// 限制并发数以避免API限制（处理配置可调整）
+	profile := a.resolveProfile(session.Doc.FilePath)
+	maxConcurrency := profileConcurrency(profile, 3)
+	renderConcurrency := profileRenderConcurrency(profile, 2)
+	doc := session.Doc
+
+	// 创建待处理队列（可以把查看中的页面提前）、渲染结果队列和结果通道
+	queue := session.startPageQueue(pageNumbers)
+	renderedChan := make(chan renderedPage, maxConcurrency)
 	resultsChan := make(chan ProcessResult, len(pageNumbers))
 
-	// 发送页面到通道
-	for _, pageNum := range pageNumbers {
-		pagesChan <- pageNum
+	// 启动渲染协程
+	var renderWG sync.WaitGroup
+	for i := 0; i < renderConcurrency; i++ {
+		renderWG.Add(1)
+		go func() {
+			defer renderWG.Done()
+			defer logger.RecoverPanic("render worker")
+			for pageNum, ok := queue.next(); ok; pageNum, ok = queue.next() {
+				// 暂停时等待继续，取消时停止处理
+				if !session.waitIfPaused(ctx) {
+					logger.Infof("渲染协程检测到取消信号，停止处理")
+					return
+				}
+
+				a.setHistoryPageStatus(historyRecord, pageNum, history.PageProcessing, nil)
+				rendered := a.renderBatchPage(doc, pageNum, profile, historyRecord, forceReprocess)
+
+				select {
+				case <-ctx.Done():
+					rendered.cleanup()
+					return
+				case renderedChan <- rendered:
+				}
+			}
+		}()
 	}
-	close(pagesChan)
+	go func() {
+		renderWG.Wait()
+		close(renderedChan)
+	}()
 
-	// 启动工作协程
+	// 启动识别协程
 	var wg sync.WaitGroup
 	for i := 0; i < maxConcurrency; i++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			for pageNum := range pagesChan {
-				// 检查暂停/取消状态
-				for {
-					a.processingMu.Lock()
-					state := a.processingState
-					a.processingMu.Unlock()
-
-					if state == ProcessingStateCancelling {
-						log.Printf("工作协程检测到取消信号，停止处理")
-						return
-					}
-
-					if state == ProcessingStatePaused {
-						log.Printf("工作协程检测到暂停信号，等待继续")
-						// 等待继续信号或取消信号
-						select {
-						case <-ctx.Done():
-							log.Printf("工作协程检测到上下文取消")
-							return
-						case <-a.resumeSignal:
-							log.Printf("工作协程收到继续信号")
-							break
-						case <-a.pauseSignal:
-							// 可能收到多个暂停信号，忽略
-							continue
-						}
-					} else {
-						break
-					}
+			defer logger.RecoverPanic("page worker")
+			for rendered := range renderedChan {
+				result := a.recognizeBatchPage(ctx, session, doc, rendered, profile, historyRecord)
+				if result.Error != nil {
+					a.setHistoryPageStatus(historyRecord, result.PageNumber, history.PageFailed, result.Error)
 				}
 
-				// 检查是否被取消
-				select {
-				case <-ctx.Done():
-					log.Printf("工作协程检测到上下文取消，停止处理")
-					return
-				default:
+				// 取消后继续取出已渲染的页面以清理临时文件，但不再发送结果
+				if ctx.Err() != nil {
+					continue
 				}
-
-				result := a.processPageWithResult(ctx, pageNum, historyRecord, doc, forceReprocess)
-
-				// 更新已处理计数
-				a.processingMu.Lock()
-				a.processedInBatch++
-				a.processingMu.Unlock()
-
-				// 再次检查是否被取消
 				select {
 				case <-ctx.Done():
-					return
 				case resultsChan <- result:
 				}
 			}
@@ -1756,38 +2482,45 @@ func (a *App) processPagesConcurrently(ctx context.Context, pageNumbers []int, h
 
 	// 收集结果并发送进度更新
 	processed := 0
+	succeeded := 0
 	total := len(pageNumbers)
 
 	for result := range resultsChan {
 		processed++
 
 		if result.Error != nil {
-			log.Printf("处理第%d页失败: %v", result.PageNumber, result.Error)
+			logger.Errorf("处理第%d页失败: %v", result.PageNumber, result.Error)
 			// 检查是否是取消导致的错误
 			if result.Error == context.Canceled || strings.Contains(result.Error.Error(), "context canceled") {
-				log.Printf("页面 %d 处理被取消", result.PageNumber)
+				logger.Infof("页面 %d 处理被取消", result.PageNumber)
 				// 取消导致的错误不发送 processing-error 事件
 			} else {
 				// 只有真正的错误才发送 processing-error 事件
-				runtime.EventsEmit(a.ctx, "processing-error", fmt.Sprintf("处理第%d页失败: %v", result.PageNumber, result.Error))
+				a.emitPageError(ctx, "processing-error", result.PageNumber, i18n.T("page.failed", result.PageNumber, result.Error), result.Error)
 			}
 		} else {
+			succeeded++
+			a.markJobPageDone(job, result.PageNumber)
+
 			// 页面处理成功，立即发送单页完成事件以触发实时刷新
-			runtime.EventsEmit(a.ctx, "page-processed", map[string]interface{}{
-				"pageNumber": result.PageNumber,
-				"status":     result.Status,
+			a.emitTask(ctx, "page-processed", map[string]interface{}{
+				"document_id": session.ID,
+				"pageNumber":  result.PageNumber,
+				"status":      result.Status,
 			})
 		}
 
-		runtime.EventsEmit(a.ctx, "processing-progress", ProgressUpdate{
+		session.incrementProcessed()
+		a.emitTask(ctx, "processing-progress", a.pageProgress(tracker, ProgressUpdate{
+			DocumentID:  session.ID,
 			Total:       total,
 			Processed:   processed,
 			CurrentPage: result.PageNumber,
 			Status:      result.Status,
-		})
+		}, result.Duration))
 	}
 
-	return processed
+	return processed, succeeded
 }
 
 // ProcessResult 处理结果
@@ -1795,6 +2528,7 @@ type ProcessResult struct {
 	PageNumber int
 	Status     string
 	Error      error
+	Duration   time.Duration // 处理耗时
 }
 
 // AIProcessResult AI处理结果
@@ -1803,79 +2537,106 @@ type AIProcessResult struct {
 	Status     string
 	Result     string
 	Error      error
+	Duration   time.Duration // 处理耗时
 }
 
-// processPageWithResult 处理页面并返回结果
-func (a *App) processPageWithResult(ctx context.Context, pageNum int, historyRecord *history.HistoryRecord, doc *pdf.PDFDocument, forceReprocess bool) ProcessResult {
-	startTime := time.Now()
-
-	// 检查缓存（除非强制重新处理）
-	if !forceReprocess {
-		if cached := a.checkPageCache(pageNum); cached != nil {
-			a.pdfProcessor.UpdatePageOCR(doc, pageNum, cached.OCRText)
-			if cached.AIText != "" {
-				a.pdfProcessor.UpdatePageAI(doc, pageNum, cached.AIText)
-			}
-			if cached.OriginalText != "" {
-				a.pdfProcessor.UpdatePageText(doc, pageNum, cached.OriginalText)
-			}
+// loadCachedPage 页面有缓存时直接使用缓存结果并保存到历史记录，返回是否命中缓存
+func (a *App) loadCachedPage(doc *pdf.PDFDocument, pageNum int, historyRecord *history.HistoryRecord, startTime time.Time) bool {
+	cached := a.checkPageCache(doc, pageNum)
+	if cached == nil {
+		return false
+	}
 
-			// 即使从缓存加载，也要保存到历史记录
-			if historyRecord != nil {
-				var originalText string
-				if pageNum > 0 && pageNum <= len(doc.Pages) {
-					originalText = doc.Pages[pageNum-1].Text
-				}
+	a.pdfProcessor.UpdatePageOCR(doc, pageNum, cached.OCRText)
+	if cached.AIText != "" {
+		a.pdfProcessor.UpdatePageAI(doc, pageNum, cached.AIText)
+	}
+	if cached.OriginalText != "" {
+		a.pdfProcessor.UpdatePageText(doc, pageNum, cached.OriginalText)
+	}
 
-				page := &history.HistoryPage{
-					HistoryID:       historyRecord.ID,
-					PageNumber:      pageNum,
-					OriginalText:    originalText,
-					OCRText:         cached.OCRText,
-					AIProcessedText: cached.AIText,
-					ProcessingTime:  time.Since(startTime).Seconds(),
-				}
+	// 即使从缓存加载，也要保存到历史记录
+	if historyRecord != nil {
+		var originalText string
+		if pageNum > 0 && pageNum <= len(doc.Pages) {
+			originalText = doc.Pages[pageNum-1].Text
+		}
 
-				log.Printf("保存缓存页面到历史记录: 页面%d, OCR长度=%d, AI长度=%d",
-					pageNum, len(cached.OCRText), len(cached.AIText))
+		page := &history.HistoryPage{
+			HistoryID:       historyRecord.ID,
+			PageNumber:      pageNum,
+			OriginalText:    originalText,
+			OCRText:         cached.OCRText,
+			AIProcessedText: cached.AIText,
+			ProcessingTime:  time.Since(startTime).Seconds(),
+			Status:          history.PageSkippedCache,
+		}
 
-				if err := a.historyManager.AddPage(page); err != nil {
-					log.Printf("保存历史记录失败: %v", err)
-				} else {
-					log.Printf("缓存页面历史记录保存成功: 页面%d", pageNum)
-				}
-			}
+		logger.Infof("保存缓存页面到历史记录: 页面%d, OCR长度=%d, AI长度=%d",
+			pageNum, len(cached.OCRText), len(cached.AIText))
 
-			return ProcessResult{
-				PageNumber: pageNum,
-				Status:     "从缓存加载",
-				Error:      nil,
-			}
+		if err := a.historyManager.AddPage(page); err != nil {
+			logger.Errorf("保存历史记录失败: %v", err)
 		}
 	}
+	return true
+}
 
-	// 检查是否被取消
-	select {
-	case <-ctx.Done():
-		return ProcessResult{
-			PageNumber: pageNum,
-			Status:     "处理被取消",
-			Error:      ctx.Err(),
-		}
-	default:
+// initHistoryPages 将批次中的页面标记为等待处理
+func (a *App) initHistoryPages(historyRecord *history.HistoryRecord, pageNumbers []int) {
+	if historyRecord == nil {
+		return
+	}
+	if err := a.historyManager.InitRecordPages(historyRecord.ID, pageNumbers); err != nil {
+		logger.Errorf("初始化页面状态失败: %v", err)
 	}
+}
 
-	// 处理页面
-	err := a.processSinglePage(ctx, pageNum, historyRecord)
-	status := "处理完成"
+// finishHistoryRecord 更新历史记录状态（部分页面失败时标记为 partial）
+// retry 为true时按记录中所有页面的状态重新统计，而不是只统计本次处理的页面
+func (a *App) finishHistoryRecord(historyRecord *history.HistoryRecord, retry bool, completed, failed int, cancelled bool) {
+	if historyRecord == nil {
+		return
+	}
+	var err error
+	if retry {
+		_, err = a.historyManager.FinishRecordByPages(historyRecord.ID, cancelled)
+	} else {
+		_, err = a.historyManager.FinishRecord(historyRecord.ID, completed, failed, cancelled)
+	}
 	if err != nil {
-		status = "处理失败"
+		logger.Errorf("更新历史记录状态失败: %v", err)
 	}
+}
 
-	return ProcessResult{
-		PageNumber: pageNum,
-		Status:     status,
-		Error:      err,
+// reportWriteFailures 提交批次的延迟写入，有写入失败（缓存或历史记录未保存）时提示用户
+func (a *App) reportWriteFailures(documentID string) {
+	if err := a.store.SyncErr(); err != nil {
+		logger.Errorf("保存处理结果失败: %v", err)
+		a.emit("save-warning", map[string]interface{}{
+			"document_id": documentID,
+			"message":     i18n.T("storage.write_failed", err),
+		})
+	}
+}
+
+// setHistoryPageStatus 更新历史记录中的页面状态，因取消而中断的页面恢复为等待处理
+func (a *App) setHistoryPageStatus(historyRecord *history.HistoryRecord, pageNum int, status history.PageStatus, pageErr error) {
+	if historyRecord == nil {
+		return
+	}
+
+	errorMsg := ""
+	if pageErr != nil {
+		if errors.Is(pageErr, context.Canceled) || strings.Contains(pageErr.Error(), "context canceled") {
+			status = history.PagePending
+		} else {
+			errorMsg = pageErr.Error()
+		}
+	}
+
+	if err := a.historyManager.SetPageStatus(historyRecord.ID, pageNum, status, errorMsg); err != nil {
+		logger.Errorf("更新第%d页状态失败: %v", pageNum, err)
 	}
 }
 
@@ -1904,10 +2665,17 @@ func (a *App) GetDocumentInfo(filePath string) (*document.DocumentInfo, error) {
 	return a.documentProcessor.GetDocumentInfo(filePath)
 }
 
-// GetSupportedModels 获取支持的AI模型
+// GetSupportedModels 获取当前AI服务的可用模型，无法获取时返回默认模型
 func (a *App) GetSupportedModels() []ocr.ModelInfo {
-	if a.ocrClient != nil {
-		return a.ocrClient.GetSupportedModels()
+	if a.ocrClient != nil && a.ocrClient.Capabilities().ListModels {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		models, err := a.ocrClient.ListModels(ctx)
+		if err == nil {
+			return models
+		}
+		logger.Warnf("获取模型列表失败，使用默认模型: %v", err)
 	}
 
 	// 返回默认模型列表
@@ -1926,7 +2694,7 @@ func (a *App) GetSupportedModels() []ocr.ModelInfo {
 // TestAIConnection 测试AI连接
 func (a *App) TestAIConnection() error {
 	if a.ocrClient == nil {
-		return fmt.Errorf("未配置AI服务")
+		return i18n.Errorf("ai.not_configured")
 	}
 
 	// 创建一个简单的测试
@@ -1934,7 +2702,7 @@ func (a *App) TestAIConnection() error {
 	defer cancel()
 
 	// 使用AI处理一个简单的文本测试
-	_, err := a.ocrClient.ProcessWithAI(ctx, "测试连接", "请回复'连接成功'")
+	_, err := a.ocrClient.ProcessText(ctx, "测试连接", "请回复'连接成功'")
 	if err != nil {
 		return fmt.Errorf("AI连接测试失败: %w", err)
 	}
@@ -1944,9 +2712,7 @@ func (a *App) TestAIConnection() error {
 
 // GetProcessingStats 获取处理统计信息
 func (a *App) GetProcessingStats() map[string]interface{} {
-	a.mu.RLock()
-	doc := a.currentDoc
-	a.mu.RUnlock()
+	doc := a.activeDocument()
 
 	stats := map[string]interface{}{
 		"total_pages":     0,
@@ -1968,7 +2734,7 @@ func (a *App) GetProcessingStats() map[string]interface{} {
 			}
 
 			// 检查缓存
-			if a.checkPageCache(i+1) != nil {
+			if a.checkPageCache(doc, i+1) != nil {
 				cached++
 			}
 		}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/pdf"
+)
+
+// AppendToDocument 将图片或PDF追加到当前文档末尾，返回追加后的文档ID
+// 当前文档须为PDF或图片；扫描目录中的文档直接追加，其他文档在扫描目录中生成包含追加页面的新PDF（原文件不变）
+// 文档内容变化后文档ID随之改变，已有页面的识别结果、批注、书签、审核状态和排除状态会迁移到新文档
+func (a *App) AppendToDocument(paths []string) (string, error) {
+	session := a.activeSession()
+	if session == nil {
+		return "", i18n.Errorf("doc.not_loaded")
+	}
+	if session.busy() {
+		return "", i18n.Errorf("doc.busy")
+	}
+	if len(paths) == 0 {
+		return "", fmt.Errorf("没有要追加的文件")
+	}
+	doc := session.Doc
+	if !isAppendable(doc.FilePath) {
+		return "", fmt.Errorf("只能向PDF或图片文档追加页面")
+	}
+	for _, path := range paths {
+		if !isAppendable(path) {
+			return "", fmt.Errorf("只能追加PDF或图片: %s", filepath.Base(path))
+		}
+	}
+
+	scanDir, err := a.scanDir()
+	if err != nil {
+		return "", err
+	}
+	target := doc.FilePath
+	if filepath.Dir(target) != scanDir || !strings.EqualFold(filepath.Ext(target), ".pdf") {
+		base := strings.TrimSuffix(filepath.Base(target), filepath.Ext(target))
+		target = filepath.Join(scanDir, fmt.Sprintf("%s-%s.pdf", base, time.Now().Format("20060102-150405")))
+	}
+
+	if err := mergeIntoPDF(append([]string{doc.FilePath}, paths...), target); err != nil {
+		return "", err
+	}
+
+	// 迁移已有页面的识别结果（文档ID为内容哈希，追加后会改变）
+	newDoc, err := a.documentProcessor.LoadDocument(target)
+	if err != nil {
+		return "", fmt.Errorf("加载追加后的文档失败: %w", err)
+	}
+	a.mu.RLock()
+	for i, page := range doc.Pages {
+		if i >= len(newDoc.Pages) || (page.OCRText == "" && page.AIText == "") {
+			continue
+		}
+		if err := a.savePageToCache(newDoc, i+1, page.OCRText, page.AIText, ""); err != nil {
+			logger.Warnf("迁移第%d页识别结果失败: %v", i+1, err)
+		}
+	}
+	a.mu.RUnlock()
+	if newID, err := a.cacheManager.GenerateDocumentID(target); err != nil {
+		logger.Warnf("生成追加后的文档ID失败，批注、书签和审核状态未迁移: %v", err)
+	} else {
+		a.copyDocumentData(session.ID, newID)
+	}
+
+	if err := a.CloseDocument(session.ID); err != nil {
+		return "", err
+	}
+	documentID, err := a.OpenDocument(target)
+	if err != nil {
+		return "", err
+	}
+	if excluded := excludedPageNumbers(doc); len(excluded) > 0 {
+		if err := a.SetPagesExcluded(excluded, true); err != nil {
+			logger.Warnf("迁移排除页面失败: %v", err)
+		}
+	}
+	logger.Infof("已向文档追加 %d 个文件，共 %d 页: %s", len(paths), newDoc.PageCount, target)
+	return documentID, nil
+}
+
+// copyDocumentData 将批注、书签和审核状态复制到文档的新ID，页码不变
+func (a *App) copyDocumentData(fromID, toID string) {
+	if fromID == toID {
+		return
+	}
+	if err := a.annotationManager.CopyDocument(fromID, toID); err != nil {
+		logger.Warnf("迁移页面批注失败: %v", err)
+	}
+	if err := a.bookmarkManager.CopyDocument(fromID, toID); err != nil {
+		logger.Warnf("迁移页面书签失败: %v", err)
+	}
+	if err := a.reviewManager.CopyDocument(fromID, toID); err != nil {
+		logger.Warnf("迁移审核状态失败: %v", err)
+	}
+}
+
+// isAppendable 文件是否为可合并的PDF或图片
+func isAppendable(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".pdf") || pdf.IsImageFile(path)
+}
+
+// mergeIntoPDF 按顺序将PDF和图片合并为 target（先写入同一目录中的临时文件再重命名替换，target 可以是输入之一）
+func mergeIntoPDF(files []string, target string) error {
+	tempDir, err := os.MkdirTemp("", "pdfseer-append-*")
+	if err != nil {
+		return i18n.Errorf("file.temp_failed", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	parts := make([]string, 0, len(files))
+	for i, file := range files {
+		if strings.EqualFold(filepath.Ext(file), ".pdf") {
+			parts = append(parts, file)
+			continue
+		}
+		part := filepath.Join(tempDir, fmt.Sprintf("image-%d.pdf", i))
+		if err := api.ImportImagesFile([]string{file}, part, nil, nil); err != nil {
+			return fmt.Errorf("图片 %s 无法转换为PDF: %w", filepath.Base(file), err)
+		}
+		parts = append(parts, part)
+	}
+
+	// 合并失败或中断时目标文件保持不变
+	tmp, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".*.tmp")
+	if err != nil {
+		return i18n.Errorf("file.temp_failed", err)
+	}
+	merged := tmp.Name()
+	tmp.Close()
+	if err := api.MergeCreateFile(parts, merged, false, nil); err != nil {
+		os.Remove(merged)
+		return fmt.Errorf("合并PDF失败: %w", err)
+	}
+	if err := os.Chmod(merged, 0644); err != nil {
+		logger.Warnf("设置文件权限失败: %v", err)
+	}
+	if err := os.Rename(merged, target); err != nil {
+		os.Remove(merged)
+		return fmt.Errorf("保存文档失败: %w", err)
+	}
+	return nil
+}
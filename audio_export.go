@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/ocr"
+	"pdf-ocr-ai/pkg/outline"
+	"pdf-ocr-ai/pkg/stitch"
+	"pdf-ocr-ai/pkg/tts"
+)
+
+// unsafeFileNamePattern 文件名中不允许的字符
+var unsafeFileNamePattern = regexp.MustCompile(`[\\/:*?"<>|\s]+`)
+
+// AudioExportResult 语音导出结果
+type AudioExportResult struct {
+	Directory string   `json:"directory"`
+	Files     []string `json:"files"`
+}
+
+// audioChapter 待合成的章节
+type audioChapter struct {
+	title  string
+	file   string
+	chunks []string
+}
+
+// ExportAudio 将当前文档指定页面的文本合成为MP3，按一级章节分别保存（没有检测到章节时保存为一个文件）
+// textType: ocr 只用OCR文本，ai 只用AI处理文本，其他值优先OCR文本；outputDir 为空时弹出目录选择对话框
+// 合成进度通过 tts-progress 事件通知，可通过 CancelAudioExport 取消
+func (a *App) ExportAudio(pageNumbers []int, textType string, outputDir string) (*AudioExportResult, error) {
+	doc := a.activeDocument()
+	if doc == nil {
+		return nil, i18n.Errorf("doc.not_loaded")
+	}
+	cfg := a.configManager.GetConfig()
+	synthesizer, err := tts.New(cfg.TTS, aiBaseURL(cfg.AI.Provider, cfg.AI.BaseURL), cfg.AI.APIKey)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := append([]int(nil), pageNumbers...)
+	sort.Ints(pages)
+	var texts []string
+	for _, pageNum := range pages {
+		if pageNum >= 1 && pageNum <= len(doc.Pages) {
+			texts = append(texts, pageText(doc.Pages[pageNum-1], textType))
+		}
+	}
+
+	baseName := strings.TrimSuffix(filepath.Base(doc.FilePath), filepath.Ext(doc.FilePath))
+	chapters, total := planAudioChapters(texts, baseName, cfg.TTS.MaxChars)
+	if total == 0 {
+		return nil, fmt.Errorf("选中的页面没有可朗读的文本")
+	}
+
+	if outputDir == "" {
+		outputDir, err = runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{Title: "选择音频保存目录"})
+		if err != nil || outputDir == "" {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(a.ctx)
+	defer cancel()
+	a.mu.Lock()
+	if a.audioCancel != nil {
+		a.mu.Unlock()
+		return nil, fmt.Errorf("已有语音导出正在进行")
+	}
+	a.audioCancel = cancel
+	a.mu.Unlock()
+	defer func() {
+		a.mu.Lock()
+		a.audioCancel = nil
+		a.mu.Unlock()
+	}()
+
+	result := &AudioExportResult{Directory: outputDir, Files: []string{}}
+	current := 0
+	for _, chapter := range chapters {
+		path := filepath.Join(outputDir, chapter.file)
+		file, err := os.Create(path)
+		if err != nil {
+			return result, fmt.Errorf("创建音频文件失败: %w", err)
+		}
+		for _, chunk := range chapter.chunks {
+			current++
+			a.emit("tts-progress", map[string]interface{}{
+				"current": current,
+				"total":   total,
+				"chapter": chapter.title,
+				"file":    chapter.file,
+			})
+			audio, err := synthesizer.Synthesize(ctx, chunk)
+			if err == nil {
+				_, err = file.Write(audio)
+			}
+			if err != nil {
+				file.Close()
+				os.Remove(path)
+				if ctx.Err() != nil {
+					return result, fmt.Errorf("语音导出已取消")
+				}
+				return result, err
+			}
+		}
+		if err := file.Close(); err != nil {
+			return result, fmt.Errorf("写入音频文件失败: %w", err)
+		}
+		result.Files = append(result.Files, path)
+	}
+
+	logger.Infof("已导出 %d 个音频文件到 %s", len(result.Files), outputDir)
+	a.emit("tts-complete", map[string]interface{}{
+		"directory": outputDir,
+		"files":     len(result.Files),
+	})
+	return result, nil
+}
+
+// CancelAudioExport 取消正在进行的语音导出
+func (a *App) CancelAudioExport() {
+	a.mu.RLock()
+	cancel := a.audioCancel
+	a.mu.RUnlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// planAudioChapters 按一级章节划分文本并切分为合成片段，返回章节和片段总数
+func planAudioChapters(texts []string, baseName string, maxChars int) ([]audioChapter, int) {
+	var chapters []audioChapter
+	total := 0
+	split := outline.SplitChapters(texts)
+	for i, chapter := range split {
+		text := tts.PlainText(stitch.Pages(chapter.Pages).Text)
+		chunks := tts.SplitText(text, maxChars)
+		if len(chunks) == 0 {
+			continue
+		}
+
+		file := baseName + ".mp3"
+		if len(split) > 1 {
+			title := chapter.Title
+			if title == "" {
+				title = baseName
+			}
+			file = fmt.Sprintf("%02d-%s.mp3", i+1, safeFileName(title))
+		}
+		chapters = append(chapters, audioChapter{title: chapter.Title, file: file, chunks: chunks})
+		total += len(chunks)
+	}
+	return chapters, total
+}
+
+// safeFileName 将标题转换为可用的文件名
+func safeFileName(title string) string {
+	name := strings.Trim(unsafeFileNamePattern.ReplaceAllString(title, "_"), "_.")
+	if runes := []rune(name); len(runes) > 50 {
+		name = string(runes[:50])
+	}
+	if name == "" {
+		name = "untitled"
+	}
+	return name
+}
+
+// aiBaseURL AI服务的地址，未配置时使用服务类型的默认地址
+func aiBaseURL(provider, baseURL string) string {
+	if baseURL != "" {
+		return baseURL
+	}
+	if provider == "" {
+		provider = ocr.DefaultProviderID
+	}
+	for _, info := range ocr.Providers() {
+		if info.ID == provider {
+			return info.DefaultBaseURL
+		}
+	}
+	return ""
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"pdf-ocr-ai/pkg/history"
+	"pdf-ocr-ai/pkg/jobs"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/ocr"
+	"pdf-ocr-ai/pkg/webhook"
+)
+
+// BatchSummary 批处理结束时的摘要（用于Webhook通知）
+type BatchSummary struct {
+	Event           string         `json:"event"` // batch.completed、batch.failed 或 batch.cancelled
+	DocumentID      string         `json:"document_id"`
+	DocumentPath    string         `json:"document_path"`
+	TaskType        string         `json:"task_type"`
+	Pages           []int          `json:"pages"`
+	SuccessCount    int            `json:"success_count"`
+	FailedCount     int            `json:"failed_count"`
+	DurationSeconds float64        `json:"duration_seconds"`
+	Usage           ocr.TokenUsage `json:"usage"` // 批处理期间的API用量（与其他并发批次共享客户端时为近似值）
+	Model           string         `json:"model"`
+	Cost            float64        `json:"cost"` // 按配置的模型价格估算的费用，未设置价格时为0
+	Error           string         `json:"error,omitempty"`
+	StartedAt       string         `json:"started_at"`
+	FinishedAt      string         `json:"finished_at"`
+}
+
+// batchTracker 记录批处理开始时的状态，用于结束时生成摘要
+type batchTracker struct {
+	session   *DocumentSession
+	taskType  string
+	model     string
+	pages     []int
+	record    *history.HistoryRecord // 结束时保存耗时统计，可为nil
+	startedAt time.Time
+	usage     ocr.TokenUsage
+	stats     progressStats
+}
+
+// startBatchTracking 开始记录批处理
+func (a *App) startBatchTracking(session *DocumentSession, taskType, model string, pages []int, record *history.HistoryRecord) *batchTracker {
+	tracker := &batchTracker{
+		session:   session,
+		taskType:  taskType,
+		model:     model,
+		pages:     pages,
+		record:    record,
+		startedAt: time.Now(),
+	}
+	if a.ocrClient != nil {
+		tracker.usage = a.ocrClient.GetUsage()
+	}
+	return tracker
+}
+
+// finishBatchTracking 生成批处理摘要并发送通知
+func (a *App) finishBatchTracking(tracker *batchTracker, successCount int, cancelled bool, errorMsg string) {
+	finishedAt := time.Now()
+
+	summary := BatchSummary{
+		Event:           "batch.completed",
+		DocumentID:      tracker.session.ID,
+		DocumentPath:    tracker.session.Doc.FilePath,
+		TaskType:        tracker.taskType,
+		Model:           tracker.model,
+		Pages:           tracker.pages,
+		SuccessCount:    successCount,
+		FailedCount:     len(tracker.pages) - successCount,
+		DurationSeconds: finishedAt.Sub(tracker.startedAt).Seconds(),
+		Error:           errorMsg,
+		StartedAt:       tracker.startedAt.Format(time.RFC3339),
+		FinishedAt:      finishedAt.Format(time.RFC3339),
+	}
+	if a.ocrClient != nil {
+		summary.Usage = a.ocrClient.GetUsage().Sub(tracker.usage)
+		summary.Cost = a.configManager.GetAIConfig().Cost(tracker.model, summary.Usage.PromptTokens, summary.Usage.CompletionTokens)
+	}
+	a.saveTimingStats(tracker, summary)
+	if !cancelled {
+		go a.emitBatchReport(tracker.record)
+	}
+	if !cancelled && successCount > 0 {
+		go a.uploadBatchResults(tracker.session.Doc, tracker.taskType == string(jobs.TaskAI))
+	}
+
+	switch {
+	case cancelled:
+		summary.Event = "batch.cancelled"
+	case successCount == 0 && len(tracker.pages) > 0:
+		summary.Event = "batch.failed"
+	}
+
+	a.sendWebhook(summary.Event, summary)
+	a.notifyBatchFinished(summary)
+}
+
+// saveTimingStats 将批处理的耗时和用量统计保存到历史记录
+func (a *App) saveTimingStats(tracker *batchTracker, summary BatchSummary) {
+	if tracker.record == nil || a.historyManager == nil {
+		return
+	}
+
+	// 重试失败页面时追加到记录原有的统计上（新记录的原有统计为0）
+	record := tracker.record
+	stats := history.TimingStats{
+		DurationSeconds: record.DurationSeconds + summary.DurationSeconds,
+		TotalTokens:     record.TotalTokens + summary.Usage.TotalTokens,
+		Cost:            record.Cost + summary.Cost,
+	}
+	tracker.stats.mu.Lock()
+	completed := record.CompletedPages + tracker.stats.completed
+	if completed > 0 {
+		pageSeconds := record.AvgPageSeconds*float64(record.CompletedPages) + tracker.stats.total.Seconds()
+		stats.AvgPageSeconds = pageSeconds / float64(completed)
+		if stats.DurationSeconds > 0 {
+			stats.PagesPerMinute = float64(completed) / stats.DurationSeconds * 60
+		}
+	}
+	tracker.stats.mu.Unlock()
+
+	if err := a.historyManager.SaveTimingStats(record.ID, stats); err != nil {
+		logger.Errorf("%v", err)
+	}
+}
+
+// sendWebhook 异步发送Webhook通知
+func (a *App) sendWebhook(event string, payload interface{}) {
+	if a.configManager == nil {
+		return
+	}
+
+	notifier := webhook.NewNotifier(a.configManager.GetConfig().Webhook)
+	if !notifier.Enabled() {
+		return
+	}
+
+	go func() {
+		if err := notifier.Send(event, payload); err != nil {
+			logger.Errorf("发送Webhook通知失败 (%s): %v", event, err)
+		}
+	}()
+}
+
+// TestWebhook 发送一条测试通知，验证Webhook配置
+func (a *App) TestWebhook() error {
+	notifier := webhook.NewNotifier(a.configManager.GetConfig().Webhook)
+	if !notifier.Enabled() {
+		return fmt.Errorf("未启用Webhook或未配置通知地址")
+	}
+
+	return notifier.Send("test", map[string]interface{}{
+		"event":   "test",
+		"message": "pdfSeer Webhook 测试通知",
+		"sent_at": time.Now().Format(time.RFC3339),
+	})
+}
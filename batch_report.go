@@ -0,0 +1,250 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"pdf-ocr-ai/pkg/history"
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// 质量问题类型
+const (
+	QualityIssueFailed         = "failed"
+	QualityIssueEmpty          = "empty"
+	QualityIssueShort          = "short"
+	QualityIssueLowConfidence  = "low_confidence"
+	QualityIssueGarbled        = "garbled"
+	QualityIssueRenderFallback = "render_fallback"
+)
+
+// garbledTextRatio 乱码字符（含私用区字符、方框和常见的编码错误字符）占比超过该值时视为乱码较多
+const garbledTextRatio = 0.05
+
+// shortTextMedianRatio 文本字数低于批次各页字数中位数的该比例时视为过短
+const shortTextMedianRatio = 0.25
+
+// BatchReportIssue 质量报告中的一个问题
+type BatchReportIssue struct {
+	Page   int    `json:"page"`
+	Kind   string `json:"kind"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// BatchReport 批次处理结果的质量报告
+type BatchReport struct {
+	HistoryID    int                      `json:"history_id"`
+	DocumentName string                   `json:"document_name"`
+	DocumentPath string                   `json:"document_path"`
+	TaskType     history.TaskType         `json:"task_type"`
+	Model        string                   `json:"model"`
+	Status       history.ProcessingStatus `json:"status"`
+	ProcessedAt  string                   `json:"processed_at"`
+	TotalPages   int                      `json:"total_pages"`   // 记录中的页数
+	CheckedPages int                      `json:"checked_pages"` // 已完成并检查结果的页数
+	Counts       map[string]int           `json:"counts"`        // 各类问题的数量
+	Issues       []BatchReportIssue       `json:"issues"`        // 按页码排序
+}
+
+// GetBatchReport 检查历史记录中各页的结果，生成质量报告
+// 报告包括处理失败、结果为空、文本过短、低置信度、乱码较多和使用了备用渲染方案的页面
+func (a *App) GetBatchReport(historyID int) (*BatchReport, error) {
+	record, err := a.historyManager.GetRecord(historyID)
+	if err != nil {
+		return nil, i18n.Errorf("history.get_failed", err)
+	}
+	if record == nil {
+		return nil, i18n.Errorf("history.not_found")
+	}
+	progress, err := a.historyManager.GetRecordProgress(historyID)
+	if err != nil {
+		return nil, err
+	}
+	pages, err := a.historyManager.GetRecordPages(historyID)
+	if err != nil {
+		return nil, fmt.Errorf("获取历史页面失败: %w", err)
+	}
+
+	report := &BatchReport{
+		HistoryID:    record.ID,
+		DocumentName: record.DocumentName,
+		DocumentPath: record.DocumentPath,
+		TaskType:     record.TaskType,
+		Model:        record.Model,
+		Status:       record.Status,
+		ProcessedAt:  record.ProcessedAt,
+		TotalPages:   progress.Total,
+		CheckedPages: len(pages),
+		Counts:       map[string]int{},
+		Issues:       []BatchReportIssue{},
+	}
+	addIssue := func(page int, kind, detail string) {
+		report.Issues = append(report.Issues, BatchReportIssue{Page: page, Kind: kind, Detail: detail})
+		report.Counts[kind]++
+	}
+
+	for _, page := range progress.Pages {
+		if page.Status != history.PageFailed {
+			continue
+		}
+		detail := ""
+		if page.ErrorMessage != nil {
+			detail = *page.ErrorMessage
+		}
+		addIssue(page.PageNumber, QualityIssueFailed, detail)
+	}
+
+	texts := make(map[int]string, len(pages))
+	var lengths []int
+	for _, page := range pages {
+		text := page.OCRText
+		if record.TaskType == history.TaskTypeAI {
+			text = page.AIProcessedText
+		}
+		text = strings.TrimSpace(text)
+		texts[page.PageNumber] = text
+		if text != "" {
+			lengths = append(lengths, utf8.RuneCountInString(text))
+		}
+	}
+	shortLimit := lowConfidenceMinRunes
+	if median := medianInt(lengths); int(float64(median)*shortTextMedianRatio) > shortLimit {
+		shortLimit = int(float64(median) * shortTextMedianRatio)
+	}
+
+	for _, page := range pages {
+		text := texts[page.PageNumber]
+		runes := utf8.RuneCountInString(text)
+		switch {
+		case text == "":
+			addIssue(page.PageNumber, QualityIssueEmpty, "")
+		case runes < shortLimit:
+			addIssue(page.PageNumber, QualityIssueShort, i18n.T("report.chars", runes))
+		}
+		if text != "" {
+			if ratio := noiseRatio(text); ratio > lowConfidenceNoiseRatio {
+				addIssue(page.PageNumber, QualityIssueLowConfidence, i18n.T("report.ratio", ratio*100))
+			} else if ratio := garbledRatio(text); ratio > garbledTextRatio {
+				addIssue(page.PageNumber, QualityIssueGarbled, i18n.T("report.ratio", ratio*100))
+			}
+		}
+		if page.RenderFallback {
+			addIssue(page.PageNumber, QualityIssueRenderFallback, "")
+		}
+	}
+
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		return report.Issues[i].Page < report.Issues[j].Page
+	})
+	return report, nil
+}
+
+// ExportBatchReport 将质量报告导出为Markdown，path为空时弹出保存对话框，返回保存路径
+func (a *App) ExportBatchReport(historyID int, path string) (string, error) {
+	report, err := a.GetBatchReport(historyID)
+	if err != nil {
+		return "", err
+	}
+
+	if path == "" {
+		path, err = runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+			DefaultFilename: fmt.Sprintf("%s-report-%d.md", strings.TrimSuffix(report.DocumentName, ".pdf"), historyID),
+			Filters:         []runtime.FileFilter{{DisplayName: "Markdown", Pattern: "*.md"}},
+			Title:           "导出质量报告",
+		})
+		if err != nil || path == "" {
+			return "", err
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(formatBatchReport(report)), 0644); err != nil {
+		return "", fmt.Errorf("保存质量报告失败: %w", err)
+	}
+	logger.Infof("已导出记录%d的质量报告到 %s", historyID, path)
+	return path, nil
+}
+
+// formatBatchReport 生成质量报告的Markdown
+func formatBatchReport(report *BatchReport) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("# %s\n\n", i18n.T("report.title", report.DocumentName)))
+	builder.WriteString(fmt.Sprintf("**%s:** %s\n\n", i18n.T("export.file_path"), report.DocumentPath))
+	if report.Model != "" {
+		builder.WriteString(fmt.Sprintf("**%s:** %s\n\n", i18n.T("report.model"), report.Model))
+	}
+	builder.WriteString(i18n.T("report.summary", report.TotalPages, report.CheckedPages, len(report.Issues)) + "\n\n")
+
+	if len(report.Issues) == 0 {
+		builder.WriteString(i18n.T("report.no_issues") + "\n")
+		return builder.String()
+	}
+
+	builder.WriteString(fmt.Sprintf("| %s | %s | %s |\n|---|---|---|\n",
+		i18n.T("report.column_page"), i18n.T("report.column_issue"), i18n.T("report.column_detail")))
+	for _, issue := range report.Issues {
+		detail := strings.ReplaceAll(strings.ReplaceAll(issue.Detail, "|", "\\|"), "\n", " ")
+		builder.WriteString(fmt.Sprintf("| %d | %s | %s |\n", issue.Page, i18n.T("report.issue."+issue.Kind), detail))
+	}
+	return builder.String()
+}
+
+// emitBatchReport 批次结束后生成质量报告，有问题时通知前端
+func (a *App) emitBatchReport(record *history.HistoryRecord) {
+	if record == nil || a.historyManager == nil {
+		return
+	}
+	report, err := a.GetBatchReport(record.ID)
+	if err != nil {
+		logger.Warnf("生成记录%d的质量报告失败: %v", record.ID, err)
+		return
+	}
+	if len(report.Issues) == 0 {
+		return
+	}
+	a.emit("batch-report", map[string]interface{}{
+		"history_id": report.HistoryID,
+		"issues":     len(report.Issues),
+		"counts":     report.Counts,
+	})
+}
+
+// garbledRatio 乱码字符的占比，包括无效字符、不可打印字符、私用区字符、方框和常见的编码错误字符
+func garbledRatio(text string) float64 {
+	total, garbled := 0, 0
+	for _, r := range text {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		total++
+		switch {
+		case r == utf8.RuneError, !unicode.IsPrint(r): // 包括私用区字符
+			garbled++
+		case r == '□' || r == '■':
+			garbled++
+		case r == 'Ã' || r == 'Â' || r == 'â':
+			// UTF-8 文本按 Latin-1 解码时出现的字符
+			garbled++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(garbled) / float64(total)
+}
+
+// medianInt 计算中位数，没有数据时返回0
+func medianInt(values []int) int {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]int{}, values...)
+	sort.Ints(sorted)
+	return sorted[len(sorted)/2]
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"strings"
+
+	"pdf-ocr-ai/pkg/bookmarks"
+	"pdf-ocr-ai/pkg/i18n"
+)
+
+// SetPageBookmark 为当前文档的页面添加书签或修改书签名称，label 为空时使用“第N页”
+func (a *App) SetPageBookmark(pageNumber int, label string) error {
+	session := a.activeSession()
+	if session == nil {
+		return i18n.Errorf("doc.not_loaded")
+	}
+	if pageNumber < 1 || pageNumber > len(session.Doc.Pages) {
+		return i18n.Errorf("page.out_of_range")
+	}
+
+	label = strings.TrimSpace(label)
+	if label == "" {
+		label = i18n.T("page.label", pageNumber)
+	}
+	if err := a.bookmarkManager.Set(session.ID, pageNumber, label); err != nil {
+		return err
+	}
+	a.emit("bookmarks-updated", map[string]interface{}{
+		"document_id": session.ID,
+	})
+	return nil
+}
+
+// RemovePageBookmark 删除当前文档页面的书签
+func (a *App) RemovePageBookmark(pageNumber int) error {
+	session := a.activeSession()
+	if session == nil {
+		return i18n.Errorf("doc.not_loaded")
+	}
+	if err := a.bookmarkManager.Remove(session.ID, pageNumber); err != nil {
+		return err
+	}
+	a.emit("bookmarks-updated", map[string]interface{}{
+		"document_id": session.ID,
+	})
+	return nil
+}
+
+// ListBookmarks 获取当前文档的书签（按页码排序）
+func (a *App) ListBookmarks() ([]bookmarks.Bookmark, error) {
+	session := a.activeSession()
+	if session == nil {
+		return []bookmarks.Bookmark{}, nil
+	}
+	return a.bookmarkManager.List(session.ID)
+}
+
+// GetBookmarkedPages 获取当前文档所有书签页的页码，用作处理或导出的页面选择
+func (a *App) GetBookmarkedPages() ([]int, error) {
+	list, err := a.ListBookmarks()
+	if err != nil {
+		return nil, err
+	}
+	pages := make([]int, 0, len(list))
+	for _, bookmark := range list {
+		pages = append(pages, bookmark.PageNumber)
+	}
+	return pages, nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+
+	"pdf-ocr-ai/pkg/cache"
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// GetCacheStats 获取缓存统计（文档数、页面数、磁盘占用和启动以来的命中情况）
+func (a *App) GetCacheStats() (*cache.CacheStats, error) {
+	if a.cacheManager == nil {
+		return nil, i18n.Errorf("init.component")
+	}
+	return a.cacheManager.Stats()
+}
+
+// ClearDocumentCache 清除指定文档的缓存（不影响历史记录），文档正在处理时拒绝清除
+func (a *App) ClearDocumentCache(documentID string) (*cache.ClearResult, error) {
+	if a.cacheManager == nil || a.store == nil {
+		return nil, i18n.Errorf("init.component")
+	}
+	if session, err := a.getSession(documentID); err == nil && session.getState() != ProcessingStateIdle {
+		return nil, fmt.Errorf("文档正在处理中，请等待处理完成后再清除缓存")
+	}
+
+	return a.clearCache(func() (*cache.ClearResult, error) {
+		return a.cacheManager.ClearDocument(documentID)
+	})
+}
+
+// ClearAllCache 清除全部缓存（不影响历史记录），有文档正在处理时拒绝清除
+func (a *App) ClearAllCache() (*cache.ClearResult, error) {
+	if a.cacheManager == nil || a.store == nil {
+		return nil, i18n.Errorf("init.component")
+	}
+	if err := a.ensureNoActiveProcessing(); err != nil {
+		return nil, err
+	}
+
+	return a.clearCache(a.cacheManager.ClearAll)
+}
+
+// clearCache 执行清除并压缩数据库，释放磁盘空间后发送 cache-cleared 事件
+func (a *App) clearCache(clear func() (*cache.ClearResult, error)) (*cache.ClearResult, error) {
+	if !maintenanceMu.TryLock() {
+		return nil, fmt.Errorf("存储维护正在进行中，请稍后再试")
+	}
+	defer maintenanceMu.Unlock()
+
+	sizeBefore := a.store.FilesSize()
+	result, err := clear()
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Entries > 0 || result.Documents > 0 {
+		if err := a.store.Vacuum(); err != nil {
+			logger.Warnf("清除缓存后压缩数据库失败: %v", err)
+		}
+		if freed := sizeBefore - a.store.FilesSize(); freed > 0 {
+			result.FreedBytes = freed
+		}
+	}
+
+	logger.Infof("已清除缓存: %d 个文档, %d 页, 图片 %d 字节, 释放 %d 字节",
+		result.Documents, result.Entries, result.ImageBytes, result.FreedBytes)
+	a.emit("cache-cleared", result)
+	return result, nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/ocr"
+	"pdf-ocr-ai/pkg/outline"
+	"pdf-ocr-ai/pkg/pdf"
+)
+
+// chapterSourceMaxTokens 生成章节摘要时最多发送的章节文本长度，超过时截取开头部分
+const chapterSourceMaxTokens = 8000
+
+// chapterSummaryMaxRunes 章节摘要的目标长度（字）
+const chapterSummaryMaxRunes = 300
+
+// chapterContextPrompt 启用章节上下文时获取当前页所在章节的摘要提示，未启用、章节只有当前页或生成失败时返回空
+// 摘要按章节内容哈希缓存，同一章节只生成一次；摘要长度不超过 budget（tokens）
+func (a *App) chapterContextPrompt(ctx context.Context, doc *pdf.PDFDocument, pageNum, budget int) string {
+	if !a.configManager.GetAIConfig().ChapterContext || a.ocrClient == nil || a.summaryManager == nil || budget <= 0 {
+		return ""
+	}
+
+	texts := make([]string, len(doc.Pages))
+	for i, page := range doc.Pages {
+		texts[i] = page.OCRText
+		if texts[i] == "" {
+			texts[i] = page.Text
+		}
+	}
+	span, ok := outline.ChapterOf(texts, pageNum)
+	if !ok || span.Start == span.End {
+		return ""
+	}
+
+	summary, err := a.chapterSummary(ctx, doc, span, texts)
+	if err != nil {
+		logger.Warnf("获取第%d页所在章节的摘要失败: %v", pageNum, err)
+		return ""
+	}
+	if summary == "" {
+		return ""
+	}
+	if ocr.EstimateTokens(summary) > budget {
+		summary = ocr.HeadTokens(summary, budget)
+	}
+
+	title := span.Title
+	if title == "" {
+		title = i18n.T("prompt.chapter_untitled")
+	}
+	return i18n.T("prompt.chapter_summary", title, span.Start, span.End) + "\n" + summary + "\n\n"
+}
+
+// chapterSummary 获取章节摘要，缓存中没有或章节内容已变化时调用AI生成并保存
+func (a *App) chapterSummary(ctx context.Context, doc *pdf.PDFDocument, span outline.ChapterSpan, texts []string) (string, error) {
+	documentID, err := a.cacheManager.GenerateDocumentID(doc.FilePath)
+	if err != nil {
+		return "", i18n.Errorf("doc.id_failed", err)
+	}
+
+	content := strings.TrimSpace(strings.Join(texts[span.Start-1:span.End], "\n\n"))
+	if content == "" {
+		return "", nil
+	}
+	hash := sha256.Sum256([]byte(content))
+	contentHash := hex.EncodeToString(hash[:])
+
+	// 批量处理时多个页面可能同时需要同一章节的摘要，串行化以免重复生成
+	a.summaryMu.Lock()
+	defer a.summaryMu.Unlock()
+
+	summary, err := a.summaryManager.Get(documentID, span.Start, contentHash)
+	if err != nil || summary != "" {
+		return summary, err
+	}
+
+	logger.Infof("生成第%d-%d页章节摘要", span.Start, span.End)
+	if ocr.EstimateTokens(content) > chapterSourceMaxTokens {
+		content = ocr.HeadTokens(content, chapterSourceMaxTokens)
+	}
+	prompt := i18n.T("prompt.chapter_summarize", chapterSummaryMaxRunes)
+	summary, err = a.ocrClient.ProcessText(ctx, content, prompt)
+	if err != nil {
+		return "", i18n.Errorf("ai.failed_err", err)
+	}
+	summary = strings.TrimSpace(summary)
+
+	if err := a.summaryManager.Set(documentID, span.Start, contentHash, summary); err != nil {
+		logger.Warnf("保存章节摘要失败: %v", err)
+	}
+	return summary, nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/jpeg"
+	_ "image/png"
+	"time"
+
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/ocr"
+	"pdf-ocr-ai/pkg/system"
+)
+
+// OCRFromClipboard 识别剪贴板中的图片并直接返回文字（不创建文档）
+func (a *App) OCRFromClipboard() (string, error) {
+	if a.ocrClient == nil {
+		return "", i18n.Errorf("ai.not_configured")
+	}
+
+	pngData, err := system.ReadClipboardImage()
+	if err != nil {
+		return "", err
+	}
+
+	// 统一转为JPEG，与页面渲染结果的格式保持一致
+	img, _, err := image.Decode(bytes.NewReader(pngData))
+	if err != nil {
+		return "", fmt.Errorf("解码剪贴板图片失败: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90}); err != nil {
+		return "", fmt.Errorf("转换剪贴板图片失败: %w", err)
+	}
+
+	bounds := img.Bounds()
+	logger.Infof("剪贴板OCR: 图片尺寸 %dx%d", bounds.Dx(), bounds.Dy())
+
+	startTime := time.Now()
+	result, err := ocr.RecognizeImageFromReader(context.Background(), a.ocrClient, &buf)
+	if err != nil {
+		return "", fmt.Errorf("识别剪贴板图片失败: %w", err)
+	}
+
+	logger.Infof("剪贴板OCR完成，耗时 %v", time.Since(startTime))
+	return result.Text, nil
+}
+
+// runClipboardOCRShortcut 快捷键触发的剪贴板OCR，结果通过事件发送给前端
+func (a *App) runClipboardOCRShortcut() {
+	defer logger.RecoverPanic("runClipboardOCRShortcut")
+
+	a.emit("clipboard-ocr-started", map[string]interface{}{})
+
+	text, err := a.OCRFromClipboard()
+	if err != nil {
+		a.emit("clipboard-ocr-result", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	a.emit("clipboard-ocr-result", map[string]interface{}{
+		"text": text,
+	})
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// configWatchInterval 检查配置文件是否被外部修改的间隔
+const configWatchInterval = 2 * time.Second
+
+// applyConfigMu 防止界面保存和外部修改同时应用配置
+var applyConfigMu sync.Mutex
+
+// startConfigWatch 定期检查 config.json，外部编辑后重新加载并在运行时生效
+func (a *App) startConfigWatch() {
+	stop := make(chan struct{})
+	a.mu.Lock()
+	a.configWatchStop = stop
+	a.mu.Unlock()
+
+	go func() {
+		defer logger.RecoverPanic("监视配置文件")
+
+		ticker := time.NewTicker(configWatchInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				a.reloadConfigFile()
+			}
+		}
+	}()
+}
+
+// stopConfigWatch 停止监视配置文件
+func (a *App) stopConfigWatch() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.configWatchStop != nil {
+		close(a.configWatchStop)
+		a.configWatchStop = nil
+	}
+}
+
+// reloadConfigFile 配置文件有变化时重新加载，并发送 config-changed 事件
+func (a *App) reloadConfigFile() {
+	changed, err := a.configManager.ReloadIfChanged(validateConfig)
+	if err != nil {
+		logger.Warnf("配置文件已修改但未能加载，继续使用当前配置: %v", err)
+		a.emit("config-changed", map[string]interface{}{
+			"source": "file",
+			"error":  err.Error(),
+		})
+		return
+	}
+	if !changed {
+		return
+	}
+
+	cfg := a.configManager.GetConfig()
+	a.removeMissingProfiles(cfg.Profiles)
+	if err := a.applyConfig(cfg); err != nil {
+		logger.Warnf("应用外部修改的配置失败: %v", err)
+	} else {
+		logger.Infof("已重新加载外部修改的配置文件")
+	}
+
+	// 事件也会推送给API服务的订阅者，不携带配置内容（其中包含API密钥），前端收到后重新获取配置
+	a.emit("config-changed", map[string]interface{}{
+		"source": "file",
+	})
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/system"
+)
+
+// rateLimitNotifyInterval 限流通知的最小间隔，避免连续重试时刷屏
+const rateLimitNotifyInterval = 5 * time.Minute
+
+var (
+	rateLimitNotifyMu   sync.Mutex
+	lastRateLimitNotify time.Time
+)
+
+// notifyDesktop 按配置发送系统通知和提示音
+func (a *App) notifyDesktop(title, message string, failed bool) {
+	if a.configManager == nil {
+		return
+	}
+
+	cfg := a.configManager.GetConfig().Notifications
+	if !cfg.Enabled {
+		return
+	}
+
+	go func() {
+		if err := system.SendDesktopNotification(title, message); err != nil {
+			logger.Errorf("发送桌面通知失败: %v", err)
+		}
+		if cfg.Sound {
+			if err := system.PlayNotificationSound(failed); err != nil {
+				logger.Errorf("播放提示音失败: %v", err)
+			}
+		}
+	}()
+}
+
+// notifyBatchFinished 批处理结束时发送桌面通知
+func (a *App) notifyBatchFinished(summary BatchSummary) {
+	name := filepath.Base(summary.DocumentPath)
+	duration := time.Duration(summary.DurationSeconds * float64(time.Second)).Round(time.Second)
+
+	switch summary.Event {
+	case "batch.cancelled":
+		// 用户主动取消，不打扰
+		return
+	case "batch.failed":
+		a.notifyDesktop("pdfSeer 处理失败",
+			fmt.Sprintf("%s: %d 页全部处理失败", name, len(summary.Pages)), true)
+	default:
+		message := fmt.Sprintf("%s: 成功 %d 页，用时 %v", name, summary.SuccessCount, duration)
+		if summary.FailedCount > 0 {
+			message += fmt.Sprintf("，失败 %d 页", summary.FailedCount)
+		}
+		a.notifyDesktop("pdfSeer 处理完成", message, summary.FailedCount > 0)
+	}
+}
+
+// onRateLimited API限流等待重试时通知前端，并按配置发送桌面通知
+func (a *App) onRateLimited(err error, delay time.Duration) {
+	a.emit("rate-limited", map[string]interface{}{
+		"error": err.Error(),
+		"delay": delay.Seconds(),
+	})
+
+	if a.configManager == nil || !a.configManager.GetConfig().Notifications.OnRateLimit {
+		return
+	}
+
+	rateLimitNotifyMu.Lock()
+	if time.Since(lastRateLimitNotify) < rateLimitNotifyInterval {
+		rateLimitNotifyMu.Unlock()
+		return
+	}
+	lastRateLimitNotify = time.Now()
+	rateLimitNotifyMu.Unlock()
+
+	a.notifyDesktop("pdfSeer 已暂缓请求",
+		fmt.Sprintf("AI服务限流，%v 后自动重试", delay.Round(time.Second)), false)
+}
+
+// TestDesktopNotification 发送一条测试通知
+func (a *App) TestDesktopNotification() error {
+	if err := system.SendDesktopNotification("pdfSeer", "桌面通知测试"); err != nil {
+		return err
+	}
+	if a.configManager.GetConfig().Notifications.Sound {
+		return system.PlayNotificationSound(false)
+	}
+	return nil
+}
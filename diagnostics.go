@@ -0,0 +1,249 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/migrate"
+	"pdf-ocr-ai/pkg/system"
+)
+
+// maxErrorEvents 诊断包中保留的最近错误事件数
+const maxErrorEvents = 100
+
+// ErrorEvent 发送到前端的错误事件
+type ErrorEvent struct {
+	Time  time.Time   `json:"time"`
+	Event string      `json:"event"`
+	Data  interface{} `json:"data"`
+}
+
+// errorEventLog 最近错误事件的环形记录
+type errorEventLog struct {
+	mu     sync.Mutex
+	events []ErrorEvent
+}
+
+// record 记录名称中包含 error 或 failed 的事件
+func (l *errorEventLog) record(eventName string, data interface{}) {
+	if !strings.Contains(eventName, "error") && !strings.Contains(eventName, "failed") {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.events) == maxErrorEvents {
+		l.events = l.events[1:]
+	}
+	l.events = append(l.events, ErrorEvent{Time: time.Now(), Event: eventName, Data: data})
+}
+
+// snapshot 获取已记录的错误事件副本
+func (l *errorEventLog) snapshot() []ErrorEvent {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]ErrorEvent{}, l.events...)
+}
+
+// SchemaInfo 数据库结构信息
+type SchemaInfo struct {
+	File        string            `json:"file"`
+	UserVersion int               `json:"user_version"`
+	Versions    map[string]int    `json:"versions,omitempty"` // 各组件的迁移版本
+	Tables      map[string]string `json:"tables"`             // 表名 -> 建表语句
+	Error       string            `json:"error,omitempty"`
+}
+
+// ExportDiagnostics 将日志、依赖报告、脱敏后的配置、数据库结构和最近错误打包为zip，返回保存路径
+func (a *App) ExportDiagnostics() (string, error) {
+	filePath, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		DefaultFilename: fmt.Sprintf("pdfseer-diagnostics-%s.zip", time.Now().Format("20060102-150405")),
+		Filters:         []runtime.FileFilter{{DisplayName: "ZIP 文件", Pattern: "*.zip"}},
+		Title:           "导出诊断信息",
+	})
+	if err != nil {
+		return "", err
+	}
+	if filePath == "" {
+		// 用户取消了保存
+		return "", nil
+	}
+
+	if err := a.writeDiagnosticsBundle(filePath); err != nil {
+		os.Remove(filePath)
+		return "", err
+	}
+
+	logger.Infof("诊断信息已导出: %s", filePath)
+	return filePath, nil
+}
+
+// writeDiagnosticsBundle 写入诊断包
+func (a *App) writeDiagnosticsBundle(filePath string) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("创建诊断文件失败: %w", err)
+	}
+	defer file.Close()
+
+	zw := zip.NewWriter(file)
+
+	info := GetAppInfo()
+	summary := fmt.Sprintf("应用: %s\n版本: %s\n系统: %s/%s\nGo: %s\n导出时间: %s\n",
+		info["name"], info["version"], goruntime.GOOS, goruntime.GOARCH,
+		goruntime.Version(), time.Now().Format(time.RFC3339))
+	if err := writeZipBytes(zw, "summary.txt", []byte(summary)); err != nil {
+		return err
+	}
+
+	report := system.FormatDependencyReport(system.CheckDependencies())
+	if err := writeZipBytes(zw, "dependencies.txt", []byte(report)); err != nil {
+		return err
+	}
+
+	if a.configManager != nil {
+		cfg := a.configManager.GetConfig()
+		cfg.AI.APIKey = redactSecret(cfg.AI.APIKey)
+		cfg.APIServer.Token = redactSecret(cfg.APIServer.Token)
+		cfg.Webhook.Secret = redactSecret(cfg.Webhook.Secret)
+		cfg.Integrations.Notion.Token = redactSecret(cfg.Integrations.Notion.Token)
+		cfg.Integrations.Paperless.Token = redactSecret(cfg.Integrations.Paperless.Token)
+		cfg.RemoteUpload.Password = redactSecret(cfg.RemoteUpload.Password)
+		cfg.RemoteUpload.SecretKey = redactSecret(cfg.RemoteUpload.SecretKey)
+		if err := writeZipJSON(zw, "config.json", cfg); err != nil {
+			return err
+		}
+	}
+
+	if err := writeZipJSON(zw, "schema.json", collectSchemaInfo()); err != nil {
+		return err
+	}
+
+	if err := writeZipJSON(zw, "recent_errors.json", a.errorEvents.snapshot()); err != nil {
+		return err
+	}
+
+	// 日志文件（含轮转的历史日志）
+	logger.Sync()
+	if dir := logger.Dir(); dir != "" {
+		entries, _ := os.ReadDir(dir)
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			if err := writeZipFile(zw, "logs/"+entry.Name(), filepath.Join(dir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("写入诊断文件失败: %w", err)
+	}
+	return nil
+}
+
+// collectSchemaInfo 读取 ~/.pdfSeer 下各数据库的版本和表结构
+func collectSchemaInfo() []SchemaInfo {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+
+	paths, _ := filepath.Glob(filepath.Join(homeDir, ".pdfSeer", "*.db"))
+	result := make([]SchemaInfo, 0, len(paths))
+	for _, path := range paths {
+		info := SchemaInfo{File: filepath.Base(path), Tables: make(map[string]string)}
+
+		db, err := sqlx.Open("sqlite3", "file:"+path+"?mode=ro")
+		if err != nil {
+			info.Error = err.Error()
+			result = append(result, info)
+			continue
+		}
+
+		if err := db.Get(&info.UserVersion, "PRAGMA user_version"); err != nil {
+			info.Error = err.Error()
+		}
+		if versions, err := migrate.Versions(db); err == nil {
+			info.Versions = versions
+		}
+
+		var tables []struct {
+			Name string `db:"name"`
+			SQL  string `db:"sql"`
+		}
+		if err := db.Select(&tables, "SELECT name, COALESCE(sql, '') AS sql FROM sqlite_master WHERE type = 'table'"); err != nil {
+			info.Error = err.Error()
+		}
+		for _, table := range tables {
+			info.Tables[table.Name] = table.SQL
+		}
+
+		db.Close()
+		result = append(result, info)
+	}
+	return result
+}
+
+// redactSecret 隐藏敏感信息，只保留末4位便于核对
+func redactSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	if len(secret) <= 8 {
+		return "***"
+	}
+	return "***" + secret[len(secret)-4:]
+}
+
+// writeZipBytes 向zip写入内容
+func writeZipBytes(zw *zip.Writer, name string, data []byte) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("写入 %s 失败: %w", name, err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("写入 %s 失败: %w", name, err)
+	}
+	return nil
+}
+
+// writeZipJSON 以JSON格式向zip写入内容
+func writeZipJSON(zw *zip.Writer, name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化 %s 失败: %w", name, err)
+	}
+	return writeZipBytes(zw, name, data)
+}
+
+// writeZipFile 将磁盘文件写入zip
+func writeZipFile(zw *zip.Writer, name, path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("读取 %s 失败: %w", path, err)
+	}
+	defer src.Close()
+
+	w, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("写入 %s 失败: %w", name, err)
+	}
+	if _, err := io.Copy(w, src); err != nil {
+		return fmt.Errorf("写入 %s 失败: %w", name, err)
+	}
+	return nil
+}
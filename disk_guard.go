@@ -0,0 +1,160 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"pdf-ocr-ai/pkg/config"
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/system"
+)
+
+// diskCheckInterval 检查磁盘可用空间的间隔
+const diskCheckInterval = 5 * time.Minute
+
+// defaultRenderedPageBytes 图片缓存为空、无法统计时估算的单页渲染图片大小
+const defaultRenderedPageBytes int64 = 512 << 10
+
+// DiskSpaceStatus 渲染目录或数据目录所在磁盘的空间状态
+type DiskSpaceStatus struct {
+	Name      string `json:"name"`       // 目录用途
+	Path      string `json:"path"`       // 目录路径
+	FreeBytes uint64 `json:"free_bytes"` // 可用空间
+	MinBytes  int64  `json:"min_bytes"`  // 提示阈值，0 表示不检查
+	Low       bool   `json:"low"`        // 可用空间低于阈值
+	Error     string `json:"error,omitempty"`
+}
+
+// lowDiskDirs 已发出空间不足提示的目录，恢复之前不重复提示
+var (
+	lowDiskMu   sync.Mutex
+	lowDiskDirs = make(map[string]bool)
+)
+
+// startDiskMonitor 启动时检查一次磁盘空间，之后定期检查
+func (a *App) startDiskMonitor() {
+	stop := make(chan struct{})
+	a.mu.Lock()
+	a.diskMonitorStop = stop
+	a.mu.Unlock()
+
+	go func() {
+		defer logger.RecoverPanic("磁盘空间监视")
+
+		a.checkDiskSpace()
+
+		ticker := time.NewTicker(diskCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				a.checkDiskSpace()
+			}
+		}
+	}()
+}
+
+// stopDiskMonitor 停止监视磁盘空间
+func (a *App) stopDiskMonitor() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.diskMonitorStop != nil {
+		close(a.diskMonitorStop)
+		a.diskMonitorStop = nil
+	}
+}
+
+// GetDiskSpace 获取渲染临时目录和数据目录所在磁盘的空间状态
+func (a *App) GetDiskSpace() []DiskSpaceStatus {
+	if a.pdfProcessor == nil || a.store == nil {
+		return nil
+	}
+
+	minBytes := a.minFreeSpace()
+	dirs := []struct{ name, path string }{
+		{i18n.T("disk.temp_dir"), a.pdfProcessor.TempDir()},
+		{i18n.T("disk.data_dir"), a.store.DataDir()},
+	}
+
+	statuses := make([]DiskSpaceStatus, 0, len(dirs))
+	for _, dir := range dirs {
+		status := DiskSpaceStatus{Name: dir.name, Path: dir.path, MinBytes: minBytes}
+		free, err := system.FreeDiskSpace(dir.path)
+		if err != nil {
+			status.Error = err.Error()
+		} else {
+			status.FreeBytes = free
+			status.Low = minBytes > 0 && free < uint64(minBytes)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// checkDiskSpace 检查磁盘空间，目录可用空间刚低于阈值时提示前端
+func (a *App) checkDiskSpace() {
+	for _, status := range a.GetDiskSpace() {
+		if status.Error != "" {
+			logger.Warnf("获取%s磁盘空间失败: %s", status.Name, status.Error)
+			continue
+		}
+
+		lowDiskMu.Lock()
+		notify := status.Low && !lowDiskDirs[status.Path]
+		lowDiskDirs[status.Path] = status.Low
+		lowDiskMu.Unlock()
+
+		if notify {
+			message := i18n.T("disk.low", status.Name, system.FormatDiskSize(status.FreeBytes), system.FormatDiskSize(uint64(status.MinBytes)))
+			logger.Warnf("%s (%s)", message, status.Path)
+			a.emit("disk-space-warning", map[string]interface{}{
+				"name":       status.Name,
+				"path":       status.Path,
+				"free_bytes": status.FreeBytes,
+				"min_bytes":  status.MinBytes,
+				"message":    message,
+			})
+		}
+	}
+}
+
+// minFreeSpace 获取配置的磁盘空间提示阈值（字节），未配置或无效时返回0
+func (a *App) minFreeSpace() int64 {
+	value := a.configManager.GetConfig().Storage.MinFreeSpace
+	if value == "" {
+		return 0
+	}
+	size, err := config.ParseByteSize(value)
+	if err != nil {
+		logger.Warnf("磁盘空间阈值配置无效，不检查磁盘空间: %v", err)
+		return 0
+	}
+	return size
+}
+
+// estimateRenderedPageBytes 按图片缓存中已有图片的平均大小估算单页渲染图片大小
+func (a *App) estimateRenderedPageBytes() int64 {
+	if a.cacheManager != nil {
+		if stats := a.cacheManager.Images().Stats(); stats.Files > 0 && stats.Bytes > 0 {
+			return stats.Bytes / int64(stats.Files)
+		}
+	}
+	return defaultRenderedPageBytes
+}
+
+// checkBatchDiskSpace 估算批量处理需要的磁盘空间（页数 × 平均渲染图片大小），超过可用空间时拒绝开始
+func (a *App) checkBatchDiskSpace(pageCount int) error {
+	required := int64(pageCount) * a.estimateRenderedPageBytes()
+	for _, status := range a.GetDiskSpace() {
+		if status.Error != "" {
+			continue
+		}
+		if uint64(required) > status.FreeBytes {
+			return i18n.Errorf("disk.batch_insufficient", status.Name, pageCount, system.FormatDiskSize(uint64(required)), system.FormatDiskSize(status.FreeBytes))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"pdf-ocr-ai/pkg/document"
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// 文档转换的目标格式
+const (
+	ConvertTargetImages   = "images"   // PDF、DjVu等文档每页渲染为一张图片
+	ConvertTargetPDF      = "pdf"      // 图片和PDF按顺序合并为一个PDF
+	ConvertTargetMarkdown = "markdown" // DOCX转为Markdown
+)
+
+// ConvertOptions 文档转换选项
+type ConvertOptions struct {
+	Output      string `json:"output"`       // 输出路径，为空时输出到输入文件所在目录
+	ImageFormat string `json:"image_format"` // 转为图片时的格式: jpg（默认）或 png
+	DPI         int    `json:"dpi"`          // 转为图片时的分辨率，0 表示默认
+}
+
+// ConvertResult 文档转换结果
+type ConvertResult struct {
+	Outputs []string          `json:"outputs"`          // 生成的文件或目录
+	Failed  map[string]string `json:"failed,omitempty"` // 转换失败的输入文件及原因
+}
+
+// ConvertDocument 不经过OCR和AI，直接用现有的处理器转换文档，不打开文档也不影响当前会话
+// images: 每个输入文件的页面图片输出到 output（单个文件时可以是 .zip）或 <文件名>_images 目录
+// pdf: 全部输入按顺序合并为一个PDF，output 为空时保存为第一个文件同名的 .pdf
+// markdown: 每个DOCX文件转换为同名的 .md 文件，单个文件时 output 可以是 .md 文件
+// 多个输入文件转为图片或Markdown时 output 作为输出目录
+func (a *App) ConvertDocument(inputs []string, targetFormat string, options ConvertOptions) (*ConvertResult, error) {
+	inputs = expandIntakePaths(inputs)
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("没有需要转换的文件")
+	}
+
+	result := &ConvertResult{Outputs: []string{}, Failed: map[string]string{}}
+	switch strings.ToLower(targetFormat) {
+	case ConvertTargetPDF:
+		output, err := convertOutputPath(options.Output, inputs[0], ".pdf", true)
+		if err != nil {
+			return nil, err
+		}
+		for _, input := range inputs {
+			if !isAppendable(input) {
+				return nil, fmt.Errorf("只能将图片和PDF合并为PDF: %s", filepath.Base(input))
+			}
+			if sameFile(input, output) {
+				return nil, fmt.Errorf("输出文件不能与输入文件相同: %s", output)
+			}
+		}
+		if err := mergeIntoPDF(inputs, output); err != nil {
+			return nil, err
+		}
+		result.Outputs = append(result.Outputs, output)
+	case ConvertTargetImages:
+		format := strings.ToLower(options.ImageFormat)
+		switch format {
+		case "", "jpeg":
+			format = "jpg"
+		case "jpg", "png":
+		default:
+			return nil, fmt.Errorf("不支持的图片格式: %s", options.ImageFormat)
+		}
+		a.convertEach(inputs, result, func(input string) (string, error) {
+			output, err := convertOutputPath(options.Output, input, "_images", len(inputs) == 1)
+			if err != nil {
+				return "", err
+			}
+			return output, a.convertToImages(input, output, format, options.DPI)
+		})
+	case ConvertTargetMarkdown:
+		a.convertEach(inputs, result, func(input string) (string, error) {
+			if !strings.EqualFold(filepath.Ext(input), ".docx") {
+				return "", fmt.Errorf("只支持将DOCX文件转换为Markdown")
+			}
+			output, err := convertOutputPath(options.Output, input, ".md", len(inputs) == 1)
+			if err != nil {
+				return "", err
+			}
+			markdown, err := document.DocxToMarkdown(input)
+			if err != nil {
+				return "", err
+			}
+			if err := os.WriteFile(output, []byte(markdown), 0644); err != nil {
+				return "", fmt.Errorf("保存Markdown文件失败: %w", err)
+			}
+			return output, nil
+		})
+	default:
+		return nil, fmt.Errorf("不支持的转换格式: %s", targetFormat)
+	}
+
+	logger.Infof("文档转换(%s): %d 个输出, %d 个失败", targetFormat, len(result.Outputs), len(result.Failed))
+	return result, nil
+}
+
+// convertEach 逐个转换输入文件并发送进度，失败的文件记录原因后继续
+func (a *App) convertEach(inputs []string, result *ConvertResult, convert func(input string) (string, error)) {
+	for i, input := range inputs {
+		a.emit("convert-progress", map[string]interface{}{
+			"current": i + 1,
+			"total":   len(inputs),
+			"file":    input,
+		})
+		output, err := convert(input)
+		if err != nil {
+			logger.Warnf("转换 %s 失败: %v", input, err)
+			result.Failed[input] = err.Error()
+			continue
+		}
+		result.Outputs = append(result.Outputs, output)
+	}
+}
+
+// convertToImages 将文档的每页渲染为图片，写入目录或ZIP文件
+func (a *App) convertToImages(input, output, format string, dpi int) error {
+	info, err := a.documentProcessor.GetDocumentInfo(input)
+	if err != nil {
+		return err
+	}
+	if !info.SupportedOCR {
+		return fmt.Errorf("该格式无法转为图片: %s", a.documentProcessor.GetFormatDescription(filepath.Ext(input)))
+	}
+	doc, err := a.documentProcessor.LoadDocument(input)
+	if err != nil {
+		return err
+	}
+
+	open, finish, err := openImageOutput(output)
+	if err != nil {
+		return err
+	}
+	baseName := strings.TrimSuffix(filepath.Base(input), filepath.Ext(input))
+	digits := len(fmt.Sprint(len(doc.Pages)))
+	for pageNum := 1; pageNum <= len(doc.Pages); pageNum++ {
+		name := fmt.Sprintf("%s_p%0*d.%s", baseName, digits, pageNum, format)
+		if err := a.exportPageImage(doc, pageNum, dpi, nil, format, name, open); err != nil {
+			finish()
+			return fmt.Errorf("第%d页: %w", pageNum, err)
+		}
+	}
+	return finish()
+}
+
+// convertOutputPath 确定输出路径：单个文件且 output 的扩展名为 suffix 时直接使用 output（转为图片时 output 是目录或ZIP文件），
+// 否则 output 作为目录（为空时使用输入文件所在目录），文件名为输入文件名加 suffix，且不覆盖已有文件
+func convertOutputPath(output, input, suffix string, single bool) (string, error) {
+	if single && output != "" && (suffix == "_images" || strings.EqualFold(filepath.Ext(output), suffix)) {
+		return output, nil
+	}
+
+	dir := output
+	if dir == "" {
+		dir = filepath.Dir(input)
+	} else if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", i18n.Errorf("file.mkdir_failed", err)
+	}
+	path := filepath.Join(dir, strings.TrimSuffix(filepath.Base(input), filepath.Ext(input))+suffix)
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("输出文件已存在: %s", path)
+	}
+	return path, nil
+}
+
+// sameFile 判断两个路径是否指向同一个文件
+func sameFile(a, b string) bool {
+	infoA, errA := os.Stat(a)
+	infoB, errB := os.Stat(b)
+	return errA == nil && errB == nil && os.SameFile(infoA, infoB)
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"pdf-ocr-ai/pkg/document"
+	"pdf-ocr-ai/pkg/email"
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/pdf"
+)
+
+// EmailAttachment 邮件附件，PDF和图片附件解压后可作为单独的文档识别
+type EmailAttachment struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Size        int    `json:"size"`
+	FilePath    string `json:"file_path,omitempty"` // 解压后的路径，不能识别的附件为空
+	Supported   bool   `json:"supported"`
+}
+
+// GetEmailAttachments 获取邮件文档的附件（documentID 为空时使用当前文档），PDF和图片附件会解压到临时目录
+func (a *App) GetEmailAttachments(documentID string) ([]EmailAttachment, error) {
+	session, err := a.emailSession(documentID)
+	if err != nil {
+		return nil, err
+	}
+	return a.extractEmailAttachments(session.Doc.FilePath)
+}
+
+// OpenEmailAttachments 将邮件文档中的PDF和图片附件作为关联文档打开或加入队列（action 与 IntakeFiles 相同）
+func (a *App) OpenEmailAttachments(documentID string, action string) (*IntakeSummary, error) {
+	attachments, err := a.GetEmailAttachments(documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, attachment := range attachments {
+		if attachment.Supported {
+			paths = append(paths, attachment.FilePath)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("邮件中没有可识别的附件")
+	}
+	return a.IntakeFiles(paths, action)
+}
+
+// emailSession 按文档ID获取邮件文档的会话
+func (a *App) emailSession(documentID string) (*DocumentSession, error) {
+	session := a.activeSession()
+	if documentID != "" {
+		var err error
+		if session, err = a.getSession(documentID); err != nil {
+			return nil, err
+		}
+	}
+	if session == nil {
+		return nil, i18n.Errorf("doc.not_loaded")
+	}
+	if docType, _ := a.documentProcessor.GetDocumentType(session.Doc.FilePath); docType != document.TypeEmail {
+		return nil, fmt.Errorf("当前文档不是邮件")
+	}
+	return session, nil
+}
+
+// extractEmailAttachments 解析邮件附件，将PDF和图片附件写入该邮件的解压目录（已解压的不重复写入）
+func (a *App) extractEmailAttachments(emlPath string) ([]EmailAttachment, error) {
+	msg, err := email.ParseFile(emlPath)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments := make([]EmailAttachment, 0, len(msg.Attachments))
+	var dir string
+	for i, item := range msg.Attachments {
+		attachment := EmailAttachment{
+			Name:        item.Name,
+			ContentType: item.ContentType,
+			Size:        len(item.Data),
+			Supported:   isOCRAttachment(item.Name),
+		}
+		if attachment.Supported {
+			if dir == "" {
+				if dir, err = a.emailImportDir(emlPath); err != nil {
+					return nil, err
+				}
+			}
+			attachment.FilePath = filepath.Join(dir, fmt.Sprintf("%02d_%s", i+1, item.Name))
+			if _, err := os.Stat(attachment.FilePath); err != nil {
+				if err := os.WriteFile(attachment.FilePath, item.Data, 0644); err != nil {
+					return nil, fmt.Errorf("保存附件 %s 失败: %w", item.Name, err)
+				}
+			}
+		}
+		attachments = append(attachments, attachment)
+	}
+	return attachments, nil
+}
+
+// emailImportDir 获取邮件附件的解压目录，没有时创建
+func (a *App) emailImportDir(emlPath string) (string, error) {
+	a.mu.RLock()
+	for dir, source := range a.importDirs {
+		if source == emlPath {
+			a.mu.RUnlock()
+			return dir, nil
+		}
+	}
+	a.mu.RUnlock()
+	return a.newImportDir(emlPath)
+}
+
+// isOCRAttachment 附件是否为可识别的PDF或图片
+func isOCRAttachment(name string) bool {
+	return strings.EqualFold(filepath.Ext(name), ".pdf") || pdf.IsImageFile(name)
+}
+
+// checkEmailAttachments 打开邮件时通知前端可识别的附件
+func (a *App) checkEmailAttachments(session *DocumentSession) {
+	if docType, _ := a.documentProcessor.GetDocumentType(session.Doc.FilePath); docType != document.TypeEmail {
+		return
+	}
+
+	attachments, err := a.extractEmailAttachments(session.Doc.FilePath)
+	if err != nil {
+		logger.Warnf("读取邮件附件失败: %v", err)
+		return
+	}
+	var supported []EmailAttachment
+	for _, attachment := range attachments {
+		if attachment.Supported {
+			supported = append(supported, attachment)
+		}
+	}
+	if len(supported) == 0 {
+		return
+	}
+
+	logger.Infof("邮件 %s 有 %d 个可识别的附件", session.Doc.FilePath, len(supported))
+	a.emit("email-attachments", map[string]interface{}{
+		"document_id": session.ID,
+		"attachments": supported,
+	})
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+
+	"pdf-ocr-ai/pkg/encryption"
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// GetEncryptionStatus 获取缓存和历史记录文本的加密状态
+func (a *App) GetEncryptionStatus() (encryption.Status, error) {
+	if a.encryption == nil {
+		return encryption.Status{}, i18n.Errorf("init.component")
+	}
+	return a.encryption.Status(), nil
+}
+
+// EnableEncryption 启用静态数据加密并加密已有的缓存和历史记录文本
+// mode 为 passphrase（使用密码，每次启动需解锁）或 keychain（密钥保存在系统钥匙串）
+func (a *App) EnableEncryption(mode string, passphrase string) error {
+	if a.encryption == nil || a.store == nil || a.cacheManager == nil || a.historyManager == nil {
+		return i18n.Errorf("init.component")
+	}
+	if err := a.ensureNoActiveProcessing(); err != nil {
+		return err
+	}
+
+	if err := a.encryption.Enable(encryption.Mode(mode), passphrase); err != nil {
+		return err
+	}
+
+	rewritten, err := a.store.RewriteEncryptedColumns(true)
+	if err != nil {
+		// 加密已有数据失败时撤销启用（事务已回滚，数据仍为明文）
+		a.encryption.Disable()
+		return fmt.Errorf("加密已有数据失败: %w", err)
+	}
+	if err := a.historyManager.RefreshSearchIndex(); err != nil {
+		logger.Warnf("清空搜索索引失败: %v", err)
+	}
+	// 渲染图片无法按字段加密，启用加密后不再保留在磁盘上
+	if _, err := a.cacheManager.Images().Clear(); err != nil {
+		logger.Warnf("清除图片缓存失败: %v", err)
+	}
+
+	logger.Infof("已启用数据加密（%s），加密 %d 条记录", mode, rewritten)
+	a.emit("encryption-changed", a.encryption.Status())
+	return nil
+}
+
+// UnlockEncryption 使用密码解锁加密数据
+func (a *App) UnlockEncryption(passphrase string) error {
+	if a.encryption == nil {
+		return i18n.Errorf("init.component")
+	}
+	if err := a.encryption.Unlock(passphrase); err != nil {
+		return err
+	}
+
+	logger.Infof("加密数据已解锁")
+	a.emit("encryption-changed", a.encryption.Status())
+	return nil
+}
+
+// LockEncryption 清除内存中的密钥，之后读写缓存和历史记录文本需要重新解锁
+func (a *App) LockEncryption() error {
+	if a.encryption == nil || !a.encryption.Enabled() {
+		return fmt.Errorf("未启用数据加密")
+	}
+	a.encryption.Lock()
+
+	logger.Infof("加密数据已锁定")
+	a.emit("encryption-changed", a.encryption.Status())
+	return nil
+}
+
+// DisableEncryption 关闭静态数据加密，将缓存和历史记录文本解密为明文（密码模式需验证密码）
+func (a *App) DisableEncryption(passphrase string) error {
+	if a.encryption == nil || a.store == nil || a.historyManager == nil {
+		return i18n.Errorf("init.component")
+	}
+	if !a.encryption.Enabled() {
+		return nil
+	}
+	if err := a.ensureNoActiveProcessing(); err != nil {
+		return err
+	}
+	if err := a.encryption.Unlock(passphrase); err != nil {
+		return err
+	}
+
+	rewritten, err := a.store.RewriteEncryptedColumns(false)
+	if err != nil {
+		return fmt.Errorf("解密已有数据失败: %w", err)
+	}
+	if err := a.encryption.Disable(); err != nil {
+		return err
+	}
+	if err := a.historyManager.RefreshSearchIndex(); err != nil {
+		logger.Warnf("重建搜索索引失败: %v", err)
+	}
+
+	logger.Infof("已关闭数据加密，解密 %d 条记录", rewritten)
+	a.emit("encryption-changed", a.encryption.Status())
+	return nil
+}
+
+// notifyEncryptionLocked 启动时加密数据尚未解锁则通知前端输入密码
+func (a *App) notifyEncryptionLocked() {
+	if a.encryption == nil {
+		return
+	}
+	if status := a.encryption.Status(); status.Enabled && !status.Unlocked {
+		a.emit("encryption-locked", status)
+	}
+}
+
+// ensureNoActiveProcessing 转换加密数据前确认没有正在进行的批量处理，避免处理中写入的数据未被转换
+func (a *App) ensureNoActiveProcessing() error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	for _, session := range a.sessions {
+		if session.getState() != ProcessingStateIdle {
+			return fmt.Errorf("文档 %s 正在处理中，请等待处理完成后再操作", session.Doc.Title)
+		}
+	}
+	return nil
+}
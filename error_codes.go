@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	openai "github.com/sashabaranov/go-openai"
+
+	"pdf-ocr-ai/pkg/encryption"
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/ocr"
+)
+
+// 错误事件的错误码，前端根据错误码显示对应的处理建议
+const (
+	ErrCodeDocumentNotLoaded = "DOCUMENT_NOT_LOADED" // 文档未加载或已关闭
+	ErrCodeDocLocked         = "DOC_LOCKED"          // 数据已加密且未解锁
+	ErrCodeDocumentBusy      = "DOC_BUSY"            // 文档已有批次在运行
+	ErrCodeAINotConfigured   = "AI_NOT_CONFIGURED"   // 未配置AI服务
+	ErrCodeAIAuth            = "AI_AUTH"             // API密钥无效或无权限
+	ErrCodeAIRateLimit       = "AI_RATE_LIMIT"       // 请求过于频繁或额度不足
+	ErrCodeAINetwork         = "AI_NETWORK"          // 连接AI服务失败或超时
+	ErrCodeAIServer          = "AI_SERVER"           // AI服务返回服务器错误
+	ErrCodeAIFailed          = "AI_FAILED"           // 其他AI处理错误
+	ErrCodeModelNoVision     = "MODEL_NO_VISION"     // 模型不支持图片识别
+	ErrCodeRenderFailed      = "RENDER_FAILED"       // 页面渲染或预处理失败
+	ErrCodeCacheIO           = "CACHE_IO"            // 读写缓存或数据库失败
+	ErrCodeDiskSpaceLow      = "DISK_SPACE_LOW"      // 磁盘空间不足
+	ErrCodeNoPages           = "NO_PAGES"            // 没有可处理的页面
+	ErrCodeNoText            = "NO_TEXT"             // 没有可处理的文本
+	ErrCodeInitFailed        = "INIT_FAILED"         // 组件初始化失败
+	ErrCodeIntakeFailed      = "INTAKE_FAILED"       // 导入文件失败
+	ErrCodeUnknown           = "UNKNOWN"             // 未分类的错误
+)
+
+// 错误事件建议的操作，前端据此显示操作按钮
+const (
+	ErrActionReloadDocument = "RELOAD_DOCUMENT"
+	ErrActionOpenSettings   = "OPEN_SETTINGS"
+	ErrActionUnlock         = "UNLOCK"
+	ErrActionRetry          = "RETRY"
+	ErrActionClearCache     = "CLEAR_CACHE"
+)
+
+// errorActions 各错误码对应的建议操作
+var errorActions = map[string]string{
+	ErrCodeDocumentNotLoaded: ErrActionReloadDocument,
+	ErrCodeDocLocked:         ErrActionUnlock,
+	ErrCodeAINotConfigured:   ErrActionOpenSettings,
+	ErrCodeAIAuth:            ErrActionOpenSettings,
+	ErrCodeAIRateLimit:       ErrActionRetry,
+	ErrCodeAINetwork:         ErrActionRetry,
+	ErrCodeAIServer:          ErrActionRetry,
+	ErrCodeModelNoVision:     ErrActionOpenSettings,
+	ErrCodeRenderFailed:      ErrActionRetry,
+	ErrCodeDiskSpaceLow:      ErrActionClearCache,
+	ErrCodeCacheIO:           ErrActionClearCache,
+}
+
+// EventError 错误事件的数据
+type EventError struct {
+	Code    string `json:"code"`
+	Error   string `json:"error"`             // 错误信息
+	Message string `json:"message,omitempty"` // 处理建议
+	Action  string `json:"action,omitempty"`  // 建议的操作
+	Page    int    `json:"page,omitempty"`    // 出错的页码
+	Model   string `json:"model,omitempty"`   // 相关的模型
+	TaskID  string `json:"task_id,omitempty"` // 所属的任务
+}
+
+// codedError 带错误码的错误，用于在返回链路中标记错误来源
+type codedError struct {
+	code string
+	err  error
+}
+
+func (e *codedError) Error() string { return e.err.Error() }
+func (e *codedError) Unwrap() error { return e.err }
+
+// withErrorCode 为错误标记错误码，err 为 nil 时返回 nil
+func withErrorCode(code string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{code: code, err: err}
+}
+
+// newEventError 创建错误事件数据，处理建议和操作由错误码决定
+func newEventError(code, message string) EventError {
+	return EventError{
+		Code:    code,
+		Error:   message,
+		Message: errorHint(code),
+		Action:  errorActions[code],
+	}
+}
+
+// errorHint 获取错误码对应的处理建议，没有时返回空
+func errorHint(code string) string {
+	key := "hint." + code
+	if hint := i18n.T(key); hint != key {
+		return hint
+	}
+	return ""
+}
+
+// emitError 发送带错误码的错误事件
+func (a *App) emitError(eventName string, event EventError) {
+	a.emit(eventName, event)
+}
+
+// emitPageError 按错误原因分类后发送任务中页面处理失败的事件
+func (a *App) emitPageError(ctx context.Context, eventName string, pageNumber int, message string, err error) {
+	event := newEventError(classifyError(err), message)
+	event.Page = pageNumber
+	a.emitTask(ctx, eventName, event)
+}
+
+// classifyError 根据错误类型和内容判断错误码
+func classifyError(err error) string {
+	if err == nil {
+		return ErrCodeUnknown
+	}
+
+	var coded *codedError
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+
+	switch {
+	case errors.Is(err, encryption.ErrLocked):
+		return ErrCodeDocLocked
+	case errors.Is(err, ocr.ErrAPIKeyRequired):
+		return ErrCodeAINotConfigured
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrCodeAINetwork
+	}
+
+	statusCode := 0
+	var apiErr *openai.APIError
+	var reqErr *openai.RequestError
+	switch {
+	case errors.As(err, &apiErr):
+		statusCode = apiErr.HTTPStatusCode
+	case errors.As(err, &reqErr):
+		statusCode = reqErr.HTTPStatusCode
+	}
+	if code := classifyStatusCode(statusCode); code != "" {
+		return code
+	}
+
+	// 自定义解析路径返回的错误只有文本
+	errStr := strings.ToLower(err.Error())
+	switch {
+	case containsAny(errStr, "状态码 401", "状态码 403", "unauthorized", "invalid api key", "incorrect api key"):
+		return ErrCodeAIAuth
+	case containsAny(errStr, "状态码 429", "rate limit", "too many requests", "quota"):
+		return ErrCodeAIRateLimit
+	case containsAny(errStr, "状态码 500", "状态码 502", "状态码 503", "状态码 504", "bad gateway", "service unavailable"):
+		return ErrCodeAIServer
+	case containsAny(errStr, "timeout", "connection refused", "connection reset", "no such host", "dial tcp"):
+		return ErrCodeAINetwork
+	case containsAny(errStr, "no space left", "database is locked", "disk i/o"):
+		return ErrCodeCacheIO
+	case containsAny(errStr, "ocr识别", "ai处理"):
+		return ErrCodeAIFailed
+	}
+	return ErrCodeUnknown
+}
+
+// classifyStatusCode 根据AI服务返回的HTTP状态码判断错误码，无法判断时返回空
+func classifyStatusCode(statusCode int) string {
+	switch {
+	case statusCode == http.StatusUnauthorized, statusCode == http.StatusForbidden:
+		return ErrCodeAIAuth
+	case statusCode == http.StatusTooManyRequests:
+		return ErrCodeAIRateLimit
+	case statusCode >= http.StatusInternalServerError:
+		return ErrCodeAIServer
+	case statusCode >= http.StatusBadRequest:
+		return ErrCodeAIFailed
+	}
+	return ""
+}
+
+// containsAny 判断 s 是否包含任一子串
+func containsAny(s string, substrs ...string) bool {
+	for _, substr := range substrs {
+		if strings.Contains(s, substr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// eventFlushInterval 合并事件的最长等待时间
+	eventFlushInterval = 200 * time.Millisecond
+	// eventFlushItems 累积到这么多个事件时立即发送
+	eventFlushItems = 50
+)
+
+// coalescedEvents 需要合并发送的高频事件
+// 进度事件只保留每个文档（每个任务）最新的一条；单页完成事件合并为一条，pageNumbers 包含期间完成的所有页面
+var coalescedEvents = map[string]bool{
+	"processing-progress": true,
+	"page-processed":      true,
+	"ai-page-processed":   true,
+}
+
+// pendingEvent 等待发送的合并事件
+type pendingEvent struct {
+	name  string
+	data  interface{}
+	pages []int // 单页完成事件合并的页码
+}
+
+// eventCoalescer 合并缓存命中等快速批次产生的大量进度和单页完成事件，按间隔或数量批量发送，避免前端卡顿
+// 其他事件发送前先发送已合并的事件，保证完成、取消等事件之前前端收到的是最终准确的进度
+type eventCoalescer struct {
+	sendMu  sync.Mutex // 保证合并事件和其他事件按顺序发送
+	mu      sync.Mutex
+	send    func(eventName string, data interface{})
+	keys    []string // 按首次出现的顺序发送
+	pending map[string]*pendingEvent
+	items   int
+	timer   *time.Timer
+}
+
+// newEventCoalescer 创建事件合并器，send 为实际发送事件的函数
+func newEventCoalescer(send func(eventName string, data interface{})) *eventCoalescer {
+	return &eventCoalescer{send: send, pending: make(map[string]*pendingEvent)}
+}
+
+// add 加入需要合并的事件，不需要合并的事件返回false
+func (c *eventCoalescer) add(eventName string, data interface{}) bool {
+	if !coalescedEvents[eventName] {
+		return false
+	}
+
+	c.mu.Lock()
+	key := eventName + "\x00" + eventSource(data)
+	event, ok := c.pending[key]
+	if !ok {
+		event = &pendingEvent{name: eventName}
+		c.pending[key] = event
+		c.keys = append(c.keys, key)
+	}
+	event.data = data
+	if page, ok := eventPageNumber(data); ok {
+		event.pages = append(event.pages, page)
+	}
+	c.items++
+
+	full := c.items >= eventFlushItems
+	if !full && c.timer == nil {
+		c.timer = time.AfterFunc(eventFlushInterval, c.flush)
+	}
+	c.mu.Unlock()
+
+	if full {
+		c.flush()
+	}
+	return true
+}
+
+// flush 立即发送所有等待中的合并事件
+func (c *eventCoalescer) flush() {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+	c.flushLocked()
+}
+
+// sendAfterPending 先发送等待中的合并事件，再发送指定事件
+func (c *eventCoalescer) sendAfterPending(eventName string, data interface{}) {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+	c.flushLocked()
+	c.send(eventName, data)
+}
+
+// flushLocked 发送所有等待中的合并事件（调用方持有 sendMu）
+func (c *eventCoalescer) flushLocked() {
+	c.mu.Lock()
+	keys, pending := c.keys, c.pending
+	c.keys, c.pending, c.items = nil, make(map[string]*pendingEvent), 0
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	c.mu.Unlock()
+
+	for _, key := range keys {
+		event := pending[key]
+		data := event.data
+		if fields, ok := data.(map[string]interface{}); ok && len(event.pages) > 0 {
+			merged := make(map[string]interface{}, len(fields)+1)
+			for k, v := range fields {
+				merged[k] = v
+			}
+			merged["pageNumbers"] = event.pages
+			data = merged
+		}
+		c.send(event.name, data)
+	}
+}
+
+// eventSource 事件所属的文档ID和任务ID，同一文档上并发的任务分别合并
+func eventSource(data interface{}) string {
+	switch v := data.(type) {
+	case ProgressUpdate:
+		return v.DocumentID + "\x00" + v.TaskID
+	case map[string]interface{}:
+		id, _ := v["document_id"].(string)
+		taskID, _ := v["task_id"].(string)
+		return id + "\x00" + taskID
+	}
+	return ""
+}
+
+// eventPageNumber 单页完成事件的页码
+func eventPageNumber(data interface{}) (int, bool) {
+	if v, ok := data.(map[string]interface{}); ok {
+		page, ok := v["pageNumber"].(int)
+		return page, ok
+	}
+	return 0, false
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"pdf-ocr-ai/pkg/config"
+	"pdf-ocr-ai/pkg/extraction"
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// GetExtractionSchemas 获取配置的结构化提取模板
+func (a *App) GetExtractionSchemas() []config.ExtractionSchema {
+	return a.configManager.GetConfig().ExtractionSchemas
+}
+
+// ExtractFields 按提取模板从当前文档的指定页面提取结构化字段，结果按页保存
+// 页面使用OCR文本（没有时使用PDF原生文本）；单页失败不影响其他页面，失败原因记录在结果中
+func (a *App) ExtractFields(pageNumbers []int, schemaID string) ([]*extraction.Result, error) {
+	session := a.activeSession()
+	if session == nil {
+		return nil, i18n.Errorf("doc.not_loaded")
+	}
+	if a.ocrClient == nil {
+		return nil, i18n.Errorf("ai.not_configured")
+	}
+	schema := a.configManager.GetConfig().FindExtractionSchema(schemaID)
+	if schema == nil {
+		return nil, i18n.Errorf("extraction.schema_missing", schemaID)
+	}
+
+	doc := session.Doc
+	prompt := extraction.BuildPrompt(*schema)
+	results := make([]*extraction.Result, 0, len(pageNumbers))
+	for i, pageNum := range pageNumbers {
+		if pageNum < 1 || pageNum > len(doc.Pages) {
+			continue
+		}
+		a.emit("extraction-progress", map[string]interface{}{
+			"document_id": session.ID,
+			"schema_id":   schema.ID,
+			"page":        pageNum,
+			"current":     i + 1,
+			"total":       len(pageNumbers),
+		})
+
+		result := a.extractPage(session.ID, doc.Pages[pageNum-1].OCRText, doc.Pages[pageNum-1].Text, pageNum, *schema, prompt)
+		if err := a.extractionManager.Save(result); err != nil {
+			logger.Errorf("保存第%d页提取结果失败: %v", pageNum, err)
+		}
+		results = append(results, result)
+	}
+
+	a.emit("extraction-complete", map[string]interface{}{
+		"document_id": session.ID,
+		"schema_id":   schema.ID,
+		"pages":       len(results),
+	})
+	return results, nil
+}
+
+// extractPage 提取单个页面的字段
+func (a *App) extractPage(documentID, ocrText, originalText string, pageNum int, schema config.ExtractionSchema, prompt string) *extraction.Result {
+	result := &extraction.Result{
+		DocumentID: documentID,
+		PageNumber: pageNum,
+		SchemaID:   schema.ID,
+		UpdatedAt:  time.Now(),
+	}
+
+	text := ocrText
+	if text == "" {
+		text = originalText
+	}
+	if strings.TrimSpace(text) == "" {
+		result.Error = "页面没有可提取的文本，请先进行OCR"
+		return result
+	}
+
+	response, err := a.ocrClient.ProcessText(a.ctx, text, prompt)
+	if err != nil {
+		result.Error = fmt.Sprintf("AI处理失败: %v", err)
+		return result
+	}
+	result.Raw = response
+
+	fields, problems, err := extraction.Parse(schema, response)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Fields = fields
+	result.Problems = problems
+	if len(problems) > 0 {
+		logger.Warnf("第%d页提取结果不完整: %s", pageNum, strings.Join(problems, "; "))
+	}
+	return result
+}
+
+// GetExtractionResults 获取当前文档使用指定模板已保存的提取结果
+func (a *App) GetExtractionResults(schemaID string) ([]*extraction.Result, error) {
+	session := a.activeSession()
+	if session == nil {
+		return nil, i18n.Errorf("doc.not_loaded")
+	}
+	return a.extractionManager.List(session.ID, schemaID)
+}
+
+// ExportExtractionCSV 将当前文档使用指定模板的提取结果导出为CSV，path为空时弹出保存对话框
+func (a *App) ExportExtractionCSV(schemaID string, path string) (string, error) {
+	session := a.activeSession()
+	if session == nil {
+		return "", i18n.Errorf("doc.not_loaded")
+	}
+	schema := a.configManager.GetConfig().FindExtractionSchema(schemaID)
+	if schema == nil {
+		return "", i18n.Errorf("extraction.schema_missing", schemaID)
+	}
+
+	results, err := a.extractionManager.List(session.ID, schema.ID)
+	if err != nil {
+		return "", err
+	}
+	if len(results) == 0 {
+		return "", fmt.Errorf("当前文档没有「%s」的提取结果", schema.Name)
+	}
+
+	if path == "" {
+		name := strings.TrimSuffix(filepath.Base(session.Doc.FilePath), filepath.Ext(session.Doc.FilePath))
+		path, err = runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+			DefaultFilename: fmt.Sprintf("%s-%s.csv", name, schema.ID),
+			Filters:         []runtime.FileFilter{{DisplayName: "CSV文件", Pattern: "*.csv"}},
+			Title:           "导出提取结果",
+		})
+		if err != nil || path == "" {
+			return "", err
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("创建文件失败: %w", err)
+	}
+	if err := extraction.WriteCSV(file, *schema, results); err != nil {
+		file.Close()
+		os.Remove(path)
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("写入文件失败: %w", err)
+	}
+
+	logger.Infof("已导出 %d 页提取结果到 %s", len(results), path)
+	return path, nil
+}
+
+// validateExtractionSchemas 检查提取模板是否有效
+func validateExtractionSchemas(cfg config.AppConfig) error {
+	seen := make(map[string]bool)
+	for _, schema := range cfg.ExtractionSchemas {
+		if err := extraction.ValidateSchema(schema); err != nil {
+			return err
+		}
+		if seen[schema.ID] {
+			return fmt.Errorf("提取模板ID重复: %s", schema.ID)
+		}
+		seen[schema.ID] = true
+	}
+	return nil
+}
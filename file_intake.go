@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// 拖放文件的处理方式
+const (
+	IntakeActionAuto  = "auto"  // 单个文件直接打开，多个文件加入队列
+	IntakeActionOpen  = "open"  // 打开第一个有效文件
+	IntakeActionQueue = "queue" // 全部加入批处理队列
+)
+
+// IntakeFile 单个文件的校验结果
+type IntakeFile struct {
+	FilePath  string `json:"file_path"`
+	Type      string `json:"type,omitempty"`
+	PageCount int    `json:"page_count,omitempty"`
+	Accepted  bool   `json:"accepted"`
+	Reason    string `json:"reason,omitempty"` // 被拒绝的原因
+}
+
+// IntakeSummary 多文件接收结果
+type IntakeSummary struct {
+	Action       string       `json:"action"` // 实际执行的处理方式: open 或 queue
+	Files        []IntakeFile `json:"files"`
+	Accepted     int          `json:"accepted"`
+	Rejected     int          `json:"rejected"`
+	OpenedID     string       `json:"opened_document_id,omitempty"`
+	QueueItemIDs []string     `json:"queue_item_ids,omitempty"`
+}
+
+// handleFileDrop 处理系统文件拖放
+func (a *App) handleFileDrop(x, y int, paths []string) {
+	if len(paths) == 0 || a.configManager == nil {
+		return
+	}
+
+	// 校验文件可能较慢（需要解析文档），不阻塞事件回调
+	action := a.configManager.GetConfig().UI.DropAction
+	go func() {
+		if _, err := a.IntakeFiles(paths, action); err != nil {
+			logger.Errorf("处理拖放文件失败: %v", err)
+			a.emitError("processing-error", newEventError(ErrCodeIntakeFailed, i18n.T("drop.failed", err)))
+		}
+	}()
+}
+
+// IntakeFiles 校验多个文件（目录会展开一层，ZIP压缩包会解压），按action打开或加入队列，并发送 files-dropped 事件
+func (a *App) IntakeFiles(paths []string, action string) (*IntakeSummary, error) {
+	if action == "" {
+		action = IntakeActionAuto
+	}
+	if action != IntakeActionAuto && action != IntakeActionOpen && action != IntakeActionQueue {
+		return nil, fmt.Errorf("不支持的处理方式: %s", action)
+	}
+
+	files := a.validateIntakeFiles(a.expandZipPaths(expandIntakePaths(paths)))
+
+	summary := &IntakeSummary{Files: files}
+	var accepted []string
+	for _, file := range files {
+		if file.Accepted {
+			accepted = append(accepted, file.FilePath)
+		}
+	}
+	summary.Accepted = len(accepted)
+	summary.Rejected = len(files) - len(accepted)
+
+	if action == IntakeActionAuto {
+		action = IntakeActionQueue
+		if len(accepted) == 1 {
+			action = IntakeActionOpen
+		}
+	}
+	summary.Action = action
+
+	var err error
+	if len(accepted) > 0 {
+		switch action {
+		case IntakeActionOpen:
+			summary.OpenedID, err = a.OpenDocument(accepted[0])
+		case IntakeActionQueue:
+			requests := make([]QueueRequest, 0, len(accepted))
+			for _, path := range accepted {
+				requests = append(requests, QueueRequest{FilePath: path})
+			}
+			summary.QueueItemIDs, err = a.EnqueueDocuments(requests)
+		}
+	}
+
+	logger.Infof("接收文件: %d 个有效, %d 个被拒绝, 处理方式: %s", summary.Accepted, summary.Rejected, summary.Action)
+	a.emit("files-dropped", summary)
+
+	return summary, err
+}
+
+// expandIntakePaths 展开目录中的文件（仅一层，按文件名排序）
+func expandIntakePaths(paths []string) []string {
+	var result []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil || !info.IsDir() {
+			result = append(result, path)
+			continue
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			result = append(result, path)
+			continue
+		}
+
+		var children []string
+		for _, entry := range entries {
+			if !entry.IsDir() && entry.Name()[0] != '.' {
+				children = append(children, filepath.Join(path, entry.Name()))
+			}
+		}
+		sort.Strings(children)
+		result = append(result, children...)
+	}
+	return result
+}
+
+// validateIntakeFiles 通过DocumentProcessor校验文件格式和可读性
+func (a *App) validateIntakeFiles(paths []string) []IntakeFile {
+	files := make([]IntakeFile, 0, len(paths))
+	seen := make(map[string]bool, len(paths))
+
+	for _, path := range paths {
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+
+		file := IntakeFile{FilePath: path}
+
+		info, err := os.Stat(path)
+		switch {
+		case err != nil:
+			file.Reason = "文件不存在或无法访问"
+		case info.IsDir():
+			file.Reason = "无法读取目录"
+		case info.Size() == 0:
+			file.Reason = "文件为空"
+		case !a.documentProcessor.IsSupported(path):
+			file.Reason = fmt.Sprintf("不支持的文件格式: %s", filepath.Ext(path))
+		default:
+			docInfo, err := a.documentProcessor.GetDocumentInfo(path)
+			if err != nil {
+				file.Reason = fmt.Sprintf("文件无法解析: %v", err)
+				break
+			}
+			file.Type = string(docInfo.Type)
+			file.PageCount = docInfo.PageCount
+			if !docInfo.SupportedOCR {
+				file.Reason = "该格式已包含文本，不需要OCR"
+				break
+			}
+			file.Accepted = true
+		}
+
+		files = append(files, file)
+	}
+
+	return files
+}
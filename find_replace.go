@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/replace"
+	"pdf-ocr-ai/pkg/revisions"
+)
+
+// maxReplacePreviewMatches 返回给界面的匹配预览条数上限
+const maxReplacePreviewMatches = 500
+
+// ReplaceMatch 一处匹配（预览用）
+type ReplaceMatch struct {
+	PageNumber int    `json:"page_number"`
+	TextType   string `json:"text_type"`
+	replace.Match
+}
+
+// ReplaceResult 查找替换结果
+type ReplaceResult struct {
+	DryRun       bool           `json:"dry_run"`
+	PagesMatched int            `json:"pages_matched"` // 有匹配的页面文本数（OCR和AI分别计数）
+	TotalMatches int            `json:"total_matches"`
+	Matches      []ReplaceMatch `json:"matches"`   // 最多 maxReplacePreviewMatches 条
+	Truncated    bool           `json:"truncated"` // 匹配预览是否被截断
+}
+
+// ReplaceInResults 在当前文档页面的OCR/AI文本中查找替换
+// pageNumbers 为空时处理所有页面；scope: ocr、ai 或 all；dryRun 为true时只返回匹配预览，不修改文本
+// 替换后的文本与手动编辑一样写入缓存并记录历史版本，可以逐页撤销
+func (a *App) ReplaceInResults(pageNumbers []int, pattern, replacement, scope string, regex, dryRun bool) (*ReplaceResult, error) {
+	session := a.activeSession()
+	if session == nil {
+		return nil, i18n.Errorf("doc.not_loaded")
+	}
+
+	var textTypes []string
+	switch scope {
+	case "ocr", "ai":
+		textTypes = []string{scope}
+	case "", "all":
+		textTypes = []string{"ocr", "ai"}
+	default:
+		return nil, fmt.Errorf("不支持的替换范围: %s", scope)
+	}
+
+	replacer, err := replace.New(pattern, replacement, regex)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := session.Doc
+	if len(pageNumbers) == 0 {
+		for i := range doc.Pages {
+			pageNumbers = append(pageNumbers, i+1)
+		}
+	}
+
+	result := &ReplaceResult{DryRun: dryRun, Matches: []ReplaceMatch{}}
+	for _, pageNumber := range pageNumbers {
+		if pageNumber < 1 || pageNumber > len(doc.Pages) {
+			continue
+		}
+		page := doc.Pages[pageNumber-1]
+		for _, textType := range textTypes {
+			replaced, matches := replacer.Apply(pageTextByType(page.OCRText, page.AIText, textType))
+			if len(matches) == 0 {
+				continue
+			}
+			result.PagesMatched++
+			result.TotalMatches += len(matches)
+			for _, match := range matches {
+				if len(result.Matches) >= maxReplacePreviewMatches {
+					result.Truncated = true
+					break
+				}
+				result.Matches = append(result.Matches, ReplaceMatch{PageNumber: pageNumber, TextType: textType, Match: match})
+			}
+
+			if dryRun {
+				continue
+			}
+			if err := a.updatePageText(pageNumber, textType, replaced, revisions.SourceReplace); err != nil {
+				return result, i18n.Errorf("replace.failed", pageNumber, err)
+			}
+		}
+	}
+
+	if !dryRun && result.TotalMatches > 0 {
+		logger.Infof("查找替换完成: %d 处匹配，涉及 %d 个页面文本", result.TotalMatches, result.PagesMatched)
+	}
+	return result, nil
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"pdf-ocr-ai/pkg/footnote"
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// ResolveFootnotes 按脚注配置处理按页顺序排列的导出文本：插入正文或汇总到章末，keep 时原样返回
+func (a *App) ResolveFootnotes(texts []string) []string {
+	return a.resolveFootnotes(texts)
+}
+
+// PreviewFootnotes 识别当前文档各页的脚注，用于检查识别效果
+func (a *App) PreviewFootnotes() []footnote.Note {
+	doc := a.activeDocument()
+	if doc == nil {
+		return []footnote.Note{}
+	}
+	notes := []footnote.Note{}
+	for i, text := range documentPageTexts(doc, "", false) {
+		_, pageNotes := footnote.Split(text)
+		for _, note := range pageNotes {
+			note.Page = i + 1
+			notes = append(notes, note)
+		}
+	}
+	return notes
+}
+
+// resolveFootnotes 按配置处理脚注
+func (a *App) resolveFootnotes(texts []string) []string {
+	result := footnote.Process(texts, a.configManager.GetConfig().Footnotes.Mode)
+	if len(result.Notes) > 0 {
+		logger.Debugf("导出时处理了 %d 条脚注", len(result.Notes))
+	}
+	return result.Pages
+}
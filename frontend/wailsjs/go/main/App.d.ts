@@ -1,97 +1,406 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {annotations} from '../models';
+import {outline} from '../models';
+import {history} from '../models';
 import {system} from '../models';
+import {cache} from '../models';
+import {main} from '../models';
+import {extraction} from '../models';
+import {ocr} from '../models';
 import {config} from '../models';
 import {pdf} from '../models';
-import {history} from '../models';
 import {document} from '../models';
-import {ocr} from '../models';
+import {tags} from '../models';
+import {encryption} from '../models';
+import {jobs} from '../models';
+import {storage} from '../models';
+import {memguard} from '../models';
+import {proofread} from '../models';
+import {scheduler} from '../models';
+import {stitch} from '../models';
+import {bookmarks} from '../models';
+import {revisions} from '../models';
+import {footnote} from '../models';
 import {frontend} from '../models';
+import {postprocess} from '../models';
+import {textstats} from '../models';
+import {reflow} from '../models';
+
+export function AcquireFromScanner(arg1:system.ScanOptions):Promise<string>;
+
+export function AddAnnotation(arg1:number,arg2:string,arg3:number,arg4:number,arg5:string):Promise<annotations.Annotation>;
+
+export function AnalyzeOutline(arg1:Array<string>,arg2:string):Promise<outline.Result>;
+
+export function AnnotateExportTexts(arg1:Array<number>,arg2:Array<string>,arg3:string):Promise<Array<string>>;
+
+export function AppendToDocument(arg1:Array<string>):Promise<string>;
+
+export function ApplyProofreadEdits(arg1:number,arg2:string):Promise<number>;
+
+export function ArchiveHistory(arg1:number):Promise<history.ColdArchiveResult>;
+
+export function CancelAudioExport():Promise<void>;
+
+export function CancelDocumentProcessing(arg1:string):Promise<void>;
 
 export function CancelProcessing():Promise<void>;
 
+export function CancelQueueItem(arg1:string):Promise<void>;
+
+export function CancelTask(arg1:string):Promise<void>;
+
 export function CheckAIProcessedPages(arg1:Array<number>):Promise<Record<string, any>>;
 
+export function CheckOCRAgainstNativeText(arg1:Array<number>):Promise<Array<main.NativeTextCheck>>;
+
 export function CheckProcessedPages(arg1:Array<number>):Promise<Record<string, any>>;
 
 export function CheckSystemDependencies():Promise<system.SystemInfo>;
 
-export function DeleteHistoryRecord(arg1:number):Promise<void>;
+export function CleanExportTexts(arg1:Array<string>):Promise<Array<string>>;
+
+export function ClearAllCache():Promise<cache.ClearResult>;
+
+export function ClearDocumentCache(arg1:string):Promise<cache.ClearResult>;
+
+export function ClearFinishedQueueItems():Promise<void>;
+
+export function CloseDocument(arg1:string):Promise<void>;
+
+export function CompareHistoryRecords(arg1:number,arg2:number):Promise<history.RecordComparison>;
+
+export function ConvertChineseText(arg1:string,arg2:string):Promise<string>;
+
+export function ConvertDocument(arg1:Array<string>,arg2:string,arg3:main.ConvertOptions):Promise<main.ConvertResult>;
+
+export function DeleteAnnotation(arg1:number):Promise<void>;
+
+export function DeleteHistoryRecord(arg1:number,arg2:boolean):Promise<void>;
+
+export function DisableEncryption(arg1:string):Promise<void>;
+
+export function DiscardJob(arg1:number):Promise<void>;
+
+export function EnableEncryption(arg1:string,arg2:string):Promise<void>;
+
+export function EnqueueDocuments(arg1:Array<main.QueueRequest>):Promise<Array<string>>;
+
+export function ExportAudio(arg1:Array<number>,arg2:string,arg3:string):Promise<main.AudioExportResult>;
+
+export function ExportBatchReport(arg1:number,arg2:string):Promise<string>;
+
+export function ExportDiagnostics():Promise<string>;
+
+export function ExportExtractionCSV(arg1:string,arg2:string):Promise<string>;
+
+export function ExportHistory(arg1:string,arg2:history.HistoryFilter):Promise<string>;
+
+export function ExportMarkdown(arg1:Array<number>,arg2:main.MarkdownExportOptions):Promise<string>;
+
+export function ExportPageImages(arg1:Array<number>,arg2:string,arg3:number,arg4:boolean,arg5:string):Promise<main.PageImageExportResult>;
 
 export function ExportProcessingResults(arg1:string):Promise<string>;
 
+export function ExportReflowedPDF(arg1:string,arg2:reflow.Options,arg3:string):Promise<string>;
+
+export function ExportSettings(arg1:string,arg2:boolean):Promise<string>;
+
 export function ExportText(arg1:Array<number>,arg2:string):Promise<string>;
 
+export function ExportToNotion(arg1:string):Promise<string>;
+
+export function ExportToObsidian(arg1:string):Promise<main.ObsidianExportResult>;
+
+export function ExportToPaperless(arg1:string):Promise<string>;
+
+export function ExtractFields(arg1:Array<number>,arg2:string):Promise<Array<extraction.Result>>;
+
 export function ExtractNativeText(arg1:number):Promise<string>;
 
+export function GetAIProviders():Promise<Array<ocr.ProviderInfo>>;
+
+export function GetAPIServerStatus():Promise<Record<string, any>>;
+
+export function GetAnnotations(arg1:number):Promise<Array<annotations.Annotation>>;
+
 export function GetAppVersion():Promise<Record<string, string>>;
 
+export function GetBatchReport(arg1:number):Promise<main.BatchReport>;
+
+export function GetBookmarkedPages():Promise<Array<number>>;
+
+export function GetCacheStats():Promise<cache.CacheStats>;
+
 export function GetConfig():Promise<config.AppConfig>;
 
+export function GetConfigSections():Promise<Array<string>>;
+
 export function GetCurrentDocument():Promise<pdf.PDFDocument>;
 
+export function GetDiskSpace():Promise<Array<main.DiskSpaceStatus>>;
+
 export function GetDocumentHistoryPages(arg1:string):Promise<Array<history.HistoryPage>>;
 
 export function GetDocumentInfo(arg1:string):Promise<document.DocumentInfo>;
 
+export function GetDocumentProcessingState(arg1:string):Promise<Record<string, any>>;
+
+export function GetDocumentProfile(arg1:string):Promise<main.DocumentProfile>;
+
+export function GetDocumentTags():Promise<Array<tags.PageTags>>;
+
+export function GetDocumentTextStats():Promise<textstats.Stats>;
+
+export function GetEmailAttachments(arg1:string):Promise<Array<main.EmailAttachment>>;
+
+export function GetEncryptionStatus():Promise<encryption.Status>;
+
+export function GetExcludedPages():Promise<Array<number>>;
+
+export function GetExtractionResults(arg1:string):Promise<Array<extraction.Result>>;
+
+export function GetExtractionSchemas():Promise<Array<config.ExtractionSchema>>;
+
 export function GetHistoryPages(arg1:number):Promise<Array<history.HistoryPage>>;
 
 export function GetHistoryRecords(arg1:number):Promise<Array<history.HistoryRecord>>;
 
+export function GetHistoryStats():Promise<history.HistoryStats>;
+
+export function GetIncompleteRecords(arg1:string):Promise<Array<main.IncompleteRecord>>;
+
 export function GetInstallInstructions():Promise<Record<string, string>>;
 
+export function GetInterruptedJobs():Promise<Array<jobs.Job>>;
+
+export function GetLatestResults(arg1:string):Promise<Array<storage.LatestPage>>;
+
+export function GetLogDirectory():Promise<string>;
+
+export function GetLogLevel():Promise<string>;
+
+export function GetLogTail(arg1:number):Promise<Array<string>>;
+
+export function GetMemoryUsage():Promise<memguard.Usage>;
+
+export function GetOpenDocuments():Promise<Array<main.WorkspaceDocument>>;
+
 export function GetPDFPath():Promise<string>;
 
 export function GetPageImage(arg1:number):Promise<Array<number>>;
 
+export function GetPageImagesRange(arg1:number,arg2:number,arg3:number):Promise<Array<main.PageImage>>;
+
+export function GetPageStatusMap():Promise<Array<main.PageStatusFlags>>;
+
+export function GetPagesByReviewStatus(arg1:string):Promise<Array<number>>;
+
+export function GetPreprocessSteps():Promise<Array<string>>;
+
 export function GetProcessingState():Promise<Record<string, any>>;
 
 export function GetProcessingStats():Promise<Record<string, any>>;
 
+export function GetProofreadEdits(arg1:number):Promise<Array<proofread.Edit>>;
+
+export function GetQueue():Promise<Array<main.QueueItem>>;
+
+export function GetRecordProgress(arg1:number):Promise<history.RecordProgress>;
+
+export function GetRunHistory(arg1:string):Promise<Array<storage.RunPage>>;
+
+export function GetScheduledTasks():Promise<Array<scheduler.TaskInfo>>;
+
+export function GetStitchedText(arg1:string):Promise<stitch.Result>;
+
 export function GetSupportedFormats():Promise<Array<string>>;
 
 export function GetSupportedModels():Promise<Array<ocr.ModelInfo>>;
 
+export function GetTagIndex(arg1:string):Promise<Array<tags.IndexEntry>>;
+
+export function GetTaskStatus(arg1:string):Promise<main.TaskInfo>;
+
+export function GetWatchFolderStatus():Promise<Record<string, any>>;
+
 export function Greet(arg1:string):Promise<string>;
 
+export function ImportGlossary(arg1:string):Promise<number>;
+
+export function ImportHistory(arg1:string):Promise<history.ImportResult>;
+
+export function ImportSettings(arg1:string):Promise<config.AppConfig>;
+
+export function ImportTextLayer(arg1:boolean,arg2:boolean):Promise<main.TextLayerImport>;
+
+export function ImportZip(arg1:string,arg2:string):Promise<main.IntakeSummary>;
+
+export function IntakeFiles(arg1:Array<string>,arg2:string):Promise<main.IntakeSummary>;
+
+export function ListActiveTasks():Promise<Array<main.TaskInfo>>;
+
+export function ListBookmarks():Promise<Array<bookmarks.Bookmark>>;
+
+export function ListHistory(arg1:history.HistoryFilter):Promise<history.HistoryListResult>;
+
+export function ListPageRevisions(arg1:number):Promise<Array<revisions.Revision>>;
+
+export function ListScanners():Promise<Array<system.Scanner>>;
+
 export function LoadDocument(arg1:string):Promise<void>;
 
 export function LoadPDF(arg1:string):Promise<void>;
 
+export function LockEncryption():Promise<void>;
+
+export function MoveQueueItem(arg1:string,arg2:number):Promise<void>;
+
+export function OCRFromClipboard():Promise<string>;
+
+export function OpenDocument(arg1:string):Promise<string>;
+
+export function OpenEmailAttachments(arg1:string,arg2:string):Promise<main.IntakeSummary>;
+
+export function PauseDocumentProcessing(arg1:string):Promise<void>;
+
 export function PauseProcessing():Promise<void>;
 
-export function ProcessPages(arg1:Array<number>):Promise<void>;
+export function PauseQueueItem(arg1:string):Promise<void>;
+
+export function PreviewFootnotes():Promise<Array<footnote.Note>>;
+
+export function PreviewGlossary(arg1:string,arg2:Array<config.GlossaryTerm>):Promise<main.GlossaryPreview>;
+
+export function PreviewTextCleanup(arg1:string,arg2:config.TextCleanupConfig):Promise<stitch.CleanResult>;
+
+export function PrioritizePage(arg1:number):Promise<void>;
+
+export function ProbeModelCapability(arg1:string,arg2:boolean):Promise<config.ModelCapability>;
+
+export function ProcessPages(arg1:Array<number>):Promise<string>;
+
+export function ProcessPagesForce(arg1:Array<number>):Promise<string>;
+
+export function ProcessSinglePage(arg1:number):Promise<string>;
+
+export function ProcessSinglePageForce(arg1:number):Promise<string>;
+
+export function ProcessWithAI(arg1:Array<number>,arg2:string):Promise<string>;
+
+export function ProcessWithAIBatch(arg1:Array<number>,arg2:string):Promise<string>;
+
+export function ProcessWithAIBatchContext(arg1:Array<number>,arg2:string,arg3:boolean):Promise<string>;
+
+export function ProcessWithAIBatchForce(arg1:Array<number>,arg2:string):Promise<string>;
+
+export function ProcessWithAIBatchForceContext(arg1:Array<number>,arg2:string,arg3:boolean):Promise<string>;
 
-export function ProcessPagesForce(arg1:Array<number>):Promise<void>;
+export function ProcessWithAIBatchRolling(arg1:Array<number>,arg2:string,arg3:boolean,arg4:boolean):Promise<string>;
 
-export function ProcessSinglePage(arg1:number):Promise<void>;
+export function ProcessWithAIContext(arg1:Array<number>,arg2:string,arg3:boolean):Promise<string>;
 
-export function ProcessSinglePageForce(arg1:number):Promise<void>;
+export function ProofreadPages(arg1:Array<number>,arg2:string):Promise<Array<proofread.Edit>>;
 
-export function ProcessWithAI(arg1:Array<number>,arg2:string):Promise<void>;
+export function RecognizePageRegion(arg1:number,arg2:main.PageRegion):Promise<string>;
 
-export function ProcessWithAIBatch(arg1:Array<number>,arg2:string):Promise<void>;
+export function RegenerateAPIToken():Promise<string>;
 
-export function ProcessWithAIBatchContext(arg1:Array<number>,arg2:string,arg3:boolean):Promise<void>;
+export function RelocateDocument(arg1:string,arg2:string):Promise<void>;
 
-export function ProcessWithAIBatchForce(arg1:Array<number>,arg2:string):Promise<void>;
+export function RemovePageBookmark(arg1:number):Promise<void>;
 
-export function ProcessWithAIBatchForceContext(arg1:Array<number>,arg2:string,arg3:boolean):Promise<void>;
+export function RemoveQueueItem(arg1:string):Promise<void>;
 
-export function ProcessWithAIContext(arg1:Array<number>,arg2:string,arg3:boolean):Promise<void>;
+export function ReplaceInResults(arg1:Array<number>,arg2:string,arg3:string,arg4:string,arg5:boolean,arg6:boolean):Promise<main.ReplaceResult>;
+
+export function ResetToDefaults(arg1:string):Promise<config.AppConfig>;
+
+export function ResolveFootnotes(arg1:Array<string>):Promise<Array<string>>;
+
+export function RestoreArchivedHistory(arg1:number):Promise<void>;
+
+export function RestorePageRevision(arg1:number):Promise<revisions.Revision>;
+
+export function ResumeDocumentProcessing(arg1:string):Promise<void>;
+
+export function ResumeJob(arg1:number):Promise<void>;
+
+export function ResumePendingJobs():Promise<number>;
 
 export function ResumeProcessing():Promise<void>;
 
+export function ResumeQueueItem(arg1:string):Promise<void>;
+
+export function ResumeRecord(arg1:number):Promise<string>;
+
+export function RetryFailedPages(arg1:number):Promise<string>;
+
+export function RunScheduledTask(arg1:string):Promise<void>;
+
+export function RunSelfTest(arg1:boolean):Promise<system.SelfTestReport>;
+
+export function RunStorageMaintenance():Promise<main.StorageMaintenanceReport>;
+
 export function SaveBinaryFileWithDialog(arg1:string,arg2:string,arg3:Array<frontend.FileFilter>):Promise<string>;
 
 export function SaveFileWithDialog(arg1:string,arg2:string,arg3:Array<frontend.FileFilter>):Promise<string>;
 
 export function SearchHistory(arg1:string,arg2:number):Promise<Array<history.SearchResult>>;
 
+export function SearchHistoryAdvanced(arg1:history.SearchOptions):Promise<history.SearchResultPage>;
+
+export function SearchTags(arg1:string):Promise<Array<tags.Match>>;
+
 export function SelectFile():Promise<string>;
 
+export function SetDocumentProfile(arg1:string,arg2:string):Promise<void>;
+
+export function SetExportApprovedOnly(arg1:boolean):Promise<void>;
+
+export function SetLogLevel(arg1:string):Promise<void>;
+
+export function SetPageBookmark(arg1:number,arg2:string):Promise<void>;
+
+export function SetPageReviewStatus(arg1:Array<number>,arg2:string):Promise<void>;
+
+export function SetPagesExcluded(arg1:Array<number>,arg2:boolean):Promise<void>;
+
+export function SetProofreadEditStatus(arg1:number,arg2:string):Promise<void>;
+
+export function SetQueueConcurrency(arg1:number):Promise<void>;
+
+export function SetViewedPage(arg1:number):Promise<void>;
+
+export function SplicePageText(arg1:number,arg2:string,arg3:number,arg4:number,arg5:string):Promise<string>;
+
+export function StitchTexts(arg1:Array<string>):Promise<stitch.Result>;
+
+export function SummarizeDocument(arg1:string):Promise<string>;
+
+export function SwitchDocument(arg1:string):Promise<void>;
+
+export function TagPages(arg1:Array<number>):Promise<Array<tags.PageTags>>;
+
 export function TestAIConnection():Promise<void>;
 
+export function TestDesktopNotification():Promise<void>;
+
+export function TestNotion():Promise<void>;
+
+export function TestPaperless():Promise<void>;
+
+export function TestPostProcessRules(arg1:Array<config.PostProcessRule>,arg2:string,arg3:string):Promise<postprocess.DryRun>;
+
+export function TestRemoteUpload():Promise<void>;
+
+export function TestWebhook():Promise<void>;
+
+export function UnlockEncryption(arg1:string):Promise<void>;
+
+export function UpdateAnnotation(arg1:number,arg2:string):Promise<void>;
+
 export function UpdateConfig(arg1:config.AppConfig):Promise<void>;
 
 export function UpdatePageText(arg1:number,arg2:string,arg3:string):Promise<void>;
@@ -2,14 +2,62 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AcquireFromScanner(arg1) {
+  return window['go']['main']['App']['AcquireFromScanner'](arg1);
+}
+
+export function AddAnnotation(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['AddAnnotation'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function AnalyzeOutline(arg1, arg2) {
+  return window['go']['main']['App']['AnalyzeOutline'](arg1, arg2);
+}
+
+export function AnnotateExportTexts(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AnnotateExportTexts'](arg1, arg2, arg3);
+}
+
+export function AppendToDocument(arg1) {
+  return window['go']['main']['App']['AppendToDocument'](arg1);
+}
+
+export function ApplyProofreadEdits(arg1, arg2) {
+  return window['go']['main']['App']['ApplyProofreadEdits'](arg1, arg2);
+}
+
+export function ArchiveHistory(arg1) {
+  return window['go']['main']['App']['ArchiveHistory'](arg1);
+}
+
+export function CancelAudioExport() {
+  return window['go']['main']['App']['CancelAudioExport']();
+}
+
+export function CancelDocumentProcessing(arg1) {
+  return window['go']['main']['App']['CancelDocumentProcessing'](arg1);
+}
+
 export function CancelProcessing() {
   return window['go']['main']['App']['CancelProcessing']();
 }
 
+export function CancelQueueItem(arg1) {
+  return window['go']['main']['App']['CancelQueueItem'](arg1);
+}
+
+export function CancelTask(arg1) {
+  return window['go']['main']['App']['CancelTask'](arg1);
+}
+
 export function CheckAIProcessedPages(arg1) {
   return window['go']['main']['App']['CheckAIProcessedPages'](arg1);
 }
 
+export function CheckOCRAgainstNativeText(arg1) {
+  return window['go']['main']['App']['CheckOCRAgainstNativeText'](arg1);
+}
+
 export function CheckProcessedPages(arg1) {
   return window['go']['main']['App']['CheckProcessedPages'](arg1);
 }
@@ -18,34 +66,170 @@ export function CheckSystemDependencies() {
   return window['go']['main']['App']['CheckSystemDependencies']();
 }
 
-export function DeleteHistoryRecord(arg1) {
-  return window['go']['main']['App']['DeleteHistoryRecord'](arg1);
+export function CleanExportTexts(arg1) {
+  return window['go']['main']['App']['CleanExportTexts'](arg1);
+}
+
+export function ClearAllCache() {
+  return window['go']['main']['App']['ClearAllCache']();
+}
+
+export function ClearDocumentCache(arg1) {
+  return window['go']['main']['App']['ClearDocumentCache'](arg1);
+}
+
+export function ClearFinishedQueueItems() {
+  return window['go']['main']['App']['ClearFinishedQueueItems']();
+}
+
+export function CloseDocument(arg1) {
+  return window['go']['main']['App']['CloseDocument'](arg1);
+}
+
+export function CompareHistoryRecords(arg1, arg2) {
+  return window['go']['main']['App']['CompareHistoryRecords'](arg1, arg2);
+}
+
+export function ConvertChineseText(arg1, arg2) {
+  return window['go']['main']['App']['ConvertChineseText'](arg1, arg2);
+}
+
+export function ConvertDocument(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ConvertDocument'](arg1, arg2, arg3);
+}
+
+export function DeleteAnnotation(arg1) {
+  return window['go']['main']['App']['DeleteAnnotation'](arg1);
+}
+
+export function DeleteHistoryRecord(arg1, arg2) {
+  return window['go']['main']['App']['DeleteHistoryRecord'](arg1, arg2);
+}
+
+export function DisableEncryption(arg1) {
+  return window['go']['main']['App']['DisableEncryption'](arg1);
+}
+
+export function DiscardJob(arg1) {
+  return window['go']['main']['App']['DiscardJob'](arg1);
+}
+
+export function EnableEncryption(arg1, arg2) {
+  return window['go']['main']['App']['EnableEncryption'](arg1, arg2);
+}
+
+export function EnqueueDocuments(arg1) {
+  return window['go']['main']['App']['EnqueueDocuments'](arg1);
+}
+
+export function ExportAudio(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExportAudio'](arg1, arg2, arg3);
+}
+
+export function ExportBatchReport(arg1, arg2) {
+  return window['go']['main']['App']['ExportBatchReport'](arg1, arg2);
+}
+
+export function ExportDiagnostics() {
+  return window['go']['main']['App']['ExportDiagnostics']();
+}
+
+export function ExportExtractionCSV(arg1, arg2) {
+  return window['go']['main']['App']['ExportExtractionCSV'](arg1, arg2);
+}
+
+export function ExportHistory(arg1, arg2) {
+  return window['go']['main']['App']['ExportHistory'](arg1, arg2);
+}
+
+export function ExportMarkdown(arg1, arg2) {
+  return window['go']['main']['App']['ExportMarkdown'](arg1, arg2);
+}
+
+export function ExportPageImages(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['ExportPageImages'](arg1, arg2, arg3, arg4, arg5);
 }
 
 export function ExportProcessingResults(arg1) {
   return window['go']['main']['App']['ExportProcessingResults'](arg1);
 }
 
+export function ExportReflowedPDF(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExportReflowedPDF'](arg1, arg2, arg3);
+}
+
+export function ExportSettings(arg1, arg2) {
+  return window['go']['main']['App']['ExportSettings'](arg1, arg2);
+}
+
 export function ExportText(arg1, arg2) {
   return window['go']['main']['App']['ExportText'](arg1, arg2);
 }
 
+export function ExportToNotion(arg1) {
+  return window['go']['main']['App']['ExportToNotion'](arg1);
+}
+
+export function ExportToObsidian(arg1) {
+  return window['go']['main']['App']['ExportToObsidian'](arg1);
+}
+
+export function ExportToPaperless(arg1) {
+  return window['go']['main']['App']['ExportToPaperless'](arg1);
+}
+
+export function ExtractFields(arg1, arg2) {
+  return window['go']['main']['App']['ExtractFields'](arg1, arg2);
+}
+
 export function ExtractNativeText(arg1) {
   return window['go']['main']['App']['ExtractNativeText'](arg1);
 }
 
+export function GetAIProviders() {
+  return window['go']['main']['App']['GetAIProviders']();
+}
+
+export function GetAPIServerStatus() {
+  return window['go']['main']['App']['GetAPIServerStatus']();
+}
+
+export function GetAnnotations(arg1) {
+  return window['go']['main']['App']['GetAnnotations'](arg1);
+}
+
 export function GetAppVersion() {
   return window['go']['main']['App']['GetAppVersion']();
 }
 
+export function GetBatchReport(arg1) {
+  return window['go']['main']['App']['GetBatchReport'](arg1);
+}
+
+export function GetBookmarkedPages() {
+  return window['go']['main']['App']['GetBookmarkedPages']();
+}
+
+export function GetCacheStats() {
+  return window['go']['main']['App']['GetCacheStats']();
+}
+
 export function GetConfig() {
   return window['go']['main']['App']['GetConfig']();
 }
 
+export function GetConfigSections() {
+  return window['go']['main']['App']['GetConfigSections']();
+}
+
 export function GetCurrentDocument() {
   return window['go']['main']['App']['GetCurrentDocument']();
 }
 
+export function GetDiskSpace() {
+  return window['go']['main']['App']['GetDiskSpace']();
+}
+
 export function GetDocumentHistoryPages(arg1) {
   return window['go']['main']['App']['GetDocumentHistoryPages'](arg1);
 }
@@ -54,6 +238,42 @@ export function GetDocumentInfo(arg1) {
   return window['go']['main']['App']['GetDocumentInfo'](arg1);
 }
 
+export function GetDocumentProcessingState(arg1) {
+  return window['go']['main']['App']['GetDocumentProcessingState'](arg1);
+}
+
+export function GetDocumentProfile(arg1) {
+  return window['go']['main']['App']['GetDocumentProfile'](arg1);
+}
+
+export function GetDocumentTags() {
+  return window['go']['main']['App']['GetDocumentTags']();
+}
+
+export function GetDocumentTextStats() {
+  return window['go']['main']['App']['GetDocumentTextStats']();
+}
+
+export function GetEmailAttachments(arg1) {
+  return window['go']['main']['App']['GetEmailAttachments'](arg1);
+}
+
+export function GetEncryptionStatus() {
+  return window['go']['main']['App']['GetEncryptionStatus']();
+}
+
+export function GetExcludedPages() {
+  return window['go']['main']['App']['GetExcludedPages']();
+}
+
+export function GetExtractionResults(arg1) {
+  return window['go']['main']['App']['GetExtractionResults'](arg1);
+}
+
+export function GetExtractionSchemas() {
+  return window['go']['main']['App']['GetExtractionSchemas']();
+}
+
 export function GetHistoryPages(arg1) {
   return window['go']['main']['App']['GetHistoryPages'](arg1);
 }
@@ -62,10 +282,46 @@ export function GetHistoryRecords(arg1) {
   return window['go']['main']['App']['GetHistoryRecords'](arg1);
 }
 
+export function GetHistoryStats() {
+  return window['go']['main']['App']['GetHistoryStats']();
+}
+
+export function GetIncompleteRecords(arg1) {
+  return window['go']['main']['App']['GetIncompleteRecords'](arg1);
+}
+
 export function GetInstallInstructions() {
   return window['go']['main']['App']['GetInstallInstructions']();
 }
 
+export function GetInterruptedJobs() {
+  return window['go']['main']['App']['GetInterruptedJobs']();
+}
+
+export function GetLatestResults(arg1) {
+  return window['go']['main']['App']['GetLatestResults'](arg1);
+}
+
+export function GetLogDirectory() {
+  return window['go']['main']['App']['GetLogDirectory']();
+}
+
+export function GetLogLevel() {
+  return window['go']['main']['App']['GetLogLevel']();
+}
+
+export function GetLogTail(arg1) {
+  return window['go']['main']['App']['GetLogTail'](arg1);
+}
+
+export function GetMemoryUsage() {
+  return window['go']['main']['App']['GetMemoryUsage']();
+}
+
+export function GetOpenDocuments() {
+  return window['go']['main']['App']['GetOpenDocuments']();
+}
+
 export function GetPDFPath() {
   return window['go']['main']['App']['GetPDFPath']();
 }
@@ -74,6 +330,22 @@ export function GetPageImage(arg1) {
   return window['go']['main']['App']['GetPageImage'](arg1);
 }
 
+export function GetPageImagesRange(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetPageImagesRange'](arg1, arg2, arg3);
+}
+
+export function GetPageStatusMap() {
+  return window['go']['main']['App']['GetPageStatusMap']();
+}
+
+export function GetPagesByReviewStatus(arg1) {
+  return window['go']['main']['App']['GetPagesByReviewStatus'](arg1);
+}
+
+export function GetPreprocessSteps() {
+  return window['go']['main']['App']['GetPreprocessSteps']();
+}
+
 export function GetProcessingState() {
   return window['go']['main']['App']['GetProcessingState']();
 }
@@ -82,6 +354,30 @@ export function GetProcessingStats() {
   return window['go']['main']['App']['GetProcessingStats']();
 }
 
+export function GetProofreadEdits(arg1) {
+  return window['go']['main']['App']['GetProofreadEdits'](arg1);
+}
+
+export function GetQueue() {
+  return window['go']['main']['App']['GetQueue']();
+}
+
+export function GetRecordProgress(arg1) {
+  return window['go']['main']['App']['GetRecordProgress'](arg1);
+}
+
+export function GetRunHistory(arg1) {
+  return window['go']['main']['App']['GetRunHistory'](arg1);
+}
+
+export function GetScheduledTasks() {
+  return window['go']['main']['App']['GetScheduledTasks']();
+}
+
+export function GetStitchedText(arg1) {
+  return window['go']['main']['App']['GetStitchedText'](arg1);
+}
+
 export function GetSupportedFormats() {
   return window['go']['main']['App']['GetSupportedFormats']();
 }
@@ -90,10 +386,66 @@ export function GetSupportedModels() {
   return window['go']['main']['App']['GetSupportedModels']();
 }
 
+export function GetTagIndex(arg1) {
+  return window['go']['main']['App']['GetTagIndex'](arg1);
+}
+
+export function GetTaskStatus(arg1) {
+  return window['go']['main']['App']['GetTaskStatus'](arg1);
+}
+
+export function GetWatchFolderStatus() {
+  return window['go']['main']['App']['GetWatchFolderStatus']();
+}
+
 export function Greet(arg1) {
   return window['go']['main']['App']['Greet'](arg1);
 }
 
+export function ImportGlossary(arg1) {
+  return window['go']['main']['App']['ImportGlossary'](arg1);
+}
+
+export function ImportHistory(arg1) {
+  return window['go']['main']['App']['ImportHistory'](arg1);
+}
+
+export function ImportSettings(arg1) {
+  return window['go']['main']['App']['ImportSettings'](arg1);
+}
+
+export function ImportTextLayer(arg1, arg2) {
+  return window['go']['main']['App']['ImportTextLayer'](arg1, arg2);
+}
+
+export function ImportZip(arg1, arg2) {
+  return window['go']['main']['App']['ImportZip'](arg1, arg2);
+}
+
+export function IntakeFiles(arg1, arg2) {
+  return window['go']['main']['App']['IntakeFiles'](arg1, arg2);
+}
+
+export function ListActiveTasks() {
+  return window['go']['main']['App']['ListActiveTasks']();
+}
+
+export function ListBookmarks() {
+  return window['go']['main']['App']['ListBookmarks']();
+}
+
+export function ListHistory(arg1) {
+  return window['go']['main']['App']['ListHistory'](arg1);
+}
+
+export function ListPageRevisions(arg1) {
+  return window['go']['main']['App']['ListPageRevisions'](arg1);
+}
+
+export function ListScanners() {
+  return window['go']['main']['App']['ListScanners']();
+}
+
 export function LoadDocument(arg1) {
   return window['go']['main']['App']['LoadDocument'](arg1);
 }
@@ -102,10 +454,58 @@ export function LoadPDF(arg1) {
   return window['go']['main']['App']['LoadPDF'](arg1);
 }
 
+export function LockEncryption() {
+  return window['go']['main']['App']['LockEncryption']();
+}
+
+export function MoveQueueItem(arg1, arg2) {
+  return window['go']['main']['App']['MoveQueueItem'](arg1, arg2);
+}
+
+export function OCRFromClipboard() {
+  return window['go']['main']['App']['OCRFromClipboard']();
+}
+
+export function OpenDocument(arg1) {
+  return window['go']['main']['App']['OpenDocument'](arg1);
+}
+
+export function OpenEmailAttachments(arg1, arg2) {
+  return window['go']['main']['App']['OpenEmailAttachments'](arg1, arg2);
+}
+
+export function PauseDocumentProcessing(arg1) {
+  return window['go']['main']['App']['PauseDocumentProcessing'](arg1);
+}
+
 export function PauseProcessing() {
   return window['go']['main']['App']['PauseProcessing']();
 }
 
+export function PauseQueueItem(arg1) {
+  return window['go']['main']['App']['PauseQueueItem'](arg1);
+}
+
+export function PreviewFootnotes() {
+  return window['go']['main']['App']['PreviewFootnotes']();
+}
+
+export function PreviewGlossary(arg1, arg2) {
+  return window['go']['main']['App']['PreviewGlossary'](arg1, arg2);
+}
+
+export function PreviewTextCleanup(arg1, arg2) {
+  return window['go']['main']['App']['PreviewTextCleanup'](arg1, arg2);
+}
+
+export function PrioritizePage(arg1) {
+  return window['go']['main']['App']['PrioritizePage'](arg1);
+}
+
+export function ProbeModelCapability(arg1, arg2) {
+  return window['go']['main']['App']['ProbeModelCapability'](arg1, arg2);
+}
+
 export function ProcessPages(arg1) {
   return window['go']['main']['App']['ProcessPages'](arg1);
 }
@@ -142,14 +542,98 @@ export function ProcessWithAIBatchForceContext(arg1, arg2, arg3) {
   return window['go']['main']['App']['ProcessWithAIBatchForceContext'](arg1, arg2, arg3);
 }
 
+export function ProcessWithAIBatchRolling(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ProcessWithAIBatchRolling'](arg1, arg2, arg3, arg4);
+}
+
 export function ProcessWithAIContext(arg1, arg2, arg3) {
   return window['go']['main']['App']['ProcessWithAIContext'](arg1, arg2, arg3);
 }
 
+export function ProofreadPages(arg1, arg2) {
+  return window['go']['main']['App']['ProofreadPages'](arg1, arg2);
+}
+
+export function RecognizePageRegion(arg1, arg2) {
+  return window['go']['main']['App']['RecognizePageRegion'](arg1, arg2);
+}
+
+export function RegenerateAPIToken() {
+  return window['go']['main']['App']['RegenerateAPIToken']();
+}
+
+export function RelocateDocument(arg1, arg2) {
+  return window['go']['main']['App']['RelocateDocument'](arg1, arg2);
+}
+
+export function RemovePageBookmark(arg1) {
+  return window['go']['main']['App']['RemovePageBookmark'](arg1);
+}
+
+export function RemoveQueueItem(arg1) {
+  return window['go']['main']['App']['RemoveQueueItem'](arg1);
+}
+
+export function ReplaceInResults(arg1, arg2, arg3, arg4, arg5, arg6) {
+  return window['go']['main']['App']['ReplaceInResults'](arg1, arg2, arg3, arg4, arg5, arg6);
+}
+
+export function ResetToDefaults(arg1) {
+  return window['go']['main']['App']['ResetToDefaults'](arg1);
+}
+
+export function ResolveFootnotes(arg1) {
+  return window['go']['main']['App']['ResolveFootnotes'](arg1);
+}
+
+export function RestoreArchivedHistory(arg1) {
+  return window['go']['main']['App']['RestoreArchivedHistory'](arg1);
+}
+
+export function RestorePageRevision(arg1) {
+  return window['go']['main']['App']['RestorePageRevision'](arg1);
+}
+
+export function ResumeDocumentProcessing(arg1) {
+  return window['go']['main']['App']['ResumeDocumentProcessing'](arg1);
+}
+
+export function ResumeJob(arg1) {
+  return window['go']['main']['App']['ResumeJob'](arg1);
+}
+
+export function ResumePendingJobs() {
+  return window['go']['main']['App']['ResumePendingJobs']();
+}
+
 export function ResumeProcessing() {
   return window['go']['main']['App']['ResumeProcessing']();
 }
 
+export function ResumeQueueItem(arg1) {
+  return window['go']['main']['App']['ResumeQueueItem'](arg1);
+}
+
+export function ResumeRecord(arg1) {
+  return window['go']['main']['App']['ResumeRecord'](arg1);
+}
+
+export function RetryFailedPages(arg1) {
+  return window['go']['main']['App']['RetryFailedPages'](arg1);
+}
+
+export function RunScheduledTask(arg1) {
+  return window['go']['main']['App']['RunScheduledTask'](arg1);
+}
+
+export function RunSelfTest(arg1) {
+  return window['go']['main']['App']['RunSelfTest'](arg1);
+}
+
+export function RunStorageMaintenance() {
+  return window['go']['main']['App']['RunStorageMaintenance']();
+}
+
 export function SaveBinaryFileWithDialog(arg1, arg2, arg3) {
   return window['go']['main']['App']['SaveBinaryFileWithDialog'](arg1, arg2, arg3);
 }
@@ -162,14 +646,110 @@ export function SearchHistory(arg1, arg2) {
   return window['go']['main']['App']['SearchHistory'](arg1, arg2);
 }
 
+export function SearchHistoryAdvanced(arg1) {
+  return window['go']['main']['App']['SearchHistoryAdvanced'](arg1);
+}
+
+export function SearchTags(arg1) {
+  return window['go']['main']['App']['SearchTags'](arg1);
+}
+
 export function SelectFile() {
   return window['go']['main']['App']['SelectFile']();
 }
 
+export function SetDocumentProfile(arg1, arg2) {
+  return window['go']['main']['App']['SetDocumentProfile'](arg1, arg2);
+}
+
+export function SetExportApprovedOnly(arg1) {
+  return window['go']['main']['App']['SetExportApprovedOnly'](arg1);
+}
+
+export function SetLogLevel(arg1) {
+  return window['go']['main']['App']['SetLogLevel'](arg1);
+}
+
+export function SetPageBookmark(arg1, arg2) {
+  return window['go']['main']['App']['SetPageBookmark'](arg1, arg2);
+}
+
+export function SetPageReviewStatus(arg1, arg2) {
+  return window['go']['main']['App']['SetPageReviewStatus'](arg1, arg2);
+}
+
+export function SetPagesExcluded(arg1, arg2) {
+  return window['go']['main']['App']['SetPagesExcluded'](arg1, arg2);
+}
+
+export function SetProofreadEditStatus(arg1, arg2) {
+  return window['go']['main']['App']['SetProofreadEditStatus'](arg1, arg2);
+}
+
+export function SetQueueConcurrency(arg1) {
+  return window['go']['main']['App']['SetQueueConcurrency'](arg1);
+}
+
+export function SetViewedPage(arg1) {
+  return window['go']['main']['App']['SetViewedPage'](arg1);
+}
+
+export function SplicePageText(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['SplicePageText'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function StitchTexts(arg1) {
+  return window['go']['main']['App']['StitchTexts'](arg1);
+}
+
+export function SummarizeDocument(arg1) {
+  return window['go']['main']['App']['SummarizeDocument'](arg1);
+}
+
+export function SwitchDocument(arg1) {
+  return window['go']['main']['App']['SwitchDocument'](arg1);
+}
+
+export function TagPages(arg1) {
+  return window['go']['main']['App']['TagPages'](arg1);
+}
+
 export function TestAIConnection() {
   return window['go']['main']['App']['TestAIConnection']();
 }
 
+export function TestDesktopNotification() {
+  return window['go']['main']['App']['TestDesktopNotification']();
+}
+
+export function TestNotion() {
+  return window['go']['main']['App']['TestNotion']();
+}
+
+export function TestPaperless() {
+  return window['go']['main']['App']['TestPaperless']();
+}
+
+export function TestPostProcessRules(arg1, arg2, arg3) {
+  return window['go']['main']['App']['TestPostProcessRules'](arg1, arg2, arg3);
+}
+
+export function TestRemoteUpload() {
+  return window['go']['main']['App']['TestRemoteUpload']();
+}
+
+export function TestWebhook() {
+  return window['go']['main']['App']['TestWebhook']();
+}
+
+export function UnlockEncryption(arg1) {
+  return window['go']['main']['App']['UnlockEncryption'](arg1);
+}
+
+export function UpdateAnnotation(arg1, arg2) {
+  return window['go']['main']['App']['UpdateAnnotation'](arg1, arg2);
+}
+
 export function UpdateConfig(arg1) {
   return window['go']['main']['App']['UpdateConfig'](arg1);
 }
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"pdf-ocr-ai/pkg/config"
+	"pdf-ocr-ai/pkg/glossary"
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// GlossaryPreview 术语替换预览
+type GlossaryPreview struct {
+	Text         string `json:"text"`
+	Replacements int    `json:"replacements"`
+}
+
+// PreviewGlossary 按术语表替换文本，用于在设置中检查（尚未保存的）术语效果
+func (a *App) PreviewGlossary(text string, terms []config.GlossaryTerm) GlossaryPreview {
+	result, count := glossary.Apply(text, terms)
+	return GlossaryPreview{Text: result, Replacements: count}
+}
+
+// ImportGlossary 从CSV文件导入术语（每行：原写法,统一写法[,说明]），path为空时弹出选择对话框
+// 与已有条目原写法相同的行覆盖原条目，返回导入的条目数
+func (a *App) ImportGlossary(path string) (int, error) {
+	if path == "" {
+		var err error
+		path, err = runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+			Filters: []runtime.FileFilter{{DisplayName: "CSV文件", Pattern: "*.csv"}},
+			Title:   "导入术语表",
+		})
+		if err != nil || path == "" {
+			return 0, err
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("打开文件失败: %w", err)
+	}
+	defer file.Close()
+
+	imported, err := readGlossaryCSV(file)
+	if err != nil {
+		return 0, err
+	}
+
+	cfg := a.configManager.GetConfig()
+	cfg.Glossary.Terms = mergeGlossaryTerms(cfg.Glossary.Terms, imported)
+	if err := a.UpdateConfig(cfg); err != nil {
+		return 0, err
+	}
+
+	logger.Infof("已从 %s 导入 %d 条术语", path, len(imported))
+	return len(imported), nil
+}
+
+// readGlossaryCSV 读取术语CSV，跳过空行和表头（首行第一列为 term 或 原写法）
+func readGlossaryCSV(r io.Reader) ([]config.GlossaryTerm, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var terms []config.GlossaryTerm
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("解析CSV失败: %w", err)
+		}
+		if len(record) > 0 {
+			record[0] = strings.TrimPrefix(record[0], "\ufeff")
+		}
+		if line == 1 && len(record) > 0 && (strings.EqualFold(record[0], "term") || record[0] == "原写法") {
+			continue
+		}
+		if len(record) == 0 || (len(record) == 1 && strings.TrimSpace(record[0]) == "") {
+			continue
+		}
+		if len(record) < 2 {
+			return nil, fmt.Errorf("第%d行缺少统一写法", line)
+		}
+
+		term := config.GlossaryTerm{
+			Term:      strings.TrimSpace(record[0]),
+			Preferred: strings.TrimSpace(record[1]),
+		}
+		if len(record) > 2 {
+			term.Note = strings.TrimSpace(record[2])
+		}
+		terms = append(terms, term)
+	}
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("文件中没有术语")
+	}
+	return terms, nil
+}
+
+// mergeGlossaryTerms 合并术语，原写法相同（不区分大小写）时使用新条目
+func mergeGlossaryTerms(existing, imported []config.GlossaryTerm) []config.GlossaryTerm {
+	index := make(map[string]int)
+	merged := append([]config.GlossaryTerm(nil), existing...)
+	for i, term := range merged {
+		index[strings.ToLower(term.Term)] = i
+	}
+	for _, term := range imported {
+		key := strings.ToLower(term.Term)
+		if i, ok := index[key]; ok {
+			term.CaseSensitive = merged[i].CaseSensitive
+			merged[i] = term
+			continue
+		}
+		index[key] = len(merged)
+		merged = append(merged, term)
+	}
+	return merged
+}
+
+// glossaryTerms 获取生效的术语表，未启用时返回nil
+func (a *App) glossaryTerms() []config.GlossaryTerm {
+	cfg := a.configManager.GetConfig().Glossary
+	if !cfg.Enabled {
+		return nil
+	}
+	return cfg.Terms
+}
+
+// processTextWithGlossary 使用AI处理文本：提示词中加入术语要求，并在结果中统一替换术语
+func (a *App) processTextWithGlossary(ctx context.Context, text, prompt string) (string, error) {
+	terms := a.glossaryTerms()
+	if section := glossary.PromptSection(terms); section != "" {
+		prompt = prompt + "\n\n" + section
+	}
+
+	result, err := a.ocrClient.ProcessText(ctx, text, prompt)
+	if err != nil {
+		return "", err
+	}
+
+	result, replaced := glossary.Apply(result, terms)
+	if replaced > 0 {
+		logger.Debugf("按术语表统一了 %d 处写法", replaced)
+	}
+	return result, nil
+}
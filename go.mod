@@ -12,7 +12,9 @@ require (
 	github.com/sashabaranov/go-openai v1.40.1
 	github.com/unidoc/unipdf/v3 v3.69.0
 	github.com/wailsapp/wails/v2 v2.10.1
+	golang.org/x/crypto v0.38.0
 	golang.org/x/image v0.27.0
+	golang.org/x/text v0.25.0
 )
 
 require (
@@ -56,10 +58,8 @@ require (
 	github.com/valyala/fasttemplate v1.2.2 // indirect
 	github.com/wailsapp/go-webview2 v1.0.19 // indirect
 	github.com/wailsapp/mimetype v1.4.1 // indirect
-	golang.org/x/crypto v0.38.0 // indirect
 	golang.org/x/net v0.40.0 // indirect
 	golang.org/x/sys v0.33.0 // indirect
-	golang.org/x/text v0.25.0 // indirect
 	golang.org/x/xerrors v0.0.0-20240903120638-7835f813f4da // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
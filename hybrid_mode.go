@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"pdf-ocr-ai/pkg/config"
+	"pdf-ocr-ai/pkg/history"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/pdf"
+	"pdf-ocr-ai/pkg/postprocess"
+	"pdf-ocr-ai/pkg/revisions"
+)
+
+// defaultHybridMinChars 混合模式下文本层至少包含的文字数（不含空白和标点），低于该值整页OCR
+const defaultHybridMinChars = 200
+
+// hybridFigureMinSide 混合模式下需要识别的插图短边最小像素，更小的图标、线条等被忽略
+const hybridFigureMinSide = 300
+
+// hybridFigureMaxCoverage 插图绘制区域占页面面积的最大比例，更大的是整页扫描图片，其文字已在文本层中
+const hybridFigureMaxCoverage = 0.6
+
+// hybridPage 混合模式下文本层完整的页面：使用原生文本，只识别其中的插图
+type hybridPage struct {
+	text    string
+	figures []string
+	cleanup func()
+}
+
+// prepareHybridPage 批量处理的渲染阶段：启用混合模式且文本层完整时提取页面插图，返回nil表示需要整页OCR
+func (a *App) prepareHybridPage(doc *pdf.PDFDocument, pageNum int) *hybridPage {
+	renderCfg := a.configManager.GetConfig().Render
+	if !renderCfg.Hybrid || pdf.IsImageFile(doc.FilePath) || pdf.IsDjVuFile(doc.FilePath) {
+		return nil
+	}
+	minChars := renderCfg.HybridMinChars
+	if minChars <= 0 {
+		minChars = defaultHybridMinChars
+	}
+
+	page := doc.Pages[pageNum-1]
+	text := page.Text
+	if text == "" {
+		extracted, hasText, err := a.pdfProcessor.ExtractNativeText(doc.FilePath, pageNum)
+		if err != nil {
+			logger.Warnf("提取第%d页文本层失败，按整页识别: %v", pageNum, err)
+			return nil
+		}
+		a.mu.Lock()
+		page.Text, page.HasText = extracted, hasText
+		a.mu.Unlock()
+		text = extracted
+	}
+	text = strings.TrimSpace(text)
+	if countTextRunes(normalizeForCompare(text)) < minChars || lowConfidenceText(text) || garbledRatio(text) > garbledTextRatio {
+		return nil
+	}
+
+	figureDir, err := os.MkdirTemp("", "pdf_figures_")
+	if err != nil {
+		logger.Warnf("创建插图目录失败，按整页识别: %v", err)
+		return nil
+	}
+	figures, err := pdf.ExtractPageFigures(doc.FilePath, pageNum, figureDir, hybridFigureMinSide, hybridFigureMaxCoverage)
+	if err != nil {
+		os.RemoveAll(figureDir)
+		logger.Warnf("%v，按整页识别", err)
+		return nil
+	}
+
+	logger.Infof("混合模式: 第%d页使用文本层，识别 %d 张插图", pageNum, len(figures))
+	return &hybridPage{text: text, figures: figures, cleanup: func() { os.RemoveAll(figureDir) }}
+}
+
+// recognizeHybridPage 批量处理的识别阶段：识别页面插图，追加到原生文本后作为页面的OCR结果
+func (a *App) recognizeHybridPage(ctx context.Context, doc *pdf.PDFDocument, pageNum int, hybrid *hybridPage, profile *config.ProcessingProfile, historyRecord *history.HistoryRecord, startTime time.Time) error {
+	parts := []string{hybrid.text}
+	for i, figure := range hybrid.figures {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		result, err := a.recognizePageImage(ctx, figure, profileOCRModel(profile))
+		if err != nil {
+			logger.Errorf("页面 %d 插图 %d OCR识别失败: %v", pageNum, i+1, err)
+			return fmt.Errorf("OCR识别失败: %w", err)
+		}
+		if result.Error != "" {
+			return fmt.Errorf("OCR识别错误: %s", result.Error)
+		}
+		if text := strings.TrimSpace(result.Text); text != "" {
+			parts = append(parts, text)
+		}
+	}
+
+	text := a.postProcessResult(doc, postprocess.TargetOCR, strings.Join(parts, "\n\n"))
+	a.pdfProcessor.UpdatePageOCR(doc, pageNum, text)
+	if err := a.savePageToCache(doc, pageNum, text, "", revisions.SourceHybrid); err != nil {
+		logger.Errorf("保存缓存失败: %v", err)
+	}
+
+	a.autoTagPage(ctx, doc, pageNum)
+
+	if historyRecord != nil {
+		page := &history.HistoryPage{
+			HistoryID:      historyRecord.ID,
+			PageNumber:     pageNum,
+			OriginalText:   doc.Pages[pageNum-1].Text,
+			OCRText:        text,
+			ProcessingTime: time.Since(startTime).Seconds(),
+		}
+		if err := a.historyManager.AddPage(page); err != nil {
+			logger.Errorf("保存历史记录失败: %v", err)
+		}
+	}
+	return nil
+}
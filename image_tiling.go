@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	imageprocessor "pdf-ocr-ai/pkg/image"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/ocr"
+	"pdf-ocr-ai/pkg/pdf"
+	"pdf-ocr-ai/pkg/stitch"
+)
+
+// recognizePageImage 识别页面图片，启用切分时过高或过宽的页面切分为有重叠的图块分别识别，再删除重复行合并
+func (a *App) recognizePageImage(ctx context.Context, imagePath, model string) (*ocr.OCRResult, error) {
+	renderCfg := a.configManager.GetConfig().Render
+	if !renderCfg.Tiling {
+		return a.ocrClient.RecognizeImage(ctx, imagePath, model)
+	}
+
+	tileDir, err := os.MkdirTemp("", "pdf_tiles_")
+	if err != nil {
+		logger.Warnf("创建图块目录失败，按整页识别: %v", err)
+		return a.ocrClient.RecognizeImage(ctx, imagePath, model)
+	}
+	defer os.RemoveAll(tileDir)
+
+	tiles, err := imageprocessor.SplitTiles(imagePath, tileDir, renderCfg.TileAspectRatio)
+	if err != nil {
+		logger.Warnf("切分图块失败，按整页识别: %v", err)
+	}
+	if len(tiles) == 0 {
+		return a.ocrClient.RecognizeImage(ctx, imagePath, model)
+	}
+
+	logger.Infof("页面图片过长，切分为%d个图块识别: %s", len(tiles), imagePath)
+	texts := make([]string, 0, len(tiles))
+	confidence := 0.0
+	for _, tile := range tiles {
+		result, err := a.ocrClient.RecognizeImage(ctx, tile, model)
+		if err != nil {
+			return nil, err
+		}
+		if result.Error != "" {
+			return result, nil
+		}
+		texts = append(texts, result.Text)
+		confidence += result.Confidence
+	}
+	return &ocr.OCRResult{
+		Text:       stitch.MergeTiles(texts),
+		Confidence: confidence / float64(len(tiles)),
+	}, nil
+}
+
+// tilingSourceImage 启用切分时，需要切分的图片文档使用原尺寸的图片识别，而不是按最大尺寸缩小后的预览图片
+func (a *App) tilingSourceImage(doc *pdf.PDFDocument, pageNum int) (string, bool) {
+	renderCfg := a.configManager.GetConfig().Render
+	if !renderCfg.Tiling || !pdf.IsImageFile(doc.FilePath) || pageNum != 1 || len(doc.Pages) == 0 {
+		return "", false
+	}
+
+	maxAspect := renderCfg.TileAspectRatio
+	if maxAspect <= 1 {
+		maxAspect = imageprocessor.DefaultTileAspectRatio
+	}
+	page := doc.Pages[0]
+	long, short := max(page.Width, page.Height), min(page.Width, page.Height)
+	if short <= 0 || long/short <= maxAspect {
+		return "", false
+	}
+
+	path, err := a.pdfProcessor.RenderImageOriginal(doc)
+	if err != nil {
+		logger.Warnf("转换原尺寸图片失败，使用缩小后的图片识别: %v", err)
+		return "", false
+	}
+	return path, true
+}
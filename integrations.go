@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/notion"
+	"pdf-ocr-ai/pkg/outline"
+	"pdf-ocr-ai/pkg/pdf"
+)
+
+// Obsidian笔记名和标签中不能使用的字符
+var (
+	obsidianUnsafeNamePattern = regexp.MustCompile(`[\\/:*?"<>|#^\[\]]+`)
+	obsidianUnsafeTagPattern  = regexp.MustCompile(`[\s#,，.。;；:：!！?？'"()\[\]{}]+`)
+)
+
+// ObsidianExportResult 导出到Obsidian的结果
+type ObsidianExportResult struct {
+	Index string   `json:"index"` // 文档笔记，按章节写入时为链接各章的索引笔记
+	Notes []string `json:"notes"` // 按章节写入时各章的笔记
+}
+
+// ExportToObsidian 将当前文档已处理的页面写入配置的Obsidian库，排除的页面除外
+// 默认每个文档一个笔记；配置按章节写入时每章一个笔记，索引笔记和各章之间用 [[wiki-link]] 相互链接
+// 同名笔记会被覆盖，重新导出即可更新；textType: ocr、ai，其他值优先OCR文本
+func (a *App) ExportToObsidian(textType string) (*ObsidianExportResult, error) {
+	doc := a.activeDocument()
+	if doc == nil {
+		return nil, i18n.Errorf("doc.not_loaded")
+	}
+	cfg := a.configManager.GetConfig().Integrations.Obsidian
+	if cfg.VaultPath == "" {
+		return nil, fmt.Errorf("未配置Obsidian库目录")
+	}
+	if info, err := os.Stat(cfg.VaultPath); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("Obsidian库目录不存在: %s", cfg.VaultPath)
+	}
+	dir := filepath.Join(cfg.VaultPath, cfg.Folder)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("创建笔记目录失败: %w", err)
+	}
+
+	pageNums, texts, err := a.exportPageTexts(doc, nil, textType, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []string
+	if cfg.Tags {
+		for _, entry := range a.topicIndex(doc) {
+			if tag := obsidianTag(entry.Tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	title := documentTitle(doc)
+	noteName := obsidianNoteName(title)
+	frontMatter := a.markdownFrontMatter(title, doc.Author, doc.FilePath, len(pageNums), tags)
+	result := &ObsidianExportResult{Notes: []string{}}
+
+	chapters := outline.SplitChapters(texts)
+	if !cfg.SplitChapters || len(chapters) < 2 {
+		var builder strings.Builder
+		builder.WriteString(frontMatter)
+		for i, text := range texts {
+			builder.WriteString("## " + i18n.T("export.page", pageNums[i]) + "\n\n")
+			builder.WriteString(strings.TrimSpace(text) + "\n\n")
+		}
+		if result.Index, err = writeObsidianNote(dir, noteName, builder.String()); err != nil {
+			return nil, err
+		}
+		logger.Infof("已导出到Obsidian: %s", result.Index)
+		return result, nil
+	}
+
+	// 按章节写入：每章的笔记链接回索引笔记和前后两章
+	names := make([]string, len(chapters))
+	for i, chapter := range chapters {
+		names[i] = fmt.Sprintf("%s - %02d", noteName, i+1)
+		if chapter.Title != "" {
+			names[i] += " " + obsidianNoteName(chapter.Title)
+		}
+	}
+
+	var index strings.Builder
+	index.WriteString(frontMatter)
+	for i, chapter := range chapters {
+		label := chapter.Title
+		if label == "" {
+			label = names[i]
+		}
+		index.WriteString(fmt.Sprintf("- [[%s|%s]]\n", names[i], strings.ReplaceAll(label, "|", " ")))
+
+		var note strings.Builder
+		note.WriteString(a.markdownFrontMatter(label, doc.Author, doc.FilePath, len(chapter.Pages), tags))
+		links := []string{fmt.Sprintf("[[%s|%s]]", noteName, strings.ReplaceAll(title, "|", " "))}
+		if i > 0 {
+			links = append(links, fmt.Sprintf("← [[%s]]", names[i-1]))
+		}
+		if i < len(chapters)-1 {
+			links = append(links, fmt.Sprintf("[[%s]] →", names[i+1]))
+		}
+		note.WriteString(strings.Join(links, " · ") + "\n\n")
+		note.WriteString(strings.TrimSpace(strings.Join(chapter.Pages, "\n\n")) + "\n")
+
+		path, err := writeObsidianNote(dir, names[i], note.String())
+		if err != nil {
+			return nil, err
+		}
+		result.Notes = append(result.Notes, path)
+	}
+	if result.Index, err = writeObsidianNote(dir, noteName, index.String()); err != nil {
+		return nil, err
+	}
+
+	logger.Infof("已导出到Obsidian: %s（%d 个章节）", result.Index, len(result.Notes))
+	return result, nil
+}
+
+// ExportToNotion 在配置的Notion页面下为当前文档创建子页面，每页文本前加页标题，排除的页面除外
+// 返回新页面的链接；textType: ocr、ai，其他值优先OCR文本
+func (a *App) ExportToNotion(textType string) (string, error) {
+	doc := a.activeDocument()
+	if doc == nil {
+		return "", i18n.Errorf("doc.not_loaded")
+	}
+	client, parentID, err := a.notionClient()
+	if err != nil {
+		return "", err
+	}
+
+	pageNums, texts, err := a.exportPageTexts(doc, nil, textType, false)
+	if err != nil {
+		return "", err
+	}
+	var blocks []notion.Block
+	for i, text := range texts {
+		blocks = append(blocks, notion.Heading(2, i18n.T("export.page", pageNums[i])))
+		blocks = append(blocks, notion.MarkdownBlocks(text)...)
+	}
+
+	page, err := client.CreatePage(a.ctx, parentID, documentTitle(doc), blocks)
+	if err != nil {
+		return "", err
+	}
+	logger.Infof("已推送 %d 页到Notion: %s", len(pageNums), page.URL)
+	return page.URL, nil
+}
+
+// TestNotion 验证Notion令牌和父页面配置
+func (a *App) TestNotion() error {
+	client, parentID, err := a.notionClient()
+	if err != nil {
+		return err
+	}
+	return client.CheckPage(a.ctx, parentID)
+}
+
+// notionClient 按配置创建Notion客户端并解析父页面ID
+func (a *App) notionClient() (*notion.Client, string, error) {
+	cfg := a.configManager.GetConfig().Integrations.Notion
+	if cfg.Token == "" || cfg.ParentPageID == "" {
+		return nil, "", fmt.Errorf("未配置Notion令牌或父页面")
+	}
+	parentID, err := notion.ParsePageID(cfg.ParentPageID)
+	if err != nil {
+		return nil, "", err
+	}
+	return notion.NewClient(cfg.Token), parentID, nil
+}
+
+// documentTitle 文档标题，没有标题时使用文件名
+func documentTitle(doc *pdf.PDFDocument) string {
+	if doc.Title != "" {
+		return doc.Title
+	}
+	return strings.TrimSuffix(filepath.Base(doc.FilePath), filepath.Ext(doc.FilePath))
+}
+
+// obsidianNoteName 将标题转换为可用作Obsidian笔记名（也是 wiki-link 目标）的文件名
+func obsidianNoteName(title string) string {
+	name := strings.Join(strings.Fields(obsidianUnsafeNamePattern.ReplaceAllString(title, " ")), " ")
+	name = strings.Trim(name, ". ")
+	if runes := []rune(name); len(runes) > 80 {
+		name = strings.TrimSpace(string(runes[:80]))
+	}
+	if name == "" {
+		name = "untitled"
+	}
+	return name
+}
+
+// obsidianTag 将主题标签转换为Obsidian标签：空白和标点替换为连字符
+func obsidianTag(tag string) string {
+	return strings.Trim(obsidianUnsafeTagPattern.ReplaceAllString(tag, "-"), "-")
+}
+
+// writeObsidianNote 写入笔记，返回文件路径
+func writeObsidianNote(dir, name, content string) (string, error) {
+	path := filepath.Join(dir, name+".md")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("写入笔记失败: %w", err)
+	}
+	return path, nil
+}
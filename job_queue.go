@@ -0,0 +1,255 @@
+package main
+
+import (
+	"fmt"
+
+	"pdf-ocr-ai/pkg/history"
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/jobs"
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// createJob 持久化一个新的批量任务，失败时仅记录日志，不影响处理
+func (a *App) createJob(documentPath string, taskType jobs.TaskType, pageNumbers []int, prompt string, contextMode, rollingContext, forceReprocess bool, historyRecord *history.HistoryRecord) *jobs.Job {
+	if a.jobManager == nil {
+		return nil
+	}
+
+	var historyID *int
+	if historyRecord != nil {
+		historyID = &historyRecord.ID
+	}
+	job, err := a.jobManager.CreateJob(&jobs.Job{
+		HistoryID:      historyID,
+		DocumentPath:   documentPath,
+		TaskType:       taskType,
+		Pages:          pageNumbers,
+		Prompt:         prompt,
+		ContextMode:    contextMode,
+		RollingContext: rollingContext,
+		ForceReprocess: forceReprocess,
+	})
+	if err != nil {
+		logger.Errorf("创建持久化任务失败: %v", err)
+		return nil
+	}
+	return job
+}
+
+// recordJob 获取历史记录最初使用的持久化任务，用于继续或重试时沿用原来的提示词和上下文设置
+func (a *App) recordJob(historyID int) (*jobs.Job, error) {
+	if a.jobManager == nil {
+		return nil, i18n.Errorf("init.component")
+	}
+	job, err := a.jobManager.LatestJobForHistory(historyID)
+	if err != nil {
+		return nil, fmt.Errorf("读取记录的处理任务失败: %w", err)
+	}
+	if job == nil {
+		return nil, fmt.Errorf("找不到记录%d使用的提示词和上下文设置，请重新选择页面进行AI处理", historyID)
+	}
+	return job, nil
+}
+
+// markJobPageDone 记录任务中已完成的页面
+func (a *App) markJobPageDone(job *jobs.Job, pageNumber int) {
+	if job == nil {
+		return
+	}
+	if err := a.jobManager.MarkPageDone(job.ID, pageNumber); err != nil {
+		logger.Errorf("记录任务进度失败 (任务%d, 第%d页): %v", job.ID, pageNumber, err)
+	}
+}
+
+// finishJob 更新任务的最终状态
+func (a *App) finishJob(job *jobs.Job, status jobs.JobStatus, errorMsg string) {
+	if job == nil {
+		return
+	}
+	if err := a.jobManager.UpdateStatus(job.ID, status, errorMsg); err != nil {
+		logger.Errorf("更新任务状态失败 (任务%d): %v", job.ID, err)
+	}
+}
+
+// finishBatchJob 批次正常结束时更新任务状态：全部成功为完成，部分页面失败为部分完成，全部失败为失败
+func (a *App) finishBatchJob(job *jobs.Job, succeeded, failed int) {
+	switch {
+	case failed == 0:
+		a.finishJob(job, jobs.StatusCompleted, "")
+	case succeeded == 0:
+		a.finishJob(job, jobs.StatusFailed, "所有页面处理失败")
+	default:
+		a.finishJob(job, jobs.StatusPartial, fmt.Sprintf("%d 页处理失败", failed))
+	}
+}
+
+// detectInterruptedJobs 启动时检测上次未完成的任务，并通知前端提示用户
+func (a *App) detectInterruptedJobs() {
+	if a.jobManager == nil {
+		return
+	}
+
+	count, err := a.jobManager.MarkRunningAsInterrupted()
+	if err != nil {
+		logger.Errorf("标记中断任务失败: %v", err)
+		return
+	}
+	if count > 0 {
+		logger.Infof("检测到 %d 个上次运行中断的任务", count)
+	}
+
+	interrupted, err := a.jobManager.GetInterruptedJobs()
+	if err != nil {
+		logger.Errorf("获取中断任务失败: %v", err)
+		return
+	}
+
+	if len(interrupted) > 0 {
+		a.emit("interrupted-jobs", interrupted)
+	}
+}
+
+// GetInterruptedJobs 获取上次运行中断、可继续的任务
+func (a *App) GetInterruptedJobs() ([]*jobs.Job, error) {
+	if a.jobManager == nil {
+		return nil, i18n.Errorf("init.component")
+	}
+	return a.jobManager.GetInterruptedJobs()
+}
+
+// ResumePendingJobs 在后台继续所有中断的任务，返回要恢复的任务数
+func (a *App) ResumePendingJobs() (int, error) {
+	interrupted, err := a.GetInterruptedJobs()
+	if err != nil {
+		return 0, err
+	}
+
+	// 同一文档的任务依次执行，避免后一个任务因文档已有批次在运行而失败
+	byDocument := make(map[string][]*jobs.Job)
+	var paths []string
+	for _, job := range interrupted {
+		if _, ok := byDocument[job.DocumentPath]; !ok {
+			paths = append(paths, job.DocumentPath)
+		}
+		byDocument[job.DocumentPath] = append(byDocument[job.DocumentPath], job)
+	}
+	for _, path := range paths {
+		go a.resumeDocumentJobs(byDocument[path])
+	}
+
+	return len(interrupted), nil
+}
+
+// resumeDocumentJobs 依次继续同一文档的中断任务，前一个任务结束后再开始下一个
+func (a *App) resumeDocumentJobs(documentJobs []*jobs.Job) {
+	defer logger.RecoverPanic("resumeDocumentJobs")
+
+	for _, job := range documentJobs {
+		session, remaining, err := a.prepareResume(job)
+		if err != nil {
+			logger.Errorf("恢复任务%d失败: %v", job.ID, err)
+			continue
+		}
+		if session == nil {
+			continue
+		}
+
+		record := a.jobRecord(job)
+		if job.TaskType == jobs.TaskAI {
+			taskID, ctx := a.startTask(TaskKindBatchAI, session, remaining)
+			a.reportBatchBusy(ctx, a.processWithAIBatch(ctx, session, remaining, job.Prompt, job.ForceReprocess, job.ContextMode, job.RollingContext, job, record))
+			a.tasks.finish(taskID)
+		} else {
+			taskID, ctx := a.startTask(TaskKindBatchOCR, session, remaining)
+			a.reportBatchBusy(ctx, a.processPagesBatch(ctx, session, remaining, job.ForceReprocess, job, record))
+			a.tasks.finish(taskID)
+		}
+	}
+}
+
+// ResumeJob 继续指定的中断任务
+func (a *App) ResumeJob(jobID int) error {
+	if a.jobManager == nil {
+		return i18n.Errorf("init.component")
+	}
+
+	job, err := a.jobManager.GetJob(jobID)
+	if err != nil {
+		return fmt.Errorf("获取任务失败: %w", err)
+	}
+	if job == nil {
+		return fmt.Errorf("任务不存在: %d", jobID)
+	}
+	if job.Status != jobs.StatusInterrupted {
+		return fmt.Errorf("任务%d不是中断状态: %s", jobID, job.Status)
+	}
+
+	return a.resumeJob(job)
+}
+
+// DiscardJob 放弃中断的任务（已处理的页面结果保留在缓存中）
+func (a *App) DiscardJob(jobID int) error {
+	if a.jobManager == nil {
+		return i18n.Errorf("init.component")
+	}
+	return a.jobManager.DeleteJob(jobID)
+}
+
+// resumeJob 打开任务对应的文档并在后台处理剩余页面
+func (a *App) resumeJob(job *jobs.Job) error {
+	session, remaining, err := a.prepareResume(job)
+	if err != nil || session == nil {
+		return err
+	}
+
+	record := a.jobRecord(job)
+	switch job.TaskType {
+	case jobs.TaskAI:
+		a.startAIBatch(session, remaining, job.Prompt, job.ForceReprocess, job.ContextMode, job.RollingContext, job, record)
+	default:
+		a.startPagesBatch(session, remaining, job.ForceReprocess, job, record)
+	}
+
+	return nil
+}
+
+// jobRecord 获取任务对应的历史记录，恢复的任务继续写入同一条记录
+// 任务没有关联记录或记录已删除时返回nil，恢复时创建新记录
+func (a *App) jobRecord(job *jobs.Job) *history.HistoryRecord {
+	if job.HistoryID == nil || a.historyManager == nil {
+		return nil
+	}
+	record, err := a.historyManager.GetRecord(*job.HistoryID)
+	if err != nil {
+		logger.Warnf("读取任务%d的历史记录失败: %v", job.ID, err)
+		return nil
+	}
+	return record
+}
+
+// prepareResume 打开任务对应的文档并将任务标记为运行中，返回文档会话和剩余页面
+// 没有剩余页面时直接标记为完成，返回的会话为nil
+func (a *App) prepareResume(job *jobs.Job) (*DocumentSession, []int, error) {
+	remaining := job.RemainingPages()
+	if len(remaining) == 0 {
+		logger.Infof("任务%d没有剩余页面，标记为完成", job.ID)
+		return nil, nil, a.jobManager.UpdateStatus(job.ID, jobs.StatusCompleted, "")
+	}
+
+	documentID, err := a.OpenDocument(job.DocumentPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("打开任务文档失败: %w", err)
+	}
+
+	session, err := a.getSession(documentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := a.jobManager.UpdateStatus(job.ID, jobs.StatusRunning, ""); err != nil {
+		return nil, nil, fmt.Errorf("更新任务状态失败: %w", err)
+	}
+
+	logger.Infof("恢复任务%d: %s, 剩余 %d/%d 页", job.ID, job.DocumentPath, len(remaining), len(job.Pages))
+	return session, remaining, nil
+}
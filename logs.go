@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// applyLogLevel 应用配置中的日志级别
+func applyLogLevel(name string) {
+	if name == "" {
+		return
+	}
+	level, err := logger.ParseLevel(name)
+	if err != nil {
+		logger.Warnf("%v，使用默认级别", err)
+		return
+	}
+	logger.SetLevel(level)
+}
+
+// GetLogTail 获取日志文件的最后n行
+func (a *App) GetLogTail(n int) ([]string, error) {
+	return logger.Tail(n)
+}
+
+// GetLogLevel 获取当前日志级别
+func (a *App) GetLogLevel() string {
+	return strings.ToLower(logger.GetLevel().String())
+}
+
+// SetLogLevel 设置日志级别并保存到配置
+func (a *App) SetLogLevel(name string) error {
+	level, err := logger.ParseLevel(name)
+	if err != nil {
+		return err
+	}
+	logger.SetLevel(level)
+	logger.Infof("日志级别已设置为 %s", level)
+
+	if a.configManager == nil {
+		return nil
+	}
+	cfg := a.configManager.GetConfig()
+	cfg.Logging.Level = strings.ToLower(level.String())
+	if err := a.configManager.UpdateConfig(cfg); err != nil {
+		return fmt.Errorf("保存日志级别失败: %w", err)
+	}
+	return nil
+}
+
+// GetLogDirectory 获取日志目录
+func (a *App) GetLogDirectory() string {
+	return logger.Dir()
+}
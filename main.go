@@ -2,8 +2,13 @@ package main
 
 import (
 	"embed"
+	goruntime "runtime"
+
+	"pdf-ocr-ai/pkg/logger"
 
 	"github.com/wailsapp/wails/v2"
+	"github.com/wailsapp/wails/v2/pkg/menu"
+	"github.com/wailsapp/wails/v2/pkg/menu/keys"
 	"github.com/wailsapp/wails/v2/pkg/options"
 	"github.com/wailsapp/wails/v2/pkg/options/assetserver"
 )
@@ -12,9 +17,29 @@ import (
 var assets embed.FS
 
 func main() {
+	// 日志写入 ~/.pdfSeer/logs，崩溃时记录堆栈便于用户反馈问题
+	if dir, err := logger.DefaultDir(); err == nil {
+		if err := logger.Init(dir, logger.LevelInfo); err != nil {
+			println("Error:", err.Error())
+		}
+	}
+	defer logger.Close()
+	defer logger.RecoverPanic("main")
+
 	// Create an instance of the app structure
 	app := NewApp()
 
+	// 应用菜单（Wails v2 不支持系统级全局热键，快捷键在窗口获得焦点时生效）
+	appMenu := menu.NewMenu()
+	if goruntime.GOOS == "darwin" {
+		appMenu.Append(menu.AppMenu())
+		appMenu.Append(menu.EditMenu())
+	}
+	toolsMenu := appMenu.AddSubmenu("工具")
+	toolsMenu.AddText("识别剪贴板图片", keys.Combo("o", keys.CmdOrCtrlKey, keys.ShiftKey), func(_ *menu.CallbackData) {
+		go app.runClipboardOCRShortcut()
+	})
+
 	// Create application with options
 	err := wails.Run(&options.App{
 		Title:  "识文君 - PDF智能助手",
@@ -24,8 +49,13 @@ func main() {
 			Assets: assets,
 		},
 		BackgroundColour: &options.RGBA{R: 27, G: 38, B: 54, A: 1},
-		OnStartup:        app.startup,
-		OnShutdown:       app.shutdown,
+		Menu:             appMenu,
+		DragAndDrop: &options.DragAndDrop{
+			EnableFileDrop:     true,
+			DisableWebViewDrop: true,
+		},
+		OnStartup:  app.startup,
+		OnShutdown: app.shutdown,
 		Bind: []interface{}{
 			app,
 		},
@@ -35,6 +65,6 @@ func main() {
 	})
 
 	if err != nil {
-		println("Error:", err.Error())
+		logger.Errorf("应用运行失败: %v", err)
 	}
 }
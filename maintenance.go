@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"pdf-ocr-ai/pkg/config"
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// maintenanceInterval 自动存储维护的间隔
+const maintenanceInterval = 6 * time.Hour
+
+// maintenanceMu 防止定时任务和自动维护同时执行
+var maintenanceMu sync.Mutex
+
+// StorageMaintenanceReport 存储维护结果
+type StorageMaintenanceReport struct {
+	CacheExpired   int      `json:"cache_expired"`   // 超过 CacheTTL 删除的文档缓存数
+	CacheEvicted   int      `json:"cache_evicted"`   // 超过 MaxCacheSize 按最近使用时间淘汰的文档缓存数
+	HistoryRemoved int      `json:"history_removed"` // 超过 HistoryRetention 删除的历史记录数
+	JobsRemoved    int      `json:"jobs_removed"`    // 超过 HistoryRetention 删除的已结束任务数
+	CacheSize      int64    `json:"cache_size"`      // 维护后的缓存大小（字节）
+	FreedBytes     int64    `json:"freed_bytes"`     // 数据库文件释放的磁盘空间（字节）
+	Errors         []string `json:"errors,omitempty"`
+	StartedAt      string   `json:"started_at"`
+	FinishedAt     string   `json:"finished_at"`
+}
+
+// applyImageCacheConfig 应用渲染图片缓存容量配置
+func (a *App) applyImageCacheConfig(cfg config.StorageConfig) {
+	if a.cacheManager == nil {
+		return
+	}
+
+	var maxBytes int64
+	if cfg.MaxImageCacheSize != "" {
+		size, err := config.ParseByteSize(cfg.MaxImageCacheSize)
+		if err != nil {
+			logger.Warnf("图片缓存容量配置无效，使用默认值: %v", err)
+		}
+		maxBytes = size
+	}
+	a.cacheManager.Images().SetMaxBytes(maxBytes)
+}
+
+// startMaintenanceLoop 启动时执行一次存储维护，之后定期执行
+func (a *App) startMaintenanceLoop() {
+	stop := make(chan struct{})
+	a.mu.Lock()
+	a.maintenanceStop = stop
+	a.mu.Unlock()
+
+	go func() {
+		defer logger.RecoverPanic("存储维护")
+
+		if _, err := a.RunStorageMaintenance(); err != nil {
+			logger.Errorf("存储维护失败: %v", err)
+		}
+
+		ticker := time.NewTicker(maintenanceInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if _, err := a.RunStorageMaintenance(); err != nil {
+					logger.Errorf("存储维护失败: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// stopMaintenanceLoop 停止定期存储维护
+func (a *App) stopMaintenanceLoop() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.maintenanceStop != nil {
+		close(a.maintenanceStop)
+		a.maintenanceStop = nil
+	}
+}
+
+// RunStorageMaintenance 按存储配置清理过期缓存和历史记录、限制缓存大小，并发送 storage-maintenance 事件
+func (a *App) RunStorageMaintenance() (*StorageMaintenanceReport, error) {
+	if a.configManager == nil || a.store == nil || a.cacheManager == nil || a.historyManager == nil {
+		return nil, i18n.Errorf("init.component")
+	}
+	if !maintenanceMu.TryLock() {
+		return nil, fmt.Errorf("存储维护正在进行中")
+	}
+	defer maintenanceMu.Unlock()
+
+	storage := a.configManager.GetConfig().Storage
+	report := &StorageMaintenanceReport{StartedAt: time.Now().Format(time.RFC3339)}
+	sizeBefore := a.store.FilesSize()
+
+	addError := func(format string, args ...interface{}) {
+		message := fmt.Sprintf(format, args...)
+		logger.Warnf("存储维护: %s", message)
+		report.Errors = append(report.Errors, message)
+	}
+
+	// 保留时长为 never 时永久保留
+	if days, err := config.ParseRetentionDays(storage.CacheTTL); err != nil {
+		addError("缓存保留时长配置无效: %v", err)
+	} else if days > 0 {
+		if report.CacheExpired, err = a.cacheManager.CleanupOldCache(days); err != nil {
+			addError("清理缓存失败: %v", err)
+		}
+	}
+
+	if storage.MaxCacheSize != "" {
+		if maxBytes, err := config.ParseByteSize(storage.MaxCacheSize); err != nil {
+			addError("缓存容量配置无效: %v", err)
+		} else if report.CacheEvicted, _, err = a.cacheManager.EvictToSize(maxBytes); err != nil {
+			addError("淘汰缓存失败: %v", err)
+		}
+	}
+
+	if days, err := config.ParseRetentionDays(storage.HistoryRetention); err != nil {
+		addError("历史记录保留时长配置无效: %v", err)
+	} else {
+		if days > 0 {
+			if report.HistoryRemoved, err = a.historyManager.CleanupOldRecords(days); err != nil {
+				addError("清理历史记录失败: %v", err)
+			}
+		}
+		// 已结束的任务与历史记录保留相同时长（重试失败页面时读取原来的AI设置）
+		if a.jobManager != nil {
+			if report.JobsRemoved, err = a.jobManager.CleanupFinishedJobs(days); err != nil {
+				addError("清理已结束的任务失败: %v", err)
+			}
+		}
+	}
+
+	// 有数据被删除时压缩数据库，才能真正释放磁盘空间
+	if report.CacheExpired+report.CacheEvicted+report.HistoryRemoved > 0 {
+		if err := a.store.Vacuum(); err != nil {
+			addError("%v", err)
+		}
+	}
+
+	if size, err := a.cacheManager.Size(); err == nil {
+		report.CacheSize = size
+	}
+	if freed := sizeBefore - a.store.FilesSize(); freed > 0 {
+		report.FreedBytes = freed
+	}
+	report.FinishedAt = time.Now().Format(time.RFC3339)
+
+	logger.Infof("存储维护完成: 过期缓存 %d, 淘汰缓存 %d, 删除历史记录 %d, 删除任务 %d, 释放 %d 字节",
+		report.CacheExpired, report.CacheEvicted, report.HistoryRemoved, report.JobsRemoved, report.FreedBytes)
+	a.emit("storage-maintenance", report)
+
+	if len(report.Errors) > 0 {
+		return report, fmt.Errorf("存储维护部分失败: %s", report.Errors[0])
+	}
+	return report, nil
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"pdf-ocr-ai/pkg/i18n"
+	imageprocessor "pdf-ocr-ai/pkg/image"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/pdf"
+)
+
+// Markdown导出时嵌入页面图片的方式
+const (
+	MarkdownImagesRelative = "relative" // 图片保存到 <文件名>_images 目录，用相对路径引用
+	MarkdownImagesBase64   = "base64"   // 图片以 data URI 内嵌到Markdown中
+)
+
+// defaultMarkdownImageEdge 嵌入的页面图片默认最长边
+const defaultMarkdownImageEdge = 1600
+
+// MarkdownExportOptions Markdown导出选项
+type MarkdownExportOptions struct {
+	TextType      string `json:"text_type"`      // ocr 只用OCR文本，ai 只用AI处理文本，其他值优先OCR文本
+	FrontMatter   bool   `json:"front_matter"`   // 在开头写入YAML front matter（标题、作者、源文件、使用的模型、日期）
+	Images        string `json:"images"`         // 页面图片: relative、base64，为空时不嵌入
+	ImageMaxEdge  int    `json:"image_max_edge"` // 页面图片最长边，0 表示默认 1600
+	PageHeading   int    `json:"page_heading"`   // 每页标题（第 N 页）的级别 1-6，0 表示不写页标题
+	HeadingOffset int    `json:"heading_offset"` // 正文中 # 标题下调的级别数，最深为 6 级
+}
+
+// ExportMarkdown 将当前文档已处理的页面导出为Markdown，排除的页面除外，可直接放入Obsidian、Hugo等的内容目录
+// pageNumbers 为空时导出全部页面；弹出保存对话框，返回保存的路径
+func (a *App) ExportMarkdown(pageNumbers []int, options MarkdownExportOptions) (string, error) {
+	return a.exportMarkdown(pageNumbers, options, "")
+}
+
+// exportMarkdown 按选项生成Markdown并保存到 output，output 为空时弹出保存对话框
+func (a *App) exportMarkdown(pageNumbers []int, options MarkdownExportOptions, output string) (string, error) {
+	doc := a.activeDocument()
+	if doc == nil {
+		return "", i18n.Errorf("doc.not_loaded")
+	}
+	switch options.Images {
+	case "", MarkdownImagesRelative, MarkdownImagesBase64:
+	default:
+		return "", fmt.Errorf("不支持的图片嵌入方式: %s", options.Images)
+	}
+	if options.PageHeading < 0 || options.PageHeading > 6 {
+		return "", fmt.Errorf("页标题级别超出范围 (0-6): %d", options.PageHeading)
+	}
+	if options.ImageMaxEdge <= 0 {
+		options.ImageMaxEdge = defaultMarkdownImageEdge
+	}
+
+	// 嵌入图片时没有文本的页面也保留图片
+	pageNums, texts, err := a.exportPageTexts(doc, pageNumbers, options.TextType, options.Images != "")
+	if err != nil {
+		return "", err
+	}
+
+	baseName := strings.TrimSuffix(filepath.Base(doc.FilePath), filepath.Ext(doc.FilePath))
+	if output == "" {
+		output, err = runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+			DefaultFilename: baseName + ".md",
+			Filters:         []runtime.FileFilter{{DisplayName: "Markdown", Pattern: "*.md"}},
+			Title:           "导出Markdown",
+		})
+		if err != nil || output == "" {
+			return "", err
+		}
+	}
+
+	// 相对路径的图片保存在Markdown文件旁边的 <文件名>_images 目录
+	imageDirName := strings.TrimSuffix(filepath.Base(output), filepath.Ext(output)) + "_images"
+	if options.Images == MarkdownImagesRelative {
+		if err := os.MkdirAll(filepath.Join(filepath.Dir(output), imageDirName), 0755); err != nil {
+			return "", fmt.Errorf("创建图片目录失败: %w", err)
+		}
+	}
+
+	var builder strings.Builder
+	if options.FrontMatter {
+		builder.WriteString(a.markdownFrontMatter(doc.Title, doc.Author, doc.FilePath, len(pageNums), nil))
+	}
+
+	digits := len(strconv.Itoa(len(doc.Pages)))
+	for i, pageNum := range pageNums {
+		label := i18n.T("export.page", pageNum)
+		if options.PageHeading > 0 {
+			builder.WriteString(strings.Repeat("#", options.PageHeading) + " " + label + "\n\n")
+		}
+		if options.Images != "" {
+			link, err := a.markdownPageImage(doc, pageNum, options, filepath.Dir(output), imageDirName, digits)
+			if err != nil {
+				return "", fmt.Errorf("第%d页: %w", pageNum, err)
+			}
+			builder.WriteString(fmt.Sprintf("![%s](%s)\n\n", label, link))
+		}
+		if text := strings.TrimSpace(shiftMarkdownHeadings(texts[i], options.HeadingOffset)); text != "" {
+			builder.WriteString(text + "\n\n")
+		}
+	}
+
+	if err := os.WriteFile(output, []byte(strings.TrimRight(builder.String(), "\n")+"\n"), 0644); err != nil {
+		return "", fmt.Errorf("保存Markdown文件失败: %w", err)
+	}
+	logger.Infof("已导出Markdown（%d 页）到 %s", len(pageNums), output)
+	return output, nil
+}
+
+// markdownFrontMatter 生成YAML front matter，models 为处理过该文档的模型（来自历史记录）
+func (a *App) markdownFrontMatter(title, author, source string, pages int, tags []string) string {
+	if title == "" {
+		title = strings.TrimSuffix(filepath.Base(source), filepath.Ext(source))
+	}
+
+	var models []string
+	if a.historyManager != nil {
+		records, err := a.historyManager.GetRecordsByDocumentPath(source)
+		if err != nil {
+			logger.Warnf("获取历史记录失败，front matter 不包含模型: %v", err)
+		}
+		seen := map[string]bool{}
+		for _, record := range records {
+			if record.Model != "" && !seen[record.Model] {
+				seen[record.Model] = true
+				models = append(models, strconv.Quote(record.Model))
+			}
+		}
+	}
+
+	var builder strings.Builder
+	builder.WriteString("---\n")
+	builder.WriteString("title: " + strconv.Quote(title) + "\n")
+	if author != "" {
+		builder.WriteString("author: " + strconv.Quote(author) + "\n")
+	}
+	builder.WriteString("source: " + strconv.Quote(source) + "\n")
+	builder.WriteString(fmt.Sprintf("pages: %d\n", pages))
+	builder.WriteString("models: [" + strings.Join(models, ", ") + "]\n")
+	builder.WriteString("date: " + time.Now().Format("2006-01-02") + "\n")
+	if len(tags) > 0 {
+		quoted := make([]string, len(tags))
+		for i, tag := range tags {
+			quoted[i] = strconv.Quote(tag)
+		}
+		builder.WriteString("tags: [" + strings.Join(quoted, ", ") + "]\n")
+	}
+	builder.WriteString("---\n\n")
+	return builder.String()
+}
+
+// exportPageTexts 收集已处理且导出的页面（未排除，设置只导出审核通过的页面时已通过）的导出文本（写入批注、清理页眉页脚、处理脚注并简繁转换）
+// pageNumbers 为空时收集全部页面；keepEmpty 为 true 时保留没有文本的页面
+func (a *App) exportPageTexts(doc *pdf.PDFDocument, pageNumbers []int, textType string, keepEmpty bool) ([]int, []string, error) {
+	if len(pageNumbers) == 0 {
+		for i := range doc.Pages {
+			pageNumbers = append(pageNumbers, i+1)
+		}
+	}
+
+	approvedOnly := a.exportApprovedOnly()
+	var pageNums []int
+	var texts, textTypes []string
+	for _, pageNum := range pageNumbers {
+		if pageNum < 1 || pageNum > len(doc.Pages) {
+			return nil, nil, i18n.Errorf("page.out_of_range_n", pageNum)
+		}
+		page := doc.Pages[pageNum-1]
+		if !page.Processed || !exportsPage(page, approvedOnly) {
+			continue
+		}
+		text := pageText(page, textType)
+		if text == "" && !keepEmpty {
+			continue
+		}
+		pageType := textType
+		if pageType != "ocr" && pageType != "ai" {
+			switch text {
+			case page.OCRText:
+				pageType = "ocr"
+			case page.AIText:
+				pageType = "ai"
+			default:
+				pageType = "original"
+			}
+		}
+		pageNums = append(pageNums, pageNum)
+		texts = append(texts, text)
+		textTypes = append(textTypes, pageType)
+	}
+	if len(pageNums) == 0 {
+		return nil, nil, fmt.Errorf("没有已处理的页面可以导出")
+	}
+	return pageNums, a.resolveFootnotes(a.CleanExportTexts(a.annotateTexts(pageNums, texts, textTypes))), nil
+}
+
+// markdownPageImage 渲染页面图片并返回Markdown中引用的链接：相对路径或 data URI
+func (a *App) markdownPageImage(doc *pdf.PDFDocument, pageNum int, options MarkdownExportOptions, dir, imageDirName string, digits int) (string, error) {
+	imagePath, err := a.pdfProcessor.RenderPageToImage(doc, pageNum)
+	if err != nil {
+		return "", err
+	}
+	data, _, _, err := imageprocessor.EncodePreview(imagePath, options.ImageMaxEdge)
+	if err != nil {
+		return "", err
+	}
+	if options.Images == MarkdownImagesBase64 {
+		return "data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(data), nil
+	}
+
+	name := fmt.Sprintf("page-%0*d.jpg", digits, pageNum)
+	if err := os.WriteFile(filepath.Join(dir, imageDirName, name), data, 0644); err != nil {
+		return "", fmt.Errorf("保存页面图片失败: %w", err)
+	}
+	return url.PathEscape(imageDirName) + "/" + name, nil
+}
+
+// shiftMarkdownHeadings 将正文中的 # 标题下调 offset 级，最深为 6 级，代码块中的内容不变
+func shiftMarkdownHeadings(text string, offset int) string {
+	if offset <= 0 {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	inFence := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || !strings.HasPrefix(line, "#") {
+			continue
+		}
+		level := len(line) - len(strings.TrimLeft(line, "#"))
+		rest := line[level:]
+		if level > 6 || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+			continue
+		}
+		lines[i] = strings.Repeat("#", min(level+offset, 6)) + rest
+	}
+	return strings.Join(lines, "\n")
+}
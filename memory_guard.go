@@ -0,0 +1,152 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	"pdf-ocr-ai/pkg/config"
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/memguard"
+)
+
+// memoryCheckInterval 检查内存占用的间隔
+const memoryCheckInterval = 10 * time.Second
+
+// defaultMemoryPageWindow 未配置时查看页前后各保留文本的页数
+const defaultMemoryPageWindow = 20
+
+// startMemoryGuard 按存储配置启动内存监视
+func (a *App) startMemoryGuard() {
+	a.memoryMonitor = memguard.NewMonitor(memoryCheckInterval, a.relieveMemory)
+	a.applyMemoryConfig(a.configManager.GetConfig().Storage)
+	a.memoryMonitor.Start()
+}
+
+// stopMemoryGuard 停止内存监视
+func (a *App) stopMemoryGuard() {
+	if a.memoryMonitor != nil {
+		a.memoryMonitor.Stop()
+	}
+}
+
+// applyMemoryConfig 应用内存预算配置
+func (a *App) applyMemoryConfig(cfg config.StorageConfig) {
+	if a.memoryMonitor == nil {
+		return
+	}
+
+	var budget int64
+	if cfg.MemoryBudget != "" {
+		size, err := config.ParseByteSize(cfg.MemoryBudget)
+		if err != nil {
+			logger.Warnf("内存预算配置无效，不限制内存: %v", err)
+		}
+		budget = size
+	}
+	a.memoryMonitor.SetBudget(budget)
+}
+
+// relieveMemory 内存接近预算时释放渲染数据，并释放后台文档查看页附近以外的页面文本
+// 当前文档和正在处理的文档不释放；刚进入接近上限的状态时通知前端
+func (a *App) relieveMemory(usage memguard.Usage, first bool) {
+	a.pdfProcessor.ReleaseRenderMemory()
+
+	window := a.configManager.GetConfig().Storage.MemoryPageWindow
+	if window <= 0 {
+		window = defaultMemoryPageWindow
+	}
+
+	a.mu.RLock()
+	var background []*DocumentSession
+	for id, session := range a.sessions {
+		if id != a.activeSessionID {
+			background = append(background, session)
+		}
+	}
+	a.mu.RUnlock()
+
+	released := 0
+	for _, session := range background {
+		released += a.releasePageText(session, window)
+	}
+
+	if released > 0 {
+		logger.Infof("内存接近上限，已释放 %d 页后台文档文本", released)
+	}
+	if first {
+		after := memguard.ReadUsage(usage.BudgetBytes)
+		logger.Warnf("内存占用接近上限: %d MB / %d MB", after.ResidentBytes>>20, after.BudgetBytes>>20)
+		a.emit("memory-warning", map[string]interface{}{
+			"usage":          after,
+			"released_pages": released,
+			"message":        i18n.T("memory.warning", after.ResidentBytes>>20, after.BudgetBytes>>20),
+		})
+	}
+}
+
+// releasePageText 释放会话中查看页前后 window 页以外的页面文本，返回释放的页数
+// 没有缓存（使用临时ID）或正在批量处理的文档不释放
+func (a *App) releasePageText(session *DocumentSession, window int) int {
+	if strings.HasPrefix(session.ID, "temp-") {
+		return 0
+	}
+
+	session.processingMu.Lock()
+	defer session.processingMu.Unlock()
+	if session.processingState != ProcessingStateIdle {
+		return 0
+	}
+
+	viewed := session.viewedPage
+	if viewed < 1 {
+		viewed = 1
+	}
+	released := a.pdfProcessor.ReleasePageText(session.Doc, func(pageNum int) bool {
+		return pageNum >= viewed-window && pageNum <= viewed+window
+	})
+	if released > 0 {
+		session.textReleased = true
+	}
+	return released
+}
+
+// restorePageText 使用会话前从缓存重新加载因内存不足释放的页面文本
+func (a *App) restorePageText(session *DocumentSession) {
+	if session == nil {
+		return
+	}
+
+	session.processingMu.Lock()
+	defer session.processingMu.Unlock()
+	if !session.textReleased {
+		return
+	}
+	if err := a.loadFromCache(session.Doc, session.ID); err != nil {
+		logger.Errorf("重新加载页面文本失败: %v", err)
+		return
+	}
+	session.textReleased = false
+}
+
+// SetViewedPage 记录当前文档正在查看的页面，内存不足时保留该页附近的页面文本
+func (a *App) SetViewedPage(pageNumber int) {
+	a.mu.RLock()
+	session := a.sessions[a.activeSessionID]
+	a.mu.RUnlock()
+	if session == nil {
+		return
+	}
+
+	session.processingMu.Lock()
+	session.viewedPage = pageNumber
+	session.processingMu.Unlock()
+}
+
+// GetMemoryUsage 获取当前内存占用和预算
+func (a *App) GetMemoryUsage() memguard.Usage {
+	if a.memoryMonitor == nil {
+		return memguard.ReadUsage(0)
+	}
+	return a.memoryMonitor.Check()
+}
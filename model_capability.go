@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"pdf-ocr-ai/pkg/config"
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/ocr"
+)
+
+// ProbeModelCapability 检测模型是否支持图片输入（OCR需要），支持时结果保存在配置中
+// force 为 false 时优先返回已保存的结果，model 为空时检测当前OCR模型
+func (a *App) ProbeModelCapability(model string, force bool) (*config.ModelCapability, error) {
+	if a.ocrClient == nil {
+		return nil, i18n.Errorf("ai.not_configured")
+	}
+	if model == "" {
+		model = a.ocrModel(nil)
+	}
+
+	if !force {
+		if capability, ok := a.configManager.GetAIConfig().Capability(model); ok && capability.Vision {
+			return &capability, nil
+		}
+	}
+	return a.probeModel(model)
+}
+
+// probeModel 发送测试请求检测模型能力，只保存支持的结果
+// 不支持的结果不保存：服务临时拒绝或更换模型版本后下次仍会重新检测
+func (a *App) probeModel(model string) (*config.ModelCapability, error) {
+	capability, err := a.ocrClient.ProbeVision(a.ctx, model)
+	if err != nil {
+		return nil, err
+	}
+
+	if capability.Vision {
+		if err := a.configManager.SetModelCapability(model, capability); err != nil {
+			logger.Warnf("保存模型能力检测结果失败: %v", err)
+		}
+		a.ocrClient.UpdateConfig(a.configManager.GetAIConfig())
+	}
+
+	logger.Infof("模型 %s 图片识别能力检测结果: %v %s", model, capability.Vision, capability.Detail)
+	return &capability, nil
+}
+
+// checkOCRModel 开始OCR前确认AI服务支持图片输入
+// 未确认支持的模型在后台检测，不阻塞处理开始；检测结果只用于提示
+func (a *App) checkOCRModel(model string) error {
+	if a.ocrClient == nil {
+		return nil
+	}
+	if !a.ocrClient.Capabilities().Vision {
+		return fmt.Errorf("当前AI服务（%s）不支持图片识别，无法进行OCR", a.ocrClient.ID())
+	}
+	if capability, ok := a.configManager.GetAIConfig().Capability(model); ok && capability.Vision {
+		return nil
+	}
+	if _, running := a.probingModels.LoadOrStore(model, true); !running {
+		go a.probeModelInBackground(model)
+	}
+	return nil
+}
+
+// probeModelInBackground 后台检测模型能力，服务拒绝图片或无法判断时提示用户
+func (a *App) probeModelInBackground(model string) {
+	defer a.probingModels.Delete(model)
+
+	capability, err := a.probeModel(model)
+	var message string
+	switch {
+	case errors.Is(err, ocr.ErrVisionUnknown):
+		logger.Warnf("模型 %s 的图片识别能力未知: %v", model, err)
+		message = fmt.Sprintf("无法确认模型 %s 是否支持图片识别，如OCR结果异常请在设置中选择支持视觉的模型", model)
+	case err != nil:
+		logger.Warnf("无法检测模型 %s 的图片识别能力，继续处理: %v", model, err)
+		return
+	case !capability.Vision:
+		logger.Warnf("模型 %s 拒绝了图片输入: %s", model, capability.Detail)
+		message = fmt.Sprintf("模型 %s 可能不支持图片识别，OCR可能失败，请在设置中选择支持视觉的模型", model)
+	default:
+		return
+	}
+	a.emit("model-capability-warning", map[string]interface{}{
+		"model":   model,
+		"message": message,
+	})
+}
+
+// emitModelUnsupported 发送模型不支持OCR的错误事件
+func (a *App) emitModelUnsupported(ctx context.Context, model string, err error) {
+	event := newEventError(ErrCodeModelNoVision, err.Error())
+	event.Model = model
+	a.emitTask(ctx, "processing-error", event)
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/pdf"
+	"pdf-ocr-ai/pkg/textdiff"
+)
+
+// nativeMismatchThreshold OCR结果与原生文本的相似度低于该值时视为不一致
+const nativeMismatchThreshold = 0.6
+
+// nativeIncompleteRatio 一方的字数不到另一方的该比例时，判断字数少的一方有缺失
+const nativeIncompleteRatio = 0.5
+
+// 不一致时可能有问题的一方
+const (
+	SuspectOCR    = "ocr"    // OCR结果缺失较多，可能识别有误
+	SuspectNative = "native" // 原生文本缺失较多，可能文本层不完整或提取有误
+)
+
+// NativeTextCheck 单页OCR结果与原生文本的比对结果
+type NativeTextCheck struct {
+	Page        int     `json:"page"`
+	Similarity  float64 `json:"similarity"` // 去除空白和标点后的相似度（0-1）
+	OCRChars    int     `json:"ocr_chars"`
+	NativeChars int     `json:"native_chars"`
+	Mismatch    bool    `json:"mismatch"`
+	Suspect     string  `json:"suspect,omitempty"` // 字数明显较少的一方：ocr 或 native
+}
+
+// CheckOCRAgainstNativeText 比对当前文档各页的OCR结果和原生文本，pageNumbers 为空时检查全部页面
+// 只返回既有OCR结果又有原生文本的页面，尚未提取原生文本的页面会先提取
+func (a *App) CheckOCRAgainstNativeText(pageNumbers []int) ([]NativeTextCheck, error) {
+	doc := a.activeDocument()
+	if doc == nil {
+		return nil, i18n.Errorf("doc.not_loaded")
+	}
+	if len(pageNumbers) == 0 {
+		for i := range doc.Pages {
+			pageNumbers = append(pageNumbers, i+1)
+		}
+	}
+
+	checks := []NativeTextCheck{}
+	for _, pageNum := range pageNumbers {
+		if pageNum < 1 || pageNum > len(doc.Pages) {
+			continue
+		}
+		page := doc.Pages[pageNum-1]
+		if strings.TrimSpace(page.OCRText) == "" {
+			continue
+		}
+		native := page.Text
+		if native == "" {
+			text, _, err := a.pdfProcessor.ExtractNativeText(doc.FilePath, pageNum)
+			if err != nil {
+				logger.Warnf("提取第%d页原生文本失败: %v", pageNum, err)
+				continue
+			}
+			native = text
+		}
+		if strings.TrimSpace(native) == "" {
+			continue
+		}
+		checks = append(checks, compareNativeText(pageNum, page.OCRText, native))
+	}
+	return checks, nil
+}
+
+// checkNativeTextAfterOCR 页面已有原生文本时比对新的OCR结果，不一致时通知前端
+func (a *App) checkNativeTextAfterOCR(ctx context.Context, doc *pdf.PDFDocument, pageNum int, ocrText string) {
+	native := doc.Pages[pageNum-1].Text
+	if strings.TrimSpace(native) == "" || strings.TrimSpace(ocrText) == "" {
+		return
+	}
+	check := compareNativeText(pageNum, ocrText, native)
+	if !check.Mismatch {
+		return
+	}
+	logger.Warnf("第%d页OCR结果与原生文本不一致，相似度 %.2f", pageNum, check.Similarity)
+	a.emitTask(ctx, "native-text-mismatch", map[string]interface{}{
+		"file_path": doc.FilePath,
+		"check":     check,
+	})
+}
+
+// compareNativeText 比较OCR结果和原生文本，忽略空白、标点和大小写（OCR结果中的Markdown标记也被忽略）
+func compareNativeText(pageNum int, ocrText, native string) NativeTextCheck {
+	ocrNorm, nativeNorm := normalizeForCompare(ocrText), normalizeForCompare(native)
+	check := NativeTextCheck{
+		Page:        pageNum,
+		Similarity:  textdiff.Similarity(ocrNorm, nativeNorm),
+		OCRChars:    countTextRunes(ocrNorm),
+		NativeChars: countTextRunes(nativeNorm),
+	}
+	check.Mismatch = check.Similarity < nativeMismatchThreshold
+	if check.Mismatch {
+		switch {
+		case float64(check.OCRChars) < float64(check.NativeChars)*nativeIncompleteRatio:
+			check.Suspect = SuspectOCR
+		case float64(check.NativeChars) < float64(check.OCRChars)*nativeIncompleteRatio:
+			check.Suspect = SuspectNative
+		}
+	}
+	return check
+}
+
+// normalizeForCompare 只保留字母、数字和汉字等文字字符，英文转为小写
+// 拉丁字母和数字组成的单词之间保留一个空格，否则整页英文会连成一个词，比对时完全不相同
+func normalizeForCompare(text string) string {
+	var builder strings.Builder
+	var last rune
+	separated := false
+	for _, r := range text {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			separated = true
+			continue
+		}
+		r = unicode.ToLower(r)
+		if separated && isWordRune(last) && isWordRune(r) {
+			builder.WriteByte(' ')
+		}
+		builder.WriteRune(r)
+		last, separated = r, false
+	}
+	return builder.String()
+}
+
+// isWordRune 是否为组成英文单词的字符（与 textdiff 的分词一致）
+func isWordRune(r rune) bool {
+	return r != 0 && r < unicode.MaxLatin1 && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// countTextRunes 统计文字字符数，不计单词间的空格
+func countTextRunes(text string) int {
+	return utf8.RuneCountInString(text) - strings.Count(text, " ")
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"pdf-ocr-ai/pkg/outline"
+)
+
+// headingBreakPattern 标题标签后多余的换行
+var headingBreakPattern = regexp.MustCompile(`(</h[1-6]>)<br>`)
+
+// AnalyzeOutline 检测按页顺序排列的文本中的章节标题，生成章节树和目录，用于导出
+// format 为 markdown 或 html，决定标题行的替换形式；一级章节使用二级标题（一级标题留给文档标题）
+func (a *App) AnalyzeOutline(texts []string, format string) outline.Result {
+	return outline.Analyze(texts, format, 2)
+}
+
+// htmlPageContent 将页面文本中的换行转换为 <br>，标题标签后不再额外换行
+func htmlPageContent(text string) string {
+	return headingBreakPattern.ReplaceAllString(strings.ReplaceAll(text, "\n", "<br>\n"), "$1")
+}
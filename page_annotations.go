@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"pdf-ocr-ai/pkg/annotations"
+	"pdf-ocr-ai/pkg/config"
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// AddAnnotation 为当前文档页面文本的指定位置添加批注
+// start、end 为按字符计的位置（从0开始，end 不含），相同时为插入点
+func (a *App) AddAnnotation(pageNumber int, textType string, start, end int, comment string) (*annotations.Annotation, error) {
+	session := a.activeSession()
+	if session == nil {
+		return nil, i18n.Errorf("doc.not_loaded")
+	}
+	if pageNumber < 1 || pageNumber > len(session.Doc.Pages) {
+		return nil, i18n.Errorf("page.out_of_range")
+	}
+	if textType != "ocr" && textType != "ai" {
+		return nil, i18n.Errorf("text.unsupported_type", textType)
+	}
+
+	page := session.Doc.Pages[pageNumber-1]
+	runes := []rune(pageTextByType(page.OCRText, page.AIText, textType))
+	if start < 0 || end < start || end > len(runes) {
+		return nil, fmt.Errorf("批注位置超出文本范围")
+	}
+
+	return a.annotationManager.Add(annotations.Annotation{
+		DocumentID: session.ID,
+		PageNumber: pageNumber,
+		TextType:   textType,
+		Start:      start,
+		End:        end,
+		Quote:      string(runes[start:end]),
+		Comment:    strings.TrimSpace(comment),
+	})
+}
+
+// UpdateAnnotation 修改批注内容
+func (a *App) UpdateAnnotation(id int64, comment string) error {
+	return a.annotationManager.UpdateComment(id, strings.TrimSpace(comment))
+}
+
+// DeleteAnnotation 删除批注
+func (a *App) DeleteAnnotation(id int64) error {
+	return a.annotationManager.Delete(id)
+}
+
+// GetAnnotations 获取当前文档的批注，pageNumber 为0时返回所有页面
+func (a *App) GetAnnotations(pageNumber int) ([]annotations.Annotation, error) {
+	session := a.activeSession()
+	if session == nil {
+		return []annotations.Annotation{}, nil
+	}
+	return a.annotationManager.List(session.ID, pageNumber)
+}
+
+// AnnotateExportTexts 按批注配置把批注写入导出文本，pageNumbers 与 texts 一一对应，textType 为导出文本的类型
+// 需要在页眉页脚清理和脚注处理之前调用，批注位置以保存的页面文本为准
+func (a *App) AnnotateExportTexts(pageNumbers []int, texts []string, textType string) []string {
+	textTypes := make([]string, len(texts))
+	for i := range textTypes {
+		textTypes[i] = textType
+	}
+	return a.annotateTexts(pageNumbers, texts, textTypes)
+}
+
+// annotateTexts 按配置写入批注，textTypes 为每页文本的类型（ocr、ai，其他类型的文本没有批注）
+func (a *App) annotateTexts(pageNumbers []int, texts []string, textTypes []string) []string {
+	mode := a.configManager.GetConfig().Annotations.ExportMode
+	session := a.activeSession()
+	if session == nil || (mode != config.AnnotationExportFootnote && mode != config.AnnotationExportMargin) {
+		return texts
+	}
+
+	list, err := a.annotationManager.List(session.ID, 0)
+	if err != nil {
+		logger.Warnf("读取批注失败，导出时不包含批注: %v", err)
+		return texts
+	}
+	byPage := make(map[string][]annotations.Annotation)
+	for _, annotation := range list {
+		key := fmt.Sprintf("%d-%s", annotation.PageNumber, annotation.TextType)
+		byPage[key] = append(byPage[key], annotation)
+	}
+
+	result := make([]string, len(texts))
+	for i, text := range texts {
+		result[i] = text
+		if i >= len(pageNumbers) || i >= len(textTypes) {
+			continue
+		}
+		if notes := byPage[fmt.Sprintf("%d-%s", pageNumbers[i], textTypes[i])]; len(notes) > 0 {
+			result[i] = annotations.Apply(text, notes, mode)
+		}
+	}
+	return result
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/pdf"
+)
+
+// SetPagesExcluded 排除或恢复当前文档的页面，排除的页面不参与批量处理、导出和统计（随文档保存）
+func (a *App) SetPagesExcluded(pageNumbers []int, excluded bool) error {
+	session := a.activeSession()
+	if session == nil {
+		return i18n.Errorf("doc.not_loaded")
+	}
+	doc := session.Doc
+	for _, pageNum := range pageNumbers {
+		if pageNum < 1 || pageNum > len(doc.Pages) {
+			return i18n.Errorf("page.out_of_range_n", pageNum)
+		}
+	}
+
+	if err := a.exclusionManager.Set(session.ID, pageNumbers, excluded); err != nil {
+		return err
+	}
+	a.mu.Lock()
+	for _, pageNum := range pageNumbers {
+		doc.Pages[pageNum-1].Excluded = excluded
+	}
+	a.mu.Unlock()
+
+	a.emit("pages-excluded", map[string]interface{}{
+		"document_id": session.ID,
+		"pages":       pageNumbers,
+		"excluded":    excluded,
+	})
+	return nil
+}
+
+// GetExcludedPages 获取当前文档排除的页码
+func (a *App) GetExcludedPages() []int {
+	doc := a.activeDocument()
+	if doc == nil {
+		return []int{}
+	}
+	return excludedPageNumbers(doc)
+}
+
+// loadExcludedPages 加载文档排除的页面
+func (a *App) loadExcludedPages(doc *pdf.PDFDocument, documentID string) {
+	pages, err := a.exclusionManager.Pages(documentID)
+	if err != nil {
+		logger.Warnf("加载排除页面失败: %v", err)
+		return
+	}
+	for _, pageNum := range pages {
+		if pageNum >= 1 && pageNum <= len(doc.Pages) {
+			doc.Pages[pageNum-1].Excluded = true
+		}
+	}
+}
+
+// includedPages 去掉排除的页面
+func includedPages(doc *pdf.PDFDocument, pageNumbers []int) []int {
+	pages := make([]int, 0, len(pageNumbers))
+	for _, pageNum := range pageNumbers {
+		if pageNum >= 1 && pageNum <= len(doc.Pages) && doc.Pages[pageNum-1].Excluded {
+			continue
+		}
+		pages = append(pages, pageNum)
+	}
+	return pages
+}
+
+// excludedPageNumbers 获取排除的页码
+func excludedPageNumbers(doc *pdf.PDFDocument) []int {
+	pages := []int{}
+	for i, page := range doc.Pages {
+		if page.Excluded {
+			pages = append(pages, i+1)
+		}
+	}
+	return pages
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"pdf-ocr-ai/pkg/i18n"
+	imageprocessor "pdf-ocr-ai/pkg/image"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/pdf"
+)
+
+// PageImageExportResult 页面图片导出结果
+type PageImageExportResult struct {
+	Path   string `json:"path"`   // 导出目录或ZIP文件
+	Files  int    `json:"files"`  // 成功导出的图片数
+	Failed []int  `json:"failed"` // 渲染或编码失败的页码
+}
+
+// ExportPageImages 将指定页面的渲染图片导出到目录或ZIP文件，pageNumbers 为空时导出全部页面
+// format 为 jpg 或 png；dpi 为0时使用默认分辨率；preprocess 为 true 时按文档的处理配置预处理
+// output 以 .zip 结尾时打包为ZIP，否则作为目录；为空时弹出目录选择对话框
+func (a *App) ExportPageImages(pageNumbers []int, format string, dpi int, preprocess bool, output string) (*PageImageExportResult, error) {
+	doc := a.activeDocument()
+	if doc == nil {
+		return nil, i18n.Errorf("doc.not_loaded")
+	}
+	format = strings.ToLower(format)
+	if format == "jpeg" {
+		format = "jpg"
+	}
+	if format != "jpg" && format != "png" {
+		return nil, fmt.Errorf("不支持的图片格式: %s", format)
+	}
+
+	pages := pageNumbers
+	if len(pages) == 0 {
+		pages = make([]int, len(doc.Pages))
+		for i := range pages {
+			pages[i] = i + 1
+		}
+	}
+
+	var err error
+	if output == "" {
+		output, err = runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{Title: "选择图片保存目录"})
+		if err != nil || output == "" {
+			return nil, err
+		}
+	}
+
+	var steps []string
+	if profile := a.resolveProfile(doc.FilePath); preprocess && profile != nil {
+		steps = profile.Preprocessing
+	}
+
+	open, finish, err := openImageOutput(output)
+	if err != nil {
+		return nil, err
+	}
+
+	baseName := strings.TrimSuffix(filepath.Base(doc.FilePath), filepath.Ext(doc.FilePath))
+	digits := len(fmt.Sprint(len(doc.Pages)))
+	result := &PageImageExportResult{Path: output, Failed: []int{}}
+	for i, pageNum := range pages {
+		a.emit("page-images-progress", map[string]interface{}{
+			"current": i + 1,
+			"total":   len(pages),
+			"page":    pageNum,
+		})
+		name := fmt.Sprintf("%s_p%0*d.%s", baseName, digits, pageNum, format)
+		if err := a.exportPageImage(doc, pageNum, dpi, steps, format, name, open); err != nil {
+			logger.Warnf("导出第%d页图片失败: %v", pageNum, err)
+			result.Failed = append(result.Failed, pageNum)
+			continue
+		}
+		result.Files++
+	}
+
+	if err := finish(); err != nil {
+		return nil, err
+	}
+	logger.Infof("已导出 %d 页图片到 %s", result.Files, output)
+	return result, nil
+}
+
+// openImageOutput 按输出方式打开每页图片的写入目标：output 以 .zip 结尾时写入ZIP，否则写入目录
+// finish 在全部图片写入后调用，关闭ZIP文件
+func openImageOutput(output string) (open func(name string) (io.Writer, func() error, error), finish func() error, err error) {
+	if !strings.EqualFold(filepath.Ext(output), ".zip") {
+		if err := os.MkdirAll(output, 0755); err != nil {
+			return nil, nil, i18n.Errorf("file.mkdir_failed", err)
+		}
+		open = func(name string) (io.Writer, func() error, error) {
+			file, err := os.Create(filepath.Join(output, name))
+			if err != nil {
+				return nil, nil, err
+			}
+			return file, file.Close, nil
+		}
+		return open, func() error { return nil }, nil
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		return nil, nil, fmt.Errorf("创建ZIP文件失败: %w", err)
+	}
+	zw := zip.NewWriter(file)
+	open = func(name string) (io.Writer, func() error, error) {
+		w, err := zw.Create(name)
+		return w, func() error { return nil }, err
+	}
+	finish = func() error {
+		err := zw.Close()
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("写入ZIP文件失败: %w", err)
+		}
+		return nil
+	}
+	return open, finish, nil
+}
+
+// exportPageImage 渲染（并按需预处理）单页图片，以指定格式写入 open 返回的目标
+func (a *App) exportPageImage(doc *pdf.PDFDocument, pageNum, dpi int, steps []string, format, name string, open func(name string) (io.Writer, func() error, error)) error {
+	if pageNum < 1 || pageNum > len(doc.Pages) {
+		return i18n.Errorf("page.out_of_range")
+	}
+	imagePath, err := a.pdfProcessor.RenderPageAtDPI(doc, pageNum, dpi)
+	if err != nil {
+		return err
+	}
+	if len(steps) > 0 {
+		tmp, err := os.CreateTemp("", "pdfseer-export-*.jpg")
+		if err != nil {
+			return i18n.Errorf("file.temp_failed", err)
+		}
+		tmp.Close()
+		defer os.Remove(tmp.Name())
+		if err := imageprocessor.Preprocess(imagePath, tmp.Name(), steps); err != nil {
+			return err
+		}
+		imagePath = tmp.Name()
+	}
+
+	w, closeFn, err := open(name)
+	if err != nil {
+		return fmt.Errorf("创建 %s 失败: %w", name, err)
+	}
+	err = imageprocessor.EncodeFile(imagePath, w, format)
+	if closeErr := closeFn(); err == nil {
+		err = closeErr
+	}
+	return err
+}
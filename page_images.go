@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/base64"
+
+	"pdf-ocr-ai/pkg/i18n"
+	imageprocessor "pdf-ocr-ai/pkg/image"
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// maxPageImagesPerCall 单次批量获取的最大页数，避免一次返回的数据过大
+const maxPageImagesPerCall = 20
+
+// PageImage 页面渲染图片，DataURI 为 JPEG 的 data URI
+type PageImage struct {
+	Page    int    `json:"page"`
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
+	DataURI string `json:"data_uri,omitempty"`
+	Error   string `json:"error,omitempty"` // 该页渲染失败的原因
+}
+
+// GetPageImagesRange 批量获取 start 到 end 页（含）的渲染图片，用于连续滚动浏览
+// maxEdge 大于0时将图片缩小到最长边不超过该像素数；单页渲染失败时记录在该页的 Error 中，不影响其他页
+func (a *App) GetPageImagesRange(start, end, maxEdge int) ([]PageImage, error) {
+	doc := a.activeDocument()
+	if doc == nil {
+		return nil, i18n.Errorf("doc.not_loaded")
+	}
+
+	start = max(start, 1)
+	end = min(end, len(doc.Pages))
+	if start > end {
+		return nil, i18n.Errorf("page.out_of_range")
+	}
+	if end-start+1 > maxPageImagesPerCall {
+		end = start + maxPageImagesPerCall - 1
+	}
+
+	images := make([]PageImage, 0, end-start+1)
+	for pageNum := start; pageNum <= end; pageNum++ {
+		image := PageImage{Page: pageNum}
+		imagePath, err := a.pdfProcessor.RenderPageToImage(doc, pageNum)
+		if err == nil {
+			var data []byte
+			data, image.Width, image.Height, err = imageprocessor.EncodePreview(imagePath, maxEdge)
+			if err == nil {
+				image.DataURI = "data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(data)
+			}
+		}
+		if err != nil {
+			logger.Warnf("获取第%d页图片失败: %v", pageNum, err)
+			image.Error = err.Error()
+		}
+		images = append(images, image)
+	}
+	return images, nil
+}
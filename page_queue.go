@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// pageQueue 批量处理的待处理页面队列
+// 工作协程按顺序取页，指定优先的页面移到队首，由下一个空闲的工作协程处理
+type pageQueue struct {
+	mu    sync.Mutex
+	pages []int
+}
+
+// newPageQueue 创建页面队列
+func newPageQueue(pages []int) *pageQueue {
+	return &pageQueue{pages: append([]int(nil), pages...)}
+}
+
+// next 取出下一个页面，队列为空时返回false
+func (q *pageQueue) next() (int, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.pages) == 0 {
+		return 0, false
+	}
+	page := q.pages[0]
+	q.pages = q.pages[1:]
+	return page, true
+}
+
+// prioritize 把尚未开始处理的页面移到队首，页面不在队列中时返回false
+func (q *pageQueue) prioritize(page int) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i, p := range q.pages {
+		if p != page {
+			continue
+		}
+		copy(q.pages[1:i+1], q.pages[:i])
+		q.pages[0] = page
+		return true
+	}
+	return false
+}
+
+// startPageQueue 为当前批次创建页面队列，批次结束时清除
+func (s *DocumentSession) startPageQueue(pages []int) *pageQueue {
+	queue := newPageQueue(pages)
+	s.processingMu.Lock()
+	s.pageQueue = queue
+	s.processingMu.Unlock()
+	return queue
+}
+
+// PrioritizePage 让当前文档批量处理中尚未开始的页面下一个处理，之后按原顺序继续
+// 用于在批量处理时先识别正在查看的页面
+func (a *App) PrioritizePage(pageNumber int) error {
+	session := a.activeSession()
+	if session == nil {
+		return i18n.Errorf("doc.not_loaded")
+	}
+
+	session.processingMu.Lock()
+	queue := session.pageQueue
+	session.processingMu.Unlock()
+
+	if queue == nil {
+		return fmt.Errorf("当前没有正在进行的批量处理")
+	}
+	if !queue.prioritize(pageNumber) {
+		return i18n.Errorf("queue.not_pending", pageNumber)
+	}
+
+	logger.Infof("优先处理第%d页: %s", pageNumber, session.ID)
+	a.emit("page-prioritized", map[string]interface{}{
+		"document_id": session.ID,
+		"pageNumber":  pageNumber,
+	})
+	return nil
+}
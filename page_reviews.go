@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/pdf"
+	"pdf-ocr-ai/pkg/reviews"
+)
+
+// reviewUnapproved 列出页面时表示所有未通过审核的页面（未审核和需要修改）
+const reviewUnapproved = "unapproved"
+
+// SetPageReviewStatus 设置当前文档页面的审核状态：unreviewed、approved 或 needs_fix（随文档保存）
+func (a *App) SetPageReviewStatus(pageNumbers []int, status string) error {
+	session := a.activeSession()
+	if session == nil {
+		return i18n.Errorf("doc.not_loaded")
+	}
+	if !reviews.ValidStatus(status) {
+		return i18n.Errorf("review.invalid_status", status)
+	}
+	doc := session.Doc
+	for _, pageNum := range pageNumbers {
+		if pageNum < 1 || pageNum > len(doc.Pages) {
+			return i18n.Errorf("page.out_of_range_n", pageNum)
+		}
+	}
+
+	if err := a.reviewManager.Set(session.ID, pageNumbers, status); err != nil {
+		return err
+	}
+	a.mu.Lock()
+	for _, pageNum := range pageNumbers {
+		doc.Pages[pageNum-1].ReviewStatus = status
+	}
+	a.mu.Unlock()
+
+	a.emit("page-review-updated", map[string]interface{}{
+		"document_id": session.ID,
+		"pages":       pageNumbers,
+		"status":      status,
+	})
+	return nil
+}
+
+// GetPagesByReviewStatus 获取当前文档指定审核状态的页码，status 为 unapproved 时返回所有未通过审核的页面
+// 排除的页面不包含在内
+func (a *App) GetPagesByReviewStatus(status string) ([]int, error) {
+	if status != reviewUnapproved && !reviews.ValidStatus(status) {
+		return nil, i18n.Errorf("review.invalid_status", status)
+	}
+	doc := a.activeDocument()
+	if doc == nil {
+		return []int{}, nil
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	pages := []int{}
+	for i, page := range doc.Pages {
+		if page.Excluded {
+			continue
+		}
+		current := pageReviewStatus(page)
+		if current == status || status == reviewUnapproved && current != reviews.StatusApproved {
+			pages = append(pages, i+1)
+		}
+	}
+	return pages, nil
+}
+
+// SetExportApprovedOnly 设置导出时是否只包含审核通过的页面并保存到配置
+// 对所有导出生效：文本和Markdown导出、Obsidian、Notion、Paperless-ngx、重排版PDF、定时导出和远程上传
+func (a *App) SetExportApprovedOnly(enabled bool) error {
+	cfg := a.configManager.GetConfig()
+	cfg.UI.ExportApprovedOnly = enabled
+	if err := a.configManager.UpdateConfig(cfg); err != nil {
+		return fmt.Errorf("保存导出设置失败: %w", err)
+	}
+	return nil
+}
+
+// exportApprovedOnly 导出时是否只包含审核通过的页面
+func (a *App) exportApprovedOnly() bool {
+	return a.configManager.GetConfig().UI.ExportApprovedOnly
+}
+
+// exportsPage 页面是否导出：排除的页面不导出，approvedOnly 为 true 时只导出审核通过的页面
+func exportsPage(page *pdf.PDFPage, approvedOnly bool) bool {
+	return !page.Excluded && (!approvedOnly || pageReviewStatus(page) == reviews.StatusApproved)
+}
+
+// loadPageReviews 加载文档页面的审核状态
+func (a *App) loadPageReviews(doc *pdf.PDFDocument, documentID string) {
+	statuses, err := a.reviewManager.Statuses(documentID)
+	if err != nil {
+		logger.Warnf("加载审核状态失败: %v", err)
+		return
+	}
+	for pageNum, status := range statuses {
+		if pageNum >= 1 && pageNum <= len(doc.Pages) {
+			doc.Pages[pageNum-1].ReviewStatus = status
+		}
+	}
+}
+
+// pageReviewStatus 页面的审核状态，没有记录时为未审核
+func pageReviewStatus(page *pdf.PDFPage) string {
+	if page.ReviewStatus == "" {
+		return reviews.StatusUnreviewed
+	}
+	return page.ReviewStatus
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+
+	"pdf-ocr-ai/pkg/cache"
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/revisions"
+)
+
+// recordPageRevisions 为内容有变化的OCR/AI文本记录历史版本，previous 为保存前的缓存（可为nil）
+func (a *App) recordPageRevisions(documentID string, pageNumber int, previous *cache.CacheEntry, ocrText, aiText, source string) {
+	if a.revisionManager == nil {
+		return
+	}
+
+	var previousOCR, previousAI string
+	if previous != nil {
+		previousOCR, previousAI = previous.OCRText, previous.AIText
+	}
+	if ocrText != previousOCR {
+		if err := a.revisionManager.Record(documentID, pageNumber, "ocr", previousOCR, ocrText, source); err != nil {
+			logger.Warnf("记录第%d页OCR文本历史版本失败: %v", pageNumber, err)
+		}
+	}
+	if aiText != previousAI {
+		if err := a.revisionManager.Record(documentID, pageNumber, "ai", previousAI, aiText, source); err != nil {
+			logger.Warnf("记录第%d页AI文本历史版本失败: %v", pageNumber, err)
+		}
+	}
+}
+
+// ListPageRevisions 获取当前文档页面的文本历史版本（从新到旧）
+func (a *App) ListPageRevisions(pageNumber int) ([]revisions.Revision, error) {
+	session := a.activeSession()
+	if session == nil {
+		return nil, i18n.Errorf("doc.not_loaded")
+	}
+	return a.revisionManager.List(session.ID, pageNumber)
+}
+
+// RestorePageRevision 将页面文本恢复为指定的历史版本，恢复本身也会记录为新版本，可以再次撤销
+func (a *App) RestorePageRevision(id int64) (*revisions.Revision, error) {
+	session := a.activeSession()
+	if session == nil {
+		return nil, i18n.Errorf("doc.not_loaded")
+	}
+
+	revision, err := a.revisionManager.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if revision.DocumentID != session.ID {
+		return nil, fmt.Errorf("历史版本不属于当前文档")
+	}
+	if err := a.updatePageText(revision.PageNumber, revision.TextType, revision.Content, revisions.SourceRestore); err != nil {
+		return nil, err
+	}
+
+	logger.Infof("第%d页%s文本已恢复到 %s 的版本", revision.PageNumber, revision.TextType, revision.CreatedAt.Format("2006-01-02 15:04:05"))
+	return revision, nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"pdf-ocr-ai/pkg/history"
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// lowConfidenceMinRunes 识别结果少于该字数时视为低置信度
+const lowConfidenceMinRunes = 20
+
+// lowConfidenceNoiseRatio 识别结果中乱码字符占比超过该值时视为低置信度
+const lowConfidenceNoiseRatio = 0.1
+
+// PageStatusFlags 页面处理状态标记，供页面网格按状态着色
+type PageStatusFlags struct {
+	Page          int  `json:"page"`
+	HasNativeText bool `json:"has_native_text"` // 页面自带文本层
+	OCRDone       bool `json:"ocr_done"`
+	AIDone        bool `json:"ai_done"`
+	Edited        bool `json:"edited"`         // 最新文本来自手动修改
+	LowConfidence bool `json:"low_confidence"` // OCR结果过短或乱码较多
+	Failed        bool `json:"failed"`         // 最近一次处理该页时失败
+}
+
+// GetPageStatusMap 一次获取当前文档所有页面的状态标记
+func (a *App) GetPageStatusMap() ([]PageStatusFlags, error) {
+	session := a.activeSession()
+	if session == nil {
+		return nil, i18n.Errorf("doc.not_loaded")
+	}
+
+	edited := map[int]bool{}
+	if a.revisionManager != nil {
+		var err error
+		if edited, err = a.revisionManager.EditedPages(session.ID); err != nil {
+			logger.Warnf("获取手动修改的页面失败: %v", err)
+		}
+	}
+	failed := a.failedPageSet(session.Doc.FilePath)
+
+	pages := session.Doc.SnapshotPages()
+	flags := make([]PageStatusFlags, 0, len(pages))
+	for _, page := range pages {
+		ocrText := strings.TrimSpace(page.OCRText)
+		flags = append(flags, PageStatusFlags{
+			Page:          page.Number,
+			HasNativeText: page.HasText,
+			OCRDone:       ocrText != "",
+			AIDone:        strings.TrimSpace(page.AIText) != "",
+			Edited:        edited[page.Number],
+			LowConfidence: ocrText != "" && lowConfidenceText(ocrText),
+			Failed:        failed[page.Number] && ocrText == "" && strings.TrimSpace(page.AIText) == "",
+		})
+	}
+	return flags, nil
+}
+
+// failedPageSet 获取文档最近一次处理时失败的页码，较新的记录优先
+func (a *App) failedPageSet(documentPath string) map[int]bool {
+	failed := map[int]bool{}
+	if a.historyManager == nil {
+		return failed
+	}
+
+	statuses, err := a.historyManager.LatestPageStatuses(documentPath)
+	if err != nil {
+		logger.Warnf("获取页面处理状态失败: %v", err)
+		return failed
+	}
+	for pageNum, status := range statuses {
+		failed[pageNum] = status == history.PageFailed
+	}
+	return failed
+}
+
+// lowConfidenceText 根据字数和乱码字符占比粗略判断OCR结果是否可信
+func lowConfidenceText(text string) bool {
+	if utf8.RuneCountInString(text) < lowConfidenceMinRunes {
+		return true
+	}
+	return noiseRatio(text) > lowConfidenceNoiseRatio
+}
+
+// noiseRatio 无效字符和不可打印字符的占比
+func noiseRatio(text string) float64 {
+	total, noise := 0, 0
+	for _, r := range text {
+		total++
+		if r == utf8.RuneError || (!unicode.IsPrint(r) && !unicode.IsSpace(r)) {
+			noise++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(noise) / float64(total)
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"pdf-ocr-ai/pkg/config"
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/paperless"
+	"pdf-ocr-ai/pkg/reflow"
+)
+
+// ExportToPaperless 为当前文档的原页面叠加识别出的文本（可搜索PDF），连同标题、标签、联系人和文档日期上传到Paperless-ngx
+// 标签为配置的标签加上页面的主题标签；文档日期和联系人取自配置的提取模板的结果，没有时使用配置的联系人
+// 排除的页面（设置只导出审核通过的页面时还有未通过的页面）保留但不加文字；返回Paperless-ngx的处理任务ID；textType: ocr、ai，其他值优先OCR文本
+func (a *App) ExportToPaperless(textType string) (string, error) {
+	session := a.activeSession()
+	if session == nil {
+		return "", i18n.Errorf("doc.not_loaded")
+	}
+	doc := session.Doc
+	appConfig := a.configManager.GetConfig()
+	cfg := appConfig.Integrations.Paperless
+	client, err := paperless.NewClient(cfg.URL, cfg.Token)
+	if err != nil {
+		return "", err
+	}
+
+	texts := documentPageTexts(doc, textType, appConfig.UI.ExportApprovedOnly)
+	for i, text := range texts {
+		texts[i] = convertChinese(text, appConfig.ChineseConversion.Export)
+	}
+	if strings.TrimSpace(strings.Join(texts, "")) == "" {
+		return "", fmt.Errorf("文档没有可添加的文本，请先处理页面")
+	}
+
+	source, err := documentPDF(doc.FilePath)
+	if err != nil {
+		return "", err
+	}
+	var searchable bytes.Buffer
+	if err := reflow.TextLayer(bytes.NewReader(source), &searchable, texts, reflow.Options{}); err != nil {
+		return "", err
+	}
+
+	meta := paperless.Metadata{
+		Title:         documentTitle(doc),
+		Tags:          append([]string{}, cfg.Tags...),
+		Correspondent: cfg.Correspondent,
+		DocumentType:  cfg.DocumentType,
+	}
+	if cfg.TopicTags {
+		for _, entry := range a.topicIndex(doc) {
+			meta.Tags = append(meta.Tags, entry.Tag)
+		}
+	}
+	a.applyPaperlessExtraction(session.ID, appConfig, &meta)
+
+	baseName := strings.TrimSuffix(filepath.Base(doc.FilePath), filepath.Ext(doc.FilePath))
+	taskID, err := client.Upload(a.ctx, baseName+".pdf", searchable.Bytes(), meta)
+	if err != nil {
+		return "", err
+	}
+	logger.Infof("已上传到Paperless-ngx: %s（%d 个标签，任务 %s）", doc.FilePath, len(meta.Tags), taskID)
+	return taskID, nil
+}
+
+// TestPaperless 验证Paperless-ngx地址和API令牌
+func (a *App) TestPaperless() error {
+	cfg := a.configManager.GetConfig().Integrations.Paperless
+	client, err := paperless.NewClient(cfg.URL, cfg.Token)
+	if err != nil {
+		return err
+	}
+	return client.Check(a.ctx)
+}
+
+// applyPaperlessExtraction 从配置的提取模板的结果中读取文档日期（第一个日期字段的第一个值）和联系人
+func (a *App) applyPaperlessExtraction(documentID string, appConfig config.AppConfig, meta *paperless.Metadata) {
+	cfg := appConfig.Integrations.Paperless
+	if cfg.ExtractionSchema == "" {
+		return
+	}
+	schema := appConfig.FindExtractionSchema(cfg.ExtractionSchema)
+	if schema == nil {
+		logger.Warnf("Paperless-ngx使用的提取模板不存在: %s", cfg.ExtractionSchema)
+		return
+	}
+	results, err := a.extractionManager.List(documentID, schema.ID)
+	if err != nil {
+		logger.Warnf("读取提取结果失败: %v", err)
+		return
+	}
+
+	dateField := ""
+	for _, field := range schema.Fields {
+		if field.Type == config.FieldTypeDate {
+			dateField = field.Name
+			break
+		}
+	}
+	correspondent := ""
+	for _, result := range results {
+		if value, ok := result.Fields[dateField].(string); ok && meta.Created == "" {
+			meta.Created = value
+		}
+		if value, ok := result.Fields[cfg.CorrespondentField].(string); ok && correspondent == "" {
+			correspondent = strings.TrimSpace(value)
+		}
+	}
+	if correspondent != "" {
+		meta.Correspondent = correspondent
+	}
+}
+
+// documentPDF 读取文档的PDF内容，图片文档先转换为PDF
+func documentPDF(path string) ([]byte, error) {
+	if strings.EqualFold(filepath.Ext(path), ".pdf") {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("读取文档失败: %w", err)
+		}
+		return data, nil
+	}
+
+	tempDir, err := os.MkdirTemp("", "pdfseer-paperless-*")
+	if err != nil {
+		return nil, i18n.Errorf("file.temp_failed", err)
+	}
+	defer os.RemoveAll(tempDir)
+	target := filepath.Join(tempDir, "document.pdf")
+	if err := mergeIntoPDF([]string{path}, target); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(target)
+}
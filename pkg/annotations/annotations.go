@@ -0,0 +1,180 @@
+package annotations
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"pdf-ocr-ai/pkg/migrate"
+	"pdf-ocr-ai/pkg/storage"
+)
+
+// migrationComponent 表结构迁移使用的组件名
+const migrationComponent = "annotations"
+
+// Annotation 附加在页面文本某一位置的批注
+type Annotation struct {
+	ID         int64     `json:"id" db:"id"`
+	DocumentID string    `json:"document_id" db:"document_id"`
+	PageNumber int       `json:"page_number" db:"page_number"`
+	TextType   string    `json:"text_type" db:"text_type"` // ocr 或 ai
+	Start      int       `json:"start" db:"start_offset"`  // 批注位置在页面文本中的起始位置（按字符计，从0开始）
+	End        int       `json:"end" db:"end_offset"`      // 结束位置（不含），与 Start 相同时为插入点
+	Quote      string    `json:"quote" db:"quote"`         // 批注时选中的文本，文本被修改后用于重新定位
+	Comment    string    `json:"comment" db:"comment"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Manager 页面批注存储
+type Manager struct {
+	db    *sqlx.DB
+	store *storage.Store // 批注内容加解密
+}
+
+// NewManager 创建批注管理器，使用统一数据库中的 page_annotations 表
+func NewManager(store *storage.Store) (*Manager, error) {
+	m := &Manager{db: store.DB(), store: store}
+	if err := store.Migrate(migrationComponent, migrations()); err != nil {
+		return nil, err
+	}
+	store.RegisterEncryptedColumns("page_annotations", "quote", "comment")
+	return m, nil
+}
+
+// migrations 表结构迁移
+func migrations() []migrate.Migration {
+	return []migrate.Migration{
+		{
+			Version:     1,
+			Description: "创建页面批注表",
+			Up: migrate.SQL(`
+			CREATE TABLE IF NOT EXISTS page_annotations (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				document_id TEXT NOT NULL,
+				page_number INTEGER NOT NULL,
+				text_type TEXT NOT NULL,
+				start_offset INTEGER NOT NULL,
+				end_offset INTEGER NOT NULL,
+				quote TEXT NOT NULL DEFAULT '',
+				comment TEXT NOT NULL DEFAULT '',
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			)`,
+				`CREATE INDEX IF NOT EXISTS idx_page_annotations_page ON page_annotations(document_id, page_number)`),
+		},
+	}
+}
+
+// Add 添加批注，返回带ID的批注
+func (m *Manager) Add(annotation Annotation) (*Annotation, error) {
+	if annotation.Comment == "" {
+		return nil, fmt.Errorf("批注内容不能为空")
+	}
+	now := time.Now()
+	annotation.CreatedAt, annotation.UpdatedAt = now, now
+
+	quote, comment := annotation.Quote, annotation.Comment
+	if err := m.store.EncryptFields(&quote, &comment); err != nil {
+		return nil, err
+	}
+	result, err := m.db.Exec(`
+	INSERT INTO page_annotations (document_id, page_number, text_type, start_offset, end_offset, quote, comment, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, annotation.DocumentID, annotation.PageNumber, annotation.TextType, annotation.Start, annotation.End, quote, comment, now, now)
+	if err != nil {
+		return nil, fmt.Errorf("保存批注失败: %w", err)
+	}
+	if annotation.ID, err = result.LastInsertId(); err != nil {
+		return nil, fmt.Errorf("保存批注失败: %w", err)
+	}
+	return &annotation, nil
+}
+
+// Get 获取批注
+func (m *Manager) Get(id int64) (*Annotation, error) {
+	var annotation Annotation
+	err := m.db.Get(&annotation, "SELECT * FROM page_annotations WHERE id = ?", id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("批注不存在: %d", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询批注失败: %w", err)
+	}
+	if err := m.store.DecryptFields(&annotation.Quote, &annotation.Comment); err != nil {
+		return nil, err
+	}
+	return &annotation, nil
+}
+
+// UpdateComment 修改批注内容
+func (m *Manager) UpdateComment(id int64, comment string) error {
+	if comment == "" {
+		return fmt.Errorf("批注内容不能为空")
+	}
+	if err := m.store.EncryptFields(&comment); err != nil {
+		return err
+	}
+	result, err := m.db.Exec("UPDATE page_annotations SET comment = ?, updated_at = ? WHERE id = ?", comment, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("更新批注失败: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("批注不存在: %d", id)
+	}
+	return nil
+}
+
+// Delete 删除批注
+func (m *Manager) Delete(id int64) error {
+	if _, err := m.db.Exec("DELETE FROM page_annotations WHERE id = ?", id); err != nil {
+		return fmt.Errorf("删除批注失败: %w", err)
+	}
+	return nil
+}
+
+// List 获取文档的批注（按页码和位置排序），pageNumber 为0时返回所有页面
+func (m *Manager) List(documentID string, pageNumber int) ([]Annotation, error) {
+	query := "SELECT * FROM page_annotations WHERE document_id = ?"
+	args := []interface{}{documentID}
+	if pageNumber > 0 {
+		query += " AND page_number = ?"
+		args = append(args, pageNumber)
+	}
+	query += " ORDER BY page_number, text_type, start_offset, id"
+
+	list := []Annotation{}
+	if err := m.db.Select(&list, query, args...); err != nil {
+		return nil, fmt.Errorf("查询批注失败: %w", err)
+	}
+	for i := range list {
+		if err := m.store.DecryptFields(&list[i].Quote, &list[i].Comment); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// DeleteDocument 删除文档的所有批注
+func (m *Manager) DeleteDocument(documentID string) error {
+	if _, err := m.db.Exec("DELETE FROM page_annotations WHERE document_id = ?", documentID); err != nil {
+		return fmt.Errorf("删除批注失败: %w", err)
+	}
+	return nil
+}
+
+// CopyDocument 将文档的批注复制到另一个文档ID（文档内容改变后ID随之改变）
+func (m *Manager) CopyDocument(fromID, toID string) error {
+	_, err := m.db.Exec(`
+	INSERT INTO page_annotations (document_id, page_number, text_type, start_offset, end_offset, quote, comment, created_at, updated_at)
+	SELECT ?, page_number, text_type, start_offset, end_offset, quote, comment, created_at, updated_at
+	FROM page_annotations WHERE document_id = ? ORDER BY id
+	`, toID, fromID)
+	if err != nil {
+		return fmt.Errorf("复制批注失败: %w", err)
+	}
+	return nil
+}
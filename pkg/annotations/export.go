@@ -0,0 +1,117 @@
+package annotations
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"pdf-ocr-ai/pkg/config"
+)
+
+// insertion 导出时插入到文本中的内容
+type insertion struct {
+	offset int // 字节位置
+	order  int // 同一位置按批注顺序插入
+	text   string
+}
+
+// Apply 按导出方式把批注写入页面文本
+// footnote：在批注位置加 [^批注N] 标记并在页面末尾追加脚注定义，之后可以和页面原有脚注一起按脚注配置处理
+// margin：在批注所在段落之后插入引用块形式的边注
+// 文本修改后找不到批注位置的批注放在页面末尾
+func Apply(text string, notes []Annotation, mode string) string {
+	if len(notes) == 0 || (mode != config.AnnotationExportFootnote && mode != config.AnnotationExportMargin) {
+		return text
+	}
+
+	runes := []rune(text)
+	var inserts []insertion
+	var definitions []string
+	for i, note := range notes {
+		end, ok := locate(runes, note)
+		offset := len(text)
+		if ok {
+			offset = len(string(runes[:end]))
+		}
+
+		switch mode {
+		case config.AnnotationExportFootnote:
+			marker := fmt.Sprintf("[^批注%d]", i+1)
+			if !ok {
+				// 找不到位置时标记放在正文末尾
+				offset = len(strings.TrimRight(text, "\n "))
+			}
+			inserts = append(inserts, insertion{offset: offset, order: i, text: marker})
+			definitions = append(definitions, fmt.Sprintf("%s: %s", marker, singleLine(note.Comment)))
+		case config.AnnotationExportMargin:
+			offset = paragraphEnd(text, offset)
+			comment := singleLine(note.Comment)
+			if note.Quote != "" {
+				comment = fmt.Sprintf("「%s」%s", singleLine(note.Quote), comment)
+			}
+			inserts = append(inserts, insertion{offset: offset, order: i, text: "\n\n> 批注：" + comment})
+		}
+	}
+
+	// 从后往前插入，前面的位置不受影响
+	sort.SliceStable(inserts, func(i, j int) bool {
+		if inserts[i].offset != inserts[j].offset {
+			return inserts[i].offset > inserts[j].offset
+		}
+		return inserts[i].order > inserts[j].order
+	})
+	for _, ins := range inserts {
+		text = text[:ins.offset] + ins.text + text[ins.offset:]
+	}
+
+	if len(definitions) > 0 {
+		text = strings.TrimRight(text, "\n ") + "\n\n" + strings.Join(definitions, "\n")
+	}
+	return text
+}
+
+// locate 找出批注在当前文本中的结束位置（按字符计）
+// 记录的位置上仍是批注时选中的文本时直接使用，否则取离原位置最近的同样文本
+func locate(runes []rune, note Annotation) (int, bool) {
+	quote := []rune(note.Quote)
+	if len(quote) == 0 {
+		if note.End >= 0 && note.End <= len(runes) {
+			return note.End, true
+		}
+		return 0, false
+	}
+	if note.Start >= 0 && note.End <= len(runes) && note.Start <= note.End && string(runes[note.Start:note.End]) == note.Quote {
+		return note.End, true
+	}
+
+	best, bestDistance := -1, 0
+	for i := 0; i+len(quote) <= len(runes); i++ {
+		if string(runes[i:i+len(quote)]) != note.Quote {
+			continue
+		}
+		distance := i - note.Start
+		if distance < 0 {
+			distance = -distance
+		}
+		if best < 0 || distance < bestDistance {
+			best, bestDistance = i, distance
+		}
+	}
+	if best < 0 {
+		return 0, false
+	}
+	return best + len(quote), true
+}
+
+// paragraphEnd 位置所在段落的结束位置（下一个空行之前或文本末尾）
+func paragraphEnd(text string, offset int) int {
+	if i := strings.Index(text[offset:], "\n\n"); i >= 0 {
+		return offset + i
+	}
+	return len(strings.TrimRight(text, "\n "))
+}
+
+// singleLine 合并为一行，避免破坏脚注定义或引用块
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
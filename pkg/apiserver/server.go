@@ -0,0 +1,414 @@
+package apiserver
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// maxUploadSize 上传文件大小上限
+const maxUploadSize = 512 << 20
+
+// eventsPath SSE事件流的路径
+const eventsPath = "/api/events"
+
+// Backend 由应用实现的处理接口
+type Backend interface {
+	OpenDocument(filePath string) (string, error)
+	ListDocuments() interface{}
+	StartOCR(documentID string, pages []int, force bool) (string, error)
+	StartAI(documentID string, pages []int, prompt string, contextMode bool, force bool) (string, error)
+	GetStatus(documentID string) (map[string]interface{}, error)
+	GetDocument(documentID string) (interface{}, error)
+	ExportDocument(documentID string, format string) (string, error)
+	CancelProcessing(documentID string) error
+}
+
+// Event 推送给SSE客户端的事件
+type Event struct {
+	Name string      `json:"event"`
+	Data interface{} `json:"data"`
+}
+
+// Server 本地HTTP API服务
+type Server struct {
+	backend   Backend
+	token     string
+	addr      string
+	uploadDir string
+
+	httpServer *http.Server
+
+	clientsMu sync.Mutex
+	clients   map[chan Event]struct{}
+}
+
+// GenerateToken 生成随机访问令牌
+func GenerateToken() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成访问令牌失败: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// NewServer 创建API服务（仅监听本机回环地址）
+func NewServer(backend Backend, port int, token string, uploadDir string) (*Server, error) {
+	if token == "" {
+		return nil, fmt.Errorf("API访问令牌不能为空")
+	}
+	if port <= 0 || port > 65535 {
+		return nil, fmt.Errorf("无效的端口: %d", port)
+	}
+	if err := os.MkdirAll(uploadDir, 0755); err != nil {
+		return nil, fmt.Errorf("创建上传目录失败: %w", err)
+	}
+
+	return &Server{
+		backend:   backend,
+		token:     token,
+		addr:      fmt.Sprintf("127.0.0.1:%d", port),
+		uploadDir: uploadDir,
+		clients:   make(map[chan Event]struct{}),
+	}, nil
+}
+
+// Addr 获取监听地址
+func (s *Server) Addr() string {
+	return s.addr
+}
+
+// Start 启动服务
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("监听 %s 失败: %w", s.addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/documents", s.handleListDocuments)
+	mux.HandleFunc("POST /api/documents", s.handleOpenDocument)
+	mux.HandleFunc("POST /api/documents/{id}/ocr", s.handleOCR)
+	mux.HandleFunc("POST /api/documents/{id}/ai", s.handleAI)
+	mux.HandleFunc("POST /api/documents/{id}/cancel", s.handleCancel)
+	mux.HandleFunc("GET /api/documents/{id}/status", s.handleStatus)
+	mux.HandleFunc("GET /api/documents/{id}/result", s.handleResult)
+	mux.HandleFunc("GET "+eventsPath, s.handleEvents)
+
+	s.httpServer = &http.Server{
+		Handler:           s.authenticate(mux),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		if err := s.httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Infof("API服务异常退出: %v", err)
+		}
+	}()
+
+	logger.Infof("API服务已启动: http://%s", s.addr)
+	return nil
+}
+
+// Stop 停止服务并断开所有事件订阅
+func (s *Server) Stop() error {
+	if s.httpServer == nil {
+		return nil
+	}
+
+	s.clientsMu.Lock()
+	for ch := range s.clients {
+		close(ch)
+	}
+	s.clients = make(map[chan Event]struct{})
+	s.clientsMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return s.httpServer.Shutdown(ctx)
+}
+
+// Publish 向所有SSE客户端推送事件，客户端处理过慢时丢弃事件
+func (s *Server) Publish(name string, data interface{}) {
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+
+	for ch := range s.clients {
+		select {
+		case ch <- Event{Name: name, Data: data}:
+		default:
+		}
+	}
+}
+
+// authenticate 校验访问令牌（支持 Authorization: Bearer、X-API-Token 头，事件流还支持 token 查询参数）
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" {
+			token = r.Header.Get("X-API-Token")
+		}
+		if token == "" && r.URL.Path == eventsPath {
+			// EventSource 无法设置请求头，只有事件流允许通过查询参数传递，避免令牌出现在其他接口的访问日志中
+			token = r.URL.Query().Get("token")
+		}
+
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			writeError(w, http.StatusUnauthorized, "无效的访问令牌")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleListDocuments 列出已打开的文档
+func (s *Server) handleListDocuments(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.backend.ListDocuments())
+}
+
+// handleOpenDocument 上传文件（multipart 的 file 字段）或通过 JSON {"file_path"} 打开本地文件
+func (s *Server) handleOpenDocument(w http.ResponseWriter, r *http.Request) {
+	var filePath string
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("读取上传文件失败: %v", err))
+			return
+		}
+		defer file.Close()
+
+		filePath, err = s.saveUpload(file, header.Filename)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	} else {
+		var req struct {
+			FilePath string `json:"file_path"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.FilePath == "" {
+			writeError(w, http.StatusBadRequest, "请求需要包含 file_path 或上传文件")
+			return
+		}
+		filePath = req.FilePath
+	}
+
+	documentID, err := s.backend.OpenDocument(filePath)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"document_id": documentID,
+		"file_path":   filePath,
+	})
+}
+
+// saveUpload 保存上传的文件到上传目录
+func (s *Server) saveUpload(src io.Reader, filename string) (string, error) {
+	name := filepath.Base(filename)
+	if name == "." || name == string(filepath.Separator) {
+		name = "upload"
+	}
+
+	target := filepath.Join(s.uploadDir, fmt.Sprintf("%d_%s", time.Now().UnixNano(), name))
+	out, err := os.Create(target)
+	if err != nil {
+		return "", fmt.Errorf("保存上传文件失败: %w", err)
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, src); err != nil {
+		os.Remove(target)
+		return "", fmt.Errorf("保存上传文件失败: %w", err)
+	}
+
+	return target, nil
+}
+
+// handleOCR 开始OCR处理，pages为空时处理全部页面
+func (s *Server) handleOCR(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Pages []int `json:"pages"`
+		Force bool  `json:"force"`
+	}
+	if !decodeOptionalJSON(w, r, &req) {
+		return
+	}
+
+	taskID, err := s.backend.StartOCR(r.PathValue("id"), req.Pages, req.Force)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]interface{}{"status": "started", "task_id": taskID})
+}
+
+// handleAI 开始AI处理
+func (s *Server) handleAI(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Pages       []int  `json:"pages"`
+		Prompt      string `json:"prompt"`
+		ContextMode bool   `json:"context_mode"`
+		Force       bool   `json:"force"`
+	}
+	if !decodeOptionalJSON(w, r, &req) {
+		return
+	}
+	if req.Prompt == "" {
+		writeError(w, http.StatusBadRequest, "AI处理需要提供 prompt")
+		return
+	}
+
+	taskID, err := s.backend.StartAI(r.PathValue("id"), req.Pages, req.Prompt, req.ContextMode, req.Force)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]interface{}{"status": "started", "task_id": taskID})
+}
+
+// handleCancel 取消文档的处理
+func (s *Server) handleCancel(w http.ResponseWriter, r *http.Request) {
+	if err := s.backend.CancelProcessing(r.PathValue("id")); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "cancelling"})
+}
+
+// handleStatus 获取文档处理状态
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := s.backend.GetStatus(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+// handleResult 获取处理结果，format 可选 json（默认）、txt、markdown、html
+func (s *Server) handleResult(w http.ResponseWriter, r *http.Request) {
+	documentID := r.PathValue("id")
+	format := r.URL.Query().Get("format")
+
+	if format == "" || format == "json" {
+		doc, err := s.backend.GetDocument(documentID)
+		if err != nil {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, doc)
+		return
+	}
+
+	content, err := s.backend.ExportDocument(documentID, format)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	contentType := "text/plain; charset=utf-8"
+	switch format {
+	case "markdown":
+		contentType = "text/markdown; charset=utf-8"
+	case "html":
+		contentType = "text/html; charset=utf-8"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, content)
+}
+
+// handleEvents 以SSE推送应用事件
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "不支持流式响应")
+		return
+	}
+
+	ch := make(chan Event, 64)
+	s.clientsMu.Lock()
+	s.clients[ch] = struct{}{}
+	s.clientsMu.Unlock()
+
+	defer func() {
+		s.clientsMu.Lock()
+		if _, exists := s.clients[ch]; exists {
+			delete(s.clients, ch)
+			close(ch)
+		}
+		s.clientsMu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(30 * time.Second)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			io.WriteString(w, ": ping\n\n")
+			flusher.Flush()
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(event.Data)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Name, data)
+			flusher.Flush()
+		}
+	}
+}
+
+// decodeOptionalJSON 解析可为空的JSON请求体
+func decodeOptionalJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("解析请求失败: %v", err))
+		return false
+	}
+	return true
+}
+
+// writeJSON 输出JSON响应
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError 输出错误响应
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{"error": message})
+}
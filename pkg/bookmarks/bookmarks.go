@@ -0,0 +1,116 @@
+package bookmarks
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"pdf-ocr-ai/pkg/migrate"
+	"pdf-ocr-ai/pkg/storage"
+)
+
+// migrationComponent 表结构迁移使用的组件名
+const migrationComponent = "bookmarks"
+
+// Bookmark 页面书签
+type Bookmark struct {
+	DocumentID string    `json:"document_id" db:"document_id"`
+	PageNumber int       `json:"page_number" db:"page_number"`
+	Label      string    `json:"label" db:"label"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Manager 页面书签存储，每个页面最多一个书签
+type Manager struct {
+	db    *sqlx.DB
+	store *storage.Store // 书签名称加解密
+}
+
+// NewManager 创建书签管理器，使用统一数据库中的 page_bookmarks 表
+func NewManager(store *storage.Store) (*Manager, error) {
+	m := &Manager{db: store.DB(), store: store}
+	if err := store.Migrate(migrationComponent, migrations()); err != nil {
+		return nil, err
+	}
+	store.RegisterEncryptedColumns("page_bookmarks", "label")
+	return m, nil
+}
+
+// migrations 表结构迁移
+func migrations() []migrate.Migration {
+	return []migrate.Migration{
+		{
+			Version:     1,
+			Description: "创建页面书签表",
+			Up: migrate.SQL(`
+			CREATE TABLE IF NOT EXISTS page_bookmarks (
+				document_id TEXT NOT NULL,
+				page_number INTEGER NOT NULL,
+				label TEXT NOT NULL DEFAULT '',
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (document_id, page_number)
+			)`),
+		},
+	}
+}
+
+// Set 添加或修改页面书签
+func (m *Manager) Set(documentID string, pageNumber int, label string) error {
+	if err := m.store.EncryptFields(&label); err != nil {
+		return err
+	}
+	now := time.Now()
+	_, err := m.db.Exec(`
+	INSERT INTO page_bookmarks (document_id, page_number, label, created_at, updated_at) VALUES (?, ?, ?, ?, ?)
+	ON CONFLICT(document_id, page_number) DO UPDATE SET label = excluded.label, updated_at = excluded.updated_at
+	`, documentID, pageNumber, label, now, now)
+	if err != nil {
+		return fmt.Errorf("保存书签失败: %w", err)
+	}
+	return nil
+}
+
+// Remove 删除页面书签
+func (m *Manager) Remove(documentID string, pageNumber int) error {
+	if _, err := m.db.Exec("DELETE FROM page_bookmarks WHERE document_id = ? AND page_number = ?", documentID, pageNumber); err != nil {
+		return fmt.Errorf("删除书签失败: %w", err)
+	}
+	return nil
+}
+
+// List 获取文档的书签（按页码排序）
+func (m *Manager) List(documentID string) ([]Bookmark, error) {
+	list := []Bookmark{}
+	if err := m.db.Select(&list, "SELECT * FROM page_bookmarks WHERE document_id = ? ORDER BY page_number", documentID); err != nil {
+		return nil, fmt.Errorf("查询书签失败: %w", err)
+	}
+	for i := range list {
+		if err := m.store.DecryptFields(&list[i].Label); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// DeleteDocument 删除文档的所有书签
+func (m *Manager) DeleteDocument(documentID string) error {
+	if _, err := m.db.Exec("DELETE FROM page_bookmarks WHERE document_id = ?", documentID); err != nil {
+		return fmt.Errorf("删除书签失败: %w", err)
+	}
+	return nil
+}
+
+// CopyDocument 将文档的书签复制到另一个文档ID，目标文档已有书签的页面保持不变
+func (m *Manager) CopyDocument(fromID, toID string) error {
+	_, err := m.db.Exec(`
+	INSERT OR IGNORE INTO page_bookmarks (document_id, page_number, label, created_at, updated_at)
+	SELECT ?, page_number, label, created_at, updated_at FROM page_bookmarks WHERE document_id = ?
+	`, toID, fromID)
+	if err != nil {
+		return fmt.Errorf("复制书签失败: %w", err)
+	}
+	return nil
+}
@@ -1,15 +1,19 @@
 package cache
 
 import (
-	"crypto/md5"
 	"database/sql"
 	"fmt"
-	"os"
 	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/mattn/go-sqlite3"
+
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/migrate"
+	"pdf-ocr-ai/pkg/storage"
 )
 
 // CacheEntry 缓存条目
@@ -20,6 +24,8 @@ type CacheEntry struct {
 	OriginalText string   `db:"original_text" json:"original_text"`
 	OCRText     string    `db:"ocr_text" json:"ocr_text"`
 	AIText      string    `db:"ai_text" json:"ai_text"`
+	ContentHash string    `db:"content_hash" json:"content_hash"` // 生成缓存时的文件内容哈希
+	Stale       bool      `db:"stale" json:"stale"`               // 文件内容已变化，缓存不再可用
 	CreatedAt   time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
 }
@@ -34,46 +40,77 @@ type DocumentCache struct {
 	Author     string    `db:"author" json:"author"`
 	CreatedAt  time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+	AccessedAt *time.Time `db:"accessed_at" json:"accessed_at,omitempty"`
 }
 
 // CacheManager 缓存管理器
 type CacheManager struct {
-	db *sqlx.DB
+	db    *sqlx.DB
+	store *storage.Store // 文本字段加解密
+
+	hashMu sync.Mutex
+	hashes map[string]fileHash // 文件路径 -> 内容哈希，文件大小和修改时间不变时复用
+
+	images *ImageCache // 渲染后的页面图片
+
+	pendingMu sync.Mutex
+	pending   map[pageKey]*CacheEntry // 已加入延迟写入队列、尚未提交的页面
+
+	// 启动以来的页面缓存命中统计
+	hits      atomic.Int64
+	misses    atomic.Int64
+	startedAt time.Time
 }
 
-// NewCacheManager 创建缓存管理器
-func NewCacheManager() (*CacheManager, error) {
-	// 获取用户目录
-	homeDir, err := os.UserHomeDir()
+// NewCacheManager 创建缓存管理器，使用统一数据库中的 documents 和 pages 表
+func NewCacheManager(store *storage.Store) (*CacheManager, error) {
+	cm := &CacheManager{db: store.DB(), store: store, hashes: make(map[string]fileHash),
+		pending: make(map[pageKey]*CacheEntry), startedAt: time.Now()}
+	store.RegisterEncryptedColumns("pages", "original_text", "ocr_text", "ai_text")
+
+	// 启用数据加密时不在磁盘上保留页面图片
+	images, err := newImageCache(filepath.Join(store.DataDir(), imageCacheDirName), cm.GenerateDocumentID,
+		func() bool { return !store.EncryptionEnabled() })
 	if err != nil {
-		return nil, fmt.Errorf("获取用户目录失败: %w", err)
+		return nil, err
 	}
+	cm.images = images
 
-	// 创建数据目录
-	dataDir := filepath.Join(homeDir, ".pdfSeer")
-	if err := os.MkdirAll(dataDir, 0755); err != nil {
-		return nil, fmt.Errorf("创建数据目录失败: %w", err)
+	// 运行数据库迁移
+	if err := store.Migrate(migrationComponent, cm.migrations()); err != nil {
+		return nil, fmt.Errorf("运行数据库迁移失败: %w", err)
 	}
 
-	// 连接数据库
-	dbPath := filepath.Join(dataDir, "cache.db")
-	db, err := sqlx.Connect("sqlite3", dbPath+"?cache=shared&_journal_mode=WAL")
-	if err != nil {
-		return nil, fmt.Errorf("连接数据库失败: %w", err)
+	// 导入旧版独立的 cache.db
+	if _, err := store.ImportLegacy("cache.db", migrationComponent, cm.migrations(), []string{"documents", "pages"}); err != nil {
+		return nil, fmt.Errorf("导入旧版缓存失败: %w", err)
 	}
 
-	cm := &CacheManager{db: db}
+	return cm, nil
+}
 
-	// 初始化数据库表
-	if err := cm.initTables(); err != nil {
-		return nil, fmt.Errorf("初始化数据库表失败: %w", err)
-	}
+// Images 获取页面图片缓存
+func (cm *CacheManager) Images() *ImageCache {
+	return cm.images
+}
 
-	return cm, nil
+// migrationComponent 缓存表在统一数据库中的迁移组件名
+const migrationComponent = "cache"
+
+// migrations 缓存数据库的版本化迁移
+// 早期版本没有记录版本号，各步骤需兼容已手动迁移过的数据库（先检查再修改）
+func (cm *CacheManager) migrations() []migrate.Migration {
+	return []migrate.Migration{
+		{Version: 1, Description: "创建缓存表", Up: cm.createTables},
+		{Version: 2, Description: "添加最近使用时间列", Up: cm.ensureAccessedAtColumn},
+		// 旧版文档标识改为打开文档时按需迁移（RekeyLegacyDocument），不在启动时计算所有源文件的哈希
+		{Version: 3, Description: "文档标识改为内容SHA-256", Up: migrate.SQL()},
+		{Version: 4, Description: "页面缓存记录内容哈希和过期标记", Up: cm.addContentHashColumns},
+	}
 }
 
-// initTables 初始化数据库表
-func (cm *CacheManager) initTables() error {
+// createTables 创建基础表结构（版本1）
+func (cm *CacheManager) createTables(tx *sqlx.Tx) error {
 	// 文档表
 	documentsSQL := `
 	CREATE TABLE IF NOT EXISTS documents (
@@ -110,7 +147,7 @@ func (cm *CacheManager) initTables() error {
 
 	// 执行SQL
 	for _, sql := range []string{documentsSQL, pagesSQL, indexSQL} {
-		if _, err := cm.db.Exec(sql); err != nil {
+		if _, err := tx.Exec(sql); err != nil {
 			return fmt.Errorf("执行SQL失败: %w", err)
 		}
 	}
@@ -118,51 +155,29 @@ func (cm *CacheManager) initTables() error {
 	return nil
 }
 
-// GenerateDocumentID 生成文档ID
-func (cm *CacheManager) GenerateDocumentID(filePath string) (string, error) {
-	// 获取文件信息
-	stat, err := os.Stat(filePath)
-	if err != nil {
-		return "", fmt.Errorf("获取文件信息失败: %w", err)
-	}
-
-	// 计算文件hash
-	file, err := os.Open(filePath)
-	if err != nil {
-		return "", fmt.Errorf("打开文件失败: %w", err)
-	}
-	defer file.Close()
-
-	hash := md5.New()
-	// 读取文件前1KB用于hash计算（避免大文件性能问题）
-	buffer := make([]byte, 1024)
-	n, _ := file.Read(buffer)
-	hash.Write(buffer[:n])
-
-	// 组合文件大小、修改时间和内容hash
-	hashStr := fmt.Sprintf("%d-%d-%x", 
-		stat.Size(), 
-		stat.ModTime().UnixNano(), 
-		hash.Sum(nil))
-
-	return hashStr, nil
-}
-
-// SaveDocument 保存文档信息
+// SaveDocument 保存文档信息（加入延迟写入队列，与页面缓存一起提交）
 func (cm *CacheManager) SaveDocument(doc *DocumentCache) error {
 	query := `
 	INSERT OR REPLACE INTO documents 
 	(id, file_path, file_hash, page_count, title, author, updated_at)
 	VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)`
 
-	_, err := cm.db.Exec(query, doc.ID, doc.FilePath, doc.FileHash, 
-		doc.PageCount, doc.Title, doc.Author)
-	
-	return err
+	saved := *doc
+	cm.store.Writer().Enqueue(func(tx *sqlx.Tx) error {
+		_, err := tx.Exec(query, saved.ID, saved.FilePath, saved.FileHash,
+			saved.PageCount, saved.Title, saved.Author)
+		return err
+	}, func(err error) {
+		if err != nil {
+			logger.Errorf("保存文档缓存失败 (%s): %v", saved.FilePath, err)
+		}
+	})
+	return nil
 }
 
 // GetDocument 获取文档信息
 func (cm *CacheManager) GetDocument(documentID string) (*DocumentCache, error) {
+	cm.sync()
 	var doc DocumentCache
 	query := `SELECT * FROM documents WHERE id = ?`
 	
@@ -174,43 +189,77 @@ func (cm *CacheManager) GetDocument(documentID string) (*DocumentCache, error) {
 	return &doc, err
 }
 
-// SavePage 保存页面缓存
+// SavePage 保存页面缓存（启用加密时文本字段加密保存）
+// 写入加入延迟写入队列批量提交，返回nil只表示已入队；提交失败记录日志，并由 Store.SyncErr 汇报
 func (cm *CacheManager) SavePage(entry *CacheEntry) error {
-	query := `
-	INSERT OR REPLACE INTO pages 
-	(document_id, page_number, original_text, ocr_text, ai_text, updated_at)
-	VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)`
+	originalText, ocrText, aiText := entry.OriginalText, entry.OCRText, entry.AIText
+	if err := cm.store.EncryptFields(&originalText, &ocrText, &aiText); err != nil {
+		return err
+	}
 
-	_, err := cm.db.Exec(query, entry.DocumentID, entry.PageNumber,
-		entry.OriginalText, entry.OCRText, entry.AIText)
-	
-	return err
+	cm.enqueuePage(entry, originalText, ocrText, aiText)
+	return nil
 }
 
-// GetPage 获取页面缓存
+// GetPage 获取页面缓存，过期或内容哈希不一致的缓存视为不存在
 func (cm *CacheManager) GetPage(documentID string, pageNumber int) (*CacheEntry, error) {
+	if pending := cm.pendingPage(documentID, pageNumber); pending != nil {
+		cm.recordLookup(true)
+		return pending, nil
+	}
+
 	var entry CacheEntry
-	query := `SELECT * FROM pages WHERE document_id = ? AND page_number = ?`
+	query := `SELECT * FROM pages WHERE document_id = ? AND page_number = ? AND stale = 0`
 	
 	err := cm.db.Get(&entry, query, documentID, pageNumber)
 	if err == sql.ErrNoRows {
+		cm.recordLookup(false)
 		return nil, nil
 	}
-	
+	if err != nil {
+		return nil, err
+	}
+
+	valid, err := cm.verifyEntries(documentID, []*CacheEntry{&entry})
+	cm.recordLookup(len(valid) > 0)
+	if len(valid) == 0 {
+		return nil, err
+	}
+	if err := cm.decryptEntry(&entry); err != nil {
+		return nil, err
+	}
 	return &entry, err
 }
 
-// GetDocumentPages 获取文档所有页面
+// GetDocumentPages 获取文档所有有效页面（不含过期缓存）
 func (cm *CacheManager) GetDocumentPages(documentID string) ([]*CacheEntry, error) {
+	cm.sync()
 	var entries []*CacheEntry
-	query := `SELECT * FROM pages WHERE document_id = ? ORDER BY page_number`
+	query := `SELECT * FROM pages WHERE document_id = ? AND stale = 0 ORDER BY page_number`
 	
 	err := cm.db.Select(&entries, query, documentID)
+	if err != nil {
+		return nil, err
+	}
+	cm.touchDocument(documentID)
+
+	entries, err = cm.verifyEntries(documentID, entries)
+	for _, entry := range entries {
+		if err := cm.decryptEntry(entry); err != nil {
+			return nil, err
+		}
+	}
 	return entries, err
 }
 
+// decryptEntry 解密缓存条目的文本字段
+func (cm *CacheManager) decryptEntry(entry *CacheEntry) error {
+	return cm.store.DecryptFields(&entry.OriginalText, &entry.OCRText, &entry.AIText)
+}
+
 // DeleteDocument 删除文档及其所有页面
 func (cm *CacheManager) DeleteDocument(documentID string) error {
+	cm.sync()
 	tx, err := cm.db.Beginx()
 	if err != nil {
 		return err
@@ -230,40 +279,42 @@ func (cm *CacheManager) DeleteDocument(documentID string) error {
 	return tx.Commit()
 }
 
-// CleanupOldCache 清理旧缓存
-func (cm *CacheManager) CleanupOldCache(days int) error {
-	cutoff := time.Now().AddDate(0, 0, -days)
+// CleanupOldCache 清理旧缓存，返回删除的文档数
+// 按页面最近一次处理的时间判断（没有页面时按文档创建时间），打开文档会更新 documents.updated_at，不能作为依据
+func (cm *CacheManager) CleanupOldCache(days int) (int, error) {
+	// updated_at 和 created_at 为UTC时间
+	cutoff := time.Now().UTC().AddDate(0, 0, -days).Format("2006-01-02 15:04:05")
+	cm.sync()
 	
 	tx, err := cm.db.Beginx()
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer tx.Rollback()
 
 	// 获取要删除的文档ID
 	var documentIDs []string
-	err = tx.Select(&documentIDs, 
-		"SELECT id FROM documents WHERE updated_at < ?", cutoff)
+	err = tx.Select(&documentIDs, `
+	SELECT d.id FROM documents d
+	WHERE COALESCE((SELECT MAX(p.updated_at) FROM pages p WHERE p.document_id = d.id), d.created_at) < ?`, cutoff)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	// 删除页面
+	// 删除页面和文档
 	for _, docID := range documentIDs {
 		if _, err := tx.Exec("DELETE FROM pages WHERE document_id = ?", docID); err != nil {
-			return err
+			return 0, err
+		}
+		if _, err := tx.Exec("DELETE FROM documents WHERE id = ?", docID); err != nil {
+			return 0, err
 		}
 	}
 
-	// 删除文档
-	if _, err := tx.Exec("DELETE FROM documents WHERE updated_at < ?", cutoff); err != nil {
-		return err
+	// 删除文件内容变化后已无对应文档的过期页面
+	if _, err := tx.Exec("DELETE FROM pages WHERE stale = 1 AND document_id NOT IN (SELECT id FROM documents)"); err != nil {
+		return 0, err
 	}
 
-	return tx.Commit()
-}
-
-// Close 关闭数据库连接
-func (cm *CacheManager) Close() error {
-	return cm.db.Close()
+	return len(documentIDs), tx.Commit()
 }
@@ -0,0 +1,161 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// fileHash 已计算的文件内容哈希
+type fileHash struct {
+	size    int64
+	modTime time.Time
+	hash    string
+}
+
+// GenerateDocumentID 生成文档ID：文件完整内容的SHA-256
+// 与路径和修改时间无关，移动或另存的相同文件共用缓存；同一进程内文件大小和修改时间不变时不重复计算
+func (cm *CacheManager) GenerateDocumentID(filePath string) (string, error) {
+	stat, err := os.Stat(filePath)
+	if err != nil {
+		return "", fmt.Errorf("获取文件信息失败: %w", err)
+	}
+
+	cm.hashMu.Lock()
+	cached, ok := cm.hashes[filePath]
+	cm.hashMu.Unlock()
+	if ok && cached.size == stat.Size() && cached.modTime.Equal(stat.ModTime()) {
+		return cached.hash, nil
+	}
+
+	hash, err := hashFile(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	cm.hashMu.Lock()
+	cm.hashes[filePath] = fileHash{size: stat.Size(), modTime: stat.ModTime(), hash: hash}
+	cm.hashMu.Unlock()
+
+	return hash, nil
+}
+
+// hashFile 分块计算文件内容的SHA-256
+func hashFile(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", fmt.Errorf("打开文件失败: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.CopyBuffer(hash, file, make([]byte, 1<<20)); err != nil {
+		return "", fmt.Errorf("计算文件哈希失败: %w", err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// RelocateDocument 文件移动或重命名后，将缓存中的文档路径改为新路径
+// 新文件内容必须与缓存的文档一致，返回文档ID；旧路径没有缓存时返回空字符串
+func (cm *CacheManager) RelocateDocument(oldPath, newPath string) (string, error) {
+	cm.sync()
+	var documentID string
+	err := cm.db.Get(&documentID, "SELECT id FROM documents WHERE file_path = ?", oldPath)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("查询文档缓存失败: %w", err)
+	}
+
+	newID, err := cm.GenerateDocumentID(newPath)
+	if err != nil {
+		return "", err
+	}
+	if newID != documentID {
+		return "", fmt.Errorf("文件内容与原文档不一致，无法关联: %s", newPath)
+	}
+
+	tx, err := cm.db.Beginx()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	// 新路径已有其他文档记录时先移除，file_path 唯一
+	if _, err := tx.Exec("DELETE FROM documents WHERE file_path = ? AND id != ?", newPath, documentID); err != nil {
+		return "", fmt.Errorf("更新缓存路径失败: %w", err)
+	}
+	if _, err := tx.Exec("UPDATE documents SET file_path = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?", newPath, documentID); err != nil {
+		return "", fmt.Errorf("更新缓存路径失败: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+
+	cm.hashMu.Lock()
+	delete(cm.hashes, oldPath)
+	cm.hashMu.Unlock()
+
+	return documentID, nil
+}
+
+// RekeyLegacyDocument 将该路径下旧版文档ID（文件大小-修改时间-前1KB的MD5）的缓存改为内容SHA-256
+// 启动迁移时不再计算所有缓存文档的哈希（需要读取全部源文件，文档多时启动很慢），改为打开文档时按需迁移
+func (cm *CacheManager) RekeyLegacyDocument(filePath, documentID string) error {
+	cm.sync()
+	var oldID string
+	err := cm.db.Get(&oldID, "SELECT id FROM documents WHERE file_path = ? AND id != ? AND length(id) != 64", filePath, documentID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("查询文档缓存失败: %w", err)
+	}
+
+	tx, err := cm.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// 历史记录表可能尚未创建（同一数据库中的其他组件）
+	var historyColumns int
+	err = tx.Get(&historyColumns, "SELECT COUNT(*) FROM pragma_table_info('processing_history') WHERE name = 'document_id'")
+	if err != nil {
+		return fmt.Errorf("读取表结构失败: %w", err)
+	}
+
+	var exists int
+	if err := tx.Get(&exists, "SELECT COUNT(*) FROM documents WHERE id = ?", documentID); err != nil {
+		return err
+	}
+
+	// 相同内容已有缓存时保留已有页面
+	if _, err := tx.Exec("UPDATE OR IGNORE pages SET document_id = ?, content_hash = ? WHERE document_id = ?", documentID, documentID, oldID); err != nil {
+		return fmt.Errorf("更新页面缓存失败: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM pages WHERE document_id = ?", oldID); err != nil {
+		return fmt.Errorf("更新页面缓存失败: %w", err)
+	}
+	if exists > 0 {
+		_, err = tx.Exec("DELETE FROM documents WHERE id = ?", oldID)
+	} else {
+		_, err = tx.Exec("UPDATE documents SET id = ?, file_hash = ? WHERE id = ?", documentID, documentID, oldID)
+	}
+	if err != nil {
+		return fmt.Errorf("更新文档缓存失败: %w", err)
+	}
+
+	if historyColumns > 0 {
+		if _, err := tx.Exec("UPDATE processing_history SET document_id = ? WHERE document_id = ?", documentID, oldID); err != nil {
+			return fmt.Errorf("更新历史记录文档标识失败: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,274 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// imageCacheDirName 渲染图片缓存目录（位于数据目录下）
+const imageCacheDirName = "page-images"
+
+// defaultImageCacheSize 默认的图片缓存容量
+const defaultImageCacheSize int64 = 1 << 30
+
+// ImageCacheStats 图片缓存统计
+type ImageCacheStats struct {
+	Files    int   `json:"files"`
+	Bytes    int64 `json:"bytes"`
+	MaxBytes int64 `json:"max_bytes"`
+}
+
+// ImageCache 渲染后的页面图片缓存
+// 文件按 <文档ID>/<页码>_<DPI>.jpg 保存，文档ID为内容哈希，文件修改后自动失效；超过容量时按最近使用时间淘汰
+type ImageCache struct {
+	dir     string
+	hasher  func(filePath string) (string, error)
+	enabled func() bool // 返回false时不读写缓存（如启用了数据加密）
+
+	mu       sync.Mutex
+	size     int64
+	maxBytes int64
+}
+
+// newImageCache 创建图片缓存并统计已有文件大小
+func newImageCache(dir string, hasher func(string) (string, error), enabled func() bool) (*ImageCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("创建图片缓存目录失败: %w", err)
+	}
+
+	ic := &ImageCache{dir: dir, hasher: hasher, enabled: enabled, maxBytes: defaultImageCacheSize}
+	files, err := ic.files()
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		ic.size += file.size
+	}
+	return ic, nil
+}
+
+// SetMaxBytes 设置缓存容量（<=0 表示使用默认值），超出时立即淘汰
+func (ic *ImageCache) SetMaxBytes(maxBytes int64) {
+	if maxBytes <= 0 {
+		maxBytes = defaultImageCacheSize
+	}
+	ic.mu.Lock()
+	ic.maxBytes = maxBytes
+	ic.mu.Unlock()
+
+	if _, err := ic.evict(); err != nil {
+		logger.Warnf("淘汰图片缓存失败: %v", err)
+	}
+}
+
+// pagePath 页面图片的缓存路径
+func (ic *ImageCache) pagePath(filePath string, pageNum, dpi int) (string, error) {
+	documentID, err := ic.hasher(filePath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(ic.dir, documentID, fmt.Sprintf("%d_%d.jpg", pageNum, dpi)), nil
+}
+
+// Get 获取已缓存的页面图片路径，命中时更新最近使用时间
+func (ic *ImageCache) Get(filePath string, pageNum, dpi int) (string, bool) {
+	if !ic.enabled() {
+		return "", false
+	}
+	path, err := ic.pagePath(filePath, pageNum, dpi)
+	if err != nil {
+		return "", false
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", false
+	}
+
+	now := time.Now()
+	os.Chtimes(path, now, now)
+	return path, true
+}
+
+// Put 保存渲染后的页面图片，返回缓存路径；不可缓存时返回空字符串
+func (ic *ImageCache) Put(filePath string, pageNum, dpi int, data []byte) (string, error) {
+	if !ic.enabled() {
+		return "", nil
+	}
+	path, err := ic.pagePath(filePath, pageNum, dpi)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("创建图片缓存目录失败: %w", err)
+	}
+
+	// 先写临时文件再重命名，避免并发渲染时读到不完整的图片
+	var previous int64
+	if info, err := os.Stat(path); err == nil {
+		previous = info.Size()
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return "", fmt.Errorf("保存图片缓存失败: %w", err)
+	}
+
+	ic.mu.Lock()
+	ic.size += int64(len(data)) - previous
+	ic.mu.Unlock()
+
+	if _, err := ic.evict(); err != nil {
+		logger.Warnf("淘汰图片缓存失败: %v", err)
+	}
+	return path, nil
+}
+
+// writeFileAtomic 在目标目录创建唯一的临时文件，写完后重命名，多个写入者互不覆盖
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// Stats 获取图片缓存统计
+func (ic *ImageCache) Stats() ImageCacheStats {
+	files, _ := ic.files()
+
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+	return ImageCacheStats{Files: len(files), Bytes: ic.size, MaxBytes: ic.maxBytes}
+}
+
+// ClearDocument 删除一个文档的所有缓存图片，返回释放的字节数
+func (ic *ImageCache) ClearDocument(documentID string) (int64, error) {
+	if documentID == "" || strings.ContainsAny(documentID, `/\`) {
+		return 0, nil
+	}
+	return ic.removeDir(filepath.Join(ic.dir, documentID))
+}
+
+// Clear 删除全部缓存图片，返回释放的字节数
+func (ic *ImageCache) Clear() (int64, error) {
+	entries, err := os.ReadDir(ic.dir)
+	if err != nil {
+		return 0, fmt.Errorf("读取图片缓存目录失败: %w", err)
+	}
+
+	var freed int64
+	for _, entry := range entries {
+		n, err := ic.removeDir(filepath.Join(ic.dir, entry.Name()))
+		freed += n
+		if err != nil {
+			return freed, err
+		}
+	}
+	return freed, nil
+}
+
+// removeDir 删除目录并更新缓存大小
+func (ic *ImageCache) removeDir(dir string) (int64, error) {
+	var freed int64
+	filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			if info, err := d.Info(); err == nil {
+				freed += info.Size()
+			}
+		}
+		return nil
+	})
+	if err := os.RemoveAll(dir); err != nil {
+		return 0, fmt.Errorf("删除图片缓存失败: %w", err)
+	}
+
+	ic.mu.Lock()
+	ic.size -= freed
+	if ic.size < 0 {
+		ic.size = 0
+	}
+	ic.mu.Unlock()
+	return freed, nil
+}
+
+// imageFile 缓存中的图片文件
+type imageFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// files 列出缓存中的所有图片
+func (ic *ImageCache) files() ([]imageFile, error) {
+	var files []imageFile
+	err := filepath.WalkDir(ic.dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".jpg") {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			files = append(files, imageFile{path: path, size: info.Size(), modTime: info.ModTime()})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("读取图片缓存目录失败: %w", err)
+	}
+	return files, nil
+}
+
+// evict 超过容量时按最近使用时间删除图片，返回删除的文件数
+func (ic *ImageCache) evict() (int, error) {
+	ic.mu.Lock()
+	over := ic.size > ic.maxBytes
+	ic.mu.Unlock()
+	if !over {
+		return 0, nil
+	}
+
+	files, err := ic.files()
+	if err != nil {
+		return 0, err
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+
+	// 以实际文件为准重新统计，修正并发写入带来的偏差
+	ic.size = 0
+	for _, file := range files {
+		ic.size += file.size
+	}
+
+	removed := 0
+	for _, file := range files {
+		if ic.size <= ic.maxBytes {
+			break
+		}
+		if err := os.Remove(file.path); err != nil {
+			continue
+		}
+		ic.size -= file.size
+		removed++
+		// 文档目录为空时一并删除
+		os.Remove(filepath.Dir(file.path))
+	}
+	return removed, nil
+}
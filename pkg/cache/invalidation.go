@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"fmt"
+	"os"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DocumentChange 同一路径的文件内容已变化（原地编辑）时的检测结果
+type DocumentChange struct {
+	FilePath           string `json:"file_path"`
+	PreviousDocumentID string `json:"previous_document_id"`
+	DocumentID         string `json:"document_id"`
+	StalePages         []int  `json:"stale_pages"` // 旧内容对应的缓存页面，已标记为过期
+}
+
+// addContentHashColumns 页面缓存记录生成时的文件内容哈希和过期标记（版本4）
+// 已有数据的内容哈希即文档ID
+func (cm *CacheManager) addContentHashColumns(tx *sqlx.Tx) error {
+	columns := map[string]string{
+		"content_hash": "ALTER TABLE pages ADD COLUMN content_hash TEXT",
+		"stale":        "ALTER TABLE pages ADD COLUMN stale INTEGER NOT NULL DEFAULT 0",
+	}
+	for _, column := range []string{"content_hash", "stale"} {
+		var count int
+		if err := tx.Get(&count, "SELECT COUNT(*) FROM pragma_table_info('pages') WHERE name = ?", column); err != nil {
+			return fmt.Errorf("检查 %s 列失败: %w", column, err)
+		}
+		if count > 0 {
+			continue
+		}
+		if _, err := tx.Exec(columns[column]); err != nil {
+			return fmt.Errorf("添加 %s 列失败: %w", column, err)
+		}
+	}
+
+	if _, err := tx.Exec("UPDATE pages SET content_hash = document_id WHERE content_hash IS NULL OR content_hash = ''"); err != nil {
+		return fmt.Errorf("回填内容哈希失败: %w", err)
+	}
+	return nil
+}
+
+// verifyEntries 校验缓存条目，过期的条目标记为过期并从结果中剔除
+// 文档文件当前的内容哈希（大小和修改时间不变时不重复计算）与文档ID不一致时，文件已被原地修改，该文档的缓存全部过期；
+// 条目记录的内容哈希与文档ID不一致时该条目过期。文件不存在时无法校验，保留缓存
+func (cm *CacheManager) verifyEntries(documentID string, entries []*CacheEntry) ([]*CacheEntry, error) {
+	if len(entries) > 0 && cm.documentChanged(documentID) {
+		if _, err := cm.db.Exec("UPDATE pages SET stale = 1 WHERE document_id = ?", documentID); err != nil {
+			return nil, fmt.Errorf("标记过期缓存失败: %w", err)
+		}
+		return nil, nil
+	}
+
+	valid := entries[:0]
+	var mismatched []int
+	for _, entry := range entries {
+		if entry.ContentHash != "" && entry.ContentHash != documentID {
+			mismatched = append(mismatched, entry.ID)
+			continue
+		}
+		valid = append(valid, entry)
+	}
+
+	for _, id := range mismatched {
+		if _, err := cm.db.Exec("UPDATE pages SET stale = 1 WHERE id = ?", id); err != nil {
+			return valid, fmt.Errorf("标记过期缓存失败: %w", err)
+		}
+	}
+	return valid, nil
+}
+
+// documentChanged 文档文件的当前内容是否已不是文档ID对应的内容
+// 旧版文档ID（非SHA-256）在打开文档时迁移，这里不校验
+func (cm *CacheManager) documentChanged(documentID string) bool {
+	if len(documentID) != sha256.Size*2 {
+		return false
+	}
+	var filePath string
+	if err := cm.db.Get(&filePath, "SELECT file_path FROM documents WHERE id = ?", documentID); err != nil {
+		return false
+	}
+	if _, err := os.Stat(filePath); err != nil {
+		return false
+	}
+	hash, err := cm.GenerateDocumentID(filePath)
+	return err == nil && hash != documentID
+}
+
+// CheckDocument 检查路径对应的缓存是否属于文件的旧内容
+// 文件被原地编辑后，旧内容的缓存页面会被标记为过期并返回变化信息；未变化或没有缓存时返回nil
+func (cm *CacheManager) CheckDocument(filePath, documentID string) (*DocumentChange, error) {
+	cm.sync()
+	var previousID string
+	err := cm.db.Get(&previousID, "SELECT id FROM documents WHERE file_path = ?", filePath)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询文档缓存失败: %w", err)
+	}
+	if previousID == documentID {
+		return nil, nil
+	}
+
+	if _, err := cm.db.Exec("UPDATE pages SET stale = 1 WHERE document_id = ?", previousID); err != nil {
+		return nil, fmt.Errorf("标记过期缓存失败: %w", err)
+	}
+
+	change := &DocumentChange{
+		FilePath:           filePath,
+		PreviousDocumentID: previousID,
+		DocumentID:         documentID,
+		StalePages:         []int{},
+	}
+	err = cm.db.Select(&change.StalePages,
+		"SELECT page_number FROM pages WHERE document_id = ? ORDER BY page_number", previousID)
+	if err != nil {
+		return nil, fmt.Errorf("查询过期页面失败: %w", err)
+	}
+	return change, nil
+}
@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// documentSizeSQL 每个文档缓存的文本大小（字节）
+const documentSizeSQL = `
+SELECT
+	d.id AS id,
+	COALESCE(SUM(LENGTH(CAST(p.original_text AS BLOB)) + LENGTH(CAST(p.ocr_text AS BLOB)) + LENGTH(CAST(p.ai_text AS BLOB))), 0) AS size
+FROM documents d
+LEFT JOIN pages p ON p.document_id = d.id
+GROUP BY d.id
+ORDER BY COALESCE(d.accessed_at, d.updated_at) ASC
+`
+
+// ensureAccessedAtColumn 为旧版数据库添加 accessed_at 列，用于按最近使用时间淘汰
+func (cm *CacheManager) ensureAccessedAtColumn(tx *sqlx.Tx) error {
+	var count int
+	err := tx.Get(&count, "SELECT COUNT(*) FROM pragma_table_info('documents') WHERE name = 'accessed_at'")
+	if err != nil {
+		return fmt.Errorf("读取表结构失败: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	if _, err := tx.Exec("ALTER TABLE documents ADD COLUMN accessed_at DATETIME"); err != nil {
+		return fmt.Errorf("添加 accessed_at 列失败: %w", err)
+	}
+	return nil
+}
+
+// touchDocument 更新文档的最近使用时间
+func (cm *CacheManager) touchDocument(documentID string) {
+	cm.db.Exec("UPDATE documents SET accessed_at = CURRENT_TIMESTAMP WHERE id = ?", documentID)
+}
+
+// Size 获取缓存文本的总大小（字节）
+func (cm *CacheManager) Size() (int64, error) {
+	cm.sync()
+	var size int64
+	err := cm.db.Get(&size, `
+	SELECT COALESCE(SUM(LENGTH(CAST(original_text AS BLOB)) + LENGTH(CAST(ocr_text AS BLOB)) + LENGTH(CAST(ai_text AS BLOB))), 0)
+	FROM pages
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("统计缓存大小失败: %w", err)
+	}
+	return size, nil
+}
+
+// EvictToSize 按最近使用时间淘汰文档缓存，直到总大小不超过maxBytes，返回淘汰的文档数和释放的字节数
+func (cm *CacheManager) EvictToSize(maxBytes int64) (int, int64, error) {
+	cm.sync()
+	var documents []struct {
+		ID   string `db:"id"`
+		Size int64  `db:"size"`
+	}
+	if err := cm.db.Select(&documents, documentSizeSQL); err != nil {
+		return 0, 0, fmt.Errorf("统计文档缓存大小失败: %w", err)
+	}
+
+	var total int64
+	for _, doc := range documents {
+		total += doc.Size
+	}
+
+	evicted := 0
+	var freed int64
+	for _, doc := range documents {
+		if total <= maxBytes {
+			break
+		}
+		if err := cm.DeleteDocument(doc.ID); err != nil {
+			return evicted, freed, fmt.Errorf("删除文档缓存失败: %w", err)
+		}
+		total -= doc.Size
+		freed += doc.Size
+		evicted++
+	}
+
+	return evicted, freed, nil
+}
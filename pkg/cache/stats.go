@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"fmt"
+	"time"
+)
+
+// CacheStats 缓存统计
+type CacheStats struct {
+	Documents    int     `json:"documents"`     // 缓存的文档数
+	Entries      int     `json:"entries"`       // 缓存的页面数（含过期）
+	StaleEntries int     `json:"stale_entries"` // 源文件已变化而过期的页面数
+	TextBytes    int64   `json:"text_bytes"`    // 缓存文本大小（字节）
+	DiskBytes    int64   `json:"disk_bytes"`    // 数据库文件大小（与历史记录共用）
+	DataDir      string  `json:"data_dir"`      // 数据目录
+	Hits         int64   `json:"hits"`          // 启动以来的页面缓存命中次数
+	Misses       int64   `json:"misses"`        // 启动以来的页面缓存未命中次数
+	HitRate      float64 `json:"hit_rate"`      // 命中率（0-1），没有查询时为0
+	Since        string  `json:"since"`         // 计数开始时间（应用启动时间）
+
+	Images ImageCacheStats `json:"images"` // 渲染图片缓存
+}
+
+// ClearResult 清除缓存的结果
+type ClearResult struct {
+	Documents  int   `json:"documents"`
+	Entries    int   `json:"entries"`
+	TextBytes  int64 `json:"text_bytes"`
+	ImageBytes int64 `json:"image_bytes"` // 删除的渲染图片大小
+	FreedBytes int64 `json:"freed_bytes"` // 压缩数据库后释放的磁盘空间
+}
+
+// recordLookup 记录一次页面缓存查询结果
+func (cm *CacheManager) recordLookup(hit bool) {
+	if hit {
+		cm.hits.Add(1)
+	} else {
+		cm.misses.Add(1)
+	}
+}
+
+// Stats 获取缓存统计
+func (cm *CacheManager) Stats() (*CacheStats, error) {
+	cm.sync()
+	stats := &CacheStats{
+		Hits:      cm.hits.Load(),
+		Misses:    cm.misses.Load(),
+		Since:     cm.startedAt.Format(time.RFC3339),
+		DiskBytes: cm.store.FilesSize(),
+		DataDir:   cm.store.DataDir(),
+	}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total)
+	}
+
+	if err := cm.db.Get(&stats.Documents, "SELECT COUNT(*) FROM documents"); err != nil {
+		return nil, fmt.Errorf("统计缓存文档失败: %w", err)
+	}
+	err := cm.db.QueryRow("SELECT COUNT(*), COALESCE(SUM(stale), 0) FROM pages").Scan(&stats.Entries, &stats.StaleEntries)
+	if err != nil {
+		return nil, fmt.Errorf("统计缓存页面失败: %w", err)
+	}
+
+	size, err := cm.Size()
+	if err != nil {
+		return nil, err
+	}
+	stats.TextBytes = size
+	stats.Images = cm.images.Stats()
+
+	return stats, nil
+}
+
+// ClearDocument 清除一个文档的缓存，文档没有缓存时返回空结果
+func (cm *CacheManager) ClearDocument(documentID string) (*ClearResult, error) {
+	cm.sync()
+	result := &ClearResult{}
+	err := cm.db.QueryRow(`
+	SELECT COUNT(*), COALESCE(SUM(LENGTH(CAST(original_text AS BLOB)) + LENGTH(CAST(ocr_text AS BLOB)) + LENGTH(CAST(ai_text AS BLOB))), 0)
+	FROM pages WHERE document_id = ?
+	`, documentID).Scan(&result.Entries, &result.TextBytes)
+	if err != nil {
+		return nil, fmt.Errorf("统计文档缓存失败: %w", err)
+	}
+	if err := cm.db.Get(&result.Documents, "SELECT COUNT(*) FROM documents WHERE id = ?", documentID); err != nil {
+		return nil, fmt.Errorf("统计文档缓存失败: %w", err)
+	}
+
+	if err := cm.DeleteDocument(documentID); err != nil {
+		return nil, fmt.Errorf("清除文档缓存失败: %w", err)
+	}
+	if result.ImageBytes, err = cm.images.ClearDocument(documentID); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// ClearAll 清除全部文档和页面缓存
+func (cm *CacheManager) ClearAll() (*ClearResult, error) {
+	stats, err := cm.Stats()
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := cm.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range []string{"DELETE FROM pages", "DELETE FROM documents"} {
+		if _, err := tx.Exec(stmt); err != nil {
+			return nil, fmt.Errorf("清除缓存失败: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("清除缓存失败: %w", err)
+	}
+
+	result := &ClearResult{Documents: stats.Documents, Entries: stats.Entries, TextBytes: stats.TextBytes}
+	if result.ImageBytes, err = cm.images.Clear(); err != nil {
+		return result, err
+	}
+	return result, nil
+}
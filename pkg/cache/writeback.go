@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"github.com/jmoiron/sqlx"
+
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// pageKey 页面缓存的键
+type pageKey struct {
+	documentID string
+	pageNumber int
+}
+
+// enqueuePage 将页面缓存加入延迟写入队列，提交前 GetPage 从内存中读取
+func (cm *CacheManager) enqueuePage(entry *CacheEntry, originalText, ocrText, aiText string) {
+	pending := *entry
+	pending.ContentHash = entry.DocumentID
+	pending.Stale = false
+	key := pageKey{documentID: entry.DocumentID, pageNumber: entry.PageNumber}
+
+	cm.pendingMu.Lock()
+	cm.pending[key] = &pending
+	cm.pendingMu.Unlock()
+
+	cm.store.Writer().Enqueue(func(tx *sqlx.Tx) error {
+		_, err := tx.Exec(`
+		INSERT OR REPLACE INTO pages
+		(document_id, page_number, original_text, ocr_text, ai_text, content_hash, stale, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, 0, CURRENT_TIMESTAMP)`,
+			pending.DocumentID, pending.PageNumber, originalText, ocrText, aiText, pending.DocumentID)
+		return err
+	}, func(err error) {
+		if err != nil {
+			logger.Errorf("保存页面缓存失败 (文档 %s 第 %d 页): %v", pending.DocumentID, pending.PageNumber, err)
+		}
+		// 同一页面在提交前可能再次保存，只移除本次加入的条目
+		cm.pendingMu.Lock()
+		if cm.pending[key] == &pending {
+			delete(cm.pending, key)
+		}
+		cm.pendingMu.Unlock()
+	})
+}
+
+// pendingPage 获取尚未提交的页面缓存（返回副本）
+func (cm *CacheManager) pendingPage(documentID string, pageNumber int) *CacheEntry {
+	cm.pendingMu.Lock()
+	defer cm.pendingMu.Unlock()
+
+	entry, ok := cm.pending[pageKey{documentID: documentID, pageNumber: pageNumber}]
+	if !ok {
+		return nil
+	}
+	copied := *entry
+	return &copied
+}
+
+// sync 批量读取或删除缓存前提交延迟写入，避免读到旧数据或删除后又被写回
+func (cm *CacheManager) sync() {
+	cm.store.Sync()
+}
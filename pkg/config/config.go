@@ -5,30 +5,143 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
+
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/secrets"
 )
 
+// apiKeySecretName API密钥在钥匙串或加密文件中的名称
+const apiKeySecretName = "ai-api-key"
+
+// secretField 保存在钥匙串或加密文件中的配置项，配置文件只保存引用
+type secretField struct {
+	name  string                                            // 在钥匙串或加密文件中的名称
+	label string                                            // 日志中显示的名称
+	field func(cfg *AppConfig) (value *string, ref *string) // 配置中的值和引用
+}
+
+// secretFields 所有保存在钥匙串或加密文件中的配置项
+var secretFields = []secretField{
+	{apiKeySecretName, "API密钥", func(cfg *AppConfig) (*string, *string) {
+		return &cfg.AI.APIKey, &cfg.AI.APIKeyRef
+	}},
+	{"notion-token", "Notion令牌", func(cfg *AppConfig) (*string, *string) {
+		return &cfg.Integrations.Notion.Token, &cfg.Integrations.Notion.TokenRef
+	}},
+	{"paperless-token", "Paperless-ngx令牌", func(cfg *AppConfig) (*string, *string) {
+		return &cfg.Integrations.Paperless.Token, &cfg.Integrations.Paperless.TokenRef
+	}},
+	{"remote-upload-password", "WebDAV密码", func(cfg *AppConfig) (*string, *string) {
+		return &cfg.RemoteUpload.Password, &cfg.RemoteUpload.PasswordRef
+	}},
+	{"remote-upload-secret-key", "S3访问密钥", func(cfg *AppConfig) (*string, *string) {
+		return &cfg.RemoteUpload.SecretKey, &cfg.RemoteUpload.SecretKeyRef
+	}},
+	{"api-server-token", "API服务令牌", func(cfg *AppConfig) (*string, *string) {
+		return &cfg.APIServer.Token, &cfg.APIServer.TokenRef
+	}},
+	{"webhook-secret", "Webhook密钥", func(cfg *AppConfig) (*string, *string) {
+		return &cfg.Webhook.Secret, &cfg.Webhook.SecretRef
+	}},
+}
+
+// clearSecretRefs 清除配置中的密钥引用（引用只在本机有效）
+func clearSecretRefs(cfg *AppConfig) {
+	for _, secret := range secretFields {
+		_, ref := secret.field(cfg)
+		*ref = ""
+	}
+}
+
+// keepSecretRefs 使用 current 中的密钥引用，引用由配置管理器维护，不接受外部修改
+func keepSecretRefs(cfg *AppConfig, current *AppConfig) {
+	for _, secret := range secretFields {
+		_, ref := secret.field(cfg)
+		_, currentRef := secret.field(current)
+		*ref = *currentRef
+	}
+}
+
 // AIConfig AI服务配置
 type AIConfig struct {
+	Provider        string  `json:"provider"` // 服务类型（openai、vllm、lmstudio、deepseek、moonshot 等），为空时为 openai
 	BaseURL         string  `json:"base_url"`
 	APIKey          string  `json:"api_key"`
-	Model           string  `json:"model"`           // 保持向后兼容，默认OCR模型
-	OCRModel        string  `json:"ocr_model"`       // OCR识别专用模型
-	TextModel       string  `json:"text_model"`      // 文本处理专用模型
-	ModelsEndpoint  string  `json:"models_endpoint"` // 模型列表API端点
-	ChatEndpoint    string  `json:"chat_endpoint"`   // 对话API端点
+	APIKeyRef       string  `json:"api_key_ref,omitempty"` // 密钥的保存位置（keychain:... 或 file:...），由配置管理器维护
+	Model           string  `json:"model"`                 // 保持向后兼容，默认OCR模型
+	OCRModel        string  `json:"ocr_model"`             // OCR识别专用模型
+	TextModel       string  `json:"text_model"`            // 文本处理专用模型
+	ModelsEndpoint  string  `json:"models_endpoint"`       // 模型列表API端点
+	ChatEndpoint    string  `json:"chat_endpoint"`         // 对话API端点
 	Timeout         int     `json:"timeout"`
 	RequestInterval float64 `json:"request_interval"`
 	BurstLimit      int     `json:"burst_limit"`
-	MaxRetries      int     `json:"max_retries"` // 最大重试次数
-	RetryDelay      int     `json:"retry_delay"` // 重试延迟（秒）
+	MaxRetries      int     `json:"max_retries"`     // 最大重试次数
+	RetryDelay      int     `json:"retry_delay"`     // 重试延迟（秒）
+	AutoTagging     bool    `json:"auto_tagging"`    // OCR完成后自动提取页面关键词和主题
+	ChapterContext  bool    `json:"chapter_context"` // 上下文模式中加入当前章节的摘要
+
+	ModelCapabilities   map[string]ModelCapability `json:"model_capabilities,omitempty"`    // 按模型名称缓存的能力检测结果
+	ModelContextLengths map[string]int             `json:"model_context_lengths,omitempty"` // 按模型名称设置的上下文长度（tokens），未设置时按模型名称判断
+	ModelPrices         map[string]ModelPrice      `json:"model_prices,omitempty"`          // 按模型名称设置的价格，用于估算费用
+}
+
+// ModelPrice 模型价格（每百万tokens）
+type ModelPrice struct {
+	Input  float64 `json:"input"`  // 输入（提示词）价格
+	Output float64 `json:"output"` // 输出（生成）价格
+}
+
+// ModelCapability 模型能力检测结果
+type ModelCapability struct {
+	Vision    bool   `json:"vision"`           // 是否支持图片输入（OCR需要）
+	BaseURL   string `json:"base_url"`         // 检测时使用的服务地址，地址变化后需重新检测
+	Detail    string `json:"detail,omitempty"` // 不支持时服务返回的错误信息
+	CheckedAt string `json:"checked_at"`
+}
+
+// Capability 获取当前服务地址下模型的能力检测结果，未检测过时返回false
+func (c AIConfig) Capability(model string) (ModelCapability, bool) {
+	capability, ok := c.ModelCapabilities[model]
+	if !ok || capability.BaseURL != c.BaseURL {
+		return ModelCapability{}, false
+	}
+	return capability, true
+}
+
+// Cost 按模型价格估算用量的费用，未设置价格时为0
+func (c AIConfig) Cost(model string, promptTokens, completionTokens int) float64 {
+	price := c.ModelPrices[model]
+	return (float64(promptTokens)*price.Input + float64(completionTokens)*price.Output) / 1e6
 }
 
 // StorageConfig 存储配置
 type StorageConfig struct {
-	CacheTTL         string `json:"cache_ttl"`
-	MaxCacheSize     string `json:"max_cache_size"`
-	HistoryRetention string `json:"history_retention"`
+	CacheTTL          string `json:"cache_ttl"`
+	MaxCacheSize      string `json:"max_cache_size"`
+	MaxImageCacheSize string `json:"max_image_cache_size"` // 渲染图片缓存容量
+	HistoryRetention  string `json:"history_retention"`
+	MaxPageRevisions  int    `json:"max_page_revisions"` // 每个页面每类文本保留的历史版本数
+	MemoryBudget      string `json:"memory_budget"`      // 内存预算，接近时释放渲染数据和后台文档的页面文本，为空表示不限制
+	MemoryPageWindow  int    `json:"memory_page_window"` // 释放页面文本时，查看页前后各保留的页数
+	MinFreeSpace      string `json:"min_free_space"`     // 渲染目录或数据目录所在磁盘可用空间低于该值时提示，为空表示不检查
+}
+
+// RenderConfig 页面渲染（libvips）配置，0 表示使用默认值
+type RenderConfig struct {
+	Concurrency   int `json:"concurrency"`      // 同时渲染的页数，0 表示CPU核数
+	CacheMaxOps   int `json:"cache_max_ops"`    // libvips 操作缓存的最大条目数
+	CacheMaxMemMB int `json:"cache_max_mem_mb"` // libvips 操作缓存的内存上限（MB）
+	Threads       int `json:"threads"`          // 每次渲染使用的线程数，渲染不稳定时可设为1
+
+	Tiling          bool    `json:"tiling"`            // OCR前将过高或过宽的页面切分为有重叠的图块分别识别
+	TileAspectRatio float64 `json:"tile_aspect_ratio"` // 长边与短边之比超过该值时切分，0 表示默认值
+
+	Hybrid         bool `json:"hybrid"`           // 文本层完整的页面直接使用原生文本，只识别其中的插图，其余页面整页OCR
+	HybridMinChars int  `json:"hybrid_min_chars"` // 文本层至少包含的文字数，低于该值整页OCR，0 表示默认值
 }
 
 // UIConfig 界面配置
@@ -36,13 +149,305 @@ type UIConfig struct {
 	Theme       string `json:"theme"`
 	DefaultFont string `json:"default_font"`
 	Layout      string `json:"layout"`
+	DropAction  string `json:"drop_action"` // 拖放文件的处理方式: auto、open 或 queue
+	Language    string `json:"language"`    // 后端消息和导出内容的语言: zh-CN、en 或 ja
+	ZipImport   string `json:"zip_import"`  // ZIP压缩包的导入方式: batch（多个文档）或 merge（合并为一个文档）
+	ZipOrder    string `json:"zip_order"`   // ZIP中文件的顺序: name（按文件名）或 archive（压缩包中的顺序）
+
+	ExportApprovedOnly bool `json:"export_approved_only"` // 导出、上传和集成只包含审核通过的页面
+}
+
+// TextCleanupConfig 导出文本清理配置：删除跨页重复的页眉页脚和单独的页码行
+type TextCleanupConfig struct {
+	Enabled     bool    `json:"enabled"`
+	Headers     bool    `json:"headers"`      // 删除重复的页眉
+	Footers     bool    `json:"footers"`      // 删除重复的页脚
+	PageNumbers bool    `json:"page_numbers"` // 删除页面开头或结尾单独的页码行
+	EdgeLines   int     `json:"edge_lines"`   // 每页开头和结尾检查的行数
+	MinRatio    float64 `json:"min_ratio"`    // 判定为重复需要出现的页面比例（0-1）
+}
+
+// ChineseConversionConfig 简繁转换配置，转换方式为 s2t、t2s、s2tw、tw2s、s2hk 或 hk2s，为空时不转换
+type ChineseConversionConfig struct {
+	Results string `json:"results"` // 保存OCR和AI结果前的转换方式
+	Export  string `json:"export"`  // 导出文本的转换方式
+}
+
+// 脚注处理方式
+const (
+	FootnoteModeKeep    = "keep"    // 保持原样
+	FootnoteModeInline  = "inline"  // 将脚注内容插入正文引用处
+	FootnoteModeChapter = "chapter" // 重新编号并汇总到每章末尾（没有章节时汇总到文末）
+)
+
+// FootnoteConfig 导出时的脚注处理配置
+type FootnoteConfig struct {
+	Mode string `json:"mode"` // keep、inline 或 chapter
+}
+
+// 批注导出方式
+const (
+	AnnotationExportNone     = "none"     // 不导出批注
+	AnnotationExportFootnote = "footnote" // 在批注位置加脚注标记，批注内容作为页面脚注
+	AnnotationExportMargin   = "margin"   // 在批注所在段落之后插入边注
+)
+
+// AnnotationConfig 页面批注配置
+type AnnotationConfig struct {
+	ExportMode string `json:"export_mode"` // none、footnote 或 margin
+}
+
+// 语音合成服务类型
+const (
+	TTSProviderOpenAI  = "openai"  // OpenAI兼容的语音合成接口（/audio/speech），使用AI服务的地址和密钥
+	TTSProviderCommand = "command" // 本地语音合成命令
+)
+
+// TTSConfig 语音导出配置
+type TTSConfig struct {
+	Provider string  `json:"provider"`  // openai 或 command
+	Model    string  `json:"model"`     // 语音合成模型，如 tts-1
+	Voice    string  `json:"voice"`     // 音色，如 alloy
+	Speed    float64 `json:"speed"`     // 语速（0.25-4.0）
+	Command  string  `json:"command"`   // 本地命令模板，{input} 为输入文本文件，{output} 为输出MP3文件
+	MaxChars int     `json:"max_chars"` // 单次合成的最大字符数，超过时按句子分段合成
+}
+
+// ObsidianConfig 导出到Obsidian库的配置
+type ObsidianConfig struct {
+	VaultPath     string `json:"vault_path"`     // Obsidian库所在目录
+	Folder        string `json:"folder"`         // 库中保存笔记的子目录，为空时保存在库的根目录
+	SplitChapters bool   `json:"split_chapters"` // 按一级标题每章写一个笔记，另写一个链接各章的索引笔记
+	Tags          bool   `json:"tags"`           // 将页面的主题标签写入笔记的 tags
+}
+
+// NotionConfig 推送到Notion的配置
+type NotionConfig struct {
+	Token        string `json:"token"`               // Notion集成的访问令牌（Internal Integration Secret）
+	TokenRef     string `json:"token_ref,omitempty"` // 令牌的保存位置，由配置管理器维护
+	ParentPageID string `json:"parent_page_id"`      // 在该页面下创建子页面，需要先将页面共享给集成
+}
+
+// PaperlessConfig 推送到Paperless-ngx的配置
+type PaperlessConfig struct {
+	URL                string   `json:"url"`                 // Paperless-ngx的地址，如 http://localhost:8000
+	Token              string   `json:"token"`               // API令牌（在Paperless-ngx的个人资料页面生成）
+	TokenRef           string   `json:"token_ref,omitempty"` // 令牌的保存位置，由配置管理器维护
+	Tags               []string `json:"tags"`                // 每个文档都添加的标签，不存在时自动创建
+	TopicTags          bool     `json:"topic_tags"`          // 同时添加页面的主题标签
+	Correspondent      string   `json:"correspondent"`       // 默认的联系人，为空时不设置
+	DocumentType       string   `json:"document_type"`       // 文档类型，为空时不设置
+	ExtractionSchema   string   `json:"extraction_schema"`   // 从该提取模板的结果中读取文档日期（第一个日期字段）和联系人
+	CorrespondentField string   `json:"correspondent_field"` // 提取结果中作为联系人的字段名，如 seller
+}
+
+// IntegrationsConfig 第三方应用集成配置
+type IntegrationsConfig struct {
+	Obsidian  ObsidianConfig  `json:"obsidian"`
+	Notion    NotionConfig    `json:"notion"`
+	Paperless PaperlessConfig `json:"paperless"`
+}
+
+// 远程存储类型
+const (
+	RemoteTypeWebDAV = "webdav" // WebDAV共享目录
+	RemoteTypeS3     = "s3"     // S3兼容的对象存储
+)
+
+// RemoteUploadConfig 批处理完成后将导出文件上传到远程存储的配置
+type RemoteUploadConfig struct {
+	Enabled      bool   `json:"enabled"`
+	Type         string `json:"type"`                     // webdav 或 s3
+	URL          string `json:"url"`                      // WebDAV目录地址，或S3服务地址（如 https://s3.amazonaws.com）
+	Username     string `json:"username"`                 // WebDAV用户名
+	Password     string `json:"password"`                 // WebDAV密码
+	PasswordRef  string `json:"password_ref,omitempty"`   // 密码的保存位置，由配置管理器维护
+	Bucket       string `json:"bucket"`                   // S3存储桶
+	Region       string `json:"region"`                   // S3区域，为空时为 us-east-1
+	AccessKey    string `json:"access_key"`               // S3访问密钥ID
+	SecretKey    string `json:"secret_key"`               // S3访问密钥
+	SecretKeyRef string `json:"secret_key_ref,omitempty"` // 访问密钥的保存位置，由配置管理器维护
+	PathStyle    bool   `json:"path_style"`               // S3使用路径形式的地址（MinIO等自建服务通常需要）
+	Prefix       string `json:"prefix"`                   // 上传到的远程目录（S3为对象键前缀）
+	Format       string `json:"format"`                   // 批处理完成后导出的格式: txt、markdown 或 html
+	Retries      int    `json:"retries"`                  // 上传失败时的重试次数
+	Timeout      int    `json:"timeout"`                  // 单个文件上传的超时（秒）
+}
+
+// WatchFolderConfig 监视文件夹配置
+type WatchFolderConfig struct {
+	Enabled      bool   `json:"enabled"`
+	InputDir     string `json:"input_dir"`     // 监视的目录
+	OutputDir    string `json:"output_dir"`    // 导出结果目录
+	ExportFormat string `json:"export_format"` // txt、markdown 或 html
+	TaskType     string `json:"task_type"`     // ocr 或 ai（AI任务先OCR再AI处理）
+	Prompt       string `json:"prompt"`        // AI任务使用的提示词
+	PollInterval int    `json:"poll_interval"` // 轮询间隔（秒）
+	Profile      string `json:"profile"`       // 目录中文件使用的处理配置ID，为空时使用默认配置
+}
+
+// APIServerConfig 本地HTTP API服务配置
+type APIServerConfig struct {
+	Enabled  bool   `json:"enabled"`
+	Port     int    `json:"port"`                // 仅监听127.0.0.1
+	Token    string `json:"token"`               // 访问令牌，为空时启用服务会自动生成
+	TokenRef string `json:"token_ref,omitempty"` // 令牌的保存位置，由配置管理器维护
+}
+
+// WebhookConfig 批处理完成通知配置
+type WebhookConfig struct {
+	Enabled    bool   `json:"enabled"`
+	URL        string `json:"url"`                  // 接收JSON的回调地址
+	Secret     string `json:"secret"`               // 可选，作为 Bearer 令牌发送
+	SecretRef  string `json:"secret_ref,omitempty"` // 密钥的保存位置，由配置管理器维护
+	ScriptPath string `json:"script_path"`          // 可选，本地脚本（通过标准输入接收JSON）
+	Timeout    int    `json:"timeout"`              // 超时（秒）
+}
+
+// NotificationConfig 桌面通知配置
+type NotificationConfig struct {
+	Enabled     bool `json:"enabled"`       // 批处理完成或失败时发送系统通知
+	Sound       bool `json:"sound"`         // 同时播放提示音（默认关闭）
+	OnRateLimit bool `json:"on_rate_limit"` // API限流导致等待重试时通知
+}
+
+// LoggingConfig 日志配置
+type LoggingConfig struct {
+	Level string `json:"level"` // debug、info、warn 或 error
+}
+
+// ScheduleConfig 定时任务配置
+type ScheduleConfig struct {
+	ID      string            `json:"id"`
+	Name    string            `json:"name"`
+	Cron    string            `json:"cron"`   // 5段cron表达式，如 "0 3 * * *"，支持 @daily 等别名
+	Task    string            `json:"task"`   // cleanup、watch_sweep 或 re_export
+	Params  map[string]string `json:"params"` // 任务参数，如 re_export 的 output_dir、format
+	Enabled bool              `json:"enabled"`
+}
+
+// ProcessingProfile 命名的处理配置，可指定给文档或监视文件夹，未设置的项使用全局设置
+type ProcessingProfile struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	RenderDPI      int      `json:"render_dpi"`      // OCR使用的渲染分辨率，0 表示默认（72）
+	OCRModel       string   `json:"ocr_model"`       // OCR模型，为空时使用AI配置中的模型
+	PromptTemplate string   `json:"prompt_template"` // 未指定提示词时AI处理使用的提示词
+	Preprocessing  []string `json:"preprocessing"`   // OCR前按顺序执行的图片预处理步骤
+	Concurrency    int      `json:"concurrency"`     // 同时处理的页数，0 表示默认
+	// RenderConcurrency 同时渲染的页数，与识别的并发数分开设置，0 表示默认
+	RenderConcurrency int `json:"render_concurrency"`
+	// PostProcessing 保存OCR和AI结果前按顺序执行的后处理规则
+	PostProcessing []PostProcessRule `json:"post_processing"`
+}
+
+// 后处理规则类型
+const (
+	PostProcessRegex      = "regex"      // 正则替换
+	PostProcessStrip      = "strip"      // 删除指定短语
+	PostProcessWhitespace = "whitespace" // 规范空白：去除行尾空白、合并连续空格和多余空行
+	PostProcessHalfWidth  = "half_width" // 全角字母、数字和空格转换为半角
+	PostProcessFullWidth  = "full_width" // 半角标点转换为全角（中文排版）
+	PostProcessChinese    = "chinese"    // 简繁转换，Pattern 为转换方式
+)
+
+// PostProcessRule 结果后处理规则
+type PostProcessRule struct {
+	Type        string `json:"type"`
+	Pattern     string `json:"pattern,omitempty"`     // regex 的正则表达式、strip 要删除的短语或 chinese 的转换方式
+	Replacement string `json:"replacement,omitempty"` // regex 的替换内容，支持 $1 引用分组
+	Target      string `json:"target,omitempty"`      // ocr、ai 或空（两者都执行）
+	Enabled     bool   `json:"enabled"`
+}
+
+// 提取字段的类型
+const (
+	FieldTypeText    = "text"
+	FieldTypeNumber  = "number"
+	FieldTypeDate    = "date"
+	FieldTypeBoolean = "boolean"
+)
+
+// ExtractionField 结构化提取的字段
+type ExtractionField struct {
+	Name        string `json:"name"`        // 字段名，用作JSON键和CSV列名
+	Label       string `json:"label"`       // 显示名称
+	Type        string `json:"type"`        // text、number、date 或 boolean
+	Description string `json:"description"` // 提供给模型的字段说明
+	Required    bool   `json:"required"`    // 页面中缺少该字段时标记为不完整
+}
+
+// ExtractionSchema 结构化提取的字段模板，如发票、合同
+type ExtractionSchema struct {
+	ID     string            `json:"id"`
+	Name   string            `json:"name"`
+	Fields []ExtractionField `json:"fields"`
+}
+
+// GlossaryTerm 术语表条目：原文中的写法及统一使用的写法
+type GlossaryTerm struct {
+	Term          string `json:"term"`           // 需要统一的写法，如 pdfseer
+	Preferred     string `json:"preferred"`      // 统一使用的写法，如 pdfSeer
+	CaseSensitive bool   `json:"case_sensitive"` // 是否区分大小写匹配
+	Note          string `json:"note"`           // 提供给模型的说明（可选）
+}
+
+// GlossaryConfig 术语表配置，AI处理时加入提示词并在结果中统一替换
+type GlossaryConfig struct {
+	Enabled bool           `json:"enabled"`
+	Terms   []GlossaryTerm `json:"terms"`
 }
 
 // AppConfig 应用配置
 type AppConfig struct {
-	AI      AIConfig      `json:"ai"`
-	Storage StorageConfig `json:"storage"`
-	UI      UIConfig      `json:"ui"`
+	AI            AIConfig           `json:"ai"`
+	Storage       StorageConfig      `json:"storage"`
+	Render        RenderConfig       `json:"render"`
+	UI            UIConfig           `json:"ui"`
+	WatchFolder   WatchFolderConfig  `json:"watch_folder"`
+	APIServer     APIServerConfig    `json:"api_server"`
+	Schedules     []ScheduleConfig   `json:"schedules"`
+	Webhook       WebhookConfig      `json:"webhook"`
+	Notifications NotificationConfig `json:"notifications"`
+	Logging       LoggingConfig      `json:"logging"`
+
+	Profiles       []ProcessingProfile `json:"profiles"`
+	DefaultProfile string              `json:"default_profile"` // 未指定处理配置的文档使用的配置ID，为空时使用全局设置
+
+	ExtractionSchemas []ExtractionSchema      `json:"extraction_schemas"`
+	Glossary          GlossaryConfig          `json:"glossary"`
+	TextCleanup       TextCleanupConfig       `json:"text_cleanup"`
+	ChineseConversion ChineseConversionConfig `json:"chinese_conversion"`
+	Footnotes         FootnoteConfig          `json:"footnotes"`
+	Annotations       AnnotationConfig        `json:"annotations"`
+	TTS               TTSConfig               `json:"tts"`
+	Integrations      IntegrationsConfig      `json:"integrations"`
+	RemoteUpload      RemoteUploadConfig      `json:"remote_upload"`
+}
+
+// FindProfile 按ID查找处理配置，不存在时返回nil
+func (c AppConfig) FindProfile(id string) *ProcessingProfile {
+	if id == "" {
+		return nil
+	}
+	for i := range c.Profiles {
+		if c.Profiles[i].ID == id {
+			profile := c.Profiles[i]
+			return &profile
+		}
+	}
+	return nil
+}
+
+// FindExtractionSchema 按ID查找提取模板，不存在时返回nil
+func (c AppConfig) FindExtractionSchema(id string) *ExtractionSchema {
+	for i := range c.ExtractionSchemas {
+		if c.ExtractionSchemas[i].ID == id {
+			schema := c.ExtractionSchemas[i]
+			return &schema
+		}
+	}
+	return nil
 }
 
 // ConfigManager 配置管理器
@@ -50,6 +455,11 @@ type ConfigManager struct {
 	configPath string
 	config     AppConfig
 	mu         sync.RWMutex
+
+	secrets *secrets.Store
+	stored  map[string]string // 钥匙串或加密文件中当前保存的密钥（按名称），未变化时保存配置不再重复写入
+
+	file fileState // 最近一次读取或写入的配置文件状态，用于发现外部修改
 }
 
 // NewConfigManager 创建配置管理器
@@ -68,6 +478,8 @@ func NewConfigManager() (*ConfigManager, error) {
 
 	cm := &ConfigManager{
 		configPath: configPath,
+		secrets:    secrets.NewStore(configDir),
+		stored:     map[string]string{},
 	}
 
 	// 加载配置
@@ -75,17 +487,36 @@ func NewConfigManager() (*ConfigManager, error) {
 		return nil, err
 	}
 
+	// 旧版配置文件中的明文密钥和令牌迁移到钥匙串或加密文件
+	cm.mu.RLock()
+	var legacy []secretField
+	for _, secret := range secretFields {
+		if value, ref := secret.field(&cm.config); *value != "" && *ref == "" {
+			legacy = append(legacy, secret)
+		}
+	}
+	cm.mu.RUnlock()
+	if len(legacy) > 0 {
+		if err := cm.Save(); err != nil {
+			logger.Warnf("迁移密钥失败: %v", err)
+		} else {
+			cfg := cm.GetConfig()
+			for _, secret := range legacy {
+				if _, ref := secret.field(&cfg); *ref != "" {
+					logger.Infof("已将%s从配置文件迁移到 %s", secret.label, *ref)
+				}
+			}
+		}
+	}
+
 	return cm, nil
 }
 
-// Load 加载配置
-func (cm *ConfigManager) Load() error {
-	cm.mu.Lock()
-	defer cm.mu.Unlock()
-
-	// 设置默认配置
-	cm.config = AppConfig{
+// DefaultConfig 默认配置
+func DefaultConfig() AppConfig {
+	return AppConfig{
 		AI: AIConfig{
+			Provider:        "openai",
 			BaseURL:         "https://api.openai.com/v1",
 			Model:           "gpt-4-vision-preview", // 保持向后兼容
 			OCRModel:        "gpt-4-vision-preview", // OCR默认使用视觉模型
@@ -99,33 +530,233 @@ func (cm *ConfigManager) Load() error {
 			RetryDelay:      1, // 默认延迟1秒
 		},
 		Storage: StorageConfig{
-			CacheTTL:         "24h",
-			MaxCacheSize:     "2GB",
-			HistoryRetention: "30d",
+			CacheTTL:          "24h",
+			MaxCacheSize:      "2GB",
+			MaxImageCacheSize: "1GB",
+			HistoryRetention:  "30d",
+			MaxPageRevisions:  20,
+			MemoryBudget:      "2GB",
+			MemoryPageWindow:  20,
+			MinFreeSpace:      "1GB",
 		},
 		UI: UIConfig{
 			Theme:       "light",
 			DefaultFont: "system",
 			Layout:      "split",
+			DropAction:  "auto",
+			Language:    "zh-CN",
+			ZipImport:   "batch",
+			ZipOrder:    "name",
+		},
+		WatchFolder: WatchFolderConfig{
+			Enabled:      false,
+			ExportFormat: "txt",
+			TaskType:     "ocr",
+			PollInterval: 5,
+		},
+		APIServer: APIServerConfig{
+			Enabled: false,
+			Port:    8765,
+		},
+		Schedules: []ScheduleConfig{
+			{
+				ID:      "daily-cleanup",
+				Name:    "清理过期缓存和历史记录",
+				Cron:    "0 3 * * *",
+				Task:    "cleanup",
+				Enabled: false,
+			},
+		},
+		Webhook: WebhookConfig{
+			Enabled: false,
+			Timeout: 10,
+		},
+		Notifications: NotificationConfig{
+			Enabled:     true,
+			Sound:       false,
+			OnRateLimit: true,
+		},
+		Logging: LoggingConfig{
+			Level: "info",
+		},
+		Profiles: []ProcessingProfile{
+			{
+				ID:             "invoice",
+				Name:           "发票和票据",
+				RenderDPI:      150,
+				PromptTemplate: "请从以下发票内容中提取发票号码、开票日期、购买方、销售方、金额、税额和价税合计，以Markdown表格输出。",
+				Preprocessing:  []string{"grayscale", "contrast", "sharpen"},
+			},
+			{
+				ID:             "paper",
+				Name:           "学术论文",
+				RenderDPI:      200,
+				PromptTemplate: "请将以下论文内容整理为结构清晰的Markdown，保留章节标题、公式（使用LaTeX）、图表说明和参考文献。",
+				Preprocessing:  []string{"grayscale", "contrast"},
+			},
+		},
+		TextCleanup: TextCleanupConfig{
+			Enabled:     false,
+			Headers:     true,
+			Footers:     true,
+			PageNumbers: true,
+			EdgeLines:   3,
+			MinRatio:    0.5,
+		},
+		Footnotes: FootnoteConfig{
+			Mode: FootnoteModeKeep,
+		},
+		Annotations: AnnotationConfig{
+			ExportMode: AnnotationExportNone,
+		},
+		TTS: TTSConfig{
+			Provider: TTSProviderOpenAI,
+			Model:    "tts-1",
+			Voice:    "alloy",
+			Speed:    1.0,
+			MaxChars: 4000,
+		},
+		Integrations: IntegrationsConfig{
+			Obsidian: ObsidianConfig{
+				Folder: "pdfSeer",
+				Tags:   true,
+			},
+			Paperless: PaperlessConfig{
+				Tags:      []string{"pdfSeer"},
+				TopicTags: true,
+			},
+		},
+		RemoteUpload: RemoteUploadConfig{
+			Enabled: false,
+			Type:    RemoteTypeWebDAV,
+			Format:  "markdown",
+			Retries: 3,
+			Timeout: 60,
+		},
+		Glossary: GlossaryConfig{
+			Enabled: true,
+			Terms:   []GlossaryTerm{},
+		},
+		ExtractionSchemas: []ExtractionSchema{
+			{
+				ID:   "invoice",
+				Name: "发票",
+				Fields: []ExtractionField{
+					{Name: "invoice_number", Label: "发票号码", Type: FieldTypeText, Description: "发票号码", Required: true},
+					{Name: "invoice_date", Label: "开票日期", Type: FieldTypeDate, Description: "开票日期", Required: true},
+					{Name: "buyer", Label: "购买方", Type: FieldTypeText, Description: "购买方名称"},
+					{Name: "seller", Label: "销售方", Type: FieldTypeText, Description: "销售方名称"},
+					{Name: "amount", Label: "金额", Type: FieldTypeNumber, Description: "不含税金额"},
+					{Name: "tax", Label: "税额", Type: FieldTypeNumber, Description: "税额"},
+					{Name: "total", Label: "价税合计", Type: FieldTypeNumber, Description: "价税合计（小写金额）", Required: true},
+				},
+			},
+			{
+				ID:   "contract",
+				Name: "合同",
+				Fields: []ExtractionField{
+					{Name: "contract_number", Label: "合同编号", Type: FieldTypeText, Description: "合同编号"},
+					{Name: "party_a", Label: "甲方", Type: FieldTypeText, Description: "甲方名称", Required: true},
+					{Name: "party_b", Label: "乙方", Type: FieldTypeText, Description: "乙方名称", Required: true},
+					{Name: "sign_date", Label: "签订日期", Type: FieldTypeDate, Description: "合同签订日期"},
+					{Name: "amount", Label: "合同金额", Type: FieldTypeNumber, Description: "合同总金额"},
+				},
+			},
 		},
 	}
+}
+
+// Load 加载配置
+func (cm *ConfigManager) Load() error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	// 设置默认配置
+	cm.config = DefaultConfig()
 
 	// 尝试从文件加载
 	if data, err := os.ReadFile(cm.configPath); err == nil {
 		if err := json.Unmarshal(data, &cm.config); err != nil {
 			return fmt.Errorf("解析配置文件失败: %w", err)
 		}
+		cm.recordFile(data)
 	}
 
+	cm.loadSecrets()
 	return nil
 }
 
-// Save 保存配置
+// loadSecrets 按配置中的引用从钥匙串或加密文件读取密钥和令牌
+func (cm *ConfigManager) loadSecrets() {
+	for _, secret := range secretFields {
+		cm.loadSecret(secret)
+	}
+}
+
+// loadSecret 按配置中的引用读取一个密钥
+func (cm *ConfigManager) loadSecret(secret secretField) {
+	value, ref := secret.field(&cm.config)
+	if *ref == "" {
+		return
+	}
+	stored, err := cm.secrets.Get(*ref)
+	if err != nil {
+		logger.Warnf("读取%s失败: %v", secret.label, err)
+		return
+	}
+	*value = stored
+	cm.stored[secret.name] = stored
+}
+
+// storeSecret 将密钥保存到钥匙串或加密文件并更新引用
+// 返回false表示无法安全保存，此时仍以明文写入配置文件，避免丢失密钥
+func (cm *ConfigManager) storeSecret(secret secretField) bool {
+	value, ref := secret.field(&cm.config)
+	// 未变化（包括启动时未能读取到密钥的情况）时保留原引用
+	if *value == cm.stored[secret.name] && *ref != "" {
+		return true
+	}
+
+	if *value == "" {
+		if *ref != "" {
+			if err := cm.secrets.Delete(*ref); err != nil {
+				logger.Warnf("删除%s失败: %v", secret.label, err)
+			}
+		}
+		*ref = ""
+		delete(cm.stored, secret.name)
+		return true
+	}
+
+	newRef, err := cm.secrets.Set(secret.name, *value)
+	if err != nil {
+		logger.Warnf("无法安全保存%s，将以明文保存在配置文件中: %v", secret.label, err)
+		*ref = ""
+		return false
+	}
+	*ref = newRef
+	cm.stored[secret.name] = *value
+	return true
+}
+
+// Save 保存配置，API密钥和集成令牌、密码保存在钥匙串或加密文件中，配置文件只保存引用
 func (cm *ConfigManager) Save() error {
-	cm.mu.RLock()
-	defer cm.mu.RUnlock()
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
 
-	data, err := json.MarshalIndent(cm.config, "", "  ")
+	var secured []secretField
+	for _, secret := range secretFields {
+		if cm.storeSecret(secret) {
+			secured = append(secured, secret)
+		}
+	}
+	fileConfig := cm.config
+	for _, secret := range secured {
+		value, _ := secret.field(&fileConfig)
+		*value = ""
+	}
+
+	data, err := json.MarshalIndent(fileConfig, "", "  ")
 	if err != nil {
 		return fmt.Errorf("序列化配置失败: %w", err)
 	}
@@ -133,6 +764,7 @@ func (cm *ConfigManager) Save() error {
 	if err := os.WriteFile(cm.configPath, data, 0600); err != nil {
 		return fmt.Errorf("保存配置文件失败: %w", err)
 	}
+	cm.recordFile(data)
 
 	return nil
 }
@@ -147,12 +779,27 @@ func (cm *ConfigManager) GetAIConfig() AIConfig {
 // UpdateAIConfig 更新AI配置
 func (cm *ConfigManager) UpdateAIConfig(config AIConfig) error {
 	cm.mu.Lock()
+	config.APIKeyRef = cm.config.AI.APIKeyRef
 	cm.config.AI = config
 	cm.mu.Unlock()
 
 	return cm.Save()
 }
 
+// SetModelCapability 保存模型能力检测结果
+func (cm *ConfigManager) SetModelCapability(model string, capability ModelCapability) error {
+	cm.mu.Lock()
+	capabilities := make(map[string]ModelCapability, len(cm.config.AI.ModelCapabilities)+1)
+	for name, c := range cm.config.AI.ModelCapabilities {
+		capabilities[name] = c
+	}
+	capabilities[model] = capability
+	cm.config.AI.ModelCapabilities = capabilities
+	cm.mu.Unlock()
+
+	return cm.Save()
+}
+
 // GetConfig 获取完整配置
 func (cm *ConfigManager) GetConfig() AppConfig {
 	cm.mu.RLock()
@@ -163,8 +810,72 @@ func (cm *ConfigManager) GetConfig() AppConfig {
 // UpdateConfig 更新完整配置
 func (cm *ConfigManager) UpdateConfig(config AppConfig) error {
 	cm.mu.Lock()
+	keepSecretRefs(&config, &cm.config)
 	cm.config = config
 	cm.mu.Unlock()
 
 	return cm.Save()
 }
+
+// RetentionNever 保留时长设为永久保留
+const RetentionNever = "never"
+
+// ParseRetentionDays 解析保留时长（如 "30d"、"24h"、"2w"、"1y"）为天数，不足一天按一天计算
+// never 表示永久保留，返回0
+func ParseRetentionDays(value string) (int, error) {
+	value = strings.TrimSpace(strings.ToLower(value))
+	if value == RetentionNever {
+		return 0, nil
+	}
+	if len(value) < 2 {
+		return 0, fmt.Errorf("无效的保留时长: %q", value)
+	}
+
+	n, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("无效的保留时长: %q", value)
+	}
+
+	switch value[len(value)-1] {
+	case 'h':
+		return (n + 23) / 24, nil
+	case 'd':
+		return n, nil
+	case 'w':
+		return n * 7, nil
+	case 'y':
+		return n * 365, nil
+	default:
+		return 0, fmt.Errorf("无效的保留时长单位: %q", value)
+	}
+}
+
+// ParseByteSize 解析容量（如 "500MB"、"2GB"、"1024"）为字节数
+func ParseByteSize(value string) (int64, error) {
+	value = strings.TrimSpace(strings.ToUpper(value))
+	units := []struct {
+		suffix string
+		size   int64
+	}{
+		{"TB", 1 << 40},
+		{"GB", 1 << 30},
+		{"MB", 1 << 20},
+		{"KB", 1 << 10},
+		{"B", 1},
+	}
+
+	multiplier := int64(1)
+	for _, unit := range units {
+		if strings.HasSuffix(value, unit.suffix) {
+			multiplier = unit.size
+			value = strings.TrimSpace(strings.TrimSuffix(value, unit.suffix))
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("无效的容量: %q", value)
+	}
+	return int64(n * float64(multiplier)), nil
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// fileState 配置文件的状态
+type fileState struct {
+	modTime time.Time
+	size    int64
+	hash    [sha256.Size]byte
+}
+
+// recordFile 记录应用读取或写入的配置文件内容，调用方需持有写锁
+func (cm *ConfigManager) recordFile(data []byte) {
+	cm.file.hash = sha256.Sum256(data)
+	cm.file.size = int64(len(data))
+	if info, err := os.Stat(cm.configPath); err == nil {
+		cm.file.modTime = info.ModTime()
+		cm.file.size = info.Size()
+	}
+}
+
+// ReloadIfChanged 配置文件被外部编辑后重新加载，返回是否加载了新配置
+// validate 不为nil时先检查新配置，解析或检查失败时保留当前配置
+// 配置文件中直接填写的明文API密钥会迁移到钥匙串或加密文件
+func (cm *ConfigManager) ReloadIfChanged(validate func(AppConfig) error) (bool, error) {
+	info, err := os.Stat(cm.configPath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("读取配置文件失败: %w", err)
+	}
+
+	cm.mu.RLock()
+	unchanged := info.ModTime().Equal(cm.file.modTime) && info.Size() == cm.file.size
+	cm.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(cm.configPath)
+	if err != nil {
+		return false, fmt.Errorf("读取配置文件失败: %w", err)
+	}
+
+	// 只有内容变化才重新加载（如编辑器保存了相同内容）；无效内容也只报告一次
+	hash := sha256.Sum256(data)
+	cm.mu.Lock()
+	cm.file = fileState{modTime: info.ModTime(), size: info.Size(), hash: cm.file.hash}
+	if hash == cm.file.hash {
+		cm.mu.Unlock()
+		return false, nil
+	}
+	cm.file.hash = hash
+	cm.mu.Unlock()
+
+	cfg := DefaultConfig()
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return false, fmt.Errorf("解析配置文件失败: %w", err)
+	}
+	if validate != nil {
+		if err := validate(cfg); err != nil {
+			return false, err
+		}
+	}
+
+	cm.mu.Lock()
+	previous := cm.config
+	cm.config = cfg
+	plaintext := false
+	for _, secret := range secretFields {
+		value, ref := secret.field(&cm.config)
+		if *value != "" {
+			plaintext = true
+			continue
+		}
+		previousValue, previousRef := secret.field(&previous)
+		if *ref == *previousRef {
+			*value = *previousValue
+		} else {
+			cm.loadSecret(secret)
+		}
+	}
+	cm.mu.Unlock()
+
+	// 手动写入配置文件的明文密钥移到钥匙串或加密文件
+	if plaintext {
+		if err := cm.Save(); err != nil {
+			return true, fmt.Errorf("保存密钥失败: %w", err)
+		}
+	}
+	return true, nil
+}
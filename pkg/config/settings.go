@@ -0,0 +1,202 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// settingsFormat 导出文件的格式标识
+const settingsFormat = "pdfseer-settings"
+
+// settingsVersion 导出文件的格式版本
+const settingsVersion = 1
+
+// 可单独恢复默认值的配置分区
+const (
+	SectionAI            = "ai"
+	SectionStorage       = "storage"
+	SectionRender        = "render"
+	SectionUI            = "ui"
+	SectionWatchFolder   = "watch_folder"
+	SectionAPIServer     = "api_server"
+	SectionSchedules     = "schedules"
+	SectionWebhook       = "webhook"
+	SectionNotifications = "notifications"
+	SectionLogging       = "logging"
+	SectionProfiles      = "profiles"
+	SectionExtraction    = "extraction"
+	SectionGlossary      = "glossary"
+	SectionTextCleanup   = "text_cleanup"
+	SectionChinese       = "chinese_conversion"
+	SectionFootnotes     = "footnotes"
+	SectionTTS           = "tts"
+	SectionAnnotations   = "annotations"
+	SectionIntegrations  = "integrations"
+	SectionRemoteUpload  = "remote_upload"
+)
+
+// Sections 所有配置分区
+var Sections = []string{
+	SectionAI, SectionStorage, SectionRender, SectionUI, SectionWatchFolder, SectionAPIServer,
+	SectionSchedules, SectionWebhook, SectionNotifications, SectionLogging, SectionProfiles,
+	SectionExtraction, SectionGlossary, SectionTextCleanup, SectionFootnotes, SectionTTS,
+	SectionAnnotations, SectionChinese, SectionIntegrations, SectionRemoteUpload,
+}
+
+// settingsFile 导出的配置文件
+type settingsFile struct {
+	Format         string     `json:"format"`
+	Version        int        `json:"version"`
+	ExportedAt     time.Time  `json:"exported_at"`
+	IncludeAPIKeys bool       `json:"include_api_keys"`
+	Config         *AppConfig `json:"config"`
+}
+
+// ExportSettings 将配置导出到文件，用于迁移到其他电脑
+// includeAPIKeys 为 false 时不导出API密钥、API服务令牌、Webhook密钥、Notion和Paperless令牌以及远程存储的密码
+func (cm *ConfigManager) ExportSettings(path string, includeAPIKeys bool) error {
+	cfg := cm.GetConfig()
+	// 钥匙串引用只在本机有效
+	clearSecretRefs(&cfg)
+	if !includeAPIKeys {
+		cfg.AI.APIKey = ""
+		cfg.APIServer.Token = ""
+		cfg.Webhook.Secret = ""
+		cfg.Integrations.Notion.Token = ""
+		cfg.Integrations.Paperless.Token = ""
+		cfg.RemoteUpload.Password = ""
+		cfg.RemoteUpload.SecretKey = ""
+	}
+
+	data, err := json.MarshalIndent(settingsFile{
+		Format:         settingsFormat,
+		Version:        settingsVersion,
+		ExportedAt:     time.Now(),
+		IncludeAPIKeys: includeAPIKeys,
+		Config:         &cfg,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化配置失败: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("保存配置文件失败: %w", err)
+	}
+	return nil
+}
+
+// ReadSettings 读取导出的配置文件（也支持直接读取 config.json），返回与当前配置合并后的完整配置
+// 文件中缺少的项使用默认值，未导出的密钥保留当前值
+func (cm *ConfigManager) ReadSettings(path string) (AppConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return AppConfig{}, fmt.Errorf("读取配置文件失败: %w", err)
+	}
+
+	var header struct {
+		Format  string `json:"format"`
+		Version int    `json:"version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return AppConfig{}, fmt.Errorf("解析配置文件失败: %w", err)
+	}
+
+	imported := DefaultConfig()
+	if header.Format == settingsFormat {
+		if header.Version > settingsVersion {
+			return AppConfig{}, fmt.Errorf("配置文件版本 %d 高于当前支持的版本 %d，请升级应用", header.Version, settingsVersion)
+		}
+		err = json.Unmarshal(data, &settingsFile{Config: &imported})
+	} else {
+		err = json.Unmarshal(data, &imported)
+	}
+	if err != nil {
+		return AppConfig{}, fmt.Errorf("解析配置文件失败: %w", err)
+	}
+
+	current := cm.GetConfig()
+	clearSecretRefs(&imported)
+	if imported.AI.APIKey == "" {
+		imported.AI.APIKey = current.AI.APIKey
+	}
+	if imported.APIServer.Token == "" {
+		imported.APIServer.Token = current.APIServer.Token
+	}
+	if imported.Webhook.Secret == "" {
+		imported.Webhook.Secret = current.Webhook.Secret
+	}
+	if imported.Integrations.Notion.Token == "" {
+		imported.Integrations.Notion.Token = current.Integrations.Notion.Token
+	}
+	if imported.Integrations.Paperless.Token == "" {
+		imported.Integrations.Paperless.Token = current.Integrations.Paperless.Token
+	}
+	if imported.RemoteUpload.Password == "" {
+		imported.RemoteUpload.Password = current.RemoteUpload.Password
+	}
+	if imported.RemoteUpload.SecretKey == "" {
+		imported.RemoteUpload.SecretKey = current.RemoteUpload.SecretKey
+	}
+	return imported, nil
+}
+
+// ResetSection 返回将指定分区恢复为默认值后的配置，section 为空时恢复全部配置
+// API密钥不属于可恢复的设置，始终保留
+func ResetSection(cfg AppConfig, section string) (AppConfig, error) {
+	defaults := DefaultConfig()
+	apiKey := cfg.AI.APIKey
+
+	switch section {
+	case "":
+		cfg = defaults
+	case SectionAI:
+		cfg.AI = defaults.AI
+	case SectionStorage:
+		cfg.Storage = defaults.Storage
+	case SectionUI:
+		cfg.UI = defaults.UI
+	case SectionRender:
+		cfg.Render = defaults.Render
+	case SectionWatchFolder:
+		cfg.WatchFolder = defaults.WatchFolder
+	case SectionAPIServer:
+		cfg.APIServer = defaults.APIServer
+	case SectionSchedules:
+		cfg.Schedules = defaults.Schedules
+	case SectionWebhook:
+		cfg.Webhook = defaults.Webhook
+	case SectionNotifications:
+		cfg.Notifications = defaults.Notifications
+	case SectionLogging:
+		cfg.Logging = defaults.Logging
+	case SectionProfiles:
+		cfg.Profiles = defaults.Profiles
+		cfg.DefaultProfile = defaults.DefaultProfile
+		cfg.WatchFolder.Profile = defaults.WatchFolder.Profile
+	case SectionExtraction:
+		cfg.ExtractionSchemas = defaults.ExtractionSchemas
+	case SectionGlossary:
+		cfg.Glossary = defaults.Glossary
+	case SectionTextCleanup:
+		cfg.TextCleanup = defaults.TextCleanup
+	case SectionChinese:
+		cfg.ChineseConversion = defaults.ChineseConversion
+	case SectionFootnotes:
+		cfg.Footnotes = defaults.Footnotes
+	case SectionTTS:
+		cfg.TTS = defaults.TTS
+	case SectionAnnotations:
+		cfg.Annotations = defaults.Annotations
+	case SectionIntegrations:
+		cfg.Integrations = defaults.Integrations
+	case SectionRemoteUpload:
+		cfg.RemoteUpload = defaults.RemoteUpload
+	default:
+		return cfg, fmt.Errorf("未知的配置分区: %s", section)
+	}
+
+	cfg.AI.APIKey = apiKey
+	return cfg, nil
+}
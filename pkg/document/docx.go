@@ -0,0 +1,315 @@
+package document
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// docxBlock 转换后的一个Markdown块，相邻的列表项之间不空行
+type docxBlock struct {
+	text string
+	list bool
+}
+
+// docxStyles word/styles.xml 中的段落样式
+type docxStyles struct {
+	Styles []struct {
+		ID   string `xml:"styleId,attr"`
+		Name struct {
+			Val string `xml:"val,attr"`
+		} `xml:"name"`
+		OutlineLevel *struct {
+			Val string `xml:"val,attr"`
+		} `xml:"pPr>outlineLvl"`
+	} `xml:"style"`
+}
+
+// DocxToMarkdown 将DOCX文档转换为Markdown：标题样式转为 #，编号段落转为列表，粗体和斜体保留，表格转为Markdown表格
+// 图片、批注、页眉页脚等不转换；不支持旧版 .doc 格式
+func DocxToMarkdown(filePath string) (string, error) {
+	reader, err := zip.OpenReader(filePath)
+	if err != nil {
+		return "", fmt.Errorf("打开DOCX文件失败: %w", err)
+	}
+	defer reader.Close()
+
+	var documentFile, stylesFile *zip.File
+	for _, file := range reader.File {
+		switch file.Name {
+		case "word/document.xml":
+			documentFile = file
+		case "word/styles.xml":
+			stylesFile = file
+		}
+	}
+	if documentFile == nil {
+		return "", fmt.Errorf("不是有效的DOCX文件: 缺少 word/document.xml")
+	}
+
+	headings := map[string]int{}
+	if stylesFile != nil {
+		if headings, err = readDocxHeadings(stylesFile); err != nil {
+			return "", err
+		}
+	}
+
+	rc, err := documentFile.Open()
+	if err != nil {
+		return "", fmt.Errorf("读取DOCX内容失败: %w", err)
+	}
+	defer rc.Close()
+
+	blocks, err := parseDocxBody(rc, headings)
+	if err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	for i, block := range blocks {
+		if i > 0 {
+			if block.list && blocks[i-1].list {
+				builder.WriteString("\n")
+			} else {
+				builder.WriteString("\n\n")
+			}
+		}
+		builder.WriteString(block.text)
+	}
+	if builder.Len() > 0 {
+		builder.WriteString("\n")
+	}
+	return builder.String(), nil
+}
+
+// readDocxHeadings 读取标题样式ID对应的标题级别：样式名为 heading N 或 Title，或设置了大纲级别
+func readDocxHeadings(file *zip.File) (map[string]int, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("读取DOCX样式失败: %w", err)
+	}
+	defer rc.Close()
+
+	var styles docxStyles
+	if err := xml.NewDecoder(rc).Decode(&styles); err != nil {
+		return nil, fmt.Errorf("解析DOCX样式失败: %w", err)
+	}
+
+	headings := make(map[string]int)
+	for _, style := range styles.Styles {
+		name := strings.ToLower(style.Name.Val)
+		switch {
+		case name == "title":
+			headings[style.ID] = 1
+		case strings.HasPrefix(name, "heading "):
+			if level, err := strconv.Atoi(strings.TrimPrefix(name, "heading ")); err == nil {
+				headings[style.ID] = level
+			}
+		case style.OutlineLevel != nil:
+			if level, err := strconv.Atoi(style.OutlineLevel.Val); err == nil && level < 9 {
+				headings[style.ID] = level + 1
+			}
+		}
+	}
+	for id, level := range headings {
+		headings[id] = min(max(level, 1), 6)
+	}
+	return headings, nil
+}
+
+// parseDocxBody 逐个读取 word/document.xml 中的元素，转换为Markdown块
+// 嵌套表格的内容合并到外层单元格中
+func parseDocxBody(r io.Reader, headings map[string]int) ([]docxBlock, error) {
+	decoder := xml.NewDecoder(r)
+
+	var (
+		blocks     []docxBlock
+		paragraph  strings.Builder
+		run        strings.Builder
+		style      string
+		listLevel  = -1
+		bold       bool
+		italic     bool
+		inText     bool
+		inRunProps bool
+		tableDepth int
+		rows       [][]string
+		row        []string
+		cell       []string
+	)
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("解析DOCX内容失败: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "p":
+				paragraph.Reset()
+				style, listLevel = "", -1
+			case "pStyle":
+				style = docxAttr(t, "val")
+			case "numPr":
+				listLevel = 0
+			case "ilvl":
+				if level, err := strconv.Atoi(docxAttr(t, "val")); err == nil {
+					listLevel = level
+				}
+			case "r":
+				run.Reset()
+				bold, italic = false, false
+			case "rPr":
+				inRunProps = true
+			case "b":
+				bold = inRunProps && docxToggle(t)
+			case "i":
+				italic = inRunProps && docxToggle(t)
+			case "t":
+				inText = true
+			case "tab":
+				run.WriteString("\t")
+			case "br", "cr":
+				if tableDepth > 0 {
+					run.WriteString(" ")
+				} else {
+					run.WriteString("  \n")
+				}
+			case "tbl":
+				tableDepth++
+				if tableDepth == 1 {
+					rows = nil
+				}
+			case "tr":
+				if tableDepth == 1 {
+					row = nil
+				}
+			case "tc":
+				if tableDepth == 1 {
+					cell = nil
+				}
+			}
+		case xml.CharData:
+			if inText {
+				run.Write(t)
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "rPr":
+				inRunProps = false
+			case "r":
+				paragraph.WriteString(formatDocxRun(run.String(), bold, italic))
+			case "p":
+				text := strings.TrimSpace(paragraph.String())
+				if tableDepth > 0 {
+					if text != "" {
+						cell = append(cell, text)
+					}
+					continue
+				}
+				if text == "" {
+					continue
+				}
+				switch level, ok := headings[style]; {
+				case ok:
+					blocks = append(blocks, docxBlock{text: strings.Repeat("#", level) + " " + text})
+				case listLevel >= 0:
+					blocks = append(blocks, docxBlock{text: strings.Repeat("  ", listLevel) + "- " + text, list: true})
+				default:
+					blocks = append(blocks, docxBlock{text: text})
+				}
+			case "tc":
+				if tableDepth == 1 {
+					row = append(row, strings.ReplaceAll(strings.Join(cell, "<br>"), "|", "\\|"))
+				}
+			case "tr":
+				if tableDepth == 1 {
+					rows = append(rows, row)
+				}
+			case "tbl":
+				tableDepth--
+				if tableDepth == 0 && len(rows) > 0 {
+					blocks = append(blocks, docxBlock{text: formatDocxTable(rows)})
+				}
+			}
+		}
+	}
+	return blocks, nil
+}
+
+// formatDocxRun 按粗体和斜体格式包裹文本，首尾空白放在标记外
+func formatDocxRun(text string, bold, italic bool) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" || (!bold && !italic) {
+		return text
+	}
+	marker := "*"
+	if bold && italic {
+		marker = "***"
+	} else if bold {
+		marker = "**"
+	}
+	start := strings.Index(text, trimmed)
+	return text[:start] + marker + trimmed + marker + text[start+len(trimmed):]
+}
+
+// formatDocxTable 将表格转为Markdown表格，第一行作为表头，列数按最多的一行补齐
+func formatDocxTable(rows [][]string) string {
+	columns := 0
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+	if columns == 0 {
+		return ""
+	}
+
+	var builder strings.Builder
+	writeRow := func(cells []string) {
+		builder.WriteString("|")
+		for i := 0; i < columns; i++ {
+			value := ""
+			if i < len(cells) {
+				value = cells[i]
+			}
+			builder.WriteString(" " + value + " |")
+		}
+	}
+
+	writeRow(rows[0])
+	builder.WriteString("\n|")
+	builder.WriteString(strings.Repeat(" --- |", columns))
+	for _, row := range rows[1:] {
+		builder.WriteString("\n")
+		writeRow(row)
+	}
+	return builder.String()
+}
+
+// docxAttr 按本地名读取元素属性，忽略命名空间前缀
+func docxAttr(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// docxToggle 读取 w:b、w:i 等开关属性，没有 val 属性时表示开启
+func docxToggle(element xml.StartElement) bool {
+	switch docxAttr(element, "val") {
+	case "0", "false", "off":
+		return false
+	}
+	return true
+}
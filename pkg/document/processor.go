@@ -2,9 +2,13 @@ package document
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/h2non/bimg"
+	"pdf-ocr-ai/pkg/email"
+	imageprocessor "pdf-ocr-ai/pkg/image"
 	"pdf-ocr-ai/pkg/pdf"
 )
 
@@ -16,11 +20,18 @@ const (
 	TypeImage DocumentType = "image"
 	TypeWord  DocumentType = "word"
 	TypeText  DocumentType = "text"
+	TypeDjVu  DocumentType = "djvu"
+	TypeEmail DocumentType = "email"
 )
 
+// emailPageRunes 邮件正文每页的最大字符数
+const emailPageRunes = 3000
+
 // SupportedFormats 支持的文件格式
 var SupportedFormats = map[string]DocumentType{
 	".pdf":  TypePDF,
+	".djvu": TypeDjVu,
+	".djv":  TypeDjVu,
 	".jpg":  TypeImage,
 	".jpeg": TypeImage,
 	".png":  TypeImage,
@@ -29,11 +40,15 @@ var SupportedFormats = map[string]DocumentType{
 	".tif":  TypeImage,
 	".gif":  TypeImage,
 	".webp": TypeImage,
+	".heic": TypeImage,
+	".heif": TypeImage,
 	".doc":  TypeWord,
 	".docx": TypeWord,
 	".txt":  TypeText,
 	".md":   TypeText,
 	".rtf":  TypeText,
+	".eml":  TypeEmail,
+	".msg":  TypeEmail,
 }
 
 // DocumentInfo 文档信息
@@ -49,7 +64,8 @@ type DocumentInfo struct {
 
 // DocumentProcessor 文档处理器
 type DocumentProcessor struct {
-	pdfProcessor *pdf.PDFProcessor
+	pdfProcessor   *pdf.PDFProcessor
+	imageProcessor *imageprocessor.ImageProcessor
 }
 
 // NewDocumentProcessor 创建文档处理器
@@ -60,7 +76,8 @@ func NewDocumentProcessor() (*DocumentProcessor, error) {
 	}
 
 	return &DocumentProcessor{
-		pdfProcessor: pdfProcessor,
+		pdfProcessor:   pdfProcessor,
+		imageProcessor: imageprocessor.NewImageProcessor(imageprocessor.DefaultConfig()),
 	}, nil
 }
 
@@ -96,12 +113,16 @@ func (dp *DocumentProcessor) GetDocumentInfo(filePath string) (*DocumentInfo, er
 	switch docType {
 	case TypePDF:
 		return dp.getPDFInfo(filePath, info)
+	case TypeDjVu:
+		return dp.getDjVuInfo(filePath, info)
 	case TypeImage:
 		return dp.getImageInfo(filePath, info)
 	case TypeWord:
 		return dp.getWordInfo(filePath, info)
 	case TypeText:
 		return dp.getTextInfo(filePath, info)
+	case TypeEmail:
+		return dp.getEmailInfo(filePath, info)
 	default:
 		return info, nil
 	}
@@ -110,9 +131,9 @@ func (dp *DocumentProcessor) GetDocumentInfo(filePath string) (*DocumentInfo, er
 // supportsOCR 检查文档类型是否支持OCR
 func (dp *DocumentProcessor) supportsOCR(docType DocumentType) bool {
 	switch docType {
-	case TypePDF, TypeImage:
+	case TypePDF, TypeImage, TypeDjVu:
 		return true
-	case TypeWord, TypeText:
+	case TypeWord, TypeText, TypeEmail:
 		return false // 这些格式已经包含文本，不需要OCR
 	default:
 		return false
@@ -134,8 +155,25 @@ func (dp *DocumentProcessor) getPDFInfo(filePath string, info *DocumentInfo) (*D
 	return info, nil
 }
 
+// getDjVuInfo 获取DjVu文档信息
+func (dp *DocumentProcessor) getDjVuInfo(filePath string, info *DocumentInfo) (*DocumentInfo, error) {
+	doc, err := dp.pdfProcessor.LoadDjVu(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("加载DjVu失败: %w", err)
+	}
+
+	info.PageCount = doc.PageCount
+	info.Title = doc.Title
+
+	return info, nil
+}
+
 // getImageInfo 获取图片文档信息
 func (dp *DocumentProcessor) getImageInfo(filePath string, info *DocumentInfo) (*DocumentInfo, error) {
+	if _, err := dp.imageProcessor.GetImageInfo(filePath); err != nil && !dp.isVipsDecodable(filePath) {
+		return nil, fmt.Errorf("无法读取图片: %w", err)
+	}
+
 	// 图片文件只有一页
 	info.PageCount = 1
 	info.Title = filepath.Base(filePath)
@@ -173,37 +211,33 @@ func (dp *DocumentProcessor) LoadDocument(filePath string) (*pdf.PDFDocument, er
 	switch docType {
 	case TypePDF:
 		return dp.pdfProcessor.LoadPDF(filePath)
+	case TypeDjVu:
+		return dp.pdfProcessor.LoadDjVu(filePath)
 	case TypeImage:
 		return dp.loadImageAsDocument(filePath)
 	case TypeWord:
 		return dp.loadWordAsDocument(filePath)
 	case TypeText:
 		return dp.loadTextAsDocument(filePath)
+	case TypeEmail:
+		return dp.loadEmailAsDocument(filePath)
 	default:
 		return nil, fmt.Errorf("不支持的文档类型: %s", docType)
 	}
 }
 
-// loadImageAsDocument 将图片加载为文档
+// loadImageAsDocument 将图片加载为单页文档，渲染时再规范化格式和尺寸
 func (dp *DocumentProcessor) loadImageAsDocument(filePath string) (*pdf.PDFDocument, error) {
-	// 创建一个虚拟的PDF文档结构来表示图片
-	doc := &pdf.PDFDocument{
-		FilePath:  filePath,
-		PageCount: 1,
-		Title:     filepath.Base(filePath),
-		Pages: []*pdf.PDFPage{
-			{
-				Number:    1,
-				Text:      "", // 图片没有原生文本
-				HasText:   false,
-				Width:     0, // 需要从图片获取实际尺寸
-				Height:    0,
-				ImagePath: filePath, // 直接使用原图片路径
-			},
-		},
-	}
+	return dp.pdfProcessor.LoadImage(filePath)
+}
 
-	return doc, nil
+// isVipsDecodable 检查libvips是否能识别图片格式
+func (dp *DocumentProcessor) isVipsDecodable(filePath string) bool {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return false
+	}
+	return bimg.DetermineImageTypeName(data) != "unknown"
 }
 
 // loadWordAsDocument 将Word文档加载为文档
@@ -248,6 +282,50 @@ func (dp *DocumentProcessor) loadTextAsDocument(filePath string) (*pdf.PDFDocume
 	return doc, nil
 }
 
+// getEmailInfo 获取邮件信息
+func (dp *DocumentProcessor) getEmailInfo(filePath string, info *DocumentInfo) (*DocumentInfo, error) {
+	msg, err := email.ParseFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	info.PageCount = len(msg.Pages(emailPageRunes))
+	info.Title = msg.Subject
+	info.Author = msg.From
+	info.SupportedOCR = false // 邮件正文已包含文本，附件作为单独的文档处理
+
+	return info, nil
+}
+
+// loadEmailAsDocument 将邮件正文加载为原生文本页面，附件不包含在文档中
+func (dp *DocumentProcessor) loadEmailAsDocument(filePath string) (*pdf.PDFDocument, error) {
+	msg, err := email.ParseFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	title := msg.Subject
+	if title == "" {
+		title = filepath.Base(filePath)
+	}
+	doc := &pdf.PDFDocument{
+		FilePath: filePath,
+		Title:    title,
+		Author:   msg.From,
+	}
+	for i, text := range msg.Pages(emailPageRunes) {
+		doc.Pages = append(doc.Pages, &pdf.PDFPage{
+			Number:  i + 1,
+			Text:    text,
+			HasText: true,
+			Width:   595,
+			Height:  842,
+		})
+	}
+	doc.PageCount = len(doc.Pages)
+
+	return doc, nil
+}
+
 // GetSupportedFormats 获取支持的格式列表
 func (dp *DocumentProcessor) GetSupportedFormats() []string {
 	formats := make([]string, 0, len(SupportedFormats))
@@ -263,6 +341,8 @@ func (dp *DocumentProcessor) GetFormatDescription(ext string) string {
 	switch ext {
 	case ".pdf":
 		return "PDF文档"
+	case ".djvu", ".djv":
+		return "DjVu文档"
 	case ".jpg", ".jpeg":
 		return "JPEG图片"
 	case ".png":
@@ -275,6 +355,8 @@ func (dp *DocumentProcessor) GetFormatDescription(ext string) string {
 		return "GIF图片"
 	case ".webp":
 		return "WebP图片"
+	case ".heic", ".heif":
+		return "HEIC图片"
 	case ".doc":
 		return "Word文档 (旧版)"
 	case ".docx":
@@ -285,6 +367,10 @@ func (dp *DocumentProcessor) GetFormatDescription(ext string) string {
 		return "Markdown文件"
 	case ".rtf":
 		return "富文本格式"
+	case ".eml":
+		return "电子邮件"
+	case ".msg":
+		return "Outlook邮件"
 	default:
 		return "未知格式"
 	}
@@ -0,0 +1,271 @@
+// Package email 解析 .eml 邮件（RFC 5322 / MIME）和 Outlook .msg 邮件，提取正文和附件
+package email
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// Attachment 邮件附件
+type Attachment struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"-"`
+}
+
+// Message 解析后的邮件
+type Message struct {
+	Subject     string       `json:"subject"`
+	From        string       `json:"from"`
+	To          string       `json:"to"`
+	Cc          string       `json:"cc,omitempty"`
+	Date        string       `json:"date"`
+	Body        string       `json:"body"` // 纯文本正文，只有HTML正文时转换为文本
+	Attachments []Attachment `json:"attachments"`
+}
+
+var (
+	headerDecoder = &mime.WordDecoder{CharsetReader: charsetReader}
+
+	htmlBlockPattern = regexp.MustCompile(`(?i)<(br|/p|/div|/tr|/h[1-6]|/li)[^>]*>`)
+	htmlDropPattern  = regexp.MustCompile(`(?is)<(script|style|head)[^>]*>.*?</(script|style|head)>`)
+	htmlTagPattern   = regexp.MustCompile(`<[^>]+>`)
+	blankLinePattern = regexp.MustCompile(`\n{3,}`)
+)
+
+// ParseFile 解析 .eml 或 .msg 文件
+func ParseFile(path string) (*Message, error) {
+	if strings.EqualFold(filepath.Ext(path), ".msg") {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("打开邮件失败: %w", err)
+		}
+		return ParseMSG(data)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开邮件失败: %w", err)
+	}
+	defer file.Close()
+	return Parse(file)
+}
+
+// Parse 解析邮件内容
+func Parse(r io.Reader) (*Message, error) {
+	raw, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, fmt.Errorf("解析邮件失败: %w", err)
+	}
+
+	msg := &Message{
+		Subject:     decodeHeader(raw.Header.Get("Subject")),
+		From:        decodeHeader(raw.Header.Get("From")),
+		To:          decodeHeader(raw.Header.Get("To")),
+		Cc:          decodeHeader(raw.Header.Get("Cc")),
+		Date:        raw.Header.Get("Date"),
+		Attachments: []Attachment{},
+	}
+	if date, err := raw.Header.Date(); err == nil {
+		msg.Date = date.Format("2006-01-02 15:04")
+	}
+
+	var plain, htmlBody string
+	err = walkPart(raw.Header, raw.Body, func(contentType, disposition, name string, data []byte) {
+		switch {
+		case name != "" || strings.HasPrefix(disposition, "attachment"):
+			if name == "" {
+				name = "attachment"
+			}
+			msg.Attachments = append(msg.Attachments, Attachment{Name: name, ContentType: contentType, Data: data})
+		case contentType == "text/plain" && plain == "":
+			plain = string(data)
+		case contentType == "text/html" && htmlBody == "":
+			htmlBody = string(data)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	msg.Body = plain
+	if strings.TrimSpace(msg.Body) == "" && htmlBody != "" {
+		msg.Body = htmlToText(htmlBody)
+	}
+	msg.Body = strings.TrimSpace(strings.ReplaceAll(msg.Body, "\r\n", "\n"))
+	return msg, nil
+}
+
+// Header 返回邮件头部摘要（主题、发件人、收件人和日期），用作正文第一页的开头
+func (m *Message) Header() string {
+	var builder strings.Builder
+	for _, field := range [][2]string{
+		{"Subject", m.Subject}, {"From", m.From}, {"To", m.To}, {"Cc", m.Cc}, {"Date", m.Date},
+	} {
+		if field[1] != "" {
+			builder.WriteString(field[0] + ": " + field[1] + "\n")
+		}
+	}
+	return builder.String()
+}
+
+// Pages 将头部摘要和正文按段落分页，每页不超过 maxRunes 个字符（单个段落过长时按字符截断）
+func (m *Message) Pages(maxRunes int) []string {
+	text := strings.TrimSpace(m.Header() + "\n" + m.Body)
+	var pages []string
+	var current []rune
+	for _, paragraph := range strings.SplitAfter(text, "\n\n") {
+		runes := []rune(paragraph)
+		if len(current) > 0 && len(current)+len(runes) > maxRunes {
+			pages = append(pages, strings.TrimSpace(string(current)))
+			current = nil
+		}
+		for len(runes) > maxRunes {
+			pages = append(pages, strings.TrimSpace(string(runes[:maxRunes])))
+			runes = runes[maxRunes:]
+		}
+		current = append(current, runes...)
+	}
+	if s := strings.TrimSpace(string(current)); s != "" || len(pages) == 0 {
+		pages = append(pages, s)
+	}
+	return pages
+}
+
+// partHeader 邮件或MIME部分的头部
+type partHeader interface {
+	Get(key string) string
+}
+
+// walkPart 递归遍历MIME部分，对每个非 multipart 部分解码后调用 visit
+func walkPart(header partHeader, body io.Reader, visit func(contentType, disposition, name string, data []byte)) error {
+	contentType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		contentType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(contentType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("解析邮件内容失败: %w", err)
+			}
+			if err := walkPart(part.Header, part, visit); err != nil {
+				return err
+			}
+		}
+	}
+
+	data, err := io.ReadAll(decodeTransfer(body, header.Get("Content-Transfer-Encoding")))
+	if err != nil {
+		return fmt.Errorf("解码邮件内容失败: %w", err)
+	}
+
+	disposition, dispParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	name := dispParams["filename"]
+	if name == "" {
+		name = params["name"]
+	}
+	name = filepath.Base(decodeHeader(name))
+	if name == "." || name == string(filepath.Separator) {
+		name = ""
+	}
+
+	if strings.HasPrefix(contentType, "text/") && name == "" {
+		data = decodeCharset(data, params["charset"])
+	}
+	visit(contentType, disposition, name, data)
+	return nil
+}
+
+// decodeTransfer 按 Content-Transfer-Encoding 解码
+func decodeTransfer(r io.Reader, encoding string) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, newlineStripper{r})
+	case "quoted-printable":
+		return quotedprintable.NewReader(r)
+	default:
+		return r
+	}
+}
+
+// newlineStripper 去掉base64内容中的换行
+type newlineStripper struct{ r io.Reader }
+
+func (n newlineStripper) Read(p []byte) (int, error) {
+	count, err := n.r.Read(p)
+	kept := 0
+	for _, b := range p[:count] {
+		if b != '\r' && b != '\n' && b != ' ' && b != '\t' {
+			p[kept] = b
+			kept++
+		}
+	}
+	return kept, err
+}
+
+// decodeCharset 将文本从邮件声明的字符集转换为UTF-8，不支持的字符集原样返回
+func decodeCharset(data []byte, charset string) []byte {
+	charset = strings.ToLower(strings.TrimSpace(charset))
+	if charset == "" || charset == "utf-8" || charset == "us-ascii" {
+		return data
+	}
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return data
+	}
+	decoded, err := enc.NewDecoder().Bytes(data)
+	if err != nil {
+		return data
+	}
+	return decoded
+}
+
+// charsetReader 供 mime.WordDecoder 解码非UTF-8的头部
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	data, err := io.ReadAll(input)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(decodeCharset(data, charset)), nil
+}
+
+// decodeHeader 解码 RFC 2047 编码的头部，失败时返回原值
+func decodeHeader(value string) string {
+	decoded, err := headerDecoder.DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
+
+// htmlToText 将HTML正文转换为纯文本
+func htmlToText(s string) string {
+	s = htmlDropPattern.ReplaceAllString(s, "")
+	s = htmlBlockPattern.ReplaceAllString(s, "\n")
+	s = htmlTagPattern.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return blankLinePattern.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+}
@@ -0,0 +1,451 @@
+package email
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// Outlook .msg 邮件是复合文档（CFB）格式，邮件属性保存在名为 __substg1.0_<属性ID><类型> 的流中，
+// 日期等定长属性保存在 __properties_version1.0 流中，每个附件是一个 __attach_version1.0_#<序号> 存储
+
+// cfbSignature 复合文档的文件头标识
+var cfbSignature = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+
+// 复合文档扇区表中的特殊值
+const (
+	cfbEndOfChain = 0xFFFFFFFE
+	cfbFreeSect   = 0xFFFFFFFF
+	cfbNoStream   = 0xFFFFFFFF
+)
+
+// 目录项类型
+const (
+	cfbStorage = 1
+	cfbStream  = 2
+	cfbRoot    = 5
+)
+
+// MAPI 属性类型
+const (
+	ptLong    = 0x0003
+	ptString8 = 0x001E
+	ptUnicode = 0x001F
+	ptSystime = 0x0040
+	ptBinary  = 0x0102
+)
+
+// 使用的 MAPI 属性ID
+const (
+	propSubject        = 0x0037
+	propClientSubmit   = 0x0039
+	propSenderName     = 0x0C1A
+	propSenderEmail    = 0x0C1F
+	propDisplayCc      = 0x0E03
+	propDisplayTo      = 0x0E04
+	propDeliveryTime   = 0x0E06
+	propBody           = 0x1000
+	propHTML           = 0x1013
+	propAttachData     = 0x3701
+	propAttachFilename = 0x3704
+	propAttachLongName = 0x3707
+	propAttachMime     = 0x370E
+	propAttachDisplay  = 0x3001
+	propInternetCPID   = 0x3FDE
+	propMessageCP      = 0x3FFD
+	propSenderSMTP     = 0x5D01
+)
+
+// codepageCharsets 常见代码页对应的字符集名称，用于解码8位字符串属性
+var codepageCharsets = map[int]string{
+	932: "shift_jis", 936: "gbk", 949: "euc-kr", 950: "big5", 20936: "gb2312", 54936: "gb18030",
+	28591: "iso-8859-1", 65001: "utf-8",
+}
+
+// cfbEntry 复合文档的目录项
+type cfbEntry struct {
+	name        string
+	kind        byte
+	left, right uint32
+	child       uint32
+	start       uint32
+	size        uint64
+}
+
+// cfbFile 只读的复合文档
+type cfbFile struct {
+	data       []byte
+	sectorSize int
+	miniSize   int
+	miniCutoff uint64
+	fat        []uint32
+	miniFAT    []uint32
+	entries    []cfbEntry
+	miniStream []byte
+}
+
+// msgStorage 邮件或附件的属性
+type msgStorage struct {
+	cfb     *cfbFile
+	entries map[string]*cfbEntry // 子目录项，按名称（大写）索引
+	fixed   map[uint32][]byte    // 定长属性的8字节值，按属性标记（ID和类型）索引
+	charset string               // 8位字符串属性的字符集
+}
+
+// ParseMSG 解析 Outlook .msg 邮件，只有RTF正文的邮件正文为空
+func ParseMSG(data []byte) (*Message, error) {
+	cfb, err := openCFB(data)
+	if err != nil {
+		return nil, fmt.Errorf("解析Outlook邮件失败: %w", err)
+	}
+	root, err := cfb.storage(&cfb.entries[0], true)
+	if err != nil {
+		return nil, fmt.Errorf("解析Outlook邮件失败: %w", err)
+	}
+	for _, id := range []uint16{propInternetCPID, propMessageCP} {
+		if cp, ok := root.integer(id); ok && root.charset == "" {
+			root.charset = codepageName(cp)
+		}
+	}
+
+	msg := &Message{
+		Subject:     root.text(propSubject),
+		From:        root.text(propSenderName),
+		To:          root.text(propDisplayTo),
+		Cc:          root.text(propDisplayCc),
+		Attachments: []Attachment{},
+	}
+	address := root.text(propSenderSMTP)
+	if !strings.Contains(address, "@") {
+		address = root.text(propSenderEmail)
+	}
+	switch {
+	case msg.From == "":
+		msg.From = address
+	case strings.Contains(address, "@") && address != msg.From:
+		msg.From += " <" + address + ">"
+	}
+	for _, id := range []uint16{propClientSubmit, propDeliveryTime} {
+		if date, ok := root.systime(id); ok {
+			msg.Date = date.Local().Format("2006-01-02 15:04")
+			break
+		}
+	}
+
+	msg.Body = root.text(propBody)
+	if strings.TrimSpace(msg.Body) == "" {
+		if htmlBody := root.text(propHTML); htmlBody != "" {
+			msg.Body = htmlToText(htmlBody)
+		}
+	}
+	msg.Body = strings.TrimSpace(strings.ReplaceAll(msg.Body, "\r\n", "\n"))
+
+	// 附件按序号排序，解压的文件名带序号
+	var attachNames []string
+	for name, entry := range root.entries {
+		if entry.kind == cfbStorage && strings.HasPrefix(name, "__ATTACH_VERSION1.0_#") {
+			attachNames = append(attachNames, name)
+		}
+	}
+	sort.Strings(attachNames)
+	for _, storageName := range attachNames {
+		attach, err := cfb.storage(root.entries[storageName], false)
+		if err != nil {
+			continue
+		}
+		attach.charset = root.charset
+		// 嵌入的邮件等对象附件没有数据流，跳过
+		data := attach.binaryValue(propAttachData)
+		if data == nil {
+			continue
+		}
+		name := attach.text(propAttachLongName)
+		for _, id := range []uint16{propAttachFilename, propAttachDisplay} {
+			if name == "" {
+				name = attach.text(id)
+			}
+		}
+		name = strings.TrimSpace(strings.NewReplacer("/", "_", "\\", "_").Replace(name))
+		if name == "" || name == "." || name == ".." {
+			name = "attachment"
+		}
+		msg.Attachments = append(msg.Attachments, Attachment{Name: name, ContentType: attach.text(propAttachMime), Data: data})
+	}
+	return msg, nil
+}
+
+// codepageName 代码页对应的字符集名称，未知时返回空
+func codepageName(cp int) string {
+	if name, ok := codepageCharsets[cp]; ok {
+		return name
+	}
+	if cp >= 1250 && cp <= 1258 {
+		return "windows-" + strconv.Itoa(cp)
+	}
+	return ""
+}
+
+// openCFB 解析复合文档的头部、扇区表和目录
+func openCFB(data []byte) (*cfbFile, error) {
+	if len(data) < 512 || !bytes.Equal(data[:8], cfbSignature) {
+		return nil, errors.New("不是有效的复合文档")
+	}
+	le := binary.LittleEndian
+	shift := le.Uint16(data[0x1E:])
+	miniShift := le.Uint16(data[0x20:])
+	if (shift != 9 && shift != 12) || miniShift != 6 {
+		return nil, errors.New("不支持的扇区大小")
+	}
+	cfb := &cfbFile{
+		data:       data,
+		sectorSize: 1 << shift,
+		miniSize:   1 << miniShift,
+		miniCutoff: uint64(le.Uint32(data[0x38:])),
+	}
+
+	// 扇区表的位置保存在头部的前109项和后续的DIFAT扇区中
+	var fatSectors []uint32
+	for i := 0; i < 109; i++ {
+		fatSectors = append(fatSectors, le.Uint32(data[0x4C+i*4:]))
+	}
+	difat := le.Uint32(data[0x44:])
+	perSector := cfb.sectorSize/4 - 1
+	for count := 0; difat != cfbEndOfChain && difat != cfbFreeSect; count++ {
+		sector, err := cfb.sector(difat)
+		if err != nil || count > len(data)/cfb.sectorSize {
+			return nil, errors.New("DIFAT损坏")
+		}
+		for i := 0; i < perSector; i++ {
+			fatSectors = append(fatSectors, le.Uint32(sector[i*4:]))
+		}
+		difat = le.Uint32(sector[perSector*4:])
+	}
+	for _, index := range fatSectors[:min(int(le.Uint32(data[0x2C:])), len(fatSectors))] {
+		sector, err := cfb.sector(index)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < cfb.sectorSize; i += 4 {
+			cfb.fat = append(cfb.fat, le.Uint32(sector[i:]))
+		}
+	}
+
+	dir, err := cfb.chain(le.Uint32(data[0x30:]), cfb.sectorSize, cfb.fat, cfb.sector)
+	if err != nil {
+		return nil, fmt.Errorf("读取目录失败: %w", err)
+	}
+	for offset := 0; offset+128 <= len(dir); offset += 128 {
+		raw := dir[offset : offset+128]
+		nameLen := min(int(le.Uint16(raw[0x40:])), 64)
+		units := make([]uint16, 0, nameLen/2)
+		for i := 0; i+1 < nameLen; i += 2 {
+			units = append(units, le.Uint16(raw[i:]))
+		}
+		cfb.entries = append(cfb.entries, cfbEntry{
+			name:  strings.TrimRight(string(utf16.Decode(units)), "\x00"),
+			kind:  raw[0x42],
+			left:  le.Uint32(raw[0x44:]),
+			right: le.Uint32(raw[0x48:]),
+			child: le.Uint32(raw[0x4C:]),
+			start: le.Uint32(raw[0x74:]),
+			size:  le.Uint64(raw[0x78:]),
+		})
+	}
+	if cfb.sectorSize == 512 {
+		// 512字节扇区的版本中流大小只有低32位有效
+		for i := range cfb.entries {
+			cfb.entries[i].size &= 0xFFFFFFFF
+		}
+	}
+	if len(cfb.entries) == 0 || cfb.entries[0].kind != cfbRoot {
+		return nil, errors.New("缺少根目录")
+	}
+
+	// 小于阈值的流保存在根目录的迷你流中，由迷你扇区表索引
+	if miniFAT := le.Uint32(data[0x3C:]); miniFAT != cfbEndOfChain {
+		raw, err := cfb.chain(miniFAT, cfb.sectorSize, cfb.fat, cfb.sector)
+		if err != nil {
+			return nil, fmt.Errorf("读取迷你扇区表失败: %w", err)
+		}
+		for i := 0; i+4 <= len(raw); i += 4 {
+			cfb.miniFAT = append(cfb.miniFAT, le.Uint32(raw[i:]))
+		}
+		root := cfb.entries[0]
+		if cfb.miniStream, err = cfb.chain(root.start, cfb.sectorSize, cfb.fat, cfb.sector); err != nil {
+			return nil, fmt.Errorf("读取迷你流失败: %w", err)
+		}
+		if uint64(len(cfb.miniStream)) > root.size {
+			cfb.miniStream = cfb.miniStream[:root.size]
+		}
+	}
+	return cfb, nil
+}
+
+// sector 返回扇区的内容
+func (c *cfbFile) sector(index uint32) ([]byte, error) {
+	start := (int64(index) + 1) * int64(c.sectorSize)
+	if start+int64(c.sectorSize) > int64(len(c.data)) {
+		return nil, fmt.Errorf("扇区 %d 超出文件范围", index)
+	}
+	return c.data[start : start+int64(c.sectorSize)], nil
+}
+
+// miniSectorAt 供 chain 读取迷你扇区
+func (c *cfbFile) miniSectorAt(index uint32) ([]byte, error) {
+	start := int64(index) * int64(c.miniSize)
+	if start+int64(c.miniSize) > int64(len(c.miniStream)) {
+		return nil, fmt.Errorf("迷你扇区 %d 超出范围", index)
+	}
+	return c.miniStream[start : start+int64(c.miniSize)], nil
+}
+
+// chain 按扇区表读取从 start 开始的扇区链
+func (c *cfbFile) chain(start uint32, size int, table []uint32, read func(uint32) ([]byte, error)) ([]byte, error) {
+	var out []byte
+	for index, count := start, 0; index != cfbEndOfChain; count++ {
+		if int(index) >= len(table) || count > len(table) {
+			return nil, errors.New("扇区链损坏")
+		}
+		sector, err := read(index)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, sector[:size]...)
+		index = table[index]
+	}
+	return out, nil
+}
+
+// stream 读取流的内容
+func (c *cfbFile) stream(entry *cfbEntry) ([]byte, error) {
+	if entry.size == 0 {
+		return []byte{}, nil
+	}
+	if entry.size > uint64(len(c.data)) {
+		return nil, errors.New("流大小无效")
+	}
+	var data []byte
+	var err error
+	if entry.size < c.miniCutoff {
+		data, err = c.chain(entry.start, c.miniSize, c.miniFAT, c.miniSectorAt)
+	} else {
+		data, err = c.chain(entry.start, c.sectorSize, c.fat, c.sector)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(data)) < entry.size {
+		return nil, errors.New("流内容不完整")
+	}
+	return data[:entry.size], nil
+}
+
+// storage 读取存储的子目录项和定长属性，top 表示顶层邮件（定长属性流的头部为32字节）
+func (c *cfbFile) storage(entry *cfbEntry, top bool) (*msgStorage, error) {
+	s := &msgStorage{cfb: c, entries: map[string]*cfbEntry{}, fixed: map[uint32][]byte{}}
+
+	// 子目录项组成以 child 为根的二叉树
+	stack := []uint32{entry.child}
+	for visited := 0; len(stack) > 0; visited++ {
+		index := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if index == cfbNoStream {
+			continue
+		}
+		if int(index) >= len(c.entries) || visited > len(c.entries) {
+			return nil, errors.New("目录损坏")
+		}
+		child := &c.entries[index]
+		s.entries[strings.ToUpper(child.name)] = child
+		stack = append(stack, child.left, child.right)
+	}
+
+	if props, ok := s.entries["__PROPERTIES_VERSION1.0"]; ok {
+		data, err := c.stream(props)
+		if err != nil {
+			return nil, err
+		}
+		header := 8
+		if top {
+			header = 32
+		}
+		for offset := header; offset+16 <= len(data); offset += 16 {
+			s.fixed[binary.LittleEndian.Uint32(data[offset:])] = data[offset+8 : offset+16]
+		}
+	}
+	return s, nil
+}
+
+// property 读取变长属性的流内容和类型
+func (s *msgStorage) property(id uint16, kinds ...uint16) ([]byte, uint16, bool) {
+	for _, kind := range kinds {
+		entry, ok := s.entries[fmt.Sprintf("__SUBSTG1.0_%04X%04X", id, kind)]
+		if !ok || entry.kind != cfbStream {
+			continue
+		}
+		data, err := s.cfb.stream(entry)
+		if err != nil {
+			continue
+		}
+		return data, kind, true
+	}
+	return nil, 0, false
+}
+
+// text 读取字符串属性，Unicode 字符串优先
+func (s *msgStorage) text(id uint16) string {
+	data, kind, ok := s.property(id, ptUnicode, ptString8, ptBinary)
+	if !ok {
+		return ""
+	}
+	var text string
+	switch kind {
+	case ptUnicode:
+		units := make([]uint16, len(data)/2)
+		for i := range units {
+			units[i] = binary.LittleEndian.Uint16(data[i*2:])
+		}
+		text = string(utf16.Decode(units))
+	default:
+		text = string(decodeCharset(data, s.charset))
+	}
+	return strings.TrimSpace(strings.TrimRight(text, "\x00"))
+}
+
+// binaryValue 读取二进制属性，不存在时返回 nil
+func (s *msgStorage) binaryValue(id uint16) []byte {
+	data, _, ok := s.property(id, ptBinary)
+	if !ok {
+		return nil
+	}
+	return data
+}
+
+// integer 读取32位整数定长属性
+func (s *msgStorage) integer(id uint16) (int, bool) {
+	value, ok := s.fixed[uint32(id)<<16|ptLong]
+	if !ok {
+		return 0, false
+	}
+	return int(binary.LittleEndian.Uint32(value)), true
+}
+
+// systime 读取时间定长属性（FILETIME，自1601年起的100纳秒数）
+func (s *msgStorage) systime(id uint16) (time.Time, bool) {
+	value, ok := s.fixed[uint32(id)<<16|ptSystime]
+	if !ok {
+		return time.Time{}, false
+	}
+	ticks := binary.LittleEndian.Uint64(value)
+	if ticks == 0 {
+		return time.Time{}, false
+	}
+	const unixEpochTicks = 116444736000000000
+	return time.Unix(0, (int64(ticks)-unixEpochTicks)*100), true
+}
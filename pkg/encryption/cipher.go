@@ -0,0 +1,86 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// fieldPrefix 加密字段的前缀，未带前缀的值视为明文（兼容启用加密前的数据）
+const fieldPrefix = "enc:v1:"
+
+// IsEncrypted 判断字段值是否为密文
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, fieldPrefix)
+}
+
+// fieldCipher 单个字段的AES-256-GCM加解密，每次加密使用随机nonce
+type fieldCipher struct {
+	aead cipher.AEAD
+}
+
+// newFieldCipher 使用32字节密钥创建字段加密器
+func newFieldCipher(key []byte) (*fieldCipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("创建加密器失败: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("创建加密器失败: %w", err)
+	}
+	return &fieldCipher{aead: aead}, nil
+}
+
+// seal 加密数据，结果为 nonce + 密文
+func (c *fieldCipher) seal(plain []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("生成随机数失败: %w", err)
+	}
+	return c.aead.Seal(nonce, nonce, plain, nil), nil
+}
+
+// open 解密 seal 生成的数据
+func (c *fieldCipher) open(data []byte) ([]byte, error) {
+	size := c.aead.NonceSize()
+	if len(data) < size {
+		return nil, fmt.Errorf("密文长度无效")
+	}
+	plain, err := c.aead.Open(nil, data[:size], data[size:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("解密失败，密钥不正确或数据已损坏")
+	}
+	return plain, nil
+}
+
+// encrypt 加密字段，空值和已加密的值保持不变
+func (c *fieldCipher) encrypt(value string) (string, error) {
+	if value == "" || IsEncrypted(value) {
+		return value, nil
+	}
+	data, err := c.seal([]byte(value))
+	if err != nil {
+		return "", err
+	}
+	return fieldPrefix + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// decrypt 解密字段，明文原样返回
+func (c *fieldCipher) decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, fieldPrefix))
+	if err != nil {
+		return "", fmt.Errorf("密文格式无效: %w", err)
+	}
+	plain, err := c.open(data)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
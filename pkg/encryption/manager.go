@@ -0,0 +1,319 @@
+package encryption
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+
+	"pdf-ocr-ai/pkg/system"
+)
+
+// Mode 数据密钥的保管方式
+type Mode string
+
+const (
+	ModePassphrase Mode = "passphrase" // 由用户密码派生的密钥加密保存，每次启动需解锁
+	ModeKeychain   Mode = "keychain"   // 保存在系统钥匙串中，启动时自动解锁
+)
+
+// ErrLocked 已启用加密但尚未解锁
+var ErrLocked = errors.New("数据已加密，请先输入密码解锁")
+
+const (
+	settingsFileName = "encryption.json"
+	keychainService  = "pdfSeer"
+	keychainAccount  = "data-encryption-key"
+	minPassphraseLen = 8
+
+	// scrypt 参数（约 100ms 派生一次）
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// settings encryption.json 的内容，只保存加密后的数据密钥，不保存密码
+type settings struct {
+	Version    int    `json:"version"`
+	Mode       Mode   `json:"mode"`
+	Salt       string `json:"salt,omitempty"`        // 密码派生使用的盐（base64）
+	WrappedKey string `json:"wrapped_key,omitempty"` // 用密码派生密钥加密的数据密钥（base64）
+	N          int    `json:"n,omitempty"`
+	R          int    `json:"r,omitempty"`
+	P          int    `json:"p,omitempty"`
+}
+
+// Status 加密状态
+type Status struct {
+	Enabled           bool `json:"enabled"`
+	Mode              Mode `json:"mode"`
+	Unlocked          bool `json:"unlocked"`
+	KeychainAvailable bool `json:"keychain_available"`
+}
+
+// Manager 静态数据加密管理器
+// 数据使用随机生成的数据密钥按字段加密；数据密钥由用户密码派生的密钥加密后保存在 encryption.json，或保存在系统钥匙串
+type Manager struct {
+	path string
+
+	mu       sync.RWMutex
+	settings *settings
+	cipher   *fieldCipher
+}
+
+// NewManager 创建加密管理器，钥匙串模式下自动解锁
+func NewManager(dataDir string) (*Manager, error) {
+	m := &Manager{path: filepath.Join(dataDir, settingsFileName)}
+
+	data, err := os.ReadFile(m.path)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取加密设置失败: %w", err)
+	}
+
+	var s settings
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("解析加密设置失败: %w", err)
+	}
+	m.settings = &s
+
+	if s.Mode == ModeKeychain {
+		if err := m.unlockFromKeychain(); err != nil {
+			return m, err
+		}
+	}
+	return m, nil
+}
+
+// Status 获取加密状态
+func (m *Manager) Status() Status {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	status := Status{KeychainAvailable: system.KeychainAvailable()}
+	if m.settings != nil {
+		status.Enabled = true
+		status.Mode = m.settings.Mode
+		status.Unlocked = m.cipher != nil
+	}
+	return status
+}
+
+// Enabled 是否已启用加密
+func (m *Manager) Enabled() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.settings != nil
+}
+
+// Enable 启用加密，生成新的数据密钥并按指定方式保管
+func (m *Manager) Enable(mode Mode, passphrase string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.settings != nil {
+		return fmt.Errorf("加密已启用")
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return fmt.Errorf("生成密钥失败: %w", err)
+	}
+
+	s := &settings{Version: 1, Mode: mode}
+	switch mode {
+	case ModePassphrase:
+		if len([]rune(passphrase)) < minPassphraseLen {
+			return fmt.Errorf("密码至少需要 %d 个字符", minPassphraseLen)
+		}
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return fmt.Errorf("生成随机数失败: %w", err)
+		}
+		s.Salt = base64.StdEncoding.EncodeToString(salt)
+		s.N, s.R, s.P = scryptN, scryptR, scryptP
+
+		kek, err := s.deriveKey(passphrase)
+		if err != nil {
+			return err
+		}
+		wrapped, err := kek.seal(key)
+		if err != nil {
+			return err
+		}
+		s.WrappedKey = base64.StdEncoding.EncodeToString(wrapped)
+
+	case ModeKeychain:
+		if err := system.KeychainSet(keychainService, keychainAccount, base64.StdEncoding.EncodeToString(key)); err != nil {
+			return fmt.Errorf("保存密钥到钥匙串失败: %w", err)
+		}
+
+	default:
+		return fmt.Errorf("不支持的密钥保管方式: %s", mode)
+	}
+
+	cipher, err := newFieldCipher(key)
+	if err != nil {
+		return err
+	}
+	if err := s.save(m.path); err != nil {
+		return err
+	}
+
+	m.settings = s
+	m.cipher = cipher
+	return nil
+}
+
+// Unlock 使用密码解锁（钥匙串模式忽略密码，从钥匙串重新读取密钥）
+func (m *Manager) Unlock(passphrase string) error {
+	m.mu.RLock()
+	s := m.settings
+	m.mu.RUnlock()
+
+	if s == nil {
+		return fmt.Errorf("未启用加密")
+	}
+	if s.Mode == ModeKeychain {
+		return m.unlockFromKeychain()
+	}
+
+	kek, err := s.deriveKey(passphrase)
+	if err != nil {
+		return err
+	}
+	wrapped, err := base64.StdEncoding.DecodeString(s.WrappedKey)
+	if err != nil {
+		return fmt.Errorf("加密设置已损坏: %w", err)
+	}
+	key, err := kek.open(wrapped)
+	if err != nil {
+		return fmt.Errorf("密码不正确")
+	}
+
+	cipher, err := newFieldCipher(key)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	m.cipher = cipher
+	m.mu.Unlock()
+	return nil
+}
+
+// unlockFromKeychain 从系统钥匙串读取数据密钥
+func (m *Manager) unlockFromKeychain() error {
+	encoded, err := system.KeychainGet(keychainService, keychainAccount)
+	if err != nil {
+		return fmt.Errorf("从钥匙串读取密钥失败: %w", err)
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("钥匙串中的密钥格式无效: %w", err)
+	}
+
+	cipher, err := newFieldCipher(key)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	m.cipher = cipher
+	m.mu.Unlock()
+	return nil
+}
+
+// Lock 清除内存中的密钥，之后读写加密数据需要重新解锁
+func (m *Manager) Lock() {
+	m.mu.Lock()
+	m.cipher = nil
+	m.mu.Unlock()
+}
+
+// Disable 关闭加密并删除密钥（调用前需先将数据解密）
+func (m *Manager) Disable() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.settings == nil {
+		return nil
+	}
+	if m.cipher == nil {
+		return ErrLocked
+	}
+
+	if err := os.Remove(m.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("删除加密设置失败: %w", err)
+	}
+	if m.settings.Mode == ModeKeychain {
+		system.KeychainDelete(keychainService, keychainAccount)
+	}
+
+	m.settings = nil
+	m.cipher = nil
+	return nil
+}
+
+// Encrypt 加密字段，未启用加密时原样返回
+func (m *Manager) Encrypt(value string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.settings == nil {
+		return value, nil
+	}
+	if m.cipher == nil {
+		if value == "" {
+			return value, nil
+		}
+		return "", ErrLocked
+	}
+	return m.cipher.encrypt(value)
+}
+
+// Decrypt 解密字段，明文原样返回
+func (m *Manager) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.cipher == nil {
+		return "", ErrLocked
+	}
+	return m.cipher.decrypt(value)
+}
+
+// deriveKey 由密码派生密钥加密密钥
+func (s *settings) deriveKey(passphrase string) (*fieldCipher, error) {
+	salt, err := base64.StdEncoding.DecodeString(s.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("加密设置已损坏: %w", err)
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt, s.N, s.R, s.P, 32)
+	if err != nil {
+		return nil, fmt.Errorf("派生密钥失败: %w", err)
+	}
+	return newFieldCipher(key)
+}
+
+// save 保存加密设置
+func (s *settings) save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化加密设置失败: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("保存加密设置失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package exclusions
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"pdf-ocr-ai/pkg/migrate"
+	"pdf-ocr-ai/pkg/storage"
+)
+
+// migrationComponent 表结构迁移使用的组件名
+const migrationComponent = "exclusions"
+
+// Manager 排除页面存储，排除的页面不参与批量处理、导出和统计
+type Manager struct {
+	db *sqlx.DB
+}
+
+// NewManager 创建排除页面管理器，使用统一数据库中的 page_exclusions 表
+func NewManager(store *storage.Store) (*Manager, error) {
+	m := &Manager{db: store.DB()}
+	if err := store.Migrate(migrationComponent, migrations()); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// migrations 表结构迁移
+func migrations() []migrate.Migration {
+	return []migrate.Migration{
+		{
+			Version:     1,
+			Description: "创建排除页面表",
+			Up: migrate.SQL(`
+			CREATE TABLE IF NOT EXISTS page_exclusions (
+				document_id TEXT NOT NULL,
+				page_number INTEGER NOT NULL,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (document_id, page_number)
+			)`),
+		},
+	}
+}
+
+// Set 排除或恢复页面
+func (m *Manager) Set(documentID string, pageNumbers []int, excluded bool) error {
+	tx, err := m.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("保存排除页面失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := "DELETE FROM page_exclusions WHERE document_id = ? AND page_number = ?"
+	if excluded {
+		query = "INSERT OR IGNORE INTO page_exclusions (document_id, page_number) VALUES (?, ?)"
+	}
+	for _, pageNumber := range pageNumbers {
+		if _, err := tx.Exec(query, documentID, pageNumber); err != nil {
+			return fmt.Errorf("保存排除页面失败: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("保存排除页面失败: %w", err)
+	}
+	return nil
+}
+
+// Pages 获取文档排除的页码（按页码排序）
+func (m *Manager) Pages(documentID string) ([]int, error) {
+	pages := []int{}
+	if err := m.db.Select(&pages, "SELECT page_number FROM page_exclusions WHERE document_id = ? ORDER BY page_number", documentID); err != nil {
+		return nil, fmt.Errorf("查询排除页面失败: %w", err)
+	}
+	return pages, nil
+}
+
+// DeleteDocument 删除文档的所有排除页面
+func (m *Manager) DeleteDocument(documentID string) error {
+	if _, err := m.db.Exec("DELETE FROM page_exclusions WHERE document_id = ?", documentID); err != nil {
+		return fmt.Errorf("删除排除页面失败: %w", err)
+	}
+	return nil
+}
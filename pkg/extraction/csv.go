@@ -0,0 +1,68 @@
+package extraction
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"pdf-ocr-ai/pkg/config"
+)
+
+// utf8BOM 写在CSV开头，使Excel按UTF-8打开中文内容
+const utf8BOM = "\ufeff"
+
+// WriteCSV 将提取结果按模板字段写为CSV，每页一行，首列为页码，末列为校验问题或错误
+func WriteCSV(w io.Writer, schema config.ExtractionSchema, results []*Result) error {
+	if _, err := io.WriteString(w, utf8BOM); err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	header := []string{"page"}
+	for _, field := range schema.Fields {
+		header = append(header, field.Name)
+	}
+	header = append(header, "problems")
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("写入CSV失败: %w", err)
+	}
+
+	for _, result := range results {
+		record := []string{strconv.Itoa(result.PageNumber)}
+		for _, field := range schema.Fields {
+			record = append(record, formatValue(result.Fields[field.Name]))
+		}
+		problems := result.Problems
+		if result.Error != "" {
+			problems = append([]string{result.Error}, problems...)
+		}
+		record = append(record, strings.Join(problems, "; "))
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("写入CSV失败: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("写入CSV失败: %w", err)
+	}
+	return nil
+}
+
+// formatValue 将字段值格式化为CSV单元格文本
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,148 @@
+package extraction
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"pdf-ocr-ai/pkg/migrate"
+	"pdf-ocr-ai/pkg/storage"
+)
+
+// migrationComponent 表结构迁移使用的组件名
+const migrationComponent = "extraction"
+
+// Result 页面的结构化提取结果
+type Result struct {
+	DocumentID string                 `json:"document_id"`
+	PageNumber int                    `json:"page_number"`
+	SchemaID   string                 `json:"schema_id"`
+	Fields     map[string]interface{} `json:"fields"`             // 按模板字段名保存，缺失的字段为nil
+	Problems   []string               `json:"problems,omitempty"` // 校验问题（缺少必填字段、类型不符等）
+	Raw        string                 `json:"raw"`                // 模型原始返回，便于排查
+	Error      string                 `json:"error,omitempty"`    // 提取失败的原因
+	UpdatedAt  time.Time              `json:"updated_at"`
+}
+
+// row 数据库中保存的提取结果
+type row struct {
+	DocumentID string    `db:"document_id"`
+	PageNumber int       `db:"page_number"`
+	SchemaID   string    `db:"schema_id"`
+	Fields     string    `db:"fields"`
+	Problems   string    `db:"problems"`
+	Raw        string    `db:"raw"`
+	Error      string    `db:"error"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+// Manager 结构化提取结果存储，按文档、页码和模板保存最近一次结果
+type Manager struct {
+	db    *sqlx.DB
+	store *storage.Store // 字段值和原始返回加解密
+}
+
+// NewManager 创建提取结果管理器，使用统一数据库中的 page_extractions 表
+func NewManager(store *storage.Store) (*Manager, error) {
+	m := &Manager{db: store.DB(), store: store}
+	if err := store.Migrate(migrationComponent, migrations()); err != nil {
+		return nil, err
+	}
+	store.RegisterEncryptedColumns("page_extractions", "fields", "raw")
+	return m, nil
+}
+
+// migrations 表结构迁移
+func migrations() []migrate.Migration {
+	return []migrate.Migration{
+		{
+			Version:     1,
+			Description: "创建页面提取结果表",
+			Up: migrate.SQL(`
+			CREATE TABLE IF NOT EXISTS page_extractions (
+				document_id TEXT NOT NULL,
+				page_number INTEGER NOT NULL,
+				schema_id TEXT NOT NULL,
+				fields TEXT NOT NULL DEFAULT '',
+				problems TEXT NOT NULL DEFAULT '',
+				raw TEXT NOT NULL DEFAULT '',
+				error TEXT NOT NULL DEFAULT '',
+				updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (document_id, page_number, schema_id)
+			)`),
+		},
+	}
+}
+
+// Save 保存页面提取结果，覆盖同一页面和模板之前的结果
+func (m *Manager) Save(result *Result) error {
+	fields, err := json.Marshal(result.Fields)
+	if err != nil {
+		return fmt.Errorf("序列化提取结果失败: %w", err)
+	}
+	problems, err := json.Marshal(result.Problems)
+	if err != nil {
+		return fmt.Errorf("序列化提取结果失败: %w", err)
+	}
+
+	fieldsText, raw := string(fields), result.Raw
+	if err := m.store.EncryptFields(&fieldsText, &raw); err != nil {
+		return err
+	}
+
+	_, err = m.db.Exec(`
+	INSERT INTO page_extractions (document_id, page_number, schema_id, fields, problems, raw, error, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(document_id, page_number, schema_id) DO UPDATE SET
+		fields = excluded.fields, problems = excluded.problems, raw = excluded.raw,
+		error = excluded.error, updated_at = excluded.updated_at
+	`, result.DocumentID, result.PageNumber, result.SchemaID, fieldsText, string(problems), raw, result.Error, result.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("保存提取结果失败: %w", err)
+	}
+	return nil
+}
+
+// List 获取文档使用指定模板的所有页面提取结果（按页码排序）
+func (m *Manager) List(documentID, schemaID string) ([]*Result, error) {
+	var rows []row
+	err := m.db.Select(&rows, `SELECT * FROM page_extractions WHERE document_id = ? AND schema_id = ? ORDER BY page_number`, documentID, schemaID)
+	if err != nil {
+		return nil, fmt.Errorf("查询提取结果失败: %w", err)
+	}
+
+	results := make([]*Result, 0, len(rows))
+	for _, r := range rows {
+		if err := m.store.DecryptFields(&r.Fields, &r.Raw); err != nil {
+			return nil, err
+		}
+		result := &Result{
+			DocumentID: r.DocumentID,
+			PageNumber: r.PageNumber,
+			SchemaID:   r.SchemaID,
+			Raw:        r.Raw,
+			Error:      r.Error,
+			UpdatedAt:  r.UpdatedAt,
+		}
+		if r.Fields != "" {
+			if err := json.Unmarshal([]byte(r.Fields), &result.Fields); err != nil {
+				return nil, fmt.Errorf("解析第%d页提取结果失败: %w", r.PageNumber, err)
+			}
+		}
+		if r.Problems != "" {
+			json.Unmarshal([]byte(r.Problems), &result.Problems)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// DeleteDocument 删除文档的所有提取结果
+func (m *Manager) DeleteDocument(documentID string) error {
+	if _, err := m.db.Exec("DELETE FROM page_extractions WHERE document_id = ?", documentID); err != nil {
+		return fmt.Errorf("删除提取结果失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package extraction
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"pdf-ocr-ai/pkg/config"
+)
+
+// fieldNamePattern 字段名只允许字母、数字和下划线，便于作为JSON键和CSV列名
+var fieldNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// dateLayouts 日期字段可接受的格式，统一转换为 2006-01-02
+var dateLayouts = []string{
+	"2006-01-02", "2006/01/02", "2006.01.02", "2006年01月02日", "2006年1月2日",
+	"2006-1-2", "2006/1/2", "02/01/2006", "January 2, 2006", "Jan 2, 2006", "2 January 2006",
+}
+
+// ValidateSchema 检查提取模板是否有效
+func ValidateSchema(schema config.ExtractionSchema) error {
+	if schema.ID == "" || schema.Name == "" {
+		return fmt.Errorf("提取模板的ID和名称不能为空")
+	}
+	if len(schema.Fields) == 0 {
+		return fmt.Errorf("提取模板 %s 没有字段", schema.Name)
+	}
+
+	seen := make(map[string]bool)
+	for _, field := range schema.Fields {
+		if !fieldNamePattern.MatchString(field.Name) {
+			return fmt.Errorf("提取模板 %s 的字段名无效: %q（只能包含字母、数字和下划线，且以字母开头）", schema.Name, field.Name)
+		}
+		if seen[field.Name] {
+			return fmt.Errorf("提取模板 %s 的字段名重复: %s", schema.Name, field.Name)
+		}
+		seen[field.Name] = true
+
+		switch field.Type {
+		case "", config.FieldTypeText, config.FieldTypeNumber, config.FieldTypeDate, config.FieldTypeBoolean:
+		default:
+			return fmt.Errorf("提取模板 %s 的字段 %s 类型无效: %s", schema.Name, field.Name, field.Type)
+		}
+	}
+	return nil
+}
+
+// BuildPrompt 根据模板生成提取提示词，要求模型只返回一个JSON对象
+func BuildPrompt(schema config.ExtractionSchema) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "请从以下文本中提取「%s」的信息，只返回一个JSON对象，不要输出任何解释或Markdown标记。\n", schema.Name)
+	b.WriteString("JSON对象包含以下字段：\n")
+	for _, field := range schema.Fields {
+		fmt.Fprintf(&b, "- %s（%s）：%s", field.Name, typeDescription(field.Type), fieldDescription(field))
+		if field.Required {
+			b.WriteString("，必填")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("文本中找不到的字段值为 null，不要编造。金额只保留数字（不含货币符号和千位分隔符），日期使用 YYYY-MM-DD 格式。")
+	return b.String()
+}
+
+// typeDescription 字段类型的说明
+func typeDescription(fieldType string) string {
+	switch fieldType {
+	case config.FieldTypeNumber:
+		return "数字"
+	case config.FieldTypeDate:
+		return "日期"
+	case config.FieldTypeBoolean:
+		return "true 或 false"
+	default:
+		return "文本"
+	}
+}
+
+// fieldDescription 字段说明，未填写时使用显示名称
+func fieldDescription(field config.ExtractionField) string {
+	if field.Description != "" {
+		return field.Description
+	}
+	if field.Label != "" {
+		return field.Label
+	}
+	return field.Name
+}
+
+// Parse 解析模型返回的JSON并按模板校验
+// 返回按字段类型转换后的值（缺失或无效的字段为nil）和校验问题；找不到JSON对象时返回错误
+// 长文本分段处理时可能返回多个JSON对象，按顺序合并，先出现的非空值优先
+func Parse(schema config.ExtractionSchema, response string) (map[string]interface{}, []string, error) {
+	objects := findJSONObjects(response)
+	if len(objects) == 0 {
+		return nil, nil, fmt.Errorf("模型返回的内容不是JSON对象")
+	}
+
+	raw := make(map[string]interface{})
+	for _, object := range objects {
+		for key, value := range object {
+			if existing, ok := raw[key]; ok && !isEmpty(existing) {
+				continue
+			}
+			raw[key] = value
+		}
+	}
+
+	fields := make(map[string]interface{}, len(schema.Fields))
+	var problems []string
+	for _, field := range schema.Fields {
+		value, ok := raw[field.Name]
+		if !ok || isEmpty(value) {
+			fields[field.Name] = nil
+			if field.Required {
+				problems = append(problems, fmt.Sprintf("缺少必填字段 %s", fieldLabel(field)))
+			}
+			continue
+		}
+
+		converted, err := convertValue(field.Type, value)
+		if err != nil {
+			fields[field.Name] = nil
+			problems = append(problems, fmt.Sprintf("字段 %s 无效: %v", fieldLabel(field), err))
+			continue
+		}
+		fields[field.Name] = converted
+	}
+	return fields, problems, nil
+}
+
+// findJSONObjects 找出文本中所有顶层JSON对象（兼容 ```json 代码块和前后的说明文字）
+func findJSONObjects(text string) []map[string]interface{} {
+	var objects []map[string]interface{}
+	for start := strings.IndexByte(text, '{'); start >= 0; {
+		decoder := json.NewDecoder(strings.NewReader(text[start:]))
+		var object map[string]interface{}
+		if err := decoder.Decode(&object); err == nil {
+			objects = append(objects, object)
+			start += int(decoder.InputOffset())
+		} else {
+			start++
+		}
+
+		next := strings.IndexByte(text[start:], '{')
+		if next < 0 {
+			break
+		}
+		start += next
+	}
+	return objects
+}
+
+// isEmpty 值是否为空（null 或空白字符串）
+func isEmpty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	if s, ok := value.(string); ok {
+		return strings.TrimSpace(s) == ""
+	}
+	return false
+}
+
+// convertValue 按字段类型转换值
+func convertValue(fieldType string, value interface{}) (interface{}, error) {
+	switch fieldType {
+	case config.FieldTypeNumber:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case string:
+			return parseNumber(v)
+		}
+		return nil, fmt.Errorf("不是数字")
+
+	case config.FieldTypeDate:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("不是日期")
+		}
+		s = strings.TrimSpace(s)
+		for _, layout := range dateLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t.Format("2006-01-02"), nil
+			}
+		}
+		return nil, fmt.Errorf("无法识别的日期格式: %s", s)
+
+	case config.FieldTypeBoolean:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			switch strings.ToLower(strings.TrimSpace(v)) {
+			case "true", "yes", "是":
+				return true, nil
+			case "false", "no", "否":
+				return false, nil
+			}
+		}
+		return nil, fmt.Errorf("不是布尔值")
+
+	default:
+		switch v := value.(type) {
+		case string:
+			return strings.TrimSpace(v), nil
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		case bool:
+			return strconv.FormatBool(v), nil
+		}
+		// 对象或数组按JSON文本保存
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		return string(data), nil
+	}
+}
+
+// parseNumber 解析文本形式的数字，去掉货币符号、千位分隔符和空格
+func parseNumber(s string) (float64, error) {
+	cleaned := strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9', r == '.', r == '-':
+			return r
+		case r == ',', r == '，', r == ' ', r == '¥', r == '￥', r == '$', r == '€', r == '£', r == '元':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(s))
+
+	value, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil {
+		return 0, fmt.Errorf("不是数字: %s", s)
+	}
+	return value, nil
+}
+
+// fieldLabel 字段的显示名称
+func fieldLabel(field config.ExtractionField) string {
+	if field.Label != "" {
+		return field.Label
+	}
+	return field.Name
+}
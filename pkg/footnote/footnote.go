@@ -0,0 +1,230 @@
+package footnote
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"pdf-ocr-ai/pkg/config"
+	"pdf-ocr-ai/pkg/outline"
+)
+
+// maxContinuationLines 脚注之间（或最后一条脚注之后）允许的续行数，超过时视为正文
+const maxContinuationLines = 2
+
+// Note 脚注
+type Note struct {
+	Page   int    `json:"page"`   // 在输入中的序号（从1开始）
+	Marker string `json:"marker"` // 原始编号，如 "1"、"†"
+	Text   string `json:"text"`
+}
+
+// Result 脚注处理结果
+type Result struct {
+	Pages []string `json:"pages"` // 处理后的各页文本，与输入一一对应
+	Notes []Note   `json:"notes"` // 识别到的脚注
+}
+
+var (
+	// definitionPattern 页面底部的脚注行：[^1]: 、<sup>1</sup>、¹、1. 、[1]、† 等编号开头
+	definitionPattern = regexp.MustCompile(`^(?:\[\^([^\]\s]+)\]:|<sup>([^<\s]+)</sup>|([¹²³⁴⁵⁶⁷⁸⁹⁰]+)|\[(\d{1,3}|[†‡§¶])\]|(\d{1,3}|[†‡§¶])(?:[.)、:：]|\s))\s*(\S.*)$`)
+	// separatorPattern 正文与脚注之间的分隔线
+	separatorPattern = regexp.MustCompile(`^(-{3,}|_{3,}|\*{3,}|—{2,}|─{3,})$`)
+	// pageNumberPattern 脚注之后单独的页码行，不并入脚注内容
+	pageNumberPattern = regexp.MustCompile(`^[-–—\s]*\d+[-–—\s]*$`)
+	superscripts      = strings.NewReplacer("¹", "1", "²", "2", "³", "3", "⁴", "4", "⁵", "5", "⁶", "6", "⁷", "7", "⁸", "8", "⁹", "9", "⁰", "0")
+	toSuperscript     = strings.NewReplacer("1", "¹", "2", "²", "3", "³", "4", "⁴", "5", "⁵", "6", "⁶", "7", "⁷", "8", "⁸", "9", "⁹", "0", "⁰")
+)
+
+// Split 拆分页面正文和页面底部的脚注
+// 底部编号行只有在正文中能找到对应的引用标记（或有分隔线隔开）时才视为脚注，避免误判有序列表
+func Split(page string) (string, []Note) {
+	lines := strings.Split(strings.TrimRight(strings.ReplaceAll(page, "\r\n", "\n"), "\n "), "\n")
+
+	start, separator := -1, false
+	gap := 0
+	for i := len(lines) - 1; i > 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		switch {
+		case line == "":
+			continue
+		case separatorPattern.MatchString(line):
+			separator = start >= 0
+		case definitionPattern.MatchString(line):
+			start, gap = i, 0
+			continue
+		default:
+			gap++
+			if gap <= maxContinuationLines {
+				continue
+			}
+		}
+		break
+	}
+	if start < 0 {
+		return page, nil
+	}
+
+	body := strings.Join(lines[:start], "\n")
+	var notes []Note
+	for _, line := range lines[start:] {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if m := definitionPattern.FindStringSubmatch(line); m != nil {
+			marker := superscripts.Replace(m[1] + m[2] + m[3] + m[4] + m[5])
+			notes = append(notes, Note{Marker: marker, Text: m[6]})
+			continue
+		}
+		if len(notes) > 0 && !pageNumberPattern.MatchString(line) {
+			notes[len(notes)-1].Text += " " + line
+		}
+	}
+
+	for _, note := range notes {
+		if referencePattern(note.Marker).FindStringIndex(body) == nil && !separator {
+			return page, nil
+		}
+	}
+	if separator {
+		body = strings.TrimRight(body, "\n ")
+		if i := strings.LastIndexByte(body, '\n'); separatorPattern.MatchString(strings.TrimSpace(body[i+1:])) {
+			body = body[:i+1]
+		}
+	}
+	return strings.TrimRight(body, "\n "), notes
+}
+
+// referencePattern 正文中引用脚注的标记：[^1]、<sup>1</sup>、^1、^{1}、¹ 或 † 等符号
+func referencePattern(marker string) *regexp.Regexp {
+	quoted := regexp.QuoteMeta(marker)
+	alternatives := []string{
+		`\[\^` + quoted + `\]`,
+		`<sup>` + quoted + `</sup>`,
+		`\^\{?` + quoted + `\}?`,
+	}
+	if sup := toSuperscript.Replace(marker); sup != marker {
+		alternatives = append(alternatives, regexp.QuoteMeta(sup))
+	} else if !strings.ContainsAny(marker, "0123456789") {
+		alternatives = append(alternatives, quoted)
+	}
+	expr := `(?:` + strings.Join(alternatives, "|") + `)`
+	if strings.ContainsAny(marker, "0123456789") {
+		// 数字编号后不能紧跟数字，避免 ^1 匹配 ^12
+		expr += `([^0-9]|$)`
+	}
+	return regexp.MustCompile(expr)
+}
+
+// Process 按配置处理各页的脚注：inline 插入正文引用处，chapter 重新编号后汇总到每章末尾
+// keep 或未识别到脚注时原样返回
+func Process(pages []string, mode string) Result {
+	result := Result{Pages: append([]string(nil), pages...), Notes: []Note{}}
+	if mode != config.FootnoteModeInline && mode != config.FootnoteModeChapter {
+		return result
+	}
+
+	pageNotes := make([][]Note, len(pages))
+	for i, page := range pages {
+		body, notes := Split(page)
+		if len(notes) == 0 {
+			continue
+		}
+		for j := range notes {
+			notes[j].Page = i + 1
+		}
+		result.Pages[i] = body
+		pageNotes[i] = notes
+		result.Notes = append(result.Notes, notes...)
+	}
+	if len(result.Notes) == 0 {
+		return result
+	}
+
+	if mode == config.FootnoteModeInline {
+		for i, notes := range pageNotes {
+			for _, note := range notes {
+				result.Pages[i] = replaceReference(result.Pages[i], note.Marker, fmt.Sprintf("（注：%s）", note.Text))
+			}
+		}
+		return result
+	}
+
+	collectByChapter(result.Pages, pageNotes)
+	return result
+}
+
+// collectByChapter 将脚注重新编号为 [n]，并在每个一级章节之前（以及文末）输出上一章的脚注
+func collectByChapter(pages []string, pageNotes [][]Note) {
+	chapterStarts := make(map[int][]int)
+	for _, h := range outline.Detect(pages) {
+		if h.Level == 1 {
+			chapterStarts[h.Page] = append(chapterStarts[h.Page], h.Line)
+		}
+	}
+
+	var pending []string
+	flush := func() string {
+		if len(pending) == 0 {
+			return ""
+		}
+		block := "---\n注释：\n" + strings.Join(pending, "\n")
+		pending = nil
+		return block
+	}
+
+	lastPage := -1
+	for i := range pages {
+		if strings.TrimSpace(pages[i]) != "" || len(pageNotes[i]) > 0 {
+			lastPage = i
+		}
+	}
+
+	for i := range pages {
+		lines := strings.Split(pages[i], "\n")
+		starts := make(map[int]bool)
+		for _, line := range chapterStarts[i+1] {
+			starts[line] = true
+		}
+
+		var out []string
+		for j, line := range lines {
+			if starts[j] {
+				if block := flush(); block != "" {
+					out = append(out, block, "")
+				}
+			}
+			out = append(out, line)
+		}
+		text := strings.Join(out, "\n")
+
+		// 本页的脚注编号在上面输出的章节之后继续
+		for _, note := range pageNotes[i] {
+			n := len(pending) + 1
+			text = replaceReference(text, note.Marker, fmt.Sprintf("[%d]", n))
+			pending = append(pending, fmt.Sprintf("[%d] %s", n, note.Text))
+		}
+		if i == lastPage {
+			if block := flush(); block != "" {
+				text = strings.TrimRight(text, "\n") + "\n\n" + block
+			}
+		}
+		pages[i] = text
+	}
+}
+
+// replaceReference 替换正文中第一个引用脚注的标记
+func replaceReference(text, marker, replacement string) string {
+	pattern := referencePattern(marker)
+	loc := pattern.FindStringSubmatchIndex(text)
+	if loc == nil {
+		return text
+	}
+	end := loc[1]
+	if len(loc) > 2 && loc[2] >= 0 {
+		// 保留编号后紧跟的字符
+		end = loc[2]
+	}
+	return text[:loc[0]] + replacement + text[end:]
+}
@@ -0,0 +1,147 @@
+package glossary
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"pdf-ocr-ai/pkg/config"
+)
+
+// Validate 检查术语表条目是否有效
+func Validate(terms []config.GlossaryTerm) error {
+	seen := make(map[string]bool)
+	for _, term := range terms {
+		if strings.TrimSpace(term.Term) == "" || strings.TrimSpace(term.Preferred) == "" {
+			return fmt.Errorf("术语表条目的原写法和统一写法不能为空")
+		}
+		key := strings.ToLower(term.Term)
+		if seen[key] {
+			return fmt.Errorf("术语表条目重复: %s", term.Term)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// PromptSection 生成加入AI提示词的术语要求，没有条目时返回空字符串
+func PromptSection(terms []config.GlossaryTerm) string {
+	if len(terms) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("【术语表】输出中请统一使用以下写法（左侧为原文中可能出现的写法，右侧为必须使用的写法）：\n")
+	for _, term := range terms {
+		fmt.Fprintf(&b, "- %s → %s", term.Term, term.Preferred)
+		if term.Note != "" {
+			fmt.Fprintf(&b, "（%s）", term.Note)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// span 文本中的一段字节区间
+type span struct {
+	start, end int
+	preferred  string
+}
+
+// Apply 将文本中的术语统一替换为指定写法，返回替换后的文本和替换次数
+// 较长的术语优先匹配；以字母或数字开头/结尾的术语按整词匹配；已是统一写法的内容不再替换
+func Apply(text string, terms []config.GlossaryTerm) (string, int) {
+	if len(terms) == 0 || text == "" {
+		return text, 0
+	}
+
+	sorted := append([]config.GlossaryTerm(nil), terms...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return utf8.RuneCountInString(sorted[i].Term) > utf8.RuneCountInString(sorted[j].Term)
+	})
+
+	// 已经是统一写法的位置不参与替换，避免 "Acme" → "Acme Cloud" 时重复追加
+	var protected []span
+	for _, term := range sorted {
+		for offset := 0; ; {
+			i := strings.Index(text[offset:], term.Preferred)
+			if i < 0 {
+				break
+			}
+			start := offset + i
+			protected = append(protected, span{start: start, end: start + len(term.Preferred)})
+			offset = start + len(term.Preferred)
+		}
+	}
+
+	var matches []span
+	for _, term := range sorted {
+		pattern := termPattern(term)
+		if pattern == nil {
+			continue
+		}
+		for _, loc := range pattern.FindAllStringIndex(text, -1) {
+			candidate := span{start: loc[0], end: loc[1], preferred: term.Preferred}
+			if text[loc[0]:loc[1]] == term.Preferred || overlaps(candidate, protected) || overlaps(candidate, matches) {
+				continue
+			}
+			matches = append(matches, candidate)
+		}
+	}
+	if len(matches) == 0 {
+		return text, 0
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].start < matches[j].start })
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		b.WriteString(text[last:m.start])
+		b.WriteString(m.preferred)
+		last = m.end
+	}
+	b.WriteString(text[last:])
+	return b.String(), len(matches)
+}
+
+// termPattern 构建术语的匹配表达式
+func termPattern(term config.GlossaryTerm) *regexp.Regexp {
+	if term.Term == "" {
+		return nil
+	}
+	expr := regexp.QuoteMeta(term.Term)
+	first, _ := utf8.DecodeRuneInString(term.Term)
+	last, _ := utf8.DecodeLastRuneInString(term.Term)
+	if isWordRune(first) {
+		expr = `\b` + expr
+	}
+	if isWordRune(last) {
+		expr += `\b`
+	}
+	if !term.CaseSensitive {
+		expr = "(?i)" + expr
+	}
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return nil
+	}
+	return pattern
+}
+
+// isWordRune 是否为整词匹配使用的单词字符（ASCII字母、数字和下划线，与 \b 一致）
+func isWordRune(r rune) bool {
+	return r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_')
+}
+
+// overlaps 区间是否与已有区间重叠
+func overlaps(s span, spans []span) bool {
+	for _, other := range spans {
+		if s.start < other.end && other.start < s.end {
+			return true
+		}
+	}
+	return false
+}
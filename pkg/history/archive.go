@@ -0,0 +1,261 @@
+package history
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	archiveFormat  = "pdfseer-history"
+	archiveVersion = 1
+)
+
+// ArchiveManifest 历史归档文件说明
+type ArchiveManifest struct {
+	Format      string `json:"format"`
+	Version     int    `json:"version"`
+	ExportedAt  string `json:"exported_at"`
+	RecordCount int    `json:"record_count"`
+}
+
+// archiveEntry records.jsonl 中的一行：一条记录及其所有页面
+type archiveEntry struct {
+	Record *HistoryRecord `json:"record"`
+	Pages  []*HistoryPage `json:"pages"`
+}
+
+// ImportResult 导入结果
+type ImportResult struct {
+	Imported int `json:"imported"`
+	Skipped  int `json:"skipped"` // 已存在而跳过的记录
+	Pages    int `json:"pages"`
+}
+
+// ExportArchive 将符合条件的记录导出为zip归档（manifest.json + records.jsonl），返回导出的记录数
+// 启用加密时页面文本以密文导出，只能导入到持有相同密钥的应用中
+func (hm *HistoryManager) ExportArchive(path string, filter HistoryFilter) (int, error) {
+	hm.sync()
+	where, args, err := filter.whereClause()
+	if err != nil {
+		return 0, err
+	}
+
+	var records []*HistoryRecord
+	if err := hm.db.Select(&records, "SELECT * FROM processing_history "+where+" ORDER BY processed_at, id", args...); err != nil {
+		return 0, fmt.Errorf("查询历史记录失败: %w", err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return 0, fmt.Errorf("创建归档文件失败: %w", err)
+	}
+	defer file.Close()
+
+	zw := zip.NewWriter(file)
+
+	w, err := zw.Create("records.jsonl")
+	if err != nil {
+		return 0, fmt.Errorf("写入归档失败: %w", err)
+	}
+	encoder := json.NewEncoder(w)
+	for _, record := range records {
+		// 已归档的记录直接从冷存储读取，不恢复到数据库
+		pages, err := hm.exportPages(record)
+		if err != nil {
+			return 0, fmt.Errorf("获取记录页面失败: %w", err)
+		}
+		if err := encoder.Encode(archiveEntry{Record: record, Pages: pages}); err != nil {
+			return 0, fmt.Errorf("写入归档失败: %w", err)
+		}
+	}
+
+	manifest := ArchiveManifest{
+		Format:      archiveFormat,
+		Version:     archiveVersion,
+		ExportedAt:  time.Now().Format(time.RFC3339),
+		RecordCount: len(records),
+	}
+	w, err = zw.Create("manifest.json")
+	if err != nil {
+		return 0, fmt.Errorf("写入归档失败: %w", err)
+	}
+	if err := json.NewEncoder(w).Encode(manifest); err != nil {
+		return 0, fmt.Errorf("写入归档失败: %w", err)
+	}
+
+	if err := zw.Close(); err != nil {
+		return 0, fmt.Errorf("写入归档失败: %w", err)
+	}
+	return len(records), nil
+}
+
+// ImportArchive 导入 ExportArchive 生成的归档，已存在的记录（同一文档、时间、任务类型和模型）会被跳过
+func (hm *HistoryManager) ImportArchive(path string) (*ImportResult, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开归档文件失败: %w", err)
+	}
+	defer zr.Close()
+
+	var manifest *ArchiveManifest
+	var recordsFile *zip.File
+	for _, f := range zr.File {
+		switch f.Name {
+		case "manifest.json":
+			rc, err := f.Open()
+			if err != nil {
+				return nil, fmt.Errorf("读取归档说明失败: %w", err)
+			}
+			manifest = &ArchiveManifest{}
+			err = json.NewDecoder(rc).Decode(manifest)
+			rc.Close()
+			if err != nil {
+				return nil, fmt.Errorf("解析归档说明失败: %w", err)
+			}
+		case "records.jsonl":
+			recordsFile = f
+		}
+	}
+	if manifest == nil || manifest.Format != archiveFormat || recordsFile == nil {
+		return nil, fmt.Errorf("不是有效的历史记录归档")
+	}
+	if manifest.Version > archiveVersion {
+		return nil, fmt.Errorf("归档版本 %d 高于当前支持的版本 %d，请升级应用", manifest.Version, archiveVersion)
+	}
+
+	rc, err := recordsFile.Open()
+	if err != nil {
+		return nil, fmt.Errorf("读取归档失败: %w", err)
+	}
+	defer rc.Close()
+
+	result := &ImportResult{}
+	scanner := bufio.NewScanner(rc)
+	scanner.Buffer(make([]byte, 1024*1024), 256*1024*1024)
+	for scanner.Scan() {
+		var entry archiveEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return result, fmt.Errorf("解析归档记录失败: %w", err)
+		}
+		if entry.Record == nil {
+			continue
+		}
+
+		imported, err := hm.importEntry(entry)
+		if err != nil {
+			return result, err
+		}
+		if imported {
+			result.Imported++
+			result.Pages += len(entry.Pages)
+		} else {
+			result.Skipped++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return result, fmt.Errorf("读取归档失败: %w", err)
+	}
+
+	return result, nil
+}
+
+// importEntry 导入一条记录及其页面，记录已存在时返回false
+func (hm *HistoryManager) importEntry(entry archiveEntry) (bool, error) {
+	record := entry.Record
+	processedAt := normalizeTimestamp(record.ProcessedAt)
+	if record.TaskType == "" {
+		record.TaskType = TaskTypeOCR
+	}
+
+	var count int
+	err := hm.db.Get(&count, `
+	SELECT COUNT(*) FROM processing_history
+	WHERE document_path = ? AND datetime(processed_at) = datetime(?) AND task_type = ? AND model = ?
+	`, record.DocumentPath, processedAt, record.TaskType, record.Model)
+	if err != nil {
+		return false, fmt.Errorf("检查重复记录失败: %w", err)
+	}
+	if count > 0 {
+		return false, nil
+	}
+
+	var completedAt *string
+	if record.CompletedAt != nil {
+		value := normalizeTimestamp(*record.CompletedAt)
+		completedAt = &value
+	}
+
+	tx, err := hm.db.Beginx()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// 页面文本先解密（验证加密归档的密钥一致），再按当前设置加密保存
+	for _, page := range entry.Pages {
+		if err := hm.store.DecryptFields(&page.OriginalText, &page.OCRText, &page.AIProcessedText); err != nil {
+			return false, fmt.Errorf("归档中的加密内容无法解密: %w", err)
+		}
+		if err := hm.store.EncryptFields(&page.OriginalText, &page.OCRText, &page.AIProcessedText); err != nil {
+			return false, err
+		}
+	}
+
+	res, err := tx.Exec(`
+	INSERT INTO processing_history
+	(document_path, document_name, page_count, status, task_type, model, ai_model, cost, processed_at, completed_at, error_message,
+	 completed_pages, failed_pages, document_id, duration_seconds, avg_page_seconds, pages_per_minute, total_tokens)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, record.DocumentPath, record.DocumentName, record.PageCount, record.Status, record.TaskType,
+		record.Model, record.AIModel, record.Cost, processedAt, completedAt, record.ErrorMessage,
+		record.CompletedPages, record.FailedPages, record.DocumentID,
+		record.DurationSeconds, record.AvgPageSeconds, record.PagesPerMinute, record.TotalTokens)
+	if err != nil {
+		return false, fmt.Errorf("导入历史记录失败: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return false, fmt.Errorf("获取记录ID失败: %w", err)
+	}
+
+	for _, page := range entry.Pages {
+		_, err := tx.Exec(`
+		INSERT OR REPLACE INTO history_pages
+		(history_id, page_number, original_text, ocr_text, ai_processed_text, processing_time, created_at, status, render_fallback)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, id, page.PageNumber, page.OriginalText, page.OCRText, page.AIProcessedText,
+			page.ProcessingTime, normalizeTimestamp(page.CreatedAt), pageStatusOrDone(page.Status), page.RenderFallback)
+		if err != nil {
+			return false, fmt.Errorf("导入页面失败: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	if hm.searchIndexEnabled() {
+		for _, page := range entry.Pages {
+			if err := hm.updateSearchIndex(hm.db, int(id), page.PageNumber); err != nil {
+				return true, fmt.Errorf("更新搜索索引失败: %w", err)
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// normalizeTimestamp 将读出的时间（RFC3339）转换为SQLite默认的UTC格式，便于比较和排序
+func normalizeTimestamp(value string) string {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC().Format("2006-01-02 15:04:05")
+	}
+	if value == "" {
+		return time.Now().UTC().Format("2006-01-02 15:04:05")
+	}
+	return value
+}
@@ -0,0 +1,339 @@
+package history
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// stubExcerptLength 冷存储记录在数据库中保留的每页摘要长度（字符）
+const stubExcerptLength = 300
+
+// ColdArchiveResult 冷存储归档结果
+type ColdArchiveResult struct {
+	Records int      `json:"records"`
+	Pages   int      `json:"pages"`
+	Files   []string `json:"files"` // 写入的归档文件
+}
+
+// migrateArchiveColumns 添加冷存储相关的列和摘要表
+func (hm *HistoryManager) migrateArchiveColumns(tx *sqlx.Tx) error {
+	var count int
+	err := tx.Get(&count, "SELECT COUNT(*) FROM pragma_table_info('processing_history') WHERE name = 'archived_at'")
+	if err != nil {
+		return fmt.Errorf("读取表结构失败: %w", err)
+	}
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS history_archive_stubs (
+			history_id INTEGER NOT NULL,
+			page_number INTEGER NOT NULL,
+			ocr_excerpt TEXT,
+			ai_excerpt TEXT,
+			PRIMARY KEY (history_id, page_number)
+		)`,
+	}
+	if count == 0 {
+		statements = append(statements,
+			`ALTER TABLE processing_history ADD COLUMN archived_at DATETIME`,
+			`ALTER TABLE processing_history ADD COLUMN archive_file TEXT`,
+		)
+	}
+
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("执行迁移语句失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// ArchiveOldRecords 将days天前的记录页面移入按月分组的压缩归档文件（~/.pdfSeer/archive/history-YYYY-MM.jsonl.gz），
+// 数据库中只保留记录元数据和每页摘要，摘要仍可被搜索，查看时自动恢复
+func (hm *HistoryManager) ArchiveOldRecords(days int) (*ColdArchiveResult, error) {
+	if days <= 0 {
+		return nil, fmt.Errorf("归档天数必须大于0")
+	}
+	hm.sync()
+	cutoff := time.Now().UTC().AddDate(0, 0, -days).Format("2006-01-02 15:04:05")
+
+	var records []*HistoryRecord
+	err := hm.db.Select(&records, `
+	SELECT * FROM processing_history
+	WHERE processed_at < ? AND archived_at IS NULL AND status != 'processing'
+	ORDER BY processed_at
+	`, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("查询待归档记录失败: %w", err)
+	}
+
+	result := &ColdArchiveResult{Files: []string{}}
+	if len(records) == 0 {
+		return result, nil
+	}
+
+	if err := os.MkdirAll(hm.archiveDir, 0755); err != nil {
+		return nil, fmt.Errorf("创建归档目录失败: %w", err)
+	}
+
+	// 按处理月份分组
+	byFile := make(map[string][]archiveEntry)
+	var fileOrder []string
+	for _, record := range records {
+		pages, err := hm.recordPages(record.ID)
+		if err != nil {
+			return nil, fmt.Errorf("获取记录页面失败: %w", err)
+		}
+		// 归档文件保存明文，更换或关闭加密后仍可恢复
+		for _, page := range pages {
+			if err := hm.store.DecryptFields(&page.OriginalText, &page.OCRText, &page.AIProcessedText); err != nil {
+				return nil, fmt.Errorf("解密记录页面失败: %w", err)
+			}
+		}
+
+		name := archiveFileName(record.ProcessedAt)
+		if _, ok := byFile[name]; !ok {
+			fileOrder = append(fileOrder, name)
+		}
+		byFile[name] = append(byFile[name], archiveEntry{Record: record, Pages: pages})
+	}
+
+	for _, name := range fileOrder {
+		entries := byFile[name]
+
+		// 先写入归档文件再修改数据库，中途失败不会丢失数据
+		if err := appendArchiveFile(filepath.Join(hm.archiveDir, name), entries); err != nil {
+			return result, err
+		}
+		for _, entry := range entries {
+			if err := hm.stubRecord(entry, name); err != nil {
+				return result, err
+			}
+			result.Records++
+			result.Pages += len(entry.Pages)
+		}
+		result.Files = append(result.Files, name)
+	}
+
+	return result, nil
+}
+
+// RestoreArchivedRecord 从冷存储恢复记录的页面
+func (hm *HistoryManager) RestoreArchivedRecord(id int) error {
+	record, err := hm.GetRecord(id)
+	if err != nil {
+		return fmt.Errorf("获取记录失败: %w", err)
+	}
+	if record == nil {
+		return fmt.Errorf("历史记录不存在")
+	}
+	if record.ArchivedAt == nil || record.ArchiveFile == nil {
+		return nil
+	}
+
+	pages, err := hm.readArchivedPages(*record.ArchiveFile, id)
+	if err != nil {
+		return err
+	}
+
+	tx, err := hm.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, page := range pages {
+		// 归档文件保存明文（旧版本归档的密文先解密），恢复时按当前设置加密
+		if err := hm.store.DecryptFields(&page.OriginalText, &page.OCRText, &page.AIProcessedText); err != nil {
+			return err
+		}
+		if err := hm.store.EncryptFields(&page.OriginalText, &page.OCRText, &page.AIProcessedText); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`
+		INSERT OR REPLACE INTO history_pages
+		(history_id, page_number, original_text, ocr_text, ai_processed_text, processing_time, created_at, status, render_fallback)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, id, page.PageNumber, page.OriginalText, page.OCRText, page.AIProcessedText,
+			page.ProcessingTime, normalizeTimestamp(page.CreatedAt), pageStatusOrDone(page.Status), page.RenderFallback)
+		if err != nil {
+			return fmt.Errorf("恢复页面失败: %w", err)
+		}
+	}
+	if _, err := tx.Exec("DELETE FROM history_archive_stubs WHERE history_id = ?", id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE processing_history SET archived_at = NULL, archive_file = NULL WHERE id = ?", id); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if hm.searchIndexEnabled() {
+		for _, page := range pages {
+			if err := hm.updateSearchIndex(hm.db, id, page.PageNumber); err != nil {
+				return fmt.Errorf("更新搜索索引失败: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// rehydrateIfArchived 记录已归档时从冷存储恢复
+func (hm *HistoryManager) rehydrateIfArchived(id int) error {
+	var archived int
+	if err := hm.db.Get(&archived, "SELECT COUNT(*) FROM processing_history WHERE id = ? AND archived_at IS NOT NULL", id); err != nil {
+		return err
+	}
+	if archived == 0 {
+		return nil
+	}
+	return hm.RestoreArchivedRecord(id)
+}
+
+// exportPages 获取导出用的页面，已归档的记录直接读取归档文件
+func (hm *HistoryManager) exportPages(record *HistoryRecord) ([]*HistoryPage, error) {
+	if record.ArchivedAt != nil && record.ArchiveFile != nil {
+		return hm.readArchivedPages(*record.ArchiveFile, record.ID)
+	}
+	return hm.recordPages(record.ID)
+}
+
+// stubRecord 删除记录页面，保留摘要并标记为已归档
+func (hm *HistoryManager) stubRecord(entry archiveEntry, fileName string) error {
+	id := entry.Record.ID
+
+	tx, err := hm.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, page := range entry.Pages {
+		ocrExcerpt, aiExcerpt, err := hm.pageExcerpts(page)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`
+		INSERT OR REPLACE INTO history_archive_stubs (history_id, page_number, ocr_excerpt, ai_excerpt)
+		VALUES (?, ?, ?, ?)
+		`, id, page.PageNumber, ocrExcerpt, aiExcerpt)
+		if err != nil {
+			return fmt.Errorf("保存归档摘要失败: %w", err)
+		}
+	}
+
+	if hm.ftsEnabled {
+		if _, err := tx.Exec("DELETE FROM history_search WHERE history_id = ?", id); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec("DELETE FROM history_pages WHERE history_id = ?", id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE processing_history SET archived_at = CURRENT_TIMESTAMP, archive_file = ? WHERE id = ?", fileName, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// readArchivedPages 从归档文件中读取记录的页面，同一记录多次归档时以最后一次为准
+func (hm *HistoryManager) readArchivedPages(fileName string, id int) ([]*HistoryPage, error) {
+	file, err := os.Open(filepath.Join(hm.archiveDir, filepath.Base(fileName)))
+	if err != nil {
+		return nil, fmt.Errorf("打开归档文件失败: %w", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("读取归档文件失败: %w", err)
+	}
+	defer gz.Close()
+
+	var pages []*HistoryPage
+	found := false
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 1024*1024), 256*1024*1024)
+	for scanner.Scan() {
+		var entry archiveEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("解析归档记录失败: %w", err)
+		}
+		if entry.Record != nil && entry.Record.ID == id {
+			pages = entry.Pages
+			found = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取归档文件失败: %w", err)
+	}
+	if !found {
+		return nil, fmt.Errorf("归档文件 %s 中未找到记录 %d", fileName, id)
+	}
+
+	return pages, nil
+}
+
+// appendArchiveFile 以新的gzip分段追加写入归档文件（gzip支持多段拼接读取）
+func appendArchiveFile(path string, entries []archiveEntry) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("打开归档文件失败: %w", err)
+	}
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	encoder := json.NewEncoder(gz)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return fmt.Errorf("写入归档文件失败: %w", err)
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("写入归档文件失败: %w", err)
+	}
+	return file.Sync()
+}
+
+// archiveFileName 按处理时间所在月份生成归档文件名
+func archiveFileName(processedAt string) string {
+	month := time.Now().UTC().Format("2006-01")
+	if t, err := time.Parse(time.RFC3339, processedAt); err == nil {
+		month = t.UTC().Format("2006-01")
+	} else if len(processedAt) >= 7 {
+		month = processedAt[:7]
+	}
+	return fmt.Sprintf("history-%s.jsonl.gz", month)
+}
+
+// pageExcerpts 生成页面的摘要，启用加密时摘要同样加密保存
+func (hm *HistoryManager) pageExcerpts(page *HistoryPage) (string, string, error) {
+	ocrText, aiText := page.OCRText, page.AIProcessedText
+	if err := hm.store.DecryptFields(&ocrText, &aiText); err != nil {
+		return "", "", err
+	}
+	ocrExcerpt, aiExcerpt := excerpt(ocrText), excerpt(aiText)
+	if err := hm.store.EncryptFields(&ocrExcerpt, &aiExcerpt); err != nil {
+		return "", "", err
+	}
+	return ocrExcerpt, aiExcerpt, nil
+}
+
+// excerpt 截取文本开头作为摘要
+func excerpt(text string) string {
+	if utf8.RuneCountInString(text) <= stubExcerptLength {
+		return text
+	}
+	return string([]rune(text)[:stubExcerptLength])
+}
@@ -0,0 +1,118 @@
+package history
+
+import (
+	"fmt"
+	"sort"
+
+	"pdf-ocr-ai/pkg/textdiff"
+)
+
+// PageDiffStatus 页面比较结果
+type PageDiffStatus string
+
+const (
+	PageDiffSame    PageDiffStatus = "same"
+	PageDiffChanged PageDiffStatus = "changed"
+	PageDiffOnlyA   PageDiffStatus = "only_a" // 仅记录A包含该页
+	PageDiffOnlyB   PageDiffStatus = "only_b" // 仅记录B包含该页
+)
+
+// PageDiff 单页文本差异
+type PageDiff struct {
+	PageNumber int             `json:"page_number"`
+	Status     PageDiffStatus  `json:"status"`
+	Spans      []textdiff.Span `json:"spans"`
+	Added      int             `json:"added"`   // 新增字符数
+	Removed    int             `json:"removed"` // 删除字符数
+}
+
+// RecordComparison 两次处理记录的比较结果
+type RecordComparison struct {
+	RecordA      *HistoryRecord `json:"record_a"`
+	RecordB      *HistoryRecord `json:"record_b"`
+	Pages        []PageDiff     `json:"pages"`
+	ChangedPages int            `json:"changed_pages"`
+}
+
+// CompareRecords 按页码对齐两条记录，逐页比较文本差异（A为旧文本，B为新文本）
+func (hm *HistoryManager) CompareRecords(idA, idB int) (*RecordComparison, error) {
+	recordA, err := hm.GetRecord(idA)
+	if err != nil {
+		return nil, fmt.Errorf("获取记录失败: %w", err)
+	}
+	recordB, err := hm.GetRecord(idB)
+	if err != nil {
+		return nil, fmt.Errorf("获取记录失败: %w", err)
+	}
+	if recordA == nil || recordB == nil {
+		return nil, fmt.Errorf("历史记录不存在")
+	}
+
+	pagesA, err := hm.GetRecordPages(idA)
+	if err != nil {
+		return nil, fmt.Errorf("获取记录页面失败: %w", err)
+	}
+	pagesB, err := hm.GetRecordPages(idB)
+	if err != nil {
+		return nil, fmt.Errorf("获取记录页面失败: %w", err)
+	}
+
+	textsA := pageTexts(recordA, pagesA)
+	textsB := pageTexts(recordB, pagesB)
+
+	pageNumbers := make([]int, 0, len(textsA)+len(textsB))
+	for pageNumber := range textsA {
+		pageNumbers = append(pageNumbers, pageNumber)
+	}
+	for pageNumber := range textsB {
+		if _, ok := textsA[pageNumber]; !ok {
+			pageNumbers = append(pageNumbers, pageNumber)
+		}
+	}
+	sort.Ints(pageNumbers)
+
+	comparison := &RecordComparison{
+		RecordA: recordA,
+		RecordB: recordB,
+		Pages:   make([]PageDiff, 0, len(pageNumbers)),
+	}
+
+	for _, pageNumber := range pageNumbers {
+		textA, inA := textsA[pageNumber]
+		textB, inB := textsB[pageNumber]
+
+		diff := PageDiff{PageNumber: pageNumber, Spans: textdiff.Diff(textA, textB)}
+		diff.Added, diff.Removed = textdiff.Stats(diff.Spans)
+
+		switch {
+		case !inB:
+			diff.Status = PageDiffOnlyA
+		case !inA:
+			diff.Status = PageDiffOnlyB
+		case textA == textB:
+			diff.Status = PageDiffSame
+		default:
+			diff.Status = PageDiffChanged
+		}
+		if diff.Status != PageDiffSame {
+			comparison.ChangedPages++
+		}
+
+		comparison.Pages = append(comparison.Pages, diff)
+	}
+
+	return comparison, nil
+}
+
+// pageTexts 获取记录中每页的结果文本，AI任务使用AI处理结果，OCR任务使用识别结果
+func pageTexts(record *HistoryRecord, pages []*HistoryPage) map[int]string {
+	texts := make(map[int]string, len(pages))
+	for _, page := range pages {
+		text := page.OCRText
+		if record.TaskType == TaskTypeAI && page.AIProcessedText != "" {
+			text = page.AIProcessedText
+		}
+		texts[page.PageNumber] = text
+	}
+	return texts
+}
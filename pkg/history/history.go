@@ -3,12 +3,17 @@ package history
 import (
 	"database/sql"
 	"fmt"
-	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/mattn/go-sqlite3"
+
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/migrate"
+	"pdf-ocr-ai/pkg/storage"
 )
 
 // ProcessingStatus 处理状态
@@ -19,32 +24,66 @@ const (
 	StatusCompleted  ProcessingStatus = "completed"
 	StatusFailed     ProcessingStatus = "failed"
 	StatusCancelled  ProcessingStatus = "cancelled"
+	StatusPaused     ProcessingStatus = "paused"  // 批量处理已暂停
+	StatusPartial    ProcessingStatus = "partial" // 部分页面处理失败
+)
+
+// TaskType 任务类型
+type TaskType string
+
+const (
+	TaskTypeOCR TaskType = "ocr" // 图片文字识别
+	TaskTypeAI  TaskType = "ai"  // AI文本处理
 )
 
 // HistoryRecord 历史记录
 type HistoryRecord struct {
-	ID           int              `db:"id" json:"id"`
-	DocumentPath string           `db:"document_path" json:"document_path"`
-	DocumentName string           `db:"document_name" json:"document_name"`
-	PageCount    int              `db:"page_count" json:"page_count"`
-	Status       ProcessingStatus `db:"status" json:"status"`
-	AIModel      string           `db:"ai_model" json:"ai_model"`
-	Cost         float64          `db:"cost" json:"cost"`
-	ProcessedAt  string           `db:"processed_at" json:"processed_at"`
-	CompletedAt  *string          `db:"completed_at" json:"completed_at,omitempty"`
-	ErrorMessage *string          `db:"error_message" json:"error_message,omitempty"`
+	ID             int              `db:"id" json:"id"`
+	DocumentID     *string          `db:"document_id" json:"document_id,omitempty"` // 与缓存共用的文档标识
+	DocumentPath   string           `db:"document_path" json:"document_path"`
+	DocumentName   string           `db:"document_name" json:"document_name"`
+	PageCount      int              `db:"page_count" json:"page_count"`
+	Status         ProcessingStatus `db:"status" json:"status"`
+	TaskType       TaskType         `db:"task_type" json:"task_type"`
+	Model          string           `db:"model" json:"model"`
+	AIModel        string           `db:"ai_model" json:"ai_model"` // 已废弃，旧版本以 "AI-" 前缀区分任务类型，保留兼容
+	Cost           float64          `db:"cost" json:"cost"`
+	ProcessedAt    string           `db:"processed_at" json:"processed_at"`
+	CompletedAt    *string          `db:"completed_at" json:"completed_at,omitempty"`
+	ErrorMessage   *string          `db:"error_message" json:"error_message,omitempty"`
+	CompletedPages int              `db:"completed_pages" json:"completed_pages"`     // 处理成功的页数
+	FailedPages    int              `db:"failed_pages" json:"failed_pages"`           // 处理失败的页数
+	ArchivedAt     *string          `db:"archived_at" json:"archived_at,omitempty"`   // 页面已移入冷存储的时间
+	ArchiveFile    *string          `db:"archive_file" json:"archive_file,omitempty"` // 冷存储归档文件名
+
+	DurationSeconds float64 `db:"duration_seconds" json:"duration_seconds"` // 处理总耗时（秒）
+	AvgPageSeconds  float64 `db:"avg_page_seconds" json:"avg_page_seconds"` // 平均每页处理耗时（秒）
+	PagesPerMinute  float64 `db:"pages_per_minute" json:"pages_per_minute"` // 吞吐量（页/分钟）
+	TotalTokens     int     `db:"total_tokens" json:"total_tokens"`         // 处理期间的token用量
+}
+
+// TimingStats 处理结束时的耗时和用量统计
+type TimingStats struct {
+	DurationSeconds float64 `json:"duration_seconds"`
+	AvgPageSeconds  float64 `json:"avg_page_seconds"`
+	PagesPerMinute  float64 `json:"pages_per_minute"`
+	TotalTokens     int     `json:"total_tokens"`
+	Cost            float64 `json:"cost"` // 按模型价格估算的费用
 }
 
 // HistoryPage 历史页面
 type HistoryPage struct {
-	ID              int     `db:"id" json:"id"`
-	HistoryID       int     `db:"history_id" json:"history_id"`
-	PageNumber      int     `db:"page_number" json:"page_number"`
-	OriginalText    string  `db:"original_text" json:"original_text"`
-	OCRText         string  `db:"ocr_text" json:"ocr_text"`
-	AIProcessedText string  `db:"ai_processed_text" json:"ai_processed_text"`
-	ProcessingTime  float64 `db:"processing_time" json:"processing_time"` // 处理时间（秒）
-	CreatedAt       string  `db:"created_at" json:"created_at"`
+	ID              int        `db:"id" json:"id"`
+	HistoryID       int        `db:"history_id" json:"history_id"`
+	PageNumber      int        `db:"page_number" json:"page_number"`
+	OriginalText    string     `db:"original_text" json:"original_text"`
+	OCRText         string     `db:"ocr_text" json:"ocr_text"`
+	AIProcessedText string     `db:"ai_processed_text" json:"ai_processed_text"`
+	ProcessingTime  float64    `db:"processing_time" json:"processing_time"` // 处理时间（秒）
+	CreatedAt       string     `db:"created_at" json:"created_at"`
+	Status          PageStatus `db:"status" json:"status"` // 为空时按 done 保存
+	ErrorMessage    *string    `db:"error_message" json:"error_message,omitempty"`
+	RenderFallback  bool       `db:"render_fallback" json:"render_fallback"` // 渲染时使用了备用方案
 }
 
 // SearchResult 搜索结果
@@ -57,48 +96,84 @@ type SearchResult struct {
 	ProcessedAt  string `json:"processed_at"`
 }
 
+// HistoryFilter 历史记录查询条件，空值表示不过滤
+type HistoryFilter struct {
+	Page     int              `json:"page"`      // 页码，从1开始
+	PageSize int              `json:"page_size"` // 每页条数，默认50
+	DateFrom string           `json:"date_from"` // 起始日期（本地时间），YYYY-MM-DD 或 YYYY-MM-DD HH:MM:SS
+	DateTo   string           `json:"date_to"`   // 截止日期（本地时间，含当天）
+	Status   ProcessingStatus `json:"status"`
+	Model    string           `json:"model"`
+	TaskType TaskType         `json:"task_type"`
+	Document string           `json:"document"` // 匹配文档名或路径
+}
+
+// HistoryListResult 分页查询结果
+type HistoryListResult struct {
+	Records    []*HistoryRecord `json:"records"`
+	Total      int              `json:"total"`
+	Page       int              `json:"page"`
+	PageSize   int              `json:"page_size"`
+	TotalPages int              `json:"total_pages"`
+}
+
+// searchIndexSQL 全文搜索表，trigram分词支持中文子串匹配
+const searchIndexSQL = `
+CREATE VIRTUAL TABLE IF NOT EXISTS history_search USING fts5(
+	history_id UNINDEXED,
+	document_path UNINDEXED,
+	document_name UNINDEXED,
+	ocr_text,
+	ai_processed_text,
+	tokenize = 'trigram'
+);`
+
+const (
+	defaultHistoryPageSize = 50
+	maxHistoryPageSize     = 500
+)
+
 // HistoryManager 历史记录管理器
 type HistoryManager struct {
 	db         *sqlx.DB
-	ftsEnabled bool // 是否支持FTS5
+	store      *storage.Store // 文本字段加解密
+	ftsEnabled bool           // 是否支持FTS5
+	archiveDir string         // 冷存储归档目录
 }
 
-// NewHistoryManager 创建历史记录管理器
-func NewHistoryManager() (*HistoryManager, error) {
-	// 获取用户目录
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return nil, fmt.Errorf("获取用户目录失败: %w", err)
-	}
-
-	// 创建数据目录
-	dataDir := filepath.Join(homeDir, ".pdfSeer")
-	if err := os.MkdirAll(dataDir, 0755); err != nil {
-		return nil, fmt.Errorf("创建数据目录失败: %w", err)
-	}
-
-	// 连接数据库
-	dbPath := filepath.Join(dataDir, "history.db")
-	db, err := sqlx.Connect("sqlite3", dbPath+"?cache=shared&_journal_mode=WAL")
-	if err != nil {
-		return nil, fmt.Errorf("连接数据库失败: %w", err)
-	}
+// migrationComponent 历史记录表在统一数据库中的迁移组件名
+const migrationComponent = "history"
 
-	hm := &HistoryManager{db: db}
+// NewHistoryManager 创建历史记录管理器，使用统一数据库中的历史记录表
+func NewHistoryManager(store *storage.Store) (*HistoryManager, error) {
+	hm := &HistoryManager{db: store.DB(), store: store, archiveDir: filepath.Join(store.DataDir(), "archive")}
+	store.RegisterEncryptedColumns("history_pages", "original_text", "ocr_text", "ai_processed_text")
+	store.RegisterEncryptedColumns("history_archive_stubs", "ocr_excerpt", "ai_excerpt")
 
 	// 检测FTS5支持
 	hm.ftsEnabled = hm.checkFTS5Support()
 
-	// 初始化数据库表
-	if err := hm.initTables(); err != nil {
-		return nil, fmt.Errorf("初始化数据库表失败: %w", err)
-	}
-
 	// 运行数据库迁移
-	if err := hm.runMigrations(); err != nil {
+	if err := store.Migrate(migrationComponent, hm.migrations()); err != nil {
 		return nil, fmt.Errorf("运行数据库迁移失败: %w", err)
 	}
 
+	if err := hm.initSearchIndex(); err != nil {
+		return nil, err
+	}
+
+	// 导入旧版独立的 history.db
+	imported, err := store.ImportLegacy("history.db", migrationComponent, hm.migrations(),
+		[]string{"processing_history", "history_pages", "history_archive_stubs"})
+	if err != nil {
+		return nil, fmt.Errorf("导入旧版历史记录失败: %w", err)
+	}
+	if imported {
+		if err := hm.rebuildSearchIndex(); err != nil {
+			return nil, err
+		}
+	}
+
 	return hm, nil
 }
 
@@ -115,8 +190,8 @@ func (hm *HistoryManager) checkFTS5Support() bool {
 	return true
 }
 
-// initTables 初始化数据库表
-func (hm *HistoryManager) initTables() error {
+// createTables 创建基础表结构（版本1，已发布，后续的列由之后的迁移添加）
+func (hm *HistoryManager) createTables(tx *sqlx.Tx) error {
 	// 历史记录表
 	historySQL := `
 	CREATE TABLE IF NOT EXISTS processing_history (
@@ -156,92 +231,197 @@ func (hm *HistoryManager) initTables() error {
 
 	// 执行基础SQL
 	for _, sql := range []string{historySQL, pagesSQL, indexSQL} {
-		if _, err := hm.db.Exec(sql); err != nil {
+		if _, err := tx.Exec(sql); err != nil {
 			return fmt.Errorf("执行SQL失败: %w", err)
 		}
 	}
 
-	// 如果支持FTS5，创建全文搜索表
-	if hm.ftsEnabled {
-		ftsSQL := `
-		CREATE VIRTUAL TABLE IF NOT EXISTS history_search USING fts5(
-			history_id,
-			document_path,
-			document_name,
-			ocr_text,
-			ai_processed_text,
-			content='history_pages',
-			content_rowid='id'
-		);`
-
-		if _, err := hm.db.Exec(ftsSQL); err != nil {
-			// 如果FTS5表创建失败，禁用FTS功能但不返回错误
-			hm.ftsEnabled = false
+	return nil
+}
+
+// migrations 历史数据库的版本化迁移
+// 早期版本没有记录版本号，各步骤需兼容已手动迁移过的数据库（先检查再修改）
+func (hm *HistoryManager) migrations() []migrate.Migration {
+	return []migrate.Migration{
+		{Version: 1, Description: "创建历史记录表", Up: hm.createTables},
+		{Version: 2, Description: "添加任务类型和模型列", Up: hm.migrateTaskType},
+		{Version: 3, Description: "添加冷存储归档列", Up: hm.migrateArchiveColumns},
+		{Version: 4, Description: "支持 paused/partial 状态和页数统计", Up: hm.migrateStatusValues},
+		{Version: 5, Description: "添加页面处理状态", Up: hm.migratePageStatus},
+		{Version: 6, Description: "添加文档标识列", Up: migrate.SQL(
+			`ALTER TABLE processing_history ADD COLUMN document_id TEXT`,
+			`CREATE INDEX IF NOT EXISTS idx_history_document_id ON processing_history(document_id)`,
+		)},
+		{Version: 7, Description: "添加耗时和用量统计列", Up: migrate.SQL(
+			`ALTER TABLE processing_history ADD COLUMN duration_seconds REAL NOT NULL DEFAULT 0`,
+			`ALTER TABLE processing_history ADD COLUMN avg_page_seconds REAL NOT NULL DEFAULT 0`,
+			`ALTER TABLE processing_history ADD COLUMN pages_per_minute REAL NOT NULL DEFAULT 0`,
+			`ALTER TABLE processing_history ADD COLUMN total_tokens INTEGER NOT NULL DEFAULT 0`,
+		)},
+		{Version: 8, Description: "添加页面渲染备用方案标记", Up: migrate.SQL(
+			`ALTER TABLE history_pages ADD COLUMN render_fallback INTEGER NOT NULL DEFAULT 0`,
+		)},
+	}
+}
+
+// statusCheckPattern 匹配建表语句中的状态约束
+var statusCheckPattern = regexp.MustCompile(`CHECK\s*\(\s*status\s+IN\s*\([^)]*\)\s*\)`)
+
+// migrateStatusValues 旧表的状态约束只允许 processing/completed/failed（或 cancelled），
+// 写入其他状态时更新会失败，重建表以支持 paused/partial，并添加成功/失败页数列
+func (hm *HistoryManager) migrateStatusValues(tx *sqlx.Tx) error {
+	var tableSQL string
+	if err := tx.Get(&tableSQL, "SELECT sql FROM sqlite_master WHERE type = 'table' AND name = 'processing_history'"); err != nil {
+		return fmt.Errorf("读取表结构失败: %w", err)
+	}
+	if strings.Contains(tableSQL, "'partial'") {
+		return nil
+	}
+
+	// 保留现有的所有列（包括后续迁移添加的列），只替换状态约束
+	newSQL := statusCheckPattern.ReplaceAllString(tableSQL,
+		"CHECK(status IN ('processing', 'completed', 'failed', 'cancelled', 'paused', 'partial'))")
+	newSQL = regexp.MustCompile(`^CREATE TABLE\s+"?processing_history"?`).ReplaceAllString(newSQL, "CREATE TABLE processing_history_new")
+	if !strings.HasPrefix(newSQL, "CREATE TABLE processing_history_new") {
+		return fmt.Errorf("无法识别的表结构: %s", tableSQL)
+	}
+
+	statements := []string{
+		newSQL,
+		`INSERT INTO processing_history_new SELECT * FROM processing_history`,
+		`DROP TABLE processing_history`,
+		`ALTER TABLE processing_history_new RENAME TO processing_history`,
+		`ALTER TABLE processing_history ADD COLUMN completed_pages INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE processing_history ADD COLUMN failed_pages INTEGER NOT NULL DEFAULT 0`,
+		`UPDATE processing_history SET completed_pages = (SELECT COUNT(*) FROM history_pages WHERE history_id = processing_history.id)`,
+		`CREATE INDEX IF NOT EXISTS idx_history_status ON processing_history(status)`,
+		`CREATE INDEX IF NOT EXISTS idx_history_date ON processing_history(processed_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_history_task_type ON processing_history(task_type)`,
+	}
+
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("执行迁移语句失败: %w", err)
 		}
 	}
 
 	return nil
 }
 
-// runMigrations 运行数据库迁移
-func (hm *HistoryManager) runMigrations() error {
-	// 检查是否需要添加 cancelled 状态支持
-	var count int
-	err := hm.db.Get(&count, "SELECT COUNT(*) FROM processing_history WHERE status = 'cancelled'")
-	if err != nil {
-		// 如果查询失败，说明可能需要更新表结构
-		// 尝试更新表结构以支持 cancelled 状态
+// migrateTaskType 添加 task_type 和 model 列，并从旧记录的 "AI-" 模型前缀迁移
+func (hm *HistoryManager) migrateTaskType(tx *sqlx.Tx) error {
+	var columns []struct {
+		Name string `db:"name"`
+	}
+	if err := tx.Select(&columns, "SELECT name FROM pragma_table_info('processing_history')"); err != nil {
+		return fmt.Errorf("读取表结构失败: %w", err)
+	}
+	for _, column := range columns {
+		if column.Name == "task_type" {
+			return nil
+		}
+	}
 
-		// 在事务中执行迁移
-		tx, err := hm.db.Beginx()
-		if err != nil {
-			return err
+	statements := []string{
+		`ALTER TABLE processing_history ADD COLUMN task_type TEXT NOT NULL DEFAULT 'ocr'`,
+		`ALTER TABLE processing_history ADD COLUMN model TEXT NOT NULL DEFAULT ''`,
+		`UPDATE processing_history SET task_type = 'ai', model = substr(ai_model, 4) WHERE ai_model LIKE 'AI-%'`,
+		`UPDATE processing_history SET model = COALESCE(ai_model, '') WHERE task_type = 'ocr'`,
+		`CREATE INDEX IF NOT EXISTS idx_history_task_type ON processing_history(task_type)`,
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("执行迁移语句失败: %w", err)
 		}
-		defer tx.Rollback()
-
-		// 分别执行每个SQL语句
-		statements := []string{
-			`CREATE TABLE processing_history_new (
-				id INTEGER PRIMARY KEY AUTOINCREMENT,
-				document_path TEXT NOT NULL,
-				document_name TEXT NOT NULL,
-				page_count INTEGER NOT NULL,
-				status TEXT CHECK(status IN ('processing', 'completed', 'failed', 'cancelled')) DEFAULT 'processing',
-				ai_model TEXT,
-				cost REAL DEFAULT 0,
-				processed_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-				completed_at DATETIME,
-				error_message TEXT
-			)`,
-			`INSERT INTO processing_history_new SELECT * FROM processing_history`,
-			`DROP TABLE processing_history`,
-			`ALTER TABLE processing_history_new RENAME TO processing_history`,
-			`CREATE INDEX IF NOT EXISTS idx_history_status ON processing_history(status)`,
-			`CREATE INDEX IF NOT EXISTS idx_history_date ON processing_history(processed_at)`,
+	}
+
+	return nil
+}
+
+// initSearchIndex 创建全文搜索表（取决于编译时是否支持FTS5，不属于版本化迁移）
+// 旧版搜索表使用 history_pages 作为外部内容表，但列不匹配导致无法写入，重建为独立的trigram索引
+func (hm *HistoryManager) initSearchIndex() error {
+	if !hm.ftsEnabled {
+		return nil
+	}
+
+	if _, err := hm.db.Exec(searchIndexSQL); err != nil {
+		// 如果FTS5表创建失败，禁用FTS功能但不返回错误
+		hm.ftsEnabled = false
+		return nil
+	}
+
+	var tableSQL string
+	if err := hm.db.Get(&tableSQL, "SELECT sql FROM sqlite_master WHERE name = 'history_search'"); err != nil {
+		return fmt.Errorf("读取搜索表结构失败: %w", err)
+	}
+	if !strings.Contains(tableSQL, "content=") {
+		return nil
+	}
+
+	for _, stmt := range []string{`DROP TABLE history_search`, searchIndexSQL} {
+		if _, err := hm.db.Exec(stmt); err != nil {
+			return fmt.Errorf("重建搜索索引失败: %w", err)
 		}
+	}
+
+	return hm.rebuildSearchIndex()
+}
+
+// searchIndexEnabled 是否维护全文搜索索引（启用加密时不建立索引，避免明文留在索引中）
+func (hm *HistoryManager) searchIndexEnabled() bool {
+	return hm.ftsEnabled && !hm.store.EncryptionEnabled()
+}
 
-		for _, stmt := range statements {
-			if _, err := tx.Exec(stmt); err != nil {
-				return fmt.Errorf("执行迁移语句失败: %w", err)
-			}
+// RefreshSearchIndex 加密状态变化后重建全文搜索索引（启用加密时清空索引）
+func (hm *HistoryManager) RefreshSearchIndex() error {
+	return hm.rebuildSearchIndex()
+}
+
+// rebuildSearchIndex 根据页面表重新生成全文搜索索引
+func (hm *HistoryManager) rebuildSearchIndex() error {
+	if !hm.ftsEnabled {
+		return nil
+	}
+	if !hm.searchIndexEnabled() {
+		if _, err := hm.db.Exec(`DELETE FROM history_search`); err != nil {
+			return fmt.Errorf("清空搜索索引失败: %w", err)
 		}
+		return nil
+	}
 
-		return tx.Commit()
+	statements := []string{
+		`DELETE FROM history_search`,
+		`INSERT INTO history_search (rowid, history_id, document_path, document_name, ocr_text, ai_processed_text)
+		SELECT hp.id, ph.id, ph.document_path, ph.document_name, hp.ocr_text, hp.ai_processed_text
+		FROM history_pages hp
+		JOIN processing_history ph ON hp.history_id = ph.id
+		WHERE hp.` + contentPageCondition,
+	}
+	for _, stmt := range statements {
+		if _, err := hm.db.Exec(stmt); err != nil {
+			return fmt.Errorf("重建搜索索引失败: %w", err)
+		}
 	}
 
 	return nil
 }
 
-// CreateRecord 创建历史记录
-func (hm *HistoryManager) CreateRecord(documentPath string, pageCount int, aiModel string) (*HistoryRecord, error) {
+// CreateRecord 创建历史记录，documentID 与缓存中的文档标识一致（为空表示未知）
+func (hm *HistoryManager) CreateRecord(documentID, documentPath string, pageCount int, taskType TaskType, model string) (*HistoryRecord, error) {
 	documentName := filepath.Base(documentPath)
 
+	var documentIDPtr *string
+	if documentID != "" {
+		documentIDPtr = &documentID
+	}
+
 	query := `
-	INSERT INTO processing_history (document_path, document_name, page_count, ai_model)
-	VALUES (?, ?, ?, ?)
+	INSERT INTO processing_history (document_id, document_path, document_name, page_count, task_type, model, ai_model)
+	VALUES (?, ?, ?, ?, ?, ?, ?)
 	`
 
-	result, err := hm.db.Exec(query, documentPath, documentName, pageCount, aiModel)
+	result, err := hm.db.Exec(query, documentIDPtr, documentPath, documentName, pageCount, taskType, model, model)
 	if err != nil {
 		return nil, fmt.Errorf("创建历史记录失败: %w", err)
 	}
@@ -271,7 +451,7 @@ func (hm *HistoryManager) GetRecord(id int) (*HistoryRecord, error) {
 func (hm *HistoryManager) UpdateRecordStatus(id int, status ProcessingStatus, errorMsg string) error {
 	query := `
 	UPDATE processing_history
-	SET status = ?, error_message = ?, completed_at = CASE WHEN ? = 'completed' THEN CURRENT_TIMESTAMP ELSE completed_at END
+	SET status = ?, error_message = ?, completed_at = CASE WHEN ? IN ('completed', 'partial') THEN CURRENT_TIMESTAMP ELSE completed_at END
 	WHERE id = ?
 	`
 
@@ -284,34 +464,100 @@ func (hm *HistoryManager) UpdateRecordStatus(id int, status ProcessingStatus, er
 	return err
 }
 
-// AddPage 添加页面记录
-func (hm *HistoryManager) AddPage(page *HistoryPage) error {
-	query := `
-	INSERT OR REPLACE INTO history_pages 
-	(history_id, page_number, original_text, ocr_text, ai_processed_text, processing_time)
-	VALUES (?, ?, ?, ?, ?, ?)
-	`
+// FinishRecord 按成功和失败页数结束记录：全部成功为 completed，全部失败为 failed，部分失败为 partial；
+// cancelled 为 true 时标记为 cancelled，并保留已处理的页数
+func (hm *HistoryManager) FinishRecord(id, completedPages, failedPages int, cancelled bool) (ProcessingStatus, error) {
+	// 结束前提交本次运行尚在队列中的页面
+	hm.sync()
+
+	status := StatusCompleted
+	errorMsg := ""
+	switch {
+	case cancelled:
+		status = StatusCancelled
+		errorMsg = "处理被用户取消"
+	case completedPages == 0 && failedPages > 0:
+		status = StatusFailed
+		errorMsg = "所有页面处理失败"
+	case failedPages > 0:
+		status = StatusPartial
+		errorMsg = fmt.Sprintf("%d 页处理失败", failedPages)
+	}
 
-	_, err := hm.db.Exec(query, page.HistoryID, page.PageNumber,
-		page.OriginalText, page.OCRText, page.AIProcessedText, page.ProcessingTime)
+	if err := hm.UpdateRecordStatus(id, status, errorMsg); err != nil {
+		return status, err
+	}
+
+	_, err := hm.db.Exec("UPDATE processing_history SET completed_pages = ?, failed_pages = ? WHERE id = ?", completedPages, failedPages, id)
+	return status, err
+}
 
+// SaveTimingStats 保存记录的耗时和用量统计
+func (hm *HistoryManager) SaveTimingStats(id int, stats TimingStats) error {
+	_, err := hm.db.Exec(`
+	UPDATE processing_history SET duration_seconds = ?, avg_page_seconds = ?, pages_per_minute = ?, total_tokens = ?, cost = ? WHERE id = ?
+	`, stats.DurationSeconds, stats.AvgPageSeconds, stats.PagesPerMinute, stats.TotalTokens, stats.Cost, id)
 	if err != nil {
-		return err
+		return fmt.Errorf("保存耗时统计失败: %w", err)
 	}
+	return nil
+}
 
-	// 更新全文搜索索引（如果支持FTS5）
-	if hm.ftsEnabled {
-		return hm.updateSearchIndex(page.HistoryID, page.PageNumber)
+// AddPage 添加页面记录（启用加密时文本字段加密保存）
+// 写入加入延迟写入队列，与同批页面的缓存和状态一起提交，返回nil只表示已入队；提交失败记录日志，并由 Store.SyncErr 汇报
+func (hm *HistoryManager) AddPage(page *HistoryPage) error {
+	originalText, ocrText, aiText := page.OriginalText, page.OCRText, page.AIProcessedText
+	if err := hm.store.EncryptFields(&originalText, &ocrText, &aiText); err != nil {
+		return err
 	}
 
+	query := `
+	INSERT OR REPLACE INTO history_pages 
+	(history_id, page_number, original_text, ocr_text, ai_processed_text, processing_time, status, render_fallback)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	historyID, pageNumber := page.HistoryID, page.PageNumber
+	processingTime, status, renderFallback := page.ProcessingTime, pageStatusOrDone(page.Status), page.RenderFallback
+	hm.store.Writer().Enqueue(func(tx *sqlx.Tx) error {
+		_, err := tx.Exec(query, historyID, pageNumber,
+			originalText, ocrText, aiText, processingTime, status, renderFallback)
+		if err != nil {
+			return err
+		}
+
+		// 更新全文搜索索引（如果支持FTS5）
+		if hm.searchIndexEnabled() {
+			return hm.updateSearchIndex(tx, historyID, pageNumber)
+		}
+		return nil
+	}, func(err error) {
+		if err != nil {
+			logger.Errorf("保存历史页面失败 (记录 %d 第 %d 页): %v", historyID, pageNumber, err)
+		}
+	})
+
 	return nil
 }
 
-// updateSearchIndex 更新搜索索引
-func (hm *HistoryManager) updateSearchIndex(historyID int, pageNumber int) error {
+// sync 读取页面前提交延迟写入队列中的页面内容和状态
+func (hm *HistoryManager) sync() {
+	hm.store.Sync()
+}
+
+// updateSearchIndex 更新搜索索引（rowid 与 history_pages.id 一致）
+func (hm *HistoryManager) updateSearchIndex(exec sqlx.Execer, historyID int, pageNumber int) error {
+	_, err := exec.Exec(`
+	DELETE FROM history_search
+	WHERE rowid = (SELECT id FROM history_pages WHERE history_id = ? AND page_number = ?)
+	`, historyID, pageNumber)
+	if err != nil {
+		return err
+	}
+
 	query := `
-	INSERT OR REPLACE INTO history_search 
-	SELECT 
+	INSERT INTO history_search (rowid, history_id, document_path, document_name, ocr_text, ai_processed_text)
+	SELECT
 		hp.id,
 		ph.id,
 		ph.document_path,
@@ -323,7 +569,7 @@ func (hm *HistoryManager) updateSearchIndex(historyID int, pageNumber int) error
 	WHERE hp.history_id = ? AND hp.page_number = ?
 	`
 
-	_, err := hm.db.Exec(query, historyID, pageNumber)
+	_, err = exec.Exec(query, historyID, pageNumber)
 	return err
 }
 
@@ -340,12 +586,109 @@ func (hm *HistoryManager) GetRecentRecords(limit int) ([]*HistoryRecord, error)
 	return records, err
 }
 
+// whereClause 根据查询条件生成 WHERE 子句（忽略分页参数）
+func (filter HistoryFilter) whereClause() (string, []interface{}, error) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.DateFrom != "" {
+		conditions = append(conditions, "datetime(processed_at, 'localtime') >= ?")
+		args = append(args, filter.DateFrom)
+	}
+	if filter.DateTo != "" {
+		dateTo := filter.DateTo
+		if len(dateTo) == len("2006-01-02") {
+			dateTo += " 23:59:59"
+		}
+		conditions = append(conditions, "datetime(processed_at, 'localtime') <= ?")
+		args = append(args, dateTo)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.Model != "" {
+		conditions = append(conditions, "model = ?")
+		args = append(args, filter.Model)
+	}
+	switch filter.TaskType {
+	case "":
+	case TaskTypeOCR, TaskTypeAI:
+		conditions = append(conditions, "task_type = ?")
+		args = append(args, filter.TaskType)
+	default:
+		return "", nil, fmt.Errorf("不支持的任务类型: %s", filter.TaskType)
+	}
+	if filter.Document != "" {
+		conditions = append(conditions, "(document_name LIKE ? OR document_path LIKE ?)")
+		pattern := "%" + filter.Document + "%"
+		args = append(args, pattern, pattern)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil, nil
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args, nil
+}
+
+// ListRecords 按条件分页查询历史记录
+func (hm *HistoryManager) ListRecords(filter HistoryFilter) (*HistoryListResult, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 {
+		filter.PageSize = defaultHistoryPageSize
+	}
+	if filter.PageSize > maxHistoryPageSize {
+		filter.PageSize = maxHistoryPageSize
+	}
+
+	where, args, err := filter.whereClause()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &HistoryListResult{
+		Records:  []*HistoryRecord{},
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+	}
+
+	if err := hm.db.Get(&result.Total, "SELECT COUNT(*) FROM processing_history "+where, args...); err != nil {
+		return nil, fmt.Errorf("统计历史记录失败: %w", err)
+	}
+	result.TotalPages = (result.Total + filter.PageSize - 1) / filter.PageSize
+
+	query := "SELECT * FROM processing_history " + where + " ORDER BY processed_at DESC, id DESC LIMIT ? OFFSET ?"
+	args = append(args, filter.PageSize, (filter.Page-1)*filter.PageSize)
+	if err := hm.db.Select(&result.Records, query, args...); err != nil {
+		return nil, fmt.Errorf("查询历史记录失败: %w", err)
+	}
+
+	return result, nil
+}
+
 // GetRecordPages 获取记录的所有页面
 func (hm *HistoryManager) GetRecordPages(historyID int) ([]*HistoryPage, error) {
+	pages, err := hm.recordPages(historyID)
+	if err != nil {
+		return nil, err
+	}
+	return pages, hm.decryptPages(pages)
+}
+
+// recordPages 获取记录的所有页面，文本字段保持数据库中的形式（启用加密时为密文）
+func (hm *HistoryManager) recordPages(historyID int) ([]*HistoryPage, error) {
+	// 已归档的记录先从冷存储恢复
+	if err := hm.rehydrateIfArchived(historyID); err != nil {
+		return nil, err
+	}
+	hm.sync()
+
 	var pages []*HistoryPage
 	query := `
 	SELECT * FROM history_pages
-	WHERE history_id = ?
+	WHERE history_id = ? AND ` + contentPageCondition + `
 	ORDER BY page_number
 	`
 
@@ -355,16 +698,78 @@ func (hm *HistoryManager) GetRecordPages(historyID int) ([]*HistoryPage, error)
 
 // GetDocumentPages 获取文档所有历史记录的页面数据
 func (hm *HistoryManager) GetDocumentPages(documentPath string) ([]*HistoryPage, error) {
+	hm.sync()
+	var archivedIDs []int
+	if err := hm.db.Select(&archivedIDs, "SELECT id FROM processing_history WHERE document_path = ? AND archived_at IS NOT NULL", documentPath); err != nil {
+		return nil, err
+	}
+	for _, id := range archivedIDs {
+		if err := hm.RestoreArchivedRecord(id); err != nil {
+			return nil, err
+		}
+	}
+
 	var pages []*HistoryPage
 	query := `
 	SELECT hp.* FROM history_pages hp
 	JOIN processing_history ph ON hp.history_id = ph.id
-	WHERE ph.document_path = ?
+	WHERE ph.document_path = ? AND hp.` + contentPageCondition + `
 	ORDER BY hp.page_number
 	`
 
-	err := hm.db.Select(&pages, query, documentPath)
-	return pages, err
+	if err := hm.db.Select(&pages, query, documentPath); err != nil {
+		return nil, err
+	}
+	return pages, hm.decryptPages(pages)
+}
+
+// decryptPages 解密页面的文本字段
+func (hm *HistoryManager) decryptPages(pages []*HistoryPage) error {
+	for _, page := range pages {
+		if err := hm.store.DecryptFields(&page.OriginalText, &page.OCRText, &page.AIProcessedText); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CountDocumentRecords 统计使用同一文档的历史记录数，按文档标识匹配，没有标识时按路径匹配
+func (hm *HistoryManager) CountDocumentRecords(documentID, documentPath string) (int, error) {
+	var count int
+	err := hm.db.Get(&count, `
+	SELECT COUNT(*) FROM processing_history
+	WHERE (document_id IS NOT NULL AND document_id = ?) OR (document_id IS NULL AND document_path = ?)
+	`, documentID, documentPath)
+	return count, err
+}
+
+// RelocateDocument 文件移动后将历史记录关联到新路径，documentID 不为空时同时补全文档标识，返回更新的记录数
+func (hm *HistoryManager) RelocateDocument(oldPath, newPath, documentID string) (int, error) {
+	tx, err := hm.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+	UPDATE processing_history
+	SET document_path = ?, document_name = ?, document_id = COALESCE(document_id, NULLIF(?, ''))
+	WHERE document_path = ?
+	`, newPath, filepath.Base(newPath), documentID, oldPath)
+	if err != nil {
+		return 0, fmt.Errorf("更新历史记录路径失败: %w", err)
+	}
+	updated, _ := result.RowsAffected()
+
+	if hm.ftsEnabled {
+		_, err := tx.Exec("UPDATE history_search SET document_path = ?, document_name = ? WHERE document_path = ?",
+			newPath, filepath.Base(newPath), oldPath)
+		if err != nil {
+			return 0, fmt.Errorf("更新搜索索引失败: %w", err)
+		}
+	}
+
+	return int(updated), tx.Commit()
 }
 
 // GetRecordsByDocumentPath 获取指定文档路径的所有历史记录
@@ -385,59 +790,34 @@ func (hm *HistoryManager) GetPages(historyID int) ([]*HistoryPage, error) {
 	return hm.GetRecordPages(historyID)
 }
 
-// SearchContent 搜索内容
+// SearchContent 搜索内容（简单关键词搜索，保持兼容）
 func (hm *HistoryManager) SearchContent(keyword string, limit int) ([]*SearchResult, error) {
-	var results []*SearchResult
-
-	if hm.ftsEnabled {
-		// 使用FTS5搜索
-		query := `
-		SELECT
-			hs.history_id,
-			hs.document_path,
-			hs.document_name,
-			hp.page_number,
-			snippet(history_search, 3, '<mark>', '</mark>', '...', 32) as snippet,
-			ph.processed_at
-		FROM history_search hs
-		JOIN history_pages hp ON hs.rowid = hp.id
-		JOIN processing_history ph ON hs.history_id = ph.id
-		WHERE history_search MATCH ?
-		ORDER BY rank
-		LIMIT ?
-		`
-
-		err := hm.db.Select(&results, query, keyword, limit)
-		return results, err
-	} else {
-		// 使用普通LIKE搜索作为后备方案
-		query := `
-		SELECT
-			ph.id as history_id,
-			ph.document_path,
-			ph.document_name,
-			hp.page_number,
-			CASE
-				WHEN hp.ocr_text LIKE ? THEN substr(hp.ocr_text, 1, 100) || '...'
-				WHEN hp.ai_processed_text LIKE ? THEN substr(hp.ai_processed_text, 1, 100) || '...'
-				ELSE '...'
-			END as snippet,
-			ph.processed_at
-		FROM history_pages hp
-		JOIN processing_history ph ON hp.history_id = ph.id
-		WHERE hp.ocr_text LIKE ? OR hp.ai_processed_text LIKE ?
-		ORDER BY ph.processed_at DESC
-		LIMIT ?
-		`
+	page, err := hm.Search(SearchOptions{
+		Query:  keyword,
+		Sort:   SearchSortRelevance,
+		Filter: HistoryFilter{PageSize: limit},
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		searchPattern := "%" + keyword + "%"
-		err := hm.db.Select(&results, query, searchPattern, searchPattern, searchPattern, searchPattern, limit)
-		return results, err
+	results := make([]*SearchResult, 0, len(page.Hits))
+	for _, hit := range page.Hits {
+		results = append(results, &SearchResult{
+			HistoryID:    hit.HistoryID,
+			DocumentPath: hit.DocumentPath,
+			DocumentName: hit.DocumentName,
+			PageNumber:   hit.PageNumber,
+			Snippet:      hit.markedSnippet(),
+			ProcessedAt:  hit.ProcessedAt,
+		})
 	}
+	return results, nil
 }
 
 // DeleteRecord 删除记录
 func (hm *HistoryManager) DeleteRecord(id int) error {
+	hm.sync()
 	tx, err := hm.db.Beginx()
 	if err != nil {
 		return err
@@ -455,6 +835,9 @@ func (hm *HistoryManager) DeleteRecord(id int) error {
 	if _, err := tx.Exec("DELETE FROM history_pages WHERE history_id = ?", id); err != nil {
 		return err
 	}
+	if _, err := tx.Exec("DELETE FROM history_archive_stubs WHERE history_id = ?", id); err != nil {
+		return err
+	}
 
 	// 删除记录
 	if _, err := tx.Exec("DELETE FROM processing_history WHERE id = ?", id); err != nil {
@@ -464,29 +847,26 @@ func (hm *HistoryManager) DeleteRecord(id int) error {
 	return tx.Commit()
 }
 
-// CleanupOldRecords 清理旧记录
-func (hm *HistoryManager) CleanupOldRecords(days int) error {
-	cutoff := time.Now().AddDate(0, 0, -days).Format("2006-01-02 15:04:05")
+// CleanupOldRecords 清理旧记录，返回删除的记录数
+func (hm *HistoryManager) CleanupOldRecords(days int) (int, error) {
+	// processed_at 为UTC时间
+	cutoff := time.Now().UTC().AddDate(0, 0, -days).Format("2006-01-02 15:04:05")
+	hm.sync()
 
 	// 获取要删除的记录ID
 	var recordIDs []int
 	err := hm.db.Select(&recordIDs,
 		"SELECT id FROM processing_history WHERE processed_at < ?", cutoff)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	// 删除每个记录
-	for _, id := range recordIDs {
+	for i, id := range recordIDs {
 		if err := hm.DeleteRecord(id); err != nil {
-			return err
+			return i, err
 		}
 	}
 
-	return nil
-}
-
-// Close 关闭数据库连接
-func (hm *HistoryManager) Close() error {
-	return hm.db.Close()
+	return len(recordIDs), nil
 }
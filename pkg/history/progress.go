@@ -0,0 +1,222 @@
+package history
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// PageStatus 页面处理状态
+type PageStatus string
+
+const (
+	PagePending      PageStatus = "pending"       // 等待处理
+	PageProcessing   PageStatus = "processing"    // 正在处理
+	PageDone         PageStatus = "done"          // 处理完成
+	PageFailed       PageStatus = "failed"        // 处理失败
+	PageSkippedCache PageStatus = "skipped_cache" // 使用缓存结果，未重新处理
+)
+
+// contentPageCondition 有处理结果的页面（等待、处理中和失败的页面没有文本）
+const contentPageCondition = "status IN ('done', 'skipped_cache')"
+
+// PageProgress 单页处理状态
+type PageProgress struct {
+	PageNumber   int        `db:"page_number" json:"page_number"`
+	Status       PageStatus `db:"status" json:"status"`
+	ErrorMessage *string    `db:"error_message" json:"error_message,omitempty"`
+}
+
+// RecordProgress 记录的逐页处理进度
+type RecordProgress struct {
+	HistoryID    int              `json:"history_id"`
+	Status       ProcessingStatus `json:"status"`
+	Total        int              `json:"total"`
+	Pending      int              `json:"pending"`
+	Processing   int              `json:"processing"`
+	Done         int              `json:"done"`
+	Failed       int              `json:"failed"`
+	SkippedCache int              `json:"skipped_cache"`
+	FailedPages  []int            `json:"failed_pages"`  // 处理失败的页码
+	PendingPages []int            `json:"pending_pages"` // 尚未完成的页码（等待中或中断时正在处理）
+	Pages        []PageProgress   `json:"pages"`
+}
+
+// migratePageStatus 为页面表添加处理状态列，已有页面视为处理完成
+func (hm *HistoryManager) migratePageStatus(tx *sqlx.Tx) error {
+	var count int
+	err := tx.Get(&count, "SELECT COUNT(*) FROM pragma_table_info('history_pages') WHERE name = 'status'")
+	if err != nil {
+		return fmt.Errorf("读取表结构失败: %w", err)
+	}
+
+	statements := []string{
+		`CREATE INDEX IF NOT EXISTS idx_pages_status ON history_pages(history_id, status)`,
+	}
+	if count == 0 {
+		statements = append([]string{
+			`ALTER TABLE history_pages ADD COLUMN status TEXT NOT NULL DEFAULT 'done'`,
+			`ALTER TABLE history_pages ADD COLUMN error_message TEXT`,
+		}, statements...)
+	}
+
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("执行迁移语句失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// InitRecordPages 为记录创建待处理的页面，已存在的页面保持不变
+func (hm *HistoryManager) InitRecordPages(historyID int, pageNumbers []int) error {
+	hm.sync()
+	tx, err := hm.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, pageNumber := range pageNumbers {
+		_, err := tx.Exec(`
+		INSERT OR IGNORE INTO history_pages (history_id, page_number, status)
+		VALUES (?, ?, ?)
+		`, historyID, pageNumber, PagePending)
+		if err != nil {
+			return fmt.Errorf("创建页面状态失败: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// SetPageStatus 更新页面处理状态（不修改页面内容），页面不存在时创建
+// 与 AddPage 使用同一个延迟写入队列，保证同一页面的状态和内容按调用顺序写入
+func (hm *HistoryManager) SetPageStatus(historyID, pageNumber int, status PageStatus, errorMsg string) error {
+	var errorMsgPtr *string
+	if errorMsg != "" {
+		errorMsgPtr = &errorMsg
+	}
+
+	hm.store.Writer().Enqueue(func(tx *sqlx.Tx) error {
+		_, err := tx.Exec(`
+		INSERT INTO history_pages (history_id, page_number, status, error_message)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(history_id, page_number) DO UPDATE SET status = excluded.status, error_message = excluded.error_message
+		`, historyID, pageNumber, status, errorMsgPtr)
+		return err
+	}, func(err error) {
+		if err != nil {
+			logger.Errorf("更新页面状态失败 (记录 %d 第 %d 页): %v", historyID, pageNumber, err)
+		}
+	})
+	return nil
+}
+
+// LatestPageStatuses 获取文档各页在最近一次处理（跳过仍在等待的页面）中的状态，一次查询所有记录
+func (hm *HistoryManager) LatestPageStatuses(documentPath string) (map[int]PageStatus, error) {
+	hm.sync()
+	var rows []PageProgress
+	err := hm.db.Select(&rows, `
+	SELECT page_number, status, NULL AS error_message FROM (
+		SELECT p.page_number, p.status, h.processed_at, h.id
+		FROM history_pages p JOIN processing_history h ON h.id = p.history_id
+		WHERE h.document_path = ? AND h.archived_at IS NULL AND p.status != 'pending'
+		UNION ALL
+		SELECT s.page_number, 'done', h.processed_at, h.id
+		FROM history_archive_stubs s JOIN processing_history h ON h.id = s.history_id
+		WHERE h.document_path = ? AND h.archived_at IS NOT NULL
+	)
+	ORDER BY processed_at DESC, id DESC
+	`, documentPath, documentPath)
+	if err != nil {
+		return nil, fmt.Errorf("获取页面状态失败: %w", err)
+	}
+
+	statuses := make(map[int]PageStatus)
+	for _, row := range rows {
+		if _, ok := statuses[row.PageNumber]; !ok {
+			statuses[row.PageNumber] = row.Status
+		}
+	}
+	return statuses, nil
+}
+
+// GetRecordProgress 获取记录的逐页处理进度
+func (hm *HistoryManager) GetRecordProgress(historyID int) (*RecordProgress, error) {
+	hm.sync()
+	record, err := hm.GetRecord(historyID)
+	if err != nil {
+		return nil, fmt.Errorf("获取记录失败: %w", err)
+	}
+	if record == nil {
+		return nil, fmt.Errorf("历史记录不存在")
+	}
+
+	progress := &RecordProgress{
+		HistoryID:    historyID,
+		Status:       record.Status,
+		FailedPages:  []int{},
+		PendingPages: []int{},
+		Pages:        []PageProgress{},
+	}
+
+	// 已归档记录的页面不在数据库中，归档时只会包含已完成的页面
+	if record.ArchivedAt != nil {
+		err = hm.db.Select(&progress.Pages, `
+		SELECT page_number, 'done' AS status, NULL AS error_message
+		FROM history_archive_stubs WHERE history_id = ?
+		ORDER BY page_number
+		`, historyID)
+	} else {
+		err = hm.db.Select(&progress.Pages, `
+		SELECT page_number, status, error_message
+		FROM history_pages WHERE history_id = ?
+		ORDER BY page_number
+		`, historyID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("获取页面状态失败: %w", err)
+	}
+
+	for _, page := range progress.Pages {
+		switch page.Status {
+		case PagePending:
+			progress.Pending++
+			progress.PendingPages = append(progress.PendingPages, page.PageNumber)
+		case PageProcessing:
+			progress.Processing++
+			progress.PendingPages = append(progress.PendingPages, page.PageNumber)
+		case PageDone:
+			progress.Done++
+		case PageFailed:
+			progress.Failed++
+			progress.FailedPages = append(progress.FailedPages, page.PageNumber)
+		case PageSkippedCache:
+			progress.SkippedCache++
+		}
+	}
+	progress.Total = len(progress.Pages)
+
+	return progress, nil
+}
+
+// pageStatusOrDone 保存页面内容时的状态，未指定时为 done
+func pageStatusOrDone(status PageStatus) PageStatus {
+	if status == "" {
+		return PageDone
+	}
+	return status
+}
+
+// FinishRecordByPages 按记录中所有页面的状态统计成功和失败页数并结束记录
+// 用于重试失败页面后，记录的统计包含之前已完成的页面
+func (hm *HistoryManager) FinishRecordByPages(id int, cancelled bool) (ProcessingStatus, error) {
+	progress, err := hm.GetRecordProgress(id)
+	if err != nil {
+		return "", err
+	}
+	return hm.FinishRecord(id, progress.Done+progress.SkippedCache, progress.Failed, cancelled)
+}
@@ -0,0 +1,624 @@
+package history
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SearchField 搜索范围
+type SearchField string
+
+const (
+	SearchFieldAll SearchField = ""    // OCR结果和AI处理结果
+	SearchFieldOCR SearchField = "ocr" // 仅OCR结果
+	SearchFieldAI  SearchField = "ai"  // 仅AI处理结果
+)
+
+// SearchSort 排序方式
+type SearchSort string
+
+const (
+	SearchSortRelevance SearchSort = ""       // 按匹配次数，相同时按时间倒序
+	SearchSortNewest    SearchSort = "newest" // 按处理时间倒序
+	SearchSortOldest    SearchSort = "oldest" // 按处理时间正序
+)
+
+// snippetBefore/snippetAfter 片段中第一个匹配前后保留的字符数
+const (
+	snippetBefore = 40
+	snippetAfter  = 120
+)
+
+// SearchOptions 搜索选项
+// Query 语法：空格分隔的词同时匹配；"双引号" 精确匹配短语；OR 或 | 分隔多组条件；-词 或 NOT 词 排除
+type SearchOptions struct {
+	Query  string        `json:"query"`
+	Field  SearchField   `json:"field"`
+	Sort   SearchSort    `json:"sort"`
+	Filter HistoryFilter `json:"filter"` // 文档、日期、状态、模型、任务类型过滤和分页
+}
+
+// TextRange 高亮区间（按字符计算，左闭右开）
+type TextRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// SearchHit 单页搜索结果
+type SearchHit struct {
+	HistoryID    int         `json:"history_id"`
+	DocumentPath string      `json:"document_path"`
+	DocumentName string      `json:"document_name"`
+	TaskType     TaskType    `json:"task_type"`
+	PageNumber   int         `json:"page_number"`
+	ProcessedAt  string      `json:"processed_at"`
+	Field        SearchField `json:"field"`      // 片段来源字段：ocr 或 ai
+	Snippet      string      `json:"snippet"`    // 纯文本片段
+	Highlights   []TextRange `json:"highlights"` // 片段中匹配词的位置
+	Score        int         `json:"score"`      // 匹配次数
+	Archived     bool        `json:"archived"`   // 记录已归档，仅搜索了页面摘要
+}
+
+// SearchResultPage 分页搜索结果
+type SearchResultPage struct {
+	Hits       []*SearchHit `json:"hits"`
+	Total      int          `json:"total"`
+	Page       int          `json:"page"`
+	PageSize   int          `json:"page_size"`
+	TotalPages int          `json:"total_pages"`
+}
+
+// searchTerm 查询中的一个词或短语
+type searchTerm struct {
+	text   []rune // 已转为小写
+	negate bool
+}
+
+// searchCandidate 数据库中初步匹配的页面
+type searchCandidate struct {
+	HistoryID       int      `db:"history_id"`
+	DocumentPath    string   `db:"document_path"`
+	DocumentName    string   `db:"document_name"`
+	TaskType        TaskType `db:"task_type"`
+	PageNumber      int      `db:"page_number"`
+	ProcessedAt     string   `db:"processed_at"`
+	OCRText         string   `db:"ocr_text"`
+	AIProcessedText string   `db:"ai_processed_text"`
+	Archived        bool     `db:"archived"`
+}
+
+// Search 按查询语法和过滤条件搜索页面内容
+// 数据库只做初步筛选（FTS5 或 LIKE），匹配判断、排序、片段和高亮统一在内存中完成，两种方式结果一致
+func (hm *HistoryManager) Search(options SearchOptions) (*SearchResultPage, error) {
+	hm.sync()
+	groups, err := parseSearchQuery(options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := options.Filter
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 {
+		filter.PageSize = defaultHistoryPageSize
+	}
+	if filter.PageSize > maxHistoryPageSize {
+		filter.PageSize = maxHistoryPageSize
+	}
+
+	var fields []string
+	switch options.Field {
+	case SearchFieldAll:
+		fields = []string{"hp.ocr_text", "hp.ai_processed_text"}
+	case SearchFieldOCR:
+		fields = []string{"hp.ocr_text"}
+	case SearchFieldAI:
+		fields = []string{"hp.ai_processed_text"}
+	default:
+		return nil, fmt.Errorf("不支持的搜索范围: %s", options.Field)
+	}
+
+	candidates, err := hm.searchCandidates(groups, fields, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]*SearchHit, 0, len(candidates))
+	for _, candidate := range candidates {
+		if hit := matchCandidate(candidate, groups, options.Field); hit != nil {
+			hits = append(hits, hit)
+		}
+	}
+
+	switch options.Sort {
+	case SearchSortRelevance:
+		sort.SliceStable(hits, func(i, j int) bool {
+			if hits[i].Score != hits[j].Score {
+				return hits[i].Score > hits[j].Score
+			}
+			return hits[i].ProcessedAt > hits[j].ProcessedAt
+		})
+	case SearchSortNewest:
+		sort.SliceStable(hits, func(i, j int) bool { return hits[i].ProcessedAt > hits[j].ProcessedAt })
+	case SearchSortOldest:
+		sort.SliceStable(hits, func(i, j int) bool { return hits[i].ProcessedAt < hits[j].ProcessedAt })
+	default:
+		return nil, fmt.Errorf("不支持的排序方式: %s", options.Sort)
+	}
+
+	result := &SearchResultPage{
+		Hits:       []*SearchHit{},
+		Total:      len(hits),
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		TotalPages: (len(hits) + filter.PageSize - 1) / filter.PageSize,
+	}
+	start := (filter.Page - 1) * filter.PageSize
+	if start < len(hits) {
+		end := start + filter.PageSize
+		if end > len(hits) {
+			end = len(hits)
+		}
+		result.Hits = hits[start:end]
+	}
+
+	return result, nil
+}
+
+// searchCandidates 查询可能匹配的页面
+func (hm *HistoryManager) searchCandidates(groups [][]searchTerm, fields []string, filter HistoryFilter) ([]searchCandidate, error) {
+	filter.Page, filter.PageSize = 0, 0
+	where, args, err := filter.whereClause()
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+	SELECT
+		ph.id AS history_id,
+		ph.document_path,
+		ph.document_name,
+		ph.task_type,
+		ph.processed_at,
+		hp.page_number,
+		COALESCE(hp.ocr_text, '') AS ocr_text,
+		COALESCE(hp.ai_processed_text, '') AS ai_processed_text
+	FROM history_pages hp
+	JOIN processing_history ph ON hp.history_id = ph.id
+	`
+
+	conditions := []string{"hp." + contentPageCondition}
+	if where != "" {
+		conditions = append(conditions, strings.TrimPrefix(where, "WHERE "))
+	}
+
+	switch match, ok := buildFTSQuery(groups, fields); {
+	case hm.store.EncryptionEnabled():
+		// 密文无法在数据库中筛选，解密后统一在内存中匹配
+	case ok && hm.ftsEnabled:
+		conditions = append(conditions, "hp.id IN (SELECT rowid FROM history_search WHERE history_search MATCH ?)")
+		args = append(args, match)
+	default:
+		condition, likeArgs := buildLikeCondition(groups, fields)
+		conditions = append(conditions, condition)
+		args = append(args, likeArgs...)
+	}
+
+	query += "WHERE " + strings.Join(conditions, " AND ")
+
+	var candidates []searchCandidate
+	if err := hm.db.Select(&candidates, query, args...); err != nil {
+		return nil, fmt.Errorf("搜索历史记录失败: %w", err)
+	}
+	if err := hm.decryptCandidates(candidates); err != nil {
+		return nil, err
+	}
+
+	archived, err := hm.searchArchivedCandidates(groups, fields, filter)
+	if err != nil {
+		return nil, err
+	}
+	return append(candidates, archived...), nil
+}
+
+// searchArchivedCandidates 在已归档记录的页面摘要中查询（摘要不在FTS索引中，始终使用LIKE）
+func (hm *HistoryManager) searchArchivedCandidates(groups [][]searchTerm, fields []string, filter HistoryFilter) ([]searchCandidate, error) {
+	where, args, err := filter.whereClause()
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+	SELECT
+		ph.id AS history_id,
+		ph.document_path,
+		ph.document_name,
+		ph.task_type,
+		ph.processed_at,
+		hp.page_number,
+		COALESCE(hp.ocr_text, '') AS ocr_text,
+		COALESCE(hp.ai_processed_text, '') AS ai_processed_text,
+		1 AS archived
+	FROM (
+		SELECT history_id, page_number, ocr_excerpt AS ocr_text, ai_excerpt AS ai_processed_text
+		FROM history_archive_stubs
+	) hp
+	JOIN processing_history ph ON hp.history_id = ph.id
+	`
+
+	conditions := []string{"ph.archived_at IS NOT NULL"}
+	if where != "" {
+		conditions = append(conditions, strings.TrimPrefix(where, "WHERE "))
+	}
+	if !hm.store.EncryptionEnabled() {
+		condition, likeArgs := buildLikeCondition(groups, fields)
+		conditions = append(conditions, condition)
+		args = append(args, likeArgs...)
+	}
+
+	query += "WHERE " + strings.Join(conditions, " AND ")
+
+	var candidates []searchCandidate
+	if err := hm.db.Select(&candidates, query, args...); err != nil {
+		return nil, fmt.Errorf("搜索归档记录失败: %w", err)
+	}
+	if err := hm.decryptCandidates(candidates); err != nil {
+		return nil, err
+	}
+	return candidates, nil
+}
+
+// decryptCandidates 解密候选页面的文本
+func (hm *HistoryManager) decryptCandidates(candidates []searchCandidate) error {
+	for i := range candidates {
+		if err := hm.store.DecryptFields(&candidates[i].OCRText, &candidates[i].AIProcessedText); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildLikeCondition 将查询转换为 LIKE 条件
+func buildLikeCondition(groups [][]searchTerm, fields []string) (string, []interface{}) {
+	var args []interface{}
+	groupConditions := make([]string, 0, len(groups))
+
+	for _, group := range groups {
+		termConditions := make([]string, 0, len(group))
+		for _, term := range group {
+			pattern := "%" + escapeLike(string(term.text)) + "%"
+			parts := make([]string, 0, len(fields))
+			for _, field := range fields {
+				if term.negate {
+					parts = append(parts, fmt.Sprintf("COALESCE(%s, '') NOT LIKE ? ESCAPE '\\'", field))
+				} else {
+					parts = append(parts, fmt.Sprintf("%s LIKE ? ESCAPE '\\'", field))
+				}
+				args = append(args, pattern)
+			}
+			if term.negate {
+				termConditions = append(termConditions, "("+strings.Join(parts, " AND ")+")")
+			} else {
+				termConditions = append(termConditions, "("+strings.Join(parts, " OR ")+")")
+			}
+		}
+		groupConditions = append(groupConditions, "("+strings.Join(termConditions, " AND ")+")")
+	}
+
+	return "(" + strings.Join(groupConditions, " OR ") + ")", args
+}
+
+// buildFTSQuery 将查询转换为 FTS5 MATCH 表达式
+// trigram 分词要求每个词至少3个字符，否则返回false改用LIKE
+func buildFTSQuery(groups [][]searchTerm, fields []string) (string, bool) {
+	columns := make([]string, 0, len(fields))
+	for _, field := range fields {
+		columns = append(columns, strings.TrimPrefix(field, "hp."))
+	}
+
+	groupExprs := make([]string, 0, len(groups))
+	for _, group := range groups {
+		var positives, negatives []string
+		for _, term := range group {
+			if len(term.text) < 3 {
+				return "", false
+			}
+			quoted := `"` + strings.ReplaceAll(string(term.text), `"`, `""`) + `"`
+			if term.negate {
+				negatives = append(negatives, quoted)
+			} else {
+				positives = append(positives, quoted)
+			}
+		}
+		expr := "(" + strings.Join(positives, " AND ") + ")"
+		if len(negatives) > 0 {
+			expr += " NOT (" + strings.Join(negatives, " OR ") + ")"
+		}
+		groupExprs = append(groupExprs, "("+expr+")")
+	}
+
+	return "{" + strings.Join(columns, " ") + "} : (" + strings.Join(groupExprs, " OR ") + ")", true
+}
+
+// matchCandidate 在内存中判断页面是否匹配，并生成片段和高亮
+func matchCandidate(candidate searchCandidate, groups [][]searchTerm, field SearchField) *SearchHit {
+	type fieldText struct {
+		field SearchField
+		text  []rune
+		lower []rune
+	}
+
+	var texts []fieldText
+	if field != SearchFieldAI {
+		texts = append(texts, fieldText{field: SearchFieldOCR, text: []rune(candidate.OCRText)})
+	}
+	if field != SearchFieldOCR {
+		texts = append(texts, fieldText{field: SearchFieldAI, text: []rune(candidate.AIProcessedText)})
+	}
+	for i := range texts {
+		texts[i].lower = lowerRunes(texts[i].text)
+	}
+
+	contains := func(term []rune) bool {
+		for _, t := range texts {
+			if indexRunes(t.lower, term, 0) >= 0 {
+				return true
+			}
+		}
+		return false
+	}
+
+	var positives [][]rune
+	for _, group := range groups {
+		matched := true
+		for _, term := range group {
+			if contains(term.text) == term.negate {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			for _, term := range group {
+				if !term.negate {
+					positives = append(positives, term.text)
+				}
+			}
+		}
+	}
+	if len(positives) == 0 {
+		return nil
+	}
+
+	// 选择匹配次数最多的字段生成片段
+	bestIndex, bestScore, totalScore := 0, -1, 0
+	var bestRanges []TextRange
+	for i, t := range texts {
+		ranges := findRanges(t.lower, positives)
+		totalScore += len(ranges)
+		if len(ranges) > bestScore {
+			bestIndex, bestScore, bestRanges = i, len(ranges), ranges
+		}
+	}
+
+	best := texts[bestIndex]
+	snippet, highlights := buildSnippet(best.text, bestRanges)
+
+	return &SearchHit{
+		HistoryID:    candidate.HistoryID,
+		DocumentPath: candidate.DocumentPath,
+		DocumentName: candidate.DocumentName,
+		TaskType:     candidate.TaskType,
+		PageNumber:   candidate.PageNumber,
+		ProcessedAt:  candidate.ProcessedAt,
+		Field:        best.field,
+		Snippet:      snippet,
+		Highlights:   highlights,
+		Score:        totalScore,
+		Archived:     candidate.Archived,
+	}
+}
+
+// findRanges 查找所有匹配位置，按位置排序并合并重叠区间
+func findRanges(text []rune, terms [][]rune) []TextRange {
+	var ranges []TextRange
+	for _, term := range terms {
+		for from := 0; ; {
+			index := indexRunes(text, term, from)
+			if index < 0 {
+				break
+			}
+			ranges = append(ranges, TextRange{Start: index, End: index + len(term)})
+			from = index + len(term)
+		}
+	}
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Start < ranges[j].Start })
+	merged := make([]TextRange, 0, len(ranges))
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && r.Start <= merged[n-1].End {
+			if r.End > merged[n-1].End {
+				merged[n-1].End = r.End
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// buildSnippet 截取第一个匹配附近的文本，并将高亮位置换算到片段中
+func buildSnippet(text []rune, ranges []TextRange) (string, []TextRange) {
+	start, end := 0, len(text)
+	if len(ranges) > 0 {
+		start = ranges[0].Start - snippetBefore
+		if start < 0 {
+			start = 0
+		}
+	}
+	if end > start+snippetBefore+snippetAfter {
+		end = start + snippetBefore + snippetAfter
+	}
+
+	prefix := ""
+	if start > 0 {
+		prefix = "..."
+	}
+	suffix := ""
+	if end < len(text) {
+		suffix = "..."
+	}
+
+	shift := utf8.RuneCountInString(prefix) - start
+	highlights := []TextRange{}
+	for _, r := range ranges {
+		if r.Start >= end {
+			break
+		}
+		if r.End > end {
+			r.End = end
+		}
+		highlights = append(highlights, TextRange{Start: r.Start + shift, End: r.End + shift})
+	}
+
+	return prefix + string(text[start:end]) + suffix, highlights
+}
+
+// markedSnippet 生成带 <mark> 标记的HTML片段（兼容旧版接口）
+func (hit *SearchHit) markedSnippet() string {
+	runes := []rune(hit.Snippet)
+	var builder strings.Builder
+	last := 0
+	for _, r := range hit.Highlights {
+		builder.WriteString(html.EscapeString(string(runes[last:r.Start])))
+		builder.WriteString("<mark>")
+		builder.WriteString(html.EscapeString(string(runes[r.Start:r.End])))
+		builder.WriteString("</mark>")
+		last = r.End
+	}
+	builder.WriteString(html.EscapeString(string(runes[last:])))
+	return builder.String()
+}
+
+// parseSearchQuery 解析查询语法，返回以OR分隔的多组条件
+func parseSearchQuery(query string) ([][]searchTerm, error) {
+	var groups [][]searchTerm
+	var current []searchTerm
+	negateNext := false
+
+	flush := func() {
+		if len(current) > 0 {
+			groups = append(groups, current)
+		}
+		current = nil
+	}
+
+	runes := []rune(strings.TrimSpace(query))
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		if unicode.IsSpace(r) {
+			i++
+			continue
+		}
+
+		negate := negateNext
+		negateNext = false
+		if r == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+			negate = true
+			i++
+			r = runes[i]
+		}
+
+		var word string
+		quoted := r == '"'
+		if quoted {
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			word = string(runes[i+1 : end])
+			i = end + 1
+		} else {
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) {
+				end++
+			}
+			word = string(runes[i:end])
+			i = end
+		}
+
+		if !quoted && !negate {
+			switch word {
+			case "OR", "|":
+				flush()
+				continue
+			case "AND":
+				continue
+			case "NOT":
+				negateNext = true
+				continue
+			}
+		}
+
+		if word = strings.TrimSpace(word); word != "" {
+			current = append(current, searchTerm{text: lowerRunes([]rune(word)), negate: negate})
+		}
+	}
+	flush()
+
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("搜索关键词不能为空")
+	}
+	for _, group := range groups {
+		hasPositive := false
+		for _, term := range group {
+			if !term.negate {
+				hasPositive = true
+			}
+		}
+		if !hasPositive {
+			return nil, fmt.Errorf("每组搜索条件至少需要一个非排除的关键词")
+		}
+	}
+
+	return groups, nil
+}
+
+// escapeLike 转义 LIKE 通配符
+func escapeLike(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(value)
+}
+
+// lowerRunes 逐字符转小写（保持字符数不变，便于换算位置）
+func lowerRunes(runes []rune) []rune {
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+	return lower
+}
+
+// indexRunes 从from开始查找子串位置
+func indexRunes(text, term []rune, from int) int {
+	if len(term) == 0 {
+		return -1
+	}
+	for i := from; i+len(term) <= len(text); i++ {
+		matched := true
+		for j, r := range term {
+			if text[i+j] != r {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,143 @@
+package history
+
+import (
+	"fmt"
+	"time"
+)
+
+// PeriodCount 按时间段统计的页数
+type PeriodCount struct {
+	Period string `db:"period" json:"period"` // 日期（YYYY-MM-DD）或周（YYYY-Www）
+	Pages  int    `db:"pages" json:"pages"`
+}
+
+// ModelUsage 按模型统计的使用情况
+type ModelUsage struct {
+	Model    string   `db:"model" json:"model"`
+	TaskType TaskType `db:"task_type" json:"task_type"`
+	Records  int      `db:"records" json:"records"`
+	Pages    int      `db:"pages" json:"pages"`
+	Cost     float64  `db:"cost" json:"cost"`
+}
+
+// HistoryStats 历史记录统计数据
+type HistoryStats struct {
+	TotalRecords     int           `json:"total_records"`
+	TotalPages       int           `json:"total_pages"`
+	CompletedRecords int           `json:"completed_records"`
+	FailedRecords    int           `json:"failed_records"`
+	CancelledRecords int           `json:"cancelled_records"`
+	PartialRecords   int           `json:"partial_records"` // 部分页面失败的记录
+	FailureRate      float64       `json:"failure_rate"`    // 失败记录占已结束记录的比例
+	AvgPageTime      float64       `json:"avg_page_time"`   // 平均每页处理时间（秒）
+	TotalCost        float64       `json:"total_cost"`      // 累计费用
+	PagesPerDay      []PeriodCount `json:"pages_per_day"`   // 最近days天
+	PagesPerWeek     []PeriodCount `json:"pages_per_week"`  // 最近days天所在的周
+	ModelUsage       []ModelUsage  `json:"model_usage"`     // 按页数降序
+	Since            string        `json:"since"`           // 按天/周统计的起始日期
+}
+
+// GetStats 统计历史记录，按天和按周的页数只统计最近days天
+func (hm *HistoryManager) GetStats(days int) (*HistoryStats, error) {
+	if days <= 0 {
+		days = 30
+	}
+	hm.sync()
+
+	stats := &HistoryStats{
+		PagesPerDay:  []PeriodCount{},
+		PagesPerWeek: []PeriodCount{},
+		ModelUsage:   []ModelUsage{},
+	}
+
+	var totals struct {
+		Total     int     `db:"total"`
+		Completed int     `db:"completed"`
+		Failed    int     `db:"failed"`
+		Cancelled int     `db:"cancelled"`
+		Partial   int     `db:"partial"`
+		Cost      float64 `db:"cost"`
+	}
+	err := hm.db.Get(&totals, `
+	SELECT
+		COUNT(*) AS total,
+		COALESCE(SUM(status = 'completed'), 0) AS completed,
+		COALESCE(SUM(status = 'failed'), 0) AS failed,
+		COALESCE(SUM(status = 'cancelled'), 0) AS cancelled,
+		COALESCE(SUM(status = 'partial'), 0) AS partial,
+		COALESCE(SUM(cost), 0) AS cost
+	FROM processing_history
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("统计历史记录失败: %w", err)
+	}
+	stats.TotalRecords = totals.Total
+	stats.CompletedRecords = totals.Completed
+	stats.FailedRecords = totals.Failed
+	stats.CancelledRecords = totals.Cancelled
+	stats.PartialRecords = totals.Partial
+	stats.TotalCost = totals.Cost
+	if finished := totals.Completed + totals.Failed + totals.Cancelled + totals.Partial; finished > 0 {
+		stats.FailureRate = float64(totals.Failed) / float64(finished)
+	}
+
+	var pages struct {
+		Count   int     `db:"count"`
+		AvgTime float64 `db:"avg_time"`
+	}
+	err = hm.db.Get(&pages, `
+	SELECT
+		COUNT(*) AS count,
+		COALESCE(AVG(CASE WHEN processing_time > 0 THEN processing_time END), 0) AS avg_time
+	FROM history_pages
+	WHERE `+contentPageCondition+`
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("统计页面失败: %w", err)
+	}
+	stats.TotalPages = pages.Count
+	stats.AvgPageTime = pages.AvgTime
+
+	// created_at 为UTC时间，按本地日期分组
+	since := time.Now().AddDate(0, 0, -(days - 1)).Format("2006-01-02")
+	stats.Since = since
+
+	err = hm.db.Select(&stats.PagesPerDay, `
+	SELECT date(created_at, 'localtime') AS period, COUNT(*) AS pages
+	FROM history_pages
+	WHERE date(created_at, 'localtime') >= ? AND `+contentPageCondition+`
+	GROUP BY period
+	ORDER BY period
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("按天统计失败: %w", err)
+	}
+
+	err = hm.db.Select(&stats.PagesPerWeek, `
+	SELECT strftime('%Y-W%W', created_at, 'localtime') AS period, COUNT(*) AS pages
+	FROM history_pages
+	WHERE date(created_at, 'localtime') >= date(?, '-6 days', 'weekday 1') AND `+contentPageCondition+`
+	GROUP BY period
+	ORDER BY period
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("按周统计失败: %w", err)
+	}
+
+	err = hm.db.Select(&stats.ModelUsage, `
+	SELECT
+		ph.model AS model,
+		ph.task_type AS task_type,
+		COUNT(*) AS records,
+		COALESCE(SUM((SELECT COUNT(*) FROM history_pages hp WHERE hp.history_id = ph.id AND hp.`+contentPageCondition+`)), 0) AS pages,
+		COALESCE(SUM(ph.cost), 0) AS cost
+	FROM processing_history ph
+	GROUP BY ph.model, ph.task_type
+	ORDER BY pages DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("按模型统计失败: %w", err)
+	}
+
+	return stats, nil
+}
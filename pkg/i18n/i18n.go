@@ -0,0 +1,65 @@
+package i18n
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// 支持的语言
+const (
+	LangZhCN = "zh-CN"
+	LangEn   = "en"
+	LangJa   = "ja"
+)
+
+// DefaultLanguage 未配置或配置了不支持的语言时使用
+const DefaultLanguage = LangZhCN
+
+// current 当前语言，由界面配置设置
+var current atomic.Value
+
+func init() {
+	current.Store(DefaultLanguage)
+}
+
+// Languages 获取支持的语言列表
+func Languages() []string {
+	return []string{LangZhCN, LangEn, LangJa}
+}
+
+// SetLanguage 设置后端消息使用的语言，不支持的语言回退到默认语言
+func SetLanguage(lang string) {
+	if _, ok := catalogs[lang]; !ok {
+		lang = DefaultLanguage
+	}
+	current.Store(lang)
+}
+
+// Language 获取当前语言
+func Language() string {
+	return current.Load().(string)
+}
+
+// T 按当前语言翻译消息，args 按消息中的格式化占位符填充
+func T(key string, args ...interface{}) string {
+	if len(args) == 0 {
+		return lookup(key)
+	}
+	return fmt.Sprintf(lookup(key), args...)
+}
+
+// Errorf 按当前语言创建错误，消息中可以使用 %w 包装原始错误
+func Errorf(key string, args ...interface{}) error {
+	return fmt.Errorf(lookup(key), args...)
+}
+
+// lookup 查找当前语言的消息，缺少时使用默认语言，仍然缺少时返回 key
+func lookup(key string) string {
+	if format, ok := catalogs[Language()][key]; ok {
+		return format
+	}
+	if format, ok := catalogs[DefaultLanguage][key]; ok {
+		return format
+	}
+	return key
+}
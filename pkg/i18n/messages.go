@@ -0,0 +1,362 @@
+package i18n
+
+// catalogs 各语言的消息目录，值为 fmt 格式字符串，同一消息在各语言中的占位符顺序必须一致
+var catalogs = map[string]map[string]string{
+	LangZhCN: {
+		// 处理错误
+		"doc.not_loaded":    "未加载PDF文档",
+		"doc.changed":       "文档内容已变化，原有处理结果已过期，是否重新处理？",
+		"ai.not_configured": "未配置AI服务",
+		"ai.failed":         "AI处理失败: %v",
+		"ai.page_failed":    "AI处理第%d页失败: %v",
+		"page.failed":       "处理第%d页失败: %v",
+		"pages.none":        "没有可处理的页面",
+		"text.none":         "没有可处理的文本",
+		"init.failed":       "初始化失败: %v",
+		"drop.failed":       "处理拖放文件失败: %v",
+		"tag.no_text":       "第%d页没有可标记的文本",
+		"replace.failed":    "第%d页替换失败: %w",
+		"queue.not_pending": "第%d页不在待处理队列中（可能已经开始处理）",
+		"page.label":        "第%d页",
+
+		// 常见错误
+		"page.out_of_range":         "页码超出范围",
+		"page.out_of_range_n":       "页码超出范围: %d",
+		"page.no_text":              "页面没有可处理的文本",
+		"doc.busy":                  "文档正在处理中，请稍后重试",
+		"doc.id_failed":             "生成文档ID失败: %w",
+		"doc.load_failed":           "加载文档失败: %w",
+		"doc.import_removed":        "%s 是从压缩包或邮件中解压的临时文件，已在文档关闭或应用退出后删除，请重新导入后再处理",
+		"ai.failed_err":             "AI处理失败: %w",
+		"batch.all_failed":          "所有页面处理失败",
+		"history.not_found":         "历史记录不存在",
+		"history.get_failed":        "获取历史记录失败: %w",
+		"record.busy":               "记录正在处理中",
+		"queue.not_found":           "队列任务不存在: %s",
+		"init.component":            "组件未初始化",
+		"text.unsupported_type":     "不支持的文本类型: %s",
+		"review.invalid_status":     "无效的审核状态: %s",
+		"extraction.schema_missing": "提取模板不存在: %s",
+		"file.save_failed":          "保存文件失败: %w",
+		"file.mkdir_failed":         "创建目录失败: %w",
+		"file.temp_failed":          "创建临时文件失败: %w",
+
+		// 发送给AI的提示词
+		"prompt.page_header":       "=== 第 %d 页 ===",
+		"prompt.instruction":       "【重要提示】请严格按照以下指令处理上述第%d页的内容，不要包含其他页面的内容：",
+		"prompt.context_header":    "【上下文信息】",
+		"prompt.previous_page":     "上文（%s）内容：",
+		"prompt.next_page":         "下文（%s）内容：",
+		"prompt.tail_excerpt":      "（末尾节选）",
+		"prompt.head_excerpt":      "（开头节选）",
+		"prompt.no_previous_first": "上一页：无（当前是第一页）",
+		"prompt.no_previous":       "上一页：无内容或为空页",
+		"prompt.current_page":      "当前页（第%d页）内容：",
+		"prompt.no_next_last":      "下一页：无（当前是最后一页）",
+		"prompt.no_next":           "下一页：无内容或为空页",
+		"prompt.context_rules":     "【处理要求】\n请根据上述上下文信息处理第%d页的内容。\n⚠️ 重要限制：\n1. 只处理和输出第%d页的内容\n2. 上文和下文的内容仅作为理解上下文的参考，不要在输出中包含它们的内容\n3. 如果当前页内容与前后页有连续性，可以适当提及相关背景，但主体内容必须是当前页\n4. 严格按照页面边界进行处理，避免跨页面混合内容",
+		"prompt.rolling_header":    "【前文摘录】以下是前面已处理页面的结果，仅供参考，请保持术语、人名译法、称谓和人称指代与前文一致，不要在输出中重复这些内容：",
+		"prompt.rolling_entry":     "第%d页：",
+		"prompt.chapter_summary":   "【章节摘要】当前页所在章节：%s（第%d-%d页）",
+		"prompt.chapter_untitled":  "正文开头",
+		"prompt.chapter_summarize": "请用不超过%d字概括以下章节的主要内容、人物或术语和论述脉络，只输出摘要本身，不要输出任何解释。使用与原文相同的语言。",
+
+		// 页面处理状态
+		"status.started":       "开始处理",
+		"status.done":          "处理完成",
+		"status.failed":        "处理失败",
+		"status.cancelled":     "处理被取消",
+		"status.from_cache":    "从缓存加载",
+		"status.ai_processing": "正在AI处理第%d页",
+		"status.ai_done":       "第%d页AI处理完成",
+
+		// 批量处理状态
+		"batch.paused":    "批量处理已暂停",
+		"batch.resumed":   "批量处理已继续",
+		"batch.cancelled": "批量处理已取消",
+		"batch.busy":      "文档正在批量处理中，请等待当前批次结束",
+
+		// 资源提示
+		"storage.write_failed":    "部分处理结果未能保存到缓存或历史记录: %v",
+		"memory.warning":          "内存占用已达 %d MB（预算 %d MB），已释放渲染缓存和后台文档的页面文本。建议关闭暂时不用的文档。",
+		"disk.temp_dir":           "渲染临时目录",
+		"disk.data_dir":           "数据目录",
+		"disk.low":                "%s所在磁盘可用空间不足: 剩余 %s，低于 %s。请清理磁盘或在设置中清除缓存",
+		"disk.batch_insufficient": "%s所在磁盘空间不足: 处理 %d 页预计需要 %s，剩余 %s",
+
+		// 错误处理建议，键为 hint.<错误码>
+		"hint.DOCUMENT_NOT_LOADED": "请重新选择PDF文件。如果刚刚删除了历史记录，文档可能需要重新加载。",
+		"hint.DOC_LOCKED":          "数据已加密，请先输入密码解锁。",
+		"hint.DOC_BUSY":            "可以在处理队列中排队新的批次，或先取消当前批次。",
+		"hint.AI_NOT_CONFIGURED":   "请在设置中填写AI服务地址和API密钥。",
+		"hint.AI_AUTH":             "API密钥无效或没有访问权限，请在设置中检查API密钥。",
+		"hint.AI_RATE_LIMIT":       "AI服务请求过于频繁或额度不足，请稍后重试，或在设置中降低并发数和请求频率。",
+		"hint.AI_NETWORK":          "无法连接AI服务，请检查网络、代理和服务地址后重试。",
+		"hint.AI_SERVER":           "AI服务暂时不可用，请稍后重试。",
+		"hint.MODEL_NO_VISION":     "请在设置中选择支持图片识别的模型。",
+		"hint.RENDER_FAILED":       "页面渲染失败，请检查文件是否损坏，或在设置中降低渲染DPI后重试。",
+		"hint.CACHE_IO":            "读写缓存失败，请检查磁盘空间和数据目录权限，或在设置中清除缓存。",
+		"hint.DISK_SPACE_LOW":      "请清理磁盘，或在设置中清除缓存后重试，也可以分批处理较少的页面。",
+
+		// 导出内容
+		"export.page":          "第 %d 页",
+		"export.results_title": "%s - 处理结果",
+		"export.file_path":     "文件路径",
+		"export.total_pages":   "总页数",
+		"export.toc":           "目录",
+		"export.topic_index":   "主题索引",
+		"export.topic_pages":   "：第 %s 页", // 接在主题名称之后
+		"export.stats_label":   "处理统计",
+		"export.stats":         "共处理 %d 页，总计 %d 页",
+		"export.no_pages":      "没有已处理的页面可以导出",
+
+		// 批次质量报告
+		"report.title":                 "%s - 质量报告",
+		"report.model":                 "模型",
+		"report.summary":               "共 %d 页，已检查 %d 页，发现 %d 个问题",
+		"report.no_issues":             "没有发现问题",
+		"report.column_page":           "页码",
+		"report.column_issue":          "问题",
+		"report.column_detail":         "说明",
+		"report.chars":                 "%d 字",
+		"report.ratio":                 "乱码占比 %.0f%%",
+		"report.issue.failed":          "处理失败",
+		"report.issue.empty":           "结果为空",
+		"report.issue.short":           "文本过短",
+		"report.issue.low_confidence":  "低置信度",
+		"report.issue.garbled":         "乱码较多",
+		"report.issue.render_fallback": "使用了备用渲染方案",
+	},
+	LangEn: {
+		"doc.not_loaded":    "No PDF document loaded",
+		"doc.changed":       "The document has changed and previous results are out of date. Process it again?",
+		"ai.not_configured": "AI service is not configured",
+		"ai.failed":         "AI processing failed: %v",
+		"ai.page_failed":    "AI processing of page %d failed: %v",
+		"page.failed":       "Processing page %d failed: %v",
+		"pages.none":        "No pages to process",
+		"text.none":         "No text to process",
+		"init.failed":       "Initialization failed: %v",
+		"drop.failed":       "Failed to handle dropped files: %v",
+		"tag.no_text":       "Page %d has no text to tag",
+		"replace.failed":    "Replacing on page %d failed: %w",
+		"queue.not_pending": "Page %d is not waiting in the queue (it may already be processing)",
+		"page.label":        "Page %d",
+
+		"page.out_of_range":         "Page number out of range",
+		"page.out_of_range_n":       "Page number out of range: %d",
+		"page.no_text":              "The page has no text to process",
+		"doc.busy":                  "The document is being processed. Try again later",
+		"doc.id_failed":             "Failed to generate the document ID: %w",
+		"doc.load_failed":           "Failed to load the document: %w",
+		"doc.import_removed":        "%s was extracted from an archive or email into a temporary folder that was removed when the document was closed or the app exited; import it again to continue",
+		"ai.failed_err":             "AI processing failed: %w",
+		"batch.all_failed":          "All pages failed to process",
+		"history.not_found":         "History record not found",
+		"history.get_failed":        "Failed to read the history record: %w",
+		"record.busy":               "The record is being processed",
+		"queue.not_found":           "Queue item not found: %s",
+		"init.component":            "Component not initialized",
+		"text.unsupported_type":     "Unsupported text type: %s",
+		"review.invalid_status":     "Invalid review status: %s",
+		"extraction.schema_missing": "Extraction template not found: %s",
+		"file.save_failed":          "Failed to save the file: %w",
+		"file.mkdir_failed":         "Failed to create the directory: %w",
+		"file.temp_failed":          "Failed to create a temporary file: %w",
+
+		"prompt.page_header":       "=== Page %d ===",
+		"prompt.instruction":       "[IMPORTANT] Follow the instructions below for the content of page %d above only. Do not include content from other pages:",
+		"prompt.context_header":    "[Context]",
+		"prompt.previous_page":     "Previous (%s):",
+		"prompt.next_page":         "Next (%s):",
+		"prompt.tail_excerpt":      " (ending excerpt)",
+		"prompt.head_excerpt":      " (opening excerpt)",
+		"prompt.no_previous_first": "Previous page: none (this is the first page)",
+		"prompt.no_previous":       "Previous page: empty",
+		"prompt.current_page":      "Current page (page %d):",
+		"prompt.no_next_last":      "Next page: none (this is the last page)",
+		"prompt.no_next":           "Next page: empty",
+		"prompt.context_rules":     "[Requirements]\nProcess the content of page %d using the context above.\n⚠️ Restrictions:\n1. Only process and output the content of page %d\n2. The previous and next pages are reference only; do not include their content in the output\n3. If the page continues from adjacent pages you may mention the relevant background, but the output must be the current page\n4. Respect page boundaries and do not mix content across pages",
+		"prompt.rolling_header":    "[Earlier pages] Results of previously processed pages, for reference only. Keep terminology, name translations, forms of address and pronouns consistent with them, and do not repeat them in the output:",
+		"prompt.rolling_entry":     "Page %d: ",
+		"prompt.chapter_summary":   "[Chapter summary] Chapter of the current page: %s (pages %d-%d)",
+		"prompt.chapter_untitled":  "Front matter",
+		"prompt.chapter_summarize": "Summarize the main content, people or terms, and line of argument of the following chapter in at most %d characters. Output only the summary without any explanation. Use the same language as the original text.",
+
+		"status.started":       "Started",
+		"status.done":          "Done",
+		"status.failed":        "Failed",
+		"status.cancelled":     "Cancelled",
+		"status.from_cache":    "Loaded from cache",
+		"status.ai_processing": "AI processing page %d",
+		"status.ai_done":       "AI processing of page %d done",
+
+		"batch.paused":    "Batch processing paused",
+		"batch.resumed":   "Batch processing resumed",
+		"batch.cancelled": "Batch processing cancelled",
+		"batch.busy":      "The document is already being processed. Wait for the current batch to finish",
+
+		"storage.write_failed":    "Some results could not be saved to the cache or history: %v",
+		"memory.warning":          "Memory usage reached %d MB (budget %d MB). Render caches and page text of background documents were released. Consider closing documents you are not using.",
+		"disk.temp_dir":           "Render temp directory",
+		"disk.data_dir":           "Data directory",
+		"disk.low":                "Low disk space for %s: %s free, below %s. Free up disk space or clear the cache in settings",
+		"disk.batch_insufficient": "Not enough disk space for %s: processing %d pages needs about %s, %s free",
+
+		"hint.DOCUMENT_NOT_LOADED": "Please select the PDF file again. If you just deleted its history, the document may need to be reloaded.",
+		"hint.DOC_LOCKED":          "Data is encrypted. Enter the password to unlock it first.",
+		"hint.DOC_BUSY":            "Queue the new batch in the processing queue, or cancel the current batch first.",
+		"hint.AI_NOT_CONFIGURED":   "Enter the AI service URL and API key in settings.",
+		"hint.AI_AUTH":             "The API key is invalid or lacks permission. Check the API key in settings.",
+		"hint.AI_RATE_LIMIT":       "The AI service is rate limiting requests or the quota is exhausted. Try again later, or lower concurrency and request rate in settings.",
+		"hint.AI_NETWORK":          "Cannot reach the AI service. Check the network, proxy and service URL, then try again.",
+		"hint.AI_SERVER":           "The AI service is temporarily unavailable. Try again later.",
+		"hint.MODEL_NO_VISION":     "Choose a model that supports image input in settings.",
+		"hint.RENDER_FAILED":       "Rendering the page failed. Check whether the file is damaged, or lower the render DPI in settings and try again.",
+		"hint.CACHE_IO":            "Reading or writing the cache failed. Check free disk space and data directory permissions, or clear the cache in settings.",
+		"hint.DISK_SPACE_LOW":      "Free up disk space or clear the cache in settings and try again, or process fewer pages at a time.",
+
+		"export.page":          "Page %d",
+		"export.results_title": "%s - Processing Results",
+		"export.file_path":     "File path",
+		"export.total_pages":   "Total pages",
+		"export.toc":           "Contents",
+		"export.topic_index":   "Topic Index",
+		"export.topic_pages":   ": pages %s",
+		"export.stats_label":   "Summary",
+		"export.stats":         "%d of %d pages processed",
+		"export.no_pages":      "No processed pages to export",
+
+		"report.title":                 "%s - Quality Report",
+		"report.model":                 "Model",
+		"report.summary":               "%d pages, %d checked, %d issues found",
+		"report.no_issues":             "No issues found",
+		"report.column_page":           "Page",
+		"report.column_issue":          "Issue",
+		"report.column_detail":         "Detail",
+		"report.chars":                 "%d characters",
+		"report.ratio":                 "%.0f%% garbled",
+		"report.issue.failed":          "Failed",
+		"report.issue.empty":           "Empty result",
+		"report.issue.short":           "Suspiciously short",
+		"report.issue.low_confidence":  "Low confidence",
+		"report.issue.garbled":         "Garbled characters",
+		"report.issue.render_fallback": "Rendering fallback used",
+	},
+	LangJa: {
+		"doc.not_loaded":    "PDFドキュメントが読み込まれていません",
+		"doc.changed":       "ドキュメントが変更され、以前の処理結果は古くなりました。再処理しますか？",
+		"ai.not_configured": "AIサービスが設定されていません",
+		"ai.failed":         "AI処理に失敗しました: %v",
+		"ai.page_failed":    "%dページのAI処理に失敗しました: %v",
+		"page.failed":       "%dページの処理に失敗しました: %v",
+		"pages.none":        "処理できるページがありません",
+		"text.none":         "処理できるテキストがありません",
+		"init.failed":       "初期化に失敗しました: %v",
+		"drop.failed":       "ドロップされたファイルの処理に失敗しました: %v",
+		"tag.no_text":       "%dページにタグ付けできるテキストがありません",
+		"replace.failed":    "%dページの置換に失敗しました: %w",
+		"queue.not_pending": "%dページは処理待ちではありません（既に処理中の可能性があります）",
+		"page.label":        "%dページ",
+
+		"page.out_of_range":         "ページ番号が範囲外です",
+		"page.out_of_range_n":       "ページ番号が範囲外です: %d",
+		"page.no_text":              "ページに処理できるテキストがありません",
+		"doc.busy":                  "ドキュメントは処理中です。しばらくしてから再試行してください",
+		"doc.id_failed":             "ドキュメントIDの生成に失敗しました: %w",
+		"doc.load_failed":           "ドキュメントの読み込みに失敗しました: %w",
+		"doc.import_removed":        "%s はアーカイブまたはメールから一時フォルダーに展開されたファイルで、ドキュメントを閉じたときかアプリ終了時に削除されました。再度インポートしてから処理してください",
+		"ai.failed_err":             "AI処理に失敗しました: %w",
+		"batch.all_failed":          "すべてのページの処理に失敗しました",
+		"history.not_found":         "履歴が見つかりません",
+		"history.get_failed":        "履歴の取得に失敗しました: %w",
+		"record.busy":               "履歴は処理中です",
+		"queue.not_found":           "キューの項目が見つかりません: %s",
+		"init.component":            "コンポーネントが初期化されていません",
+		"text.unsupported_type":     "サポートされていないテキストの種類です: %s",
+		"review.invalid_status":     "無効なレビュー状態です: %s",
+		"extraction.schema_missing": "抽出テンプレートが見つかりません: %s",
+		"file.save_failed":          "ファイルの保存に失敗しました: %w",
+		"file.mkdir_failed":         "ディレクトリの作成に失敗しました: %w",
+		"file.temp_failed":          "一時ファイルの作成に失敗しました: %w",
+
+		"prompt.page_header":       "=== %dページ ===",
+		"prompt.instruction":       "【重要】以下の指示に従い、上記の%dページの内容のみを処理してください。他のページの内容は含めないでください：",
+		"prompt.context_header":    "【コンテキスト】",
+		"prompt.previous_page":     "前のページ（%s）：",
+		"prompt.next_page":         "次のページ（%s）：",
+		"prompt.tail_excerpt":      "（末尾の抜粋）",
+		"prompt.head_excerpt":      "（冒頭の抜粋）",
+		"prompt.no_previous_first": "前のページ：なし（最初のページです）",
+		"prompt.no_previous":       "前のページ：空白",
+		"prompt.current_page":      "現在のページ（%dページ）：",
+		"prompt.no_next_last":      "次のページ：なし（最後のページです）",
+		"prompt.no_next":           "次のページ：空白",
+		"prompt.context_rules":     "【処理要件】\n上記のコンテキストを参考に%dページの内容を処理してください。\n⚠️ 制限事項：\n1. %dページの内容のみを処理して出力してください\n2. 前後のページはコンテキストの参考のみとし、出力に含めないでください\n3. 前後のページと内容が続く場合は背景に触れてもかまいませんが、本文は現在のページにしてください\n4. ページの境界を守り、ページをまたいで内容を混ぜないでください",
+		"prompt.rolling_header":    "【前のページの抜粋】処理済みのページの結果です。参考のみとし、用語・人名の訳し方・呼称・人称を統一し、出力では繰り返さないでください：",
+		"prompt.rolling_entry":     "%dページ：",
+		"prompt.chapter_summary":   "【章の要約】現在のページの章：%s（%d-%dページ）",
+		"prompt.chapter_untitled":  "本文の冒頭",
+		"prompt.chapter_summarize": "次の章の主な内容、人物や用語、論旨の流れを%d字以内で要約してください。要約のみを出力し、説明は出力しないでください。原文と同じ言語を使用してください。",
+
+		"status.started":       "処理開始",
+		"status.done":          "処理完了",
+		"status.failed":        "処理失敗",
+		"status.cancelled":     "処理キャンセル",
+		"status.from_cache":    "キャッシュから読み込み",
+		"status.ai_processing": "%dページをAI処理中",
+		"status.ai_done":       "%dページのAI処理が完了しました",
+
+		"batch.paused":    "一括処理を一時停止しました",
+		"batch.resumed":   "一括処理を再開しました",
+		"batch.cancelled": "一括処理をキャンセルしました",
+		"batch.busy":      "ドキュメントは一括処理中です。現在の処理が終わるまでお待ちください",
+
+		"storage.write_failed":    "一部の処理結果をキャッシュまたは履歴に保存できませんでした: %v",
+		"memory.warning":          "メモリ使用量が %d MB（上限 %d MB）に達したため、レンダリングキャッシュとバックグラウンドのドキュメントのページテキストを解放しました。使用していないドキュメントを閉じてください。",
+		"disk.temp_dir":           "レンダリング一時ディレクトリ",
+		"disk.data_dir":           "データディレクトリ",
+		"disk.low":                "%sのディスク空き容量が不足しています: 残り %s（しきい値 %s）。ディスクを整理するか、設定でキャッシュを削除してください",
+		"disk.batch_insufficient": "%sのディスク容量が不足しています: %d ページの処理に約 %s 必要ですが、残りは %s です",
+
+		"hint.DOCUMENT_NOT_LOADED": "PDFファイルを選択し直してください。履歴を削除した直後の場合は、ドキュメントの再読み込みが必要です。",
+		"hint.DOC_LOCKED":          "データは暗号化されています。先にパスワードを入力してロックを解除してください。",
+		"hint.DOC_BUSY":            "処理キューで新しい一括処理を待機させるか、現在の処理を先にキャンセルしてください。",
+		"hint.AI_NOT_CONFIGURED":   "設定でAIサービスのURLとAPIキーを入力してください。",
+		"hint.AI_AUTH":             "APIキーが無効か、アクセス権限がありません。設定でAPIキーを確認してください。",
+		"hint.AI_RATE_LIMIT":       "AIサービスへのリクエストが多すぎるか、利用枠が不足しています。しばらくしてから再試行するか、設定で同時実行数とリクエスト頻度を下げてください。",
+		"hint.AI_NETWORK":          "AIサービスに接続できません。ネットワーク、プロキシ、サービスURLを確認してから再試行してください。",
+		"hint.AI_SERVER":           "AIサービスが一時的に利用できません。しばらくしてから再試行してください。",
+		"hint.MODEL_NO_VISION":     "設定で画像入力に対応したモデルを選択してください。",
+		"hint.RENDER_FAILED":       "ページのレンダリングに失敗しました。ファイルが破損していないか確認するか、設定でレンダリングDPIを下げて再試行してください。",
+		"hint.CACHE_IO":            "キャッシュの読み書きに失敗しました。ディスクの空き容量とデータディレクトリの権限を確認するか、設定でキャッシュを削除してください。",
+		"hint.DISK_SPACE_LOW":      "ディスクを整理するか、設定でキャッシュを削除してから再試行してください。ページ数を減らして処理することもできます。",
+
+		"export.page":          "%d ページ",
+		"export.results_title": "%s - 処理結果",
+		"export.file_path":     "ファイルパス",
+		"export.total_pages":   "総ページ数",
+		"export.toc":           "目次",
+		"export.topic_index":   "トピック索引",
+		"export.topic_pages":   "：%s ページ",
+		"export.stats_label":   "処理統計",
+		"export.stats":         "%d ページ処理済み（全 %d ページ）",
+		"export.no_pages":      "エクスポートできる処理済みページがありません",
+
+		"report.title":                 "%s - 品質レポート",
+		"report.model":                 "モデル",
+		"report.summary":               "全 %d ページ、確認済み %d ページ、問題 %d 件",
+		"report.no_issues":             "問題は見つかりませんでした",
+		"report.column_page":           "ページ",
+		"report.column_issue":          "問題",
+		"report.column_detail":         "詳細",
+		"report.chars":                 "%d 文字",
+		"report.ratio":                 "文字化け率 %.0f%%",
+		"report.issue.failed":          "処理失敗",
+		"report.issue.empty":           "結果が空",
+		"report.issue.short":           "テキストが短すぎる",
+		"report.issue.low_confidence":  "低信頼度",
+		"report.issue.garbled":         "文字化けが多い",
+		"report.issue.render_fallback": "代替レンダリングを使用",
+	},
+}
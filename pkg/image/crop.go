@@ -0,0 +1,49 @@
+package image
+
+import (
+	"fmt"
+	"image"
+	"os"
+
+	"golang.org/x/image/draw"
+)
+
+// minCropEdge 裁剪结果的短边小于该像素数时放大，避免小区域的文字过小
+const minCropEdge = 600
+
+// CropRegion 按相对坐标（0-1）裁剪图片区域，结果以JPEG保存到 outputPath
+func CropRegion(inputPath, outputPath string, x, y, width, height float64) error {
+	if width <= 0 || height <= 0 || x < 0 || y < 0 || x+width > 1.0001 || y+height > 1.0001 {
+		return fmt.Errorf("区域超出页面范围")
+	}
+
+	file, err := os.Open(inputPath)
+	if err != nil {
+		return fmt.Errorf("打开图片失败: %w", err)
+	}
+	img, _, err := image.Decode(file)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("解码图片失败: %w", err)
+	}
+
+	bounds := img.Bounds()
+	rect := image.Rect(
+		bounds.Min.X+int(x*float64(bounds.Dx())),
+		bounds.Min.Y+int(y*float64(bounds.Dy())),
+		bounds.Min.X+int((x+width)*float64(bounds.Dx())),
+		bounds.Min.Y+int((y+height)*float64(bounds.Dy())),
+	).Intersect(bounds)
+	if rect.Dx() < 2 || rect.Dy() < 2 {
+		return fmt.Errorf("区域过小")
+	}
+
+	scale := 1.0
+	if short := min(rect.Dx(), rect.Dy()); short < minCropEdge {
+		scale = min(float64(minCropEdge)/float64(short), 4)
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, int(float64(rect.Dx())*scale), int(float64(rect.Dy())*scale)))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, rect, draw.Src, nil)
+
+	return saveJPEG(dst, outputPath)
+}
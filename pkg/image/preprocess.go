@@ -0,0 +1,219 @@
+package image
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"os"
+	"sort"
+)
+
+// 图片预处理步骤（OCR前执行，处理结果均为灰度图）
+const (
+	StepGrayscale = "grayscale" // 转为灰度
+	StepContrast  = "contrast"  // 拉伸对比度（忽略最暗和最亮的1%像素）
+	StepSharpen   = "sharpen"   // 锐化文字边缘
+	StepDenoise   = "denoise"   // 3x3中值滤波去除噪点
+	StepBinarize  = "binarize"  // 按大津阈值二值化
+)
+
+// PreprocessSteps 支持的预处理步骤
+var PreprocessSteps = []string{StepGrayscale, StepContrast, StepSharpen, StepDenoise, StepBinarize}
+
+// ValidatePreprocessSteps 检查预处理步骤是否都受支持
+func ValidatePreprocessSteps(steps []string) error {
+	for _, step := range steps {
+		supported := false
+		for _, s := range PreprocessSteps {
+			if step == s {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return fmt.Errorf("不支持的预处理步骤: %s", step)
+		}
+	}
+	return nil
+}
+
+// Preprocess 按顺序对图片执行预处理步骤，结果以JPEG保存到 outputPath
+func Preprocess(inputPath, outputPath string, steps []string) error {
+	if err := ValidatePreprocessSteps(steps); err != nil {
+		return err
+	}
+
+	file, err := os.Open(inputPath)
+	if err != nil {
+		return fmt.Errorf("打开图片失败: %w", err)
+	}
+	img, _, err := image.Decode(file)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("解码图片失败: %w", err)
+	}
+
+	// 所有步骤都在灰度图上执行，文字识别不需要颜色信息
+	gray := toGray(img)
+	for _, step := range steps {
+		switch step {
+		case StepContrast:
+			stretchContrast(gray)
+		case StepSharpen:
+			gray = convolve3x3(gray, [9]int{0, -1, 0, -1, 5, -1, 0, -1, 0})
+		case StepDenoise:
+			gray = medianFilter(gray)
+		case StepBinarize:
+			binarize(gray, otsuThreshold(gray))
+		}
+	}
+
+	out, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("创建图片文件失败: %w", err)
+	}
+	defer out.Close()
+
+	if err := jpeg.Encode(out, gray, &jpeg.Options{Quality: 95}); err != nil {
+		return fmt.Errorf("保存图片失败: %w", err)
+	}
+	return nil
+}
+
+// toGray 转为灰度图
+func toGray(img image.Image) *image.Gray {
+	if gray, ok := img.(*image.Gray); ok {
+		return gray
+	}
+	bounds := img.Bounds()
+	gray := image.NewGray(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			gray.Set(x-bounds.Min.X, y-bounds.Min.Y, color.GrayModel.Convert(img.At(x, y)))
+		}
+	}
+	return gray
+}
+
+// stretchContrast 将1%到99%分位的亮度线性拉伸到0-255
+func stretchContrast(gray *image.Gray) {
+	if len(gray.Pix) == 0 {
+		return
+	}
+	sorted := append([]uint8(nil), gray.Pix...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	low := int(sorted[len(sorted)/100])
+	high := int(sorted[len(sorted)-1-len(sorted)/100])
+	if high <= low {
+		return
+	}
+
+	for i, v := range gray.Pix {
+		gray.Pix[i] = clamp((int(v) - low) * 255 / (high - low))
+	}
+}
+
+// convolve3x3 使用3x3卷积核处理图片（边缘像素保持不变）
+func convolve3x3(gray *image.Gray, kernel [9]int) *image.Gray {
+	bounds := gray.Bounds()
+	out := image.NewGray(bounds)
+	copy(out.Pix, gray.Pix)
+
+	for y := bounds.Min.Y + 1; y < bounds.Max.Y-1; y++ {
+		for x := bounds.Min.X + 1; x < bounds.Max.X-1; x++ {
+			sum := 0
+			for ky := -1; ky <= 1; ky++ {
+				for kx := -1; kx <= 1; kx++ {
+					sum += int(gray.GrayAt(x+kx, y+ky).Y) * kernel[(ky+1)*3+kx+1]
+				}
+			}
+			out.SetGray(x, y, color.Gray{Y: clamp(sum)})
+		}
+	}
+	return out
+}
+
+// medianFilter 3x3中值滤波（边缘像素保持不变）
+func medianFilter(gray *image.Gray) *image.Gray {
+	bounds := gray.Bounds()
+	out := image.NewGray(bounds)
+	copy(out.Pix, gray.Pix)
+
+	var window [9]uint8
+	for y := bounds.Min.Y + 1; y < bounds.Max.Y-1; y++ {
+		for x := bounds.Min.X + 1; x < bounds.Max.X-1; x++ {
+			i := 0
+			for ky := -1; ky <= 1; ky++ {
+				for kx := -1; kx <= 1; kx++ {
+					window[i] = gray.GrayAt(x+kx, y+ky).Y
+					i++
+				}
+			}
+			sort.Slice(window[:], func(a, b int) bool { return window[a] < window[b] })
+			out.SetGray(x, y, color.Gray{Y: window[4]})
+		}
+	}
+	return out
+}
+
+// otsuThreshold 按大津法计算二值化阈值
+func otsuThreshold(gray *image.Gray) uint8 {
+	var histogram [256]int
+	for _, v := range gray.Pix {
+		histogram[v]++
+	}
+
+	total := len(gray.Pix)
+	sum := 0
+	for i, count := range histogram {
+		sum += i * count
+	}
+
+	var best float64
+	threshold := 127
+	sumBackground, weightBackground := 0, 0
+	for i, count := range histogram {
+		weightBackground += count
+		if weightBackground == 0 {
+			continue
+		}
+		weightForeground := total - weightBackground
+		if weightForeground == 0 {
+			break
+		}
+		sumBackground += i * count
+
+		meanBackground := float64(sumBackground) / float64(weightBackground)
+		meanForeground := float64(sum-sumBackground) / float64(weightForeground)
+		diff := meanBackground - meanForeground
+		variance := float64(weightBackground) * float64(weightForeground) * diff * diff
+		if variance > best {
+			best = variance
+			threshold = i
+		}
+	}
+	return uint8(threshold)
+}
+
+// binarize 按阈值转为黑白图
+func binarize(gray *image.Gray, threshold uint8) {
+	for i, v := range gray.Pix {
+		if v > threshold {
+			gray.Pix[i] = 255
+		} else {
+			gray.Pix[i] = 0
+		}
+	}
+}
+
+// clamp 将值限制在0-255
+func clamp(v int) uint8 {
+	if v < 0 {
+		return 0
+	}
+	if v > 255 {
+		return 255
+	}
+	return uint8(v)
+}
@@ -0,0 +1,71 @@
+package image
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+// EncodePreview 将图片缩小到最长边不超过 maxEdge 后编码为JPEG，返回图片数据及缩放后的宽高
+// maxEdge 小于等于0时不缩放
+func EncodePreview(inputPath string, maxEdge int) ([]byte, int, int, error) {
+	file, err := os.Open(inputPath)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("打开图片失败: %w", err)
+	}
+	img, _, err := image.Decode(file)
+	file.Close()
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("解码图片失败: %w", err)
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if long := max(width, height); maxEdge > 0 && long > maxEdge {
+		scale := float64(maxEdge) / float64(long)
+		width = max(1, int(float64(width)*scale))
+		height = max(1, int(float64(height)*scale))
+		dst := image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.ApproxBiLinear.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+		img = dst
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, 0, 0, fmt.Errorf("编码图片失败: %w", err)
+	}
+	return buf.Bytes(), width, height, nil
+}
+
+// EncodeFile 将图片重新编码为指定格式（jpg 或 png）写入 w
+func EncodeFile(inputPath string, w io.Writer, format string) error {
+	file, err := os.Open(inputPath)
+	if err != nil {
+		return fmt.Errorf("打开图片失败: %w", err)
+	}
+	img, _, err := image.Decode(file)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("解码图片失败: %w", err)
+	}
+
+	switch strings.ToLower(format) {
+	case "png":
+		err = png.Encode(w, img)
+	case "jpg", "jpeg":
+		err = jpeg.Encode(w, img, &jpeg.Options{Quality: 90})
+	default:
+		return fmt.Errorf("不支持的图片格式: %s", format)
+	}
+	if err != nil {
+		return fmt.Errorf("编码图片失败: %w", err)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"image"
+	_ "image/gif" // 注册GIF解码器
 	"image/jpeg"
 	"image/png"
 	"io"
@@ -11,9 +12,15 @@ import (
 	"path/filepath"
 	"strings"
 
+	_ "golang.org/x/image/bmp" // 注册BMP解码器
 	"golang.org/x/image/draw"
+	_ "golang.org/x/image/tiff" // 注册TIFF解码器
+	_ "golang.org/x/image/webp" // 注册WebP解码器
 )
 
+// maxDirectOCRSize 可直接发送给OCR接口的图片文件大小上限，超过时重新编码
+const maxDirectOCRSize = 8 * 1024 * 1024
+
 // ProcessorConfig 图片处理配置
 type ProcessorConfig struct {
 	MaxWidth    int     // 最大宽度
@@ -208,6 +215,24 @@ type ImageInfo struct {
 	Filename string `json:"filename"`
 }
 
+// NeedsNormalization 判断图片是否需要转换格式或缩放后才能发送给OCR接口
+func (p *ImageProcessor) NeedsNormalization(info *ImageInfo) bool {
+	// 只有JPEG和PNG可以直接发送
+	format := strings.ToLower(info.Format)
+	if format != "jpeg" && format != "png" {
+		return true
+	}
+
+	// 超过配置尺寸需要缩放
+	newWidth, newHeight := p.calculateNewSize(info.Width, info.Height)
+	if newWidth != info.Width || newHeight != info.Height {
+		return true
+	}
+
+	// 文件过大需要重新编码压缩
+	return info.Size > maxDirectOCRSize
+}
+
 // OptimizeForOCR 为OCR优化图片
 func (p *ImageProcessor) OptimizeForOCR(inputPath string, outputPath string) error {
 	// OCR优化配置
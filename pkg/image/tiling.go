@@ -0,0 +1,90 @@
+package image
+
+import (
+	"fmt"
+	"image"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// DefaultTileAspectRatio 长边与短边之比超过该值时切分图块
+	DefaultTileAspectRatio = 3.0
+	// tileShape 每个图块的长边与短边之比，接近纵向A4纸，模型缩放后文字仍清晰
+	tileShape = 1.4
+	// tileOverlap 相邻图块重叠的比例，避免切断的文字行在两个图块中都不完整
+	tileOverlap = 0.1
+)
+
+// SplitTiles 将过高或过宽的图片沿长边切分为有重叠的图块，以JPEG保存到 outputDir
+// 长边与短边之比不超过 maxAspect 时不切分，返回 nil；maxAspect 不大于1时使用默认值
+func SplitTiles(inputPath, outputDir string, maxAspect float64) ([]string, error) {
+	if maxAspect <= 1 {
+		maxAspect = DefaultTileAspectRatio
+	}
+
+	file, err := os.Open(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("打开图片失败: %w", err)
+	}
+	img, _, err := image.Decode(file)
+	file.Close()
+	if err != nil {
+		return nil, fmt.Errorf("解码图片失败: %w", err)
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	vertical := height >= width
+	long, short := height, width
+	if !vertical {
+		long, short = width, height
+	}
+	if short == 0 || float64(long)/float64(short) <= maxAspect {
+		return nil, nil
+	}
+
+	tileLength := int(float64(short) * tileShape)
+	step := int(float64(tileLength) * (1 - tileOverlap))
+
+	type subImager interface {
+		SubImage(r image.Rectangle) image.Image
+	}
+	sub, ok := img.(subImager)
+	if !ok {
+		return nil, fmt.Errorf("不支持切分的图片格式")
+	}
+
+	var paths []string
+	for start := 0; ; start += step {
+		end := min(start+tileLength, long)
+		rect := image.Rect(bounds.Min.X, bounds.Min.Y+start, bounds.Max.X, bounds.Min.Y+end)
+		if !vertical {
+			rect = image.Rect(bounds.Min.X+start, bounds.Min.Y, bounds.Min.X+end, bounds.Max.Y)
+		}
+
+		path := filepath.Join(outputDir, fmt.Sprintf("tile_%03d.jpg", len(paths)+1))
+		if err := saveJPEG(sub.SubImage(rect), path); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+		if end == long {
+			break
+		}
+	}
+	return paths, nil
+}
+
+// saveJPEG 以较高质量保存JPEG图片
+func saveJPEG(img image.Image, path string) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("创建图片文件失败: %w", err)
+	}
+	defer out.Close()
+	if err := jpeg.Encode(out, img, &jpeg.Options{Quality: 90}); err != nil {
+		return fmt.Errorf("保存图片失败: %w", err)
+	}
+	return nil
+}
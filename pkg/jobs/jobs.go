@@ -0,0 +1,365 @@
+package jobs
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+
+	"pdf-ocr-ai/pkg/migrate"
+)
+
+// JobStatus 任务状态
+type JobStatus string
+
+const (
+	StatusRunning     JobStatus = "running"
+	StatusCompleted   JobStatus = "completed"
+	StatusFailed      JobStatus = "failed"
+	StatusPartial     JobStatus = "partial" // 部分页面处理失败
+	StatusCancelled   JobStatus = "cancelled"
+	StatusInterrupted JobStatus = "interrupted" // 应用异常退出时未完成的任务
+)
+
+// TaskType 任务类型
+type TaskType string
+
+const (
+	TaskOCR TaskType = "ocr"
+	TaskAI  TaskType = "ai"
+)
+
+// Job 持久化的批量处理任务
+type Job struct {
+	ID             int       `db:"id" json:"id"`
+	DocumentPath   string    `db:"document_path" json:"document_path"`
+	TaskType       TaskType  `db:"task_type" json:"task_type"`
+	PagesJSON      string    `db:"pages" json:"-"`
+	Prompt         string    `db:"prompt" json:"prompt"`
+	ContextMode    bool      `db:"context_mode" json:"context_mode"`
+	RollingContext bool      `db:"rolling_context" json:"rolling_context"`
+	ForceReprocess bool      `db:"force_reprocess" json:"force_reprocess"`
+	HistoryID      *int      `db:"history_id" json:"history_id,omitempty"` // 对应的历史记录，继续或重试记录时读取原来的提示词和上下文设置
+	Status         JobStatus `db:"status" json:"status"`
+	ErrorMessage   *string   `db:"error_message" json:"error_message,omitempty"`
+	CreatedAt      string    `db:"created_at" json:"created_at"`
+	UpdatedAt      string    `db:"updated_at" json:"updated_at"`
+
+	Pages     []int `db:"-" json:"pages"`      // 任务包含的全部页面
+	DonePages []int `db:"-" json:"done_pages"` // 已完成的页面
+}
+
+// RemainingPages 获取尚未完成的页面
+func (j *Job) RemainingPages() []int {
+	done := make(map[int]bool, len(j.DonePages))
+	for _, page := range j.DonePages {
+		done[page] = true
+	}
+
+	remaining := make([]int, 0, len(j.Pages))
+	for _, page := range j.Pages {
+		if !done[page] {
+			remaining = append(remaining, page)
+		}
+	}
+	return remaining
+}
+
+// JobManager 任务管理器
+type JobManager struct {
+	db *sqlx.DB
+}
+
+// NewJobManager 创建任务管理器
+func NewJobManager() (*JobManager, error) {
+	// 获取用户目录
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("获取用户目录失败: %w", err)
+	}
+
+	// 创建数据目录
+	dataDir := filepath.Join(homeDir, ".pdfSeer")
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("创建数据目录失败: %w", err)
+	}
+
+	// 连接数据库
+	dbPath := filepath.Join(dataDir, "jobs.db")
+	db, err := sqlx.Connect("sqlite3", dbPath+"?cache=shared&_journal_mode=WAL")
+	if err != nil {
+		return nil, fmt.Errorf("连接数据库失败: %w", err)
+	}
+
+	jm := &JobManager{db: db}
+
+	// 运行数据库迁移
+	if err := migrate.Run(db, dbPath, migrationComponent, jm.migrations()); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("运行数据库迁移失败: %w", err)
+	}
+
+	return jm, nil
+}
+
+// migrationComponent 任务表的迁移组件名
+const migrationComponent = "jobs"
+
+// migrations 任务数据库的版本化迁移
+// 早期版本没有记录版本号，各步骤需兼容已手动迁移过的数据库（先检查再修改）
+func (jm *JobManager) migrations() []migrate.Migration {
+	return []migrate.Migration{
+		{Version: 1, Description: "创建任务表", Up: jm.createTables},
+		{Version: 2, Description: "添加历史记录列", Up: addColumn("jobs", "history_id", "INTEGER")},
+		{Version: 3, Description: "添加滚动上下文列", Up: addColumn("jobs", "rolling_context", "BOOLEAN DEFAULT 0")},
+	}
+}
+
+// createTables 创建基础表结构（版本1）
+func (jm *JobManager) createTables(tx *sqlx.Tx) error {
+	// 任务表
+	jobsSQL := `
+	CREATE TABLE IF NOT EXISTS jobs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		document_path TEXT NOT NULL,
+		task_type TEXT NOT NULL,
+		pages TEXT NOT NULL,
+		prompt TEXT DEFAULT '',
+		context_mode BOOLEAN DEFAULT 0,
+		force_reprocess BOOLEAN DEFAULT 0,
+		status TEXT DEFAULT 'running',
+		error_message TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	// 任务页面进度表
+	jobPagesSQL := `
+	CREATE TABLE IF NOT EXISTS job_pages (
+		job_id INTEGER NOT NULL,
+		page_number INTEGER NOT NULL,
+		completed_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (job_id) REFERENCES jobs(id),
+		UNIQUE(job_id, page_number)
+	);`
+
+	// 创建索引
+	indexSQL := `
+	CREATE INDEX IF NOT EXISTS idx_jobs_status ON jobs(status);
+	CREATE INDEX IF NOT EXISTS idx_job_pages_job ON job_pages(job_id);
+	`
+
+	for _, sql := range []string{jobsSQL, jobPagesSQL, indexSQL} {
+		if _, err := tx.Exec(sql); err != nil {
+			return fmt.Errorf("执行SQL失败: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// addColumn 添加列的迁移步骤，未记录版本号的旧数据库可能已添加过该列，此时跳过
+func addColumn(table, column, definition string) func(tx *sqlx.Tx) error {
+	return func(tx *sqlx.Tx) error {
+		var count int
+		if err := tx.Get(&count, "SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column); err != nil {
+			return fmt.Errorf("读取表结构失败: %w", err)
+		}
+		if count > 0 {
+			return nil
+		}
+		if _, err := tx.Exec(`ALTER TABLE ` + table + ` ADD COLUMN ` + column + ` ` + definition); err != nil {
+			return fmt.Errorf("执行SQL失败: %w", err)
+		}
+		return nil
+	}
+}
+
+// CreateJob 创建任务
+func (jm *JobManager) CreateJob(job *Job) (*Job, error) {
+	pagesJSON, err := json.Marshal(job.Pages)
+	if err != nil {
+		return nil, fmt.Errorf("序列化页面列表失败: %w", err)
+	}
+
+	query := `
+	INSERT INTO jobs (document_path, task_type, pages, prompt, context_mode, rolling_context, force_reprocess, history_id, status)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := jm.db.Exec(query, job.DocumentPath, job.TaskType, string(pagesJSON),
+		job.Prompt, job.ContextMode, job.RollingContext, job.ForceReprocess, job.HistoryID, StatusRunning)
+	if err != nil {
+		return nil, fmt.Errorf("创建任务失败: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("获取任务ID失败: %w", err)
+	}
+
+	return jm.GetJob(int(id))
+}
+
+// GetJob 获取任务（包含页面进度）
+func (jm *JobManager) GetJob(id int) (*Job, error) {
+	var job Job
+	err := jm.db.Get(&job, `SELECT * FROM jobs WHERE id = ?`, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := jm.loadProgress(&job); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// LatestJobForHistory 获取历史记录最近的任务（包含页面进度），没有时返回nil
+func (jm *JobManager) LatestJobForHistory(historyID int) (*Job, error) {
+	var id int
+	err := jm.db.Get(&id, `SELECT id FROM jobs WHERE history_id = ? ORDER BY id DESC LIMIT 1`, historyID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return jm.GetJob(id)
+}
+
+// loadProgress 加载任务的页面列表和已完成页面
+func (jm *JobManager) loadProgress(job *Job) error {
+	if err := json.Unmarshal([]byte(job.PagesJSON), &job.Pages); err != nil {
+		return fmt.Errorf("解析任务页面列表失败: %w", err)
+	}
+
+	job.DonePages = []int{}
+	return jm.db.Select(&job.DonePages,
+		`SELECT page_number FROM job_pages WHERE job_id = ? ORDER BY page_number`, job.ID)
+}
+
+// MarkPageDone 标记页面已完成
+func (jm *JobManager) MarkPageDone(jobID int, pageNumber int) error {
+	if _, err := jm.db.Exec(`INSERT OR IGNORE INTO job_pages (job_id, page_number) VALUES (?, ?)`,
+		jobID, pageNumber); err != nil {
+		return err
+	}
+
+	_, err := jm.db.Exec(`UPDATE jobs SET updated_at = CURRENT_TIMESTAMP WHERE id = ?`, jobID)
+	return err
+}
+
+// UpdateStatus 更新任务状态
+func (jm *JobManager) UpdateStatus(id int, status JobStatus, errorMsg string) error {
+	var errorMsgPtr *string
+	if errorMsg != "" {
+		errorMsgPtr = &errorMsg
+	}
+
+	_, err := jm.db.Exec(`UPDATE jobs SET status = ?, error_message = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`,
+		status, errorMsgPtr, id)
+	return err
+}
+
+// MarkRunningAsInterrupted 将上次运行遗留的进行中任务标记为中断（启动时调用）
+func (jm *JobManager) MarkRunningAsInterrupted() (int, error) {
+	result, err := jm.db.Exec(`UPDATE jobs SET status = ? WHERE status = ?`, StatusInterrupted, StatusRunning)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := result.RowsAffected()
+	return int(count), err
+}
+
+// GetInterruptedJobs 获取所有中断的任务
+func (jm *JobManager) GetInterruptedJobs() ([]*Job, error) {
+	var jobs []*Job
+	if err := jm.db.Select(&jobs, `SELECT * FROM jobs WHERE status = ? ORDER BY created_at`, StatusInterrupted); err != nil {
+		return nil, err
+	}
+
+	for _, job := range jobs {
+		if err := jm.loadProgress(job); err != nil {
+			return nil, err
+		}
+	}
+
+	return jobs, nil
+}
+
+// finishedStatuses 已结束的任务状态，这些任务不会再继续
+var finishedStatuses = []interface{}{StatusCompleted, StatusPartial, StatusFailed, StatusCancelled}
+
+// CleanupFinishedJobs 删除已结束任务的页面进度（只在继续中断的任务时使用），以及超过 days 天的已结束任务
+// 任务保留到期前，重试记录的失败页面时仍可读取原来的提示词和上下文设置；days 为0时保留任务，只删除页面进度
+func (jm *JobManager) CleanupFinishedJobs(days int) (int, error) {
+	tx, err := jm.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query, args, err := sqlx.In(`DELETE FROM job_pages WHERE job_id IN (SELECT id FROM jobs WHERE status IN (?))`, finishedStatuses)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(query, args...); err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	if days > 0 {
+		// updated_at 为UTC时间
+		cutoff := time.Now().UTC().AddDate(0, 0, -days).Format("2006-01-02 15:04:05")
+		query, args, err := sqlx.In(`DELETE FROM jobs WHERE status IN (?) AND updated_at < ?`, finishedStatuses, cutoff)
+		if err != nil {
+			return 0, err
+		}
+		result, err := tx.Exec(query, args...)
+		if err != nil {
+			return 0, err
+		}
+		count, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		removed = int(count)
+	}
+
+	return removed, tx.Commit()
+}
+
+// DeleteJob 删除任务及其进度
+func (jm *JobManager) DeleteJob(id int) error {
+	tx, err := jm.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM job_pages WHERE job_id = ?", id); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM jobs WHERE id = ?", id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Close 关闭数据库连接
+func (jm *JobManager) Close() error {
+	return jm.db.Close()
+}
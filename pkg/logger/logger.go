@@ -0,0 +1,265 @@
+package logger
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level 日志级别
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+const (
+	logFileName  = "pdfseer.log"
+	maxFileSize  = 10 << 20 // 单个日志文件上限
+	maxBackups   = 5        // 保留的历史日志文件数
+	tailMaxLines = 5000     // GetTail 最多返回的行数
+)
+
+var levelNames = map[Level]string{
+	LevelDebug: "DEBUG",
+	LevelInfo:  "INFO",
+	LevelWarn:  "WARN",
+	LevelError: "ERROR",
+}
+
+// ParseLevel 解析日志级别名称
+func ParseLevel(name string) (Level, error) {
+	for level, levelName := range levelNames {
+		if strings.EqualFold(name, levelName) {
+			return level, nil
+		}
+	}
+	return LevelInfo, fmt.Errorf("无效的日志级别: %s", name)
+}
+
+// String 日志级别名称
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+// Logger 分级日志，写入控制台和按大小轮转的日志文件
+type Logger struct {
+	mu    sync.Mutex
+	level Level
+	dir   string
+	file  *os.File
+	size  int64
+}
+
+var std = &Logger{level: LevelInfo}
+
+// DefaultDir 默认日志目录 ~/.pdfSeer/logs
+func DefaultDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("获取用户目录失败: %w", err)
+	}
+	return filepath.Join(homeDir, ".pdfSeer", "logs"), nil
+}
+
+// Init 初始化默认日志，日志文件写入dir目录，并接管标准库log的输出
+func Init(dir string, level Level) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("创建日志目录失败: %w", err)
+	}
+
+	std.mu.Lock()
+	std.dir = dir
+	std.level = level
+	err := std.openFile()
+	std.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	// 第三方库和遗留代码中的 log.Printf 以INFO级别写入
+	log.SetFlags(0)
+	log.SetOutput(stdWriter{})
+	return nil
+}
+
+// Dir 获取日志目录
+func Dir() string {
+	std.mu.Lock()
+	defer std.mu.Unlock()
+	return std.dir
+}
+
+// SetLevel 设置日志级别
+func SetLevel(level Level) {
+	std.mu.Lock()
+	std.level = level
+	std.mu.Unlock()
+}
+
+// GetLevel 获取当前日志级别
+func GetLevel() Level {
+	std.mu.Lock()
+	defer std.mu.Unlock()
+	return std.level
+}
+
+// Debugf 输出调试日志
+func Debugf(format string, args ...interface{}) { std.write(LevelDebug, fmt.Sprintf(format, args...)) }
+
+// Infof 输出信息日志
+func Infof(format string, args ...interface{}) { std.write(LevelInfo, fmt.Sprintf(format, args...)) }
+
+// Warnf 输出警告日志
+func Warnf(format string, args ...interface{}) { std.write(LevelWarn, fmt.Sprintf(format, args...)) }
+
+// Errorf 输出错误日志
+func Errorf(format string, args ...interface{}) { std.write(LevelError, fmt.Sprintf(format, args...)) }
+
+// RecoverPanic 记录panic及堆栈后继续抛出，用于 defer（确保崩溃信息写入日志文件）
+func RecoverPanic(where string) {
+	if r := recover(); r != nil {
+		std.write(LevelError, fmt.Sprintf("PANIC in %s: %v\n%s", where, r, debug.Stack()))
+		Sync()
+		panic(r)
+	}
+}
+
+// Sync 将日志文件刷新到磁盘
+func Sync() {
+	std.mu.Lock()
+	defer std.mu.Unlock()
+	if std.file != nil {
+		std.file.Sync()
+	}
+}
+
+// Close 关闭日志文件
+func Close() {
+	std.mu.Lock()
+	defer std.mu.Unlock()
+	if std.file != nil {
+		std.file.Sync()
+		std.file.Close()
+		std.file = nil
+	}
+}
+
+// Tail 获取当前日志文件的最后n行
+func Tail(n int) ([]string, error) {
+	if n <= 0 {
+		return []string{}, nil
+	}
+	if n > tailMaxLines {
+		n = tailMaxLines
+	}
+
+	dir := Dir()
+	if dir == "" {
+		return nil, fmt.Errorf("日志未初始化")
+	}
+
+	file, err := os.Open(filepath.Join(dir, logFileName))
+	if err != nil {
+		return nil, fmt.Errorf("打开日志文件失败: %w", err)
+	}
+	defer file.Close()
+
+	lines := make([]string, 0, n)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(lines) == n {
+			lines = lines[1:]
+		}
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取日志文件失败: %w", err)
+	}
+
+	return lines, nil
+}
+
+// write 写入一条日志
+func (l *Logger) write(level Level, message string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if level < l.level {
+		return
+	}
+
+	line := fmt.Sprintf("%s [%s] %s\n", time.Now().Format("2006-01-02 15:04:05.000"), level, strings.TrimRight(message, "\n"))
+
+	os.Stderr.WriteString(line)
+
+	if l.file == nil {
+		return
+	}
+	if l.size+int64(len(line)) > maxFileSize {
+		if err := l.rotate(); err != nil {
+			os.Stderr.WriteString(fmt.Sprintf("日志轮转失败: %v\n", err))
+			return
+		}
+	}
+
+	n, _ := io.WriteString(l.file, line)
+	l.size += int64(n)
+}
+
+// openFile 打开当前日志文件（调用方需持有锁）
+func (l *Logger) openFile() error {
+	path := filepath.Join(l.dir, logFileName)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("打开日志文件失败: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("读取日志文件信息失败: %w", err)
+	}
+
+	l.file = file
+	l.size = info.Size()
+	return nil
+}
+
+// rotate 轮转日志文件：pdfseer.log -> pdfseer.log.1 -> ... -> pdfseer.log.N（调用方需持有锁）
+func (l *Logger) rotate() error {
+	if l.file != nil {
+		l.file.Close()
+		l.file = nil
+	}
+
+	base := filepath.Join(l.dir, logFileName)
+	os.Remove(fmt.Sprintf("%s.%d", base, maxBackups))
+	for i := maxBackups - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", base, i), fmt.Sprintf("%s.%d", base, i+1))
+	}
+	if err := os.Rename(base, base+".1"); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return l.openFile()
+}
+
+// stdWriter 将标准库log的输出转为INFO日志
+type stdWriter struct{}
+
+func (stdWriter) Write(p []byte) (int, error) {
+	std.write(LevelInfo, string(p))
+	return len(p), nil
+}
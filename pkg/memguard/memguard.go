@@ -0,0 +1,134 @@
+package memguard
+
+import (
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// WarnRatio 内存占用达到预算的这个比例时视为接近上限
+	WarnRatio = 0.85
+	// recoverRatio 降到这个比例以下后，再次接近上限时重新发出警告
+	recoverRatio = 0.7
+)
+
+// Usage 进程内存占用
+type Usage struct {
+	ResidentBytes uint64  `json:"resident_bytes"` // 进程常驻内存（包括 libvips 等C库分配的内存，无法读取时为Go运行时占用）
+	HeapBytes     uint64  `json:"heap_bytes"`     // Go堆内存
+	BudgetBytes   int64   `json:"budget_bytes"`   // 内存预算，0 表示不限制
+	Ratio         float64 `json:"ratio"`          // 占预算的比例
+}
+
+// Monitor 定期检查内存占用，接近预算时回调
+type Monitor struct {
+	mu         sync.Mutex
+	budget     int64
+	interval   time.Duration
+	onPressure func(usage Usage, first bool)
+	warned     bool
+	stop       chan struct{}
+}
+
+// NewMonitor 创建内存监视器，内存接近预算时调用 onPressure，first 表示本次是从正常进入接近上限的状态
+func NewMonitor(interval time.Duration, onPressure func(usage Usage, first bool)) *Monitor {
+	return &Monitor{interval: interval, onPressure: onPressure}
+}
+
+// SetBudget 设置内存预算（字节），0 表示不限制
+func (m *Monitor) SetBudget(budget int64) {
+	m.mu.Lock()
+	m.budget = budget
+	m.mu.Unlock()
+}
+
+// Start 开始定期检查
+func (m *Monitor) Start() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stop != nil {
+		return
+	}
+	stop := make(chan struct{})
+	m.stop = stop
+
+	go func() {
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				m.Check()
+			}
+		}
+	}()
+}
+
+// Stop 停止检查
+func (m *Monitor) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stop != nil {
+		close(m.stop)
+		m.stop = nil
+	}
+}
+
+// Check 立即检查一次内存占用，接近预算时回调
+func (m *Monitor) Check() Usage {
+	m.mu.Lock()
+	usage := ReadUsage(m.budget)
+	pressure := usage.BudgetBytes > 0 && usage.Ratio >= WarnRatio
+	first := pressure && !m.warned
+	switch {
+	case pressure:
+		m.warned = true
+	case usage.Ratio < recoverRatio:
+		m.warned = false
+	}
+	m.mu.Unlock()
+
+	if pressure && m.onPressure != nil {
+		m.onPressure(usage, first)
+	}
+	return usage
+}
+
+// ReadUsage 读取当前进程的内存占用
+func ReadUsage(budget int64) Usage {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	usage := Usage{HeapBytes: stats.HeapAlloc, BudgetBytes: budget}
+	usage.ResidentBytes = residentBytes()
+	if usage.ResidentBytes == 0 {
+		usage.ResidentBytes = stats.Sys
+	}
+	if budget > 0 {
+		usage.Ratio = float64(usage.ResidentBytes) / float64(budget)
+	}
+	return usage
+}
+
+// residentBytes 从 /proc/self/statm 读取常驻内存（仅Linux），无法读取时返回0
+func residentBytes() uint64 {
+	data, err := os.ReadFile("/proc/self/statm")
+	if err != nil {
+		return 0
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) < 2 {
+		return 0
+	}
+	pages, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return pages * uint64(os.Getpagesize())
+}
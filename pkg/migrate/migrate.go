@@ -0,0 +1,166 @@
+package migrate
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/jmoiron/sqlx"
+
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// Migration 一个版本的数据库结构变更
+// 版本号从1开始连续递增；已发布的迁移不能修改，结构变更只能追加新版本
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(tx *sqlx.Tx) error
+}
+
+// SQL 按顺序执行SQL语句的迁移步骤
+func SQL(statements ...string) func(tx *sqlx.Tx) error {
+	return func(tx *sqlx.Tx) error {
+		for _, stmt := range statements {
+			if _, err := tx.Exec(stmt); err != nil {
+				return fmt.Errorf("执行迁移语句失败: %w", err)
+			}
+		}
+		return nil
+	}
+}
+
+// versionsTableSQL 记录各组件的结构版本（同一数据库中缓存、历史记录等组件独立迁移）
+const versionsTableSQL = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+	component TEXT PRIMARY KEY,
+	version INTEGER NOT NULL,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+)`
+
+// Version 获取组件当前的结构版本，未版本化的数据库为0
+func Version(db *sqlx.DB, component string) (int, error) {
+	var exists int
+	if err := db.Get(&exists, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'"); err != nil {
+		return 0, fmt.Errorf("读取数据库版本失败: %w", err)
+	}
+	if exists == 0 {
+		return 0, nil
+	}
+
+	var version int
+	err := db.Get(&version, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations WHERE component = ?", component)
+	if err != nil {
+		return 0, fmt.Errorf("读取数据库版本失败: %w", err)
+	}
+	return version, nil
+}
+
+// Versions 获取数据库中所有组件的结构版本
+func Versions(db *sqlx.DB) (map[string]int, error) {
+	var rows []struct {
+		Component string `db:"component"`
+		Version   int    `db:"version"`
+	}
+	if err := db.Select(&rows, "SELECT component, version FROM schema_migrations"); err != nil {
+		return nil, fmt.Errorf("读取数据库版本失败: %w", err)
+	}
+
+	versions := make(map[string]int, len(rows))
+	for _, row := range rows {
+		versions[row.Component] = row.Version
+	}
+	return versions, nil
+}
+
+// Run 将组件迁移到最新版本，每个版本在独立事务中执行并同时更新 schema_migrations
+// 已有数据的数据库迁移前会备份到 dbPath.<组件>-v<当前版本>.bak；数据库版本高于程序支持的版本时返回错误，避免旧版程序写坏新版数据
+func Run(db *sqlx.DB, dbPath, component string, migrations []Migration) error {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	for i, m := range sorted {
+		if m.Version != i+1 {
+			return fmt.Errorf("迁移版本不连续: 期望 %d, 实际 %d", i+1, m.Version)
+		}
+	}
+	latest := len(sorted)
+
+	current, err := Version(db, component)
+	if err != nil {
+		return err
+	}
+	if current > latest {
+		return fmt.Errorf("数据库 %s 中 %s 的版本(%d)高于当前程序支持的版本(%d)，请升级程序", dbPath, component, current, latest)
+	}
+	if current == latest {
+		return nil
+	}
+
+	if err := backup(db, dbPath, component, current); err != nil {
+		return err
+	}
+	if _, err := db.Exec(versionsTableSQL); err != nil {
+		return fmt.Errorf("创建版本表失败: %w", err)
+	}
+
+	for _, m := range sorted[current:] {
+		if err := apply(db, component, m); err != nil {
+			return fmt.Errorf("%s 迁移到版本 %d（%s）失败: %w", component, m.Version, m.Description, err)
+		}
+		logger.Infof("数据库 %s 的 %s 已迁移到版本 %d: %s", dbPath, component, m.Version, m.Description)
+	}
+
+	return nil
+}
+
+// apply 在事务中执行一个版本的迁移
+func apply(db *sqlx.DB, component string, m Migration) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := m.Up(tx); err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+	INSERT INTO schema_migrations (component, version) VALUES (?, ?)
+	ON CONFLICT(component) DO UPDATE SET version = excluded.version, updated_at = CURRENT_TIMESTAMP
+	`, component, m.Version)
+	if err != nil {
+		return fmt.Errorf("更新数据库版本失败: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// backup 迁移前备份已有数据的数据库
+// 组件已有版本，或数据库来自没有版本记录的旧版程序（有表但没有 schema_migrations）时才备份，新建的数据库不备份
+func backup(db *sqlx.DB, dbPath, component string, version int) error {
+	if version == 0 {
+		var tables, versioned int
+		err := db.Get(&tables, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table'")
+		if err == nil {
+			err = db.Get(&versioned, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'")
+		}
+		if err != nil {
+			return fmt.Errorf("读取数据库结构失败: %w", err)
+		}
+		if tables == 0 || versioned > 0 {
+			return nil
+		}
+	}
+
+	backupPath := fmt.Sprintf("%s.%s-v%d.bak", dbPath, component, version)
+	if err := os.Remove(backupPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("删除旧备份失败: %w", err)
+	}
+	if _, err := db.Exec("VACUUM INTO ?", backupPath); err != nil {
+		return fmt.Errorf("迁移前备份数据库失败: %w", err)
+	}
+
+	logger.Infof("数据库迁移前已备份: %s", backupPath)
+	return nil
+}
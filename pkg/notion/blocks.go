@@ -0,0 +1,122 @@
+package notion
+
+import (
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// richText Notion的文本对象
+type richText struct {
+	Type string `json:"type"`
+	Text struct {
+		Content string `json:"content"`
+	} `json:"text"`
+}
+
+// textContent 段落、标题和列表项块的内容
+type textContent struct {
+	RichText []richText `json:"rich_text"`
+}
+
+// Block Notion页面中的一个块
+type Block struct {
+	Object           string       `json:"object"`
+	Type             string       `json:"type"`
+	Paragraph        *textContent `json:"paragraph,omitempty"`
+	Heading1         *textContent `json:"heading_1,omitempty"`
+	Heading2         *textContent `json:"heading_2,omitempty"`
+	Heading3         *textContent `json:"heading_3,omitempty"`
+	BulletedListItem *textContent `json:"bulleted_list_item,omitempty"`
+	Divider          *struct{}    `json:"divider,omitempty"`
+}
+
+// Paragraph 创建段落块
+func Paragraph(text string) Block {
+	return Block{Object: "block", Type: "paragraph", Paragraph: &textContent{RichText: richTexts(text)}}
+}
+
+// Heading 创建标题块，Notion只有三级标题，更深的级别按三级处理
+func Heading(level int, text string) Block {
+	content := &textContent{RichText: richTexts(text)}
+	switch {
+	case level <= 1:
+		return Block{Object: "block", Type: "heading_1", Heading1: content}
+	case level == 2:
+		return Block{Object: "block", Type: "heading_2", Heading2: content}
+	default:
+		return Block{Object: "block", Type: "heading_3", Heading3: content}
+	}
+}
+
+// BulletedListItem 创建无序列表项块
+func BulletedListItem(text string) Block {
+	return Block{Object: "block", Type: "bulleted_list_item", BulletedListItem: &textContent{RichText: richTexts(text)}}
+}
+
+// Divider 创建分隔线块
+func Divider() Block {
+	return Block{Object: "block", Type: "divider", Divider: &struct{}{}}
+}
+
+// MarkdownBlocks 将Markdown文本转换为块：# 标题转为标题块，- 和 * 开头的行转为列表项，
+// 其余连续的非空行合并为一个段落；行内格式按原文保留
+func MarkdownBlocks(text string) []Block {
+	var blocks []Block
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) > 0 {
+			blocks = append(blocks, Paragraph(strings.Join(paragraph, "\n")))
+			paragraph = nil
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "#"):
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			title := strings.TrimSpace(trimmed[level:])
+			if level > 6 || title == "" || !strings.HasPrefix(trimmed[level:], " ") {
+				paragraph = append(paragraph, trimmed)
+				continue
+			}
+			flush()
+			blocks = append(blocks, Heading(level, title))
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* "):
+			flush()
+			blocks = append(blocks, BulletedListItem(strings.TrimSpace(trimmed[2:])))
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flush()
+	return blocks
+}
+
+// richTexts 将文本按单个文本对象的长度上限（按UTF-16计算）切分
+func richTexts(text string) []richText {
+	var texts []richText
+	for text != "" {
+		end, units := 0, 0
+		for end < len(text) {
+			r, size := utf8.DecodeRuneInString(text[end:])
+			n := utf16.RuneLen(r)
+			if n < 0 {
+				n = 1
+			}
+			if units+n > maxRichText {
+				break
+			}
+			units += n
+			end += size
+		}
+		item := richText{Type: "text"}
+		item.Text.Content = text[:end]
+		texts = append(texts, item)
+		text = text[end:]
+	}
+	return texts
+}
@@ -0,0 +1,147 @@
+package notion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	apiBaseURL = "https://api.notion.com/v1"
+	apiVersion = "2022-06-28"
+
+	maxRichText = 2000 // 单个文本对象的最大长度（UTF-16编码单元）
+	maxChildren = 100  // 单次请求最多添加的块数
+	maxRetries  = 3    // 被限流时的最大重试次数
+)
+
+// pageIDPattern 页面ID：32位十六进制，可能带连字符，也可能是页面链接的结尾
+var pageIDPattern = regexp.MustCompile(`([0-9a-fA-F]{8})-?([0-9a-fA-F]{4})-?([0-9a-fA-F]{4})-?([0-9a-fA-F]{4})-?([0-9a-fA-F]{12})(?:[?#].*)?$`)
+
+// Client Notion API客户端
+type Client struct {
+	token   string
+	baseURL string
+	client  *http.Client
+}
+
+// Page 创建的页面
+type Page struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+}
+
+// NewClient 创建客户端，token 为Notion集成的访问令牌
+func NewClient(token string) *Client {
+	return &Client{
+		token:   token,
+		baseURL: apiBaseURL,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// ParsePageID 从页面ID或页面链接中解析出页面ID
+func ParsePageID(value string) (string, error) {
+	match := pageIDPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return "", fmt.Errorf("无效的Notion页面ID或链接: %s", value)
+	}
+	return strings.ToLower(strings.Join(match[1:6], "-")), nil
+}
+
+// CheckPage 检查访问令牌是否有效，以及集成能否访问页面
+func (c *Client) CheckPage(ctx context.Context, pageID string) error {
+	return c.do(ctx, http.MethodGet, "/pages/"+pageID, nil, nil)
+}
+
+// CreatePage 在 parentID 页面下创建子页面，块数超过单次请求的上限时分批追加
+func (c *Client) CreatePage(ctx context.Context, parentID, title string, blocks []Block) (*Page, error) {
+	first := blocks[:min(len(blocks), maxChildren)]
+	body := map[string]interface{}{
+		"parent": map[string]string{"page_id": parentID},
+		"properties": map[string]interface{}{
+			"title": map[string]interface{}{"title": richTexts(title)},
+		},
+		"children": first,
+	}
+
+	var page Page
+	if err := c.do(ctx, http.MethodPost, "/pages", body, &page); err != nil {
+		return nil, err
+	}
+	for start := len(first); start < len(blocks); start += maxChildren {
+		batch := blocks[start:min(len(blocks), start+maxChildren)]
+		if err := c.do(ctx, http.MethodPatch, "/blocks/"+page.ID+"/children", map[string]interface{}{"children": batch}, nil); err != nil {
+			return &page, fmt.Errorf("页面已创建，但追加内容失败: %w", err)
+		}
+	}
+	return &page, nil
+}
+
+// do 发送请求并解析响应，被限流（429）时按 Retry-After 等待后重试
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("序列化请求失败: %w", err)
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("创建请求失败: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+c.token)
+		req.Header.Set("Notion-Version", apiVersion)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("请求Notion失败: %w", err)
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("读取Notion响应失败: %w", err)
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < maxRetries {
+			wait := time.Second
+			if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+				wait = time.Duration(seconds) * time.Second
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+			continue
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			var apiErr struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			}
+			if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Message != "" {
+				return fmt.Errorf("Notion返回错误 (%d %s): %s", resp.StatusCode, apiErr.Code, apiErr.Message)
+			}
+			return fmt.Errorf("Notion返回状态码 %d", resp.StatusCode)
+		}
+		if out != nil {
+			if err := json.Unmarshal(respBody, out); err != nil {
+				return fmt.Errorf("解析Notion响应失败: %w", err)
+			}
+		}
+		return nil
+	}
+}
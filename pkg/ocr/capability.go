@@ -0,0 +1,125 @@
+package ocr
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+
+	"pdf-ocr-ai/pkg/config"
+)
+
+// probeTimeout 能力检测请求的超时时间
+const probeTimeout = 30 * time.Second
+
+// ErrVisionUnknown 模型正常回答但没有答出图片内容，可能只是回答不规范，无法据此判断不支持图片输入
+var ErrVisionUnknown = errors.New("无法判断模型是否支持图片识别")
+
+// ProbeVision 发送一张很小的测试图片（白底黑色方块），检测模型是否支持图片输入
+// 服务拒绝图片时返回不支持；模型回答不出图片内容时返回 ErrVisionUnknown；网络、认证等错误通过 error 返回
+func (c *OpenAIClient) ProbeVision(ctx context.Context, model string) (config.ModelCapability, error) {
+	capability := config.ModelCapability{
+		BaseURL:   c.config.BaseURL,
+		CheckedAt: time.Now().Format(time.RFC3339),
+	}
+
+	if err := c.rateLimiter.Wait(ctx); err != nil {
+		return capability, fmt.Errorf("频率限制等待失败: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	req := openai.ChatCompletionRequest{
+		Model: model,
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role: openai.ChatMessageRoleUser,
+				MultiContent: []openai.ChatMessagePart{
+					{
+						Type: openai.ChatMessagePartTypeText,
+						Text: "图片中央方块是什么颜色？只回答颜色，不要解释。What color is the square? Answer with one word.",
+					},
+					{
+						Type: openai.ChatMessagePartTypeImageURL,
+						ImageURL: &openai.ChatMessageImageURL{
+							URL:    "data:image/png;base64," + probeImage(),
+							Detail: openai.ImageURLDetailLow,
+						},
+					},
+				},
+			},
+		},
+		MaxTokens:   10,
+		Temperature: 0,
+	}
+
+	// 只请求一次，不重试：检测结果需要明确区分“不支持”和“暂时失败”
+	resp, err := c.createChatCompletionWithFloatTimestamp(ctx, req)
+	if err != nil {
+		if isImageRejectedError(err) {
+			capability.Detail = err.Error()
+			return capability, nil
+		}
+		return capability, fmt.Errorf("检测模型能力失败: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return capability, fmt.Errorf("检测模型能力失败: 未收到AI响应")
+	}
+
+	// 部分服务会忽略不支持的图片内容并正常返回，因此需要检查回答
+	answer := strings.ToLower(strings.TrimSpace(resp.Choices[0].Message.Content))
+	if !strings.Contains(answer, "黑") && !strings.Contains(answer, "black") {
+		return capability, fmt.Errorf("%w: 模型未能识别测试图片，回答: %s", ErrVisionUnknown, answer)
+	}
+	capability.Vision = true
+	return capability, nil
+}
+
+// isImageRejectedError 判断是否为服务拒绝图片输入的错误（请求格式错误类的状态码）
+func isImageRejectedError(err error) bool {
+	statusCode := 0
+	var apiErr *openai.APIError
+	var reqErr *openai.RequestError
+	switch {
+	case errors.As(err, &apiErr):
+		statusCode = apiErr.HTTPStatusCode
+	case errors.As(err, &reqErr):
+		statusCode = reqErr.HTTPStatusCode
+	default:
+		// 自定义解析路径返回的错误
+		errStr := err.Error()
+		for _, code := range []int{http.StatusBadRequest, http.StatusUnsupportedMediaType, http.StatusUnprocessableEntity} {
+			if strings.Contains(errStr, fmt.Sprintf("状态码 %d", code)) {
+				statusCode = code
+			}
+		}
+	}
+
+	switch statusCode {
+	case http.StatusBadRequest, http.StatusUnsupportedMediaType, http.StatusUnprocessableEntity:
+		return true
+	}
+	return false
+}
+
+// probeImage 生成检测使用的测试图片（base64编码的PNG）
+func probeImage() string {
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(16, 16, 48, 48), image.NewUniform(color.Black), image.Point{}, draw.Src)
+
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
@@ -0,0 +1,258 @@
+package ocr
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// defaultContextLength 无法识别模型时假定的上下文长度
+	defaultContextLength = 8192
+	// defaultMaxOutput 无法识别模型时的最大输出长度
+	defaultMaxOutput = 4000
+	// chunkOverlapTokens 相邻分段之间重叠的长度，便于模型衔接上下文
+	chunkOverlapTokens = 200
+	// promptReserveTokens 为分段说明和消息格式预留的长度
+	promptReserveTokens = 300
+	// minInputTokens 输入长度下限，避免上下文过小时分段过碎
+	minInputTokens = 1000
+)
+
+// ModelLimits 模型的长度限制（tokens）
+type ModelLimits struct {
+	ContextLength int `json:"context_length"` // 上下文长度（输入和输出合计）
+	MaxOutput     int `json:"max_output"`     // 单次请求的最大输出长度
+}
+
+// knownModelLimits 常见模型的长度限制，按前缀匹配（更长、更具体的前缀在前）
+var knownModelLimits = []struct {
+	prefix string
+	limits ModelLimits
+}{
+	{"gpt-4.1", ModelLimits{1047576, 32768}},
+	{"gpt-4o", ModelLimits{128000, 16384}},
+	{"gpt-4-turbo", ModelLimits{128000, 4096}},
+	{"gpt-4-vision", ModelLimits{128000, 4096}},
+	{"gpt-4-32k", ModelLimits{32768, 4096}},
+	{"gpt-4", ModelLimits{8192, 4096}},
+	{"gpt-3.5-turbo", ModelLimits{16385, 4096}},
+	{"o1", ModelLimits{200000, 32768}},
+	{"o3", ModelLimits{200000, 32768}},
+	{"o4", ModelLimits{200000, 32768}},
+	{"claude", ModelLimits{200000, 8192}},
+	{"gemini", ModelLimits{1048576, 8192}},
+	{"deepseek", ModelLimits{64000, 8192}},
+	{"moonshot-v1-8k", ModelLimits{8192, 4096}},
+	{"moonshot-v1-32k", ModelLimits{32768, 4096}},
+	{"moonshot-v1-128k", ModelLimits{131072, 4096}},
+	{"kimi", ModelLimits{131072, 8192}},
+	{"qwen", ModelLimits{32768, 8192}},
+	{"glm-4", ModelLimits{128000, 4096}},
+}
+
+// LimitsFor 获取模型的长度限制：contextLength 大于0时（配置或服务返回的值）覆盖上下文长度，
+// 其余按常见模型名称判断；输出长度不超过上下文的一半
+func LimitsFor(model string, contextLength int) ModelLimits {
+	limits := ModelLimits{ContextLength: defaultContextLength, MaxOutput: defaultMaxOutput}
+
+	lower := strings.ToLower(model)
+	// 兼容带服务商前缀的模型名，如 openai/gpt-4o
+	if i := strings.LastIndex(lower, "/"); i >= 0 {
+		lower = lower[i+1:]
+	}
+	for _, known := range knownModelLimits {
+		if strings.HasPrefix(lower, known.prefix) {
+			limits = known.limits
+			break
+		}
+	}
+
+	if contextLength > 0 {
+		limits.ContextLength = contextLength
+	}
+	if limits.MaxOutput > limits.ContextLength/2 {
+		limits.MaxOutput = limits.ContextLength / 2
+	}
+	return limits
+}
+
+// EstimateTokens 估算文本的token数：中日韩字符约每字1个，其他文字约每4个字符1个
+func EstimateTokens(text string) int {
+	cjk, other := 0, 0
+	for _, r := range text {
+		if isCJK(r) {
+			cjk++
+		} else {
+			other++
+		}
+	}
+	return cjk + (other+3)/4
+}
+
+// isCJK 是否为中日韩字符
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// inputTokenBudget 计算每次请求可用于正文的长度
+func inputTokenBudget(limits ModelLimits, prompt string) int {
+	budget := limits.ContextLength - limits.MaxOutput - EstimateTokens(prompt) - promptReserveTokens
+	if budget < minInputTokens {
+		budget = minInputTokens
+	}
+	return budget
+}
+
+// TextChunk 长文本的一个分段
+type TextChunk struct {
+	Context string // 与上一段重叠的内容，仅作上下文参考
+	Content string // 本段需要处理的内容
+}
+
+// message 构建分段请求的用户消息
+func (c TextChunk) message() string {
+	if c.Context == "" {
+		return c.Content
+	}
+	return fmt.Sprintf("【上文（仅供参考，不要输出）】\n%s\n\n【本部分】\n%s", c.Context, c.Content)
+}
+
+// chunkPrompt 为分段请求补充说明，使各段结果可以直接拼接
+func chunkPrompt(prompt string, index, total int) string {
+	return fmt.Sprintf("%s\n\n注意：这是一篇长文档的第 %d/%d 部分。只处理【本部分】的内容，【上文】仅用于理解上下文，不要重复输出；不要添加开场白或总结，以便与其他部分的结果直接拼接。", prompt, index+1, total)
+}
+
+// SplitText 将文本按段落（必要时按行、按字符）切分为不超过 maxTokens 的分段
+// 从第二段开始，附带上一段末尾约 overlapTokens 的内容作为上下文
+func SplitText(text string, maxTokens, overlapTokens int) []TextChunk {
+	if EstimateTokens(text) <= maxTokens {
+		return []TextChunk{{Content: text}}
+	}
+
+	// 上下文也计入请求长度
+	contentTokens := maxTokens - overlapTokens
+	if contentTokens < maxTokens/2 {
+		contentTokens = maxTokens / 2
+	}
+
+	var chunks []TextChunk
+	var current strings.Builder
+	currentTokens := 0
+	flush := func() {
+		if currentTokens == 0 {
+			return
+		}
+		chunk := TextChunk{Content: strings.TrimSpace(current.String())}
+		if len(chunks) > 0 {
+			chunk.Context = TailTokens(chunks[len(chunks)-1].Content, overlapTokens)
+		}
+		chunks = append(chunks, chunk)
+		current.Reset()
+		currentTokens = 0
+	}
+
+	for _, piece := range splitPieces(text, contentTokens) {
+		tokens := EstimateTokens(piece)
+		if currentTokens > 0 && currentTokens+tokens > contentTokens {
+			flush()
+		}
+		current.WriteString(piece)
+		currentTokens += tokens
+	}
+	flush()
+	return chunks
+}
+
+// splitPieces 按段落切分，超长段落再按行、按字符切分，每块不超过 maxTokens（保留原换行）
+func splitPieces(text string, maxTokens int) []string {
+	var pieces []string
+	for _, paragraph := range strings.SplitAfter(text, "\n\n") {
+		if EstimateTokens(paragraph) <= maxTokens {
+			pieces = append(pieces, paragraph)
+			continue
+		}
+		for _, line := range strings.SplitAfter(paragraph, "\n") {
+			if EstimateTokens(line) <= maxTokens {
+				pieces = append(pieces, line)
+				continue
+			}
+			pieces = append(pieces, splitRunes(line, maxTokens)...)
+		}
+	}
+	return pieces
+}
+
+// splitRunes 按字符切分没有换行的超长文本
+func splitRunes(text string, maxTokens int) []string {
+	var pieces []string
+	start, tokens, other := 0, 0, 0
+	for i, r := range text {
+		// 与 EstimateTokens 一致：非中日韩字符每4个计1个token
+		cost := 1
+		if !isCJK(r) {
+			other++
+			if other%4 != 1 {
+				cost = 0
+			}
+		}
+		if tokens+cost > maxTokens && i > start {
+			pieces = append(pieces, text[start:i])
+			start, tokens, other, cost = i, 0, 0, 1
+			if !isCJK(r) {
+				other = 1
+			}
+		}
+		tokens += cost
+	}
+	if start < len(text) {
+		pieces = append(pieces, text[start:])
+	}
+	return pieces
+}
+
+// HeadTokens 取文本开头约 tokens 长度的内容，尽量在行尾结束
+func HeadTokens(text string, tokens int) string {
+	if tokens <= 0 {
+		return ""
+	}
+	end := 0
+	cjk, other := 0, 0
+	for end < len(text) && cjk+(other+3)/4 < tokens {
+		r, size := utf8.DecodeRuneInString(text[end:])
+		if isCJK(r) {
+			cjk++
+		} else {
+			other++
+		}
+		end += size
+	}
+	if i := strings.LastIndexByte(text[:end], '\n'); i >= 0 && i > end/2 {
+		end = i
+	}
+	return strings.TrimSpace(text[:end])
+}
+
+// TailTokens 取文本末尾约 tokens 长度的内容，尽量从行首开始
+func TailTokens(text string, tokens int) string {
+	if tokens <= 0 {
+		return ""
+	}
+	end := len(text)
+	start := end
+	cjk, other := 0, 0
+	for start > 0 && cjk+(other+3)/4 < tokens {
+		r, size := utf8.DecodeLastRuneInString(text[:start])
+		if isCJK(r) {
+			cjk++
+		} else {
+			other++
+		}
+		start -= size
+	}
+	if i := strings.IndexByte(text[start:end], '\n'); i >= 0 && i < (end-start)/2 {
+		start += i + 1
+	}
+	return strings.TrimSpace(text[start:end])
+}
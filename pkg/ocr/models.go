@@ -0,0 +1,90 @@
+package ocr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ListModels 通过模型列表端点获取可用模型
+// 已检测过的模型按检测结果标记是否支持视觉，其余按内置模型信息或模型名称判断
+// 服务返回的上下文长度（如 vLLM 的 max_model_len）会被记录，用于之后的请求
+func (c *OpenAIClient) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	endpoint := c.config.ModelsEndpoint
+	if endpoint == "" {
+		endpoint = "/models"
+	}
+	if !strings.HasPrefix(endpoint, "/") {
+		endpoint = "/" + endpoint
+	}
+	url := strings.TrimSuffix(c.config.BaseURL, "/") + endpoint
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP请求失败: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.config.APIKey)
+
+	httpClient := &http.Client{Timeout: time.Duration(c.config.Timeout) * time.Second}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("获取模型列表失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API返回错误状态码 %d: %s", resp.StatusCode, string(body))
+	}
+
+	var list struct {
+		Data []struct {
+			ID      string `json:"id"`
+			OwnedBy string `json:"owned_by"`
+
+			// 不同服务返回上下文长度的字段不同
+			ContextLength    int `json:"context_length"`     // OpenRouter 等
+			MaxModelLen      int `json:"max_model_len"`      // vLLM
+			MaxContextLength int `json:"max_context_length"` // LM Studio
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("解析模型列表失败: %w", err)
+	}
+
+	known := make(map[string]ModelInfo)
+	for _, model := range c.GetSupportedModels() {
+		known[model.ID] = model
+	}
+
+	models := make([]ModelInfo, 0, len(list.Data))
+	for _, item := range list.Data {
+		model, ok := known[item.ID]
+		if !ok {
+			model = ModelInfo{ID: item.ID, Name: item.ID, Description: item.OwnedBy}
+			model.SupportsVision = c.info.Capabilities.Vision && c.isVisionModel(item.ID)
+		}
+		if capability, ok := c.config.Capability(item.ID); ok {
+			model.SupportsVision = capability.Vision
+		}
+
+		for _, listed := range []int{item.ContextLength, item.MaxModelLen, item.MaxContextLength} {
+			if listed > 0 {
+				c.listedContext.Store(item.ID, listed)
+				break
+			}
+		}
+		limits := c.ModelLimits(item.ID)
+		model.ContextLength = limits.ContextLength
+		model.MaxTokens = limits.MaxOutput
+		models = append(models, model)
+	}
+	return models, nil
+}
@@ -7,23 +7,29 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"pdf-ocr-ai/pkg/config"
+	"pdf-ocr-ai/pkg/logger"
 	"pdf-ocr-ai/pkg/ratelimiter"
 
 	"github.com/sashabaranov/go-openai"
 )
 
-// OpenAIClient OpenAI客户端
+// OpenAIClient OpenAI兼容接口的客户端，内置的提供方都使用此实现
 type OpenAIClient struct {
+	info        ProviderInfo
 	client      *openai.Client
 	config      config.AIConfig
 	rateLimiter *ratelimiter.RateLimiter
+	usage       usageTracker
+	onRateLimit func(err error, delay time.Duration) // 遇到API限流时的回调
+
+	listedContext sync.Map // 模型列表中返回的上下文长度（模型名称 -> tokens）
 }
 
 // OCRResult OCR识别结果
@@ -33,8 +39,18 @@ type OCRResult struct {
 	Error      string  `json:"error,omitempty"`
 }
 
+var _ Provider = (*OpenAIClient)(nil)
+
 // NewOpenAIClient 创建OpenAI客户端
 func NewOpenAIClient(cfg config.AIConfig) *OpenAIClient {
+	return newOpenAIClient(ProviderInfo{ID: DefaultProviderID, Capabilities: Capabilities{Vision: true, Text: true, ListModels: true}}, cfg)
+}
+
+// newOpenAIClient 按提供方创建客户端，未配置服务地址时使用提供方的默认地址
+func newOpenAIClient(info ProviderInfo, cfg config.AIConfig) *OpenAIClient {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = info.DefaultBaseURL
+	}
 	clientConfig := openai.DefaultConfig(cfg.APIKey)
 	if cfg.BaseURL != "" {
 		clientConfig.BaseURL = cfg.BaseURL
@@ -46,14 +62,36 @@ func NewOpenAIClient(cfg config.AIConfig) *OpenAIClient {
 	rateLimiter := ratelimiter.NewRateLimiter(cfg.RequestInterval, cfg.BurstLimit)
 
 	return &OpenAIClient{
+		info:        info,
 		client:      client,
 		config:      cfg,
 		rateLimiter: rateLimiter,
 	}
 }
 
-// RecognizeImage 识别图片中的文字
-func (c *OpenAIClient) RecognizeImage(ctx context.Context, imagePath string) (*OCRResult, error) {
+// ID 提供方ID
+func (c *OpenAIClient) ID() string {
+	return c.info.ID
+}
+
+// Capabilities 提供方支持的功能
+func (c *OpenAIClient) Capabilities() Capabilities {
+	return c.info.Capabilities
+}
+
+// ModelLimits 获取模型的长度限制，上下文长度优先使用配置值，其次是模型列表返回的值
+func (c *OpenAIClient) ModelLimits(model string) ModelLimits {
+	contextLength := c.config.ModelContextLengths[model]
+	if contextLength <= 0 {
+		if listed, ok := c.listedContext.Load(model); ok {
+			contextLength = listed.(int)
+		}
+	}
+	return LimitsFor(model, contextLength)
+}
+
+// RecognizeImage 使用指定模型识别图片中的文字，model 为空时使用配置的OCR模型
+func (c *OpenAIClient) RecognizeImage(ctx context.Context, imagePath string, model string) (*OCRResult, error) {
 	// 等待频率限制
 	if err := c.rateLimiter.Wait(ctx); err != nil {
 		return nil, fmt.Errorf("频率限制等待失败: %w", err)
@@ -73,7 +111,10 @@ func (c *OpenAIClient) RecognizeImage(ctx context.Context, imagePath string) (*O
 	defer cancel()
 
 	// 获取OCR专用模型，如果没有配置则使用默认模型
-	ocrModel := c.config.OCRModel
+	ocrModel := model
+	if ocrModel == "" {
+		ocrModel = c.config.OCRModel
+	}
 	if ocrModel == "" {
 		ocrModel = c.config.Model
 	}
@@ -86,12 +127,16 @@ func (c *OpenAIClient) RecognizeImage(ctx context.Context, imagePath string) (*O
 	}
 }
 
-// isVisionModel 检查是否为视觉模型 - 使用更宽松的检测策略
+// isVisionModel 检查是否为视觉模型 - 优先使用能力检测结果，未检测过时按名称宽松判断
 func (c *OpenAIClient) isVisionModel(model string) bool {
 	if model == "" {
 		return false
 	}
 
+	if capability, ok := c.config.Capability(model); ok {
+		return capability.Vision
+	}
+
 	lowerModel := strings.ToLower(model)
 
 	// 明确不支持视觉的模型
@@ -130,7 +175,8 @@ func (c *OpenAIClient) recognizeWithVision(ctx context.Context, base64Image stri
 2. 如果包含表格，请用Markdown格式输出
 3. 直接返回识别的文字内容，不要使用代码块格式，不要添加任何解释或说明
 4. 不要在返回内容中添加 OCR Start 和 OCR End 标记
-5. 如果无法识别任何文字，返回空字符串`,
+5. 正文中的脚注上标编号写作 <sup>编号</sup>，页面底部的脚注保持在末尾，每条单独一行并以编号开头
+6. 如果无法识别任何文字，返回空字符串`,
 			},
 			{
 				Role: openai.ChatMessageRoleUser,
@@ -149,7 +195,7 @@ func (c *OpenAIClient) recognizeWithVision(ctx context.Context, base64Image stri
 				},
 			},
 		},
-		MaxTokens:   4000,
+		MaxTokens:   c.ModelLimits(model).MaxOutput,
 		Temperature: 0.1, // 低温度确保一致性
 	}
 
@@ -198,7 +244,7 @@ func (c *OpenAIClient) recognizeWithText(ctx context.Context, imagePath string,
 
 // GetSupportedModels 获取支持的模型列表
 func (c *OpenAIClient) GetSupportedModels() []ModelInfo {
-	return []ModelInfo{
+	models := []ModelInfo{
 		{
 			ID:             "gpt-4-vision-preview",
 			Name:           "GPT-4 Vision Preview",
@@ -248,6 +294,12 @@ func (c *OpenAIClient) GetSupportedModels() []ModelInfo {
 			Recommended:    false,
 		},
 	}
+	for i := range models {
+		limits := c.ModelLimits(models[i].ID)
+		models[i].ContextLength = limits.ContextLength
+		models[i].MaxTokens = limits.MaxOutput
+	}
+	return models
 }
 
 // ModelInfo 模型信息
@@ -257,6 +309,7 @@ type ModelInfo struct {
 	Description    string `json:"description"`
 	SupportsVision bool   `json:"supports_vision"`
 	MaxTokens      int    `json:"max_tokens"`
+	ContextLength  int    `json:"context_length"`
 	Recommended    bool   `json:"recommended"`
 }
 
@@ -289,13 +342,18 @@ func (c *OpenAIClient) createChatCompletionWithFloatTimestamp(ctx context.Contex
 	// 首先尝试使用标准的API调用
 	resp, err := c.client.CreateChatCompletion(ctx, req)
 	if err == nil {
+		c.usage.record(resp.Usage)
 		return resp, nil
 	}
 
 	// 如果错误包含时间戳解析问题，使用自定义解析
 	if strings.Contains(err.Error(), "cannot unmarshal number") && strings.Contains(err.Error(), "into Go struct field") && strings.Contains(err.Error(), "created") {
-		log.Printf("检测到时间戳解析错误，使用自定义解析: %v", err)
-		return c.createChatCompletionWithCustomParsing(ctx, req)
+		logger.Errorf("检测到时间戳解析错误，使用自定义解析: %v", err)
+		resp, err = c.createChatCompletionWithCustomParsing(ctx, req)
+		if err == nil {
+			c.usage.record(resp.Usage)
+		}
+		return resp, err
 	}
 
 	// 其他错误直接返回
@@ -373,8 +431,8 @@ func (c *OpenAIClient) createChatCompletionWithCustomParsing(ctx context.Context
 	return customResp.ToStandardResponse(), nil
 }
 
-// RecognizeImageFromReader 从Reader识别图片
-func (c *OpenAIClient) RecognizeImageFromReader(ctx context.Context, reader io.Reader) (*OCRResult, error) {
+// RecognizeImageFromReader 使用提供方从Reader识别图片
+func RecognizeImageFromReader(ctx context.Context, provider OCRProvider, reader io.Reader) (*OCRResult, error) {
 	// 读取数据
 	imageData, err := io.ReadAll(reader)
 	if err != nil {
@@ -395,25 +453,16 @@ func (c *OpenAIClient) RecognizeImageFromReader(ctx context.Context, reader io.R
 	}
 
 	// 调用识别
-	return c.RecognizeImage(ctx, tmpFile.Name())
+	return provider.RecognizeImage(ctx, tmpFile.Name(), "")
 }
 
-// ProcessWithAI 使用AI处理文本（纠错、总结等）
-func (c *OpenAIClient) ProcessWithAI(ctx context.Context, text string, prompt string) (string, error) {
+// ProcessText 使用AI处理文本（纠错、总结等），超出模型上下文的长文本自动分段处理后拼接
+func (c *OpenAIClient) ProcessText(ctx context.Context, text string, prompt string) (string, error) {
 	// 检查输入文本是否为空或空字符串
 	if text == "空字符串" || strings.TrimSpace(text) == "" {
 		return "没有需要处理的内容", nil
 	}
 
-	// 等待频率限制
-	if err := c.rateLimiter.Wait(ctx); err != nil {
-		return "", fmt.Errorf("频率限制等待失败: %w", err)
-	}
-
-	// 创建超时上下文
-	timeoutCtx, cancel := context.WithTimeout(ctx, time.Duration(c.config.Timeout)*time.Second)
-	defer cancel()
-
 	// 获取文本处理专用模型，如果没有配置则使用默认模型
 	textModel := c.config.TextModel
 	if textModel == "" {
@@ -423,9 +472,39 @@ func (c *OpenAIClient) ProcessWithAI(ctx context.Context, text string, prompt st
 		textModel = "gpt-4" // 最后的备选方案
 	}
 
+	limits := c.ModelLimits(textModel)
+	budget := inputTokenBudget(limits, prompt)
+	chunks := SplitText(text, budget, chunkOverlapTokens)
+	if len(chunks) == 1 {
+		return c.completeText(ctx, textModel, limits.MaxOutput, prompt, text)
+	}
+
+	logger.Infof("文本约 %d tokens，超出模型 %s 的输入限制（%d），分 %d 段处理", EstimateTokens(text), textModel, budget, len(chunks))
+	results := make([]string, 0, len(chunks))
+	for i, chunk := range chunks {
+		result, err := c.completeText(ctx, textModel, limits.MaxOutput, chunkPrompt(prompt, i, len(chunks)), chunk.message())
+		if err != nil {
+			return "", fmt.Errorf("处理第 %d/%d 段失败: %w", i+1, len(chunks), err)
+		}
+		results = append(results, result)
+	}
+	return strings.Join(results, "\n\n"), nil
+}
+
+// completeText 发送一次文本处理请求
+func (c *OpenAIClient) completeText(ctx context.Context, model string, maxOutput int, prompt string, text string) (string, error) {
+	// 等待频率限制
+	if err := c.rateLimiter.Wait(ctx); err != nil {
+		return "", fmt.Errorf("频率限制等待失败: %w", err)
+	}
+
+	// 创建超时上下文
+	timeoutCtx, cancel := context.WithTimeout(ctx, time.Duration(c.config.Timeout)*time.Second)
+	defer cancel()
+
 	// 构建请求
 	req := openai.ChatCompletionRequest{
-		Model: textModel, // 使用配置的文本处理模型
+		Model: model, // 使用配置的文本处理模型
 		Messages: []openai.ChatCompletionMessage{
 			{
 				Role:    openai.ChatMessageRoleSystem,
@@ -436,7 +515,7 @@ func (c *OpenAIClient) ProcessWithAI(ctx context.Context, text string, prompt st
 				Content: text,
 			},
 		},
-		MaxTokens:   4000,
+		MaxTokens:   maxOutput,
 		Temperature: 0.3,
 	}
 
@@ -462,6 +541,9 @@ func (c *OpenAIClient) ProcessWithAI(ctx context.Context, text string, prompt st
 
 // UpdateConfig 更新配置
 func (c *OpenAIClient) UpdateConfig(cfg config.AIConfig) {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = c.info.DefaultBaseURL
+	}
 	c.config = cfg
 
 	// 更新客户端配置
@@ -482,9 +564,15 @@ func (c *OpenAIClient) Close() {
 	}
 }
 
+// SetRateLimitHandler 设置遇到API限流（等待重试）时的回调
+func (c *OpenAIClient) SetRateLimitHandler(handler func(err error, delay time.Duration)) {
+	c.onRateLimit = handler
+}
+
 // getRetryConfig 获取重试配置
 func (c *OpenAIClient) getRetryConfig() RetryConfig {
 	config := DefaultRetryConfig
+	config.OnRateLimit = c.onRateLimit
 
 	// 使用配置中的重试参数
 	if c.config.MaxRetries > 0 {
@@ -503,6 +591,8 @@ type RetryConfig struct {
 	MaxRetries int           // 最大重试次数
 	BaseDelay  time.Duration // 基础延迟时间
 	MaxDelay   time.Duration // 最大延迟时间
+
+	OnRateLimit func(err error, delay time.Duration) // 遇到限流错误、等待重试前调用
 }
 
 // DefaultRetryConfig 默认重试配置
@@ -551,6 +641,14 @@ func isRetryableError(err error) bool {
 	return false
 }
 
+// isRateLimitError 判断是否为API限流错误
+func isRateLimitError(err error) bool {
+	errStr := strings.ToLower(err.Error())
+	return strings.Contains(errStr, "rate limit") ||
+		strings.Contains(errStr, "too many requests") ||
+		strings.Contains(errStr, "429")
+}
+
 // calculateBackoffDelay 计算退避延迟时间（指数退避）
 func calculateBackoffDelay(attempt int, config RetryConfig) time.Duration {
 	if attempt <= 0 {
@@ -589,13 +687,17 @@ func retryWithBackoff(ctx context.Context, config RetryConfig, operation func()
 
 		// 检查是否为可重试的错误
 		if !isRetryableError(err) {
-			log.Printf("遇到不可重试的错误，停止重试: %v", err)
+			logger.Errorf("遇到不可重试的错误，停止重试: %v", err)
 			return err
 		}
 
 		// 计算延迟时间
 		delay := calculateBackoffDelay(attempt, config)
-		log.Printf("第 %d 次重试失败: %v，%v 后重试", attempt+1, err, delay)
+		logger.Errorf("第 %d 次重试失败: %v，%v 后重试", attempt+1, err, delay)
+
+		if config.OnRateLimit != nil && isRateLimitError(err) {
+			config.OnRateLimit(err, delay)
+		}
 
 		// 等待延迟时间
 		select {
@@ -606,7 +708,7 @@ func retryWithBackoff(ctx context.Context, config RetryConfig, operation func()
 		}
 	}
 
-	log.Printf("重试 %d 次后仍然失败，最后错误: %v", config.MaxRetries, lastErr)
+	logger.Errorf("重试 %d 次后仍然失败，最后错误: %v", config.MaxRetries, lastErr)
 	return fmt.Errorf("重试 %d 次后仍然失败: %w", config.MaxRetries, lastErr)
 }
 
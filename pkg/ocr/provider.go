@@ -0,0 +1,132 @@
+package ocr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"pdf-ocr-ai/pkg/config"
+)
+
+// DefaultProviderID 未配置服务类型时使用的提供方
+const DefaultProviderID = "openai"
+
+// ErrAPIKeyRequired 提供方需要API密钥但未配置
+var ErrAPIKeyRequired = errors.New("未配置API密钥")
+
+// Capabilities 服务提供方支持的功能
+type Capabilities struct {
+	Vision     bool `json:"vision"`      // 支持图片识别（具体模型是否支持仍需检测）
+	Text       bool `json:"text"`        // 支持文本处理
+	ListModels bool `json:"list_models"` // 支持获取模型列表
+}
+
+// OCRProvider 图片文字识别服务
+type OCRProvider interface {
+	// RecognizeImage 识别图片中的文字，model 为空时使用配置的OCR模型
+	RecognizeImage(ctx context.Context, imagePath string, model string) (*OCRResult, error)
+}
+
+// TextProvider 文本处理服务（纠错、总结等）
+type TextProvider interface {
+	// ProcessText 按提示词处理文本
+	ProcessText(ctx context.Context, text string, prompt string) (string, error)
+}
+
+// Provider AI服务提供方，新的后端实现此接口并通过 Register 注册
+type Provider interface {
+	OCRProvider
+	TextProvider
+
+	// ID 注册时使用的提供方ID
+	ID() string
+	// Capabilities 提供方支持的功能
+	Capabilities() Capabilities
+	// ListModels 获取可用模型列表
+	ListModels(ctx context.Context) ([]ModelInfo, error)
+	// ModelLimits 获取模型的上下文长度和最大输出长度
+	ModelLimits(model string) ModelLimits
+	// ProbeVision 检测模型是否支持图片输入
+	ProbeVision(ctx context.Context, model string) (config.ModelCapability, error)
+
+	// UpdateConfig 配置变化后更新连接参数和频率限制
+	UpdateConfig(cfg config.AIConfig)
+	// SetRateLimitHandler 设置遇到API限流（等待重试）时的回调
+	SetRateLimitHandler(handler func(err error, delay time.Duration))
+	// GetUsage 获取启动以来的累计用量
+	GetUsage() TokenUsage
+	// Close 释放资源
+	Close()
+}
+
+// ProviderInfo 已注册提供方的说明，供界面选择
+type ProviderInfo struct {
+	ID             string       `json:"id"`
+	Name           string       `json:"name"`
+	Description    string       `json:"description"`
+	DefaultBaseURL string       `json:"default_base_url"` // 未配置服务地址时使用
+	APIKeyOptional bool         `json:"api_key_optional"` // 本地服务通常不需要API密钥
+	Capabilities   Capabilities `json:"capabilities"`
+}
+
+// Factory 按配置创建提供方
+type Factory func(info ProviderInfo, cfg config.AIConfig) (Provider, error)
+
+// registration 注册的提供方
+type registration struct {
+	info    ProviderInfo
+	factory Factory
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]registration)
+)
+
+// Register 注册提供方，ID 重复时覆盖之前的注册
+func Register(info ProviderInfo, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[info.ID] = registration{info: info, factory: factory}
+}
+
+// Providers 获取已注册的提供方（按ID排序，默认提供方在最前）
+func Providers() []ProviderInfo {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	providers := make([]ProviderInfo, 0, len(registry))
+	for _, r := range registry {
+		providers = append(providers, r.info)
+	}
+	sort.Slice(providers, func(i, j int) bool {
+		if (providers[i].ID == DefaultProviderID) != (providers[j].ID == DefaultProviderID) {
+			return providers[i].ID == DefaultProviderID
+		}
+		return providers[i].ID < providers[j].ID
+	})
+	return providers
+}
+
+// NewProvider 按配置中的服务类型创建提供方，未配置时使用 openai
+// 提供方需要API密钥而配置中没有时返回 ErrAPIKeyRequired
+func NewProvider(cfg config.AIConfig) (Provider, error) {
+	id := cfg.Provider
+	if id == "" {
+		id = DefaultProviderID
+	}
+
+	registryMu.RLock()
+	r, ok := registry[id]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("未知的AI服务类型: %s", id)
+	}
+	if cfg.APIKey == "" && !r.info.APIKeyOptional {
+		return nil, ErrAPIKeyRequired
+	}
+	return r.factory(r.info, cfg)
+}
@@ -0,0 +1,55 @@
+package ocr
+
+import "pdf-ocr-ai/pkg/config"
+
+// 内置的提供方均使用 OpenAI 兼容接口
+func init() {
+	openAICompatible := Capabilities{Vision: true, Text: true, ListModels: true}
+
+	Register(ProviderInfo{
+		ID:             DefaultProviderID,
+		Name:           "OpenAI",
+		Description:    "OpenAI 官方接口，或任意 OpenAI 兼容服务",
+		DefaultBaseURL: "https://api.openai.com/v1",
+		Capabilities:   openAICompatible,
+	}, newCompatibleProvider)
+
+	Register(ProviderInfo{
+		ID:             "vllm",
+		Name:           "vLLM",
+		Description:    "本地部署的 vLLM 服务（需加载多模态模型才能OCR）",
+		DefaultBaseURL: "http://localhost:8000/v1",
+		APIKeyOptional: true,
+		Capabilities:   openAICompatible,
+	}, newCompatibleProvider)
+
+	Register(ProviderInfo{
+		ID:             "lmstudio",
+		Name:           "LM Studio",
+		Description:    "LM Studio 本地服务（需加载视觉模型才能OCR）",
+		DefaultBaseURL: "http://localhost:1234/v1",
+		APIKeyOptional: true,
+		Capabilities:   openAICompatible,
+	}, newCompatibleProvider)
+
+	Register(ProviderInfo{
+		ID:             "deepseek",
+		Name:           "DeepSeek",
+		Description:    "DeepSeek 开放平台，仅支持文本处理",
+		DefaultBaseURL: "https://api.deepseek.com/v1",
+		Capabilities:   Capabilities{Vision: false, Text: true, ListModels: true},
+	}, newCompatibleProvider)
+
+	Register(ProviderInfo{
+		ID:             "moonshot",
+		Name:           "Moonshot (Kimi)",
+		Description:    "月之暗面开放平台，OCR需使用 vision 系列模型",
+		DefaultBaseURL: "https://api.moonshot.cn/v1",
+		Capabilities:   openAICompatible,
+	}, newCompatibleProvider)
+}
+
+// newCompatibleProvider 创建 OpenAI 兼容接口的提供方
+func newCompatibleProvider(info ProviderInfo, cfg config.AIConfig) (Provider, error) {
+	return newOpenAIClient(info, cfg), nil
+}
@@ -0,0 +1,54 @@
+package ocr
+
+import (
+	"sync"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// TokenUsage 累计的API调用用量
+type TokenUsage struct {
+	Requests         int `json:"requests"`
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// Sub 计算两次用量快照之间的差值
+func (u TokenUsage) Sub(earlier TokenUsage) TokenUsage {
+	return TokenUsage{
+		Requests:         u.Requests - earlier.Requests,
+		PromptTokens:     u.PromptTokens - earlier.PromptTokens,
+		CompletionTokens: u.CompletionTokens - earlier.CompletionTokens,
+		TotalTokens:      u.TotalTokens - earlier.TotalTokens,
+	}
+}
+
+// usageTracker 线程安全的用量统计
+type usageTracker struct {
+	mu    sync.Mutex
+	usage TokenUsage
+}
+
+// record 记录一次成功请求的用量
+func (t *usageTracker) record(usage openai.Usage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.usage.Requests++
+	t.usage.PromptTokens += usage.PromptTokens
+	t.usage.CompletionTokens += usage.CompletionTokens
+	t.usage.TotalTokens += usage.TotalTokens
+}
+
+// snapshot 获取当前累计用量
+func (t *usageTracker) snapshot() TokenUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.usage
+}
+
+// GetUsage 获取客户端启动以来的累计用量
+func (c *OpenAIClient) GetUsage() TokenUsage {
+	return c.usage.snapshot()
+}
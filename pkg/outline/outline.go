@@ -0,0 +1,286 @@
+package outline
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxHeadingRunes 标题行的最大长度，超过时视为正文
+const maxHeadingRunes = 40
+
+// Heading 检测到的标题
+type Heading struct {
+	ID    string `json:"id"` // 导出时使用的锚点
+	Title string `json:"title"`
+	Level int    `json:"level"` // 层级，从1开始
+	Page  int    `json:"page"`  // 在输入中的序号（从1开始）
+	Line  int    `json:"line"`  // 页内行号（从0开始）
+}
+
+// Section 章节树节点
+type Section struct {
+	Heading
+	Children []*Section `json:"children"`
+}
+
+// Result 大纲检测结果
+type Result struct {
+	Headings    []Heading  `json:"headings"`
+	Sections    []*Section `json:"sections"`
+	Pages       []string   `json:"pages"`        // 标题行已替换为带锚点标题的各页文本
+	MarkdownTOC string     `json:"markdown_toc"` // Markdown目录，没有标题时为空
+	HTMLTOC     string     `json:"html_toc"`     // HTML目录，没有标题时为空
+}
+
+// headingRule 按编号格式识别标题的规则
+type headingRule struct {
+	pattern *regexp.Regexp
+	level   int
+}
+
+var (
+	markdownHeadingPattern = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*$`)
+	// headingRules 常见的章节编号格式，层级为相对值，检测后会压缩为从1开始
+	headingRules = []headingRule{
+		{regexp.MustCompile(`^第[一二三四五六七八九十百零〇\d]+[部篇编]\s*\S*`), 1},
+		{regexp.MustCompile(`^第[一二三四五六七八九十百零〇\d]+章\s*\S*`), 2},
+		{regexp.MustCompile(`^(?i:chapter|part)\s+(\d+|[IVXLC]+)\b`), 2},
+		{regexp.MustCompile(`^第[一二三四五六七八九十百零〇\d]+节\s*\S*`), 3},
+		{regexp.MustCompile(`^[一二三四五六七八九十]+、\s*\S+`), 3},
+		{regexp.MustCompile(`^\d+\.\d+\s+\S+`), 3},
+		{regexp.MustCompile(`^\d+\.\d+\.\d+\s+\S+`), 4},
+	}
+	// sentenceEndPattern 以这些标点结尾的行是正文
+	sentenceEndPattern = regexp.MustCompile(`[。，；,;、]$`)
+)
+
+// Detect 检测各页文本中的章节标题：Markdown标题（#）和"第一章"、"1.1 "等常见编号格式
+// 连续重复的同名标题（如每页重复的章节页眉）只保留第一个
+func Detect(pages []string) []Heading {
+	var headings []Heading
+	for p, page := range pages {
+		for i, line := range strings.Split(strings.ReplaceAll(page, "\r\n", "\n"), "\n") {
+			title, level, ok := parseHeading(line)
+			if !ok {
+				continue
+			}
+			if n := len(headings); n > 0 && headings[n-1].Title == title {
+				continue
+			}
+			headings = append(headings, Heading{Title: title, Level: level, Page: p + 1, Line: i})
+		}
+	}
+
+	normalizeLevels(headings)
+	for i := range headings {
+		headings[i].ID = fmt.Sprintf("sec-%d", i+1)
+	}
+	return headings
+}
+
+// parseHeading 判断一行是否为标题，返回标题文本和层级
+func parseHeading(line string) (string, int, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return "", 0, false
+	}
+	if m := markdownHeadingPattern.FindStringSubmatch(line); m != nil {
+		return strings.Trim(m[2], "*_ "), len(m[1]), true
+	}
+	if utf8.RuneCountInString(line) > maxHeadingRunes || sentenceEndPattern.MatchString(line) {
+		return "", 0, false
+	}
+	// 更具体的规则（如 1.1.1）排在后面，取最后一个匹配的规则
+	level := 0
+	for _, rule := range headingRules {
+		if rule.pattern.MatchString(line) {
+			level = rule.level
+		}
+	}
+	if level == 0 {
+		return "", 0, false
+	}
+	return strings.Trim(line, "*_ "), level, true
+}
+
+// normalizeLevels 将出现过的层级压缩为从1开始的连续层级
+func normalizeLevels(headings []Heading) {
+	used := make(map[int]bool)
+	for _, h := range headings {
+		used[h.Level] = true
+	}
+	mapping := make(map[int]int)
+	next := 1
+	for level := 1; level <= 6; level++ {
+		if used[level] {
+			mapping[level] = next
+			next++
+		}
+	}
+	for i := range headings {
+		headings[i].Level = mapping[headings[i].Level]
+	}
+}
+
+// Build 将标题列表组织为章节树
+func Build(headings []Heading) []*Section {
+	var roots []*Section
+	var stack []*Section
+	for _, h := range headings {
+		section := &Section{Heading: h, Children: []*Section{}}
+		for len(stack) > 0 && stack[len(stack)-1].Level >= h.Level {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			roots = append(roots, section)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, section)
+		}
+		stack = append(stack, section)
+	}
+	return roots
+}
+
+// Analyze 检测标题并生成目录，format 为 markdown 或 html，决定标题行在各页文本中的替换形式
+// baseLevel 为一级章节使用的标题级别（如文档标题占用了 h1 时传 2）
+func Analyze(pages []string, format string, baseLevel int) Result {
+	headings := Detect(pages)
+	result := Result{
+		Headings: headings,
+		Sections: Build(headings),
+		Pages:    markHeadings(pages, headings, format, baseLevel),
+	}
+	if len(headings) > 0 {
+		result.MarkdownTOC = markdownTOC(result.Sections, 0)
+		result.HTMLTOC = "<ul class=\"toc\">\n" + htmlTOC(result.Sections) + "</ul>\n"
+	}
+	return result
+}
+
+// markHeadings 将标题行替换为带锚点的Markdown或HTML标题
+func markHeadings(pages []string, headings []Heading, format string, baseLevel int) []string {
+	marked := append([]string(nil), pages...)
+	byPage := make(map[int][]Heading)
+	for _, h := range headings {
+		byPage[h.Page] = append(byPage[h.Page], h)
+	}
+
+	for page, pageHeadings := range byPage {
+		lines := strings.Split(strings.ReplaceAll(pages[page-1], "\r\n", "\n"), "\n")
+		for _, h := range pageHeadings {
+			level := h.Level + baseLevel - 1
+			if level > 6 {
+				level = 6
+			}
+			if format == "html" {
+				lines[h.Line] = fmt.Sprintf("<h%d id=\"%s\">%s</h%d>", level, h.ID, html.EscapeString(h.Title), level)
+			} else {
+				lines[h.Line] = fmt.Sprintf("<a id=\"%s\"></a>\n\n%s %s", h.ID, strings.Repeat("#", level), h.Title)
+			}
+		}
+		marked[page-1] = strings.Join(lines, "\n")
+	}
+	return marked
+}
+
+// markdownTOC 生成Markdown嵌套列表目录
+func markdownTOC(sections []*Section, depth int) string {
+	var b strings.Builder
+	for _, s := range sections {
+		fmt.Fprintf(&b, "%s- [%s](#%s)\n", strings.Repeat("  ", depth), s.Title, s.ID)
+		b.WriteString(markdownTOC(s.Children, depth+1))
+	}
+	return b.String()
+}
+
+// htmlTOC 生成HTML嵌套列表目录的列表项
+func htmlTOC(sections []*Section) string {
+	var b strings.Builder
+	for _, s := range sections {
+		fmt.Fprintf(&b, "<li><a href=\"#%s\">%s</a>", s.ID, html.EscapeString(s.Title))
+		if len(s.Children) > 0 {
+			b.WriteString("\n<ul>\n" + htmlTOC(s.Children) + "</ul>\n")
+		}
+		b.WriteString("</li>\n")
+	}
+	return b.String()
+}
+
+// Chapter 按一级标题划分的章节
+type Chapter struct {
+	Title string   `json:"title"` // 第一个一级标题之前的内容没有标题
+	Pages []string `json:"pages"` // 章节在各页中的文本，按页顺序
+}
+
+// SplitChapters 按一级标题把各页文本划分为章节（标题行属于新章节），没有检测到标题时整体作为一个章节
+func SplitChapters(pages []string) []Chapter {
+	starts := make(map[int]map[int]string)
+	for _, h := range Detect(pages) {
+		if h.Level != 1 {
+			continue
+		}
+		if starts[h.Page] == nil {
+			starts[h.Page] = make(map[int]string)
+		}
+		starts[h.Page][h.Line] = h.Title
+	}
+
+	var chapters []Chapter
+	current := Chapter{}
+	closeChapter := func() {
+		if current.Title != "" || strings.TrimSpace(strings.Join(current.Pages, "")) != "" {
+			chapters = append(chapters, current)
+		}
+	}
+	for p, page := range pages {
+		lines := strings.Split(strings.ReplaceAll(page, "\r\n", "\n"), "\n")
+		from := 0
+		for i := range lines {
+			title, ok := starts[p+1][i]
+			if !ok {
+				continue
+			}
+			if i > from {
+				current.Pages = append(current.Pages, strings.Join(lines[from:i], "\n"))
+			}
+			closeChapter()
+			current = Chapter{Title: title}
+			from = i
+		}
+		current.Pages = append(current.Pages, strings.Join(lines[from:], "\n"))
+	}
+	closeChapter()
+	return chapters
+}
+
+// ChapterSpan 章节覆盖的页面范围
+type ChapterSpan struct {
+	Title string `json:"title"` // 第一个一级标题之前的内容没有标题
+	Start int    `json:"start"` // 起始页（从1开始）
+	End   int    `json:"end"`   // 结束页（包含）
+}
+
+// ChapterOf 按一级标题划分章节，返回 page 所在章节的页面范围，页码超出范围时返回 false
+func ChapterOf(pages []string, page int) (ChapterSpan, bool) {
+	if page < 1 || page > len(pages) {
+		return ChapterSpan{}, false
+	}
+
+	span := ChapterSpan{Start: 1, End: len(pages)}
+	for _, h := range Detect(pages) {
+		if h.Level != 1 {
+			continue
+		}
+		if h.Page <= page {
+			span.Title, span.Start = h.Title, h.Page
+			continue
+		}
+		span.End = h.Page - 1
+		break
+	}
+	return span, true
+}
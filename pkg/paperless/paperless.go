@@ -0,0 +1,181 @@
+package paperless
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 名称对象的接口路径
+const (
+	endpointTags           = "tags"
+	endpointCorrespondents = "correspondents"
+	endpointDocumentTypes  = "document_types"
+)
+
+// Client Paperless-ngx API客户端
+type Client struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// Metadata 上传文档时设置的元数据，为空的字段不设置
+type Metadata struct {
+	Title         string
+	Created       string // 文档日期，格式 2006-01-02
+	Tags          []string
+	Correspondent string
+	DocumentType  string
+}
+
+// NewClient 创建客户端，baseURL 为Paperless-ngx的地址，token 为API令牌
+func NewClient(baseURL, token string) (*Client, error) {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if u, err := url.Parse(baseURL); err != nil || u.Host == "" {
+		return nil, fmt.Errorf("无效的Paperless-ngx地址: %s", baseURL)
+	}
+	if token == "" {
+		return nil, fmt.Errorf("未配置Paperless-ngx的API令牌")
+	}
+	return &Client{
+		baseURL: baseURL,
+		token:   token,
+		client:  &http.Client{Timeout: 2 * time.Minute},
+	}, nil
+}
+
+// Check 检查地址和API令牌是否有效
+func (c *Client) Check(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/api/tags/?page_size=1", nil, "", nil)
+}
+
+// Upload 上传PDF文档并设置元数据，标签、联系人和文档类型不存在时自动创建
+// Paperless-ngx 在后台处理上传的文档，返回的是处理任务的ID
+func (c *Client) Upload(ctx context.Context, filename string, data []byte, meta Metadata) (string, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("document", filename)
+	if err != nil {
+		return "", fmt.Errorf("创建上传请求失败: %w", err)
+	}
+	part.Write(data)
+
+	if meta.Title != "" {
+		writer.WriteField("title", meta.Title)
+	}
+	if meta.Created != "" {
+		writer.WriteField("created", meta.Created)
+	}
+	added := map[int]bool{}
+	for _, tag := range meta.Tags {
+		if strings.TrimSpace(tag) == "" {
+			continue
+		}
+		id, err := c.ensureObject(ctx, endpointTags, strings.TrimSpace(tag))
+		if err != nil {
+			return "", err
+		}
+		if !added[id] {
+			writer.WriteField("tags", strconv.Itoa(id))
+			added[id] = true
+		}
+	}
+	if meta.Correspondent != "" {
+		id, err := c.ensureObject(ctx, endpointCorrespondents, meta.Correspondent)
+		if err != nil {
+			return "", err
+		}
+		writer.WriteField("correspondent", strconv.Itoa(id))
+	}
+	if meta.DocumentType != "" {
+		id, err := c.ensureObject(ctx, endpointDocumentTypes, meta.DocumentType)
+		if err != nil {
+			return "", err
+		}
+		writer.WriteField("document_type", strconv.Itoa(id))
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("创建上传请求失败: %w", err)
+	}
+
+	var taskID string
+	if err := c.do(ctx, http.MethodPost, "/api/documents/post_document/", body.Bytes(), writer.FormDataContentType(), &taskID); err != nil {
+		return "", err
+	}
+	return taskID, nil
+}
+
+// ensureObject 按名称（不区分大小写）查找标签、联系人或文档类型，不存在时创建，返回其ID
+func (c *Client) ensureObject(ctx context.Context, endpoint, name string) (int, error) {
+	var list struct {
+		Results []struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		} `json:"results"`
+	}
+	query := "/api/" + endpoint + "/?name__iexact=" + url.QueryEscape(name)
+	if err := c.do(ctx, http.MethodGet, query, nil, "", &list); err != nil {
+		return 0, err
+	}
+	if len(list.Results) > 0 {
+		return list.Results[0].ID, nil
+	}
+
+	data, err := json.Marshal(map[string]string{"name": name})
+	if err != nil {
+		return 0, fmt.Errorf("序列化请求失败: %w", err)
+	}
+	var created struct {
+		ID int `json:"id"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/api/"+endpoint+"/", data, "application/json", &created); err != nil {
+		return 0, fmt.Errorf("创建 %s 失败: %w", name, err)
+	}
+	return created.ID, nil
+}
+
+// do 发送请求并解析JSON响应
+func (c *Client) do(ctx context.Context, method, path string, body []byte, contentType string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Authorization", "Token "+c.token)
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求Paperless-ngx失败: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("读取Paperless-ngx响应失败: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message := strings.TrimSpace(string(respBody))
+		if len(message) > 512 {
+			message = message[:512]
+		}
+		return fmt.Errorf("Paperless-ngx返回错误 (%s): %s", resp.Status, message)
+	}
+	if out != nil {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("解析Paperless-ngx响应失败: %w", err)
+		}
+	}
+	return nil
+}
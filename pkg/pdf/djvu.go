@@ -0,0 +1,139 @@
+package pdf
+
+import (
+	"fmt"
+	"image"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	_ "golang.org/x/image/tiff" // 注册TIFF解码器，ddjvu输出TIFF格式
+
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/system"
+)
+
+// IsDjVuFile 判断文件是否为DjVu文档
+func IsDjVuFile(filePath string) bool {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	return ext == ".djvu" || ext == ".djv"
+}
+
+// LoadDjVu 加载DjVu文件（依赖DjVuLibre的djvused获取页数）
+func (p *PDFProcessor) LoadDjVu(filePath string) (*PDFDocument, error) {
+	djvused, err := system.FindExecutable("djvused")
+	if err != nil {
+		return nil, fmt.Errorf("DjVu支持需要安装DjVuLibre: %w", err)
+	}
+
+	output, err := system.Command(djvused, "-e", "n", filePath).Output()
+	if err != nil {
+		return nil, fmt.Errorf("获取DjVu页数失败: %w", err)
+	}
+
+	pageCount, err := strconv.Atoi(strings.TrimSpace(string(output)))
+	if err != nil || pageCount <= 0 {
+		return nil, fmt.Errorf("解析DjVu页数失败: %q", strings.TrimSpace(string(output)))
+	}
+
+	logger.Debugf("DjVu文件 %s 共有 %d 页", filePath, pageCount)
+
+	doc := &PDFDocument{
+		FilePath:  filePath,
+		PageCount: pageCount,
+		Pages:     make([]*PDFPage, 0, pageCount),
+		Title:     filepath.Base(filePath),
+	}
+
+	for i := 1; i <= pageCount; i++ {
+		doc.Pages = append(doc.Pages, &PDFPage{
+			Number:  i,
+			HasText: false,
+		})
+	}
+
+	return doc, nil
+}
+
+// renderDjVuPage 使用ddjvu将DjVu页面渲染为JPEG图片
+func (p *PDFProcessor) renderDjVuPage(djvuPath string, pageNum int, doc *PDFDocument, dpi int) (string, error) {
+	ddjvu, err := system.FindExecutable("ddjvu")
+	if err != nil {
+		return "", fmt.Errorf("DjVu渲染需要安装DjVuLibre: %w", err)
+	}
+
+	logger.Debugf("使用 ddjvu 渲染第%d页，DjVu文件: %s", pageNum, djvuPath)
+
+	// 中间TIFF文件使用唯一的临时文件名，避免不同文档的同一页互相覆盖
+	tiffFile, err := os.CreateTemp(p.tempDir, fmt.Sprintf("page_%d_djvu_*.tiff", pageNum))
+	if err != nil {
+		return "", fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	tiffPath := tiffFile.Name()
+	tiffFile.Close()
+	defer os.Remove(tiffPath)
+
+	// 按图片处理器的最大尺寸（随分辨率缩放）输出，ddjvu会保持宽高比
+	cmd := system.Command(ddjvu,
+		"-format=tiff",
+		fmt.Sprintf("-page=%d", pageNum),
+		fmt.Sprintf("-size=%dx%d", 1600*dpi/DefaultRenderDPI, 2400*dpi/DefaultRenderDPI),
+		djvuPath, tiffPath)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("ddjvu 渲染第%d页失败: %v: %s", pageNum, err, strings.TrimSpace(string(output)))
+	}
+
+	tiffFile, err = os.Open(tiffPath)
+	if err != nil {
+		return "", fmt.Errorf("打开渲染结果失败: %w", err)
+	}
+	defer tiffFile.Close()
+
+	img, _, err := image.Decode(tiffFile)
+	if err != nil {
+		return "", fmt.Errorf("解码渲染结果失败: %w", err)
+	}
+
+	imagePath := filepath.Join(p.tempDir, renderFileName(djvuPath, pageNum, dpi, "djvu"))
+	outFile, err := os.Create(imagePath)
+	if err != nil {
+		return "", fmt.Errorf("创建图片文件失败: %w", err)
+	}
+	defer outFile.Close()
+
+	if err := jpeg.Encode(outFile, img, &jpeg.Options{Quality: 90}); err != nil {
+		return "", fmt.Errorf("保存图片文件失败: %w", err)
+	}
+
+	// 更新页面尺寸信息
+	if doc != nil && pageNum >= 1 && pageNum <= len(doc.Pages) {
+		bounds := img.Bounds()
+		doc.mu.Lock()
+		doc.Pages[pageNum-1].Width = float64(bounds.Dx())
+		doc.Pages[pageNum-1].Height = float64(bounds.Dy())
+		doc.mu.Unlock()
+	}
+
+	logger.Debugf("ddjvu 渲染第%d页成功，输出文件: %s", pageNum, imagePath)
+	return imagePath, nil
+}
+
+// extractDjVuText 使用djvutxt提取DjVu页面的隐藏文本层
+func (p *PDFProcessor) extractDjVuText(filePath string, pageNum int) (string, bool, error) {
+	djvutxt, err := system.FindExecutable("djvutxt")
+	if err != nil {
+		return "", false, fmt.Errorf("提取DjVu文本需要安装DjVuLibre: %w", err)
+	}
+
+	output, err := system.Command(djvutxt, fmt.Sprintf("--page=%d", pageNum), filePath).Output()
+	if err != nil {
+		return "", false, fmt.Errorf("djvutxt 提取第%d页失败: %w", pageNum, err)
+	}
+
+	text := strings.TrimSpace(string(output))
+	hasText := len(text) > 10
+
+	return text, hasText, nil
+}
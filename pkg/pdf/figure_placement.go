@@ -0,0 +1,184 @@
+package pdf
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+)
+
+// figureCoverage 解析页面内容流，返回每个图片资源绘制区域占页面面积的最大比例
+// 只统计页面内容中直接绘制的图片（表单对象中的图片不统计）；无法解析时返回空表
+func figureCoverage(filePath string, pageNum int) map[string]float64 {
+	coverage := map[string]float64{}
+	ctx, err := api.ReadContextFile(filePath)
+	if err != nil {
+		return coverage
+	}
+	pageDict, _, inherited, err := ctx.PageDict(pageNum, false)
+	if err != nil || pageDict == nil || inherited == nil {
+		return coverage
+	}
+	box := inherited.MediaBox
+	if inherited.CropBox != nil {
+		box = inherited.CropBox
+	}
+	if box == nil || box.Width() <= 0 || box.Height() <= 0 {
+		return coverage
+	}
+	content, err := ctx.PageContent(pageDict, pageNum)
+	if err != nil {
+		return coverage
+	}
+
+	pageArea := box.Width() * box.Height()
+	ctm := [6]float64{1, 0, 0, 1, 0, 0}
+	var stack [][6]float64
+	var operands []string
+	for _, token := range contentTokens(content) {
+		if !token.operator {
+			operands = append(operands, token.value)
+			continue
+		}
+		switch token.value {
+		case "q":
+			stack = append(stack, ctm)
+		case "Q":
+			if len(stack) > 0 {
+				ctm, stack = stack[len(stack)-1], stack[:len(stack)-1]
+			}
+		case "cm":
+			if m, ok := parseMatrix(operands); ok {
+				ctm = multiplyMatrix(m, ctm)
+			}
+		case "Do":
+			if len(operands) > 0 && len(operands[len(operands)-1]) > 1 && operands[len(operands)-1][0] == '/' {
+				name := operands[len(operands)-1][1:]
+				// 单位正方形经当前变换矩阵映射后的面积
+				area := math.Abs(ctm[0]*ctm[3] - ctm[1]*ctm[2])
+				coverage[name] = max(coverage[name], area/pageArea)
+			}
+		}
+		operands = operands[:0]
+	}
+	return coverage
+}
+
+// parseMatrix 解析 cm 操作的6个数字
+func parseMatrix(operands []string) ([6]float64, bool) {
+	var m [6]float64
+	if len(operands) < 6 {
+		return m, false
+	}
+	for i, operand := range operands[len(operands)-6:] {
+		value, err := strconv.ParseFloat(operand, 64)
+		if err != nil {
+			return m, false
+		}
+		m[i] = value
+	}
+	return m, true
+}
+
+// multiplyMatrix 计算 m × n（PDF的行向量约定，先应用 m 再应用 n）
+func multiplyMatrix(m, n [6]float64) [6]float64 {
+	return [6]float64{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+// contentToken 内容流中的操作数或操作符
+type contentToken struct {
+	value    string
+	operator bool
+}
+
+// contentTokens 将内容流切分为操作数和操作符，字符串、数组、字典和内嵌图片作为不参与计算的操作数跳过
+func contentTokens(data []byte) []contentToken {
+	var tokens []contentToken
+	isDelimiter := func(c byte) bool {
+		return isContentSpace(c) || c == '(' || c == ')' || c == '<' || c == '>' || c == '[' || c == ']' || c == '{' || c == '}' || c == '/' || c == '%'
+	}
+
+	for i := 0; i < len(data); {
+		c := data[i]
+		switch {
+		case isContentSpace(c):
+			i++
+		case c == '%':
+			for i < len(data) && data[i] != '\n' && data[i] != '\r' {
+				i++
+			}
+		case c == '(':
+			depth := 0
+			for ; i < len(data); i++ {
+				if data[i] == '\\' {
+					i++
+					continue
+				}
+				if data[i] == '(' {
+					depth++
+				} else if data[i] == ')' {
+					depth--
+					if depth == 0 {
+						i++
+						break
+					}
+				}
+			}
+			tokens = append(tokens, contentToken{value: "()"})
+		case c == '<' && i+1 < len(data) && data[i+1] == '<', c == '>' && i+1 < len(data) && data[i+1] == '>':
+			i += 2
+		case c == '<':
+			for i < len(data) && data[i] != '>' {
+				i++
+			}
+			i++
+			tokens = append(tokens, contentToken{value: "<>"})
+		case c == '[' || c == ']' || c == '{' || c == '}' || c == ')' || c == '>':
+			i++
+		case c == '/':
+			start := i
+			i++
+			for i < len(data) && !isDelimiter(data[i]) {
+				i++
+			}
+			tokens = append(tokens, contentToken{value: string(data[start:i])})
+		default:
+			start := i
+			for i < len(data) && !isDelimiter(data[i]) {
+				i++
+			}
+			word := string(data[start:i])
+			if _, err := strconv.ParseFloat(word, 64); err == nil {
+				tokens = append(tokens, contentToken{value: word})
+				continue
+			}
+			if word == "true" || word == "false" || word == "null" {
+				tokens = append(tokens, contentToken{value: word})
+				continue
+			}
+			tokens = append(tokens, contentToken{value: word, operator: true})
+			// 内嵌图片的二进制数据在 ID 和 EI 之间
+			if word == "ID" {
+				for i++; i+2 < len(data); i++ {
+					if isContentSpace(data[i]) && data[i+1] == 'E' && data[i+2] == 'I' && (i+3 == len(data) || isDelimiter(data[i+3])) {
+						i += 3
+						break
+					}
+				}
+			}
+		}
+	}
+	return tokens
+}
+
+// isContentSpace 是否为PDF空白字符
+func isContentSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
@@ -0,0 +1,82 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	_ "golang.org/x/image/tiff"
+)
+
+// ExtractPageFigures 将PDF页面中短边不小于 minSide 像素的嵌入图片保存到 outputDir，返回图片路径
+// 图标、线条等较小的图片被忽略，绘制区域超过页面面积 maxCoverage 的图片（如带文本层的扫描页的整页图片）也被忽略；
+// 无法解码的图片（如JPEG 2000）返回错误，由调用方改为整页识别
+func ExtractPageFigures(filePath string, pageNum int, outputDir string, minSide int, maxCoverage float64) ([]string, error) {
+	coverage := figureCoverage(filePath, pageNum)
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("打开PDF文件失败: %w", err)
+	}
+	defer file.Close()
+
+	var paths []string
+	err = api.ExtractImages(file, []string{strconv.Itoa(pageNum)}, func(figure model.Image, _ bool, _ int) error {
+		if figure.Thumb || coverage[figure.Name] > maxCoverage {
+			return nil
+		}
+		data, err := io.ReadAll(figure)
+		if err != nil {
+			return fmt.Errorf("读取插图失败: %w", err)
+		}
+
+		// JPEG和PNG直接保存，其他格式解码后转为PNG
+		if figure.FileType == "jpg" || figure.FileType == "png" {
+			cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+			if err != nil {
+				return fmt.Errorf("解码插图失败: %w", err)
+			}
+			if min(cfg.Width, cfg.Height) < minSide {
+				return nil
+			}
+			path := filepath.Join(outputDir, fmt.Sprintf("figure_%03d.%s", len(paths)+1, figure.FileType))
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				return fmt.Errorf("保存插图失败: %w", err)
+			}
+			paths = append(paths, path)
+			return nil
+		}
+
+		decoded, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("不支持的插图格式 %s: %w", figure.FileType, err)
+		}
+		bounds := decoded.Bounds()
+		if min(bounds.Dx(), bounds.Dy()) < minSide {
+			return nil
+		}
+		path := filepath.Join(outputDir, fmt.Sprintf("figure_%03d.png", len(paths)+1))
+		out, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("保存插图失败: %w", err)
+		}
+		defer out.Close()
+		if err := png.Encode(out, decoded); err != nil {
+			return fmt.Errorf("保存插图失败: %w", err)
+		}
+		paths = append(paths, path)
+		return nil
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("提取第%d页插图失败: %w", pageNum, err)
+	}
+	return paths, nil
+}
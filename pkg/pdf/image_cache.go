@@ -0,0 +1,129 @@
+package pdf
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	"os"
+
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// DefaultRenderDPI 默认的页面渲染分辨率（libvips 默认72DPI），分辨率作为图片缓存键的一部分
+const DefaultRenderDPI = 72
+
+// MinRenderDPI、MaxRenderDPI 可指定的渲染分辨率范围
+const (
+	MinRenderDPI = 36
+	MaxRenderDPI = 600
+)
+
+// PageImageCache 渲染后页面图片的持久化缓存
+type PageImageCache interface {
+	// Get 返回已缓存的图片路径
+	Get(filePath string, pageNum, dpi int) (string, bool)
+	// Put 保存图片并返回缓存路径，不可缓存时返回空字符串
+	Put(filePath string, pageNum, dpi int, data []byte) (string, error)
+}
+
+// SetImageCache 设置页面图片缓存，重新打开文档时无需再次渲染
+func (p *PDFProcessor) SetImageCache(cache PageImageCache) {
+	p.imageCache = cache
+}
+
+// cachedPageImage 查找已缓存的页面图片，命中时从图片头读取页面尺寸
+func (p *PDFProcessor) cachedPageImage(doc *PDFDocument, pageNum int) (string, bool) {
+	if p.imageCache == nil {
+		return "", false
+	}
+	path, ok := p.imageCache.Get(doc.FilePath, pageNum, DefaultRenderDPI)
+	if !ok {
+		return "", false
+	}
+
+	if file, err := os.Open(path); err == nil {
+		if config, _, err := image.DecodeConfig(file); err == nil {
+			doc.mu.Lock()
+			doc.Pages[pageNum-1].Width = float64(config.Width)
+			doc.Pages[pageNum-1].Height = float64(config.Height)
+			doc.mu.Unlock()
+		}
+		file.Close()
+	}
+
+	logger.Debugf("第%d页使用缓存的渲染图片: %s", pageNum, path)
+	return path, true
+}
+
+// storePageImage 将渲染结果保存到图片缓存，返回缓存路径（保存失败时返回原路径）
+func (p *PDFProcessor) storePageImage(doc *PDFDocument, pageNum, dpi int, imagePath string) string {
+	if p.imageCache == nil {
+		return imagePath
+	}
+	data, err := os.ReadFile(imagePath)
+	if err != nil {
+		return imagePath
+	}
+
+	cachedPath, err := p.imageCache.Put(doc.FilePath, pageNum, dpi, data)
+	if err != nil {
+		logger.Warnf("保存第%d页图片缓存失败: %v", pageNum, err)
+		return imagePath
+	}
+	if cachedPath == "" {
+		return imagePath
+	}
+	os.Remove(imagePath)
+	return cachedPath
+}
+
+// renderFileName 渲染结果的临时文件名，包含源文件路径的哈希，避免不同文档的同一页在共享的临时目录中互相覆盖
+// 非默认分辨率时包含分辨率，避免与预览图片冲突
+func renderFileName(sourcePath string, pageNum, dpi int, renderer string) string {
+	sum := sha256.Sum256([]byte(sourcePath))
+	key := hex.EncodeToString(sum[:6])
+	if dpi == DefaultRenderDPI {
+		return fmt.Sprintf("%s_page_%d_%s.jpg", key, pageNum, renderer)
+	}
+	return fmt.Sprintf("%s_page_%d_%ddpi_%s.jpg", key, pageNum, dpi, renderer)
+}
+
+// RenderPageAtDPI 按指定分辨率渲染页面（用于OCR），dpi为0或默认分辨率时与 RenderPageToImage 相同
+// 其他分辨率的渲染结果只保存在图片缓存中，不替换页面的预览图片；渲染失败时返回错误而不是占位图
+func (p *PDFProcessor) RenderPageAtDPI(doc *PDFDocument, pageNum, dpi int) (string, error) {
+	if dpi == 0 || dpi == DefaultRenderDPI {
+		return p.RenderPageToImage(doc, pageNum)
+	}
+	if dpi < MinRenderDPI || dpi > MaxRenderDPI {
+		return "", fmt.Errorf("渲染分辨率超出范围 (%d-%d): %d", MinRenderDPI, MaxRenderDPI, dpi)
+	}
+	if pageNum < 1 || pageNum > len(doc.Pages) {
+		return "", fmt.Errorf("页码超出范围: %d", pageNum)
+	}
+
+	if p.imageCache != nil {
+		if path, ok := p.imageCache.Get(doc.FilePath, pageNum, dpi); ok {
+			logger.Debugf("第%d页使用缓存的 %d DPI 渲染图片: %s", pageNum, dpi, path)
+			return path, nil
+		}
+	}
+
+	logger.Debugf("以 %d DPI 渲染第%d页，文件: %s", dpi, pageNum, doc.FilePath)
+
+	var imagePath string
+	var err error
+	switch {
+	case IsDjVuFile(doc.FilePath):
+		imagePath, err = p.renderDjVuPage(doc.FilePath, pageNum, nil, dpi)
+	case IsImageFile(doc.FilePath):
+		imagePath, err = p.renderImagePage(doc.FilePath, pageNum, nil, dpi)
+	default:
+		imagePath, err = p.renderWithBimg(doc.FilePath, pageNum, nil, dpi)
+	}
+	if err != nil {
+		return "", fmt.Errorf("以 %d DPI 渲染第%d页失败: %w", dpi, pageNum, err)
+	}
+	return p.storePageImage(doc, pageNum, dpi, imagePath), nil
+}
@@ -0,0 +1,140 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/h2non/bimg"
+
+	imageprocessor "pdf-ocr-ai/pkg/image"
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// imageExtensions 作为单页文档打开的图片格式
+var imageExtensions = map[string]bool{
+	".jpg": true, ".jpeg": true, ".png": true, ".bmp": true, ".tiff": true, ".tif": true,
+	".gif": true, ".webp": true, ".heic": true, ".heif": true,
+}
+
+// IsImageFile 判断文件是否为图片文档
+func IsImageFile(filePath string) bool {
+	return imageExtensions[strings.ToLower(filepath.Ext(filePath))]
+}
+
+// LoadImage 将图片加载为单页文档，页面尺寸为图片原始尺寸
+func (p *PDFProcessor) LoadImage(filePath string) (*PDFDocument, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("读取图片文件失败: %w", err)
+	}
+
+	width, height, err := imageSize(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PDFDocument{
+		FilePath:  filePath,
+		PageCount: 1,
+		Title:     filepath.Base(filePath),
+		Pages: []*PDFPage{
+			{
+				Number:  1,
+				HasText: false, // 图片没有原生文本
+				Width:   float64(width),
+				Height:  float64(height),
+			},
+		},
+	}, nil
+}
+
+// imageSize 读取图片尺寸，Go无法解码的格式（如HEIC）通过libvips读取
+func imageSize(data []byte) (int, int, error) {
+	if config, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+		return config.Width, config.Height, nil
+	}
+	size, err := bimg.NewImage(data).Size()
+	if err != nil {
+		return 0, 0, fmt.Errorf("读取图片尺寸失败: %w", err)
+	}
+	return size.Width, size.Height, nil
+}
+
+// renderImagePage 将图片文档规范化为JPEG：转换格式，并按图片处理器的最大尺寸（随分辨率缩放）缩小
+func (p *PDFProcessor) renderImagePage(imagePath string, pageNum int, doc *PDFDocument, dpi int) (string, error) {
+	data, err := os.ReadFile(imagePath)
+	if err != nil {
+		return "", fmt.Errorf("读取图片文件失败: %w", err)
+	}
+
+	if _, _, err := image.DecodeConfig(bytes.NewReader(data)); err != nil {
+		// Go无法解码的格式先通过libvips转换为JPEG
+		converted, convErr := bimg.NewImage(data).Convert(bimg.JPEG)
+		if convErr != nil {
+			return "", fmt.Errorf("转换图片格式失败: %v（原始解码错误: %v）", convErr, err)
+		}
+		data = converted
+	}
+
+	processor := imageprocessor.NewImageProcessor(imageprocessor.ProcessorConfig{
+		MaxWidth:    1600 * dpi / DefaultRenderDPI,
+		MaxHeight:   2400 * dpi / DefaultRenderDPI,
+		Quality:     90,
+		Format:      "jpeg",
+		Compression: true,
+	})
+	output, err := processor.ProcessImageFromReader(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("规范化图片失败: %w", err)
+	}
+
+	outputPath := filepath.Join(p.tempDir, renderFileName(imagePath, pageNum, dpi, "image"))
+	if err := os.WriteFile(outputPath, output, 0644); err != nil {
+		return "", fmt.Errorf("保存图片文件失败: %w", err)
+	}
+
+	// 更新页面尺寸信息
+	if doc != nil && pageNum >= 1 && pageNum <= len(doc.Pages) {
+		if config, _, err := image.DecodeConfig(bytes.NewReader(output)); err == nil {
+			doc.mu.Lock()
+			doc.Pages[pageNum-1].Width = float64(config.Width)
+			doc.Pages[pageNum-1].Height = float64(config.Height)
+			doc.mu.Unlock()
+		}
+	}
+
+	logger.Debugf("图片文档第%d页规范化成功，输出文件: %s", pageNum, outputPath)
+	return outputPath, nil
+}
+
+// RenderImageOriginal 将图片文档转换为原尺寸的JPEG，不按最大尺寸缩小
+// 用于切分图块识别过高或过宽的图片，缩小后的长图文字过小无法识别
+func (p *PDFProcessor) RenderImageOriginal(doc *PDFDocument) (string, error) {
+	if !IsImageFile(doc.FilePath) {
+		return "", fmt.Errorf("不是图片文档: %s", doc.FilePath)
+	}
+	outputPath := filepath.Join(p.tempDir, renderFileName(doc.FilePath, 1, DefaultRenderDPI, "original"))
+	if _, err := os.Stat(outputPath); err == nil {
+		return outputPath, nil
+	}
+
+	data, err := os.ReadFile(doc.FilePath)
+	if err != nil {
+		return "", fmt.Errorf("读取图片文件失败: %w", err)
+	}
+	if _, format, err := image.DecodeConfig(bytes.NewReader(data)); err != nil || format != "jpeg" {
+		converted, convErr := bimg.NewImage(data).Convert(bimg.JPEG)
+		if convErr != nil {
+			return "", fmt.Errorf("转换图片格式失败: %w", convErr)
+		}
+		data = converted
+	}
+	if err := os.WriteFile(outputPath, data, 0644); err != nil {
+		return "", fmt.Errorf("保存图片文件失败: %w", err)
+	}
+	return outputPath, nil
+}
@@ -0,0 +1,39 @@
+package pdf
+
+import "runtime/debug"
+
+// ReleaseRenderMemory 释放 libvips 缓存的渲染数据，并把空闲内存归还给系统
+func (p *PDFProcessor) ReleaseRenderMemory() {
+	dropVipsCache()
+	debug.FreeOSMemory()
+}
+
+// ReleasePageText 释放已处理页面的文本内容，只保留页码、尺寸和处理状态等元数据，返回释放的页数
+// keep 返回true的页面保留文本；调用方负责在需要时从缓存重新加载
+// 未处理的页面没有缓存，原生文本释放后无法恢复，因此不释放
+func (p *PDFProcessor) ReleasePageText(doc *PDFDocument, keep func(pageNum int) bool) int {
+	doc.mu.Lock()
+	defer doc.mu.Unlock()
+
+	released := 0
+	for _, page := range doc.Pages {
+		if !page.Processed || keep(page.Number) || (page.Text == "" && page.OCRText == "" && page.AIText == "") {
+			continue
+		}
+		page.Text, page.OCRText, page.AIText = "", "", ""
+		released++
+	}
+	return released
+}
+
+// SnapshotPages 在文档锁内复制页面信息，读取时不会与释放或重新加载页面文本冲突
+func (doc *PDFDocument) SnapshotPages() []PDFPage {
+	doc.mu.RLock()
+	defer doc.mu.RUnlock()
+
+	pages := make([]PDFPage, len(doc.Pages))
+	for i, page := range doc.Pages {
+		pages[i] = *page
+	}
+	return pages
+}
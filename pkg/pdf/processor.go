@@ -15,19 +15,23 @@ import (
 	"github.com/h2non/bimg"
 	"github.com/pdfcpu/pdfcpu/pkg/api"
 	imageprocessor "pdf-ocr-ai/pkg/image"
+	"pdf-ocr-ai/pkg/logger"
 )
 
 // PDFPage PDF页面信息
 type PDFPage struct {
-	Number      int     `json:"number"`
-	Text        string  `json:"text"`         // PDF原生文本
-	OCRText     string  `json:"ocr_text"`     // OCR识别文本
-	AIText      string  `json:"ai_text"`      // AI处理后文本
-	ImagePath   string  `json:"image_path"`   // 渲染图片路径
-	HasText     bool    `json:"has_text"`     // 是否包含原生文本
-	Width       float64 `json:"width"`
-	Height      float64 `json:"height"`
-	Processed   bool    `json:"processed"`    // 是否已处理
+	Number         int     `json:"number"`
+	Text           string  `json:"text"`       // PDF原生文本
+	OCRText        string  `json:"ocr_text"`   // OCR识别文本
+	AIText         string  `json:"ai_text"`    // AI处理后文本
+	ImagePath      string  `json:"image_path"` // 渲染图片路径
+	HasText        bool    `json:"has_text"`   // 是否包含原生文本
+	Width          float64 `json:"width"`
+	Height         float64 `json:"height"`
+	Processed      bool    `json:"processed"`       // 是否已处理
+	RenderFallback bool    `json:"render_fallback"` // 渲染时使用了 pdfcpu + bimg 备用方案
+	Excluded       bool    `json:"excluded"`        // 已排除，不参与批量处理、导出和统计
+	ReviewStatus   string  `json:"review_status"`   // 人工审核状态：unreviewed、approved、needs_fix
 }
 
 // PDFDocument PDF文档
@@ -45,6 +49,7 @@ type PDFDocument struct {
 type PDFProcessor struct {
 	tempDir        string
 	imageProcessor *imageprocessor.ImageProcessor
+	imageCache     PageImageCache // 持久化的渲染图片缓存（可选）
 }
 
 // NewPDFProcessor 创建PDF处理器
@@ -79,7 +84,7 @@ func (p *PDFProcessor) LoadPDF(filePath string) (*PDFDocument, error) {
 		return nil, fmt.Errorf("获取页数失败: %w", err)
 	}
 
-	fmt.Printf("[DEBUG] PDF文件 %s 共有 %d 页\n", filePath, pageCount)
+	logger.Debugf("PDF文件 %s 共有 %d 页", filePath, pageCount)
 
 	doc := &PDFDocument{
 		FilePath:  filePath,
@@ -120,28 +125,45 @@ func (p *PDFProcessor) RenderPageToImage(doc *PDFDocument, pageNum int) (string,
 	page := doc.Pages[pageNum-1]
 	if page.ImagePath != "" {
 		if _, err := os.Stat(page.ImagePath); err == nil {
-			fmt.Printf("[DEBUG] 第%d页已存在缓存图片: %s\n", pageNum, page.ImagePath)
+			logger.Debugf("第%d页已存在缓存图片: %s", pageNum, page.ImagePath)
 			return page.ImagePath, nil
 		}
 	}
 
-	fmt.Printf("[DEBUG] 开始渲染第%d页，PDF文件: %s\n", pageNum, doc.FilePath)
+	// 检查持久化的图片缓存
+	if cachedPath, ok := p.cachedPageImage(doc, pageNum); ok {
+		doc.mu.Lock()
+		doc.Pages[pageNum-1].ImagePath = cachedPath
+		doc.mu.Unlock()
+		return cachedPath, nil
+	}
+
+	logger.Debugf("开始渲染第%d页，PDF文件: %s", pageNum, doc.FilePath)
 
 	var imagePath string
 	var err error
 
-	// 尝试使用 bimg 渲染 PDF 页面
-	imagePath, err = p.renderWithBimg(doc.FilePath, pageNum, doc)
+	// DjVu文档使用 ddjvu 渲染，图片文档规范化原图，其余尝试使用 bimg 渲染 PDF 页面
+	switch {
+	case IsDjVuFile(doc.FilePath):
+		imagePath, err = p.renderDjVuPage(doc.FilePath, pageNum, doc, DefaultRenderDPI)
+	case IsImageFile(doc.FilePath):
+		imagePath, err = p.renderImagePage(doc.FilePath, pageNum, doc, DefaultRenderDPI)
+	default:
+		imagePath, err = p.renderWithBimg(doc.FilePath, pageNum, doc, DefaultRenderDPI)
+	}
 	if err != nil {
-		fmt.Printf("[WARN] bimg 渲染失败: %v，尝试创建占位符\n", err)
-		// 如果 bimg 渲染失败，创建占位符图片
+		logger.Warnf("页面渲染失败: %v，尝试创建占位符", err)
+		// 如果渲染失败，创建占位符图片
 		imagePath, err = p.createPlaceholderImageFile(pageNum, fmt.Sprintf("第%d页 - 渲染失败", pageNum))
 		if err != nil {
 			return "", fmt.Errorf("创建占位符图片失败: %w", err)
 		}
-		fmt.Printf("[DEBUG] 第%d页占位符图片创建成功\n", pageNum)
+		logger.Debugf("第%d页占位符图片创建成功", pageNum)
 	} else {
-		fmt.Printf("[DEBUG] 使用 bimg 渲染第%d页成功\n", pageNum)
+		logger.Debugf("渲染第%d页成功", pageNum)
+		// 占位符不缓存，下次打开时重新尝试渲染
+		imagePath = p.storePageImage(doc, pageNum, DefaultRenderDPI, imagePath)
 	}
 
 	// 更新页面信息
@@ -153,18 +175,28 @@ func (p *PDFProcessor) RenderPageToImage(doc *PDFDocument, pageNum int) (string,
 }
 
 // renderWithBimg 使用原生 libvips 渲染 PDF 页面
-func (p *PDFProcessor) renderWithBimg(pdfPath string, pageNum int, doc *PDFDocument) (string, error) {
-	fmt.Printf("[DEBUG] 使用原生 libvips 渲染第%d页，PDF文件: %s\n", pageNum, pdfPath)
+// doc 不为空时更新页面尺寸
+func (p *PDFProcessor) renderWithBimg(pdfPath string, pageNum int, doc *PDFDocument, dpi int) (string, error) {
+	logger.Debugf("使用原生 libvips 渲染第%d页，PDF文件: %s", pageNum, pdfPath)
 
-	// 使用原生 libvips 渲染 PDF 页面
-	result, err := p.renderPDFPageWithVips(pdfPath, pageNum)
+	// 使用原生 libvips 渲染 PDF 页面（限制并发，避免大量线程同时进入 libvips）
+	release := renderSlots.acquire()
+	result, err := p.renderPDFPageWithVips(pdfPath, pageNum, dpi)
 	if err != nil {
-		fmt.Printf("[WARN] 原生 libvips 渲染失败: %v，尝试使用 pdfcpu + bimg 方法\n", err)
-		return p.renderWithBimgFallback(pdfPath, pageNum)
+		logger.Warnf("原生 libvips 渲染失败: %v，尝试使用 pdfcpu + bimg 方法", err)
+		path, fallbackErr := p.renderWithBimgFallback(pdfPath, pageNum, dpi)
+		release()
+		if fallbackErr == nil && doc != nil && pageNum >= 1 && pageNum <= len(doc.Pages) {
+			doc.mu.Lock()
+			doc.Pages[pageNum-1].RenderFallback = true
+			doc.mu.Unlock()
+		}
+		return path, fallbackErr
 	}
+	release()
 
 	// 保存图片到文件
-	imagePath := filepath.Join(p.tempDir, fmt.Sprintf("page_%d_vips.jpg", pageNum))
+	imagePath := filepath.Join(p.tempDir, renderFileName(pdfPath, pageNum, dpi, "vips"))
 	err = ioutil.WriteFile(imagePath, result.ImageData, 0644)
 	if err != nil {
 		return "", fmt.Errorf("保存图片文件失败: %w", err)
@@ -176,16 +208,16 @@ func (p *PDFProcessor) renderWithBimg(pdfPath string, pageNum int, doc *PDFDocum
 		doc.Pages[pageNum-1].Width = float64(result.Width)
 		doc.Pages[pageNum-1].Height = float64(result.Height)
 		doc.mu.Unlock()
-		fmt.Printf("[DEBUG] 更新第%d页尺寸信息: %dx%d\n", pageNum, result.Width, result.Height)
+		logger.Debugf("更新第%d页尺寸信息: %dx%d", pageNum, result.Width, result.Height)
 	}
 
-	fmt.Printf("[DEBUG] 原生 libvips 渲染第%d页成功，输出文件: %s\n", pageNum, imagePath)
+	logger.Debugf("原生 libvips 渲染第%d页成功，输出文件: %s", pageNum, imagePath)
 	return imagePath, nil
 }
 
 // renderWithBimgFallback 使用 pdfcpu + bimg 作为备用方案
-func (p *PDFProcessor) renderWithBimgFallback(pdfPath string, pageNum int) (string, error) {
-	fmt.Printf("[DEBUG] 使用 pdfcpu + bimg 备用方案渲染第%d页\n", pageNum)
+func (p *PDFProcessor) renderWithBimgFallback(pdfPath string, pageNum int, dpi int) (string, error) {
+	logger.Debugf("使用 pdfcpu + bimg 备用方案渲染第%d页", pageNum)
 
 	// 首先使用 pdfcpu 提取单页PDF
 	singlePagePath := filepath.Join(p.tempDir, fmt.Sprintf("single_page_%d.pdf", pageNum))
@@ -196,7 +228,7 @@ func (p *PDFProcessor) renderWithBimgFallback(pdfPath string, pageNum int) (stri
 		return "", fmt.Errorf("提取第%d页失败: %w", pageNum, err)
 	}
 
-	fmt.Printf("[DEBUG] 成功提取第%d页到: %s\n", pageNum, singlePagePath)
+	logger.Debugf("成功提取第%d页到: %s", pageNum, singlePagePath)
 	defer os.Remove(singlePagePath) // 清理临时文件
 
 	// 读取单页PDF文件
@@ -205,14 +237,14 @@ func (p *PDFProcessor) renderWithBimgFallback(pdfPath string, pageNum int) (stri
 		return "", fmt.Errorf("读取单页PDF文件失败: %w", err)
 	}
 
-	fmt.Printf("[DEBUG] 单页PDF文件大小: %d bytes\n", len(pdfData))
+	logger.Debugf("单页PDF文件大小: %d bytes", len(pdfData))
 
 	// 配置 bimg 选项
 	options := bimg.Options{
 		Type:    bimg.JPEG,
 		Quality: 90,
-		Width:   800,  // 设置宽度
-		Height:  1000, // 设置高度
+		Width:   800 * dpi / DefaultRenderDPI,  // 设置宽度（按分辨率缩放）
+		Height:  1000 * dpi / DefaultRenderDPI, // 设置高度
 		Crop:    false,
 		Enlarge: true,
 	}
@@ -224,13 +256,13 @@ func (p *PDFProcessor) renderWithBimgFallback(pdfPath string, pageNum int) (stri
 	}
 
 	// 保存图片到文件
-	imagePath := filepath.Join(p.tempDir, fmt.Sprintf("page_%d_bimg.jpg", pageNum))
+	imagePath := filepath.Join(p.tempDir, renderFileName(pdfPath, pageNum, dpi, "bimg"))
 	err = ioutil.WriteFile(imagePath, imageData, 0644)
 	if err != nil {
 		return "", fmt.Errorf("保存图片文件失败: %w", err)
 	}
 
-	fmt.Printf("[DEBUG] pdfcpu + bimg 渲染第%d页成功，输出文件: %s\n", pageNum, imagePath)
+	logger.Debugf("pdfcpu + bimg 渲染第%d页成功，输出文件: %s", pageNum, imagePath)
 	return imagePath, nil
 }
 
@@ -354,7 +386,7 @@ func (p *PDFProcessor) createPlaceholderImageFile(pageNum int, errorMsg string)
 		return "", fmt.Errorf("编码占位符图片失败: %w", err)
 	}
 
-	fmt.Printf("[DEBUG] 创建了 %dx%d 的占位符图片文件: %s\n", width, height, imagePath)
+	logger.Debugf("创建了 %dx%d 的占位符图片文件: %s", width, height, imagePath)
 	return imagePath, nil
 }
 
@@ -513,12 +545,22 @@ func (p *PDFProcessor) drawText(img *image.RGBA, text string, startX, startY int
 
 // ExtractNativeText 提取PDF页面的原生文本
 func (p *PDFProcessor) ExtractNativeText(filePath string, pageNum int) (string, bool, error) {
-	fmt.Printf("[DEBUG] 开始提取第%d页原生文本，PDF文件: %s\n", pageNum, filePath)
+	logger.Debugf("开始提取第%d页原生文本，PDF文件: %s", pageNum, filePath)
+
+	// DjVu文档的文本层通过 djvutxt 提取
+	if IsDjVuFile(filePath) {
+		return p.extractDjVuText(filePath, pageNum)
+	}
+
+	// 图片文档没有文本层
+	if IsImageFile(filePath) {
+		return "", false, nil
+	}
 
 	// 创建临时目录用于提取PDF内容
 	tempDir, err := os.MkdirTemp("", "pdf_content_extract_")
 	if err != nil {
-		fmt.Printf("[WARN] 创建临时目录失败: %v\n", err)
+		logger.Warnf("创建临时目录失败: %v", err)
 		return "", false, err
 	}
 	defer os.RemoveAll(tempDir)
@@ -526,19 +568,19 @@ func (p *PDFProcessor) ExtractNativeText(filePath string, pageNum int) (string,
 	// 使用pdfcpu提取指定页面的内容
 	err = api.ExtractContentFile(filePath, tempDir, []string{fmt.Sprintf("%d", pageNum)}, nil)
 	if err != nil {
-		fmt.Printf("[WARN] 提取第%d页PDF内容失败: %v\n", pageNum, err)
+		logger.Warnf("提取第%d页PDF内容失败: %v", pageNum, err)
 		return "", false, err
 	}
 
 	// 查找生成的内容文件
 	files, err := filepath.Glob(filepath.Join(tempDir, "*.txt"))
 	if err != nil {
-		fmt.Printf("[WARN] 查找内容文件失败: %v\n", err)
+		logger.Warnf("查找内容文件失败: %v", err)
 		return "", false, err
 	}
 
 	if len(files) == 0 {
-		fmt.Printf("[DEBUG] 第%d页没有生成内容文件\n", pageNum)
+		logger.Debugf("第%d页没有生成内容文件", pageNum)
 		return "", false, nil
 	}
 
@@ -547,7 +589,7 @@ func (p *PDFProcessor) ExtractNativeText(filePath string, pageNum int) (string,
 	for _, file := range files {
 		content, err := os.ReadFile(file)
 		if err != nil {
-			fmt.Printf("[WARN] 读取内容文件失败: %v\n", err)
+			logger.Warnf("读取内容文件失败: %v", err)
 			continue
 		}
 
@@ -566,9 +608,9 @@ func (p *PDFProcessor) ExtractNativeText(filePath string, pageNum int) (string,
 	hasText := len(extractedText) > 10 && len(strings.TrimSpace(extractedText)) > 5
 
 	if hasText {
-		fmt.Printf("[DEBUG] 第%d页原生文本提取成功，文本长度: %d\n", pageNum, len(extractedText))
+		logger.Debugf("第%d页原生文本提取成功，文本长度: %d", pageNum, len(extractedText))
 	} else {
-		fmt.Printf("[DEBUG] 第%d页无有效原生文本\n", pageNum)
+		logger.Debugf("第%d页无有效原生文本", pageNum)
 	}
 
 	return extractedText, hasText, nil
@@ -928,13 +970,13 @@ func (p *PDFProcessor) cleanExtractedText(text string) string {
 
 // ExtractAllNativeText 提取PDF所有页面的原生文本
 func (p *PDFProcessor) ExtractAllNativeText(doc *PDFDocument) error {
-	fmt.Printf("[DEBUG] 开始提取PDF所有页面的原生文本，共%d页\n", doc.PageCount)
+	logger.Debugf("开始提取PDF所有页面的原生文本，共%d页", doc.PageCount)
 
 	for i := 1; i <= doc.PageCount; i++ {
 		text, hasText, err := p.ExtractNativeText(doc.FilePath, i)
 		if err != nil {
 			// 提取失败不影响其他页面，继续处理
-			fmt.Printf("[WARN] 第%d页原生文本提取失败: %v\n", i, err)
+			logger.Warnf("第%d页原生文本提取失败: %v", i, err)
 			continue
 		}
 
@@ -947,7 +989,7 @@ func (p *PDFProcessor) ExtractAllNativeText(doc *PDFDocument) error {
 		doc.mu.Unlock()
 	}
 
-	fmt.Printf("[DEBUG] PDF原生文本提取完成\n")
+	logger.Debugf("PDF原生文本提取完成")
 	return nil
 }
 
@@ -955,3 +997,8 @@ func (p *PDFProcessor) ExtractAllNativeText(doc *PDFDocument) error {
 func (p *PDFProcessor) Cleanup() error {
 	return os.RemoveAll(p.tempDir)
 }
+
+// TempDir 获取渲染图片的临时目录
+func (p *PDFProcessor) TempDir() string {
+	return p.tempDir
+}
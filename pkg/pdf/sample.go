@@ -0,0 +1,65 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// SampleText 内置样例PDF页面上的文字，自检时用于核对OCR结果
+const SampleText = "pdfSeer OCR 2468"
+
+// samplePDF 生成只有一页的内置样例PDF，页面上用大号字体写着 SampleText
+func samplePDF() []byte {
+	content := fmt.Sprintf("BT /F1 36 Tf 40 90 Td (%s) Tj ET", SampleText)
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 420 200] /Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+// RenderSample 使用 libvips 渲染内置样例PDF，返回渲染出的图片路径
+// 与 RenderPageToImage 不同，渲染失败时直接返回错误，不生成占位图，也不写入图片缓存
+func (p *PDFProcessor) RenderSample() (string, error) {
+	pdfPath := filepath.Join(p.tempDir, "selftest_sample.pdf")
+	if err := os.WriteFile(pdfPath, samplePDF(), 0644); err != nil {
+		return "", fmt.Errorf("写入样例PDF失败: %w", err)
+	}
+	defer os.Remove(pdfPath)
+
+	release := renderSlots.acquire()
+	result, err := p.renderPDFPageWithVips(pdfPath, 1, DefaultRenderDPI)
+	release()
+	if err != nil {
+		return "", fmt.Errorf("渲染样例PDF失败: %w", err)
+	}
+	if result == nil || len(result.ImageData) == 0 {
+		return "", fmt.Errorf("渲染样例PDF失败: 未生成图片数据")
+	}
+
+	imagePath := filepath.Join(p.tempDir, "selftest_sample.jpg")
+	if err := os.WriteFile(imagePath, result.ImageData, 0644); err != nil {
+		return "", fmt.Errorf("保存样例图片失败: %w", err)
+	}
+	return imagePath, nil
+}
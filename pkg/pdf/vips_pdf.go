@@ -5,9 +5,15 @@ package pdf
 #include "vips/vips.h"
 #include <stdlib.h>
 
-// 自定义 PDF 加载函数，支持页面参数
-int vips_pdfload_buffer_page(void *buf, size_t len, VipsImage **out, int page) {
-    return vips_pdfload_buffer(buf, len, out, "page", page, "access", VIPS_ACCESS_RANDOM, NULL);
+// 自定义 PDF 加载函数，直接从文件加载指定页面，支持页面和分辨率参数
+// libvips 按需读取文件，不需要把整个PDF读入内存
+int vips_pdfload_file_page(const char *filename, VipsImage **out, int page, double dpi) {
+    return vips_pdfload(filename, out, "page", page, "dpi", dpi, "access", VIPS_ACCESS_RANDOM, NULL);
+}
+
+// VIPS_INIT 是宏，cgo 无法直接调用
+int pdfseer_vips_init(void) {
+    return VIPS_INIT("pdfseer");
 }
 
 // 将 VipsImage 转换为 JPEG 数据
@@ -18,10 +24,44 @@ int vips_image_to_jpeg(VipsImage *in, void **buf, size_t *len, int quality) {
 import "C"
 import (
 	"fmt"
-	"io/ioutil"
 	"unsafe"
+
+	"pdf-ocr-ai/pkg/logger"
 )
 
+// dropVipsCache 清空 libvips 的操作缓存，释放其中保存的渲染数据
+func dropVipsCache() {
+	C.vips_cache_drop_all()
+}
+
+// vipsStartup 初始化 libvips（bimg 也会初始化，libvips 已启动时直接返回成功）
+func vipsStartup() error {
+	if C.pdfseer_vips_init() != 0 {
+		return fmt.Errorf("初始化 libvips 失败")
+	}
+	return nil
+}
+
+// vipsShutdown 释放 libvips 的线程和缓存
+func vipsShutdown() {
+	C.vips_shutdown()
+}
+
+// vipsSetCache 设置 libvips 操作缓存的条目数和内存上限，0 表示保持不变
+func vipsSetCache(maxOps, maxMemBytes int) {
+	if maxOps > 0 {
+		C.vips_cache_set_max(C.int(maxOps))
+	}
+	if maxMemBytes > 0 {
+		C.vips_cache_set_max_mem(C.size_t(maxMemBytes))
+	}
+}
+
+// vipsSetConcurrency 设置 libvips 每次操作使用的线程数
+func vipsSetConcurrency(threads int) {
+	C.vips_concurrency_set(C.int(threads))
+}
+
 // PageRenderResult 页面渲染结果
 type PageRenderResult struct {
 	ImageData []byte
@@ -30,25 +70,17 @@ type PageRenderResult struct {
 }
 
 // renderPDFPageWithVips 使用原生 libvips 渲染 PDF 页面
-func (p *PDFProcessor) renderPDFPageWithVips(pdfPath string, pageNum int) (*PageRenderResult, error) {
-	fmt.Printf("[DEBUG] 使用原生 libvips 渲染第%d页，PDF文件: %s\n", pageNum, pdfPath)
-
-	// 读取 PDF 文件
-	pdfData, err := ioutil.ReadFile(pdfPath)
-	if err != nil {
-		return nil, fmt.Errorf("读取PDF文件失败: %w", err)
-	}
-
-	fmt.Printf("[DEBUG] PDF文件大小: %d bytes，页面: %d\n", len(pdfData), pageNum)
+func (p *PDFProcessor) renderPDFPageWithVips(pdfPath string, pageNum int, dpi int) (*PageRenderResult, error) {
+	logger.Debugf("使用原生 libvips 渲染第%d页，PDF文件: %s", pageNum, pdfPath)
 
-	// 准备 C 函数参数
+	// 准备 C 函数参数（从文件加载，大文件不会在每次渲染时整体读入内存）
 	var image *C.VipsImage
-	buf := unsafe.Pointer(&pdfData[0])
-	length := C.size_t(len(pdfData))
+	filename := C.CString(pdfPath)
+	defer C.free(unsafe.Pointer(filename))
 	page := C.int(pageNum - 1) // libvips 页面索引从0开始
 
 	// 调用自定义的 PDF 加载函数
-	err_code := C.vips_pdfload_buffer_page(buf, length, &image, page)
+	err_code := C.vips_pdfload_file_page(filename, &image, page, C.double(dpi))
 	if err_code != 0 {
 		return nil, fmt.Errorf("libvips PDF 加载失败，错误代码: %d", err_code)
 	}
@@ -56,7 +88,7 @@ func (p *PDFProcessor) renderPDFPageWithVips(pdfPath string, pageNum int) (*Page
 
 	width := int(image.Xsize)
 	height := int(image.Ysize)
-	fmt.Printf("[DEBUG] 成功加载第%d页，图片尺寸: %dx%d\n", pageNum, width, height)
+	logger.Debugf("成功加载第%d页，图片尺寸: %dx%d", pageNum, width, height)
 
 	// 转换为 JPEG 数据
 	var jpegBuf unsafe.Pointer
@@ -71,7 +103,7 @@ func (p *PDFProcessor) renderPDFPageWithVips(pdfPath string, pageNum int) (*Page
 
 	// 将 C 数据转换为 Go 字节数组
 	imageData := C.GoBytes(jpegBuf, C.int(jpegLen))
-	fmt.Printf("[DEBUG] 成功转换为JPEG，数据大小: %d bytes\n", len(imageData))
+	logger.Debugf("成功转换为JPEG，数据大小: %d bytes", len(imageData))
 
 	return &PageRenderResult{
 		ImageData: imageData,
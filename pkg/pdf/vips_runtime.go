@@ -0,0 +1,104 @@
+package pdf
+
+import (
+	"runtime"
+	"sync"
+
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// VipsSettings libvips 运行参数
+type VipsSettings struct {
+	RenderConcurrency int // 同时进行的页面渲染数，0 表示CPU核数
+	CacheMaxOps       int // libvips 操作缓存的最大条目数，0 表示使用 libvips 默认值
+	CacheMaxMemMB     int // libvips 操作缓存占用的最大内存（MB），0 表示使用 libvips 默认值
+	Threads           int // 每次渲染使用的线程数，0 表示使用 libvips 默认值
+}
+
+var (
+	vipsOnce    sync.Once
+	vipsMu      sync.Mutex // 保护 vipsStarted，设置缓存和线程时不能同时关闭 libvips
+	vipsStarted bool
+
+	// renderSlots libvips 是进程级的，所有PDF处理器共用同一个渲染并发限制
+	renderSlots = newRenderLimiter(runtime.NumCPU())
+)
+
+// InitVips 初始化 libvips，应用启动时调用一次，重复调用无效
+// 各 goroutine 的渲染都在初始化之后进行，避免多个线程同时触发初始化
+func InitVips() error {
+	var err error
+	vipsOnce.Do(func() {
+		if err = vipsStartup(); err == nil {
+			vipsMu.Lock()
+			vipsStarted = true
+			vipsMu.Unlock()
+		}
+	})
+	return err
+}
+
+// ShutdownVips 应用退出时释放 libvips 资源，之后不能再渲染
+func ShutdownVips() {
+	vipsMu.Lock()
+	defer vipsMu.Unlock()
+	if vipsStarted {
+		vipsShutdown()
+		vipsStarted = false
+	}
+}
+
+// ConfigureVips 应用 libvips 缓存、线程数和渲染并发数设置
+func ConfigureVips(settings VipsSettings) {
+	concurrency := settings.RenderConcurrency
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+	renderSlots.resize(concurrency)
+
+	// 缓存和线程设置需要在 libvips 初始化之后调用
+	vipsMu.Lock()
+	defer vipsMu.Unlock()
+	if !vipsStarted {
+		return
+	}
+	if settings.CacheMaxOps > 0 || settings.CacheMaxMemMB > 0 {
+		vipsSetCache(settings.CacheMaxOps, settings.CacheMaxMemMB<<20)
+	}
+	if settings.Threads > 0 {
+		vipsSetConcurrency(settings.Threads)
+	}
+	logger.Debugf("libvips 设置: 渲染并发 %d, 缓存 %d 项/%d MB, 线程 %d",
+		concurrency, settings.CacheMaxOps, settings.CacheMaxMemMB, settings.Threads)
+}
+
+// renderLimiter 限制同时调用 libvips 渲染的 goroutine 数量，可在运行时调整
+type renderLimiter struct {
+	mu    sync.Mutex
+	slots chan struct{}
+}
+
+// newRenderLimiter 创建渲染并发限制
+func newRenderLimiter(n int) *renderLimiter {
+	return &renderLimiter{slots: make(chan struct{}, n)}
+}
+
+// acquire 占用一个渲染名额，返回释放函数
+// 调整并发数后，已占用的名额仍释放回原来的通道
+func (l *renderLimiter) acquire() func() {
+	l.mu.Lock()
+	slots := l.slots
+	l.mu.Unlock()
+
+	slots <- struct{}{}
+	return func() { <-slots }
+}
+
+// resize 调整并发数，只影响之后开始的渲染
+func (l *renderLimiter) resize(n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if cap(l.slots) != n {
+		l.slots = make(chan struct{}, n)
+	}
+}
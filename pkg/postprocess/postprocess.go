@@ -0,0 +1,194 @@
+package postprocess
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"pdf-ocr-ai/pkg/config"
+	"pdf-ocr-ai/pkg/zhconv"
+)
+
+// 规则作用的结果类型
+const (
+	TargetOCR = "ocr"
+	TargetAI  = "ai"
+)
+
+var (
+	trailingSpacePattern = regexp.MustCompile(`[ \t\x{3000}]+\n`)
+	repeatedSpacePattern = regexp.MustCompile(`[ \t]{2,}`)
+	blankLinesPattern    = regexp.MustCompile(`\n{3,}`)
+)
+
+// halfToFull 转换为全角的半角标点（仅在与中文相邻时转换，避免影响英文和数字）
+var halfToFull = map[rune]rune{
+	',': '，', '.': '。', ':': '：', ';': '；', '!': '！', '?': '？', '(': '（', ')': '）',
+}
+
+// StepResult 一条规则的执行结果
+type StepResult struct {
+	Index   int    `json:"index"` // 规则序号（从0开始）
+	Type    string `json:"type"`
+	Changed bool   `json:"changed"`
+	Output  string `json:"output"` // 执行该规则后的文本
+}
+
+// DryRun 试运行结果
+type DryRun struct {
+	Input  string       `json:"input"`
+	Output string       `json:"output"`
+	Steps  []StepResult `json:"steps"`
+}
+
+// step 编译后的规则
+type step struct {
+	index  int
+	rule   config.PostProcessRule
+	expand func(string) string
+}
+
+// Pipeline 编译后的后处理规则
+type Pipeline struct {
+	steps []step
+}
+
+// Validate 检查规则是否有效
+func Validate(rules []config.PostProcessRule) error {
+	_, err := Compile(rules)
+	return err
+}
+
+// Compile 编译启用的规则，规则无效时返回错误
+func Compile(rules []config.PostProcessRule) (*Pipeline, error) {
+	p := &Pipeline{}
+	for i, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		if rule.Target != "" && rule.Target != TargetOCR && rule.Target != TargetAI {
+			return nil, fmt.Errorf("后处理规则%d的作用对象无效: %s", i+1, rule.Target)
+		}
+
+		s := step{index: i, rule: rule}
+		switch rule.Type {
+		case config.PostProcessRegex:
+			if rule.Pattern == "" {
+				return nil, fmt.Errorf("后处理规则%d的正则表达式不能为空", i+1)
+			}
+			re, err := regexp.Compile(rule.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("后处理规则%d的正则表达式无效: %w", i+1, err)
+			}
+			s.expand = func(text string) string { return re.ReplaceAllString(text, rule.Replacement) }
+		case config.PostProcessStrip:
+			if rule.Pattern == "" {
+				return nil, fmt.Errorf("后处理规则%d要删除的短语不能为空", i+1)
+			}
+			s.expand = func(text string) string { return strings.ReplaceAll(text, rule.Pattern, "") }
+		case config.PostProcessWhitespace:
+			s.expand = normalizeWhitespace
+		case config.PostProcessHalfWidth:
+			s.expand = toHalfWidth
+		case config.PostProcessFullWidth:
+			s.expand = toFullWidthPunctuation
+		case config.PostProcessChinese:
+			converter, err := zhconv.Get(rule.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("后处理规则%d: %w", i+1, err)
+			}
+			s.expand = converter.Convert
+		default:
+			return nil, fmt.Errorf("后处理规则%d的类型无效: %s", i+1, rule.Type)
+		}
+		p.steps = append(p.steps, s)
+	}
+	return p, nil
+}
+
+// Empty 是否没有启用的规则
+func (p *Pipeline) Empty() bool {
+	return p == nil || len(p.steps) == 0
+}
+
+// Apply 按顺序执行作用于 target 的规则
+func (p *Pipeline) Apply(text, target string) string {
+	return p.Run(text, target).Output
+}
+
+// Run 按顺序执行作用于 target 的规则，返回每条规则的执行结果
+func (p *Pipeline) Run(text, target string) *DryRun {
+	result := &DryRun{Input: text, Output: text, Steps: []StepResult{}}
+	if p == nil {
+		return result
+	}
+	for _, s := range p.steps {
+		if s.rule.Target != "" && target != "" && s.rule.Target != target {
+			continue
+		}
+		output := s.expand(result.Output)
+		result.Steps = append(result.Steps, StepResult{
+			Index:   s.index,
+			Type:    s.rule.Type,
+			Changed: output != result.Output,
+			Output:  output,
+		})
+		result.Output = output
+	}
+	return result
+}
+
+// normalizeWhitespace 去除行尾空白、合并连续空格，连续空行只保留一行
+func normalizeWhitespace(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = trailingSpacePattern.ReplaceAllString(text, "\n")
+	text = repeatedSpacePattern.ReplaceAllString(text, " ")
+	text = blankLinesPattern.ReplaceAllString(text, "\n\n")
+	return strings.TrimSpace(text)
+}
+
+// toHalfWidth 将全角字母、数字、空格和ASCII符号转换为半角，中文标点保持不变
+func toHalfWidth(text string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '　':
+			return ' '
+		case r >= 'Ａ' && r <= 'Ｚ', r >= 'ａ' && r <= 'ｚ', r >= '０' && r <= '９':
+			return r - 0xFEE0
+		case r >= '！' && r <= '～' && strings.ContainsRune("＃＄％＆＊＋－／＜＝＞＠＼＾＿｀｜～", r):
+			return r - 0xFEE0
+		}
+		return r
+	}, text)
+}
+
+// toFullWidthPunctuation 将紧跟在汉字后的半角标点转换为全角
+// 括号成对转换：右括号跟随与之匹配的左括号，如 "中文(English)" 转换为 "中文（English）"
+func toFullWidthPunctuation(text string) string {
+	runes := []rune(text)
+	var opens []bool // 未闭合的左括号是否已转换
+	for i, r := range runes {
+		full, ok := halfToFull[r]
+		if !ok {
+			continue
+		}
+		afterHan := i > 0 && unicode.Is(unicode.Han, runes[i-1])
+		switch {
+		case r == '(':
+			opens = append(opens, afterHan)
+		case r == ')' && len(opens) > 0:
+			afterHan = opens[len(opens)-1]
+			opens = opens[:len(opens)-1]
+		}
+		if !afterHan {
+			continue
+		}
+		// 小数点和省略号等不转换
+		if r == '.' && i+1 < len(runes) && (runes[i+1] == '.' || (runes[i+1] >= '0' && runes[i+1] <= '9')) {
+			continue
+		}
+		runes[i] = full
+	}
+	return string(runes)
+}
@@ -0,0 +1,122 @@
+package profiles
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"pdf-ocr-ai/pkg/migrate"
+	"pdf-ocr-ai/pkg/storage"
+)
+
+// migrationComponent 表结构迁移使用的组件名
+const migrationComponent = "profiles"
+
+// Assignment 文档指定的处理配置
+type Assignment struct {
+	FilePath  string `db:"file_path" json:"file_path"`
+	ProfileID string `db:"profile_id" json:"profile_id"`
+	UpdatedAt string `db:"updated_at" json:"updated_at"`
+}
+
+// Manager 文档与处理配置的对应关系（处理配置本身保存在配置文件中）
+// 按文件路径关联，文件内容修改后仍使用原配置
+type Manager struct {
+	db *sqlx.DB
+}
+
+// NewManager 创建处理配置管理器，使用统一数据库中的 document_profiles 表
+func NewManager(store *storage.Store) (*Manager, error) {
+	m := &Manager{db: store.DB()}
+	if err := store.Migrate(migrationComponent, migrations()); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// migrations 表结构迁移
+func migrations() []migrate.Migration {
+	return []migrate.Migration{
+		{
+			Version:     1,
+			Description: "创建文档处理配置表",
+			Up: migrate.SQL(`
+			CREATE TABLE IF NOT EXISTS document_profiles (
+				file_path TEXT PRIMARY KEY,
+				profile_id TEXT NOT NULL,
+				updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			)`,
+				`CREATE INDEX IF NOT EXISTS idx_document_profiles_profile ON document_profiles(profile_id)`),
+		},
+	}
+}
+
+// Assign 为文档指定处理配置，profileID 为空时取消指定
+func (m *Manager) Assign(filePath, profileID string) error {
+	var err error
+	if profileID == "" {
+		_, err = m.db.Exec("DELETE FROM document_profiles WHERE file_path = ?", filePath)
+	} else {
+		_, err = m.db.Exec(`
+		INSERT INTO document_profiles (file_path, profile_id, updated_at)
+		VALUES (?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(file_path) DO UPDATE SET profile_id = excluded.profile_id, updated_at = excluded.updated_at
+		`, filePath, profileID)
+	}
+	if err != nil {
+		return fmt.Errorf("保存文档处理配置失败: %w", err)
+	}
+	return nil
+}
+
+// Get 获取文档指定的处理配置ID，未指定时返回空字符串
+func (m *Manager) Get(filePath string) (string, error) {
+	var profileID string
+	err := m.db.Get(&profileID, "SELECT profile_id FROM document_profiles WHERE file_path = ?", filePath)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("查询文档处理配置失败: %w", err)
+	}
+	return profileID, nil
+}
+
+// List 列出所有文档指定的处理配置
+func (m *Manager) List() ([]Assignment, error) {
+	assignments := []Assignment{}
+	if err := m.db.Select(&assignments, "SELECT * FROM document_profiles ORDER BY file_path"); err != nil {
+		return nil, fmt.Errorf("查询文档处理配置失败: %w", err)
+	}
+	return assignments, nil
+}
+
+// Relocate 文件移动或重命名后更新关联的路径
+func (m *Manager) Relocate(oldPath, newPath string) error {
+	_, err := m.db.Exec("UPDATE OR REPLACE document_profiles SET file_path = ? WHERE file_path = ?", newPath, oldPath)
+	if err != nil {
+		return fmt.Errorf("更新文档处理配置失败: %w", err)
+	}
+	return nil
+}
+
+// RemoveMissing 删除指向已不存在的处理配置的关联，返回删除的数量
+func (m *Manager) RemoveMissing(profileIDs []string) (int, error) {
+	query := "DELETE FROM document_profiles"
+	var args []interface{}
+	if len(profileIDs) > 0 {
+		var err error
+		query, args, err = sqlx.In("DELETE FROM document_profiles WHERE profile_id NOT IN (?)", profileIDs)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	result, err := m.db.Exec(query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("清理文档处理配置失败: %w", err)
+	}
+	removed, _ := result.RowsAffected()
+	return int(removed), nil
+}
@@ -0,0 +1,206 @@
+package proofread
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 修改的状态
+const (
+	StatusPending  = "pending"  // 待确认
+	StatusAccepted = "accepted" // 已接受，尚未合并到文本
+	StatusRejected = "rejected" // 已拒绝
+	StatusApplied  = "applied"  // 已合并到文本
+)
+
+// Prompt 校对提示词，要求模型以JSON数组返回修改而不是改写后的全文
+const Prompt = `请校对以下文本中的错别字、OCR识别错误、标点和语法问题。不要改写全文，不要调整措辞和风格，只指出确实需要修改的地方。
+只返回一个JSON数组，不要输出任何解释或Markdown标记，每个元素包含：
+- original：原文中需要修改的内容，必须与原文完全一致，尽量简短
+- replacement：修改后的内容
+- reason：修改原因（简短说明）
+- context：原文中包含该内容的一小段（前后各约10个字），与原文完全一致，用于定位
+没有需要修改的地方时返回 []。`
+
+// Edit 一处校对修改
+type Edit struct {
+	ID          int64     `json:"id" db:"id"`
+	DocumentID  string    `json:"document_id" db:"document_id"`
+	PageNumber  int       `json:"page_number" db:"page_number"`
+	TextType    string    `json:"text_type" db:"text_type"` // ocr 或 ai
+	Start       int       `json:"start" db:"start_offset"`  // 在页面文本中的起始位置（按字符计，从0开始）
+	End         int       `json:"end" db:"end_offset"`      // 结束位置（不含）
+	Original    string    `json:"original" db:"original"`
+	Replacement string    `json:"replacement" db:"replacement"`
+	Reason      string    `json:"reason" db:"reason"`
+	Status      string    `json:"status" db:"status"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// suggestion 模型返回的一条修改建议
+type suggestion struct {
+	Original    string `json:"original"`
+	Replacement string `json:"replacement"`
+	Reason      string `json:"reason"`
+	Context     string `json:"context"`
+}
+
+// Parse 解析模型返回的修改建议，并在原文中定位每处修改
+// 长文本分段处理时返回中可能有多个JSON数组，全部合并；无法在原文中找到、与其他修改重叠或没有实际改动的建议会被忽略
+func Parse(text, response string) ([]Edit, error) {
+	suggestions, found := findSuggestions(response)
+	if !found {
+		return nil, fmt.Errorf("模型返回中没有修改列表")
+	}
+
+	runes := []rune(text)
+	edits := []Edit{}
+	for _, s := range suggestions {
+		if s.Original == "" || s.Original == s.Replacement {
+			continue
+		}
+		start, ok := locate(runes, s, edits)
+		if !ok {
+			continue
+		}
+		edits = append(edits, Edit{
+			Start:       start,
+			End:         start + len([]rune(s.Original)),
+			Original:    s.Original,
+			Replacement: s.Replacement,
+			Reason:      s.Reason,
+			Status:      StatusPending,
+		})
+	}
+	sort.Slice(edits, func(i, j int) bool { return edits[i].Start < edits[j].Start })
+	return edits, nil
+}
+
+// findSuggestions 找出文本中所有顶层JSON数组中的修改建议（兼容 ```json 代码块和前后的说明文字）
+func findSuggestions(text string) ([]suggestion, bool) {
+	var suggestions []suggestion
+	found := false
+	for start := strings.IndexByte(text, '['); start >= 0; {
+		decoder := json.NewDecoder(strings.NewReader(text[start:]))
+		var batch []suggestion
+		if err := decoder.Decode(&batch); err == nil {
+			suggestions = append(suggestions, batch...)
+			found = true
+			start += int(decoder.InputOffset())
+		} else {
+			start++
+		}
+
+		next := strings.IndexByte(text[start:], '[')
+		if next < 0 {
+			break
+		}
+		start += next
+	}
+	return suggestions, found
+}
+
+// locate 在原文中定位修改：优先使用上下文，否则取第一个未被其他修改占用的位置
+func locate(runes []rune, s suggestion, existing []Edit) (int, bool) {
+	original := []rune(s.Original)
+	if s.Context != "" {
+		if ctx := indexRunes(runes, []rune(s.Context), 0); ctx >= 0 {
+			if i := indexRunes([]rune(s.Context), original, 0); i >= 0 && !overlaps(ctx+i, ctx+i+len(original), existing) {
+				return ctx + i, true
+			}
+		}
+	}
+	for from := 0; ; {
+		i := indexRunes(runes, original, from)
+		if i < 0 {
+			return 0, false
+		}
+		if !overlaps(i, i+len(original), existing) {
+			return i, true
+		}
+		from = i + 1
+	}
+}
+
+// indexRunes 查找子序列的位置
+func indexRunes(runes, sub []rune, from int) int {
+	for i := from; i+len(sub) <= len(runes); i++ {
+		if string(runes[i:i+len(sub)]) == string(sub) {
+			return i
+		}
+	}
+	return -1
+}
+
+// overlaps 区间是否与已有修改重叠
+func overlaps(start, end int, edits []Edit) bool {
+	for _, e := range edits {
+		if start < e.End && e.Start < end {
+			return true
+		}
+	}
+	return false
+}
+
+// Merge 将已接受的修改合并到文本中，返回新文本、合并的修改ID，以及位置已按合并结果调整的待确认修改
+// 原文位置上的内容与修改不一致（文本在校对后被编辑过）的修改不会合并；与合并内容重叠的待确认修改标记为已拒绝
+func Merge(text string, edits []Edit) (string, []int64, []Edit) {
+	runes := []rune(text)
+	var accepted, pending []Edit
+	for _, e := range edits {
+		switch e.Status {
+		case StatusAccepted:
+			if e.Start >= 0 && e.End <= len(runes) && e.Start <= e.End && string(runes[e.Start:e.End]) == e.Original {
+				accepted = append(accepted, e)
+			}
+		case StatusPending:
+			pending = append(pending, e)
+		}
+	}
+	sort.Slice(accepted, func(i, j int) bool { return accepted[i].Start < accepted[j].Start })
+
+	var b strings.Builder
+	var applied []int64
+	last := 0
+	for _, e := range accepted {
+		if e.Start < last {
+			continue
+		}
+		b.WriteString(string(runes[last:e.Start]))
+		b.WriteString(e.Replacement)
+		last = e.End
+		applied = append(applied, e.ID)
+	}
+	b.WriteString(string(runes[last:]))
+
+	// 待确认修改的位置随之前的合并结果移动
+	for i, p := range pending {
+		shift := 0
+		for _, e := range accepted {
+			if !containsID(applied, e.ID) {
+				continue
+			}
+			if e.End <= p.Start {
+				shift += len([]rune(e.Replacement)) - (e.End - e.Start)
+			} else if p.Start < e.End && e.Start < p.End {
+				pending[i].Status = StatusRejected
+			}
+		}
+		pending[i].Start += shift
+		pending[i].End += shift
+	}
+	return b.String(), applied, pending
+}
+
+// containsID 切片中是否包含ID
+func containsID(ids []int64, id int64) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
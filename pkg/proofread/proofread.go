@@ -0,0 +1,164 @@
+package proofread
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"pdf-ocr-ai/pkg/migrate"
+	"pdf-ocr-ai/pkg/storage"
+)
+
+// migrationComponent 表结构迁移使用的组件名
+const migrationComponent = "proofread"
+
+// Manager 校对修改存储，每处修改一行，逐条接受或拒绝后再合并到页面文本
+type Manager struct {
+	db    *sqlx.DB
+	store *storage.Store // 修改内容加解密
+}
+
+// NewManager 创建校对修改管理器，使用统一数据库中的 proofread_edits 表
+func NewManager(store *storage.Store) (*Manager, error) {
+	m := &Manager{db: store.DB(), store: store}
+	if err := store.Migrate(migrationComponent, migrations()); err != nil {
+		return nil, err
+	}
+	store.RegisterEncryptedColumns("proofread_edits", "original", "replacement", "reason")
+	return m, nil
+}
+
+// migrations 表结构迁移
+func migrations() []migrate.Migration {
+	return []migrate.Migration{
+		{
+			Version:     1,
+			Description: "创建校对修改表",
+			Up: migrate.SQL(`
+			CREATE TABLE IF NOT EXISTS proofread_edits (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				document_id TEXT NOT NULL,
+				page_number INTEGER NOT NULL,
+				text_type TEXT NOT NULL,
+				start_offset INTEGER NOT NULL,
+				end_offset INTEGER NOT NULL,
+				original TEXT NOT NULL DEFAULT '',
+				replacement TEXT NOT NULL DEFAULT '',
+				reason TEXT NOT NULL DEFAULT '',
+				status TEXT NOT NULL DEFAULT 'pending',
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			)`,
+				`CREATE INDEX IF NOT EXISTS idx_proofread_edits_page ON proofread_edits(document_id, page_number)`),
+		},
+	}
+}
+
+// ReplacePage 保存页面新的校对结果，替换该页面同类文本尚未合并的修改，返回带ID的修改
+func (m *Manager) ReplacePage(documentID string, pageNumber int, textType string, edits []Edit) ([]Edit, error) {
+	tx, err := m.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("保存校对结果失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM proofread_edits WHERE document_id = ? AND page_number = ? AND text_type = ? AND status != ?",
+		documentID, pageNumber, textType, StatusApplied)
+	if err != nil {
+		return nil, fmt.Errorf("保存校对结果失败: %w", err)
+	}
+
+	saved := make([]Edit, 0, len(edits))
+	for _, edit := range edits {
+		edit.DocumentID, edit.PageNumber, edit.TextType = documentID, pageNumber, textType
+		original, replacement, reason := edit.Original, edit.Replacement, edit.Reason
+		if err := m.store.EncryptFields(&original, &replacement, &reason); err != nil {
+			return nil, err
+		}
+		result, err := tx.Exec(`
+		INSERT INTO proofread_edits (document_id, page_number, text_type, start_offset, end_offset, original, replacement, reason, status, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, documentID, pageNumber, textType, edit.Start, edit.End, original, replacement, reason, edit.Status, edit.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("保存校对结果失败: %w", err)
+		}
+		if edit.ID, err = result.LastInsertId(); err != nil {
+			return nil, fmt.Errorf("保存校对结果失败: %w", err)
+		}
+		saved = append(saved, edit)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("保存校对结果失败: %w", err)
+	}
+	return saved, nil
+}
+
+// List 获取文档的校对修改（按页码和位置排序），pageNumber 为0时返回所有页面
+func (m *Manager) List(documentID string, pageNumber int) ([]Edit, error) {
+	query := "SELECT * FROM proofread_edits WHERE document_id = ?"
+	args := []interface{}{documentID}
+	if pageNumber > 0 {
+		query += " AND page_number = ?"
+		args = append(args, pageNumber)
+	}
+	query += " ORDER BY page_number, text_type, start_offset"
+
+	edits := []Edit{}
+	if err := m.db.Select(&edits, query, args...); err != nil {
+		return nil, fmt.Errorf("查询校对结果失败: %w", err)
+	}
+	for i := range edits {
+		if err := m.store.DecryptFields(&edits[i].Original, &edits[i].Replacement, &edits[i].Reason); err != nil {
+			return nil, err
+		}
+	}
+	return edits, nil
+}
+
+// SetStatus 更新修改的状态，已合并的修改不能再更改
+func (m *Manager) SetStatus(id int64, status string) error {
+	switch status {
+	case StatusPending, StatusAccepted, StatusRejected:
+	default:
+		return fmt.Errorf("无效的修改状态: %s", status)
+	}
+
+	result, err := m.db.Exec("UPDATE proofread_edits SET status = ? WHERE id = ? AND status != ?", status, id, StatusApplied)
+	if err != nil {
+		return fmt.Errorf("更新修改状态失败: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("修改不存在或已合并: %d", id)
+	}
+	return nil
+}
+
+// MarkApplied 标记修改已合并，并保存其余修改调整后的位置和状态
+func (m *Manager) MarkApplied(applied []int64, remaining []Edit) error {
+	tx, err := m.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("更新修改状态失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, id := range applied {
+		if _, err := tx.Exec("UPDATE proofread_edits SET status = ? WHERE id = ?", StatusApplied, id); err != nil {
+			return fmt.Errorf("更新修改状态失败: %w", err)
+		}
+	}
+	for _, edit := range remaining {
+		_, err := tx.Exec("UPDATE proofread_edits SET start_offset = ?, end_offset = ?, status = ? WHERE id = ?",
+			edit.Start, edit.End, edit.Status, edit.ID)
+		if err != nil {
+			return fmt.Errorf("更新修改状态失败: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// DeleteDocument 删除文档的所有校对修改
+func (m *Manager) DeleteDocument(documentID string) error {
+	if _, err := m.db.Exec("DELETE FROM proofread_edits WHERE document_id = ?", documentID); err != nil {
+		return fmt.Errorf("删除校对结果失败: %w", err)
+	}
+	return nil
+}
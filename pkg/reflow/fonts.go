@@ -0,0 +1,138 @@
+package reflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pdfcpu/pdfcpu/pkg/font"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// defaultFont 只含西文字符时使用的PDF核心字体
+const defaultFont = "Times-Roman"
+
+// latinExtras 核心字体（WinAnsi编码）能显示的 0xFF 以上的常用标点
+const latinExtras = "‘’‚“”„–—…•€™†‡‰‹›"
+
+// cjkFontCandidates 系统中常见的中日韩TrueType字体，使用第一个存在且能安装的
+var cjkFontCandidates = []string{
+	`C:\Windows\Fonts\simsun.ttc`,
+	`C:\Windows\Fonts\msyh.ttc`,
+	`C:\Windows\Fonts\simhei.ttf`,
+	"/System/Library/Fonts/Supplemental/Songti.ttc",
+	"/System/Library/Fonts/STHeiti Light.ttc",
+	"/Library/Fonts/Arial Unicode.ttf",
+	"/usr/share/fonts/truetype/wqy/wqy-microhei.ttc",
+	"/usr/share/fonts/truetype/wqy/wqy-zenhei.ttc",
+	"/usr/share/fonts/wqy-microhei/wqy-microhei.ttc",
+	"/usr/share/fonts/wqy-zenhei/wqy-zenhei.ttc",
+	"/usr/share/fonts/truetype/droid/DroidSansFallbackFull.ttf",
+}
+
+var (
+	installMu sync.Mutex
+	installed = map[string]string{} // 字体文件路径 -> 安装后的字体名
+)
+
+// resolveFont 选择正文字体：只含西文字符时使用 Font（核心字体名或字体文件），默认 Times-Roman；
+// 含中日韩等其他文字时使用 CJKFont 或字体文件形式的 Font，都未指定时查找系统中常见的中日韩字体
+func resolveFont(options Options, needsUnicode bool) (string, error) {
+	if !needsUnicode {
+		if options.Font == "" {
+			return defaultFont, nil
+		}
+		if font.IsCoreFont(options.Font) {
+			return options.Font, nil
+		}
+		return installFont(options.Font)
+	}
+
+	path := options.CJKFont
+	if path == "" && options.Font != "" && !font.IsCoreFont(options.Font) {
+		path = options.Font
+	}
+	if path != "" {
+		return installFont(path)
+	}
+	for _, path := range cjkFontCandidates {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		if name, err := installFont(path); err == nil {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("未找到可用的中日韩字体，请指定TrueType字体文件（.ttf 或 .ttc）")
+}
+
+// installFont 将TrueType字体文件安装到pdfcpu的字体目录，返回字体名（TTC集合使用其中第一个字体）
+func installFont(path string) (string, error) {
+	installMu.Lock()
+	defer installMu.Unlock()
+	if name, ok := installed[path]; ok {
+		return name, nil
+	}
+
+	// 初始化pdfcpu配置目录，设置字体目录
+	model.NewDefaultConfiguration()
+	if font.UserFontDir == "" {
+		return "", fmt.Errorf("pdfcpu字体目录不可用")
+	}
+
+	// 先安装到临时目录获得字体名，再复制到字体目录
+	tempDir, err := os.MkdirTemp("", "pdfseer-font-*")
+	if err != nil {
+		return "", fmt.Errorf("创建临时目录失败: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ttf":
+		err = font.InstallTrueTypeFont(tempDir, path)
+	case ".ttc":
+		err = font.InstallTrueTypeCollection(tempDir, path)
+	default:
+		return "", fmt.Errorf("只支持TrueType字体文件（.ttf 或 .ttc）: %s", filepath.Base(path))
+	}
+	if err != nil {
+		return "", fmt.Errorf("安装字体 %s 失败: %w", filepath.Base(path), err)
+	}
+
+	gobs, err := filepath.Glob(filepath.Join(tempDir, "*.gob"))
+	if err != nil || len(gobs) == 0 {
+		return "", fmt.Errorf("安装字体 %s 失败: 没有可用的字体", filepath.Base(path))
+	}
+	sort.Strings(gobs)
+	for _, gob := range gobs {
+		data, err := os.ReadFile(gob)
+		if err != nil {
+			return "", fmt.Errorf("安装字体失败: %w", err)
+		}
+		if err := os.WriteFile(filepath.Join(font.UserFontDir, filepath.Base(gob)), data, 0644); err != nil {
+			return "", fmt.Errorf("安装字体失败: %w", err)
+		}
+	}
+	if err := font.LoadUserFonts(); err != nil {
+		return "", fmt.Errorf("加载字体失败: %w", err)
+	}
+
+	name := strings.TrimSuffix(filepath.Base(gobs[0]), ".gob")
+	installed[path] = name
+	return name, nil
+}
+
+// needsUnicodeFont 文本是否包含核心字体无法显示的字符
+func needsUnicodeFont(blocks []Block) bool {
+	for _, block := range blocks {
+		for _, r := range block.Text {
+			if r > 0xFF && !strings.ContainsRune(latinExtras, r) {
+				return true
+			}
+		}
+	}
+	return false
+}
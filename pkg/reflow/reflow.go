@@ -0,0 +1,397 @@
+// Package reflow 将识别出的文本重新排版为新的PDF（不含原页面图片），相当于扫描书籍的电子版
+package reflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/font"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// 默认版式
+const (
+	DefaultPageSize    = "A4"
+	DefaultFontSize    = 11
+	DefaultMargin      = 20.0 // mm
+	DefaultLineSpacing = 1.5
+)
+
+// headingScales 一至三级标题相对正文的字号比例
+var headingScales = []float64{1, 1.6, 1.35, 1.15}
+
+// Options 版式选项，零值使用默认值
+type Options struct {
+	PageSize    string  `json:"page_size"`    // 纸张大小，如 A4、A5、B5、Letter
+	FontSize    int     `json:"font_size"`    // 正文字号（pt）
+	Margin      float64 `json:"margin"`       // 页边距（mm）
+	LineSpacing float64 `json:"line_spacing"` // 行距倍数
+	Font        string  `json:"font"`         // 西文字体：PDF核心字体名（如 Times-Roman、Helvetica）或TrueType字体文件路径
+	CJKFont     string  `json:"cjk_font"`     // 含中日韩文字时使用的TrueType字体文件，为空时查找系统字体
+	Indent      bool    `json:"indent"`       // 段落首行缩进两个字符
+	PageNumbers bool    `json:"page_numbers"` // 页面底部显示页码
+}
+
+// Block 排版块：标题或段落
+type Block struct {
+	Text    string
+	Heading int  // 标题级别1-3，0 表示正文
+	Line    bool // 表格行、代码等按原样单独成行，不缩进
+}
+
+var (
+	markdownImage  = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
+	markdownLink   = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+	markdownEmph   = regexp.MustCompile("\\*\\*|__|`")
+	orderedItem    = regexp.MustCompile(`^\d+[.)、]\s`)
+	tableSeparator = regexp.MustCompile(`^\|?[\s:|-]+\|?$`)
+)
+
+// Parse 将连续文本（可含Markdown标记）拆分为排版块：空行分隔段落，段内的硬换行合并，
+// # 标题转为标题块，列表项、表格行和代码行单独成行，图片和链接地址去除
+func Parse(text string) []Block {
+	var blocks []Block
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) > 0 {
+			blocks = append(blocks, Block{Text: joinLines(paragraph)})
+			paragraph = nil
+		}
+	}
+
+	inCode := false
+	for _, raw := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(raw), "```") {
+			flush()
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			if line := strings.TrimRight(raw, " \t"); line != "" {
+				blocks = append(blocks, Block{Text: line, Line: true})
+			}
+			continue
+		}
+
+		line := strings.TrimSpace(raw)
+		line = strings.TrimSpace(strings.TrimLeft(line, ">"))
+		switch {
+		case line == "":
+			flush()
+		case strings.HasPrefix(line, "#"):
+			flush()
+			level := len(line) - len(strings.TrimLeft(line, "#"))
+			if title := cleanInline(strings.TrimLeft(line, "# ")); title != "" {
+				blocks = append(blocks, Block{Text: title, Heading: min(level, 3)})
+			}
+		case line == "---" || line == "***" || line == "___":
+			flush()
+		case strings.HasPrefix(line, "|"):
+			flush()
+			if !tableSeparator.MatchString(line) {
+				cells := strings.Split(strings.Trim(line, "|"), "|")
+				for i := range cells {
+					cells[i] = strings.TrimSpace(cells[i])
+				}
+				blocks = append(blocks, Block{Text: cleanInline(strings.Join(cells, "    ")), Line: true})
+			}
+		case strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") || strings.HasPrefix(line, "+ "):
+			flush()
+			paragraph = append(paragraph, "• "+cleanInline(line[2:]))
+		case orderedItem.MatchString(line):
+			flush()
+			paragraph = append(paragraph, cleanInline(line))
+		default:
+			if text := cleanInline(line); text != "" {
+				paragraph = append(paragraph, text)
+			}
+		}
+	}
+	flush()
+	return blocks
+}
+
+// cleanInline 去掉行内的Markdown标记：图片、链接地址、粗体和代码标记
+func cleanInline(line string) string {
+	line = markdownImage.ReplaceAllString(line, "")
+	line = markdownLink.ReplaceAllString(line, "$1")
+	line = markdownEmph.ReplaceAllString(line, "")
+	return strings.TrimSpace(line)
+}
+
+// joinLines 合并段落内的硬换行：中日韩文字之间直接连接，英文断词去掉连字符，其余用空格连接
+func joinLines(lines []string) string {
+	var builder strings.Builder
+	for i, line := range lines {
+		if i == 0 {
+			builder.WriteString(line)
+			continue
+		}
+		prev := builder.String()
+		last, _ := utf8.DecodeLastRuneInString(prev)
+		first, _ := utf8.DecodeRuneInString(line)
+		switch {
+		case last == '-' && unicode.IsLower(first):
+			beforeHyphen, _ := utf8.DecodeLastRuneInString(prev[:len(prev)-1])
+			if unicode.IsLetter(beforeHyphen) {
+				builder.Reset()
+				builder.WriteString(prev[:len(prev)-1])
+			}
+		case isWide(last) || isWide(first):
+		default:
+			builder.WriteString(" ")
+		}
+		builder.WriteString(line)
+	}
+	return builder.String()
+}
+
+// layout 排版过程的状态
+type layout struct {
+	fontName    string
+	fontSize    int
+	lineSpacing float64
+	indent      bool
+	width       float64
+	height      float64
+	margin      float64
+	y           float64
+	pages       [][]map[string]interface{}
+}
+
+// Render 将排版块排版为新的PDF写入 w
+func Render(blocks []Block, w io.Writer, options Options) error {
+	if len(blocks) == 0 {
+		return fmt.Errorf("没有可排版的文本")
+	}
+	options = withDefaults(options)
+
+	paper, size, err := paperSize(options.PageSize)
+	if err != nil {
+		return err
+	}
+	fontName, err := resolveFont(options, needsUnicodeFont(blocks))
+	if err != nil {
+		return err
+	}
+
+	l := &layout{
+		fontName:    fontName,
+		fontSize:    options.FontSize,
+		lineSpacing: options.LineSpacing,
+		indent:      options.Indent,
+		width:       size.Width,
+		height:      size.Height,
+		margin:      options.Margin * 72 / 25.4,
+	}
+	if l.width-2*l.margin < float64(options.FontSize)*4 || l.height-2*l.margin < float64(options.FontSize)*4 {
+		return fmt.Errorf("页边距过大，没有排版空间")
+	}
+	l.newPage()
+	for i, block := range blocks {
+		l.writeBlock(block, i > 0 && block.Heading > 0 && blocks[i-1].Heading == 0)
+	}
+
+	pages := make(map[string]interface{}, len(l.pages))
+	for i, texts := range l.pages {
+		if options.PageNumbers {
+			number := strconv.Itoa(i + 1)
+			numberSize := max(options.FontSize-2, 6)
+			x := (l.width - font.TextWidth(number, fontName, numberSize)) / 2
+			texts = append(texts, l.text(number, x, l.margin/2, numberSize))
+		}
+		pages[strconv.Itoa(i+1)] = map[string]interface{}{
+			"content": map[string]interface{}{"text": texts},
+		}
+	}
+	data, err := json.Marshal(map[string]interface{}{
+		"paper":  paper,
+		"origin": "LowerLeft",
+		"pages":  pages,
+	})
+	if err != nil {
+		return fmt.Errorf("生成排版数据失败: %w", err)
+	}
+
+	conf := model.NewDefaultConfiguration()
+	if err := api.Create(nil, bytes.NewReader(data), w, conf); err != nil {
+		return fmt.Errorf("生成PDF失败: %w", err)
+	}
+	return nil
+}
+
+// withDefaults 为未设置的选项填入默认值
+func withDefaults(options Options) Options {
+	if options.PageSize == "" {
+		options.PageSize = DefaultPageSize
+	}
+	if options.FontSize <= 0 {
+		options.FontSize = DefaultFontSize
+	}
+	if options.Margin <= 0 {
+		options.Margin = DefaultMargin
+	}
+	if options.LineSpacing <= 0 {
+		options.LineSpacing = DefaultLineSpacing
+	}
+	return options
+}
+
+// paperSize 按名称查找纸张尺寸（pt），不区分大小写，返回pdfcpu使用的纸张名
+func paperSize(name string) (string, *types.Dim, error) {
+	for key, dim := range types.PaperSize {
+		if strings.EqualFold(key, name) {
+			return key, dim, nil
+		}
+	}
+	return "", nil, fmt.Errorf("不支持的纸张大小: %s", name)
+}
+
+// newPage 开始新页面
+func (l *layout) newPage() {
+	l.pages = append(l.pages, []map[string]interface{}{})
+	l.y = l.height - l.margin
+}
+
+// writeBlock 排版一个块，放不下的行移到下一页；spaceBefore 为 true 时在标题前多空半行
+func (l *layout) writeBlock(block Block, spaceBefore bool) {
+	size := l.fontSize
+	if block.Heading > 0 {
+		size = int(float64(l.fontSize)*headingScales[block.Heading] + 0.5)
+	}
+	lineHeight := float64(size) * l.lineSpacing
+	if spaceBefore && l.y < l.height-l.margin {
+		l.y -= lineHeight / 2
+	}
+
+	indent := 0.0
+	if l.indent && block.Heading == 0 && !block.Line && !strings.HasPrefix(block.Text, "•") {
+		indent = float64(size) * 2
+	}
+	lines := wrap(block.Text, l.fontName, size, l.width-2*l.margin, indent)
+
+	for i, line := range lines {
+		// 标题不单独留在页面底部
+		need := lineHeight
+		if i == 0 && block.Heading > 0 {
+			need += float64(l.fontSize) * l.lineSpacing
+		}
+		if l.y-need < l.margin {
+			l.newPage()
+		}
+		l.y -= lineHeight
+		x := l.margin
+		if i == 0 {
+			x += indent
+		}
+		page := len(l.pages) - 1
+		l.pages[page] = append(l.pages[page], l.text(line, x, l.y, size))
+	}
+
+	// 段落之间空半行
+	if block.Heading > 0 || !block.Line {
+		l.y -= float64(l.fontSize) * l.lineSpacing / 2
+	}
+}
+
+// text pdfcpu文本框的JSON描述，pos 为文本框左下角
+func (l *layout) text(value string, x, y float64, size int) map[string]interface{} {
+	return map[string]interface{}{
+		"value": value,
+		"pos":   []float64{x, y},
+		"font":  map[string]interface{}{"name": l.fontName, "size": size},
+	}
+}
+
+// wrap 按宽度折行：英文按单词折行，过长的单词和中日韩文字按字符折行，行首的闭合标点留在上一行末尾
+// 第一行的宽度减去首行缩进 indent
+func wrap(text, fontName string, size int, maxWidth, indent float64) []string {
+	var lines []string
+	var line strings.Builder
+	lineWidth := indent
+	push := func() {
+		if s := strings.TrimRight(line.String(), " "); s != "" {
+			lines = append(lines, s)
+		}
+		line.Reset()
+		lineWidth = 0
+	}
+
+	for _, token := range tokenize(text) {
+		if token == " " && line.Len() == 0 {
+			continue
+		}
+		tokenWidth := font.TextWidth(token, fontName, size)
+		if lineWidth+tokenWidth <= maxWidth || isClosingPunct(token) {
+			line.WriteString(token)
+			lineWidth += tokenWidth
+			continue
+		}
+		push()
+		if token == " " {
+			continue
+		}
+		if tokenWidth <= maxWidth {
+			line.WriteString(token)
+			lineWidth = tokenWidth
+			continue
+		}
+		// 单词比整行还宽时按字符折行
+		for _, r := range token {
+			runeWidth := font.TextWidth(string(r), fontName, size)
+			if lineWidth+runeWidth > maxWidth && line.Len() > 0 {
+				push()
+			}
+			line.WriteRune(r)
+			lineWidth += runeWidth
+		}
+	}
+	push()
+	return lines
+}
+
+// tokenize 将文本切分为折行单位：连续的西文字符、单个空格和单个中日韩字符或全角标点
+func tokenize(text string) []string {
+	var tokens []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			tokens = append(tokens, word.String())
+			word.Reset()
+		}
+	}
+	for _, r := range text {
+		switch {
+		case r == ' ' || r == '\t':
+			flush()
+			tokens = append(tokens, " ")
+		case isWide(r):
+			flush()
+			tokens = append(tokens, string(r))
+		default:
+			word.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+// isWide 是否为中日韩文字或全角标点
+func isWide(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		(r >= 0x3000 && r <= 0x303F) || (r >= 0xFF00 && r <= 0xFFEF)
+}
+
+// isClosingPunct 是否为不能出现在行首的标点
+func isClosingPunct(token string) bool {
+	return utf8.RuneCountInString(token) == 1 && strings.Contains("，。、；：？！）》」』”’,.;:?!)", token)
+}
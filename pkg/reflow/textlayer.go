@@ -0,0 +1,94 @@
+package reflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// minLayerFontSize 文本层排版时允许缩小到的最小字号
+const minLayerFontSize = 3
+
+// TextLayer 在PDF的每页上叠加不可见的文本（透明度为0的图章），使扫描件可以搜索和复制文字
+// texts[i] 为第 i+1 页的文本，为空时该页不加文字；每页文本按版式选项排版在一张纸上（放不下时缩小字号），
+// 再缩放到原页面的宽度叠加，文字位置与页面图片中的文字并不对应
+func TextLayer(rs io.ReadSeeker, w io.Writer, texts []string, options Options) error {
+	options = withDefaults(options)
+	paper, size, err := paperSize(options.PageSize)
+	if err != nil {
+		return err
+	}
+
+	pageBlocks := make([][]Block, len(texts))
+	var all []Block
+	for i, text := range texts {
+		pageBlocks[i] = Parse(text)
+		all = append(all, pageBlocks[i]...)
+	}
+	if len(all) == 0 {
+		return fmt.Errorf("没有可添加的文本")
+	}
+	fontName, err := resolveFont(options, needsUnicodeFont(all))
+	if err != nil {
+		return err
+	}
+
+	pages := make(map[string]interface{}, len(texts))
+	for i, blocks := range pageBlocks {
+		pageTexts := []map[string]interface{}{}
+		for fontSize := options.FontSize; len(blocks) > 0; fontSize-- {
+			l := &layout{
+				fontName:    fontName,
+				fontSize:    fontSize,
+				lineSpacing: options.LineSpacing,
+				width:       size.Width,
+				height:      size.Height,
+				margin:      options.Margin * 72 / 25.4,
+			}
+			l.newPage()
+			for j, block := range blocks {
+				l.writeBlock(block, j > 0 && block.Heading > 0 && blocks[j-1].Heading == 0)
+			}
+			// 最小字号仍放不下时，超出的部分叠加在同一页上
+			if len(l.pages) == 1 || fontSize <= minLayerFontSize {
+				for _, page := range l.pages {
+					pageTexts = append(pageTexts, page...)
+				}
+				break
+			}
+		}
+		pages[strconv.Itoa(i+1)] = map[string]interface{}{
+			"content": map[string]interface{}{"text": pageTexts},
+		}
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"paper":  paper,
+		"origin": "LowerLeft",
+		"pages":  pages,
+	})
+	if err != nil {
+		return fmt.Errorf("生成文本层数据失败: %w", err)
+	}
+	var overlay bytes.Buffer
+	conf := model.NewDefaultConfiguration()
+	if err := api.Create(nil, bytes.NewReader(data), &overlay, conf); err != nil {
+		return fmt.Errorf("生成文本层失败: %w", err)
+	}
+
+	wm, err := api.PDFMultiWatermarkForReadSeeker(bytes.NewReader(overlay.Bytes()), 1, 1,
+		"scalefactor:1 rel, rotation:0, opacity:0", true, false, types.POINTS)
+	if err != nil {
+		return fmt.Errorf("生成文本层失败: %w", err)
+	}
+	if err := api.AddWatermarks(rs, w, nil, wm, conf); err != nil {
+		return fmt.Errorf("添加文本层失败: %w", err)
+	}
+	return nil
+}
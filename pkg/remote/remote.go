@@ -0,0 +1,95 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"pdf-ocr-ai/pkg/config"
+)
+
+// Uploader 远程存储
+type Uploader interface {
+	// Upload 上传文件内容，name 为相对于配置的远程目录的路径（使用 / 分隔）
+	Upload(ctx context.Context, name string, data []byte) error
+}
+
+// New 按配置创建远程存储
+func New(cfg config.RemoteUploadConfig) (Uploader, error) {
+	timeout := time.Duration(cfg.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+	client := &http.Client{Timeout: timeout}
+
+	switch cfg.Type {
+	case config.RemoteTypeWebDAV, "":
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("未配置WebDAV地址")
+		}
+		return &webDAV{cfg: cfg, client: client}, nil
+	case config.RemoteTypeS3:
+		if cfg.URL == "" || cfg.Bucket == "" || cfg.AccessKey == "" || cfg.SecretKey == "" {
+			return nil, fmt.Errorf("未配置S3服务地址、存储桶或访问密钥")
+		}
+		return &s3Bucket{cfg: cfg, client: client}, nil
+	default:
+		return nil, fmt.Errorf("不支持的远程存储类型: %s", cfg.Type)
+	}
+}
+
+// UploadWithRetry 上传文件，失败时按 1s、2s、4s... 的间隔重试 retries 次
+func UploadWithRetry(ctx context.Context, uploader Uploader, name string, data []byte, retries int) error {
+	delay := time.Second
+	for attempt := 0; ; attempt++ {
+		err := uploader.Upload(ctx, name, data)
+		if err == nil || attempt >= retries {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// remotePath 将远程目录前缀和文件名合并为不以 / 开头的路径
+func remotePath(prefix, name string) string {
+	return strings.TrimPrefix(path.Join("/", prefix, name), "/")
+}
+
+// escapePath 按段转义路径，保留 /
+func escapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i] = escapeSegment(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// escapeSegment 按 RFC 3986 转义路径段：只保留字母、数字和 -_.~
+func escapeSegment(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// checkStatus 检查响应状态码
+func checkStatus(resp *http.Response, action string) error {
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s失败: %s", action, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"pdf-ocr-ai/pkg/config"
+)
+
+// defaultS3Region 未配置区域时使用的区域，大多数S3兼容服务都接受
+const defaultS3Region = "us-east-1"
+
+// s3Bucket S3兼容的对象存储，使用 AWS Signature Version 4 签名
+type s3Bucket struct {
+	cfg    config.RemoteUploadConfig
+	client *http.Client
+}
+
+// Upload 以 PUT Object 上传文件
+func (s *s3Bucket) Upload(ctx context.Context, name string, data []byte) error {
+	endpoint, err := url.Parse(strings.TrimRight(s.cfg.URL, "/"))
+	if err != nil || endpoint.Host == "" {
+		return fmt.Errorf("无效的S3服务地址: %s", s.cfg.URL)
+	}
+
+	key := remotePath(s.cfg.Prefix, name)
+	host := s.cfg.Bucket + "." + endpoint.Host
+	objectPath := "/" + key
+	if s.cfg.PathStyle {
+		host = endpoint.Host
+		objectPath = "/" + s.cfg.Bucket + "/" + key
+	}
+	escapedPath := escapePath(objectPath)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint.Scheme+"://"+host+escapedPath, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("创建S3请求失败: %w", err)
+	}
+	if contentType := mime.TypeByExtension(path.Ext(key)); contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	s.sign(req, host, escapedPath, data, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("S3请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("上传 %s 失败: %s %s", name, resp.Status, strings.TrimSpace(string(body)))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// sign 按 AWS Signature Version 4 为请求签名，签名包含 host、x-amz-content-sha256 和 x-amz-date 请求头
+func (s *s3Bucket) sign(req *http.Request, host, escapedPath string, data []byte, now time.Time) {
+	region := s.cfg.Region
+	if region == "" {
+		region = defaultS3Region
+	}
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(data)
+
+	req.Host = host
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	const signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		escapedPath,
+		"",
+		"host:" + host + "\nx-amz-content-sha256:" + payloadHash + "\nx-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKey, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,87 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+
+	"pdf-ocr-ai/pkg/config"
+)
+
+// webDAV WebDAV共享目录
+type webDAV struct {
+	cfg    config.RemoteUploadConfig
+	client *http.Client
+
+	mu      sync.Mutex
+	created map[string]bool // 已确认存在的远程目录
+}
+
+// Upload 创建缺少的远程目录后以 PUT 上传文件
+func (w *webDAV) Upload(ctx context.Context, name string, data []byte) error {
+	target := remotePath(w.cfg.Prefix, name)
+	if dir := path.Dir(target); dir != "." {
+		if err := w.mkdirAll(ctx, dir); err != nil {
+			return err
+		}
+	}
+
+	resp, err := w.do(ctx, http.MethodPut, target, data)
+	if err != nil {
+		return err
+	}
+	return checkStatus(resp, "上传 "+name)
+}
+
+// mkdirAll 逐级创建远程目录（MKCOL），已存在的目录返回 405，忽略即可
+func (w *webDAV) mkdirAll(ctx context.Context, dir string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.created == nil {
+		w.created = map[string]bool{}
+	}
+
+	current := ""
+	for _, segment := range strings.Split(dir, "/") {
+		current = path.Join(current, segment)
+		if w.created[current] {
+			continue
+		}
+		resp, err := w.do(ctx, "MKCOL", current+"/", nil)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusMethodNotAllowed {
+			if err := checkStatus(resp, "创建远程目录 "+current); err != nil {
+				return err
+			}
+		}
+		w.created[current] = true
+	}
+	return nil
+}
+
+// do 发送请求并关闭响应体
+func (w *webDAV) do(ctx context.Context, method, target string, data []byte) (*http.Response, error) {
+	url := strings.TrimRight(w.cfg.URL, "/") + "/" + escapePath(target)
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("创建WebDAV请求失败: %w", err)
+	}
+	if w.cfg.Username != "" || w.cfg.Password != "" {
+		req.SetBasicAuth(w.cfg.Username, w.cfg.Password)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("WebDAV请求失败: %w", err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return resp, nil
+}
@@ -0,0 +1,104 @@
+package replace
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// contextRunes 预览中匹配内容前后保留的字符数
+const contextRunes = 20
+
+// Match 一处匹配
+type Match struct {
+	Line   int    `json:"line"`   // 所在行（从1开始）
+	Before string `json:"before"` // 替换前的片段（含前后文）
+	After  string `json:"after"`  // 替换后的片段
+}
+
+// Replacer 查找替换规则
+type Replacer struct {
+	re          *regexp.Regexp
+	replacement string
+	regex       bool
+}
+
+// New 创建查找替换规则，regex 为false时按普通文本查找
+// 正则模式下替换内容支持 $1、${name} 引用分组
+func New(pattern, replacement string, regex bool) (*Replacer, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("查找内容不能为空")
+	}
+	expr := pattern
+	if !regex {
+		expr = regexp.QuoteMeta(pattern)
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("正则表达式无效: %w", err)
+	}
+	return &Replacer{re: re, replacement: replacement, regex: regex}, nil
+}
+
+// Apply 替换文本中的所有匹配，返回替换后的文本和匹配列表（没有匹配时原样返回）
+func (r *Replacer) Apply(text string) (string, []Match) {
+	locs := r.re.FindAllStringSubmatchIndex(text, -1)
+	var matches []Match
+	var b strings.Builder
+	last := 0
+	for _, loc := range locs {
+		// 跳过空匹配（如 a* 在每个位置都能匹配）和替换后没有变化的匹配
+		if loc[0] == loc[1] {
+			continue
+		}
+		replaced := r.expand(text, loc)
+		if replaced == text[loc[0]:loc[1]] {
+			continue
+		}
+		b.WriteString(text[last:loc[0]])
+		b.WriteString(replaced)
+		last = loc[1]
+
+		prefix, suffix := surrounding(text, loc[0], loc[1])
+		matches = append(matches, Match{
+			Line:   strings.Count(text[:loc[0]], "\n") + 1,
+			Before: prefix + text[loc[0]:loc[1]] + suffix,
+			After:  prefix + replaced + suffix,
+		})
+	}
+	if len(matches) == 0 {
+		return text, nil
+	}
+	b.WriteString(text[last:])
+	return b.String(), matches
+}
+
+// expand 计算一处匹配的替换内容
+func (r *Replacer) expand(text string, loc []int) string {
+	if !r.regex {
+		return r.replacement
+	}
+	return string(r.re.ExpandString(nil, r.replacement, text, loc))
+}
+
+// surrounding 匹配前后同一行内的若干字符
+func surrounding(text string, start, end int) (string, string) {
+	prefix := text[:start]
+	if i := strings.LastIndexByte(prefix, '\n'); i >= 0 {
+		prefix = prefix[i+1:]
+	}
+	for utf8.RuneCountInString(prefix) > contextRunes {
+		_, size := utf8.DecodeRuneInString(prefix)
+		prefix = prefix[size:]
+	}
+
+	suffix := text[end:]
+	if i := strings.IndexByte(suffix, '\n'); i >= 0 {
+		suffix = suffix[:i]
+	}
+	if runes := []rune(suffix); len(runes) > contextRunes {
+		suffix = string(runes[:contextRunes])
+	}
+	return prefix, suffix
+}
@@ -0,0 +1,122 @@
+package reviews
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"pdf-ocr-ai/pkg/migrate"
+	"pdf-ocr-ai/pkg/storage"
+)
+
+// migrationComponent 表结构迁移使用的组件名
+const migrationComponent = "reviews"
+
+// 页面审核状态
+const (
+	StatusUnreviewed = "unreviewed" // 未审核（默认，不保存）
+	StatusApproved   = "approved"   // 已通过
+	StatusNeedsFix   = "needs_fix"  // 需要修改
+)
+
+// Manager 页面审核状态存储，用于发布前人工校对OCR结果
+type Manager struct {
+	db *sqlx.DB
+}
+
+// NewManager 创建审核状态管理器，使用统一数据库中的 page_reviews 表
+func NewManager(store *storage.Store) (*Manager, error) {
+	m := &Manager{db: store.DB()}
+	if err := store.Migrate(migrationComponent, migrations()); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// migrations 表结构迁移
+func migrations() []migrate.Migration {
+	return []migrate.Migration{
+		{
+			Version:     1,
+			Description: "创建页面审核状态表",
+			Up: migrate.SQL(`
+			CREATE TABLE IF NOT EXISTS page_reviews (
+				document_id TEXT NOT NULL,
+				page_number INTEGER NOT NULL,
+				status TEXT NOT NULL,
+				updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (document_id, page_number)
+			)`),
+		},
+	}
+}
+
+// ValidStatus 是否为有效的审核状态
+func ValidStatus(status string) bool {
+	return status == StatusUnreviewed || status == StatusApproved || status == StatusNeedsFix
+}
+
+// Set 设置页面的审核状态，设为未审核时删除记录
+func (m *Manager) Set(documentID string, pageNumbers []int, status string) error {
+	if !ValidStatus(status) {
+		return fmt.Errorf("无效的审核状态: %s", status)
+	}
+	tx, err := m.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("保存审核状态失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, pageNumber := range pageNumbers {
+		if status == StatusUnreviewed {
+			_, err = tx.Exec("DELETE FROM page_reviews WHERE document_id = ? AND page_number = ?", documentID, pageNumber)
+		} else {
+			_, err = tx.Exec(`INSERT INTO page_reviews (document_id, page_number, status, updated_at) VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+				ON CONFLICT(document_id, page_number) DO UPDATE SET status = excluded.status, updated_at = excluded.updated_at`,
+				documentID, pageNumber, status)
+		}
+		if err != nil {
+			return fmt.Errorf("保存审核状态失败: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("保存审核状态失败: %w", err)
+	}
+	return nil
+}
+
+// Statuses 获取文档已审核页面的状态（页码到状态），未审核的页面不包含在内
+func (m *Manager) Statuses(documentID string) (map[int]string, error) {
+	var rows []struct {
+		PageNumber int    `db:"page_number"`
+		Status     string `db:"status"`
+	}
+	if err := m.db.Select(&rows, "SELECT page_number, status FROM page_reviews WHERE document_id = ?", documentID); err != nil {
+		return nil, fmt.Errorf("查询审核状态失败: %w", err)
+	}
+	statuses := make(map[int]string, len(rows))
+	for _, row := range rows {
+		statuses[row.PageNumber] = row.Status
+	}
+	return statuses, nil
+}
+
+// DeleteDocument 删除文档的所有审核状态
+func (m *Manager) DeleteDocument(documentID string) error {
+	if _, err := m.db.Exec("DELETE FROM page_reviews WHERE document_id = ?", documentID); err != nil {
+		return fmt.Errorf("删除审核状态失败: %w", err)
+	}
+	return nil
+}
+
+// CopyDocument 将文档的审核状态复制到另一个文档ID，目标文档已审核的页面保持不变
+func (m *Manager) CopyDocument(fromID, toID string) error {
+	_, err := m.db.Exec(`
+	INSERT OR IGNORE INTO page_reviews (document_id, page_number, status, updated_at)
+	SELECT ?, page_number, status, updated_at FROM page_reviews WHERE document_id = ?
+	`, toID, fromID)
+	if err != nil {
+		return fmt.Errorf("复制审核状态失败: %w", err)
+	}
+	return nil
+}
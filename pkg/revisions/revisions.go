@@ -0,0 +1,220 @@
+package revisions
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"pdf-ocr-ai/pkg/migrate"
+	"pdf-ocr-ai/pkg/storage"
+)
+
+// migrationComponent 表结构迁移使用的组件名
+const migrationComponent = "revisions"
+
+// DefaultLimit 未配置时每个页面每类文本保留的修订版本数
+const DefaultLimit = 20
+
+// 修订来源
+const (
+	SourceInitial   = "initial"    // 开始记录修订之前已有的文本
+	SourceOCR       = "ocr"        // OCR识别
+	SourceAI        = "ai"         // AI处理
+	SourceEdit      = "edit"       // 手动编辑
+	SourceProofread = "proofread"  // 合并校对修改
+	SourceReplace   = "replace"    // 查找替换
+	SourceRestore   = "restore"    // 恢复历史版本
+	SourceRegion    = "region"     // 重新识别页面区域后插入或替换
+	SourceTextLayer = "text_layer" // 导入PDF已有的文本层
+	SourceHybrid    = "hybrid"     // 混合模式：原生文本加插图的识别结果
+)
+
+// Revision 页面文本的一个修订版本
+type Revision struct {
+	ID         int64     `json:"id" db:"id"`
+	DocumentID string    `json:"document_id" db:"document_id"`
+	PageNumber int       `json:"page_number" db:"page_number"`
+	TextType   string    `json:"text_type" db:"text_type"` // ocr 或 ai
+	Content    string    `json:"content" db:"content"`
+	Source     string    `json:"source" db:"source"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// Manager 页面文本修订历史，每个页面每类文本保留最近的若干个版本
+type Manager struct {
+	db    *sqlx.DB
+	store *storage.Store // 文本内容加解密
+
+	mu    sync.Mutex
+	limit int
+}
+
+// NewManager 创建修订历史管理器，使用统一数据库中的 page_revisions 表
+func NewManager(store *storage.Store, limit int) (*Manager, error) {
+	m := &Manager{db: store.DB(), store: store}
+	m.SetLimit(limit)
+	if err := store.Migrate(migrationComponent, migrations()); err != nil {
+		return nil, err
+	}
+	store.RegisterEncryptedColumns("page_revisions", "content")
+	return m, nil
+}
+
+// migrations 表结构迁移
+func migrations() []migrate.Migration {
+	return []migrate.Migration{
+		{
+			Version:     1,
+			Description: "创建页面修订历史表",
+			Up: migrate.SQL(`
+			CREATE TABLE IF NOT EXISTS page_revisions (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				document_id TEXT NOT NULL,
+				page_number INTEGER NOT NULL,
+				text_type TEXT NOT NULL,
+				content TEXT NOT NULL DEFAULT '',
+				source TEXT NOT NULL DEFAULT '',
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			)`,
+				`CREATE INDEX IF NOT EXISTS idx_page_revisions_page ON page_revisions(document_id, page_number, text_type)`),
+		},
+	}
+}
+
+// SetLimit 设置每个页面每类文本保留的版本数，小于等于0时使用默认值
+func (m *Manager) SetLimit(limit int) {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	m.mu.Lock()
+	m.limit = limit
+	m.mu.Unlock()
+}
+
+// Record 记录页面文本的新版本，与最新版本相同或为空时不记录
+// 页面还没有修订记录时，先把修改前的文本（previous）作为初始版本保存，以便撤销第一次修改
+func (m *Manager) Record(documentID string, pageNumber int, textType, previous, content, source string) error {
+	if content == "" {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	latest, err := m.latest(documentID, pageNumber, textType)
+	if err != nil {
+		return err
+	}
+	if latest != nil && latest.Content == content {
+		return nil
+	}
+	if latest == nil && previous != "" && previous != content {
+		if err := m.insert(documentID, pageNumber, textType, previous, SourceInitial); err != nil {
+			return err
+		}
+	}
+	if err := m.insert(documentID, pageNumber, textType, content, source); err != nil {
+		return err
+	}
+
+	_, err = m.db.Exec(`
+	DELETE FROM page_revisions WHERE document_id = ? AND page_number = ? AND text_type = ? AND id NOT IN (
+		SELECT id FROM page_revisions WHERE document_id = ? AND page_number = ? AND text_type = ? ORDER BY id DESC LIMIT ?
+	)`, documentID, pageNumber, textType, documentID, pageNumber, textType, m.limit)
+	if err != nil {
+		return fmt.Errorf("清理旧版本失败: %w", err)
+	}
+	return nil
+}
+
+// insert 插入一个版本
+func (m *Manager) insert(documentID string, pageNumber int, textType, content, source string) error {
+	if err := m.store.EncryptFields(&content); err != nil {
+		return err
+	}
+	_, err := m.db.Exec(`INSERT INTO page_revisions (document_id, page_number, text_type, content, source, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		documentID, pageNumber, textType, content, source, time.Now())
+	if err != nil {
+		return fmt.Errorf("保存修订版本失败: %w", err)
+	}
+	return nil
+}
+
+// latest 获取最新版本，没有时返回nil
+func (m *Manager) latest(documentID string, pageNumber int, textType string) (*Revision, error) {
+	var revision Revision
+	err := m.db.Get(&revision, `SELECT * FROM page_revisions WHERE document_id = ? AND page_number = ? AND text_type = ? ORDER BY id DESC LIMIT 1`,
+		documentID, pageNumber, textType)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询修订版本失败: %w", err)
+	}
+	if err := m.store.DecryptFields(&revision.Content); err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// List 获取页面的修订版本（从新到旧）
+func (m *Manager) List(documentID string, pageNumber int) ([]Revision, error) {
+	revisions := []Revision{}
+	err := m.db.Select(&revisions, `SELECT * FROM page_revisions WHERE document_id = ? AND page_number = ? ORDER BY id DESC`, documentID, pageNumber)
+	if err != nil {
+		return nil, fmt.Errorf("查询修订版本失败: %w", err)
+	}
+	for i := range revisions {
+		if err := m.store.DecryptFields(&revisions[i].Content); err != nil {
+			return nil, err
+		}
+	}
+	return revisions, nil
+}
+
+// Get 获取指定版本
+func (m *Manager) Get(id int64) (*Revision, error) {
+	var revision Revision
+	err := m.db.Get(&revision, `SELECT * FROM page_revisions WHERE id = ?`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("修订版本不存在: %d", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询修订版本失败: %w", err)
+	}
+	if err := m.store.DecryptFields(&revision.Content); err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// DeleteDocument 删除文档的所有修订版本
+func (m *Manager) DeleteDocument(documentID string) error {
+	if _, err := m.db.Exec("DELETE FROM page_revisions WHERE document_id = ?", documentID); err != nil {
+		return fmt.Errorf("删除修订版本失败: %w", err)
+	}
+	return nil
+}
+
+// EditedPages 获取文档中最新版本来自手动修改（编辑、校对、查找替换、恢复版本、区域重新识别）的页码
+func (m *Manager) EditedPages(documentID string) (map[int]bool, error) {
+	var pages []int
+	err := m.db.Select(&pages, `
+	SELECT DISTINCT r.page_number FROM page_revisions r
+	WHERE r.document_id = ? AND r.source IN (?, ?, ?, ?, ?) AND r.id = (
+		SELECT MAX(id) FROM page_revisions WHERE document_id = r.document_id AND page_number = r.page_number AND text_type = r.text_type
+	)`, documentID, SourceEdit, SourceProofread, SourceReplace, SourceRestore, SourceRegion)
+	if err != nil {
+		return nil, fmt.Errorf("查询修订版本失败: %w", err)
+	}
+
+	edited := make(map[int]bool, len(pages))
+	for _, page := range pages {
+		edited[page] = true
+	}
+	return edited, nil
+}
@@ -0,0 +1,228 @@
+package scheduler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// Task 定时任务定义
+type Task struct {
+	ID   string
+	Name string
+	Cron string
+	Kind string
+	Run  func() error
+}
+
+// TaskInfo 定时任务状态
+type TaskInfo struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Cron      string `json:"cron"`
+	Kind      string `json:"kind"`
+	NextRun   string `json:"next_run,omitempty"`
+	LastRun   string `json:"last_run,omitempty"`
+	LastError string `json:"last_error,omitempty"`
+	Running   bool   `json:"running"`
+}
+
+// entry 调度器内部的任务条目
+type entry struct {
+	task      Task
+	schedule  *CronSchedule
+	nextRun   time.Time
+	lastRun   time.Time
+	lastError string
+	running   bool
+}
+
+// Scheduler 轻量级定时任务调度器
+type Scheduler struct {
+	mu      sync.Mutex
+	entries []*entry
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewScheduler 创建调度器
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// SetTasks 替换全部任务，表达式无效时不做任何修改
+func (s *Scheduler) SetTasks(tasks []Task) error {
+	now := time.Now()
+	entries := make([]*entry, 0, len(tasks))
+
+	for _, task := range tasks {
+		schedule, err := ParseCron(task.Cron)
+		if err != nil {
+			return fmt.Errorf("定时任务 %s: %w", task.Name, err)
+		}
+		entries = append(entries, &entry{
+			task:     task,
+			schedule: schedule,
+			nextRun:  schedule.Next(now),
+		})
+	}
+
+	s.mu.Lock()
+	// 保留同ID任务的运行记录
+	for _, e := range entries {
+		for _, old := range s.entries {
+			if old.task.ID == e.task.ID {
+				e.lastRun = old.lastRun
+				e.lastError = old.lastError
+			}
+		}
+	}
+	s.entries = entries
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Start 启动调度循环
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stop != nil {
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.loop(s.stop, s.done)
+}
+
+// Stop 停止调度循环（不会中断正在运行的任务）
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+// Tasks 获取任务状态列表
+func (s *Scheduler) Tasks() []TaskInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	infos := make([]TaskInfo, 0, len(s.entries))
+	for _, e := range s.entries {
+		info := TaskInfo{
+			ID:        e.task.ID,
+			Name:      e.task.Name,
+			Cron:      e.task.Cron,
+			Kind:      e.task.Kind,
+			LastError: e.lastError,
+			Running:   e.running,
+		}
+		if !e.nextRun.IsZero() {
+			info.NextRun = e.nextRun.Format("2006-01-02 15:04:05")
+		}
+		if !e.lastRun.IsZero() {
+			info.LastRun = e.lastRun.Format("2006-01-02 15:04:05")
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// RunNow 立即运行指定任务
+func (s *Scheduler) RunNow(id string) error {
+	s.mu.Lock()
+	var target *entry
+	for _, e := range s.entries {
+		if e.task.ID == id {
+			target = e
+			break
+		}
+	}
+	if target == nil {
+		s.mu.Unlock()
+		return fmt.Errorf("定时任务不存在: %s", id)
+	}
+	if target.running {
+		s.mu.Unlock()
+		return fmt.Errorf("定时任务正在运行: %s", target.task.Name)
+	}
+	target.running = true
+	s.mu.Unlock()
+
+	go s.execute(target)
+	return nil
+}
+
+// loop 每隔一段时间检查到期的任务
+func (s *Scheduler) loop(stop, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(15 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			s.runDue(now)
+		}
+	}
+}
+
+// runDue 运行所有到期的任务，上一次仍在运行的任务跳过本次
+func (s *Scheduler) runDue(now time.Time) {
+	s.mu.Lock()
+	var due []*entry
+	for _, e := range s.entries {
+		if e.nextRun.IsZero() || now.Before(e.nextRun) {
+			continue
+		}
+		e.nextRun = e.schedule.Next(now)
+		if e.running {
+			logger.Warnf("定时任务 %s 上一次尚未结束，跳过本次运行", e.task.Name)
+			continue
+		}
+		e.running = true
+		due = append(due, e)
+	}
+	s.mu.Unlock()
+
+	for _, e := range due {
+		go s.execute(e)
+	}
+}
+
+// execute 执行任务并记录结果
+func (s *Scheduler) execute(e *entry) {
+	defer logger.RecoverPanic("定时任务 " + e.task.Name)
+
+	logger.Infof("开始执行定时任务: %s", e.task.Name)
+	start := time.Now()
+	err := e.task.Run()
+
+	s.mu.Lock()
+	e.running = false
+	e.lastRun = start
+	e.lastError = ""
+	if err != nil {
+		e.lastError = err.Error()
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		logger.Errorf("定时任务 %s 执行失败: %v", e.task.Name, err)
+	} else {
+		logger.Infof("定时任务 %s 执行完成，耗时 %v", e.task.Name, time.Since(start))
+	}
+}
@@ -0,0 +1,217 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/system"
+)
+
+const (
+	keychainService = "pdfSeer"
+
+	// 引用前缀：配置文件中只保存引用，不保存密钥本身
+	keychainPrefix = "keychain:"
+	filePrefix     = "file:"
+
+	secretsFileName = "secrets.enc"
+	keyFileName     = "secrets.key"
+)
+
+// Store 敏感配置（如API密钥）的保存位置
+// 优先保存在系统钥匙串（macOS 钥匙串、Windows 凭据管理器、Linux libsecret），不可用时保存在数据目录下的加密文件
+type Store struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// fileContents secrets.enc 的内容，每个值单独使用 AES-256-GCM 加密
+type fileContents struct {
+	Version int               `json:"version"`
+	Entries map[string]string `json:"entries"` // 名称 -> base64(nonce + 密文)
+}
+
+// NewStore 创建密钥存储，加密文件保存在 dir 下
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Set 保存密钥并返回写入配置文件的引用
+// 钥匙串不可用或写入失败时保存到加密文件
+func (s *Store) Set(name, value string) (string, error) {
+	if system.KeychainAvailable() {
+		err := system.KeychainSet(keychainService, name, value)
+		if err == nil {
+			// 之前保存在加密文件中的同名密钥不再需要
+			s.deleteFile(name)
+			return keychainPrefix + name, nil
+		}
+		logger.Warnf("保存密钥到系统钥匙串失败，改为保存到加密文件: %v", err)
+	}
+
+	if err := s.setFile(name, value); err != nil {
+		return "", err
+	}
+	return filePrefix + name, nil
+}
+
+// Get 按引用读取密钥
+func (s *Store) Get(ref string) (string, error) {
+	switch {
+	case strings.HasPrefix(ref, keychainPrefix):
+		return system.KeychainGet(keychainService, strings.TrimPrefix(ref, keychainPrefix))
+	case strings.HasPrefix(ref, filePrefix):
+		return s.getFile(strings.TrimPrefix(ref, filePrefix))
+	default:
+		return "", fmt.Errorf("无效的密钥引用: %s", ref)
+	}
+}
+
+// Delete 按引用删除密钥
+func (s *Store) Delete(ref string) error {
+	switch {
+	case strings.HasPrefix(ref, keychainPrefix):
+		return system.KeychainDelete(keychainService, strings.TrimPrefix(ref, keychainPrefix))
+	case strings.HasPrefix(ref, filePrefix):
+		return s.deleteFile(strings.TrimPrefix(ref, filePrefix))
+	default:
+		return nil
+	}
+}
+
+// setFile 将密钥加密保存到 secrets.enc
+func (s *Store) setFile(name, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	aead, err := s.fileCipher(true)
+	if err != nil {
+		return err
+	}
+	contents, err := s.readFile()
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("生成随机数失败: %w", err)
+	}
+	// 名称作为附加数据，防止密文在条目之间互换
+	sealed := aead.Seal(nonce, nonce, []byte(value), []byte(name))
+	contents.Entries[name] = base64.StdEncoding.EncodeToString(sealed)
+	return s.writeFile(contents)
+}
+
+// getFile 从 secrets.enc 读取并解密密钥
+func (s *Store) getFile(name string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	contents, err := s.readFile()
+	if err != nil {
+		return "", err
+	}
+	encoded, ok := contents.Entries[name]
+	if !ok {
+		return "", fmt.Errorf("加密文件中未找到密钥 %s", name)
+	}
+
+	aead, err := s.fileCipher(false)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("密钥 %s 已损坏", name)
+	}
+	nonceSize := aead.NonceSize()
+	value, err := aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], []byte(name))
+	if err != nil {
+		return "", fmt.Errorf("解密密钥 %s 失败: %w", name, err)
+	}
+	return string(value), nil
+}
+
+// deleteFile 从 secrets.enc 删除密钥
+func (s *Store) deleteFile(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	contents, err := s.readFile()
+	if err != nil {
+		return err
+	}
+	if _, ok := contents.Entries[name]; !ok {
+		return nil
+	}
+	delete(contents.Entries, name)
+	return s.writeFile(contents)
+}
+
+// fileCipher 读取（create 为 true 时按需生成）加密文件使用的随机密钥
+// 密钥文件仅当前用户可读，用于避免配置文件被分享、同步或打包诊断信息时泄露明文密钥
+func (s *Store) fileCipher(create bool) (cipher.AEAD, error) {
+	keyPath := filepath.Join(s.dir, keyFileName)
+	key, err := os.ReadFile(keyPath)
+	if os.IsNotExist(err) && create {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("生成密钥失败: %w", err)
+		}
+		if err := os.WriteFile(keyPath, key, 0600); err != nil {
+			return nil, fmt.Errorf("保存密钥文件失败: %w", err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("读取密钥文件失败: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("密钥文件已损坏: %s", keyPath)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// readFile 读取 secrets.enc，文件不存在时返回空内容
+func (s *Store) readFile() (*fileContents, error) {
+	contents := &fileContents{Version: 1, Entries: map[string]string{}}
+	data, err := os.ReadFile(filepath.Join(s.dir, secretsFileName))
+	if os.IsNotExist(err) {
+		return contents, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取加密文件失败: %w", err)
+	}
+	if err := json.Unmarshal(data, contents); err != nil {
+		return nil, fmt.Errorf("解析加密文件失败: %w", err)
+	}
+	if contents.Entries == nil {
+		contents.Entries = map[string]string{}
+	}
+	return contents, nil
+}
+
+// writeFile 保存 secrets.enc
+func (s *Store) writeFile(contents *fileContents) error {
+	data, err := json.MarshalIndent(contents, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化加密文件失败: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(s.dir, secretsFileName), data, 0600); err != nil {
+		return fmt.Errorf("保存加密文件失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package stitch
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"pdf-ocr-ai/pkg/config"
+)
+
+const (
+	// defaultEdgeLines 未配置时每页开头和结尾检查的行数
+	defaultEdgeLines = 3
+	// defaultMinRatio 未配置时判定为重复需要出现的页面比例
+	defaultMinRatio = 0.5
+	// minRepeatPages 判定为重复页眉页脚至少需要出现的页数
+	minRepeatPages = 3
+)
+
+// 删除内容的类型
+const (
+	KindHeader     = "header"
+	KindFooter     = "footer"
+	KindPageNumber = "page_number"
+)
+
+// Removal 清理时删除的一行
+type Removal struct {
+	Page int    `json:"page"` // 在输入中的序号（从1开始）
+	Line string `json:"line"`
+	Kind string `json:"kind"` // header、footer 或 page_number
+}
+
+// CleanResult 清理结果
+type CleanResult struct {
+	Pages   []string  `json:"pages"`   // 清理后的各页文本，与输入一一对应
+	Removed []Removal `json:"removed"` // 删除的行，按页排序
+}
+
+var (
+	digitsPattern = regexp.MustCompile(`\d+`)
+	spacePattern  = regexp.MustCompile(`\s+`)
+	// pageNumberPattern 单独一行的页码，如 "12"、"- 12 -"、"第 12 页"、"Page 12 of 30"、"12/30"
+	pageNumberPattern = regexp.MustCompile(`(?i)^[-–—\s]*(第\s*\d+\s*页(\s*[/，,]?\s*共\s*\d+\s*页)?|page\s*\d+(\s*(of|/)\s*\d+)?|\d+(\s*/\s*\d+)?)[-–—\s]*$`)
+)
+
+// Clean 删除多数页面开头（页眉）或结尾（页脚）重复出现的行，以及页面边缘单独的页码行
+// 比较时忽略数字、空白和大小写，"第 3 页 共 10 页" 这类随页变化的内容也能识别；未启用时原样返回
+func Clean(pages []string, cfg config.TextCleanupConfig) CleanResult {
+	result := CleanResult{Pages: append([]string(nil), pages...), Removed: []Removal{}}
+	if !cfg.Enabled || (!cfg.Headers && !cfg.Footers && !cfg.PageNumbers) {
+		return result
+	}
+
+	edgeLines := cfg.EdgeLines
+	if edgeLines <= 0 {
+		edgeLines = defaultEdgeLines
+	}
+	ratio := cfg.MinRatio
+	if ratio <= 0 || ratio > 1 {
+		ratio = defaultMinRatio
+	}
+
+	lines := make([][]string, len(pages))
+	nonEmpty := 0
+	for i, page := range pages {
+		page = strings.TrimSpace(strings.ReplaceAll(page, "\r\n", "\n"))
+		if page != "" {
+			lines[i] = strings.Split(page, "\n")
+			nonEmpty++
+		}
+	}
+
+	threshold := int(float64(nonEmpty)*ratio + 0.5)
+	if threshold < minRepeatPages {
+		threshold = minRepeatPages
+	}
+	// 页码行只在至少有两页时删除，避免误删单页文档中的数字
+	stripPageNumbers := cfg.PageNumbers && nonEmpty > 1
+
+	var removed [][]Removal
+	for _, top := range []bool{true, false} {
+		repeated := top && cfg.Headers || !top && cfg.Footers
+		kind := KindFooter
+		if top {
+			kind = KindHeader
+		}
+		removed = append(removed, removeEdges(lines, top, edgeLines, threshold, repeated, stripPageNumbers, kind))
+	}
+
+	for i, pageLines := range lines {
+		if pageLines != nil {
+			result.Pages[i] = strings.TrimSpace(strings.Join(pageLines, "\n"))
+		}
+	}
+	// 按页合并页眉和页脚的删除记录，同一页内页眉在前
+	result.Removed = append(append(result.Removed, removed[0]...), removed[1]...)
+	sort.SliceStable(result.Removed, func(i, j int) bool {
+		return result.Removed[i].Page < result.Removed[j].Page
+	})
+	return result
+}
+
+// removeEdges 删除页面开头（或结尾）重复出现的行和页码行，直接修改 pages
+func removeEdges(pages [][]string, top bool, edgeLines, threshold int, repeated, pageNumbers bool, kind string) []Removal {
+	counts := make(map[string]int)
+	if repeated {
+		for _, pageLines := range pages {
+			seen := make(map[string]bool)
+			for _, i := range edgeIndexes(pageLines, top, edgeLines) {
+				key := normalizeLine(pageLines[i])
+				if !seen[key] {
+					seen[key] = true
+					counts[key]++
+				}
+			}
+		}
+	}
+
+	var removed []Removal
+	for p, pageLines := range pages {
+		drop := make(map[int]bool)
+		for _, i := range edgeIndexes(pageLines, top, edgeLines) {
+			line := strings.TrimSpace(pageLines[i])
+			switch {
+			case repeated && counts[normalizeLine(line)] >= threshold:
+				removed = append(removed, Removal{Page: p + 1, Line: line, Kind: kind})
+			case pageNumbers && pageNumberPattern.MatchString(line):
+				removed = append(removed, Removal{Page: p + 1, Line: line, Kind: KindPageNumber})
+			default:
+				continue
+			}
+			drop[i] = true
+		}
+		if len(drop) == 0 {
+			continue
+		}
+		kept := make([]string, 0, len(pageLines))
+		for i, line := range pageLines {
+			if !drop[i] {
+				kept = append(kept, line)
+			}
+		}
+		pages[p] = kept
+	}
+	return removed
+}
+
+// normalizeLine 归一化行内容用于跨页比较：忽略数字（页码、日期）、空白和大小写
+func normalizeLine(line string) string {
+	line = strings.ToLower(strings.TrimSpace(line))
+	line = digitsPattern.ReplaceAllString(line, "#")
+	return spacePattern.ReplaceAllString(line, " ")
+}
+
+// edgeIndexes 页面开头（或结尾）最多 edgeLines 个非空行的下标
+func edgeIndexes(pageLines []string, top bool, edgeLines int) []int {
+	var indexes []int
+	for n := 0; n < len(pageLines) && len(indexes) < edgeLines; n++ {
+		i := n
+		if !top {
+			i = len(pageLines) - 1 - n
+		}
+		if strings.TrimSpace(pageLines[i]) != "" {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
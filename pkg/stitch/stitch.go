@@ -0,0 +1,120 @@
+package stitch
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"pdf-ocr-ai/pkg/config"
+)
+
+// Result 拼接结果
+type Result struct {
+	Text         string    `json:"text"`
+	Removed      []Removal `json:"removed"`       // 删除的页眉、页脚和页码行
+	MergedBreaks int       `json:"merged_breaks"` // 合并的跨页断句和断词数
+}
+
+// orderedListPattern 有序列表项
+var orderedListPattern = regexp.MustCompile(`^\d+[.)、]\s`)
+
+// stitchCleanup 拼接前始终删除页眉页脚和页码，使跨页内容能够衔接
+var stitchCleanup = config.TextCleanupConfig{
+	Enabled:     true,
+	Headers:     true,
+	Footers:     true,
+	PageNumbers: true,
+}
+
+// Pages 按阅读顺序拼接各页文本：删除重复的页眉页脚和页码，合并跨页断开的句子和单词
+// 空页面被跳过；无法合并的页面之间用空行分隔
+func Pages(pages []string) Result {
+	cleaned := Clean(pages, stitchCleanup)
+	result := Result{Removed: cleaned.Removed}
+
+	var parts []string
+	for _, page := range cleaned.Pages {
+		text := strings.TrimSpace(page)
+		if text == "" {
+			continue
+		}
+		if len(parts) > 0 {
+			prev := parts[len(parts)-1]
+			separator, trimHyphen := join(prev, text)
+			if trimHyphen {
+				parts[len(parts)-1] = prev[:len(prev)-1]
+			}
+			if separator != "\n\n" {
+				result.MergedBreaks++
+			}
+			parts = append(parts, separator)
+		}
+		parts = append(parts, text)
+	}
+	result.Text = strings.Join(parts, "")
+	return result
+}
+
+// join 决定上一页与下一页之间的分隔：跨页断开的单词或句子直接合并（返回空字符串或空格），
+// 否则按段落分隔；trimHyphen 表示需要去掉上一页末尾的断词连字符
+func join(prev, next string) (separator string, trimHyphen bool) {
+	lastLine := prev[strings.LastIndexByte(prev, '\n')+1:]
+	firstLine := next
+	if i := strings.IndexByte(next, '\n'); i >= 0 {
+		firstLine = next[:i]
+	}
+	if isBlockLine(lastLine) || isBlockLine(firstLine) {
+		return "\n\n", false
+	}
+
+	last, _ := utf8.DecodeLastRuneInString(prev)
+	first, _ := utf8.DecodeRuneInString(next)
+
+	// 英文连字符断词：exam- / ple
+	if last == '-' && utf8.RuneCountInString(prev) > 1 {
+		beforeHyphen, _ := utf8.DecodeLastRuneInString(prev[:len(prev)-1])
+		if unicode.IsLetter(beforeHyphen) && unicode.IsLower(first) {
+			return "", true
+		}
+	}
+
+	if endsSentence(last) {
+		return "\n\n", false
+	}
+
+	// 句子跨页：下一页以小写字母或中日韩文字开头
+	switch {
+	case isCJK(last) && (isCJK(first) || unicode.IsPunct(first)):
+		return "", false
+	case unicode.IsLower(first) || (isCJK(first) && unicode.IsLetter(last)):
+		return " ", false
+	case isCJK(last) && unicode.IsLetter(first):
+		return "", false
+	}
+	return "\n\n", false
+}
+
+// endsSentence 是否为句末标点（含引号和括号结尾）
+func endsSentence(r rune) bool {
+	return strings.ContainsRune(".!?。！？:：;；…\"”’」』)）]】", r)
+}
+
+// isBlockLine 是否为不应与相邻页合并的Markdown块（标题、表格、列表、代码块、引用）
+func isBlockLine(line string) bool {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return true
+	}
+	for _, prefix := range []string{"#", "|", "```", ">", "- ", "* ", "+ "} {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return orderedListPattern.MatchString(line)
+}
+
+// isCJK 是否为中日韩字符
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
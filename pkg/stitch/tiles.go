@@ -0,0 +1,76 @@
+package stitch
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// maxTileOverlapLines 合并图块时检查的重叠行数上限
+	maxTileOverlapLines = 8
+	// minPartialLineRunes 被切断的行至少需要的字数，过短时不按包含关系判断重复
+	minPartialLineRunes = 4
+)
+
+// MergeTiles 按顺序合并同一页各图块的识别结果，删除相邻图块重叠区域中重复识别的行
+func MergeTiles(texts []string) string {
+	var merged []string
+	for _, text := range texts {
+		lines := strings.Split(strings.TrimSpace(text), "\n")
+		if k := overlapLines(merged, lines); k > 0 {
+			// 重复的行保留较完整的一份
+			for i := 0; i < k; i++ {
+				prev := &merged[len(merged)-k+i]
+				if utf8.RuneCountInString(lines[i]) > utf8.RuneCountInString(*prev) {
+					*prev = lines[i]
+				}
+			}
+			lines = lines[k:]
+		}
+		merged = append(merged, lines...)
+	}
+	return strings.TrimSpace(strings.Join(merged, "\n"))
+}
+
+// overlapLines 获取 next 开头与 prev 结尾重复的行数
+func overlapLines(prev, next []string) int {
+	for k := min(maxTileOverlapLines, len(prev), len(next)); k > 0; k-- {
+		if linesMatch(prev[len(prev)-k:], next[:k]) {
+			return k
+		}
+	}
+	return 0
+}
+
+// linesMatch 判断两组行是否相同（忽略空白），至少需要一行非空
+// 图块边界处的行可能被切断只识别出一部分，首尾行与另一份互为包含时也视为相同
+func linesMatch(a, b []string) bool {
+	nonEmpty := false
+	for i := range a {
+		x, y := compactLine(a[i]), compactLine(b[i])
+		if x != "" {
+			nonEmpty = true
+		}
+		if x == y {
+			continue
+		}
+		edge := i == 0 || i == len(a)-1
+		if edge && min(utf8.RuneCountInString(x), utf8.RuneCountInString(y)) >= minPartialLineRunes &&
+			(strings.Contains(x, y) || strings.Contains(y, x)) {
+			continue
+		}
+		return false
+	}
+	return nonEmpty
+}
+
+// compactLine 删除行中的所有空白
+func compactLine(line string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, line)
+}
@@ -0,0 +1,175 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// FieldCipher 文本字段加密器（未设置时数据以明文保存）
+type FieldCipher interface {
+	Enabled() bool
+	Encrypt(value string) (string, error)
+	Decrypt(value string) (string, error)
+}
+
+// encryptedTable 需要加密的表和文本列
+type encryptedTable struct {
+	table   string
+	columns []string
+}
+
+// SetCipher 设置文本字段加密器
+func (s *Store) SetCipher(cipher FieldCipher) {
+	s.cipherMu.Lock()
+	s.cipher = cipher
+	s.cipherMu.Unlock()
+}
+
+// fieldCipher 获取当前的加密器
+func (s *Store) fieldCipher() FieldCipher {
+	s.cipherMu.RLock()
+	defer s.cipherMu.RUnlock()
+	return s.cipher
+}
+
+// EncryptionEnabled 是否已启用字段加密
+func (s *Store) EncryptionEnabled() bool {
+	cipher := s.fieldCipher()
+	return cipher != nil && cipher.Enabled()
+}
+
+// EncryptField 加密文本字段，未启用加密时原样返回
+func (s *Store) EncryptField(value string) (string, error) {
+	cipher := s.fieldCipher()
+	if cipher == nil {
+		return value, nil
+	}
+	return cipher.Encrypt(value)
+}
+
+// DecryptField 解密文本字段，明文原样返回
+func (s *Store) DecryptField(value string) (string, error) {
+	cipher := s.fieldCipher()
+	if cipher == nil {
+		return value, nil
+	}
+	return cipher.Decrypt(value)
+}
+
+// EncryptFields 原地加密多个字段
+func (s *Store) EncryptFields(values ...*string) error {
+	for _, value := range values {
+		encrypted, err := s.EncryptField(*value)
+		if err != nil {
+			return err
+		}
+		*value = encrypted
+	}
+	return nil
+}
+
+// DecryptFields 原地解密多个字段
+func (s *Store) DecryptFields(values ...*string) error {
+	for _, value := range values {
+		decrypted, err := s.DecryptField(*value)
+		if err != nil {
+			return err
+		}
+		*value = decrypted
+	}
+	return nil
+}
+
+// RegisterEncryptedColumns 登记组件中保存文本内容的列，启用或关闭加密时统一转换
+func (s *Store) RegisterEncryptedColumns(table string, columns ...string) {
+	s.cipherMu.Lock()
+	s.encrypted = append(s.encrypted, encryptedTable{table: table, columns: columns})
+	s.cipherMu.Unlock()
+}
+
+// RewriteEncryptedColumns 将已登记列中的现有数据全部加密（encrypt为true）或解密，返回转换的行数
+func (s *Store) RewriteEncryptedColumns(encrypt bool) (int, error) {
+	s.cipherMu.RLock()
+	tables := append([]encryptedTable(nil), s.encrypted...)
+	s.cipherMu.RUnlock()
+
+	// 队列中的写入已按转换前的状态加密或未加密，先提交再统一转换
+	if err := s.Flush(); err != nil {
+		return 0, fmt.Errorf("提交延迟写入失败: %w", err)
+	}
+
+	convert := s.DecryptField
+	if encrypt {
+		convert = s.EncryptField
+	}
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rewritten := 0
+	for _, t := range tables {
+		rows, err := tx.Queryx(fmt.Sprintf("SELECT rowid, %s FROM %s", strings.Join(t.columns, ", "), t.table))
+		if err != nil {
+			return 0, fmt.Errorf("读取 %s 失败: %w", t.table, err)
+		}
+
+		type rowValues struct {
+			rowid  int64
+			values []interface{}
+		}
+		var updates []rowValues
+		for rows.Next() {
+			var rowid int64
+			values := make([]sql.NullString, len(t.columns))
+			dest := []interface{}{&rowid}
+			for i := range values {
+				dest = append(dest, &values[i])
+			}
+			if err := rows.Scan(dest...); err != nil {
+				rows.Close()
+				return 0, fmt.Errorf("读取 %s 失败: %w", t.table, err)
+			}
+
+			changed := false
+			converted := make([]interface{}, len(values))
+			for i, value := range values {
+				if !value.Valid {
+					converted[i] = nil
+					continue
+				}
+				result, err := convert(value.String)
+				if err != nil {
+					rows.Close()
+					return 0, err
+				}
+				converted[i] = result
+				changed = changed || result != value.String
+			}
+			if changed {
+				updates = append(updates, rowValues{rowid: rowid, values: converted})
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return 0, fmt.Errorf("读取 %s 失败: %w", t.table, err)
+		}
+
+		assignments := make([]string, len(t.columns))
+		for i, column := range t.columns {
+			assignments[i] = column + " = ?"
+		}
+		update := fmt.Sprintf("UPDATE %s SET %s WHERE rowid = ?", t.table, strings.Join(assignments, ", "))
+		for _, row := range updates {
+			if _, err := tx.Exec(update, append(row.values, row.rowid)...); err != nil {
+				return 0, fmt.Errorf("更新 %s 失败: %w", t.table, err)
+			}
+		}
+		rewritten += len(updates)
+	}
+
+	return rewritten, tx.Commit()
+}
@@ -0,0 +1,206 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/migrate"
+)
+
+// DBFileName 统一数据库文件名
+const DBFileName = "pdfseer.db"
+
+// Store 统一的本地数据库 ~/.pdfSeer/pdfseer.db
+// 缓存（最新结果）和历史记录（每次运行）共用一个数据库和文档标识，各组件的表结构独立迁移
+type Store struct {
+	db      *sqlx.DB
+	dataDir string
+	path    string
+	writer  *BatchWriter // 页面结果的延迟写入队列
+
+	cipherMu  sync.RWMutex
+	cipher    FieldCipher      // 文本字段加密器
+	encrypted []encryptedTable // 各组件登记的加密列
+}
+
+// Open 打开统一数据库
+func Open() (*Store, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("获取用户目录失败: %w", err)
+	}
+
+	dataDir := filepath.Join(homeDir, ".pdfSeer")
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("创建数据目录失败: %w", err)
+	}
+
+	path := filepath.Join(dataDir, DBFileName)
+	db, err := sqlx.Connect("sqlite3", path+"?cache=shared&_journal_mode=WAL&_busy_timeout=5000")
+	if err != nil {
+		return nil, fmt.Errorf("连接数据库失败: %w", err)
+	}
+
+	return &Store{db: db, dataDir: dataDir, path: path, writer: newBatchWriter(db)}, nil
+}
+
+// DB 获取数据库连接
+func (s *Store) DB() *sqlx.DB {
+	return s.db
+}
+
+// Writer 获取延迟写入队列
+func (s *Store) Writer() *BatchWriter {
+	return s.writer
+}
+
+// Flush 提交延迟写入队列中的全部写入
+func (s *Store) Flush() error {
+	return s.writer.Flush()
+}
+
+// Sync 读取前提交延迟写入，并等待正在进行的提交完成（单条写入的错误已在各自的回调中处理，这里只记录日志）
+func (s *Store) Sync() {
+	if err := s.writer.Flush(); err != nil {
+		logger.Warnf("提交延迟写入失败: %v", err)
+	}
+}
+
+// SyncErr 提交延迟写入队列，返回此前在后台提交时失败的写入
+func (s *Store) SyncErr() error {
+	s.writer.Flush()
+	if failed, err := s.writer.TakeFailures(); failed > 0 {
+		return fmt.Errorf("%d 条写入未能保存: %w", failed, err)
+	}
+	return nil
+}
+
+// Path 获取数据库文件路径
+func (s *Store) Path() string {
+	return s.path
+}
+
+// DataDir 获取数据目录 ~/.pdfSeer
+func (s *Store) DataDir() string {
+	return s.dataDir
+}
+
+// Migrate 将组件的表结构迁移到最新版本
+func (s *Store) Migrate(component string, migrations []migrate.Migration) error {
+	return migrate.Run(s.db, s.path, component, migrations)
+}
+
+// ImportLegacy 将旧版独立数据库（cache.db、history.db）中组件的表导入统一数据库
+// 旧库先按组件迁移到最新结构再逐表复制（已存在的行保留），导入成功后重命名为 <文件名>.imported，只执行一次
+func (s *Store) ImportLegacy(fileName, component string, migrations []migrate.Migration, tables []string) (bool, error) {
+	legacyPath := filepath.Join(s.dataDir, fileName)
+	if _, err := os.Stat(legacyPath); os.IsNotExist(err) {
+		return false, nil
+	}
+
+	legacy, err := sqlx.Connect("sqlite3", legacyPath)
+	if err != nil {
+		return false, fmt.Errorf("打开旧版数据库失败: %w", err)
+	}
+	err = migrate.Run(legacy, legacyPath, component, migrations)
+	legacy.Close()
+	if err != nil {
+		return false, fmt.Errorf("迁移旧版数据库失败: %w", err)
+	}
+
+	if err := s.copyTables(legacyPath, tables); err != nil {
+		return false, err
+	}
+
+	if err := os.Rename(legacyPath, legacyPath+".imported"); err != nil {
+		return true, fmt.Errorf("重命名旧版数据库失败: %w", err)
+	}
+	for _, suffix := range []string{"-wal", "-shm"} {
+		os.Remove(legacyPath + suffix)
+	}
+
+	logger.Infof("已将旧版数据库 %s 导入 %s", fileName, DBFileName)
+	return true, nil
+}
+
+// copyTables 从附加的旧版数据库复制表数据（ATTACH只对单个连接有效，需使用独立连接）
+func (s *Store) copyTables(legacyPath string, tables []string) error {
+	ctx := context.Background()
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "ATTACH DATABASE ? AS legacy", legacyPath); err != nil {
+		return fmt.Errorf("附加旧版数据库失败: %w", err)
+	}
+	defer conn.ExecContext(ctx, "DETACH DATABASE legacy")
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, table := range tables {
+		// 只复制两边都有的列，列顺序可能因 ALTER TABLE 而不同
+		var columns []string
+		err := tx.Select(&columns, `
+		SELECT l.name FROM pragma_table_info(?, 'legacy') l
+		JOIN pragma_table_info(?, 'main') m ON m.name = l.name
+		ORDER BY l.cid
+		`, table, table)
+		if err != nil {
+			return fmt.Errorf("读取表 %s 结构失败: %w", table, err)
+		}
+		if len(columns) == 0 {
+			continue
+		}
+
+		list := strings.Join(columns, ", ")
+		query := fmt.Sprintf("INSERT OR IGNORE INTO main.%s (%s) SELECT %s FROM legacy.%s", table, list, list, table)
+		if _, err := tx.Exec(query); err != nil {
+			return fmt.Errorf("导入表 %s 失败: %w", table, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Vacuum 压缩数据库文件，回收已删除数据占用的磁盘空间
+func (s *Store) Vacuum() error {
+	s.Sync()
+	if _, err := s.db.Exec("VACUUM"); err != nil {
+		return fmt.Errorf("压缩数据库失败: %w", err)
+	}
+	_, err := s.db.Exec("PRAGMA wal_checkpoint(TRUNCATE)")
+	return err
+}
+
+// FilesSize 数据库文件（含WAL）的总大小
+func (s *Store) FilesSize() int64 {
+	var total int64
+	for _, name := range []string{s.path, s.path + "-wal"} {
+		if info, err := os.Stat(name); err == nil {
+			total += info.Size()
+		}
+	}
+	return total
+}
+
+// Close 提交剩余的延迟写入并关闭数据库
+func (s *Store) Close() error {
+	if err := s.writer.Close(); err != nil {
+		logger.Errorf("提交延迟写入失败: %v", err)
+	}
+	return s.db.Close()
+}
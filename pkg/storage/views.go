@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"fmt"
+
+	"pdf-ocr-ai/pkg/migrate"
+)
+
+// LatestPage 文档每页的最新结果（来自缓存）
+type LatestPage struct {
+	DocumentID string `db:"document_id" json:"document_id"`
+	FilePath   string `db:"file_path" json:"file_path"`
+	PageNumber int    `db:"page_number" json:"page_number"`
+	OCRText    string `db:"ocr_text" json:"ocr_text"`
+	AIText     string `db:"ai_text" json:"ai_text"`
+	UpdatedAt  string `db:"updated_at" json:"updated_at"`
+}
+
+// RunPage 某次运行中一页的结果（来自历史记录）
+type RunPage struct {
+	HistoryID       int    `db:"history_id" json:"history_id"`
+	DocumentID      string `db:"document_id" json:"document_id"`
+	DocumentPath    string `db:"document_path" json:"document_path"`
+	RunStatus       string `db:"run_status" json:"run_status"`
+	TaskType        string `db:"task_type" json:"task_type"`
+	Model           string `db:"model" json:"model"`
+	ProcessedAt     string `db:"processed_at" json:"processed_at"`
+	PageNumber      int    `db:"page_number" json:"page_number"`
+	PageStatus      string `db:"page_status" json:"page_status"`
+	OCRText         string `db:"ocr_text" json:"ocr_text"`
+	AIProcessedText string `db:"ai_processed_text" json:"ai_processed_text"`
+}
+
+// ViewMigrations 跨组件视图，需在缓存和历史记录组件迁移之后执行
+func ViewMigrations() []migrate.Migration {
+	return []migrate.Migration{
+		{Version: 1, Description: "创建最新结果和运行历史视图", Up: migrate.SQL(
+			`CREATE VIEW IF NOT EXISTS latest_pages AS
+			SELECT
+				d.id AS document_id,
+				d.file_path,
+				p.page_number,
+				COALESCE(p.ocr_text, '') AS ocr_text,
+				COALESCE(p.ai_text, '') AS ai_text,
+				COALESCE(p.updated_at, p.created_at, '') AS updated_at
+			FROM documents d
+			JOIN pages p ON p.document_id = d.id`,
+			`CREATE VIEW IF NOT EXISTS run_history AS
+			SELECT
+				ph.id AS history_id,
+				COALESCE(ph.document_id, '') AS document_id,
+				ph.document_path,
+				ph.status AS run_status,
+				ph.task_type,
+				ph.model,
+				ph.processed_at,
+				hp.page_number,
+				hp.status AS page_status,
+				COALESCE(hp.ocr_text, '') AS ocr_text,
+				COALESCE(hp.ai_processed_text, '') AS ai_processed_text
+			FROM processing_history ph
+			JOIN history_pages hp ON hp.history_id = ph.id`,
+		)},
+		{Version: 2, Description: "最新结果排除过期缓存", Up: migrate.SQL(
+			`DROP VIEW IF EXISTS latest_pages`,
+			`CREATE VIEW latest_pages AS
+			SELECT
+				d.id AS document_id,
+				d.file_path,
+				p.page_number,
+				COALESCE(p.ocr_text, '') AS ocr_text,
+				COALESCE(p.ai_text, '') AS ai_text,
+				COALESCE(p.updated_at, p.created_at, '') AS updated_at
+			FROM documents d
+			JOIN pages p ON p.document_id = d.id
+			WHERE p.stale = 0`,
+		)},
+	}
+}
+
+// LatestPages 获取文档每页的最新结果
+func (s *Store) LatestPages(documentID string) ([]LatestPage, error) {
+	s.Sync()
+	pages := []LatestPage{}
+	err := s.db.Select(&pages, `
+	SELECT * FROM latest_pages WHERE document_id = ? ORDER BY page_number
+	`, documentID)
+	if err != nil {
+		return nil, fmt.Errorf("查询最新结果失败: %w", err)
+	}
+	for i := range pages {
+		if err := s.DecryptFields(&pages[i].OCRText, &pages[i].AIText); err != nil {
+			return nil, err
+		}
+	}
+	return pages, nil
+}
+
+// RunHistory 获取文档所有运行的逐页结果，按运行时间倒序
+// 按文档标识匹配；旧版记录没有文档标识时按路径匹配，documentID 不能为空（否则会匹配所有旧版记录）
+func (s *Store) RunHistory(documentID, documentPath string) ([]RunPage, error) {
+	if documentID == "" {
+		return nil, fmt.Errorf("查询运行历史失败: 文档标识为空")
+	}
+	s.Sync()
+	pages := []RunPage{}
+	err := s.db.Select(&pages, `
+	SELECT * FROM run_history
+	WHERE document_id = ? OR (document_id = '' AND document_path = ?)
+	ORDER BY processed_at DESC, history_id DESC, page_number
+	`, documentID, documentPath)
+	if err != nil {
+		return nil, fmt.Errorf("查询运行历史失败: %w", err)
+	}
+	for i := range pages {
+		if err := s.DecryptFields(&pages[i].OCRText, &pages[i].AIProcessedText); err != nil {
+			return nil, err
+		}
+	}
+	return pages, nil
+}
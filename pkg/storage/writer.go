@@ -0,0 +1,217 @@
+package storage
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"pdf-ocr-ai/pkg/logger"
+)
+
+const (
+	// defaultFlushInterval 写入队列的最长等待时间
+	defaultFlushInterval = 250 * time.Millisecond
+	// defaultFlushSize 队列中的写入数达到该值时立即提交
+	defaultFlushSize = 100
+)
+
+// WriteFunc 在批量事务中执行的一次写入
+type WriteFunc func(tx *sqlx.Tx) error
+
+// pendingWrite 等待提交的写入
+type pendingWrite struct {
+	fn   WriteFunc
+	done func(err error) // 提交后回调（可为空），err 为该次写入或事务提交的错误
+}
+
+// BatchWriter 延迟写入队列
+// 页面完成时的缓存和历史记录写入先进入队列，按时间间隔、队列长度或关闭时合并到一个事务中提交，避免并发处理时逐条同步写入互相等待
+type BatchWriter struct {
+	db       *sqlx.DB
+	interval time.Duration
+	size     int
+
+	mu      sync.Mutex
+	pending []pendingWrite
+	closed  bool
+	failed  int   // 上次 TakeFailures 之后失败的写入数
+	lastErr error // 最近一次失败的原因
+
+	flushMu sync.Mutex // 保证同一时间只有一个事务在提交，且按入队顺序提交
+
+	wake chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+// newBatchWriter 创建写入队列并启动后台提交
+func newBatchWriter(db *sqlx.DB) *BatchWriter {
+	w := &BatchWriter{
+		db:       db,
+		interval: defaultFlushInterval,
+		size:     defaultFlushSize,
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Enqueue 加入一次写入，done 在提交后调用；队列已关闭时直接同步执行
+func (w *BatchWriter) Enqueue(fn WriteFunc, done func(err error)) {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		w.commit([]pendingWrite{{fn: fn, done: done}})
+		return
+	}
+	w.pending = append(w.pending, pendingWrite{fn: fn, done: done})
+	full := len(w.pending) >= w.size
+	w.mu.Unlock()
+
+	if full {
+		select {
+		case w.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Pending 等待提交的写入数
+func (w *BatchWriter) Pending() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.pending)
+}
+
+// Flush 立即提交队列中的全部写入，读取刚写入的数据前调用
+func (w *BatchWriter) Flush() error {
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+
+	w.mu.Lock()
+	batch := w.pending
+	w.pending = nil
+	w.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+	return w.commit(batch)
+}
+
+// TakeFailures 返回上次调用之后失败的写入数和最近一次失败的原因，并清零
+// 写入在后台提交，入队时无法得知结果，处理结束后通过该方法汇报
+func (w *BatchWriter) TakeFailures() (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	failed, err := w.failed, w.lastErr
+	w.failed, w.lastErr = 0, nil
+	return failed, err
+}
+
+// Close 停止后台提交并提交剩余写入，之后的写入同步执行
+func (w *BatchWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	close(w.stop)
+	<-w.done
+	return w.Flush()
+}
+
+// run 后台按时间间隔或队列长度提交
+func (w *BatchWriter) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		case <-w.wake:
+		}
+		if err := w.Flush(); err != nil {
+			logger.Errorf("批量写入失败: %v", err)
+		}
+	}
+}
+
+// commit 在一个事务中执行一批写入
+// 每次写入使用独立的保存点，单条失败只回滚该条，不影响同批的其他写入
+func (w *BatchWriter) commit(batch []pendingWrite) error {
+	errs := make([]error, len(batch))
+	err := w.commitTx(batch, errs)
+
+	failed := 0
+	for i, item := range batch {
+		itemErr := errs[i]
+		if itemErr == nil {
+			itemErr = err
+		}
+		if itemErr != nil {
+			failed++
+		}
+		if item.done != nil {
+			item.done(itemErr)
+		}
+	}
+
+	if failed > 0 {
+		w.mu.Lock()
+		w.failed += failed
+		for i := range errs {
+			if errs[i] != nil {
+				w.lastErr = errs[i]
+			}
+		}
+		if err != nil {
+			w.lastErr = err
+		}
+		w.mu.Unlock()
+	}
+
+	if err != nil {
+		return fmt.Errorf("提交 %d 条写入失败: %w", len(batch), err)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d 条写入中有 %d 条失败", len(batch), failed)
+	}
+	return nil
+}
+
+// commitTx 执行事务，单条写入的错误记录在 errs 中
+func (w *BatchWriter) commitTx(batch []pendingWrite, errs []error) error {
+	tx, err := w.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i, item := range batch {
+		if _, err := tx.Exec("SAVEPOINT batch_write"); err != nil {
+			return err
+		}
+		if errs[i] = item.fn(tx); errs[i] != nil {
+			if _, err := tx.Exec("ROLLBACK TO batch_write"); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.Exec("RELEASE batch_write"); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
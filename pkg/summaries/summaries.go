@@ -0,0 +1,89 @@
+package summaries
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"pdf-ocr-ai/pkg/migrate"
+	"pdf-ocr-ai/pkg/storage"
+)
+
+// migrationComponent 表结构迁移使用的组件名
+const migrationComponent = "summaries"
+
+// Manager 章节摘要缓存，按章节起始页和内容哈希保存，章节文本变化后重新生成
+type Manager struct {
+	db    *sqlx.DB
+	store *storage.Store // 摘要内容加解密
+}
+
+// NewManager 创建章节摘要管理器，使用统一数据库中的 chapter_summaries 表
+func NewManager(store *storage.Store) (*Manager, error) {
+	m := &Manager{db: store.DB(), store: store}
+	if err := store.Migrate(migrationComponent, migrations()); err != nil {
+		return nil, err
+	}
+	store.RegisterEncryptedColumns("chapter_summaries", "summary")
+	return m, nil
+}
+
+// migrations 表结构迁移
+func migrations() []migrate.Migration {
+	return []migrate.Migration{
+		{
+			Version:     1,
+			Description: "创建章节摘要表",
+			Up: migrate.SQL(`
+			CREATE TABLE IF NOT EXISTS chapter_summaries (
+				document_id TEXT NOT NULL,
+				start_page INTEGER NOT NULL,
+				content_hash TEXT NOT NULL,
+				summary TEXT NOT NULL DEFAULT '',
+				updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (document_id, start_page)
+			)`),
+		},
+	}
+}
+
+// Get 获取章节摘要，没有摘要或章节内容已变化时返回空
+func (m *Manager) Get(documentID string, startPage int, contentHash string) (string, error) {
+	var summary string
+	err := m.db.Get(&summary, `SELECT summary FROM chapter_summaries WHERE document_id = ? AND start_page = ? AND content_hash = ?`,
+		documentID, startPage, contentHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("查询章节摘要失败: %w", err)
+	}
+	if err := m.store.DecryptFields(&summary); err != nil {
+		return "", err
+	}
+	return summary, nil
+}
+
+// Set 保存章节摘要，替换该章节之前的摘要
+func (m *Manager) Set(documentID string, startPage int, contentHash, summary string) error {
+	if err := m.store.EncryptFields(&summary); err != nil {
+		return err
+	}
+	_, err := m.db.Exec(`INSERT OR REPLACE INTO chapter_summaries (document_id, start_page, content_hash, summary, updated_at) VALUES (?, ?, ?, ?, ?)`,
+		documentID, startPage, contentHash, summary, time.Now())
+	if err != nil {
+		return fmt.Errorf("保存章节摘要失败: %w", err)
+	}
+	return nil
+}
+
+// DeleteDocument 删除文档的所有章节摘要
+func (m *Manager) DeleteDocument(documentID string) error {
+	if _, err := m.db.Exec("DELETE FROM chapter_summaries WHERE document_id = ?", documentID); err != nil {
+		return fmt.Errorf("删除章节摘要失败: %w", err)
+	}
+	return nil
+}
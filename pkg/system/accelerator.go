@@ -0,0 +1,148 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// 硬件加速类型
+const (
+	AcceleratorCUDA  = "cuda"  // NVIDIA GPU
+	AcceleratorMetal = "metal" // Apple GPU
+	AcceleratorCPU   = "cpu"   // 无可用加速，使用CPU
+)
+
+// Accelerator 硬件加速检测结果，供本地OCR引擎选择加速路径
+type Accelerator struct {
+	Type      string `json:"type"`
+	Available bool   `json:"available"`
+	Device    string `json:"device,omitempty"`  // 设备名称
+	Version   string `json:"version,omitempty"` // 驱动版本
+	Error     string `json:"error,omitempty"`
+}
+
+var (
+	acceleratorsOnce sync.Once
+	accelerators     []*Accelerator
+)
+
+// DetectAccelerators 检测可用的硬件加速，结果在进程内缓存（硬件和驱动在运行期间不会变化）
+func DetectAccelerators() []*Accelerator {
+	acceleratorsOnce.Do(func() {
+		accelerators = []*Accelerator{detectCUDA()}
+		if runtime.GOOS == "darwin" {
+			accelerators = append(accelerators, detectMetal())
+		}
+	})
+	return accelerators
+}
+
+// PreferredAccelerator 本地OCR引擎应使用的加速类型，按 CUDA、Metal 的顺序选择，都不可用时返回 AcceleratorCPU
+func PreferredAccelerator() string {
+	for _, preferred := range []string{AcceleratorCUDA, AcceleratorMetal} {
+		for _, acc := range DetectAccelerators() {
+			if acc.Type == preferred && acc.Available {
+				return acc.Type
+			}
+		}
+	}
+	return AcceleratorCPU
+}
+
+// detectCUDA 通过 nvidia-smi 和CUDA驱动库检测NVIDIA GPU（macOS不支持CUDA）
+func detectCUDA() *Accelerator {
+	acc := &Accelerator{Type: AcceleratorCUDA}
+	if runtime.GOOS == "darwin" {
+		acc.Error = "macOS不支持CUDA"
+		return acc
+	}
+
+	if !hasCUDADriver() {
+		acc.Error = "未找到CUDA驱动库"
+		return acc
+	}
+
+	smi, err := FindExecutable("nvidia-smi")
+	if err != nil {
+		// 驱动库存在但没有 nvidia-smi 时仍认为可用，只是无法获取设备信息
+		acc.Available = true
+		return acc
+	}
+
+	output, err := execCommandHidden(smi, "--query-gpu=name,driver_version", "--format=csv,noheader").Output()
+	if err != nil {
+		acc.Error = "nvidia-smi 执行失败，GPU驱动可能未正确安装"
+		return acc
+	}
+
+	line := strings.TrimSpace(strings.Split(string(output), "\n")[0])
+	if line == "" {
+		acc.Error = "未检测到NVIDIA GPU"
+		return acc
+	}
+
+	acc.Available = true
+	parts := strings.SplitN(line, ",", 2)
+	acc.Device = strings.TrimSpace(parts[0])
+	if len(parts) == 2 {
+		acc.Version = strings.TrimSpace(parts[1])
+	}
+	return acc
+}
+
+// hasCUDADriver 检查CUDA驱动库是否存在
+func hasCUDADriver() bool {
+	var libPaths []string
+	switch runtime.GOOS {
+	case "linux":
+		libPaths = []string{
+			"/usr/lib/x86_64-linux-gnu/libcuda.so.1",
+			"/usr/lib/aarch64-linux-gnu/libcuda.so.1",
+			"/usr/lib64/libcuda.so.1",
+			"/usr/lib/wsl/lib/libcuda.so.1",
+		}
+	case "windows":
+		libPaths = []string{filepath.Join(os.Getenv("SystemRoot"), "System32", "nvcuda.dll")}
+	}
+
+	for _, path := range libPaths {
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// detectMetal 检测macOS上的Metal支持，Apple Silicon始终支持
+func detectMetal() *Accelerator {
+	acc := &Accelerator{Type: AcceleratorMetal}
+	if runtime.GOARCH == "arm64" {
+		acc.Available = true
+		acc.Device = "Apple Silicon"
+		return acc
+	}
+
+	output, err := execCommandHidden("system_profiler", "SPDisplaysDataType").Output()
+	if err != nil {
+		acc.Error = "无法获取显卡信息"
+		return acc
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "Chipset Model:") && acc.Device == "" {
+			acc.Device = strings.TrimSpace(strings.TrimPrefix(line, "Chipset Model:"))
+		}
+		if strings.HasPrefix(line, "Metal") && strings.Contains(line, ":") {
+			acc.Available = true
+			acc.Version = strings.TrimSpace(line[strings.Index(line, ":")+1:])
+		}
+	}
+	if !acc.Available {
+		acc.Error = "显卡不支持Metal"
+	}
+	return acc
+}
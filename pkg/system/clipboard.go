@@ -0,0 +1,85 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// ReadClipboardImage 读取剪贴板中的图片，返回PNG数据
+// macOS 使用 osascript，Linux 使用 wl-paste 或 xclip，Windows 使用 PowerShell
+func ReadClipboardImage() ([]byte, error) {
+	tempDir, err := os.MkdirTemp("", "clipboard-ocr-*")
+	if err != nil {
+		return nil, fmt.Errorf("创建临时目录失败: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	pngPath := filepath.Join(tempDir, "clipboard.png")
+
+	switch runtime.GOOS {
+	case "darwin":
+		script := []string{
+			"-e", fmt.Sprintf(`set f to open for access POSIX file "%s" with write permission`, escapeAppleScript(pngPath)),
+			"-e", "try",
+			"-e", "write (the clipboard as «class PNGf») to f",
+			"-e", "on error",
+			"-e", "close access f",
+			"-e", "error \"no image\"",
+			"-e", "end try",
+			"-e", "close access f",
+		}
+		if err := execCommandHidden("osascript", script...).Run(); err != nil {
+			return nil, fmt.Errorf("剪贴板中没有图片")
+		}
+
+	case "linux":
+		return readLinuxClipboardImage()
+
+	case "windows":
+		script := fmt.Sprintf(`Add-Type -AssemblyName System.Windows.Forms
+Add-Type -AssemblyName System.Drawing
+$img = [System.Windows.Forms.Clipboard]::GetImage()
+if ($img -eq $null) { exit 2 }
+$img.Save('%s', [System.Drawing.Imaging.ImageFormat]::Png)`, escapePowerShell(pngPath))
+		if err := execCommandHidden("powershell", "-NoProfile", "-NonInteractive", "-STA", "-Command", script).Run(); err != nil {
+			return nil, fmt.Errorf("剪贴板中没有图片")
+		}
+
+	default:
+		return nil, fmt.Errorf("当前系统不支持读取剪贴板图片: %s", runtime.GOOS)
+	}
+
+	data, err := os.ReadFile(pngPath)
+	if err != nil || len(data) == 0 {
+		return nil, fmt.Errorf("剪贴板中没有图片")
+	}
+	return data, nil
+}
+
+// readLinuxClipboardImage 在Wayland下使用wl-paste，X11下使用xclip
+func readLinuxClipboardImage() ([]byte, error) {
+	var name string
+	var args []string
+
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		if path, err := FindExecutable("wl-paste"); err == nil {
+			name, args = path, []string{"--no-newline", "--type", "image/png"}
+		}
+	}
+	if name == "" {
+		path, err := FindExecutable("xclip")
+		if err != nil {
+			return nil, fmt.Errorf("读取剪贴板图片需要安装 xclip 或 wl-clipboard")
+		}
+		name, args = path, []string{"-selection", "clipboard", "-t", "image/png", "-o"}
+	}
+
+	output, err := execCommandHidden(name, args...).Output()
+	if err != nil || len(output) == 0 || strings.HasPrefix(string(output), "Error") {
+		return nil, fmt.Errorf("剪贴板中没有图片")
+	}
+	return output, nil
+}
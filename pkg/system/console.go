@@ -0,0 +1,8 @@
+//go:build !windows
+
+package system
+
+import "os/exec"
+
+// hideConsoleWindow 只有Windows会为命令行程序弹出控制台窗口，其他平台无需处理
+func hideConsoleWindow(cmd *exec.Cmd) {}
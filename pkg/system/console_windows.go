@@ -0,0 +1,20 @@
+//go:build windows
+
+package system
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// createNoWindow CREATE_NO_WINDOW 进程创建标志，子进程不分配控制台
+const createNoWindow = 0x08000000
+
+// hideConsoleWindow 隐藏命令的控制台窗口，避免依赖检查、剪贴板和通知等调用时闪出cmd窗口
+func hideConsoleWindow(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.HideWindow = true
+	cmd.SysProcAttr.CreationFlags |= createNoWindow
+}
@@ -25,6 +25,7 @@ type SystemInfo struct {
 	OS           string              `json:"os"`
 	Arch         string              `json:"arch"`
 	Dependencies []*DependencyStatus `json:"dependencies"`
+	Accelerators []*Accelerator      `json:"accelerators"` // 本地OCR引擎可用的硬件加速
 }
 
 // CheckDependencies 检查系统依赖
@@ -33,6 +34,7 @@ func CheckDependencies() *SystemInfo {
 		OS:           runtime.GOOS,
 		Arch:         runtime.GOARCH,
 		Dependencies: make([]*DependencyStatus, 0),
+		Accelerators: DetectAccelerators(),
 	}
 
 	// 检查libvips
@@ -40,6 +42,13 @@ func CheckDependencies() *SystemInfo {
 	info.Dependencies = append(info.Dependencies, vipsStatus)
 
 	// 检查其他可选依赖
+	djvuStatus := checkDjVuLibre()
+	info.Dependencies = append(info.Dependencies, djvuStatus)
+
+	if runtime.GOOS != "windows" {
+		info.Dependencies = append(info.Dependencies, checkSANE())
+	}
+
 	if runtime.GOOS == "darwin" {
 		brewStatus := checkBrew()
 		info.Dependencies = append(info.Dependencies, brewStatus)
@@ -100,18 +109,73 @@ func checkLibVips() *DependencyStatus {
 	return status
 }
 
-// execCommandHidden 执行命令并隐藏控制台窗口（Windows专用）
-func execCommandHidden(name string, args ...string) *exec.Cmd {
-	cmd := exec.Command(name, args...)
+// checkDjVuLibre 检查DjVuLibre命令行工具（可选，用于DjVu文档渲染）
+func checkDjVuLibre() *DependencyStatus {
+	status := &DependencyStatus{
+		Name:        "djvulibre",
+		Required:    false,
+		Description: "DjVu文档工具（ddjvu/djvused），用于DjVu页面渲染",
+		Installed:   false,
+	}
 
-	// 在Windows下隐藏控制台窗口
-	if runtime.GOOS == "windows" {
-		hideConsoleWindow(cmd)
+	for _, tool := range []string{"ddjvu", "djvused"} {
+		if _, err := FindExecutable(tool); err != nil {
+			status.Error = fmt.Sprintf("未找到 %s 命令", tool)
+			return status
+		}
+	}
+
+	status.Installed = true
+	ddjvuPath, _ := FindExecutable("ddjvu")
+	status.Version = fmt.Sprintf("已安装（%s）", ddjvuPath)
+	return status
+}
+
+// FindExecutable 查找外部命令行工具，除PATH外还会检查常见的安装目录
+// （打包的macOS应用启动时PATH通常不包含Homebrew目录）
+func FindExecutable(name string) (string, error) {
+	if path, err := exec.LookPath(name); err == nil {
+		return path, nil
+	}
+
+	var searchDirs []string
+	switch runtime.GOOS {
+	case "darwin":
+		searchDirs = []string{"/opt/homebrew/bin", "/usr/local/bin", "/opt/local/bin"}
+	case "linux":
+		searchDirs = []string{"/usr/bin", "/usr/local/bin"}
+	case "windows":
+		searchDirs = []string{
+			"C:\\Program Files\\DjVuLibre",
+			"C:\\Program Files (x86)\\DjVuLibre",
+		}
+		if !strings.HasSuffix(strings.ToLower(name), ".exe") {
+			name += ".exe"
+		}
+	}
+
+	for _, dir := range searchDirs {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
 	}
 
+	return "", fmt.Errorf("未找到命令: %s", name)
+}
+
+// execCommandHidden 创建命令，在Windows下隐藏控制台窗口
+func execCommandHidden(name string, args ...string) *exec.Cmd {
+	cmd := exec.Command(name, args...)
+	hideConsoleWindow(cmd)
 	return cmd
 }
 
+// Command 创建在Windows下不弹出控制台窗口的命令，供调用外部工具的其他包使用
+func Command(name string, args ...string) *exec.Cmd {
+	return execCommandHidden(name, args...)
+}
+
 // checkPkgConfig 通过pkg-config检查
 func checkPkgConfig(pkg string) (string, error) {
 	// 设置常见的环境变量路径
@@ -399,7 +463,7 @@ func checkBrew() *DependencyStatus {
 		for _, brewPath := range brewPaths {
 			if _, err := os.Stat(brewPath); err == nil {
 				// 找到了brew，尝试直接执行
-				directCmd := exec.Command(brewPath, "--version")
+				directCmd := execCommandHidden(brewPath, "--version")
 				if directOutput, directErr := directCmd.Output(); directErr == nil {
 					lines := strings.Split(string(directOutput), "\n")
 					if len(lines) > 0 {
@@ -449,6 +513,25 @@ Arch: sudo pacman -S libvips`
 3. 使用MSYS2: pacman -S mingw-w64-x86_64-libvips`
 	}
 
+	switch runtime.GOOS {
+	case "darwin":
+		instructions["djvulibre"] = `macOS安装DjVuLibre（可选，用于DjVu文档）:
+1. 使用Homebrew: brew install djvulibre
+2. 使用MacPorts: sudo port install djvulibre`
+
+	case "linux":
+		instructions["djvulibre"] = `Linux安装DjVuLibre（可选，用于DjVu文档）:
+Ubuntu/Debian: sudo apt-get install djvulibre-bin
+CentOS/RHEL: sudo yum install djvulibre
+Fedora: sudo dnf install djvulibre
+Arch: sudo pacman -S djvulibre`
+
+	case "windows":
+		instructions["djvulibre"] = `Windows安装DjVuLibre（可选，用于DjVu文档）:
+1. 下载安装包: https://djvu.sourceforge.net/
+2. 将安装目录加入PATH环境变量`
+	}
+
 	return instructions
 }
 
@@ -487,23 +570,25 @@ func FormatDependencyReport(info *SystemInfo) string {
 		}
 	}
 
-	return report.String()
-}
+	report.WriteString("硬件加速:\n")
+	for _, acc := range info.Accelerators {
+		status := "❌"
+		if acc.Available {
+			status = "✅"
+		}
 
-// hideConsoleWindow 隐藏控制台窗口的平台特定实现
-func hideConsoleWindow(cmd *exec.Cmd) {
-	// 在Windows下隐藏控制台窗口
-	// 注意：为了保持跨平台编译兼容性，这里使用运行时检查
-	// 实际的Windows实现需要在Windows环境下构建时添加：
-	//
-	// if runtime.GOOS == "windows" {
-	//     import "syscall"
-	//     cmd.SysProcAttr = &syscall.SysProcAttr{
-	//         HideWindow:    true,
-	//         CreationFlags: 0x08000000, // CREATE_NO_WINDOW
-	//     }
-	// }
-	//
-	// 当前实现确保了代码在所有平台都能编译，
-	// Windows用户可以根据需要在本地构建时添加上述代码
+		report.WriteString(fmt.Sprintf("  %s %s", status, acc.Type))
+		if acc.Device != "" {
+			report.WriteString(fmt.Sprintf(" - %s", acc.Device))
+		}
+		if acc.Version != "" {
+			report.WriteString(fmt.Sprintf(" (%s)", acc.Version))
+		}
+		if acc.Error != "" {
+			report.WriteString(fmt.Sprintf(" - %s", acc.Error))
+		}
+		report.WriteString("\n")
+	}
+
+	return report.String()
 }
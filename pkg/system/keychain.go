@@ -0,0 +1,134 @@
+package system
+
+import (
+	"encoding/hex"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// passwordVaultType Windows 凭据管理器（通过 PowerShell 访问 WinRT PasswordVault）
+const passwordVaultType = `[Windows.Security.Credentials.PasswordVault, Windows.Security.Credentials, ContentType = WindowsRuntime] | Out-Null
+$vault = New-Object Windows.Security.Credentials.PasswordVault
+`
+
+// KeychainAvailable 当前系统是否有可用的钥匙串（macOS: security，Linux: secret-tool/libsecret，Windows: 凭据管理器）
+func KeychainAvailable() bool {
+	switch runtime.GOOS {
+	case "darwin":
+		_, err := FindExecutable("security")
+		return err == nil
+	case "linux":
+		_, err := FindExecutable("secret-tool")
+		return err == nil
+	case "windows":
+		_, err := FindExecutable("powershell")
+		return err == nil
+	default:
+		return false
+	}
+}
+
+// KeychainSet 在系统钥匙串中保存密钥，已存在时覆盖
+func KeychainSet(service, account, secret string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		// 命令通过标准输入交给 security 的交互模式执行，密钥以十六进制传递，不出现在命令行中
+		command := fmt.Sprintf("add-generic-password -U -s %s -a %s -X %s\n",
+			quoteSecurityArg(service), quoteSecurityArg(account), hex.EncodeToString([]byte(secret)))
+		if err := runKeychainCommand(command, "security", "-i"); err != nil {
+			return err
+		}
+		// 交互模式中命令失败时退出码仍为0，读回确认已保存
+		if saved, err := KeychainGet(service, account); err != nil || saved != strings.TrimRight(secret, "\r\n") {
+			return fmt.Errorf("访问钥匙串失败: 未能保存 %s/%s", service, account)
+		}
+		return nil
+
+	case "linux":
+		secretTool, err := FindExecutable("secret-tool")
+		if err != nil {
+			return fmt.Errorf("未找到 secret-tool，请安装 libsecret-tools: %w", err)
+		}
+		return runKeychainCommand(secret, secretTool, "store", "--label="+service, "service", service, "account", account)
+
+	case "windows":
+		// 密钥通过标准输入传递，不出现在命令行中
+		script := passwordVaultType + fmt.Sprintf(`$secret = [Console]::In.ReadToEnd()
+$vault.Add((New-Object Windows.Security.Credentials.PasswordCredential('%s', '%s', $secret)))`,
+			escapePowerShell(service), escapePowerShell(account))
+		return runKeychainCommand(secret, "powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+
+	default:
+		return fmt.Errorf("当前系统不支持钥匙串: %s", runtime.GOOS)
+	}
+}
+
+// KeychainGet 从系统钥匙串读取密钥
+func KeychainGet(service, account string) (string, error) {
+	var cmd []string
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = []string{"security", "find-generic-password", "-s", service, "-a", account, "-w"}
+	case "linux":
+		secretTool, err := FindExecutable("secret-tool")
+		if err != nil {
+			return "", fmt.Errorf("未找到 secret-tool，请安装 libsecret-tools: %w", err)
+		}
+		cmd = []string{secretTool, "lookup", "service", service, "account", account}
+	case "windows":
+		script := passwordVaultType + fmt.Sprintf(`$credential = $vault.Retrieve('%s', '%s')
+$credential.RetrievePassword()
+[Console]::Out.Write($credential.Password)`, escapePowerShell(service), escapePowerShell(account))
+		cmd = []string{"powershell", "-NoProfile", "-NonInteractive", "-Command", script}
+	default:
+		return "", fmt.Errorf("当前系统不支持钥匙串: %s", runtime.GOOS)
+	}
+
+	output, err := execCommandHidden(cmd[0], cmd[1:]...).Output()
+	secret := strings.TrimRight(string(output), "\r\n")
+	if err != nil || secret == "" {
+		return "", fmt.Errorf("钥匙串中未找到 %s/%s", service, account)
+	}
+	return secret, nil
+}
+
+// KeychainDelete 从系统钥匙串删除密钥
+func KeychainDelete(service, account string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		return runKeychainCommand("", "security", "delete-generic-password", "-s", service, "-a", account)
+
+	case "linux":
+		secretTool, err := FindExecutable("secret-tool")
+		if err != nil {
+			return fmt.Errorf("未找到 secret-tool，请安装 libsecret-tools: %w", err)
+		}
+		return runKeychainCommand("", secretTool, "clear", "service", service, "account", account)
+
+	case "windows":
+		script := passwordVaultType + fmt.Sprintf(`$vault.Remove($vault.Retrieve('%s', '%s'))`,
+			escapePowerShell(service), escapePowerShell(account))
+		return runKeychainCommand("", "powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+
+	default:
+		return fmt.Errorf("当前系统不支持钥匙串: %s", runtime.GOOS)
+	}
+}
+
+// quoteSecurityArg 为 security 交互模式的命令参数加引号
+func quoteSecurityArg(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// runKeychainCommand 执行钥匙串命令，stdin不为空时通过标准输入传递
+func runKeychainCommand(stdin, name string, args ...string) error {
+	cmd := execCommandHidden(name, args...)
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("访问钥匙串失败: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
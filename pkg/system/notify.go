@@ -0,0 +1,109 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// SendDesktopNotification 发送系统通知（macOS: osascript，Linux: notify-send，Windows: PowerShell托盘气泡）
+func SendDesktopNotification(title, message string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf(`display notification "%s" with title "%s"`,
+			escapeAppleScript(message), escapeAppleScript(title))
+		return runNotifyCommand("osascript", "-e", script)
+
+	case "linux":
+		notifySend, err := FindExecutable("notify-send")
+		if err != nil {
+			return fmt.Errorf("未找到 notify-send，请安装 libnotify: %w", err)
+		}
+		return runNotifyCommand(notifySend, "--app-name=pdfSeer", title, message)
+
+	case "windows":
+		script := fmt.Sprintf(`Add-Type -AssemblyName System.Windows.Forms
+$n = New-Object System.Windows.Forms.NotifyIcon
+$n.Icon = [System.Drawing.SystemIcons]::Information
+$n.BalloonTipTitle = '%s'
+$n.BalloonTipText = '%s'
+$n.Visible = $true
+$n.ShowBalloonTip(5000)
+Start-Sleep -Seconds 6
+$n.Dispose()`, escapePowerShell(title), escapePowerShell(message))
+		cmd := execCommandHidden("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+		// 气泡需要进程存活几秒，不等待其结束
+		return startDetached(cmd)
+
+	default:
+		return fmt.Errorf("当前系统不支持桌面通知: %s", runtime.GOOS)
+	}
+}
+
+// PlayNotificationSound 播放提示音，failed为true时使用错误提示音
+func PlayNotificationSound(failed bool) error {
+	switch runtime.GOOS {
+	case "darwin":
+		sound := "/System/Library/Sounds/Glass.aiff"
+		if failed {
+			sound = "/System/Library/Sounds/Basso.aiff"
+		}
+		return startDetached(execCommandHidden("afplay", sound))
+
+	case "linux":
+		sound := "/usr/share/sounds/freedesktop/stereo/complete.oga"
+		if failed {
+			sound = "/usr/share/sounds/freedesktop/stereo/dialog-error.oga"
+		}
+		if _, err := os.Stat(sound); err != nil {
+			return fmt.Errorf("未找到提示音文件: %s", sound)
+		}
+		for _, player := range []string{"paplay", "pw-play", "aplay"} {
+			if path, err := FindExecutable(player); err == nil {
+				return startDetached(execCommandHidden(path, sound))
+			}
+		}
+		return fmt.Errorf("未找到可用的音频播放器")
+
+	case "windows":
+		sound := "Asterisk"
+		if failed {
+			sound = "Hand"
+		}
+		return startDetached(execCommandHidden("powershell", "-NoProfile", "-NonInteractive", "-Command",
+			fmt.Sprintf("[System.Media.SystemSounds]::%s.Play(); Start-Sleep -Milliseconds 800", sound)))
+
+	default:
+		return fmt.Errorf("当前系统不支持提示音: %s", runtime.GOOS)
+	}
+}
+
+// startDetached 启动命令但不等待结束，后台回收进程
+func startDetached(cmd *exec.Cmd) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// runNotifyCommand 执行通知命令并附带输出信息
+func runNotifyCommand(name string, args ...string) error {
+	if output, err := execCommandHidden(name, args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("发送通知失败: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// escapeAppleScript 转义AppleScript字符串
+func escapeAppleScript(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, `"`, `\"`)
+}
+
+// escapePowerShell 转义PowerShell单引号字符串
+func escapePowerShell(s string) string {
+	return strings.ReplaceAll(s, "'", "''")
+}
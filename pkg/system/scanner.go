@@ -0,0 +1,179 @@
+package system
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// 扫描来源
+const (
+	ScanSourceFlatbed = "flatbed" // 平板，每次扫描一页
+	ScanSourceADF     = "adf"     // 自动进纸器，扫描到纸张用完为止
+)
+
+// ScanOptions 扫描参数，为空的字段使用扫描仪的默认值
+type ScanOptions struct {
+	Device     string `json:"device"`     // 扫描仪ID，为空时使用第一个扫描仪
+	Resolution int    `json:"resolution"` // DPI
+	Mode       string `json:"mode"`       // color、gray 或 lineart
+	Source     string `json:"source"`     // flatbed 或 adf
+}
+
+// Scanner 可用的扫描仪
+type Scanner struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// maxADFPages 自动进纸器单次扫描的页数上限
+const maxADFPages = 500
+
+// wiaFormatPNG WIA 图片格式 PNG 的 GUID
+const wiaFormatPNG = "{B96B3CAF-0728-11D3-9D7B-0000F81EF32E}"
+
+// ListScanners 列出可用的扫描仪
+// macOS 和 Linux 使用 SANE 的 scanimage，Windows 使用 WIA（PowerShell）
+func ListScanners() ([]Scanner, error) {
+	var output []byte
+	var err error
+	switch runtime.GOOS {
+	case "windows":
+		script := `$dm = New-Object -ComObject WIA.DeviceManager
+foreach ($info in $dm.DeviceInfos) { if ($info.Type -eq 1) { Write-Output ($info.DeviceID + '|' + $info.Properties.Item('Name').Value) } }`
+		output, err = execCommandHidden("powershell", "-NoProfile", "-NonInteractive", "-Command", script).Output()
+	default:
+		scanimage, findErr := FindExecutable("scanimage")
+		if findErr != nil {
+			return nil, fmt.Errorf("扫描需要安装 SANE（scanimage）")
+		}
+		output, err = execCommandHidden(scanimage, "-f", "%d|%v %m%n").Output()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("获取扫描仪列表失败: %w", err)
+	}
+
+	var scanners []Scanner
+	for _, line := range strings.Split(string(output), "\n") {
+		id, name, found := strings.Cut(strings.TrimSpace(line), "|")
+		if found && id != "" {
+			scanners = append(scanners, Scanner{ID: id, Name: strings.TrimSpace(name)})
+		}
+	}
+	return scanners, nil
+}
+
+// Scan 使用扫描仪扫描页面，图片保存到 outputDir，按扫描顺序返回图片路径
+func Scan(outputDir string, options ScanOptions) ([]string, error) {
+	var err error
+	switch runtime.GOOS {
+	case "windows":
+		err = scanWithWIA(outputDir, options)
+	default:
+		err = scanWithSANE(outputDir, options)
+	}
+
+	pages, _ := filepath.Glob(filepath.Join(outputDir, "page_*.png"))
+	sort.Strings(pages)
+	// 自动进纸器扫描到纸张用完时命令会返回错误，已扫描的页面仍然有效
+	if len(pages) == 0 {
+		if err == nil {
+			err = fmt.Errorf("没有扫描到页面")
+		}
+		return nil, err
+	}
+	return pages, nil
+}
+
+// scanWithSANE 使用 scanimage 扫描
+func scanWithSANE(outputDir string, options ScanOptions) error {
+	scanimage, err := FindExecutable("scanimage")
+	if err != nil {
+		return fmt.Errorf("扫描需要安装 SANE（scanimage）")
+	}
+
+	args := []string{"--format=png", "--batch=" + filepath.Join(outputDir, "page_%03d.png")}
+	if options.Source != ScanSourceADF {
+		args = append(args, "--batch-count=1")
+	}
+	if options.Device != "" {
+		args = append(args, "--device-name="+options.Device)
+	}
+	if options.Resolution > 0 {
+		args = append(args, fmt.Sprintf("--resolution=%d", options.Resolution))
+	}
+	switch options.Mode {
+	case "color":
+		args = append(args, "--mode=Color")
+	case "gray":
+		args = append(args, "--mode=Gray")
+	case "lineart":
+		args = append(args, "--mode=Lineart")
+	}
+	if options.Source == ScanSourceADF {
+		args = append(args, "--source=ADF")
+	}
+
+	output, err := execCommandHidden(scanimage, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("扫描失败: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// scanWithWIA 使用 WIA 扫描，自动进纸器逐页传输直到没有纸张
+func scanWithWIA(outputDir string, options ScanOptions) error {
+	count := 1
+	if options.Source == ScanSourceADF {
+		count = maxADFPages
+	}
+	intent := 1 // 彩色
+	switch options.Mode {
+	case "gray":
+		intent = 2
+	case "lineart":
+		intent = 4
+	}
+
+	script := fmt.Sprintf(`$dm = New-Object -ComObject WIA.DeviceManager
+$info = $null
+foreach ($i in $dm.DeviceInfos) { if ($i.Type -eq 1 -and ('%s' -eq '' -or $i.DeviceID -eq '%s')) { $info = $i; break } }
+if ($info -eq $null) { Write-Error 'no scanner'; exit 2 }
+$device = $info.Connect()
+$item = $device.Items.Item(1)
+try { $item.Properties.Item('6146').Value = %d } catch {}
+if (%d -gt 0) { try { $item.Properties.Item('6147').Value = %d; $item.Properties.Item('6148').Value = %d } catch {} }
+for ($n = 1; $n -le %d; $n++) {
+  try { $image = $item.Transfer('%s') } catch { if ($n -eq 1) { throw } else { break } }
+  $image.SaveFile((Join-Path '%s' ('page_{0:D3}.png' -f $n)))
+}`,
+		escapePowerShell(options.Device), escapePowerShell(options.Device),
+		intent, options.Resolution, options.Resolution, options.Resolution,
+		count, wiaFormatPNG, escapePowerShell(outputDir))
+
+	output, err := execCommandHidden("powershell", "-NoProfile", "-NonInteractive", "-STA", "-Command", script).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("扫描失败: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// checkSANE 检查SANE命令行工具（可选，用于扫描仪扫描）
+func checkSANE() *DependencyStatus {
+	status := &DependencyStatus{
+		Name:        "sane",
+		Required:    false,
+		Description: "扫描仪驱动（scanimage），用于从扫描仪获取页面",
+	}
+
+	path, err := FindExecutable("scanimage")
+	if err != nil {
+		status.Error = "未找到 scanimage 命令"
+		return status
+	}
+	status.Installed = true
+	status.Version = fmt.Sprintf("已安装（%s）", path)
+	return status
+}
@@ -0,0 +1,190 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// 自检项状态
+const (
+	SelfTestPass = "pass" // 通过
+	SelfTestWarn = "warn" // 可用但存在问题
+	SelfTestFail = "fail" // 失败，相关功能无法使用
+	SelfTestSkip = "skip" // 未执行
+)
+
+// 磁盘可用空间阈值
+const (
+	diskSpaceFailBytes = 200 << 20 // 低于该值时渲染图片和数据库写入可能失败
+	diskSpaceWarnBytes = 1 << 30
+)
+
+// SelfTestItem 单个自检项的结果
+type SelfTestItem struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`               // pass/warn/fail/skip
+	Message    string `json:"message"`              // 检查结果说明
+	Suggestion string `json:"suggestion,omitempty"` // 未通过时的修复建议
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// SelfTestReport 自检报告
+type SelfTestReport struct {
+	OS       string          `json:"os"`
+	Arch     string          `json:"arch"`
+	Items    []*SelfTestItem `json:"items"`
+	Passed   bool            `json:"passed"`   // 没有失败项
+	Warnings int             `json:"warnings"` // 警告项数量
+	Failures int             `json:"failures"` // 失败项数量
+	Time     time.Time       `json:"time"`
+}
+
+// NewSelfTestReport 创建空的自检报告
+func NewSelfTestReport() *SelfTestReport {
+	return &SelfTestReport{
+		OS:     runtime.GOOS,
+		Arch:   runtime.GOARCH,
+		Items:  make([]*SelfTestItem, 0),
+		Passed: true,
+		Time:   time.Now(),
+	}
+}
+
+// Add 添加自检项并更新汇总
+func (r *SelfTestReport) Add(item *SelfTestItem) {
+	r.Items = append(r.Items, item)
+	switch item.Status {
+	case SelfTestFail:
+		r.Failures++
+		r.Passed = false
+	case SelfTestWarn:
+		r.Warnings++
+	}
+}
+
+// RunSelfTestItem 执行检查并记录耗时
+func RunSelfTestItem(name string, check func(item *SelfTestItem)) *SelfTestItem {
+	item := &SelfTestItem{Name: name, Status: SelfTestPass}
+	start := time.Now()
+	check(item)
+	item.DurationMs = time.Since(start).Milliseconds()
+	return item
+}
+
+// DependencySelfTestItems 将依赖检查结果转换为自检项，缺少必需依赖为失败，缺少可选依赖为警告
+func DependencySelfTestItems(info *SystemInfo) []*SelfTestItem {
+	instructions := GetInstallInstructions()
+	items := make([]*SelfTestItem, 0, len(info.Dependencies))
+	for _, dep := range info.Dependencies {
+		item := &SelfTestItem{Name: "依赖: " + dep.Name, Status: SelfTestPass}
+		switch {
+		case dep.Installed:
+			item.Message = dep.Version
+		case dep.Required:
+			item.Status = SelfTestFail
+			item.Message = dep.Error
+			item.Suggestion = instructions[dep.Name]
+		default:
+			item.Status = SelfTestWarn
+			item.Message = dep.Error
+			item.Suggestion = instructions[dep.Name]
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// CheckDataDirWritable 检查数据目录能否创建、写入和删除文件
+func CheckDataDirWritable(dir string) *SelfTestItem {
+	return RunSelfTestItem("数据目录写入", func(item *SelfTestItem) {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			item.Status = SelfTestFail
+			item.Message = fmt.Sprintf("无法创建数据目录 %s: %v", dir, err)
+			item.Suggestion = fmt.Sprintf("请确认当前用户对 %s 的上级目录有写入权限", dir)
+			return
+		}
+
+		probe := filepath.Join(dir, fmt.Sprintf(".selftest-%d", time.Now().UnixNano()))
+		if err := os.WriteFile(probe, []byte("pdfSeer"), 0644); err != nil {
+			item.Status = SelfTestFail
+			item.Message = fmt.Sprintf("无法写入数据目录 %s: %v", dir, err)
+			item.Suggestion = writeAccessSuggestion(dir)
+			return
+		}
+		if err := os.Remove(probe); err != nil {
+			item.Status = SelfTestWarn
+			item.Message = fmt.Sprintf("测试文件写入成功但无法删除: %v", err)
+			item.Suggestion = writeAccessSuggestion(dir)
+			return
+		}
+		item.Message = dir
+	})
+}
+
+// writeAccessSuggestion 数据目录不可写时的修复建议
+func writeAccessSuggestion(dir string) string {
+	if runtime.GOOS == "windows" {
+		return fmt.Sprintf("请在 %s 的属性中取消“只读”，并确认杀毒软件没有拦截应用写入", dir)
+	}
+	return fmt.Sprintf("请检查目录权限，例如执行: chmod -R u+rw %s", dir)
+}
+
+// CheckDiskSpace 检查数据目录所在磁盘的可用空间
+func CheckDiskSpace(dir string) *SelfTestItem {
+	return RunSelfTestItem("磁盘可用空间", func(item *SelfTestItem) {
+		free, err := FreeDiskSpace(dir)
+		if err != nil {
+			item.Status = SelfTestWarn
+			item.Message = fmt.Sprintf("无法获取磁盘可用空间: %v", err)
+			return
+		}
+
+		item.Message = fmt.Sprintf("可用 %s", FormatDiskSize(free))
+		switch {
+		case free < diskSpaceFailBytes:
+			item.Status = SelfTestFail
+			item.Suggestion = "磁盘空间不足，渲染图片和保存结果可能失败。请清理磁盘，或在设置中清除缓存、缩短历史记录保留时间"
+		case free < diskSpaceWarnBytes:
+			item.Status = SelfTestWarn
+			item.Suggestion = "磁盘空间较少，处理大型文档时可能不足。建议清理磁盘或在设置中清除缓存"
+		}
+	})
+}
+
+// FormatDiskSize 格式化磁盘空间大小
+func FormatDiskSize(size uint64) string {
+	switch {
+	case size >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(size)/(1<<30))
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	default:
+		return fmt.Sprintf("%d KB", size>>10)
+	}
+}
+
+// FormatSelfTestReport 格式化自检报告
+func FormatSelfTestReport(report *SelfTestReport) string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("系统信息: %s/%s\n", report.OS, report.Arch))
+	b.WriteString(fmt.Sprintf("自检结果: %d 项失败，%d 项警告\n", report.Failures, report.Warnings))
+
+	icons := map[string]string{SelfTestPass: "✅", SelfTestWarn: "⚠️", SelfTestFail: "❌", SelfTestSkip: "⏭️"}
+	for _, item := range report.Items {
+		b.WriteString(fmt.Sprintf("  %s %s", icons[item.Status], item.Name))
+		if item.Message != "" {
+			b.WriteString(fmt.Sprintf(" - %s", item.Message))
+		}
+		b.WriteString("\n")
+		if item.Suggestion != "" && item.Status != SelfTestPass {
+			b.WriteString(fmt.Sprintf("    建议: %s\n", strings.ReplaceAll(item.Suggestion, "\n", "\n    ")))
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,300 @@
+package tags
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"pdf-ocr-ai/pkg/migrate"
+	"pdf-ocr-ai/pkg/storage"
+)
+
+// migrationComponent 表结构迁移使用的组件名
+const migrationComponent = "tags"
+
+// 标签类型
+const (
+	KindKeyword = "keyword" // 关键词
+	KindTopic   = "topic"   // 主题
+)
+
+// 每页保留的标签数量和单个标签的最大长度（字符）
+const (
+	maxKeywords  = 10
+	maxTopics    = 5
+	maxTagLength = 40
+)
+
+// PageTags 页面的关键词和主题
+type PageTags struct {
+	DocumentID   string    `json:"document_id"`
+	DocumentPath string    `json:"document_path"`
+	PageNumber   int       `json:"page_number"`
+	Keywords     []string  `json:"keywords"`
+	Topics       []string  `json:"topics"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// IndexEntry 标签索引项：标签及出现的页面
+type IndexEntry struct {
+	Tag   string `json:"tag"`
+	Kind  string `json:"kind"`
+	Pages []int  `json:"pages"`
+}
+
+// Match 按标签搜索到的页面
+type Match struct {
+	DocumentID   string `json:"document_id"`
+	DocumentPath string `json:"document_path"`
+	PageNumber   int    `json:"page_number"`
+	Tag          string `json:"tag"`
+	Kind         string `json:"kind"`
+}
+
+// row 数据库中的一个标签
+type row struct {
+	DocumentID   string    `db:"document_id"`
+	DocumentPath string    `db:"document_path"`
+	PageNumber   int       `db:"page_number"`
+	Kind         string    `db:"kind"`
+	Tag          string    `db:"tag"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+// Manager 页面标签存储，每个标签一行，便于跨文档搜索
+// 启用数据加密时标签内容加密保存，搜索在解密后进行
+type Manager struct {
+	db    *sqlx.DB
+	store *storage.Store
+}
+
+// NewManager 创建标签管理器，使用统一数据库中的 page_tags 表
+func NewManager(store *storage.Store) (*Manager, error) {
+	m := &Manager{db: store.DB(), store: store}
+	if err := store.Migrate(migrationComponent, migrations()); err != nil {
+		return nil, err
+	}
+	store.RegisterEncryptedColumns("page_tags", "tag")
+	return m, nil
+}
+
+// migrations 表结构迁移
+func migrations() []migrate.Migration {
+	return []migrate.Migration{
+		{
+			Version:     1,
+			Description: "创建页面标签表",
+			Up: migrate.SQL(`
+			CREATE TABLE IF NOT EXISTS page_tags (
+				document_id TEXT NOT NULL,
+				document_path TEXT NOT NULL,
+				page_number INTEGER NOT NULL,
+				kind TEXT NOT NULL,
+				tag TEXT NOT NULL,
+				updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			)`,
+				`CREATE INDEX IF NOT EXISTS idx_page_tags_page ON page_tags(document_id, page_number)`),
+		},
+	}
+}
+
+// SetPageTags 替换页面的标签
+func (m *Manager) SetPageTags(pageTags *PageTags) error {
+	tx, err := m.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("保存页面标签失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM page_tags WHERE document_id = ? AND page_number = ?", pageTags.DocumentID, pageTags.PageNumber); err != nil {
+		return fmt.Errorf("保存页面标签失败: %w", err)
+	}
+
+	insert := func(kind string, values []string) error {
+		for _, value := range values {
+			tag := value
+			if err := m.store.EncryptFields(&tag); err != nil {
+				return err
+			}
+			_, err := tx.Exec(`INSERT INTO page_tags (document_id, document_path, page_number, kind, tag, updated_at) VALUES (?, ?, ?, ?, ?, ?)`,
+				pageTags.DocumentID, pageTags.DocumentPath, pageTags.PageNumber, kind, tag, pageTags.UpdatedAt)
+			if err != nil {
+				return fmt.Errorf("保存页面标签失败: %w", err)
+			}
+		}
+		return nil
+	}
+	if err := insert(KindKeyword, pageTags.Keywords); err != nil {
+		return err
+	}
+	if err := insert(KindTopic, pageTags.Topics); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// loadRows 查询并解密标签
+func (m *Manager) loadRows(query string, args ...interface{}) ([]row, error) {
+	var rows []row
+	if err := m.db.Select(&rows, query, args...); err != nil {
+		return nil, fmt.Errorf("查询页面标签失败: %w", err)
+	}
+	for i := range rows {
+		if err := m.store.DecryptFields(&rows[i].Tag); err != nil {
+			return nil, err
+		}
+	}
+	return rows, nil
+}
+
+// GetDocumentTags 获取文档各页面的标签（按页码排序）
+func (m *Manager) GetDocumentTags(documentID string) ([]*PageTags, error) {
+	rows, err := m.loadRows("SELECT * FROM page_tags WHERE document_id = ? ORDER BY page_number, rowid", documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pages []*PageTags
+	for _, r := range rows {
+		if len(pages) == 0 || pages[len(pages)-1].PageNumber != r.PageNumber {
+			pages = append(pages, &PageTags{
+				DocumentID:   r.DocumentID,
+				DocumentPath: r.DocumentPath,
+				PageNumber:   r.PageNumber,
+				Keywords:     []string{},
+				Topics:       []string{},
+				UpdatedAt:    r.UpdatedAt,
+			})
+		}
+		page := pages[len(pages)-1]
+		if r.Kind == KindTopic {
+			page.Topics = append(page.Topics, r.Tag)
+		} else {
+			page.Keywords = append(page.Keywords, r.Tag)
+		}
+	}
+	return pages, nil
+}
+
+// Index 生成文档的标签索引，kind 为空时包含关键词和主题
+// 按出现页数倒序、相同时按标签排序
+func (m *Manager) Index(documentID, kind string) ([]IndexEntry, error) {
+	rows, err := m.loadRows("SELECT * FROM page_tags WHERE document_id = ? ORDER BY page_number", documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(map[string]*IndexEntry)
+	for _, r := range rows {
+		if kind != "" && r.Kind != kind {
+			continue
+		}
+		key := r.Kind + "\x00" + strings.ToLower(r.Tag)
+		entry, ok := entries[key]
+		if !ok {
+			entry = &IndexEntry{Tag: r.Tag, Kind: r.Kind}
+			entries[key] = entry
+		}
+		if n := len(entry.Pages); n == 0 || entry.Pages[n-1] != r.PageNumber {
+			entry.Pages = append(entry.Pages, r.PageNumber)
+		}
+	}
+
+	index := make([]IndexEntry, 0, len(entries))
+	for _, entry := range entries {
+		index = append(index, *entry)
+	}
+	sort.Slice(index, func(i, j int) bool {
+		if len(index[i].Pages) != len(index[j].Pages) {
+			return len(index[i].Pages) > len(index[j].Pages)
+		}
+		return index[i].Tag < index[j].Tag
+	})
+	return index, nil
+}
+
+// Search 在所有文档中查找包含指定标签的页面（不区分大小写，部分匹配）
+func (m *Manager) Search(query string) ([]Match, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return []Match{}, nil
+	}
+
+	rows, err := m.loadRows("SELECT * FROM page_tags ORDER BY updated_at DESC, document_id, page_number")
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []Match{}
+	for _, r := range rows {
+		if strings.Contains(strings.ToLower(r.Tag), query) {
+			matches = append(matches, Match{
+				DocumentID:   r.DocumentID,
+				DocumentPath: r.DocumentPath,
+				PageNumber:   r.PageNumber,
+				Tag:          r.Tag,
+				Kind:         r.Kind,
+			})
+		}
+	}
+	return matches, nil
+}
+
+// DeleteDocument 删除文档的所有标签
+func (m *Manager) DeleteDocument(documentID string) error {
+	if _, err := m.db.Exec("DELETE FROM page_tags WHERE document_id = ?", documentID); err != nil {
+		return fmt.Errorf("删除页面标签失败: %w", err)
+	}
+	return nil
+}
+
+// Prompt 提取关键词和主题的提示词
+func Prompt() string {
+	return fmt.Sprintf("请阅读以下文本，提取最多%d个关键词和最多%d个主题，只返回JSON对象，不要输出任何解释或Markdown标记，格式为："+
+		`{"keywords": ["关键词"], "topics": ["主题"]}`+
+		"。关键词为文本中出现的重要术语、人名、地名或机构名；主题为概括文本内容的简短短语。使用与原文相同的语言。", maxKeywords, maxTopics)
+}
+
+// ParseResponse 解析模型返回的关键词和主题，去除重复和空白，并限制数量和长度
+func ParseResponse(response string) (keywords, topics []string, err error) {
+	start := strings.IndexByte(response, '{')
+	end := strings.LastIndexByte(response, '}')
+	if start < 0 || end < start {
+		return nil, nil, fmt.Errorf("模型返回的内容不是JSON对象")
+	}
+
+	var parsed struct {
+		Keywords []string `json:"keywords"`
+		Topics   []string `json:"topics"`
+	}
+	if err := json.Unmarshal([]byte(response[start:end+1]), &parsed); err != nil {
+		return nil, nil, fmt.Errorf("解析标签失败: %w", err)
+	}
+	return normalize(parsed.Keywords, maxKeywords), normalize(parsed.Topics, maxTopics), nil
+}
+
+// normalize 清理标签列表
+func normalize(values []string, limit int) []string {
+	seen := make(map[string]bool)
+	result := []string{}
+	for _, value := range values {
+		tag := strings.Join(strings.Fields(value), " ")
+		if runes := []rune(tag); len(runes) > maxTagLength {
+			tag = string(runes[:maxTagLength])
+		}
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, tag)
+		if len(result) == limit {
+			break
+		}
+	}
+	return result
+}
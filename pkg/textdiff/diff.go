@@ -0,0 +1,227 @@
+package textdiff
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Op 差异片段类型
+type Op string
+
+const (
+	OpEqual  Op = "equal"
+	OpInsert Op = "insert" // 仅存在于新文本
+	OpDelete Op = "delete" // 仅存在于旧文本
+)
+
+// Span 差异片段
+type Span struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+// maxEdits 编辑距离上限，超过后不再逐词比对，直接视为整体替换（避免超长文本占用过多内存）
+const maxEdits = 2000
+
+// Diff 比较两段文本，按词返回差异片段
+// 英文和数字按单词切分，中文等按单字切分，便于对OCR结果逐字比对
+func Diff(oldText, newText string) []Span {
+	a := tokenize(oldText)
+	b := tokenize(newText)
+
+	// 去掉公共前缀和后缀，缩小比对范围
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var spans []Span
+	spans = appendSpan(spans, OpEqual, a[:prefix]...)
+	spans = append(spans, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	spans = appendSpan(spans, OpEqual, a[len(a)-suffix:]...)
+	return mergeSpans(spans)
+}
+
+// Stats 统计新增和删除的字符数
+func Stats(spans []Span) (added, removed int) {
+	for _, span := range spans {
+		switch span.Op {
+		case OpInsert:
+			added += utf8.RuneCountInString(span.Text)
+		case OpDelete:
+			removed += utf8.RuneCountInString(span.Text)
+		}
+	}
+	return added, removed
+}
+
+// Similarity 计算两段文本的相似度（0-1），为相同字符数占两段文本平均字符数的比例
+// 两段文本都为空时返回1
+func Similarity(a, b string) float64 {
+	total := utf8.RuneCountInString(a) + utf8.RuneCountInString(b)
+	if total == 0 {
+		return 1
+	}
+	equal := 0
+	for _, span := range Diff(a, b) {
+		if span.Op == OpEqual {
+			equal += utf8.RuneCountInString(span.Text)
+		}
+	}
+	return float64(2*equal) / float64(total)
+}
+
+// myers Myers差分算法，返回从a到b的编辑序列
+func myers(a, b []string) []Span {
+	n, m := len(a), len(b)
+	if n == 0 && m == 0 {
+		return nil
+	}
+	if n == 0 {
+		return appendSpan(nil, OpInsert, b...)
+	}
+	if m == 0 {
+		return appendSpan(nil, OpDelete, a...)
+	}
+
+	max := n + m
+	offset := max + 1
+	v := make([]int, 2*max+3)
+	// trace[d] 保存第d轮开始前 k∈[-(d+1), d+1] 的状态
+	var trace [][]int
+
+	for d := 0; d <= max; d++ {
+		if d > maxEdits {
+			spans := appendSpan(nil, OpDelete, a...)
+			return appendSpan(spans, OpInsert, b...)
+		}
+
+		snapshot := make([]int, 2*d+3)
+		copy(snapshot, v[offset-d-1:offset+d+2])
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace)
+			}
+		}
+	}
+
+	return nil
+}
+
+// backtrack 根据每一轮的状态回溯出编辑序列
+func backtrack(a, b []string, trace [][]int) []Span {
+	x, y := len(a), len(b)
+	var reversed []Span
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		snapshot := trace[d]
+		at := func(k int) int { return snapshot[k+d+1] }
+
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			reversed = append(reversed, Span{Op: OpEqual, Text: a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				reversed = append(reversed, Span{Op: OpInsert, Text: b[prevY]})
+			} else {
+				reversed = append(reversed, Span{Op: OpDelete, Text: a[prevX]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	spans := make([]Span, 0, len(reversed))
+	for i := len(reversed) - 1; i >= 0; i-- {
+		spans = append(spans, reversed[i])
+	}
+	return spans
+}
+
+// tokenize 切分文本：连续的字母数字、连续的空白各为一个词，其余字符（中文、标点）单独成词
+func tokenize(text string) []string {
+	var tokens []string
+	start := -1
+	kind := 0
+
+	classify := func(r rune) int {
+		switch {
+		case unicode.IsSpace(r):
+			return 1
+		case r < unicode.MaxLatin1 && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			return 2
+		default:
+			return 0
+		}
+	}
+
+	for i, r := range text {
+		c := classify(r)
+		if start >= 0 && (c != kind || c == 0) {
+			tokens = append(tokens, text[start:i])
+			start = -1
+		}
+		if start < 0 {
+			start = i
+			kind = c
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, text[start:])
+	}
+	return tokens
+}
+
+// appendSpan 将一组词作为一个片段追加
+func appendSpan(spans []Span, op Op, tokens ...string) []Span {
+	if len(tokens) == 0 {
+		return spans
+	}
+	return append(spans, Span{Op: op, Text: strings.Join(tokens, "")})
+}
+
+// mergeSpans 合并相邻的同类片段
+func mergeSpans(spans []Span) []Span {
+	merged := make([]Span, 0, len(spans))
+	for _, span := range spans {
+		if span.Text == "" {
+			continue
+		}
+		if n := len(merged); n > 0 && merged[n-1].Op == span.Op {
+			merged[n-1].Text += span.Text
+			continue
+		}
+		merged = append(merged, span)
+	}
+	return merged
+}
@@ -0,0 +1,127 @@
+package textstats
+
+import (
+	"sort"
+	"unicode"
+)
+
+const (
+	// cjkCharsPerMinute 中日韩文本每分钟阅读的字数
+	cjkCharsPerMinute = 300
+	// latinWordsPerMinute 拉丁文本每分钟阅读的词数
+	latinWordsPerMinute = 220
+)
+
+// PageStats 单页文本统计
+type PageStats struct {
+	Page       int `json:"page"`       // 页码（从1开始）
+	Characters int `json:"characters"` // 非空白字符数
+	Words      int `json:"words"`      // 中日韩字数加拉丁词数
+}
+
+// Distribution 各页字符数的分布，只统计有文本的页面
+type Distribution struct {
+	Min    int     `json:"min"`
+	Max    int     `json:"max"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+}
+
+// Stats 文档文本统计
+type Stats struct {
+	Characters     int          `json:"characters"`      // 非空白字符数
+	CJKCharacters  int          `json:"cjk_characters"`  // 中日韩字符数（不含标点）
+	LatinWords     int          `json:"latin_words"`     // 拉丁字母组成的词数
+	Words          int          `json:"words"`           // 中日韩字数加拉丁词数
+	CJKRatio       float64      `json:"cjk_ratio"`       // 中日韩字符占文字字符（中日韩字符和拉丁字母）的比例
+	LatinRatio     float64      `json:"latin_ratio"`     // 拉丁字母占文字字符的比例
+	ReadingMinutes float64      `json:"reading_minutes"` // 估计的阅读时间
+	TextPages      int          `json:"text_pages"`      // 有文本的页数
+	EmptyPages     []int        `json:"empty_pages"`     // 没有文本的页码
+	Pages          []PageStats  `json:"pages"`
+	Distribution   Distribution `json:"distribution"`
+}
+
+// Analyze 统计按页顺序排列的文本
+func Analyze(texts []string) Stats {
+	stats := Stats{Pages: make([]PageStats, 0, len(texts)), EmptyPages: []int{}}
+	var latinLetters int
+	var lengths []int
+
+	for i, text := range texts {
+		page := PageStats{Page: i + 1}
+		inWord := false
+		for _, r := range text {
+			if unicode.IsSpace(r) {
+				inWord = false
+				continue
+			}
+			page.Characters++
+			switch {
+			case isCJK(r):
+				stats.CJKCharacters++
+				page.Words++
+				inWord = false
+			case r < unicode.MaxLatin1 && unicode.IsLetter(r) || unicode.Is(unicode.Latin, r):
+				latinLetters++
+				if !inWord {
+					stats.LatinWords++
+					page.Words++
+				}
+				inWord = true
+			case unicode.IsDigit(r) || r == '\'' || r == '-':
+				// 数字、撇号和连字符不拆分单词
+			default:
+				inWord = false
+			}
+		}
+
+		stats.Characters += page.Characters
+		stats.Pages = append(stats.Pages, page)
+		if page.Characters == 0 {
+			stats.EmptyPages = append(stats.EmptyPages, page.Page)
+			continue
+		}
+		stats.TextPages++
+		lengths = append(lengths, page.Characters)
+	}
+
+	stats.Words = stats.CJKCharacters + stats.LatinWords
+	if letters := stats.CJKCharacters + latinLetters; letters > 0 {
+		stats.CJKRatio = float64(stats.CJKCharacters) / float64(letters)
+		stats.LatinRatio = float64(latinLetters) / float64(letters)
+	}
+	stats.ReadingMinutes = float64(stats.CJKCharacters)/cjkCharsPerMinute + float64(stats.LatinWords)/latinWordsPerMinute
+	stats.Distribution = distribution(lengths)
+	return stats
+}
+
+// distribution 计算字符数的分布
+func distribution(lengths []int) Distribution {
+	if len(lengths) == 0 {
+		return Distribution{}
+	}
+	sorted := append([]int{}, lengths...)
+	sort.Ints(sorted)
+
+	total := 0
+	for _, n := range sorted {
+		total += n
+	}
+	mid := len(sorted) / 2
+	median := float64(sorted[mid])
+	if len(sorted)%2 == 0 {
+		median = float64(sorted[mid-1]+sorted[mid]) / 2
+	}
+	return Distribution{
+		Min:    sorted[0],
+		Max:    sorted[len(sorted)-1],
+		Mean:   float64(total) / float64(len(sorted)),
+		Median: median,
+	}
+}
+
+// isCJK 是否为中日韩文字（汉字、假名和谚文）
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) || unicode.In(r, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
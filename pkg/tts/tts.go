@@ -0,0 +1,174 @@
+package tts
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sashabaranov/go-openai"
+
+	"pdf-ocr-ai/pkg/config"
+)
+
+// defaultMaxChars 未配置时单次合成的最大字符数（OpenAI语音接口上限为4096）
+const defaultMaxChars = 4000
+
+// Synthesizer 语音合成器，将一段文本合成为MP3音频
+type Synthesizer interface {
+	Synthesize(ctx context.Context, text string) ([]byte, error)
+}
+
+// New 按配置创建语音合成器，baseURL 和 apiKey 为AI服务的地址和密钥（OpenAI兼容接口使用）
+func New(cfg config.TTSConfig, baseURL, apiKey string) (Synthesizer, error) {
+	switch cfg.Provider {
+	case "", config.TTSProviderOpenAI:
+		if cfg.Model == "" || cfg.Voice == "" {
+			return nil, fmt.Errorf("未配置语音合成模型或音色")
+		}
+		clientConfig := openai.DefaultConfig(apiKey)
+		if baseURL != "" {
+			clientConfig.BaseURL = baseURL
+		}
+		return &openAISynthesizer{client: openai.NewClientWithConfig(clientConfig), cfg: cfg}, nil
+	case config.TTSProviderCommand:
+		args := strings.Fields(cfg.Command)
+		if len(args) == 0 {
+			return nil, fmt.Errorf("未配置本地语音合成命令")
+		}
+		if !strings.Contains(cfg.Command, "{output}") {
+			return nil, fmt.Errorf("本地语音合成命令中缺少 {output} 占位符")
+		}
+		return &commandSynthesizer{args: args}, nil
+	default:
+		return nil, fmt.Errorf("不支持的语音合成服务: %s", cfg.Provider)
+	}
+}
+
+// openAISynthesizer 使用OpenAI兼容的 /audio/speech 接口合成
+type openAISynthesizer struct {
+	client *openai.Client
+	cfg    config.TTSConfig
+}
+
+// Synthesize 合成一段文本
+func (s *openAISynthesizer) Synthesize(ctx context.Context, text string) ([]byte, error) {
+	resp, err := s.client.CreateSpeech(ctx, openai.CreateSpeechRequest{
+		Model:          openai.SpeechModel(s.cfg.Model),
+		Input:          text,
+		Voice:          openai.SpeechVoice(s.cfg.Voice),
+		ResponseFormat: openai.SpeechResponseFormatMp3,
+		Speed:          s.cfg.Speed,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("语音合成失败: %w", err)
+	}
+	defer resp.Close()
+
+	audio, err := io.ReadAll(resp)
+	if err != nil {
+		return nil, fmt.Errorf("读取合成音频失败: %w", err)
+	}
+	return audio, nil
+}
+
+// commandSynthesizer 调用本地命令合成，文本通过临时文件传入
+type commandSynthesizer struct {
+	args []string
+}
+
+// Synthesize 合成一段文本
+func (s *commandSynthesizer) Synthesize(ctx context.Context, text string) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "pdfseer-tts-*")
+	if err != nil {
+		return nil, fmt.Errorf("创建临时目录失败: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "input.txt")
+	output := filepath.Join(dir, "output.mp3")
+	if err := os.WriteFile(input, []byte(text), 0600); err != nil {
+		return nil, fmt.Errorf("写入临时文件失败: %w", err)
+	}
+
+	replacer := strings.NewReplacer("{input}", input, "{output}", output)
+	args := make([]string, len(s.args))
+	for i, arg := range s.args {
+		args[i] = replacer.Replace(arg)
+	}
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("语音合成命令执行失败: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	audio, err := os.ReadFile(output)
+	if err != nil {
+		return nil, fmt.Errorf("语音合成命令没有生成音频: %w", err)
+	}
+	return audio, nil
+}
+
+// SplitText 将文本按段落和句子切分为不超过 maxChars 个字符的片段
+func SplitText(text string, maxChars int) []string {
+	if maxChars <= 0 {
+		maxChars = defaultMaxChars
+	}
+
+	var chunks []string
+	var current strings.Builder
+	flush := func() {
+		if s := strings.TrimSpace(current.String()); s != "" {
+			chunks = append(chunks, s)
+		}
+		current.Reset()
+	}
+	for _, sentence := range splitSentences(text) {
+		if utf8.RuneCountInString(current.String())+utf8.RuneCountInString(sentence) > maxChars {
+			flush()
+		}
+		// 单个句子超长时按字符截断
+		for utf8.RuneCountInString(sentence) > maxChars {
+			runes := []rune(sentence)
+			chunks = append(chunks, string(runes[:maxChars]))
+			sentence = string(runes[maxChars:])
+		}
+		current.WriteString(sentence)
+	}
+	flush()
+	return chunks
+}
+
+// sentencePattern 句子：到句末标点或换行为止
+var sentencePattern = regexp.MustCompile(`[^。！？!?.；;\n]*[。！？!?.；;\n]+|[^。！？!?.；;\n]+$`)
+
+// splitSentences 按句末标点和换行切分，保留标点
+func splitSentences(text string) []string {
+	return sentencePattern.FindAllString(text, -1)
+}
+
+var (
+	markdownImagePattern = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
+	markdownLinkPattern  = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	htmlTagPattern       = regexp.MustCompile(`<[^>]+>`)
+	headingMarkPattern   = regexp.MustCompile(`(?m)^\s{0,3}(#{1,6}|>|[-*+])\s+`)
+	tableRulePattern     = regexp.MustCompile(`(?m)^\s*\|?[\s:|-]+\|[\s:|-]*$`)
+	emphasisPattern      = regexp.MustCompile("[*_`~]+")
+	blankLinesPattern    = regexp.MustCompile(`\n{3,}`)
+)
+
+// PlainText 去掉Markdown和HTML标记，得到适合朗读的纯文本
+func PlainText(text string) string {
+	text = markdownImagePattern.ReplaceAllString(text, "")
+	text = markdownLinkPattern.ReplaceAllString(text, "$1")
+	text = htmlTagPattern.ReplaceAllString(text, "")
+	text = tableRulePattern.ReplaceAllString(text, "")
+	text = headingMarkPattern.ReplaceAllString(text, "")
+	text = emphasisPattern.ReplaceAllString(text, "")
+	text = strings.ReplaceAll(text, "|", " ")
+	return strings.TrimSpace(blankLinesPattern.ReplaceAllString(text, "\n\n"))
+}
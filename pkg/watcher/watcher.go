@@ -0,0 +1,171 @@
+package watcher
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// fileState 文件在上次扫描时的状态
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// FolderWatcher 轮询方式监视目录中新增的文件
+type FolderWatcher struct {
+	dir        string
+	interval   time.Duration
+	extensions map[string]bool
+	onFile     func(path string)
+
+	mu      sync.Mutex
+	seen    map[string]*fileState
+	emitted map[string]bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewFolderWatcher 创建目录监视器，extensions为空时接受所有文件
+func NewFolderWatcher(dir string, interval time.Duration, extensions []string, onFile func(path string)) (*FolderWatcher, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("监视目录不可用: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("监视路径不是目录: %s", dir)
+	}
+
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	exts := make(map[string]bool, len(extensions))
+	for _, ext := range extensions {
+		exts[strings.ToLower(ext)] = true
+	}
+
+	return &FolderWatcher{
+		dir:        dir,
+		interval:   interval,
+		extensions: exts,
+		onFile:     onFile,
+		seen:       make(map[string]*fileState),
+		emitted:    make(map[string]bool),
+	}, nil
+}
+
+// Start 开始监视
+func (w *FolderWatcher) Start() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.stop != nil {
+		return
+	}
+
+	w.stop = make(chan struct{})
+	w.done = make(chan struct{})
+	go w.run(w.stop, w.done)
+
+	logger.Infof("开始监视目录: %s (间隔 %v)", w.dir, w.interval)
+}
+
+// Stop 停止监视并等待轮询协程退出
+func (w *FolderWatcher) Stop() {
+	w.mu.Lock()
+	stop, done := w.stop, w.done
+	w.stop, w.done = nil, nil
+	w.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+
+	close(stop)
+	<-done
+	logger.Infof("停止监视目录: %s", w.dir)
+}
+
+// ScanNow 立即扫描一次目录（供定时任务手动触发）
+func (w *FolderWatcher) ScanNow() {
+	w.scan()
+}
+
+// run 轮询循环
+func (w *FolderWatcher) run(stop, done chan struct{}) {
+	defer close(done)
+	defer logger.RecoverPanic("监视文件夹")
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.scan()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			w.scan()
+		}
+	}
+}
+
+// scan 扫描目录，文件大小和修改时间在两次扫描间保持不变才视为写入完成
+func (w *FolderWatcher) scan() {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		logger.Errorf("扫描监视目录失败: %v", err)
+		return
+	}
+
+	var ready []string
+	present := make(map[string]bool, len(entries))
+
+	w.mu.Lock()
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if len(w.extensions) > 0 && !w.extensions[strings.ToLower(filepath.Ext(entry.Name()))] {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		path := filepath.Join(w.dir, entry.Name())
+		present[path] = true
+
+		state, exists := w.seen[path]
+		if !exists || state.size != info.Size() || !state.modTime.Equal(info.ModTime()) {
+			w.seen[path] = &fileState{size: info.Size(), modTime: info.ModTime()}
+			continue
+		}
+
+		if !w.emitted[path] {
+			w.emitted[path] = true
+			ready = append(ready, path)
+		}
+	}
+
+	// 清理已删除文件的状态
+	for path := range w.seen {
+		if !present[path] {
+			delete(w.seen, path)
+			delete(w.emitted, path)
+		}
+	}
+	w.mu.Unlock()
+
+	for _, path := range ready {
+		w.onFile(path)
+	}
+}
@@ -0,0 +1,109 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"pdf-ocr-ai/pkg/config"
+)
+
+// Notifier 批处理完成通知（HTTP回调或本地脚本）
+type Notifier struct {
+	cfg    config.WebhookConfig
+	client *http.Client
+}
+
+// NewNotifier 创建通知器
+func NewNotifier(cfg config.WebhookConfig) *Notifier {
+	timeout := time.Duration(cfg.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &Notifier{
+		cfg:    cfg,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Enabled 是否配置了通知目标
+func (n *Notifier) Enabled() bool {
+	return n.cfg.Enabled && (n.cfg.URL != "" || n.cfg.ScriptPath != "")
+}
+
+// Send 发送通知，URL和脚本都配置时都会执行
+func (n *Notifier) Send(event string, payload interface{}) error {
+	if !n.Enabled() {
+		return nil
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("序列化通知内容失败: %w", err)
+	}
+
+	var errs []string
+	if n.cfg.URL != "" {
+		if err := n.post(event, body); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if n.cfg.ScriptPath != "" {
+		if err := n.runScript(event, body); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// post 以JSON POST到回调地址
+func (n *Notifier) post(event string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, n.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("创建回调请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "pdfSeer-webhook")
+	req.Header.Set("X-PdfSeer-Event", event)
+	if n.cfg.Secret != "" {
+		req.Header.Set("Authorization", "Bearer "+n.cfg.Secret)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("回调请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("回调返回状态码 %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// runScript 运行本地脚本，通知内容通过标准输入传入，事件名通过 PDFSEER_EVENT 环境变量传入
+func (n *Notifier) runScript(event string, body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), n.client.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, n.cfg.ScriptPath)
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Env = append(cmd.Environ(), "PDFSEER_EVENT="+event)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("通知脚本执行失败: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
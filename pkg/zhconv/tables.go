@@ -0,0 +1,101 @@
+package zhconv
+
+// s2tChars 常用简体字到繁体字的对应表，每项为一个简体字和对应的繁体字
+// 一简对多繁的字按最常见的用法对应，其他用法由 s2tPhrases 覆盖
+const s2tChars = "" +
+	"万萬 与與 专專 业業 丛叢 东東 丝絲 两兩 严嚴 丧喪 个個 丰豐 临臨 为為 丽麗 举舉 么麼 义義 乌烏 乐樂 " +
+	"乔喬 习習 乡鄉 书書 买買 乱亂 争爭 于於 亏虧 云雲 亚亞 产產 亩畝 亲親 亿億 仅僅 仆僕 从從 仓倉 仪儀 " +
+	"们們 价價 众眾 优優 会會 伞傘 伟偉 传傳 伤傷 伦倫 伪偽 体體 侠俠 侦偵 俩倆 俭儉 债債 倾傾 偿償 储儲 " +
+	"儿兒 兑兌 党黨 兰蘭 关關 兴興 养養 兽獸 内內 冈岡 写寫 军軍 农農 冯馮 冲衝 况況 冻凍 净淨 准準 凉涼 " +
+	"减減 凑湊 几幾 凤鳳 凭憑 凯凱 击擊 凿鑿 划劃 刘劉 则則 刚剛 创創 删刪 别別 剂劑 剑劍 剥剝 剧劇 劝勸 " +
+	"办辦 务務 动動 励勵 劲勁 劳勞 势勢 勋勳 匀勻 区區 医醫 华華 协協 单單 卖賣 卢盧 卧臥 卫衛 却卻 厂廠 " +
+	"厅廳 历歷 厉厲 压壓 厕廁 厨廚 县縣 参參 双雙 发發 变變 叙敘 叠疊 叶葉 号號 叹嘆 后後 吓嚇 吕呂 吗嗎 " +
+	"吨噸 听聽 启啟 吴吳 员員 呜嗚 咏詠 响響 哑啞 哗嘩 哟喲 唤喚 啰囉 啸嘯 喷噴 喽嘍 嘱囑 团團 园園 围圍 " +
+	"国國 图圖 圆圓 圣聖 场場 坏壞 块塊 坚堅 坛壇 坝壩 坞塢 坟墳 坠墜 垄壟 垒壘 垦墾 堕墮 墙牆 壮壯 声聲 " +
+	"壳殼 壶壺 处處 备備 复復 够夠 头頭 夸誇 夹夾 夺奪 奋奮 奖獎 妆妝 妇婦 妈媽 娄婁 娇嬌 娱娛 婴嬰 婶嬸 " +
+	"孙孫 学學 宁寧 宝寶 实實 宠寵 审審 宪憲 宽寬 宾賓 寝寢 对對 寻尋 导導 寿壽 将將 尔爾 尘塵 尝嘗 尧堯 " +
+	"尸屍 尽盡 层層 届屆 属屬 屿嶼 岁歲 岂豈 岗崗 岛島 岭嶺 峡峽 峦巒 崭嶄 巅巔 巩鞏 币幣 帅帥 师師 帐帳 " +
+	"帘簾 帜幟 带帶 帧幀 帮幫 幂冪 干幹 并並 广廣 庄莊 庆慶 庐廬 库庫 应應 庙廟 庞龐 废廢 开開 异異 弃棄 " +
+	"张張 弥彌 弯彎 弹彈 归歸 当當 录錄 彦彥 彻徹 径徑 忆憶 忏懺 忧憂 怀懷 态態 怂慫 怜憐 总總 恋戀 恳懇 " +
+	"恶惡 恼惱 悦悅 悬懸 惊驚 惧懼 惨慘 惩懲 惫憊 惭慚 惯慣 愤憤 愿願 懒懶 戏戲 战戰 扑撲 执執 扩擴 扫掃 " +
+	"扬揚 扰擾 抚撫 抛拋 抢搶 护護 报報 担擔 拟擬 拢攏 拣揀 拥擁 拦攔 拨撥 择擇 挂掛 挚摯 挡擋 挣掙 挤擠 " +
+	"挥揮 捞撈 损損 捡撿 换換 捣搗 据據 掳擄 掷擲 掺摻 揽攬 搀攙 摄攝 摆擺 摇搖 摊攤 撑撐 敌敵 敛斂 数數 " +
+	"斋齋 斩斬 断斷 无無 旧舊 时時 旷曠 昼晝 显顯 晋晉 晒曬 晓曉 晕暈 暂暫 术術 机機 杀殺 杂雜 权權 条條 " +
+	"来來 杨楊 杰傑 极極 构構 枣棗 枪槍 柜櫃 标標 栈棧 栋棟 栏欄 树樹 样樣 档檔 桥橋 梦夢 检檢 椭橢 楼樓 " +
+	"橱櫥 欢歡 欧歐 歼殲 残殘 殴毆 毁毀 毕畢 毡氈 气氣 汇匯 汉漢 汤湯 沟溝 没沒 沪滬 泪淚 泻瀉 泼潑 泽澤 " +
+	"洁潔 洒灑 洼窪 浅淺 浇澆 浊濁 测測 济濟 浏瀏 浑渾 浓濃 涂塗 涌湧 涛濤 涝澇 涡渦 润潤 涨漲 涩澀 淀澱 " +
+	"渊淵 渐漸 渔漁 渗滲 温溫 湾灣 湿濕 溃潰 溅濺 滚滾 滞滯 满滿 滤濾 滥濫 滨濱 滩灘 潇瀟 灭滅 灯燈 灵靈 " +
+	"灶竈 灾災 灿燦 炉爐 点點 炼煉 烁爍 烂爛 烛燭 烟煙 烦煩 烧燒 烫燙 热熱 焕煥 爱愛 爷爺 牵牽 牺犧 状狀 " +
+	"犹猶 狈狽 独獨 狭狹 狮獅 猎獵 猪豬 猫貓 献獻 玛瑪 环環 现現 琐瑣 琼瓊 电電 画畫 畅暢 疗療 疮瘡 疯瘋 " +
+	"痒癢 痴癡 瘫癱 瘾癮 盏盞 盐鹽 监監 盖蓋 盗盜 盘盤 睁睜 矫矯 矿礦 码碼 砖磚 砚硯 础礎 硕碩 确確 硷鹼 " +
+	"碍礙 礼禮 祷禱 祸禍 禅禪 离離 秃禿 种種 积積 称稱 税稅 稳穩 穷窮 窃竊 窍竅 窝窩 竖豎 竞競 笋筍 笔筆 " +
+	"笼籠 筑築 筹籌 签簽 简簡 类類 粪糞 粮糧 紧緊 纠糾 红紅 纤纖 约約 级級 纪紀 纬緯 纯純 纱紗 纲綱 纳納 " +
+	"纵縱 纷紛 纸紙 纹紋 纺紡 线線 练練 组組 绅紳 细細 织織 终終 绍紹 经經 绑綁 结結 绕繞 绘繪 给給 络絡 " +
+	"绝絕 统統 继繼 绩績 绪緒 续續 绳繩 维維 绵綿 综綜 绿綠 缓緩 编編 缘緣 缝縫 缠纏 缩縮 网網 罗羅 罚罰 " +
+	"罢罷 羡羨 翘翹 耸聳 耻恥 聋聾 职職 联聯 聪聰 肃肅 肠腸 肤膚 肾腎 肿腫 胀脹 胁脅 胆膽 胜勝 胶膠 脉脈 " +
+	"脏臟 脑腦 脚腳 脱脫 脸臉 腊臘 腻膩 腾騰 舆輿 舰艦 艰艱 艳艷 艺藝 节節 芦蘆 苍蒼 苏蘇 苹蘋 茧繭 荐薦 " +
+	"荡蕩 荣榮 药藥 莲蓮 获獲 莹瑩 萝蘿 营營 萧蕭 蓝藍 蔼藹 虏虜 虑慮 虚虛 虫蟲 虽雖 虾蝦 蚀蝕 蚂螞 蚕蠶 " +
+	"蛮蠻 蜗蝸 蜡蠟 蝇蠅 衔銜 补補 衬襯 袄襖 袜襪 袭襲 装裝 见見 观觀 规規 觅覓 视視 览覽 觉覺 触觸 誉譽 " +
+	"计計 订訂 认認 讨討 让讓 训訓 议議 讯訊 记記 讲講 许許 论論 设設 访訪 证證 评評 识識 诈詐 诉訴 诊診 " +
+	"词詞 译譯 试試 诗詩 诚誠 话話 询詢 该該 详詳 语語 误誤 说說 请請 诸諸 诺諾 读讀 课課 谁誰 调調 谅諒 " +
+	"谈談 谊誼 谋謀 谎謊 谓謂 谜謎 谢謝 谨謹 谱譜 贝貝 负負 贡貢 财財 责責 贤賢 败敗 账賬 货貨 质質 贫貧 " +
+	"购購 贯貫 贴貼 贵貴 贷貸 贸貿 费費 贼賊 资資 赌賭 赏賞 赐賜 赔賠 赖賴 赚賺 赛賽 赞讚 赠贈 赢贏 赵趙 " +
+	"赶趕 趋趨 跃躍 践踐 跷蹺 踪蹤 躯軀 车車 轨軌 转轉 轮輪 软軟 轰轟 轻輕 载載 轿轎 较較 辅輔 辆輛 辈輩 " +
+	"辉輝 输輸 辞辭 辩辯 辫辮 边邊 辽遼 达達 迁遷 过過 迈邁 运運 还還 这這 进進 远遠 违違 连連 迟遲 迹跡 " +
+	"适適 选選 逊遜 递遞 逻邏 遗遺 遥遙 邓鄧 邮郵 邹鄒 邻鄰 郑鄭 酝醞 酱醬 酿釀 释釋 针針 钉釘 钓釣 钟鐘 " +
+	"钢鋼 钥鑰 钱錢 钻鑽 铁鐵 铃鈴 铅鉛 铜銅 银銀 铸鑄 铺鋪 链鏈 销銷 锁鎖 锅鍋 锈鏽 锋鋒 锐銳 错錯 锦錦 " +
+	"键鍵 锻鍛 镇鎮 镜鏡 镶鑲 长長 门門 闪閃 闭閉 问問 闯闖 闰閏 闲閒 间間 闷悶 闸閘 闹鬧 闻聞 阀閥 阅閱 " +
+	"阐闡 阔闊 队隊 阳陽 阴陰 阵陣 阶階 际際 陆陸 陈陳 陕陝 险險 随隨 隐隱 隶隸 难難 雏雛 雳靂 雾霧 霁霽 " +
+	"静靜 韦韋 韩韓 韵韻 页頁 顶頂 项項 顺順 须須 顽頑 顾顧 顿頓 颁頒 颂頌 预預 领領 颇頗 颈頸 频頻 颖穎 " +
+	"颗顆 题題 颜顏 额額 颠顛 风風 飘飄 飞飛 饥飢 饭飯 饮飲 饰飾 饱飽 饲飼 饺餃 饿餓 馆館 馈饋 马馬 驰馳 " +
+	"驱驅 驳駁 驴驢 驶駛 驻駐 驾駕 骂罵 骄驕 骆駱 验驗 骑騎 骗騙 骤驟 髅髏 鬓鬢 鱼魚 鲁魯 鲜鮮 鲸鯨 鸟鳥 " +
+	"鸡雞 鸣鳴 鸥鷗 鸭鴨 鸿鴻 鹅鵝 鹊鵲 鹏鵬 鹤鶴 鹰鷹 麦麥 黄黃 齐齊 齿齒 龄齡 龙龍 龟龜"
+
+// t2sExtraChars 字表以外需要转换为简体的繁体字（一简对多繁时的其他繁体字和异体字）
+const t2sExtraChars = "乾干 髮发 麵面 裡里 裏里 曆历 複复 製制 範范 髒脏 沖冲 於于"
+
+// s2tPhrases 按字转换会出错的词语
+var s2tPhrases = map[string]string{
+	// 干：乾、幹、干
+	"干净": "乾淨", "干燥": "乾燥", "饼干": "餅乾", "干杯": "乾杯", "干旱": "乾旱", "干枯": "乾枯",
+	"干涉": "干涉", "干扰": "干擾", "干预": "干預", "若干": "若干", "相干": "相干", "干支": "干支",
+	// 后：後、后
+	"皇后": "皇后", "王后": "王后", "太后": "太后", "后妃": "后妃",
+	// 发：發、髮
+	"头发": "頭髮", "理发": "理髮", "发型": "髮型", "白发": "白髮", "毛发": "毛髮", "发夹": "髮夾",
+	// 面：面、麵
+	"面条": "麵條", "面包": "麵包", "面粉": "麵粉", "方便面": "方便麵",
+	// 里：里、裡
+	"这里": "這裡", "那里": "那裡", "哪里": "哪裡", "里面": "裡面", "里边": "裡邊",
+	"心里": "心裡", "家里": "家裡", "夜里": "夜裡", "手里": "手裡",
+	// 历：歷、曆
+	"日历": "日曆", "历法": "曆法", "农历": "農曆", "阳历": "陽曆", "阴历": "陰曆", "公历": "公曆",
+	// 复：復、複
+	"复杂": "複雜", "复制": "複製", "重复": "重複", "复印": "複印", "复数": "複數", "复合": "複合",
+	// 制：制、製
+	"制造": "製造", "制作": "製作", "制品": "製品",
+	// 范：范、範
+	"范围": "範圍", "规范": "規範", "模范": "模範", "示范": "示範", "范例": "範例", "范畴": "範疇",
+	// 其他
+	"批准": "批准", "准许": "准許", "茶几": "茶几", "肮脏": "骯髒", "脏话": "髒話",
+}
+
+// twPhrases 台湾常用词，s2tw 时覆盖 s2tPhrases，tw2s 时反向转换
+var twPhrases = map[string]string{
+	"软件": "軟體", "硬件": "硬體", "网络": "網路", "信息": "資訊", "程序": "程式",
+	"打印": "列印", "打印机": "印表機", "内存": "記憶體", "默认": "預設", "鼠标": "滑鼠",
+	"数据库": "資料庫", "服务器": "伺服器", "文件夹": "資料夾", "界面": "介面", "菜单": "選單",
+	"优化": "最佳化", "出租车": "計程車", "自行车": "腳踏車", "激光": "雷射", "短信": "簡訊", "博客": "部落格",
+}
+
+// hkPhrases 香港常用词，s2hk 时覆盖 s2tPhrases，hk2s 时反向转换
+var hkPhrases = map[string]string{
+	"网络": "網絡", "信息": "資訊", "默认": "預設", "鼠标": "滑鼠", "服务器": "伺服器",
+	"出租车": "的士", "自行车": "單車", "激光": "鐳射", "短信": "短訊", "博客": "網誌",
+}
+
+// twChars 台湾与通用繁体字不同的写法
+var twChars = map[rune]rune{'着': '著'}
+
+// hkChars 香港与通用繁体字不同的写法
+var hkChars = map[rune]rune{'裡': '裏'}
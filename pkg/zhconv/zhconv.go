@@ -0,0 +1,172 @@
+// Package zhconv 简繁中文转换，使用内置的常用字表和词表，按最长词语优先匹配
+package zhconv
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// 转换方式
+const (
+	ModeS2T  = "s2t"  // 简体到繁体
+	ModeT2S  = "t2s"  // 繁体到简体
+	ModeS2TW = "s2tw" // 简体到台湾繁体（含台湾常用词）
+	ModeTW2S = "tw2s" // 台湾繁体到简体（含台湾常用词）
+	ModeS2HK = "s2hk" // 简体到香港繁体（含香港常用词）
+	ModeHK2S = "hk2s" // 香港繁体到简体（含香港常用词）
+)
+
+// Modes 支持的转换方式
+var Modes = []string{ModeS2T, ModeT2S, ModeS2TW, ModeTW2S, ModeS2HK, ModeHK2S}
+
+// Converter 一种转换方式的字表和词表
+type Converter struct {
+	chars     map[rune]rune
+	phrases   map[string]string
+	maxPhrase int // 词表中最长词语的字数
+}
+
+var (
+	initOnce   sync.Once
+	converters map[string]*Converter
+)
+
+// ValidMode 是否为支持的转换方式
+func ValidMode(mode string) bool {
+	for _, m := range Modes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// Get 获取转换方式对应的转换器
+func Get(mode string) (*Converter, error) {
+	initOnce.Do(buildConverters)
+	c, ok := converters[mode]
+	if !ok {
+		return nil, fmt.Errorf("不支持的简繁转换方式: %s", mode)
+	}
+	return c, nil
+}
+
+// Convert 按转换方式转换文本，mode 为空时原样返回
+func Convert(text, mode string) (string, error) {
+	if mode == "" {
+		return text, nil
+	}
+	c, err := Get(mode)
+	if err != nil {
+		return text, err
+	}
+	return c.Convert(text), nil
+}
+
+// Convert 转换文本，每个位置优先匹配最长的词语，没有匹配的词语时按字转换
+func (c *Converter) Convert(text string) string {
+	var builder strings.Builder
+	builder.Grow(len(text))
+
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		matched := false
+		for n := min(c.maxPhrase, len(runes)-i); n >= 2; n-- {
+			if to, ok := c.phrases[string(runes[i:i+n])]; ok {
+				builder.WriteString(to)
+				i += n
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+		if to, ok := c.chars[runes[i]]; ok {
+			builder.WriteRune(to)
+		} else {
+			builder.WriteRune(runes[i])
+		}
+		i++
+	}
+	return builder.String()
+}
+
+// buildConverters 由内置字表和词表生成各转换方式的转换器
+func buildConverters() {
+	s2t := parsePairs(s2tChars)
+	t2s := make(map[rune]rune, len(s2t))
+	for s, t := range s2t {
+		t2s[t] = s
+	}
+	for t, s := range parsePairs(t2sExtraChars) {
+		t2s[t] = s
+	}
+
+	converters = map[string]*Converter{
+		ModeS2T:  newConverter(s2t, nil, s2tPhrases),
+		ModeT2S:  newConverter(t2s, nil),
+		ModeS2TW: newConverter(s2t, twChars, s2tPhrases, twPhrases),
+		ModeTW2S: newConverter(t2s, nil, invertPhrases(twPhrases)),
+		ModeS2HK: newConverter(s2t, hkChars, s2tPhrases, hkPhrases),
+		ModeHK2S: newConverter(t2s, nil, invertPhrases(hkPhrases)),
+	}
+}
+
+// newConverter 合并字表和词表，后面的词表覆盖前面的词表
+// variants 为地区写法，同时作用于字表的结果和词表的结果
+func newConverter(chars, variants map[rune]rune, phraseTables ...map[string]string) *Converter {
+	c := &Converter{
+		chars:   make(map[rune]rune, len(chars)+len(variants)),
+		phrases: make(map[string]string),
+	}
+	for from, to := range chars {
+		if v, ok := variants[to]; ok {
+			to = v
+		}
+		c.chars[from] = to
+	}
+	for from, to := range variants {
+		if _, ok := c.chars[from]; !ok {
+			c.chars[from] = to
+		}
+	}
+	for _, table := range phraseTables {
+		for from, to := range table {
+			if len(variants) > 0 {
+				to = strings.Map(func(r rune) rune {
+					if v, ok := variants[r]; ok {
+						return v
+					}
+					return r
+				}, to)
+			}
+			c.phrases[from] = to
+			c.maxPhrase = max(c.maxPhrase, utf8.RuneCountInString(from))
+		}
+	}
+	return c
+}
+
+// parsePairs 解析以空格分隔的两字对应表
+func parsePairs(table string) map[rune]rune {
+	pairs := make(map[rune]rune)
+	for _, field := range strings.Fields(table) {
+		runes := []rune(field)
+		if len(runes) == 2 {
+			pairs[runes[0]] = runes[1]
+		}
+	}
+	return pairs
+}
+
+// invertPhrases 反转词表，用于地区词到简体的转换
+func invertPhrases(phrases map[string]string) map[string]string {
+	inverted := make(map[string]string, len(phrases))
+	for from, to := range phrases {
+		inverted[to] = from
+	}
+	return inverted
+}
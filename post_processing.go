@@ -0,0 +1,64 @@
+package main
+
+import (
+	"pdf-ocr-ai/pkg/config"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/pdf"
+	"pdf-ocr-ai/pkg/postprocess"
+	"pdf-ocr-ai/pkg/zhconv"
+)
+
+// postProcessResult 按文档处理配置的后处理规则处理OCR或AI结果，再按简繁转换配置转换
+// 没有规则或规则无效时跳过后处理
+func (a *App) postProcessResult(doc *pdf.PDFDocument, target, text string) string {
+	if profile := a.resolveProfile(doc.FilePath); profile != nil && len(profile.PostProcessing) > 0 {
+		pipeline, err := postprocess.Compile(profile.PostProcessing)
+		if err != nil {
+			logger.Warnf("处理配置 %s 的后处理规则无效，跳过后处理: %v", profile.Name, err)
+		} else {
+			text = pipeline.Apply(text, target)
+		}
+	}
+	return convertChinese(text, a.configManager.GetConfig().ChineseConversion.Results)
+}
+
+// convertExportTexts 按简繁转换配置转换导出的各页文本，未配置时原样返回
+func (a *App) convertExportTexts(texts []string) []string {
+	mode := a.configManager.GetConfig().ChineseConversion.Export
+	if mode == "" {
+		return texts
+	}
+	converted := make([]string, len(texts))
+	for i, text := range texts {
+		converted[i] = convertChinese(text, mode)
+	}
+	return converted
+}
+
+// convertChinese 按转换方式进行简繁转换，方式为空或无效时原样返回
+func convertChinese(text, mode string) string {
+	if mode == "" || text == "" {
+		return text
+	}
+	converted, err := zhconv.Convert(text, mode)
+	if err != nil {
+		logger.Warnf("简繁转换失败: %v", err)
+		return text
+	}
+	return converted
+}
+
+// ConvertChineseText 按指定方式转换文本，用于预览简繁转换效果
+func (a *App) ConvertChineseText(text string, mode string) (string, error) {
+	return zhconv.Convert(text, mode)
+}
+
+// TestPostProcessRules 试运行后处理规则，返回每条规则执行后的文本，不修改任何结果
+// target 为 ocr 或 ai 时只执行作用于该类结果的规则，为空时执行全部规则
+func (a *App) TestPostProcessRules(rules []config.PostProcessRule, text string, target string) (*postprocess.DryRun, error) {
+	pipeline, err := postprocess.Compile(rules)
+	if err != nil {
+		return nil, err
+	}
+	return pipeline.Run(text, target), nil
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"pdf-ocr-ai/pkg/config"
+	"pdf-ocr-ai/pkg/i18n"
+	imageprocessor "pdf-ocr-ai/pkg/image"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/pdf"
+	"pdf-ocr-ai/pkg/postprocess"
+)
+
+// maxProfileConcurrency 处理配置可设置的最大并发页数
+const maxProfileConcurrency = 8
+
+// 处理配置的来源
+const (
+	ProfileSourceDocument    = "document"     // 为文档单独指定
+	ProfileSourceWatchFolder = "watch_folder" // 监视文件夹的配置
+	ProfileSourceDefault     = "default"      // 默认配置
+)
+
+// DocumentProfile 文档生效的处理配置
+type DocumentProfile struct {
+	FilePath string                    `json:"file_path"`
+	Profile  *config.ProcessingProfile `json:"profile"` // 为空时使用全局设置
+	Source   string                    `json:"source"`  // document、watch_folder 或 default，使用全局设置时为空
+}
+
+// GetPreprocessSteps 获取支持的图片预处理步骤
+func (a *App) GetPreprocessSteps() []string {
+	return imageprocessor.PreprocessSteps
+}
+
+// SetDocumentProfile 为文档指定处理配置，profileID 为空时取消指定（之后使用监视文件夹或默认配置）
+func (a *App) SetDocumentProfile(filePath string, profileID string) error {
+	if a.profileManager == nil {
+		return i18n.Errorf("init.component")
+	}
+	if profileID != "" && a.configManager.GetConfig().FindProfile(profileID) == nil {
+		return fmt.Errorf("处理配置不存在: %s", profileID)
+	}
+	if err := a.profileManager.Assign(filePath, profileID); err != nil {
+		return err
+	}
+
+	a.emit("document-profile-changed", a.documentProfile(filePath))
+	return nil
+}
+
+// GetDocumentProfile 获取文档生效的处理配置及其来源
+func (a *App) GetDocumentProfile(filePath string) (*DocumentProfile, error) {
+	if a.profileManager == nil {
+		return nil, i18n.Errorf("init.component")
+	}
+	return a.documentProfile(filePath), nil
+}
+
+// resolveProfile 获取文档生效的处理配置，没有时返回nil（使用全局设置）
+func (a *App) resolveProfile(filePath string) *config.ProcessingProfile {
+	return a.documentProfile(filePath).Profile
+}
+
+// documentProfile 按优先级查找文档的处理配置：文档指定 > 所在监视文件夹 > 默认配置
+func (a *App) documentProfile(filePath string) *DocumentProfile {
+	result := &DocumentProfile{FilePath: filePath}
+	if a.configManager == nil {
+		return result
+	}
+	cfg := a.configManager.GetConfig()
+
+	if a.profileManager != nil {
+		profileID, err := a.profileManager.Get(filePath)
+		if err != nil {
+			logger.Warnf("查询文档处理配置失败: %v", err)
+		}
+		if profile := cfg.FindProfile(profileID); profile != nil {
+			result.Profile, result.Source = profile, ProfileSourceDocument
+			return result
+		}
+	}
+
+	if cfg.WatchFolder.Profile != "" && isInsideDir(cfg.WatchFolder.InputDir, filePath) {
+		if profile := cfg.FindProfile(cfg.WatchFolder.Profile); profile != nil {
+			result.Profile, result.Source = profile, ProfileSourceWatchFolder
+			return result
+		}
+	}
+
+	if profile := cfg.FindProfile(cfg.DefaultProfile); profile != nil {
+		result.Profile, result.Source = profile, ProfileSourceDefault
+	}
+	return result
+}
+
+// isInsideDir 文件是否位于目录（含子目录）中
+func isInsideDir(dir, filePath string) bool {
+	if dir == "" {
+		return false
+	}
+	rel, err := filepath.Rel(dir, filePath)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+// ocrModel 实际使用的OCR模型名称：处理配置指定的模型 > OCR模型 > 通用模型
+func (a *App) ocrModel(profile *config.ProcessingProfile) string {
+	if model := profileOCRModel(profile); model != "" {
+		return model
+	}
+	aiConfig := a.configManager.GetAIConfig()
+	if aiConfig.OCRModel != "" {
+		return aiConfig.OCRModel
+	}
+	return aiConfig.Model
+}
+
+// profileOCRModel 处理配置指定的OCR模型，未指定时为空
+func profileOCRModel(profile *config.ProcessingProfile) string {
+	if profile == nil {
+		return ""
+	}
+	return profile.OCRModel
+}
+
+// profilePrompt 处理配置的提示词模板，未指定时为空
+func profilePrompt(profile *config.ProcessingProfile) string {
+	if profile == nil {
+		return ""
+	}
+	return profile.PromptTemplate
+}
+
+// profileConcurrency 处理配置的并发页数，未指定时使用 defaultValue
+func profileConcurrency(profile *config.ProcessingProfile, defaultValue int) int {
+	if profile == nil || profile.Concurrency <= 0 {
+		return defaultValue
+	}
+	return profile.Concurrency
+}
+
+// profileRenderConcurrency 处理配置的并发渲染页数，未指定时使用 defaultValue
+func profileRenderConcurrency(profile *config.ProcessingProfile, defaultValue int) int {
+	if profile == nil || profile.RenderConcurrency <= 0 {
+		return defaultValue
+	}
+	return profile.RenderConcurrency
+}
+
+// renderPageForOCR 按处理配置渲染页面并执行预处理，返回图片路径和清理临时文件的函数
+func (a *App) renderPageForOCR(doc *pdf.PDFDocument, pageNum int, profile *config.ProcessingProfile) (string, func(), error) {
+	noop := func() {}
+
+	dpi := 0
+	if profile != nil {
+		dpi = profile.RenderDPI
+	}
+	imagePath, ok := a.tilingSourceImage(doc, pageNum)
+	var err error
+	if !ok {
+		imagePath, err = a.pdfProcessor.RenderPageAtDPI(doc, pageNum, dpi)
+	}
+	if err != nil {
+		return "", noop, withErrorCode(ErrCodeRenderFailed, fmt.Errorf("渲染页面失败: %w", err))
+	}
+	if profile == nil || len(profile.Preprocessing) == 0 {
+		return imagePath, noop, nil
+	}
+
+	tmp, err := os.CreateTemp("", "pdfseer-preprocess-*.jpg")
+	if err != nil {
+		return "", noop, i18n.Errorf("file.temp_failed", err)
+	}
+	tmp.Close()
+	cleanup := func() { os.Remove(tmp.Name()) }
+
+	if err := imageprocessor.Preprocess(imagePath, tmp.Name(), profile.Preprocessing); err != nil {
+		cleanup()
+		return "", noop, withErrorCode(ErrCodeRenderFailed, fmt.Errorf("预处理第%d页失败: %w", pageNum, err))
+	}
+	logger.Debugf("第%d页按处理配置 %s 预处理: %s", pageNum, profile.Name, strings.Join(profile.Preprocessing, ", "))
+	return tmp.Name(), cleanup, nil
+}
+
+// validateProfiles 检查处理配置及其引用是否有效
+func validateProfiles(cfg config.AppConfig) error {
+	seen := make(map[string]bool)
+	for _, profile := range cfg.Profiles {
+		if profile.ID == "" || profile.Name == "" {
+			return fmt.Errorf("处理配置的ID和名称不能为空")
+		}
+		if seen[profile.ID] {
+			return fmt.Errorf("处理配置ID重复: %s", profile.ID)
+		}
+		seen[profile.ID] = true
+
+		if profile.RenderDPI != 0 && (profile.RenderDPI < pdf.MinRenderDPI || profile.RenderDPI > pdf.MaxRenderDPI) {
+			return fmt.Errorf("处理配置 %s 的渲染分辨率需在 %d-%d 之间", profile.Name, pdf.MinRenderDPI, pdf.MaxRenderDPI)
+		}
+		if profile.Concurrency < 0 || profile.Concurrency > maxProfileConcurrency {
+			return fmt.Errorf("处理配置 %s 的并发页数需在 0-%d 之间", profile.Name, maxProfileConcurrency)
+		}
+		if profile.RenderConcurrency < 0 || profile.RenderConcurrency > maxProfileConcurrency {
+			return fmt.Errorf("处理配置 %s 的并发渲染页数需在 0-%d 之间", profile.Name, maxProfileConcurrency)
+		}
+		if err := imageprocessor.ValidatePreprocessSteps(profile.Preprocessing); err != nil {
+			return fmt.Errorf("处理配置 %s: %w", profile.Name, err)
+		}
+		if err := postprocess.Validate(profile.PostProcessing); err != nil {
+			return fmt.Errorf("处理配置 %s: %w", profile.Name, err)
+		}
+	}
+
+	if cfg.DefaultProfile != "" && !seen[cfg.DefaultProfile] {
+		return fmt.Errorf("默认处理配置不存在: %s", cfg.DefaultProfile)
+	}
+	if cfg.WatchFolder.Profile != "" && !seen[cfg.WatchFolder.Profile] {
+		return fmt.Errorf("监视文件夹的处理配置不存在: %s", cfg.WatchFolder.Profile)
+	}
+	return nil
+}
+
+// removeMissingProfiles 删除处理配置后清理文档上的指定
+func (a *App) removeMissingProfiles(profiles []config.ProcessingProfile) {
+	if a.profileManager == nil {
+		return
+	}
+	ids := make([]string, 0, len(profiles))
+	for _, profile := range profiles {
+		ids = append(ids, profile.ID)
+	}
+	removed, err := a.profileManager.RemoveMissing(ids)
+	if err != nil {
+		logger.Warnf("清理文档处理配置失败: %v", err)
+		return
+	}
+	if removed > 0 {
+		logger.Infof("已删除的处理配置不再用于 %d 个文档", removed)
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// progressWindow 计算平均耗时和吞吐量时使用的最近完成页数
+const progressWindow = 10
+
+// progressStats 批处理的耗时统计，由收集结果的协程和结束处理时访问
+type progressStats struct {
+	mu        sync.Mutex
+	completed int
+	total     time.Duration   // 所有页面的处理耗时之和
+	durations []time.Duration // 最近完成页面的处理耗时
+	finished  []time.Time     // 最近完成页面的完成时间
+}
+
+// record 记录一个完成的页面
+func (s *progressStats) record(duration time.Duration, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.completed++
+	s.total += duration
+	s.durations = append(s.durations, duration)
+	s.finished = append(s.finished, at)
+	if len(s.durations) > progressWindow {
+		s.durations = s.durations[1:]
+		s.finished = s.finished[1:]
+	}
+}
+
+// averagePage 最近完成页面的平均处理耗时
+func (s *progressStats) averagePage() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.durations) == 0 {
+		return 0
+	}
+	var sum time.Duration
+	for _, d := range s.durations {
+		sum += d
+	}
+	return sum / time.Duration(len(s.durations))
+}
+
+// pagesPerSecond 吞吐量：按最近完成页面的间隔计算（并发处理时比单页耗时更准确），
+// 完成页数不足时按开始以来的总体速度计算
+func (s *progressStats) pagesPerSecond(startedAt, now time.Time) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if n := len(s.finished); n >= 3 {
+		if span := s.finished[n-1].Sub(s.finished[0]).Seconds(); span > 0 {
+			return float64(n-1) / span
+		}
+	}
+	if elapsed := now.Sub(startedAt).Seconds(); elapsed > 0 && s.completed > 0 {
+		return float64(s.completed) / elapsed
+	}
+	return 0
+}
+
+// pageProgress 记录完成的页面，并在进度更新中填入耗时、剩余时间、吞吐量和用量
+func (a *App) pageProgress(tracker *batchTracker, update ProgressUpdate, pageDuration time.Duration) ProgressUpdate {
+	if tracker == nil {
+		return update
+	}
+	now := time.Now()
+	tracker.stats.record(pageDuration, now)
+
+	update.ElapsedSeconds = now.Sub(tracker.startedAt).Seconds()
+	update.PageSeconds = pageDuration.Seconds()
+	update.AvgPageSeconds = tracker.stats.averagePage().Seconds()
+	if rate := tracker.stats.pagesPerSecond(tracker.startedAt, now); rate > 0 {
+		update.PagesPerMinute = rate * 60
+		if remaining := update.Total - update.Processed; remaining > 0 {
+			update.EtaSeconds = float64(remaining) / rate
+		}
+	}
+	if a.ocrClient != nil {
+		update.TotalTokens = a.ocrClient.GetUsage().Sub(tracker.usage).TotalTokens
+	}
+	return update
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"pdf-ocr-ai/pkg/ocr"
+	"pdf-ocr-ai/pkg/pdf"
+)
+
+// prevSummaryMaxTokens {{prev_summary}} 使用的上一页结果的最大长度
+const prevSummaryMaxTokens = 300
+
+// expandPromptVariables 替换提示词中的文档变量，未知变量保持原样：
+// {{title}} {{author}} 文档标题和作者，{{page}} {{total_pages}} 当前页码和总页数，
+// {{language}} 当前页文本的语言，{{prev_summary}} 上一页处理结果（没有时为OCR文本）的末尾节选
+func expandPromptVariables(prompt string, doc *pdf.PDFDocument, pageNum int) string {
+	return expandPagesPromptVariables(prompt, doc, []int{pageNum})
+}
+
+// expandPagesPromptVariables 为合并处理的多页替换提示词变量：{{page}} 为页码范围（如 "1-3, 5"），
+// {{language}} 按所有页面的文本判断，{{prev_summary}} 取第一页的上一页
+func expandPagesPromptVariables(prompt string, doc *pdf.PDFDocument, pageNumbers []int) string {
+	if !strings.Contains(prompt, "{{") {
+		return prompt
+	}
+	var pages []int
+	for _, pageNum := range pageNumbers {
+		if pageNum >= 1 && pageNum <= len(doc.Pages) {
+			pages = append(pages, pageNum)
+		}
+	}
+	if len(pages) == 0 {
+		return prompt
+	}
+	sort.Ints(pages)
+
+	var prevSummary string
+	if pages[0] > 1 {
+		prev := doc.Pages[pages[0]-2]
+		prevSummary = strings.TrimSpace(prev.AIText)
+		if prevSummary == "" {
+			prevSummary = strings.TrimSpace(prev.OCRText)
+		}
+		if ocr.EstimateTokens(prevSummary) > prevSummaryMaxTokens {
+			prevSummary = ocr.TailTokens(prevSummary, prevSummaryMaxTokens)
+		}
+	}
+
+	var text strings.Builder
+	for _, pageNum := range pages {
+		page := doc.Pages[pageNum-1]
+		if page.OCRText != "" {
+			text.WriteString(page.OCRText)
+		} else {
+			text.WriteString(page.Text)
+		}
+	}
+
+	return strings.NewReplacer(
+		"{{title}}", doc.Title,
+		"{{author}}", doc.Author,
+		"{{page}}", formatPages(pages),
+		"{{total_pages}}", strconv.Itoa(len(doc.Pages)),
+		"{{language}}", detectTextLanguage(text.String()),
+		"{{prev_summary}}", prevSummary,
+	).Replace(prompt)
+}
+
+// detectTextLanguage 按文字类型粗略判断文本语言：有假名为日语，有谚文为韩语，汉字为主为中文，拉丁字母为主为英文，无法判断时为空
+func detectTextLanguage(text string) string {
+	var han, kana, hangul, latin int
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			kana++
+		case unicode.Is(unicode.Hangul, r):
+			hangul++
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+
+	switch {
+	case kana > 0 && kana*10 >= han:
+		return "日语"
+	case hangul > han && hangul > latin:
+		return "韩语"
+	case han > 0 && han*2 >= latin:
+		return "中文"
+	case latin > 0:
+		return "英文"
+	}
+	return ""
+}
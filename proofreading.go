@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/proofread"
+	"pdf-ocr-ai/pkg/revisions"
+)
+
+// ProofreadPages 使用AI校对当前文档指定页面的文本，以逐条修改的形式保存（不直接改动文本）
+// textType: ocr 校对OCR文本，ai 校对AI处理文本；单页失败不影响其他页面
+func (a *App) ProofreadPages(pageNumbers []int, textType string) ([]proofread.Edit, error) {
+	session := a.activeSession()
+	if session == nil {
+		return nil, i18n.Errorf("doc.not_loaded")
+	}
+	if a.ocrClient == nil {
+		return nil, i18n.Errorf("ai.not_configured")
+	}
+	if textType != "ocr" && textType != "ai" {
+		return nil, i18n.Errorf("text.unsupported_type", textType)
+	}
+
+	doc := session.Doc
+	all := []proofread.Edit{}
+	failed := 0
+	for i, pageNum := range pageNumbers {
+		if pageNum < 1 || pageNum > len(doc.Pages) {
+			continue
+		}
+		a.emit("proofread-progress", map[string]interface{}{
+			"document_id": session.ID,
+			"page":        pageNum,
+			"current":     i + 1,
+			"total":       len(pageNumbers),
+		})
+
+		text := pageTextByType(doc.Pages[pageNum-1].OCRText, doc.Pages[pageNum-1].AIText, textType)
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		edits, err := a.proofreadPage(text)
+		if err != nil {
+			logger.Errorf("校对第%d页失败: %v", pageNum, err)
+			failed++
+			continue
+		}
+		saved, err := a.proofreadManager.ReplacePage(session.ID, pageNum, textType, edits)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, saved...)
+	}
+
+	a.emit("proofread-complete", map[string]interface{}{
+		"document_id": session.ID,
+		"edits":       len(all),
+		"failed":      failed,
+	})
+	if failed > 0 && failed == len(pageNumbers) {
+		return nil, fmt.Errorf("所有页面校对失败，请查看日志")
+	}
+	return all, nil
+}
+
+// proofreadPage 校对单个页面的文本
+func (a *App) proofreadPage(text string) ([]proofread.Edit, error) {
+	response, err := a.ocrClient.ProcessText(a.ctx, text, proofread.Prompt)
+	if err != nil {
+		return nil, i18n.Errorf("ai.failed_err", err)
+	}
+	edits, err := proofread.Parse(text, response)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for i := range edits {
+		edits[i].CreatedAt = now
+	}
+	return edits, nil
+}
+
+// GetProofreadEdits 获取当前文档的校对修改，pageNumber 为0时返回所有页面
+func (a *App) GetProofreadEdits(pageNumber int) ([]proofread.Edit, error) {
+	session := a.activeSession()
+	if session == nil {
+		return nil, i18n.Errorf("doc.not_loaded")
+	}
+	return a.proofreadManager.List(session.ID, pageNumber)
+}
+
+// SetProofreadEditStatus 接受或拒绝一处修改（status: accepted、rejected 或 pending）
+func (a *App) SetProofreadEditStatus(id int64, status string) error {
+	return a.proofreadManager.SetStatus(id, status)
+}
+
+// ApplyProofreadEdits 将页面已接受的修改合并到文本中并保存，返回合并的修改数
+// 校对后文本被编辑过、与原文对不上的修改会被跳过
+func (a *App) ApplyProofreadEdits(pageNumber int, textType string) (int, error) {
+	session := a.activeSession()
+	if session == nil {
+		return 0, i18n.Errorf("doc.not_loaded")
+	}
+	if pageNumber < 1 || pageNumber > len(session.Doc.Pages) {
+		return 0, i18n.Errorf("page.out_of_range")
+	}
+
+	edits, err := a.proofreadManager.List(session.ID, pageNumber)
+	if err != nil {
+		return 0, err
+	}
+	var pageEdits []proofread.Edit
+	for _, edit := range edits {
+		if edit.TextType == textType {
+			pageEdits = append(pageEdits, edit)
+		}
+	}
+
+	page := session.Doc.Pages[pageNumber-1]
+	merged, applied, remaining := proofread.Merge(pageTextByType(page.OCRText, page.AIText, textType), pageEdits)
+	if len(applied) == 0 {
+		return 0, nil
+	}
+	if err := a.updatePageText(pageNumber, textType, merged, revisions.SourceProofread); err != nil {
+		return 0, err
+	}
+	if err := a.proofreadManager.MarkApplied(applied, remaining); err != nil {
+		return 0, err
+	}
+
+	logger.Infof("第%d页已合并 %d 处校对修改", pageNumber, len(applied))
+	return len(applied), nil
+}
+
+// pageTextByType 按文本类型选择页面文本
+func pageTextByType(ocrText, aiText, textType string) string {
+	if textType == "ai" {
+		return aiText
+	}
+	return ocrText
+}
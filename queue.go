@@ -0,0 +1,517 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/jobs"
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// QueueItemStatus 队列任务状态
+type QueueItemStatus string
+
+const (
+	QueueStatusQueued    QueueItemStatus = "queued"
+	QueueStatusRunning   QueueItemStatus = "running"
+	QueueStatusPaused    QueueItemStatus = "paused"
+	QueueStatusCompleted QueueItemStatus = "completed"
+	QueueStatusFailed    QueueItemStatus = "failed"
+	QueueStatusCancelled QueueItemStatus = "cancelled"
+)
+
+// QueueRequest 加入队列的请求
+type QueueRequest struct {
+	FilePath       string `json:"file_path"`
+	Pages          []int  `json:"pages"`     // 为空表示全部页面
+	TaskType       string `json:"task_type"` // ocr 或 ai
+	Prompt         string `json:"prompt"`
+	ContextMode    bool   `json:"context_mode"`
+	RollingContext bool   `json:"rolling_context"` // AI按页顺序处理，每页附带前面已处理页面的摘录
+	ForceReprocess bool   `json:"force_reprocess"`
+}
+
+// QueueItem 跨文档处理队列中的任务
+type QueueItem struct {
+	ID             string          `json:"id"`
+	FilePath       string          `json:"file_path"`
+	DocumentID     string          `json:"document_id,omitempty"`
+	Pages          []int           `json:"pages"`
+	TaskType       string          `json:"task_type"`
+	Prompt         string          `json:"prompt"`
+	ContextMode    bool            `json:"context_mode"`
+	RollingContext bool            `json:"rolling_context"`
+	ForceReprocess bool            `json:"force_reprocess"`
+	Status         QueueItemStatus `json:"status"`
+	Error          string          `json:"error,omitempty"`
+	Processed      int             `json:"processed"`
+	Total          int             `json:"total"`
+	CreatedAt      string          `json:"created_at"`
+	StartedAt      string          `json:"started_at,omitempty"`
+	FinishedAt     string          `json:"finished_at,omitempty"`
+
+	session    *DocumentSession // 运行中的文档会话
+	cancelled  bool             // 运行中被用户取消
+	onFinished func(*QueueItem) // 任务结束后的回调（如监视文件夹导出结果）
+}
+
+// ProcessingQueue 跨文档批量处理队列
+type ProcessingQueue struct {
+	mu          sync.Mutex
+	items       []*QueueItem
+	nextID      int
+	concurrency int // 同时处理的文档数上限
+}
+
+// newProcessingQueue 创建处理队列（默认逐个文档顺序处理）
+func newProcessingQueue() *ProcessingQueue {
+	return &ProcessingQueue{concurrency: 1}
+}
+
+// findLocked 按ID查找任务（调用方需持有锁）
+func (q *ProcessingQueue) findLocked(id string) (int, *QueueItem) {
+	for i, item := range q.items {
+		if item.ID == id {
+			return i, item
+		}
+	}
+	return -1, nil
+}
+
+// snapshot 获取队列快照（运行中的任务附带实时进度）
+func (q *ProcessingQueue) snapshot() []QueueItem {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	items := make([]QueueItem, 0, len(q.items))
+	for _, item := range q.items {
+		copied := *item
+		if item.session != nil {
+			item.session.processingMu.Lock()
+			copied.Processed = item.session.processedInBatch
+			item.session.processingMu.Unlock()
+		}
+		copied.session = nil
+		items = append(items, copied)
+	}
+	return items
+}
+
+// EnqueueDocuments 将多个文档加入处理队列，返回队列任务ID
+func (a *App) EnqueueDocuments(requests []QueueRequest) ([]string, error) {
+	return a.enqueue(requests, nil)
+}
+
+// enqueue 将请求加入队列，onFinished在每个任务结束后调用
+func (a *App) enqueue(requests []QueueRequest, onFinished func(*QueueItem)) ([]string, error) {
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("没有要加入队列的文档")
+	}
+
+	for _, req := range requests {
+		if req.FilePath == "" {
+			return nil, fmt.Errorf("文档路径不能为空")
+		}
+		if req.TaskType != "" && req.TaskType != string(jobs.TaskOCR) && req.TaskType != string(jobs.TaskAI) {
+			return nil, fmt.Errorf("不支持的任务类型: %s", req.TaskType)
+		}
+		if req.TaskType == string(jobs.TaskAI) && req.Prompt == "" {
+			return nil, fmt.Errorf("AI任务需要提供提示词: %s", req.FilePath)
+		}
+	}
+
+	a.queue.mu.Lock()
+	ids := make([]string, 0, len(requests))
+	for _, req := range requests {
+		taskType := req.TaskType
+		if taskType == "" {
+			taskType = string(jobs.TaskOCR)
+		}
+
+		a.queue.nextID++
+		item := &QueueItem{
+			ID:             fmt.Sprintf("q-%d", a.queue.nextID),
+			FilePath:       req.FilePath,
+			Pages:          req.Pages,
+			TaskType:       taskType,
+			Prompt:         req.Prompt,
+			ContextMode:    req.ContextMode,
+			RollingContext: req.RollingContext,
+			ForceReprocess: req.ForceReprocess,
+			Status:         QueueStatusQueued,
+			Total:          len(req.Pages),
+			CreatedAt:      time.Now().Format("2006-01-02 15:04:05"),
+			onFinished:     onFinished,
+		}
+		a.queue.items = append(a.queue.items, item)
+		ids = append(ids, item.ID)
+	}
+	a.queue.mu.Unlock()
+
+	logger.Infof("已加入处理队列: %d 个文档", len(ids))
+
+	a.emitQueueUpdated()
+	a.dispatchQueue()
+
+	return ids, nil
+}
+
+// startOrQueueBatch 文档空闲时立即开始批量处理并返回任务ID
+// 文档已有批次在运行时加入处理队列排在其后，返回队列任务ID，可通过 GetQueue 和 MoveQueueItem 查看和调整
+func (a *App) startOrQueueBatch(session *DocumentSession, req QueueRequest) string {
+	if session != nil {
+		req.Pages = includedPages(session.Doc, req.Pages)
+	}
+	if session != nil && session.busy() {
+		req.FilePath = session.Doc.FilePath
+		if req.TaskType == string(jobs.TaskAI) && req.Prompt == "" {
+			req.Prompt = profilePrompt(a.resolveProfile(req.FilePath))
+		}
+		ids, err := a.enqueue([]QueueRequest{req}, nil)
+		if err != nil {
+			logger.Errorf("批次加入队列失败: %v", err)
+			a.emitError("processing-error", newEventError(ErrCodeDocumentBusy, i18n.T("batch.busy")))
+			return ""
+		}
+		logger.Infof("文档 %s 正在处理，新批次已加入队列: %s", session.ID, ids[0])
+		a.emit("batch-queued", map[string]interface{}{
+			"document_id": session.ID,
+			"queue_id":    ids[0],
+			"pages":       req.Pages,
+			"task_type":   req.TaskType,
+		})
+		return ids[0]
+	}
+
+	if req.TaskType == string(jobs.TaskAI) {
+		return a.startAIBatch(session, req.Pages, req.Prompt, req.ForceReprocess, req.ContextMode, req.RollingContext, nil, nil)
+	}
+	return a.startPagesBatch(session, req.Pages, req.ForceReprocess, nil, nil)
+}
+
+// GetQueue 获取处理队列
+func (a *App) GetQueue() []QueueItem {
+	return a.queue.snapshot()
+}
+
+// SetQueueConcurrency 设置同时处理的文档数上限
+func (a *App) SetQueueConcurrency(concurrency int) error {
+	if concurrency < 1 {
+		return fmt.Errorf("并发数必须大于0")
+	}
+
+	a.queue.mu.Lock()
+	a.queue.concurrency = concurrency
+	a.queue.mu.Unlock()
+
+	a.dispatchQueue()
+	return nil
+}
+
+// MoveQueueItem 调整等待中任务在队列中的位置
+func (a *App) MoveQueueItem(id string, newIndex int) error {
+	a.queue.mu.Lock()
+	index, item := a.queue.findLocked(id)
+	if item == nil {
+		a.queue.mu.Unlock()
+		return i18n.Errorf("queue.not_found", id)
+	}
+	if newIndex < 0 {
+		newIndex = 0
+	}
+	if newIndex >= len(a.queue.items) {
+		newIndex = len(a.queue.items) - 1
+	}
+
+	items := append(a.queue.items[:index:index], a.queue.items[index+1:]...)
+	items = append(items[:newIndex], append([]*QueueItem{item}, items[newIndex:]...)...)
+	a.queue.items = items
+	a.queue.mu.Unlock()
+
+	a.emitQueueUpdated()
+	return nil
+}
+
+// PauseQueueItem 暂停队列任务（等待中的任务会被跳过，运行中的任务暂停处理）
+func (a *App) PauseQueueItem(id string) error {
+	a.queue.mu.Lock()
+	_, item := a.queue.findLocked(id)
+	if item == nil {
+		a.queue.mu.Unlock()
+		return i18n.Errorf("queue.not_found", id)
+	}
+
+	var session *DocumentSession
+	switch item.Status {
+	case QueueStatusQueued:
+		item.Status = QueueStatusPaused
+	case QueueStatusRunning:
+		if item.session == nil {
+			a.queue.mu.Unlock()
+			return fmt.Errorf("任务正在启动，请稍后再试")
+		}
+		item.Status = QueueStatusPaused
+		session = item.session
+	default:
+		a.queue.mu.Unlock()
+		return fmt.Errorf("任务当前状态无法暂停: %s", item.Status)
+	}
+	a.queue.mu.Unlock()
+
+	if session != nil {
+		a.pauseSession(session)
+	}
+
+	a.emitQueueUpdated()
+	return nil
+}
+
+// ResumeQueueItem 继续已暂停的队列任务
+func (a *App) ResumeQueueItem(id string) error {
+	a.queue.mu.Lock()
+	_, item := a.queue.findLocked(id)
+	if item == nil {
+		a.queue.mu.Unlock()
+		return i18n.Errorf("queue.not_found", id)
+	}
+	if item.Status != QueueStatusPaused {
+		a.queue.mu.Unlock()
+		return fmt.Errorf("任务未处于暂停状态: %s", item.Status)
+	}
+
+	session := item.session
+	if session != nil {
+		item.Status = QueueStatusRunning
+	} else {
+		item.Status = QueueStatusQueued
+	}
+	a.queue.mu.Unlock()
+
+	if session != nil {
+		a.resumeSession(session)
+	}
+
+	a.emitQueueUpdated()
+	a.dispatchQueue()
+	return nil
+}
+
+// CancelQueueItem 取消队列任务
+func (a *App) CancelQueueItem(id string) error {
+	a.queue.mu.Lock()
+	_, item := a.queue.findLocked(id)
+	if item == nil {
+		a.queue.mu.Unlock()
+		return i18n.Errorf("queue.not_found", id)
+	}
+
+	session := item.session
+	switch item.Status {
+	case QueueStatusQueued:
+		item.Status = QueueStatusCancelled
+		item.FinishedAt = time.Now().Format("2006-01-02 15:04:05")
+	case QueueStatusPaused:
+		if session != nil {
+			item.cancelled = true
+		} else {
+			item.Status = QueueStatusCancelled
+			item.FinishedAt = time.Now().Format("2006-01-02 15:04:05")
+		}
+	case QueueStatusRunning:
+		item.cancelled = true
+	default:
+		a.queue.mu.Unlock()
+		return fmt.Errorf("任务已结束: %s", item.Status)
+	}
+	a.queue.mu.Unlock()
+
+	if session != nil {
+		a.cancelSession(session)
+	}
+
+	a.emitQueueUpdated()
+	return nil
+}
+
+// RemoveQueueItem 从队列中移除未运行的任务
+func (a *App) RemoveQueueItem(id string) error {
+	a.queue.mu.Lock()
+	index, item := a.queue.findLocked(id)
+	if item == nil {
+		a.queue.mu.Unlock()
+		return i18n.Errorf("queue.not_found", id)
+	}
+	if item.session != nil {
+		a.queue.mu.Unlock()
+		return fmt.Errorf("任务正在运行，请先取消")
+	}
+	a.queue.items = append(a.queue.items[:index], a.queue.items[index+1:]...)
+	a.queue.mu.Unlock()
+
+	a.emitQueueUpdated()
+	return nil
+}
+
+// ClearFinishedQueueItems 清除已结束的队列任务
+func (a *App) ClearFinishedQueueItems() {
+	a.queue.mu.Lock()
+	remaining := a.queue.items[:0]
+	for _, item := range a.queue.items {
+		switch item.Status {
+		case QueueStatusCompleted, QueueStatusFailed, QueueStatusCancelled:
+			continue
+		}
+		remaining = append(remaining, item)
+	}
+	a.queue.items = remaining
+	a.queue.mu.Unlock()
+
+	a.emitQueueUpdated()
+}
+
+// dispatchQueue 在并发上限内启动等待中的任务，文档正在处理时该文档的任务继续等待
+func (a *App) dispatchQueue() {
+	busy := a.busyDocumentPaths()
+
+	a.queue.mu.Lock()
+	running := 0
+	for _, item := range a.queue.items {
+		if item.session != nil || item.Status == QueueStatusRunning {
+			running++
+			busy[item.FilePath] = true
+		}
+	}
+
+	var toStart []*QueueItem
+	for _, item := range a.queue.items {
+		if running >= a.queue.concurrency {
+			break
+		}
+		if item.Status == QueueStatusQueued && !busy[item.FilePath] {
+			item.Status = QueueStatusRunning
+			item.StartedAt = time.Now().Format("2006-01-02 15:04:05")
+			toStart = append(toStart, item)
+			busy[item.FilePath] = true
+			running++
+		}
+	}
+	a.queue.mu.Unlock()
+
+	for _, item := range toStart {
+		go a.runQueueItem(item)
+	}
+}
+
+// runQueueItem 执行单个队列任务
+func (a *App) runQueueItem(item *QueueItem) {
+	defer logger.RecoverPanic("runQueueItem")
+
+	err := a.executeQueueItem(item)
+
+	a.queue.mu.Lock()
+	item.session = nil
+	if errors.Is(err, errBatchBusy) && !item.cancelled {
+		// 文档在开始前被其他批次占用，重新排队，等该批次结束后再执行
+		item.Status = QueueStatusQueued
+		item.StartedAt = ""
+		a.queue.mu.Unlock()
+		logger.Infof("队列任务 %s 的文档正在处理，重新排队: %s", item.ID, item.FilePath)
+		a.emitQueueUpdated()
+		a.dispatchQueue()
+		return
+	}
+	item.FinishedAt = time.Now().Format("2006-01-02 15:04:05")
+	switch {
+	case item.cancelled || errors.Is(err, context.Canceled):
+		item.Status = QueueStatusCancelled
+	case err != nil:
+		item.Status = QueueStatusFailed
+		item.Error = err.Error()
+	default:
+		item.Status = QueueStatusCompleted
+		item.Processed = item.Total
+	}
+	status := item.Status
+	a.queue.mu.Unlock()
+
+	logger.Infof("队列任务 %s 结束: %s (%s)", item.ID, status, item.FilePath)
+
+	if item.onFinished != nil {
+		item.onFinished(item)
+	}
+
+	a.emit("queue-item-finished", map[string]interface{}{
+		"id":          item.ID,
+		"document_id": item.DocumentID,
+		"file_path":   item.FilePath,
+		"status":      status,
+		"error":       item.Error,
+	})
+	a.emitQueueUpdated()
+	a.dispatchQueue()
+}
+
+// executeQueueItem 加载文档并执行OCR或AI批量处理（阻塞直到处理结束），返回批次的结果
+// 文档是否已有批次在运行由批次开始时检查（返回 errBatchBusy），避免检查后到开始前被其他批次抢先
+func (a *App) executeQueueItem(item *QueueItem) error {
+	if a.ocrClient == nil {
+		return i18n.Errorf("ai.not_configured")
+	}
+
+	session, err := a.loadDocumentSession(item.FilePath)
+	if err != nil {
+		return i18n.Errorf("doc.load_failed", err)
+	}
+	session = a.registerSession(session, false)
+	a.emitWorkspaceChanged()
+
+	pages := item.Pages
+	if len(pages) == 0 {
+		pages = allPageNumbers(session.Doc.PageCount)
+	}
+	pages = includedPages(session.Doc, pages)
+
+	a.queue.mu.Lock()
+	item.session = session
+	item.DocumentID = session.ID
+	item.Total = len(pages)
+	cancelled := item.cancelled
+	a.queue.mu.Unlock()
+
+	if cancelled {
+		return nil
+	}
+
+	// 模型不支持图片识别时直接标记失败，不再逐页报错
+	if item.TaskType != string(jobs.TaskAI) {
+		if err := a.checkOCRModel(a.ocrModel(a.resolveProfile(item.FilePath))); err != nil {
+			return err
+		}
+	}
+
+	a.emit("queue-item-started", map[string]interface{}{
+		"id":          item.ID,
+		"document_id": session.ID,
+		"file_path":   item.FilePath,
+		"total":       len(pages),
+	})
+	a.emitQueueUpdated()
+
+	if item.TaskType == string(jobs.TaskAI) {
+		taskID, ctx := a.startTask(TaskKindBatchAI, session, pages)
+		defer a.tasks.finish(taskID)
+		return a.processWithAIBatch(ctx, session, pages, item.Prompt, item.ForceReprocess, item.ContextMode, item.RollingContext, nil, nil)
+	}
+	taskID, ctx := a.startTask(TaskKindBatchOCR, session, pages)
+	defer a.tasks.finish(taskID)
+	return a.processPagesBatch(ctx, session, pages, item.ForceReprocess, nil, nil)
+}
+
+// emitQueueUpdated 通知前端队列变化
+func (a *App) emitQueueUpdated() {
+	a.emit("queue-updated", a.queue.snapshot())
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/reflow"
+	"pdf-ocr-ai/pkg/stitch"
+)
+
+// ExportReflowedPDF 将当前文档的处理结果重新排版为新的PDF（不含原页面图片），排除的页面除外
+// 各页文本先拼接为连续文本（删除页眉页脚和页码、合并跨页断句），再按 options 的纸张、字体和页边距排版
+// textType: ocr 只用OCR文本，ai 只用AI处理文本，其他值优先OCR文本；output 为空时弹出保存对话框
+func (a *App) ExportReflowedPDF(textType string, options reflow.Options, output string) (string, error) {
+	doc := a.activeDocument()
+	if doc == nil {
+		return "", i18n.Errorf("doc.not_loaded")
+	}
+
+	stitched := stitch.Pages(documentPageTexts(doc, textType, a.exportApprovedOnly()))
+	text := convertChinese(stitched.Text, a.configManager.GetConfig().ChineseConversion.Export)
+	blocks := reflow.Parse(text)
+	if len(blocks) == 0 {
+		return "", fmt.Errorf("文档没有可排版的文本，请先处理页面")
+	}
+
+	var err error
+	if output == "" {
+		baseName := strings.TrimSuffix(filepath.Base(doc.FilePath), filepath.Ext(doc.FilePath))
+		output, err = runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+			DefaultFilename: baseName + "-reflowed.pdf",
+			Filters:         []runtime.FileFilter{{DisplayName: "PDF", Pattern: "*.pdf"}},
+			Title:           "导出重排版PDF",
+		})
+		if err != nil || output == "" {
+			return "", err
+		}
+	}
+	if sameFile(output, doc.FilePath) {
+		return "", fmt.Errorf("不能覆盖原文档: %s", output)
+	}
+
+	// 排版成功后再写入，避免失败时留下不完整的文件
+	var buf bytes.Buffer
+	if err := reflow.Render(blocks, &buf, options); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(output, buf.Bytes(), 0644); err != nil {
+		return "", fmt.Errorf("保存PDF失败: %w", err)
+	}
+
+	logger.Infof("已导出重排版PDF（%d 个段落）到 %s", len(blocks), output)
+	return output, nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"unicode/utf16"
+
+	"pdf-ocr-ai/pkg/i18n"
+	imageprocessor "pdf-ocr-ai/pkg/image"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/postprocess"
+	"pdf-ocr-ai/pkg/revisions"
+)
+
+// PageRegion 页面上的矩形区域，坐标和尺寸为相对页面宽高的比例（0-1）
+type PageRegion struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// RecognizePageRegion 裁剪当前文档页面的指定区域并只识别该区域，返回识别结果，不修改页面文本
+// 识别结果经过处理配置的后处理规则，可再通过 SplicePageText 插入页面文本
+func (a *App) RecognizePageRegion(pageNumber int, region PageRegion) (string, error) {
+	doc := a.activeDocument()
+	if doc == nil {
+		return "", i18n.Errorf("doc.not_loaded")
+	}
+	if pageNumber < 1 || pageNumber > len(doc.Pages) {
+		return "", i18n.Errorf("page.out_of_range")
+	}
+	if a.ocrClient == nil {
+		return "", i18n.Errorf("ai.not_configured")
+	}
+
+	profile := a.resolveProfile(doc.FilePath)
+	imagePath, cleanup, err := a.renderPageForOCR(doc, pageNumber, profile)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	tmp, err := os.CreateTemp("", "pdfseer-region-*.jpg")
+	if err != nil {
+		return "", i18n.Errorf("file.temp_failed", err)
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := imageprocessor.CropRegion(imagePath, tmp.Name(), region.X, region.Y, region.Width, region.Height); err != nil {
+		return "", fmt.Errorf("裁剪第%d页区域失败: %w", pageNumber, err)
+	}
+
+	result, err := a.ocrClient.RecognizeImage(a.ctx, tmp.Name(), profileOCRModel(profile))
+	if err != nil {
+		return "", fmt.Errorf("OCR识别失败: %w", err)
+	}
+	if result.Error != "" {
+		return "", fmt.Errorf("OCR识别错误: %s", result.Error)
+	}
+
+	logger.Infof("重新识别第%d页区域 (%.2f, %.2f, %.2f, %.2f)", pageNumber, region.X, region.Y, region.Width, region.Height)
+	return a.postProcessResult(doc, postprocess.TargetOCR, result.Text), nil
+}
+
+// SplicePageText 用 text 替换页面文本中 [start, end) 范围的内容（start 等于 end 时插入），保存为新的修订版本
+// start、end 为前端文本框中的位置（UTF-16 编码单位），返回修改后的页面文本
+func (a *App) SplicePageText(pageNumber int, textType string, start, end int, text string) (string, error) {
+	doc := a.activeDocument()
+	if doc == nil {
+		return "", i18n.Errorf("doc.not_loaded")
+	}
+	if pageNumber < 1 || pageNumber > len(doc.Pages) {
+		return "", i18n.Errorf("page.out_of_range")
+	}
+
+	a.mu.RLock()
+	current := doc.Pages[pageNumber-1].OCRText
+	if textType == "ai" {
+		current = doc.Pages[pageNumber-1].AIText
+	}
+	a.mu.RUnlock()
+
+	units := utf16.Encode([]rune(current))
+	if start < 0 || end < start || end > len(units) {
+		return "", fmt.Errorf("插入位置超出文本范围")
+	}
+	spliced := string(utf16.Decode(units[:start])) + text + string(utf16.Decode(units[end:]))
+
+	if err := a.updatePageText(pageNumber, textType, spliced, revisions.SourceRegion); err != nil {
+		return "", err
+	}
+	return spliced, nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"pdf-ocr-ai/pkg/config"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/pdf"
+	"pdf-ocr-ai/pkg/remote"
+)
+
+// remoteFile 待上传的文件
+type remoteFile struct {
+	Name string // 远程目录下的文件名
+	Data []byte
+}
+
+// uploadBatchResults 批处理完成后按配置的格式导出文档结果并上传到远程存储，未启用时不做任何事
+func (a *App) uploadBatchResults(doc *pdf.PDFDocument, preferAI bool) {
+	cfg := a.configManager.GetConfig().RemoteUpload
+	if !cfg.Enabled {
+		return
+	}
+
+	ext := ".txt"
+	switch cfg.Format {
+	case "markdown":
+		ext = ".md"
+	case "html":
+		ext = ".html"
+	}
+	content := formatDocumentText(doc, allPageNumbers(doc.PageCount), cfg.Format, preferAI, a.exportApprovedOnly())
+	baseName := strings.TrimSuffix(filepath.Base(doc.FilePath), filepath.Ext(doc.FilePath))
+
+	if err := a.uploadToRemote(cfg, []remoteFile{{Name: baseName + ext, Data: []byte(content)}}); err != nil {
+		logger.Errorf("上传处理结果失败 %s: %v", doc.FilePath, err)
+	}
+}
+
+// uploadToRemote 逐个上传文件，失败时按配置重试，发送 remote-upload-progress 和 remote-upload-complete 事件
+// 部分文件上传失败时继续上传其余文件，返回的错误包含所有失败的文件
+func (a *App) uploadToRemote(cfg config.RemoteUploadConfig, files []remoteFile) error {
+	uploader, err := remote.New(cfg)
+	if err != nil {
+		return err
+	}
+
+	var uploaded []string
+	failed := map[string]string{}
+	for i, file := range files {
+		a.emit("remote-upload-progress", map[string]interface{}{
+			"current": i + 1,
+			"total":   len(files),
+			"file":    file.Name,
+		})
+		if err := remote.UploadWithRetry(a.ctx, uploader, file.Name, file.Data, cfg.Retries); err != nil {
+			logger.Warnf("上传 %s 到 %s 失败: %v", file.Name, cfg.Type, err)
+			failed[file.Name] = err.Error()
+			continue
+		}
+		uploaded = append(uploaded, file.Name)
+	}
+
+	a.emit("remote-upload-complete", map[string]interface{}{
+		"uploaded": uploaded,
+		"failed":   failed,
+	})
+	logger.Infof("上传到远程存储(%s): %d 个成功, %d 个失败", cfg.Type, len(uploaded), len(failed))
+
+	if len(failed) > 0 {
+		names := make([]string, 0, len(failed))
+		for name := range failed {
+			names = append(names, name)
+		}
+		return fmt.Errorf("%d 个文件上传失败: %s", len(failed), strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// TestRemoteUpload 上传一个测试文件（pdfseer-test.txt），验证远程存储配置
+func (a *App) TestRemoteUpload() error {
+	cfg := a.configManager.GetConfig().RemoteUpload
+	uploader, err := remote.New(cfg)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(a.ctx, 2*time.Minute)
+	defer cancel()
+	content := fmt.Sprintf("pdfSeer 远程存储测试文件 %s\n", time.Now().Format(time.RFC3339))
+	return uploader.Upload(ctx, "pdfseer-test.txt", []byte(content))
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"pdf-ocr-ai/pkg/config"
+	"pdf-ocr-ai/pkg/history"
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/pdf"
+)
+
+// applyRenderConfig 应用页面渲染的并发数和 libvips 缓存设置
+func (a *App) applyRenderConfig(cfg config.RenderConfig) {
+	pdf.ConfigureVips(pdf.VipsSettings{
+		RenderConcurrency: cfg.Concurrency,
+		CacheMaxOps:       cfg.CacheMaxOps,
+		CacheMaxMemMB:     cfg.CacheMaxMemMB,
+		Threads:           cfg.Threads,
+	})
+}
+
+// renderedPage 渲染协程的输出，交给识别协程处理
+type renderedPage struct {
+	pageNum   int
+	imagePath string
+	cleanup   func()
+	elapsed   time.Duration  // 渲染（或读取缓存）耗时
+	result    *ProcessResult // 缓存命中或渲染失败时已有结果，不需要识别
+	hybrid    *hybridPage    // 混合模式下使用文本层的页面，只识别插图
+}
+
+// renderBatchPage 批量处理的渲染阶段：有缓存时直接使用缓存，混合模式下文本层完整的页面不渲染，否则按处理配置渲染页面
+func (a *App) renderBatchPage(doc *pdf.PDFDocument, pageNum int, profile *config.ProcessingProfile, historyRecord *history.HistoryRecord, forceReprocess bool) renderedPage {
+	startedAt := time.Now()
+	rendered := renderedPage{pageNum: pageNum, cleanup: func() {}}
+
+	if !forceReprocess && a.loadCachedPage(doc, pageNum, historyRecord, startedAt) {
+		rendered.result = &ProcessResult{PageNumber: pageNum, Status: i18n.T("status.from_cache")}
+	} else if hybrid := a.prepareHybridPage(doc, pageNum); hybrid != nil {
+		rendered.hybrid, rendered.cleanup = hybrid, hybrid.cleanup
+	} else if imagePath, cleanup, err := a.renderPageForOCR(doc, pageNum, profile); err != nil {
+		rendered.result = &ProcessResult{PageNumber: pageNum, Status: i18n.T("status.failed"), Error: err}
+	} else {
+		rendered.imagePath, rendered.cleanup = imagePath, cleanup
+	}
+
+	rendered.elapsed = time.Since(startedAt)
+	return rendered
+}
+
+// recognizeBatchPage 批量处理的识别阶段，耗时包含渲染和识别，不含在队列中等待的时间
+func (a *App) recognizeBatchPage(ctx context.Context, session *DocumentSession, doc *pdf.PDFDocument, rendered renderedPage, profile *config.ProcessingProfile, historyRecord *history.HistoryRecord) ProcessResult {
+	defer rendered.cleanup()
+
+	if rendered.result != nil {
+		result := *rendered.result
+		result.Duration = rendered.elapsed
+		return result
+	}
+
+	// 渲染完成后批次被暂停时，等继续后再调用API
+	if !session.waitIfPaused(ctx) {
+		return ProcessResult{PageNumber: rendered.pageNum, Status: i18n.T("status.cancelled"), Error: context.Canceled}
+	}
+
+	startedAt := time.Now()
+	var err error
+	if rendered.hybrid != nil {
+		err = a.recognizeHybridPage(ctx, doc, rendered.pageNum, rendered.hybrid, profile, historyRecord, startedAt.Add(-rendered.elapsed))
+	} else {
+		err = a.recognizePage(ctx, doc, rendered.pageNum, rendered.imagePath, profile, historyRecord, startedAt.Add(-rendered.elapsed))
+	}
+	status := i18n.T("status.done")
+	if err != nil {
+		status = i18n.T("status.failed")
+	}
+	return ProcessResult{
+		PageNumber: rendered.pageNum,
+		Status:     status,
+		Error:      err,
+		Duration:   rendered.elapsed + time.Since(startedAt),
+	}
+}
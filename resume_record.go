@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"pdf-ocr-ai/pkg/history"
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/jobs"
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// IncompleteRecord 未处理完的历史记录（应用退出或崩溃时仍在处理中）
+type IncompleteRecord struct {
+	HistoryID    int                      `json:"history_id"`
+	TaskType     history.TaskType         `json:"task_type"`
+	Status       history.ProcessingStatus `json:"status"`
+	Model        string                   `json:"model"`
+	ProcessedAt  string                   `json:"processed_at"`
+	Total        int                      `json:"total"`
+	Done         int                      `json:"done"`
+	MissingPages []int                    `json:"missing_pages"` // 尚未完成的页码
+}
+
+// GetIncompleteRecords 获取文档中处于处理中或暂停状态、但当前没有在运行的历史记录
+func (a *App) GetIncompleteRecords(documentPath string) ([]IncompleteRecord, error) {
+	records, err := a.historyManager.GetRecordsByDocumentPath(documentPath)
+	if err != nil {
+		return nil, i18n.Errorf("history.get_failed", err)
+	}
+
+	running := a.runningHistoryIDs()
+	var incomplete []IncompleteRecord
+	for _, record := range records {
+		if record.Status != history.StatusProcessing && record.Status != history.StatusPaused {
+			continue
+		}
+		if running[record.ID] {
+			continue
+		}
+
+		progress, err := a.historyManager.GetRecordProgress(record.ID)
+		if err != nil {
+			logger.Warnf("获取记录%d的进度失败: %v", record.ID, err)
+			continue
+		}
+		if len(progress.PendingPages) == 0 {
+			continue
+		}
+
+		incomplete = append(incomplete, IncompleteRecord{
+			HistoryID:    record.ID,
+			TaskType:     record.TaskType,
+			Status:       record.Status,
+			Model:        record.Model,
+			ProcessedAt:  record.ProcessedAt,
+			Total:        progress.Total,
+			Done:         progress.Done + progress.SkippedCache,
+			MissingPages: progress.PendingPages,
+		})
+	}
+	return incomplete, nil
+}
+
+// ResumeRecord 继续处理未完成记录中尚未完成的页面，结果写入同一条记录，返回任务ID
+func (a *App) ResumeRecord(historyID int) (string, error) {
+	record, err := a.historyManager.GetRecord(historyID)
+	if err != nil {
+		return "", i18n.Errorf("history.get_failed", err)
+	}
+	if record == nil {
+		return "", i18n.Errorf("history.not_found")
+	}
+	if record.Status != history.StatusProcessing && record.Status != history.StatusPaused {
+		return "", fmt.Errorf("记录已结束: %s", record.Status)
+	}
+	if a.runningHistoryIDs()[historyID] {
+		return "", i18n.Errorf("record.busy")
+	}
+
+	progress, err := a.historyManager.GetRecordProgress(historyID)
+	if err != nil {
+		return "", err
+	}
+	if len(progress.PendingPages) == 0 {
+		return "", fmt.Errorf("记录没有未完成的页面")
+	}
+
+	documentID, err := a.OpenDocument(record.DocumentPath)
+	if err != nil {
+		return "", i18n.Errorf("doc.load_failed", err)
+	}
+	session, err := a.getSession(documentID)
+	if err != nil {
+		return "", err
+	}
+	if session.busy() {
+		return "", i18n.Errorf("doc.busy")
+	}
+
+	// 记录对应的中断任务一起继续，避免之后恢复中断任务时再次处理同样的页面
+	job, err := a.recordJob(historyID)
+	if err != nil && record.TaskType == history.TaskTypeAI {
+		return "", err
+	}
+	if job != nil {
+		if err := a.jobManager.UpdateStatus(job.ID, jobs.StatusRunning, ""); err != nil {
+			return "", fmt.Errorf("更新任务状态失败: %w", err)
+		}
+	}
+
+	logger.Infof("继续处理记录%d的未完成页面: %v", historyID, progress.PendingPages)
+
+	switch record.TaskType {
+	case history.TaskTypeAI:
+		return a.startAIBatch(session, progress.PendingPages, job.Prompt, false, job.ContextMode, job.RollingContext, job, record), nil
+	default:
+		return a.startPagesBatch(session, progress.PendingPages, false, job, record), nil
+	}
+}
+
+// checkIncompleteRecords 打开文档时检查未处理完的记录，有则通知前端提示继续处理
+func (a *App) checkIncompleteRecords(session *DocumentSession) {
+	if a.historyManager == nil || strings.HasPrefix(session.ID, "temp-") {
+		return
+	}
+
+	incomplete, err := a.GetIncompleteRecords(session.Doc.FilePath)
+	if err != nil {
+		logger.Warnf("检查未完成的记录失败: %v", err)
+		return
+	}
+	if len(incomplete) == 0 {
+		return
+	}
+
+	logger.Infof("文档 %s 有 %d 条未处理完的记录", session.Doc.FilePath, len(incomplete))
+	a.emit("incomplete-records", map[string]interface{}{
+		"document_id": session.ID,
+		"records":     incomplete,
+	})
+}
+
+// runningHistoryIDs 获取各文档当前批次正在写入的历史记录ID
+func (a *App) runningHistoryIDs() map[int]bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	ids := make(map[int]bool)
+	for _, session := range a.sessions {
+		session.processingMu.Lock()
+		if session.historyID != 0 {
+			ids[session.historyID] = true
+		}
+		session.processingMu.Unlock()
+	}
+	return ids
+}
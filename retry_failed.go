@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+
+	"pdf-ocr-ai/pkg/history"
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// RetryFailedPages 重新处理历史记录中失败的页面，结果追加到同一条记录，返回任务ID
+// 文档未打开时先打开记录对应的文档；AI处理沿用记录最初的提示词和上下文设置
+func (a *App) RetryFailedPages(historyID int) (string, error) {
+	record, err := a.historyManager.GetRecord(historyID)
+	if err != nil {
+		return "", i18n.Errorf("history.get_failed", err)
+	}
+	if record == nil {
+		return "", i18n.Errorf("history.not_found")
+	}
+	if record.Status == history.StatusProcessing {
+		return "", i18n.Errorf("record.busy")
+	}
+
+	progress, err := a.historyManager.GetRecordProgress(historyID)
+	if err != nil {
+		return "", err
+	}
+	if len(progress.FailedPages) == 0 {
+		return "", fmt.Errorf("没有处理失败的页面")
+	}
+
+	documentID, err := a.OpenDocument(record.DocumentPath)
+	if err != nil {
+		return "", i18n.Errorf("doc.load_failed", err)
+	}
+	session, err := a.getSession(documentID)
+	if err != nil {
+		return "", err
+	}
+	if session.getState() != ProcessingStateIdle {
+		return "", i18n.Errorf("doc.busy")
+	}
+
+	logger.Infof("重试记录%d的失败页面: %v", historyID, progress.FailedPages)
+
+	switch record.TaskType {
+	case history.TaskTypeAI:
+		job, err := a.recordJob(historyID)
+		if err != nil {
+			return "", err
+		}
+		return a.startAIBatch(session, progress.FailedPages, job.Prompt, true, job.ContextMode, job.RollingContext, nil, record), nil
+	default:
+		return a.startPagesBatch(session, progress.FailedPages, true, nil, record), nil
+	}
+}
+
+// reopenHistoryRecord 重试前把已结束的记录重新标记为处理中
+func (a *App) reopenHistoryRecord(record *history.HistoryRecord) {
+	if err := a.historyManager.UpdateRecordStatus(record.ID, history.StatusProcessing, ""); err != nil {
+		logger.Errorf("更新历史记录状态失败: %v", err)
+	}
+}
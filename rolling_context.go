@@ -0,0 +1,84 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/ocr"
+)
+
+// 滚动上下文的长度限制（tokens）：最近一页保留末尾较长的部分，更早的页面只保留开头摘录，总长度超出时丢弃最早的页面
+const (
+	rollingContextMaxTokens    = 2000
+	rollingLatestExcerptTokens = 800
+	rollingOlderExcerptTokens  = 120
+)
+
+// rollingEntry 已处理页面结果的摘录：作为最近一页时使用末尾部分，作为更早的页面时使用开头部分
+type rollingEntry struct {
+	page int
+	head string
+	tail string
+}
+
+// rollingContextWindow 顺序批量AI处理时维护已处理页面的压缩摘录，nil 表示未启用
+// 优先处理的页面会打乱处理顺序，因此摘录按页码排序保存，生成时只使用当前页之前的页面
+type rollingContextWindow struct {
+	entries []rollingEntry
+}
+
+// add 记录页面的处理结果
+func (w *rollingContextWindow) add(page int, result string) {
+	if w == nil {
+		return
+	}
+	result = strings.TrimSpace(result)
+	if result == "" {
+		return
+	}
+	entry := rollingEntry{page: page, head: result, tail: result}
+	if ocr.EstimateTokens(result) > rollingOlderExcerptTokens {
+		entry.head = ocr.HeadTokens(result, rollingOlderExcerptTokens) + "……"
+	}
+	if ocr.EstimateTokens(result) > rollingLatestExcerptTokens {
+		entry.tail = "……" + ocr.TailTokens(result, rollingLatestExcerptTokens)
+	}
+	i := sort.Search(len(w.entries), func(i int) bool { return w.entries[i].page >= page })
+	if i < len(w.entries) && w.entries[i].page == page {
+		w.entries[i] = entry
+		return
+	}
+	w.entries = append(w.entries, rollingEntry{})
+	copy(w.entries[i+1:], w.entries[i:])
+	w.entries[i] = entry
+}
+
+// prompt 生成页面 page 的前文摘录（只包含之前的页面），总长度超出限制时丢弃最早的页面，没有时返回空
+func (w *rollingContextWindow) prompt(page int) string {
+	if w == nil {
+		return ""
+	}
+	entries := w.entries[:sort.Search(len(w.entries), func(i int) bool { return w.entries[i].page >= page })]
+	if len(entries) == 0 {
+		return ""
+	}
+	for len(entries) > 1 && ocr.EstimateTokens(renderRolling(entries)) > rollingContextMaxTokens {
+		entries = entries[1:]
+	}
+	return i18n.T("prompt.rolling_header") + "\n" + renderRolling(entries) + "\n"
+}
+
+// renderRolling 按页顺序输出摘录：最近一页取末尾部分，更早的页面取开头部分
+func renderRolling(entries []rollingEntry) string {
+	var b strings.Builder
+	last := len(entries) - 1
+	for i, entry := range entries {
+		text := entry.head
+		if i == last {
+			text = entry.tail
+		}
+		b.WriteString(i18n.T("prompt.rolling_entry", entry.page) + text + "\n\n")
+	}
+	return b.String()
+}
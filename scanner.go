@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/jobs"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/system"
+)
+
+// ListScanners 列出可用的扫描仪
+func (a *App) ListScanners() ([]system.Scanner, error) {
+	return system.ListScanners()
+}
+
+// AcquireFromScanner 从扫描仪扫描页面，合并为PDF保存到数据目录的 scans 中并打开，已配置AI时直接开始OCR识别
+// 返回打开的文档ID
+func (a *App) AcquireFromScanner(options system.ScanOptions) (string, error) {
+	tempDir, err := os.MkdirTemp("", "pdfseer-scan-*")
+	if err != nil {
+		return "", i18n.Errorf("file.temp_failed", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	a.emit("scanner-started", map[string]interface{}{"source": options.Source})
+	logger.Infof("开始扫描: 设备=%q 分辨率=%d 模式=%s 来源=%s", options.Device, options.Resolution, options.Mode, options.Source)
+
+	pages, err := system.Scan(tempDir, options)
+	if err != nil {
+		return "", err
+	}
+
+	scanDir, err := a.scanDir()
+	if err != nil {
+		return "", err
+	}
+	pdfPath := filepath.Join(scanDir, fmt.Sprintf("scan-%s.pdf", time.Now().Format("20060102-150405")))
+	if err := api.ImportImagesFile(pages, pdfPath, nil, nil); err != nil {
+		return "", fmt.Errorf("生成扫描文档失败: %w", err)
+	}
+	logger.Infof("已扫描 %d 页，保存到 %s", len(pages), pdfPath)
+
+	documentID, err := a.OpenDocument(pdfPath)
+	if err != nil {
+		return "", err
+	}
+	if session, err := a.getSession(documentID); err == nil && a.ocrClient != nil {
+		a.startOrQueueBatch(session, QueueRequest{Pages: allPageNumbers(session.Doc.PageCount), TaskType: string(jobs.TaskOCR)})
+	}
+	return documentID, nil
+}
+
+// scanDir 获取保存扫描文档的目录（数据目录下的 scans），不存在时创建
+func (a *App) scanDir() (string, error) {
+	dir := filepath.Join(os.TempDir(), "pdfseer-scans")
+	if a.store != nil {
+		dir = filepath.Join(a.store.DataDir(), "scans")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("创建扫描目录失败: %w", err)
+	}
+	return dir, nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"pdf-ocr-ai/pkg/config"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/scheduler"
+)
+
+// 定时任务类型
+const (
+	scheduleTaskCleanup    = "cleanup"     // 按存储配置清理过期缓存和历史记录
+	scheduleTaskWatchSweep = "watch_sweep" // 立即扫描监视文件夹
+	scheduleTaskReExport   = "re_export"   // 重新导出工作区中所有文档的结果
+)
+
+// applyScheduleConfig 根据配置重建定时任务
+func (a *App) applyScheduleConfig(schedules []config.ScheduleConfig) error {
+	tasks := make([]scheduler.Task, 0, len(schedules))
+	for _, sc := range schedules {
+		if !sc.Enabled {
+			continue
+		}
+
+		run, err := a.scheduledTaskFunc(sc)
+		if err != nil {
+			return err
+		}
+
+		id := sc.ID
+		if id == "" {
+			id = sc.Name
+		}
+		tasks = append(tasks, scheduler.Task{
+			ID:   id,
+			Name: sc.Name,
+			Cron: sc.Cron,
+			Kind: sc.Task,
+			Run:  run,
+		})
+	}
+
+	if err := a.scheduler.SetTasks(tasks); err != nil {
+		return err
+	}
+	a.scheduler.Start()
+	return nil
+}
+
+// scheduledTaskFunc 获取定时任务的执行函数
+func (a *App) scheduledTaskFunc(sc config.ScheduleConfig) (func() error, error) {
+	switch sc.Task {
+	case scheduleTaskCleanup:
+		return func() error {
+			_, err := a.RunStorageMaintenance()
+			return err
+		}, nil
+	case scheduleTaskWatchSweep:
+		return a.runWatchSweep, nil
+	case scheduleTaskReExport:
+		params := sc.Params
+		return func() error {
+			return a.runReExport(params["output_dir"], params["format"])
+		}, nil
+	default:
+		return nil, fmt.Errorf("不支持的定时任务类型: %s", sc.Task)
+	}
+}
+
+// runWatchSweep 立即扫描监视文件夹
+func (a *App) runWatchSweep() error {
+	a.mu.RLock()
+	w := a.folderWatcher
+	a.mu.RUnlock()
+
+	if w == nil {
+		return fmt.Errorf("监视文件夹未启用")
+	}
+	w.ScanNow()
+	return nil
+}
+
+// runReExport 将工作区中所有文档的结果重新导出到输出目录
+func (a *App) runReExport(outputDir string, format string) error {
+	if outputDir == "" {
+		outputDir = a.configManager.GetConfig().WatchFolder.OutputDir
+	}
+	if outputDir == "" {
+		return fmt.Errorf("未设置导出目录")
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("创建导出目录失败: %w", err)
+	}
+
+	ext := ".txt"
+	switch format {
+	case "markdown":
+		ext = ".md"
+	case "html":
+		ext = ".html"
+	}
+
+	a.mu.RLock()
+	sessions := make([]*DocumentSession, 0, len(a.sessions))
+	for _, session := range a.sessions {
+		sessions = append(sessions, session)
+	}
+	a.mu.RUnlock()
+
+	var files []remoteFile
+	for _, session := range sessions {
+		content := formatDocumentText(session.Doc, allPageNumbers(session.Doc.PageCount), format, false, a.exportApprovedOnly())
+		baseName := strings.TrimSuffix(filepath.Base(session.Doc.FilePath), filepath.Ext(session.Doc.FilePath))
+		outputPath := filepath.Join(outputDir, baseName+ext)
+		if err := os.WriteFile(outputPath, []byte(content), 0644); err != nil {
+			return fmt.Errorf("导出 %s 失败: %w", session.Doc.FilePath, err)
+		}
+		files = append(files, remoteFile{Name: baseName + ext, Data: []byte(content)})
+	}
+
+	logger.Infof("定时导出完成: %d 个文档 -> %s", len(sessions), outputDir)
+
+	// 启用远程存储时同时上传导出的文件
+	if cfg := a.configManager.GetConfig().RemoteUpload; cfg.Enabled && len(files) > 0 {
+		return a.uploadToRemote(cfg, files)
+	}
+	return nil
+}
+
+// GetScheduledTasks 获取定时任务及下次运行时间
+func (a *App) GetScheduledTasks() []scheduler.TaskInfo {
+	return a.scheduler.Tasks()
+}
+
+// RunScheduledTask 立即运行指定的定时任务
+func (a *App) RunScheduledTask(id string) error {
+	return a.scheduler.RunNow(id)
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/pdf"
+	"pdf-ocr-ai/pkg/system"
+)
+
+// selfTestOCRTimeout 自检OCR请求的超时时间
+const selfTestOCRTimeout = 30 * time.Second
+
+// RunSelfTest 执行完整自检：依赖、样例页面渲染、OCR往返、数据目录写入和磁盘空间
+// includeOCR 为false时跳过OCR往返，避免每次启动都消耗API调用
+func (a *App) RunSelfTest(includeOCR bool) *system.SelfTestReport {
+	report := system.NewSelfTestReport()
+
+	for _, item := range system.DependencySelfTestItems(system.CheckDependencies()) {
+		report.Add(item)
+	}
+
+	dataDir := a.selfTestDataDir()
+	report.Add(system.CheckDataDirWritable(dataDir))
+	report.Add(system.CheckDiskSpace(dataDir))
+
+	var samplePath string
+	report.Add(system.RunSelfTestItem("样例页面渲染", func(item *system.SelfTestItem) {
+		samplePath = a.selfTestRender(item)
+	}))
+	if samplePath != "" {
+		defer os.Remove(samplePath)
+	}
+
+	report.Add(system.RunSelfTestItem("OCR往返", func(item *system.SelfTestItem) {
+		a.selfTestOCR(item, samplePath, includeOCR)
+	}))
+
+	return report
+}
+
+// runStartupSelfTest 启动时在后台执行自检（不含OCR往返），并把结果发送到前端
+func (a *App) runStartupSelfTest() {
+	report := a.RunSelfTest(false)
+	logger.Infof("启动自检结果:\n%s", system.FormatSelfTestReport(report))
+	a.emit("self-test", report)
+}
+
+// selfTestDataDir 获取自检使用的数据目录，数据库未打开时按默认位置推算
+func (a *App) selfTestDataDir() string {
+	if a.store != nil {
+		return a.store.DataDir()
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ".pdfSeer"
+	}
+	return filepath.Join(homeDir, ".pdfSeer")
+}
+
+// selfTestRender 渲染内置样例PDF，成功时返回图片路径
+func (a *App) selfTestRender(item *system.SelfTestItem) string {
+	if a.pdfProcessor == nil {
+		item.Status = system.SelfTestFail
+		item.Message = "PDF处理器未初始化"
+		item.Suggestion = "请查看日志中的初始化错误，修复后重启应用"
+		return ""
+	}
+
+	imagePath, err := a.pdfProcessor.RenderSample()
+	if err != nil {
+		item.Status = system.SelfTestFail
+		item.Message = err.Error()
+		item.Suggestion = "libvips 无法渲染PDF，请确认安装的 libvips 带有 PDF 支持（poppler 或 pdfium），安装后重启应用。\n" +
+			system.GetInstallInstructions()["libvips"]
+		return ""
+	}
+
+	item.Message = "libvips 渲染成功"
+	return imagePath
+}
+
+// selfTestOCR 用渲染出的样例图片向配置的API发送一次OCR请求，并核对识别结果
+func (a *App) selfTestOCR(item *system.SelfTestItem, samplePath string, includeOCR bool) {
+	switch {
+	case !includeOCR:
+		item.Status = system.SelfTestSkip
+		item.Message = "启动自检不调用API，可在设置中手动运行完整自检"
+		return
+	case a.ocrClient == nil:
+		item.Status = system.SelfTestFail
+		item.Message = "未配置AI服务"
+		item.Suggestion = "请在设置的「AI配置」中填写API地址、密钥和OCR模型"
+		return
+	case samplePath == "":
+		item.Status = system.SelfTestSkip
+		item.Message = "样例页面渲染失败，无法进行OCR测试"
+		item.Suggestion = "请先解决样例页面渲染的问题"
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(a.ctx, selfTestOCRTimeout)
+	defer cancel()
+
+	result, err := a.ocrClient.RecognizeImage(ctx, samplePath, "")
+	if err != nil {
+		item.Status = system.SelfTestFail
+		item.Message = err.Error()
+		item.Suggestion = ocrFailureSuggestion(err)
+		return
+	}
+
+	text := strings.TrimSpace(result.Text)
+	if !strings.Contains(strings.ToLower(text), strings.ToLower(pdf.SampleText)) {
+		item.Status = system.SelfTestWarn
+		item.Message = fmt.Sprintf("识别结果与样例文字不一致: %q", text)
+		item.Suggestion = fmt.Sprintf("样例页面上的文字是 %q。请确认OCR模型支持图片输入，可在设置中检测模型能力或换用视觉模型", pdf.SampleText)
+		return
+	}
+
+	item.Message = fmt.Sprintf("识别结果: %s", text)
+}
+
+// ocrFailureSuggestion 根据OCR请求的错误给出修复建议
+func ocrFailureSuggestion(err error) string {
+	errStr := err.Error()
+	switch {
+	case errors.Is(err, context.DeadlineExceeded) || strings.Contains(errStr, "timeout"):
+		return "请求超时，请检查网络连接和API地址，或在设置中增大超时时间"
+	case strings.Contains(errStr, "状态码 401") || strings.Contains(errStr, "状态码 403"):
+		return "API密钥无效或没有权限，请在设置中检查API密钥"
+	case strings.Contains(errStr, "状态码 404"):
+		return "API地址或模型名称不正确，请检查API地址是否包含正确的路径（如 /v1）以及OCR模型名称"
+	case strings.Contains(errStr, "状态码 429"):
+		return "API请求过于频繁或额度不足，请稍后重试或在设置中降低请求频率"
+	case strings.Contains(errStr, "状态码 400") || strings.Contains(errStr, "状态码 415") || strings.Contains(errStr, "状态码 422"):
+		return "服务拒绝了图片输入，OCR模型可能不支持视觉识别，请换用视觉模型"
+	case strings.Contains(errStr, "connection refused") || strings.Contains(errStr, "no such host"):
+		return "无法连接到API服务，请检查API地址，本地服务请确认已经启动"
+	default:
+		return "请检查AI配置中的API地址、密钥和OCR模型，并确认网络可以访问该服务"
+	}
+}
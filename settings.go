@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"pdf-ocr-ai/pkg/config"
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// ExportSettings 导出配置以便迁移到其他电脑，path为空时弹出保存对话框，返回保存路径
+// includeAPIKeys 为 false 时不导出API密钥等凭据
+func (a *App) ExportSettings(path string, includeAPIKeys bool) (string, error) {
+	if path == "" {
+		var err error
+		path, err = runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+			DefaultFilename: fmt.Sprintf("pdfseer-settings-%s.json", time.Now().Format("20060102")),
+			Filters:         []runtime.FileFilter{{DisplayName: "配置文件", Pattern: "*.json"}},
+			Title:           "导出配置",
+		})
+		if err != nil || path == "" {
+			return "", err
+		}
+	}
+
+	if err := a.configManager.ExportSettings(path, includeAPIKeys); err != nil {
+		return "", err
+	}
+
+	logger.Infof("已导出配置到 %s (包含API密钥: %v)", path, includeAPIKeys)
+	return path, nil
+}
+
+// ImportSettings 导入配置并立即生效，path为空时弹出选择对话框，返回导入后的配置（取消时为nil）
+// 导入文件中没有的API密钥保留当前值
+func (a *App) ImportSettings(path string) (*config.AppConfig, error) {
+	if path == "" {
+		var err error
+		path, err = runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+			Filters: []runtime.FileFilter{{DisplayName: "配置文件", Pattern: "*.json"}},
+			Title:   "导入配置",
+		})
+		if err != nil || path == "" {
+			return nil, err
+		}
+	}
+
+	cfg, err := a.configManager.ReadSettings(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.UpdateConfig(cfg); err != nil {
+		return nil, fmt.Errorf("应用导入的配置失败: %w", err)
+	}
+
+	logger.Infof("已从 %s 导入配置", path)
+	applied := a.configManager.GetConfig()
+	return &applied, nil
+}
+
+// ResetToDefaults 将指定分区（如 ai、storage、watch_folder）恢复为默认值，section 为空时恢复全部配置
+// API密钥始终保留，返回恢复后的配置
+func (a *App) ResetToDefaults(section string) (*config.AppConfig, error) {
+	cfg, err := config.ResetSection(a.configManager.GetConfig(), section)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.UpdateConfig(cfg); err != nil {
+		return nil, fmt.Errorf("恢复默认配置失败: %w", err)
+	}
+
+	if section == "" {
+		logger.Infof("已将全部配置恢复为默认值")
+	} else {
+		logger.Infof("已将 %s 配置恢复为默认值", section)
+	}
+	applied := a.configManager.GetConfig()
+	return &applied, nil
+}
+
+// GetConfigSections 获取可单独恢复默认值的配置分区
+func (a *App) GetConfigSections() []string {
+	return config.Sections
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"pdf-ocr-ai/pkg/config"
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/pdf"
+	"pdf-ocr-ai/pkg/stitch"
+)
+
+// defaultSummaryPrompt 未指定提示词时的全文摘要提示词
+const defaultSummaryPrompt = "请阅读以下文档全文，概括其主要内容：先用一段话总结全文，再按章节或主题列出要点。使用Markdown格式输出。"
+
+// StitchTexts 将按页顺序排列的文本拼接为连续文本（删除页眉页脚、合并跨页断句），用于导出
+func (a *App) StitchTexts(texts []string) stitch.Result {
+	return stitch.Pages(texts)
+}
+
+// CleanExportTexts 按导出文本清理配置删除各页的页眉页脚和页码，并按导出的简繁转换配置转换
+// texts 为按页顺序排列的文本，返回与输入一一对应的清理结果
+func (a *App) CleanExportTexts(texts []string) []string {
+	return a.convertExportTexts(stitch.Clean(texts, a.configManager.GetConfig().TextCleanup).Pages)
+}
+
+// PreviewTextCleanup 预览按指定（可能尚未保存的）清理配置会从当前文档删除的内容
+func (a *App) PreviewTextCleanup(textType string, cfg config.TextCleanupConfig) (*stitch.CleanResult, error) {
+	doc := a.activeDocument()
+	if doc == nil {
+		return nil, i18n.Errorf("doc.not_loaded")
+	}
+	cfg.Enabled = true
+	result := stitch.Clean(documentPageTexts(doc, textType, false), cfg)
+	return &result, nil
+}
+
+// GetStitchedText 获取当前文档拼接后的连续文本
+// textType: ocr 只用OCR文本，ai 只用AI处理文本，其他值优先OCR文本，其次AI文本，最后原生文本
+func (a *App) GetStitchedText(textType string) (*stitch.Result, error) {
+	doc := a.activeDocument()
+	if doc == nil {
+		return nil, i18n.Errorf("doc.not_loaded")
+	}
+
+	result := stitch.Pages(documentPageTexts(doc, textType, false))
+	if strings.TrimSpace(result.Text) == "" {
+		return nil, fmt.Errorf("文档没有可拼接的文本，请先处理页面")
+	}
+	return &result, nil
+}
+
+// SummarizeDocument 使用AI总结当前文档全文，prompt 为空时使用默认的摘要提示词
+// 全文先拼接为连续文本，超出模型上下文时按段处理
+func (a *App) SummarizeDocument(prompt string) (string, error) {
+	if a.ocrClient == nil {
+		return "", i18n.Errorf("ai.not_configured")
+	}
+	stitched, err := a.GetStitchedText("")
+	if err != nil {
+		return "", err
+	}
+	if prompt == "" {
+		prompt = defaultSummaryPrompt
+	}
+
+	logger.Infof("开始总结文档全文（%d 字符，删除页眉页脚和页码 %d 行，合并跨页断句 %d 处）",
+		len(stitched.Text), len(stitched.Removed), stitched.MergedBreaks)
+	summary, err := a.processTextWithGlossary(a.ctx, stitched.Text, prompt)
+	if err != nil {
+		return "", i18n.Errorf("ai.failed_err", err)
+	}
+	return summary, nil
+}
+
+// documentPageTexts 按页码顺序获取文档各页的文本，排除的页面为空
+// approvedOnly 为 true 时未审核通过的页面也为空
+func documentPageTexts(doc *pdf.PDFDocument, textType string, approvedOnly bool) []string {
+	texts := make([]string, 0, len(doc.Pages))
+	for _, page := range doc.Pages {
+		if !exportsPage(page, approvedOnly) {
+			texts = append(texts, "") // 保持按页码对齐
+			continue
+		}
+		texts = append(texts, pageText(page, textType))
+	}
+	return texts
+}
+
+// pageText 获取页面文本
+// textType: ocr 只用OCR文本，ai 只用AI处理文本，其他值优先OCR文本，其次AI文本，最后原生文本
+func pageText(page *pdf.PDFPage, textType string) string {
+	switch textType {
+	case "ocr":
+		return page.OCRText
+	case "ai":
+		return page.AIText
+	}
+	if page.OCRText != "" {
+		return page.OCRText
+	}
+	if page.AIText != "" {
+		return page.AIText
+	}
+	return page.Text
+}
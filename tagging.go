@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/pdf"
+	"pdf-ocr-ai/pkg/tags"
+)
+
+// TagPages 提取当前文档指定页面的关键词和主题，返回成功标记的页面
+func (a *App) TagPages(pageNumbers []int) ([]*tags.PageTags, error) {
+	session := a.activeSession()
+	if session == nil {
+		return nil, i18n.Errorf("doc.not_loaded")
+	}
+	if a.ocrClient == nil {
+		return nil, i18n.Errorf("ai.not_configured")
+	}
+
+	results := make([]*tags.PageTags, 0, len(pageNumbers))
+	var lastErr error
+	for _, pageNum := range pageNumbers {
+		pageTags, err := a.tagPage(a.ctx, session.ID, session.Doc, pageNum)
+		if err != nil {
+			logger.Warnf("第%d页标签提取失败: %v", pageNum, err)
+			lastErr = err
+			continue
+		}
+		results = append(results, pageTags)
+	}
+	if len(results) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+
+	a.emit("page-tags-updated", map[string]interface{}{
+		"document_id": session.ID,
+		"pages":       len(results),
+	})
+	return results, nil
+}
+
+// GetDocumentTags 获取当前文档各页面的关键词和主题
+func (a *App) GetDocumentTags() ([]*tags.PageTags, error) {
+	session := a.activeSession()
+	if session == nil {
+		return nil, i18n.Errorf("doc.not_loaded")
+	}
+	return a.tagManager.GetDocumentTags(session.ID)
+}
+
+// GetTagIndex 获取当前文档的标签索引（标签及出现的页面），kind 为 keyword、topic 或空（全部）
+func (a *App) GetTagIndex(kind string) ([]tags.IndexEntry, error) {
+	session := a.activeSession()
+	if session == nil {
+		return nil, i18n.Errorf("doc.not_loaded")
+	}
+	return a.tagManager.Index(session.ID, kind)
+}
+
+// SearchTags 在所有已标记的文档中按标签查找页面
+func (a *App) SearchTags(query string) ([]tags.Match, error) {
+	return a.tagManager.Search(query)
+}
+
+// tagPage 调用AI提取页面的关键词和主题并保存
+func (a *App) tagPage(ctx context.Context, documentID string, doc *pdf.PDFDocument, pageNum int) (*tags.PageTags, error) {
+	if pageNum < 1 || pageNum > len(doc.Pages) {
+		return nil, i18n.Errorf("page.out_of_range")
+	}
+	page := doc.Pages[pageNum-1]
+	text := page.OCRText
+	if text == "" {
+		text = page.Text
+	}
+	if strings.TrimSpace(text) == "" {
+		return nil, i18n.Errorf("tag.no_text", pageNum)
+	}
+
+	response, err := a.ocrClient.ProcessText(ctx, text, tags.Prompt())
+	if err != nil {
+		return nil, i18n.Errorf("ai.failed_err", err)
+	}
+	keywords, topics, err := tags.ParseResponse(response)
+	if err != nil {
+		return nil, err
+	}
+
+	pageTags := &tags.PageTags{
+		DocumentID:   documentID,
+		DocumentPath: doc.FilePath,
+		PageNumber:   pageNum,
+		Keywords:     keywords,
+		Topics:       topics,
+		UpdatedAt:    time.Now(),
+	}
+	if err := a.tagManager.SetPageTags(pageTags); err != nil {
+		return nil, err
+	}
+	return pageTags, nil
+}
+
+// autoTagPage OCR完成后按配置自动标记页面，失败只记录日志
+func (a *App) autoTagPage(ctx context.Context, doc *pdf.PDFDocument, pageNum int) {
+	if !a.configManager.GetAIConfig().AutoTagging || a.ocrClient == nil {
+		return
+	}
+	documentID, err := a.cacheManager.GenerateDocumentID(doc.FilePath)
+	if err != nil {
+		logger.Warnf("生成文档ID失败，跳过标签提取: %v", err)
+		return
+	}
+	if _, err := a.tagPage(ctx, documentID, doc, pageNum); err != nil {
+		logger.Warnf("第%d页自动标签提取失败: %v", pageNum, err)
+	}
+}
+
+// topicIndex 获取文档的主题索引，用于导出；没有标签时返回nil
+func (a *App) topicIndex(doc *pdf.PDFDocument) []tags.IndexEntry {
+	documentID, err := a.cacheManager.GenerateDocumentID(doc.FilePath)
+	if err != nil {
+		return nil
+	}
+	index, err := a.tagManager.Index(documentID, tags.KindTopic)
+	if err != nil {
+		logger.Warnf("获取主题索引失败: %v", err)
+		return nil
+	}
+	return index
+}
+
+// formatPages 将页码列表格式化为 "1, 3-5, 8"
+func formatPages(pages []int) string {
+	var parts []string
+	for i := 0; i < len(pages); {
+		j := i
+		for j+1 < len(pages) && pages[j+1] == pages[j]+1 {
+			j++
+		}
+		if j > i {
+			parts = append(parts, fmt.Sprintf("%d-%d", pages[i], pages[j]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d", pages[i]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ", ")
+}
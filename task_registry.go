@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"pdf-ocr-ai/pkg/logger"
+)
+
+// 任务类型
+const (
+	TaskKindSinglePageOCR = "single_page_ocr"
+	TaskKindAI            = "ai"
+	TaskKindBatchOCR      = "batch_ocr"
+	TaskKindBatchAI       = "batch_ai"
+)
+
+// 任务状态
+const (
+	TaskStatusRunning   = "running"
+	TaskStatusCompleted = "completed"
+	TaskStatusFailed    = "failed" // 有错误事件（批量处理中有页面失败）
+	TaskStatusCancelled = "cancelled"
+)
+
+// maxFinishedTasks 保留的已结束任务数，供 GetTaskStatus 查询
+const maxFinishedTasks = 100
+
+// TaskInfo 任务的信息和状态
+type TaskInfo struct {
+	ID         string     `json:"id"`
+	Kind       string     `json:"kind"`
+	DocumentID string     `json:"document_id"`
+	Pages      []int      `json:"pages"`
+	Status     string     `json:"status"`
+	Errors     int        `json:"errors"`               // 错误事件数
+	LastError  string     `json:"last_error,omitempty"` // 最近一次错误
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// runningTask 运行中的任务及其上下文
+type runningTask struct {
+	info   TaskInfo
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// taskRegistry 为所有异步处理登记唯一ID，使事件可以区分来源、任务可以按ID取消
+type taskRegistry struct {
+	mu       sync.Mutex
+	nextID   uint64
+	tasks    map[string]*runningTask
+	finished []TaskInfo     // 最近结束的任务，按结束顺序
+	running  sync.WaitGroup // 运行中的任务，退出时等待
+	closed   bool           // 应用正在退出，之后开始的任务立即取消
+}
+
+func newTaskRegistry() *taskRegistry {
+	return &taskRegistry{tasks: make(map[string]*runningTask)}
+}
+
+// taskIDKey 上下文中保存任务ID的键
+type taskIDKey struct{}
+
+// taskIDFromContext 获取上下文所属的任务ID，不属于任务时返回空
+func taskIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(taskIDKey{}).(string)
+	return id
+}
+
+// start 登记新任务，返回任务ID和可取消的上下文（上下文中带有任务ID），任务结束后必须调用 finish
+func (r *taskRegistry) start(parent context.Context, kind, documentID string, pages []int) (string, context.Context) {
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	id := fmt.Sprintf("task-%d", r.nextID)
+	ctx, cancel := context.WithCancel(context.WithValue(parent, taskIDKey{}, id))
+	r.tasks[id] = &runningTask{
+		info: TaskInfo{
+			ID:         id,
+			Kind:       kind,
+			DocumentID: documentID,
+			Pages:      append([]int{}, pages...),
+			Status:     TaskStatusRunning,
+			StartedAt:  time.Now(),
+		},
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	r.running.Add(1)
+	if r.closed {
+		cancel()
+	}
+	return id, ctx
+}
+
+// finish 结束任务并释放其上下文，根据取消和错误情况确定最终状态
+func (r *taskRegistry) finish(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	task, ok := r.tasks[id]
+	if !ok {
+		return
+	}
+	delete(r.tasks, id)
+	defer r.running.Done()
+
+	now := time.Now()
+	info := task.info
+	info.FinishedAt = &now
+	switch {
+	case task.ctx.Err() != nil:
+		info.Status = TaskStatusCancelled
+	case info.Errors > 0:
+		info.Status = TaskStatusFailed
+	default:
+		info.Status = TaskStatusCompleted
+	}
+	task.cancel()
+
+	if len(r.finished) == maxFinishedTasks {
+		r.finished = r.finished[1:]
+	}
+	r.finished = append(r.finished, info)
+}
+
+// recordError 记录任务的错误事件
+func (r *taskRegistry) recordError(id, message string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if task, ok := r.tasks[id]; ok {
+		task.info.Errors++
+		task.info.LastError = message
+	}
+}
+
+// cancel 取消指定任务，任务不存在（已结束）时返回 false
+func (r *taskRegistry) cancel(id string) bool {
+	r.mu.Lock()
+	task, ok := r.tasks[id]
+	r.mu.Unlock()
+
+	if ok {
+		task.cancel()
+	}
+	return ok
+}
+
+// cancelAll 应用退出时取消所有运行中的任务，之后开始的任务也立即取消
+func (r *taskRegistry) cancelAll() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	for _, task := range r.tasks {
+		task.cancel()
+	}
+}
+
+// wait 等待所有任务结束，超时返回 false
+func (r *taskRegistry) wait(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		r.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// list 获取运行中的任务，按开始时间排序
+func (r *taskRegistry) list() []TaskInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tasks := make([]TaskInfo, 0, len(r.tasks))
+	for _, task := range r.tasks {
+		tasks = append(tasks, task.info)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].StartedAt.Before(tasks[j].StartedAt)
+	})
+	return tasks
+}
+
+// get 获取运行中或最近结束的任务
+func (r *taskRegistry) get(id string) (TaskInfo, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if task, ok := r.tasks[id]; ok {
+		return task.info, true
+	}
+	for i := len(r.finished) - 1; i >= 0; i-- {
+		if r.finished[i].ID == id {
+			return r.finished[i], true
+		}
+	}
+	return TaskInfo{}, false
+}
+
+// startTask 为文档会话登记任务，会话为空时文档ID为空
+func (a *App) startTask(kind string, session *DocumentSession, pages []int) (string, context.Context) {
+	documentID := ""
+	if session != nil {
+		documentID = session.ID
+	}
+	return a.tasks.start(a.ctx, kind, documentID, pages)
+}
+
+// runTask 登记任务并在后台执行，返回任务ID
+func (a *App) runTask(kind string, session *DocumentSession, pages []int, run func(ctx context.Context)) string {
+	taskID, ctx := a.startTask(kind, session, pages)
+	go func() {
+		defer a.tasks.finish(taskID)
+		run(ctx)
+	}()
+	return taskID
+}
+
+// taskCanceller 获取取消上下文所属任务的函数，批次取消时调用
+func (a *App) taskCanceller(ctx context.Context) context.CancelFunc {
+	taskID := taskIDFromContext(ctx)
+	return func() { a.tasks.cancel(taskID) }
+}
+
+// emitTask 发送属于任务的事件，事件数据中加入任务ID，错误事件同时计入任务状态
+func (a *App) emitTask(ctx context.Context, eventName string, data interface{}) {
+	taskID := taskIDFromContext(ctx)
+	if taskID != "" {
+		switch v := data.(type) {
+		case map[string]interface{}:
+			v["task_id"] = taskID
+		case ProgressUpdate:
+			v.TaskID = taskID
+			data = v
+		case EventError:
+			v.TaskID = taskID
+			data = v
+			a.tasks.recordError(taskID, v.Error)
+		}
+	}
+	a.emit(eventName, data)
+}
+
+// CancelTask 取消指定任务
+func (a *App) CancelTask(taskID string) error {
+	if !a.tasks.cancel(taskID) {
+		return fmt.Errorf("任务不存在或已结束: %s", taskID)
+	}
+	logger.Infof("已取消任务 %s", taskID)
+	return nil
+}
+
+// ListActiveTasks 获取运行中的任务
+func (a *App) ListActiveTasks() []TaskInfo {
+	return a.tasks.list()
+}
+
+// GetTaskStatus 获取运行中或最近结束的任务状态
+func (a *App) GetTaskStatus(taskID string) (*TaskInfo, error) {
+	info, ok := a.tasks.get(taskID)
+	if !ok {
+		return nil, fmt.Errorf("任务不存在: %s", taskID)
+	}
+	return &info, nil
+}
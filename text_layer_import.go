@@ -0,0 +1,85 @@
+package main
+
+import (
+	"strings"
+
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/jobs"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/revisions"
+)
+
+// TextLayerImport 文本层导入结果
+type TextLayerImport struct {
+	Imported []int  `json:"imported"`          // 已导入文本层作为OCR结果的页码
+	Poor     []int  `json:"poor"`              // 没有文本层或文本层质量差、需要OCR的页码
+	Skipped  []int  `json:"skipped"`           // 已有OCR结果而跳过的页码
+	TaskID   string `json:"task_id,omitempty"` // ocrPoor 为 true 时开始的OCR任务ID
+}
+
+// ImportTextLayer 将PDF已有的文本层（如 Acrobat 生成的）作为当前文档各页的OCR结果导入，排除的页面除外
+// 没有文本层、文字过少或乱码较多的页面不导入；overwrite 为 false 时跳过已有OCR结果的页面
+// ocrPoor 为 true 时对不导入的页面开始OCR识别
+func (a *App) ImportTextLayer(overwrite bool, ocrPoor bool) (*TextLayerImport, error) {
+	session := a.activeSession()
+	if session == nil {
+		return nil, i18n.Errorf("doc.not_loaded")
+	}
+	if session.busy() {
+		return nil, i18n.Errorf("doc.busy")
+	}
+	doc := session.Doc
+
+	result := &TextLayerImport{Imported: []int{}, Poor: []int{}, Skipped: []int{}}
+	pages := includedPages(doc, allPageNumbers(len(doc.Pages)))
+	for i, pageNum := range pages {
+		a.emit("text-layer-progress", map[string]interface{}{
+			"current": i + 1,
+			"total":   len(pages),
+		})
+
+		page := doc.Pages[pageNum-1]
+		if page.OCRText != "" && !overwrite {
+			result.Skipped = append(result.Skipped, pageNum)
+			continue
+		}
+
+		text := page.Text
+		if text == "" {
+			extracted, hasText, err := a.pdfProcessor.ExtractNativeText(doc.FilePath, pageNum)
+			if err != nil {
+				logger.Warnf("提取第%d页文本层失败: %v", pageNum, err)
+			}
+			a.mu.Lock()
+			page.Text, page.HasText = extracted, hasText
+			a.mu.Unlock()
+			text = extracted
+		}
+		text = strings.TrimSpace(text)
+		if text == "" || lowConfidenceText(text) || garbledRatio(text) > garbledTextRatio {
+			result.Poor = append(result.Poor, pageNum)
+			continue
+		}
+
+		a.pdfProcessor.UpdatePageOCR(doc, pageNum, text)
+		if err := a.savePageToCache(doc, pageNum, text, page.AIText, revisions.SourceTextLayer); err != nil {
+			logger.Errorf("更新缓存失败: %v", err)
+		}
+		result.Imported = append(result.Imported, pageNum)
+	}
+
+	logger.Infof("导入文本层: %d 页已导入, %d 页需要OCR, %d 页已有结果", len(result.Imported), len(result.Poor), len(result.Skipped))
+	a.emit("text-layer-imported", map[string]interface{}{
+		"document_id": session.ID,
+		"imported":    result.Imported,
+		"poor":        result.Poor,
+	})
+
+	if ocrPoor && len(result.Poor) > 0 {
+		if a.ocrClient == nil {
+			return result, i18n.Errorf("ai.not_configured")
+		}
+		result.TaskID = a.startOrQueueBatch(session, QueueRequest{Pages: result.Poor, TaskType: string(jobs.TaskOCR)})
+	}
+	return result, nil
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/textstats"
+)
+
+// GetDocumentTextStats 统计当前文档的字数、中日韩与拉丁文字比例、估计阅读时间和各页长度分布
+// 每页优先使用OCR文本，其次AI文本，最后原生文本；排除的页面不参与统计
+func (a *App) GetDocumentTextStats() (*textstats.Stats, error) {
+	doc := a.activeDocument()
+	if doc == nil {
+		return nil, i18n.Errorf("doc.not_loaded")
+	}
+
+	pages := includedPages(doc, allPageNumbers(len(doc.Pages)))
+	texts := make([]string, 0, len(pages))
+	for _, pageNum := range pages {
+		texts = append(texts, pageText(doc.Pages[pageNum-1], ""))
+	}
+
+	// Analyze 按顺序编号，换回文档中的页码
+	stats := textstats.Analyze(texts)
+	for i := range stats.Pages {
+		stats.Pages[i].Page = pages[i]
+	}
+	for i, index := range stats.EmptyPages {
+		stats.EmptyPages[i] = pages[index-1]
+	}
+	return &stats, nil
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"pdf-ocr-ai/pkg/config"
+	"pdf-ocr-ai/pkg/document"
+	"pdf-ocr-ai/pkg/jobs"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/watcher"
+)
+
+const (
+	watchProcessedDir = "processed" // 处理成功的源文件移入此子目录
+	watchFailedDir    = "failed"    // 处理失败的源文件移入此子目录
+)
+
+// applyWatchFolderConfig 根据配置启动或停止监视文件夹
+func (a *App) applyWatchFolderConfig(cfg config.WatchFolderConfig) error {
+	a.mu.Lock()
+	previous := a.folderWatcher
+	a.folderWatcher = nil
+	a.mu.Unlock()
+
+	if previous != nil {
+		previous.Stop()
+	}
+
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.InputDir == "" {
+		return fmt.Errorf("未设置监视目录")
+	}
+
+	// 只监视可以OCR的格式
+	var extensions []string
+	for ext, docType := range document.SupportedFormats {
+		if docType == document.TypePDF || docType == document.TypeImage || docType == document.TypeDjVu {
+			extensions = append(extensions, ext)
+		}
+	}
+
+	interval := time.Duration(cfg.PollInterval) * time.Second
+	w, err := watcher.NewFolderWatcher(cfg.InputDir, interval, extensions, a.onWatchedFile)
+	if err != nil {
+		return fmt.Errorf("启动监视文件夹失败: %w", err)
+	}
+
+	a.mu.Lock()
+	a.folderWatcher = w
+	a.mu.Unlock()
+
+	w.Start()
+	return nil
+}
+
+// onWatchedFile 监视目录中出现新文件时加入处理队列
+func (a *App) onWatchedFile(filePath string) {
+	cfg := a.configManager.GetConfig().WatchFolder
+	logger.Infof("监视文件夹发现新文件: %s", filePath)
+
+	a.emit("watch-folder-file", map[string]interface{}{
+		"file_path": filePath,
+		"status":    "queued",
+	})
+
+	// AI任务需要先完成OCR，再基于OCR结果进行AI处理
+	_, err := a.enqueue([]QueueRequest{{
+		FilePath: filePath,
+		TaskType: string(jobs.TaskOCR),
+	}}, func(item *QueueItem) {
+		// 未设置提示词时使用文件夹处理配置的提示词模板
+		prompt := cfg.Prompt
+		if prompt == "" {
+			prompt = profilePrompt(a.resolveProfile(filePath))
+		}
+		if item.Status == QueueStatusCompleted && cfg.TaskType == string(jobs.TaskAI) && prompt != "" {
+			if _, err := a.enqueue([]QueueRequest{{
+				FilePath: filePath,
+				TaskType: string(jobs.TaskAI),
+				Prompt:   prompt,
+			}}, func(aiItem *QueueItem) {
+				a.finishWatchedFile(cfg, aiItem, true)
+			}); err != nil {
+				logger.Errorf("监视文件夹AI任务入队失败: %v", err)
+				a.finishWatchedFile(cfg, item, false)
+			}
+			return
+		}
+		a.finishWatchedFile(cfg, item, false)
+	})
+	if err != nil {
+		logger.Errorf("监视文件夹文件入队失败: %v", err)
+	}
+}
+
+// finishWatchedFile 导出处理结果并将源文件移出监视目录
+func (a *App) finishWatchedFile(cfg config.WatchFolderConfig, item *QueueItem, preferAI bool) {
+	status := "completed"
+	var outputPath string
+	var finishErr error
+
+	if item.Status != QueueStatusCompleted {
+		status = string(item.Status)
+		finishErr = fmt.Errorf("处理未完成: %s %s", item.Status, item.Error)
+	} else {
+		outputPath, finishErr = a.exportWatchedResult(cfg, item, preferAI)
+		if finishErr != nil {
+			status = "failed"
+		}
+	}
+
+	targetDir := watchProcessedDir
+	if finishErr != nil {
+		targetDir = watchFailedDir
+		logger.Errorf("监视文件夹处理失败 %s: %v", item.FilePath, finishErr)
+	}
+
+	if err := moveIntoSubdir(item.FilePath, targetDir); err != nil {
+		logger.Errorf("移动源文件失败 %s: %v", item.FilePath, err)
+	}
+
+	// 关闭后台打开的文档，避免长时间运行后工作区堆积
+	if item.DocumentID != "" {
+		if active := a.activeSession(); active == nil || active.ID != item.DocumentID {
+			a.CloseDocument(item.DocumentID)
+		}
+	}
+
+	payload := map[string]interface{}{
+		"file_path":   item.FilePath,
+		"output_path": outputPath,
+		"status":      status,
+	}
+	if finishErr != nil {
+		payload["error"] = finishErr.Error()
+	}
+	a.emit("watch-folder-file", payload)
+}
+
+// exportWatchedResult 将文档结果按配置格式写入输出目录
+func (a *App) exportWatchedResult(cfg config.WatchFolderConfig, item *QueueItem, preferAI bool) (string, error) {
+	session, err := a.getSession(item.DocumentID)
+	if err != nil {
+		return "", err
+	}
+
+	outputDir := cfg.OutputDir
+	if outputDir == "" {
+		outputDir = filepath.Join(filepath.Dir(item.FilePath), "output")
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return "", fmt.Errorf("创建输出目录失败: %w", err)
+	}
+
+	format := cfg.ExportFormat
+	ext := ".txt"
+	switch format {
+	case "markdown":
+		ext = ".md"
+	case "html":
+		ext = ".html"
+	}
+
+	content := formatDocumentText(session.Doc, allPageNumbers(session.Doc.PageCount), format, preferAI, a.exportApprovedOnly())
+
+	baseName := strings.TrimSuffix(filepath.Base(item.FilePath), filepath.Ext(item.FilePath))
+	outputPath := filepath.Join(outputDir, baseName+ext)
+	if err := os.WriteFile(outputPath, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("写入导出文件失败: %w", err)
+	}
+
+	logger.Infof("监视文件夹导出完成: %s", outputPath)
+	return outputPath, nil
+}
+
+// moveIntoSubdir 将文件移动到其所在目录的子目录中，目标已存在时追加时间戳
+func moveIntoSubdir(filePath string, subdir string) error {
+	targetDir := filepath.Join(filepath.Dir(filePath), subdir)
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return err
+	}
+
+	target := filepath.Join(targetDir, filepath.Base(filePath))
+	if _, err := os.Stat(target); err == nil {
+		ext := filepath.Ext(filePath)
+		base := strings.TrimSuffix(filepath.Base(filePath), ext)
+		target = filepath.Join(targetDir, fmt.Sprintf("%s_%s%s", base, time.Now().Format("20060102150405"), ext))
+	}
+
+	return os.Rename(filePath, target)
+}
+
+// GetWatchFolderStatus 获取监视文件夹状态
+func (a *App) GetWatchFolderStatus() map[string]interface{} {
+	cfg := a.configManager.GetConfig().WatchFolder
+
+	a.mu.RLock()
+	running := a.folderWatcher != nil
+	a.mu.RUnlock()
+
+	return map[string]interface{}{
+		"enabled":    cfg.Enabled,
+		"running":    running,
+		"input_dir":  cfg.InputDir,
+		"output_dir": cfg.OutputDir,
+	}
+}
@@ -0,0 +1,505 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"pdf-ocr-ai/pkg/history"
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/pdf"
+)
+
+// DocumentSession 工作区中打开的文档及其独立的处理状态
+type DocumentSession struct {
+	ID       string
+	Doc      *pdf.PDFDocument
+	OpenedAt time.Time
+
+	// 批量处理控制（每个文档独立）
+	processingMu     sync.Mutex
+	processingCancel context.CancelFunc
+	processingState  ProcessingState
+	resumed          chan struct{} // 暂停期间不为空，继续、取消或批次结束时关闭以唤醒等待的工作协程
+	pageQueue        *pageQueue    // 当前批次尚未开始处理的页面
+	currentBatch     []int         // 当前批次的页面
+	processedInBatch int           // 当前批次已处理的页面数
+	historyID        int           // 当前批次的历史记录ID，暂停/继续时同步状态
+	taskID           string        // 当前批次的任务ID
+
+	// 内存保护
+	viewedPage   int  // 前端正在查看的页面
+	textReleased bool // 页面文本已因内存不足释放，使用前需要从缓存重新加载
+}
+
+// WorkspaceDocument 工作区文档摘要（用于前端标签页）
+type WorkspaceDocument struct {
+	DocumentID     string `json:"document_id"`
+	FilePath       string `json:"file_path"`
+	Title          string `json:"title"`
+	PageCount      int    `json:"page_count"`
+	ProcessedPages int    `json:"processed_pages"`
+	State          int    `json:"state"`
+	Active         bool   `json:"active"`
+	OpenedAt       string `json:"opened_at"`
+}
+
+// newDocumentSession 创建文档会话
+func newDocumentSession(id string, doc *pdf.PDFDocument) *DocumentSession {
+	return &DocumentSession{
+		ID:       id,
+		Doc:      doc,
+		OpenedAt: time.Now(),
+	}
+}
+
+// beginBatch 开始一个批次，文档已有批次在运行时返回false
+func (s *DocumentSession) beginBatch(taskID string, pageNumbers []int, cancel context.CancelFunc) bool {
+	s.processingMu.Lock()
+	defer s.processingMu.Unlock()
+
+	if s.processingState != ProcessingStateIdle {
+		return false
+	}
+	s.taskID = taskID
+	s.processingCancel = cancel
+	s.processingState = ProcessingStateRunning
+	s.currentBatch = pageNumbers
+	s.processedInBatch = 0
+	return true
+}
+
+// endBatch 结束批次并重置状态
+func (s *DocumentSession) endBatch() {
+	s.processingMu.Lock()
+	defer s.processingMu.Unlock()
+
+	s.processingCancel = nil
+	s.processingState = ProcessingStateIdle
+	s.wakePaused()
+	s.pageQueue = nil
+	s.currentBatch = nil
+	s.processedInBatch = 0
+	s.historyID = 0
+	s.taskID = ""
+}
+
+// currentTaskID 获取当前批次的任务ID，没有批次时返回空
+func (s *DocumentSession) currentTaskID() string {
+	s.processingMu.Lock()
+	defer s.processingMu.Unlock()
+	return s.taskID
+}
+
+// setHistoryRecord 关联当前批次的历史记录
+func (s *DocumentSession) setHistoryRecord(record *history.HistoryRecord) {
+	if record == nil {
+		return
+	}
+	s.processingMu.Lock()
+	s.historyID = record.ID
+	s.processingMu.Unlock()
+}
+
+// busy 是否有批次在运行（包括暂停和取消中）
+func (s *DocumentSession) busy() bool {
+	return s.getState() != ProcessingStateIdle
+}
+
+// getState 获取处理状态
+func (s *DocumentSession) getState() ProcessingState {
+	s.processingMu.Lock()
+	defer s.processingMu.Unlock()
+	return s.processingState
+}
+
+// pause 运行中的批次转为暂停，返回状态是否改变
+func (s *DocumentSession) pause() bool {
+	s.processingMu.Lock()
+	defer s.processingMu.Unlock()
+
+	if s.processingState != ProcessingStateRunning {
+		return false
+	}
+	s.processingState = ProcessingStatePaused
+	s.resumed = make(chan struct{})
+	return true
+}
+
+// resume 暂停的批次转为运行，返回状态是否改变
+func (s *DocumentSession) resume() bool {
+	s.processingMu.Lock()
+	defer s.processingMu.Unlock()
+
+	if s.processingState != ProcessingStatePaused {
+		return false
+	}
+	s.processingState = ProcessingStateRunning
+	s.wakePaused()
+	return true
+}
+
+// cancel 运行中或暂停的批次转为取消中并取消上下文，返回状态是否改变
+// 批次的清理由批量处理函数在结束时完成
+func (s *DocumentSession) cancel() bool {
+	s.processingMu.Lock()
+	defer s.processingMu.Unlock()
+
+	if s.processingState != ProcessingStateRunning && s.processingState != ProcessingStatePaused {
+		return false
+	}
+	s.processingState = ProcessingStateCancelling
+	s.wakePaused()
+	if s.processingCancel != nil {
+		s.processingCancel()
+	}
+	return true
+}
+
+// wakePaused 唤醒所有等待继续的工作协程（调用方持有 processingMu）
+func (s *DocumentSession) wakePaused() {
+	if s.resumed != nil {
+		close(s.resumed)
+		s.resumed = nil
+	}
+}
+
+// waitIfPaused 批次暂停时阻塞到继续，返回false表示批次已取消，工作协程应停止
+// OCR和AI批量处理的工作协程在开始每一页之前调用
+func (s *DocumentSession) waitIfPaused(ctx context.Context) bool {
+	for {
+		s.processingMu.Lock()
+		state, resumed := s.processingState, s.resumed
+		s.processingMu.Unlock()
+
+		switch {
+		case state == ProcessingStateCancelling || ctx.Err() != nil:
+			return false
+		case state != ProcessingStatePaused || resumed == nil:
+			return true
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-resumed:
+		}
+	}
+}
+
+// incrementProcessed 增加已处理计数
+func (s *DocumentSession) incrementProcessed() {
+	s.processingMu.Lock()
+	s.processedInBatch++
+	s.processingMu.Unlock()
+}
+
+// processingSnapshot 获取处理状态快照
+func (s *DocumentSession) processingSnapshot() map[string]interface{} {
+	s.processingMu.Lock()
+	defer s.processingMu.Unlock()
+
+	return map[string]interface{}{
+		"document_id":     s.ID,
+		"task_id":         s.taskID,
+		"state":           int(s.processingState),
+		"current_batch":   s.currentBatch,
+		"processed_count": s.processedInBatch,
+		"total_count":     len(s.currentBatch),
+	}
+}
+
+// summary 生成工作区文档摘要
+func (s *DocumentSession) summary(active bool) WorkspaceDocument {
+	processed := 0
+	for _, page := range s.Doc.Pages {
+		if page.Processed {
+			processed++
+		}
+	}
+
+	return WorkspaceDocument{
+		DocumentID:     s.ID,
+		FilePath:       s.Doc.FilePath,
+		Title:          s.Doc.Title,
+		PageCount:      s.Doc.PageCount,
+		ProcessedPages: processed,
+		State:          int(s.getState()),
+		Active:         active,
+		OpenedAt:       s.OpenedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// activeSession 获取当前活动文档会话
+func (a *App) activeSession() *DocumentSession {
+	a.mu.RLock()
+	session := a.sessions[a.activeSessionID]
+	a.mu.RUnlock()
+	a.restorePageText(session)
+	return session
+}
+
+// activeDocument 获取当前活动文档
+func (a *App) activeDocument() *pdf.PDFDocument {
+	if session := a.activeSession(); session != nil {
+		return session.Doc
+	}
+	return nil
+}
+
+// getSession 按文档ID获取会话
+func (a *App) getSession(documentID string) (*DocumentSession, error) {
+	a.mu.RLock()
+	session, exists := a.sessions[documentID]
+	a.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("文档未打开: %s", documentID)
+	}
+	a.restorePageText(session)
+	return session, nil
+}
+
+// OpenDocument 打开文档并加入工作区，设为当前活动文档，返回文档ID
+func (a *App) OpenDocument(filePath string) (string, error) {
+	session, err := a.loadDocumentSession(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	session = a.registerSession(session, true)
+
+	logger.Infof("文档已打开: %s (ID=%s)", filePath, session.ID)
+
+	// 通知前端文档已加载
+	a.emit("document-loaded", map[string]interface{}{
+		"document":    session.Doc,
+		"document_id": session.ID,
+	})
+	a.emitWorkspaceChanged()
+	a.checkDocumentChanged(session)
+	a.checkIncompleteRecords(session)
+	a.checkEmailAttachments(session)
+
+	return session.ID, nil
+}
+
+// registerSession 将会话加入工作区，同一文档已打开时返回已有会话（保留其处理状态）
+func (a *App) registerSession(session *DocumentSession, activate bool) *DocumentSession {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if existing, exists := a.sessions[session.ID]; exists {
+		session = existing
+	} else {
+		a.sessions[session.ID] = session
+		a.sessionOrder = append(a.sessionOrder, session.ID)
+	}
+	if activate {
+		a.activeSessionID = session.ID
+	}
+	return session
+}
+
+// CloseDocument 关闭工作区中的文档（会取消该文档正在进行的处理）
+func (a *App) CloseDocument(documentID string) error {
+	session, err := a.getSession(documentID)
+	if err != nil {
+		return err
+	}
+
+	a.cancelSession(session)
+
+	a.mu.Lock()
+	delete(a.sessions, documentID)
+	for i, id := range a.sessionOrder {
+		if id == documentID {
+			a.sessionOrder = append(a.sessionOrder[:i], a.sessionOrder[i+1:]...)
+			break
+		}
+	}
+
+	// 关闭的是活动文档时，切换到最近打开的文档
+	activeChanged := false
+	if a.activeSessionID == documentID {
+		a.activeSessionID = ""
+		if len(a.sessionOrder) > 0 {
+			a.activeSessionID = a.sessionOrder[len(a.sessionOrder)-1]
+		}
+		activeChanged = true
+	}
+	newActive := a.sessions[a.activeSessionID]
+	a.mu.Unlock()
+
+	logger.Infof("文档已关闭: %s", documentID)
+	a.removeImportDirs(false)
+
+	if activeChanged && newActive != nil {
+		a.emit("document-loaded", map[string]interface{}{
+			"document":    newActive.Doc,
+			"document_id": newActive.ID,
+		})
+	}
+	a.emitWorkspaceChanged()
+
+	return nil
+}
+
+// SwitchDocument 切换当前活动文档
+func (a *App) SwitchDocument(documentID string) error {
+	session, err := a.getSession(documentID)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.activeSessionID = documentID
+	a.mu.Unlock()
+
+	a.emit("document-loaded", map[string]interface{}{
+		"document":    session.Doc,
+		"document_id": session.ID,
+	})
+	a.emitWorkspaceChanged()
+
+	return nil
+}
+
+// GetOpenDocuments 获取工作区中打开的文档列表（按打开顺序）
+func (a *App) GetOpenDocuments() []WorkspaceDocument {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	documents := make([]WorkspaceDocument, 0, len(a.sessionOrder))
+	for _, id := range a.sessionOrder {
+		if session, exists := a.sessions[id]; exists {
+			documents = append(documents, session.summary(id == a.activeSessionID))
+		}
+	}
+	return documents
+}
+
+// GetDocumentProcessingState 获取指定文档的处理状态
+func (a *App) GetDocumentProcessingState(documentID string) (map[string]interface{}, error) {
+	session, err := a.getSession(documentID)
+	if err != nil {
+		return nil, err
+	}
+	return session.processingSnapshot(), nil
+}
+
+// PauseDocumentProcessing 暂停指定文档的批量处理
+func (a *App) PauseDocumentProcessing(documentID string) error {
+	session, err := a.getSession(documentID)
+	if err != nil {
+		return err
+	}
+	a.pauseSession(session)
+	return nil
+}
+
+// ResumeDocumentProcessing 继续指定文档的批量处理
+func (a *App) ResumeDocumentProcessing(documentID string) error {
+	session, err := a.getSession(documentID)
+	if err != nil {
+		return err
+	}
+	a.resumeSession(session)
+	return nil
+}
+
+// CancelDocumentProcessing 取消指定文档的批量处理
+func (a *App) CancelDocumentProcessing(documentID string) error {
+	session, err := a.getSession(documentID)
+	if err != nil {
+		return err
+	}
+	a.cancelSession(session)
+	return nil
+}
+
+// pauseSession 暂停会话的批量处理（OCR和AI批次相同），正在处理的页面完成后不再开始新页面
+func (a *App) pauseSession(session *DocumentSession) {
+	if !session.pause() {
+		return
+	}
+	logger.Infof("用户请求暂停批量处理: %s", session.ID)
+	a.syncSessionHistoryStatus(session, history.StatusPaused)
+
+	a.emit("processing-paused", map[string]interface{}{
+		"document_id": session.ID,
+		"task_id":     session.currentTaskID(),
+		"message":     i18n.T("batch.paused"),
+	})
+}
+
+// resumeSession 继续会话的批量处理
+func (a *App) resumeSession(session *DocumentSession) {
+	if !session.resume() {
+		return
+	}
+	logger.Infof("用户请求继续批量处理: %s", session.ID)
+	a.syncSessionHistoryStatus(session, history.StatusProcessing)
+
+	a.emit("processing-resumed", map[string]interface{}{
+		"document_id": session.ID,
+		"task_id":     session.currentTaskID(),
+		"message":     i18n.T("batch.resumed"),
+	})
+}
+
+// cancelSession 取消会话的批量处理
+func (a *App) cancelSession(session *DocumentSession) {
+	if !session.cancel() {
+		return
+	}
+	logger.Infof("用户请求取消批量处理: %s", session.ID)
+
+	// 发送取消通知，但不立即清理状态，让批量处理函数自己清理
+	a.emit("processing-cancelled", map[string]interface{}{
+		"document_id": session.ID,
+		"task_id":     session.currentTaskID(),
+		"message":     i18n.T("batch.cancelled"),
+	})
+}
+
+// endSessionBatch 结束会话的批次，并启动等待该文档空闲的队列任务
+func (a *App) endSessionBatch(session *DocumentSession) {
+	session.endBatch()
+	a.dispatchQueue()
+}
+
+// busyDocumentPaths 获取有批次在运行的文档路径
+func (a *App) busyDocumentPaths() map[string]bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	paths := make(map[string]bool)
+	for _, session := range a.sessions {
+		if session.busy() {
+			paths[session.Doc.FilePath] = true
+		}
+	}
+	return paths
+}
+
+// syncSessionHistoryStatus 同步当前批次历史记录的状态
+func (a *App) syncSessionHistoryStatus(session *DocumentSession, status history.ProcessingStatus) {
+	session.processingMu.Lock()
+	historyID := session.historyID
+	session.processingMu.Unlock()
+
+	if historyID == 0 {
+		return
+	}
+	if err := a.historyManager.UpdateRecordStatus(historyID, status, ""); err != nil {
+		logger.Errorf("更新历史记录状态失败: %v", err)
+	}
+}
+
+// emitWorkspaceChanged 通知前端工作区文档列表变化
+func (a *App) emitWorkspaceChanged() {
+	a.emit("workspace-changed", a.GetOpenDocuments())
+}
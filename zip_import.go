@@ -0,0 +1,290 @@
+package main
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+
+	"pdf-ocr-ai/pkg/config"
+	"pdf-ocr-ai/pkg/i18n"
+	"pdf-ocr-ai/pkg/logger"
+	"pdf-ocr-ai/pkg/pdf"
+)
+
+// ZIP压缩包的导入方式
+const (
+	ZipImportBatch = "batch" // 压缩包中的文件作为多个文档
+	ZipImportMerge = "merge" // 压缩包中的PDF和图片合并为一个文档
+)
+
+// ZIP中文件的顺序
+const (
+	ZipOrderName    = "name"    // 按文件路径排序
+	ZipOrderArchive = "archive" // 按压缩包中的顺序
+)
+
+// 压缩包解压后的大小上限：单个文件和所有文件合计
+const (
+	maxZipEntrySize = 2 << 30
+	maxZipTotalSize = 8 << 30
+)
+
+// importDirPrefix 解压目录名的前缀
+const importDirPrefix = "pdfseer-import-"
+
+// ImportZip 解压ZIP压缩包，按配置的导入方式将其中的文档打开或加入队列（action 与 IntakeFiles 相同）
+// 解压的文件保存在临时目录中，其中的文档都关闭后删除，之后不能再恢复或重试其中文档的任务
+func (a *App) ImportZip(zipPath string, action string) (*IntakeSummary, error) {
+	return a.IntakeFiles([]string{zipPath}, action)
+}
+
+// expandZipPaths 将路径中的ZIP压缩包替换为解压后的文件（合并导入时为合并后的PDF）
+func (a *App) expandZipPaths(paths []string) []string {
+	var result []string
+	for _, p := range paths {
+		if !strings.EqualFold(filepath.Ext(p), ".zip") {
+			result = append(result, p)
+			continue
+		}
+
+		files, err := a.extractZipImport(p)
+		if err != nil {
+			logger.Errorf("解压 %s 失败: %v", p, err)
+			result = append(result, p) // 保留原路径，由校验给出拒绝原因
+			continue
+		}
+		if a.configManager.GetConfig().UI.ZipImport != ZipImportMerge {
+			result = append(result, files...)
+			continue
+		}
+
+		merged, err := mergeZipFiles(p, files)
+		if err != nil {
+			logger.Errorf("合并 %s 中的文件失败: %v", p, err)
+			result = append(result, files...)
+			continue
+		}
+		result = append(result, merged)
+	}
+	return result
+}
+
+// extractZipImport 将压缩包中的文件解压到新的临时目录，按配置的顺序返回文件路径
+// 目录、隐藏文件和 __MACOSX 中的文件会被跳过，文件名加序号前缀避免不同目录中的同名文件冲突
+func (a *App) extractZipImport(zipPath string) ([]string, error) {
+	reader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, fmt.Errorf("打开压缩包失败: %w", err)
+	}
+	defer reader.Close()
+
+	var entries []*zip.File
+	for _, file := range reader.File {
+		name := path.Clean(strings.ReplaceAll(file.Name, "\\", "/"))
+		base := path.Base(name)
+		if file.FileInfo().IsDir() || strings.HasPrefix(base, ".") || strings.HasPrefix(name, "__MACOSX/") {
+			continue
+		}
+		entries = append(entries, file)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("压缩包中没有文件")
+	}
+	if a.configManager.GetConfig().UI.ZipOrder != ZipOrderArchive {
+		sort.SliceStable(entries, func(i, j int) bool {
+			return strings.ToLower(entries[i].Name) < strings.ToLower(entries[j].Name)
+		})
+	}
+
+	dir, err := a.newImportDir("")
+	if err != nil {
+		return nil, err
+	}
+
+	digits := len(fmt.Sprint(len(entries)))
+	files := make([]string, 0, len(entries))
+	remaining := int64(maxZipTotalSize)
+	for i, entry := range entries {
+		// 只使用文件名，避免压缩包中的路径指向解压目录之外
+		name := fmt.Sprintf("%0*d_%s", digits, i+1, path.Base(strings.ReplaceAll(entry.Name, "\\", "/")))
+		target := filepath.Join(dir, name)
+		written, err := extractZipEntry(entry, target, remaining)
+		if errors.Is(err, errZipTooLarge) {
+			logger.Warnf("压缩包 %s 解压后超过 %d GB，其余文件已跳过", zipPath, maxZipTotalSize>>30)
+			break
+		}
+		if err != nil {
+			logger.Warnf("解压 %s 失败: %v", entry.Name, err)
+			continue
+		}
+		remaining -= written
+		files = append(files, target)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("压缩包中的文件都无法解压")
+	}
+
+	logger.Infof("已解压 %s 中的 %d 个文件到 %s", zipPath, len(files), dir)
+	return files, nil
+}
+
+// errZipTooLarge 解压的文件合计超过大小上限
+var errZipTooLarge = errors.New("压缩包解压后过大")
+
+// extractZipEntry 解压压缩包中的单个文件，最多写入 remaining 字节（不信任压缩包中记录的大小），返回写入的字节数
+func extractZipEntry(entry *zip.File, target string, remaining int64) (int64, error) {
+	if entry.UncompressedSize64 > maxZipEntrySize {
+		return 0, fmt.Errorf("文件过大")
+	}
+	if entry.UncompressedSize64 > uint64(remaining) {
+		return 0, errZipTooLarge
+	}
+	src, err := entry.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(target)
+	if err != nil {
+		return 0, err
+	}
+	limit := min(remaining, maxZipEntrySize)
+	written, err := io.Copy(dst, io.LimitReader(src, limit+1))
+	if err == nil && written > limit {
+		err = errZipTooLarge
+		if limit == maxZipEntrySize {
+			err = fmt.Errorf("文件过大")
+		}
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(target)
+	}
+	return written, err
+}
+
+// mergeZipFiles 将解压的PDF和图片按顺序合并为一个PDF，保存在解压目录中，不能合并的文件会被跳过
+func mergeZipFiles(zipPath string, files []string) (string, error) {
+	if len(files) == 0 {
+		return "", fmt.Errorf("压缩包中没有文件")
+	}
+	dir := filepath.Dir(files[0])
+
+	var parts []string
+	for i, file := range files {
+		switch {
+		case strings.EqualFold(filepath.Ext(file), ".pdf"):
+			parts = append(parts, file)
+		case pdf.IsImageFile(file):
+			// 图片先转换为单页PDF
+			part := filepath.Join(dir, fmt.Sprintf(".image-%d.pdf", i))
+			if err := api.ImportImagesFile([]string{file}, part, nil, nil); err != nil {
+				logger.Warnf("图片 %s 无法转换为PDF，已跳过: %v", filepath.Base(file), err)
+				continue
+			}
+			parts = append(parts, part)
+		default:
+			logger.Warnf("%s 不能合并到PDF，已跳过", filepath.Base(file))
+		}
+	}
+	if len(parts) == 0 {
+		return "", fmt.Errorf("压缩包中没有可合并的PDF或图片")
+	}
+
+	merged := filepath.Join(dir, strings.TrimSuffix(filepath.Base(zipPath), filepath.Ext(zipPath))+".pdf")
+	if err := api.MergeCreateFile(parts, merged, false, nil); err != nil {
+		return "", fmt.Errorf("合并PDF失败: %w", err)
+	}
+	logger.Infof("已将 %s 中的 %d 个文件合并为 %s", filepath.Base(zipPath), len(parts), merged)
+	return merged, nil
+}
+
+// newImportDir 创建解压目录并登记，source 为来源邮件的路径（ZIP为空）
+func (a *App) newImportDir(source string) (string, error) {
+	dir, err := os.MkdirTemp("", importDirPrefix+"*")
+	if err != nil {
+		return "", fmt.Errorf("创建解压目录失败: %w", err)
+	}
+	a.mu.Lock()
+	a.importDirs[dir] = source
+	a.mu.Unlock()
+	return dir, nil
+}
+
+// removeImportDirs 删除来源邮件和其中的文档都已关闭（且不在处理队列中）的解压目录，all 为 true 时全部删除
+func (a *App) removeImportDirs(all bool) {
+	a.mu.RLock()
+	var inUse []string
+	if !all {
+		for _, session := range a.sessions {
+			inUse = append(inUse, session.Doc.FilePath)
+		}
+	}
+	a.mu.RUnlock()
+	if !all {
+		for _, item := range a.GetQueue() {
+			if item.Status == QueueStatusQueued || item.Status == QueueStatusRunning || item.Status == QueueStatusPaused {
+				inUse = append(inUse, item.FilePath)
+			}
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for dir, source := range a.importDirs {
+		used := false
+		for _, file := range inUse {
+			if file == source || strings.HasPrefix(file, dir+string(filepath.Separator)) {
+				used = true
+				break
+			}
+		}
+		if used {
+			continue
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			logger.Warnf("删除解压目录 %s 失败: %v", dir, err)
+			continue
+		}
+		logger.Debugf("已删除解压目录: %s", dir)
+		delete(a.importDirs, dir)
+	}
+}
+
+// importedFileRemoved 文件位于已删除的解压目录中时返回说明原因的错误
+func importedFileRemoved(filePath string) error {
+	rel, err := filepath.Rel(os.TempDir(), filePath)
+	if err != nil || !strings.HasPrefix(rel, importDirPrefix) {
+		return nil
+	}
+	if _, err := os.Stat(filePath); !os.IsNotExist(err) {
+		return nil
+	}
+	return i18n.Errorf("doc.import_removed", filepath.Base(filePath))
+}
+
+// validateZipImport 检查ZIP导入配置
+func validateZipImport(ui config.UIConfig) error {
+	switch ui.ZipImport {
+	case "", ZipImportBatch, ZipImportMerge:
+	default:
+		return fmt.Errorf("不支持的ZIP导入方式: %s", ui.ZipImport)
+	}
+	switch ui.ZipOrder {
+	case "", ZipOrderName, ZipOrderArchive:
+	default:
+		return fmt.Errorf("不支持的ZIP文件顺序: %s", ui.ZipOrder)
+	}
+	return nil
+}